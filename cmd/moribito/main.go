@@ -4,10 +4,15 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/charmbracelet/bubbletea"
 
 	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/export"
 	"github.com/ericschmar/moribito/internal/ldap"
 	"github.com/ericschmar/moribito/internal/tui"
 	"github.com/ericschmar/moribito/internal/version"
@@ -28,6 +33,26 @@ func main() {
 		showVersion  = flag.Bool("version", false, "Show version information")
 		checkUpdates = flag.Bool("check-updates", false, "Enable automatic update checking")
 		createConfig = flag.Bool("create-config", false, "Create default configuration file in OS-appropriate location")
+		noEmoji      = flag.Bool("no-emoji", false, "Use ASCII instead of emoji in the UI")
+		autoConnect  = flag.Bool("auto-connect", false, "Connect on startup when the active connection is complete")
+		readOnly     = flag.Bool("read-only", false, "Turn off edits, deletes, new entries and bulk modifications")
+
+		// Moving the whole configuration between machines
+		exportConfig    = flag.String("export-config", "", "Write the configuration and snapshots to this bundle file and exit")
+		exportPasswords = flag.Bool("export-passwords", false, "Include bind passwords in the -export-config bundle")
+		importConfig    = flag.String("import-config", "", "Restore the configuration and snapshots from this bundle file and exit")
+
+		// Query mode: run one search and export the results instead of the TUI
+		query      = flag.String("query", "", "Run this LDAP filter non-interactively and export the results")
+		queryBase  = flag.String("base", "", "Base DN to search from in query mode (default: the connection's base DN)")
+		queryScope = flag.String("scope", "sub", "Search scope in query mode: base, one or sub")
+		queryAttrs = flag.String("attrs", "", "Comma separated attributes to return in query mode (default: all)")
+		output     = flag.String("output", export.FormatLDIF, "Output format in query mode: ldif, csv or json")
+		encoding   = flag.String("encoding", export.EncodingUTF8, "Text encoding of query mode output: utf-8, utf-8-bom or latin-1")
+		outputFile = flag.String("file", "", "File to write query results to (default: stdout)")
+		allPages   = flag.Bool("all-pages", false, "Read every page of query results, not just the first")
+		annotate   = flag.Bool("annotate", false, "Record the search in CSV and JSON query output too, as LDIF always does")
+		deref      = flag.String("deref", "", "How query mode follows aliases: never, search, find or always (default: ldap.deref_aliases)")
 	)
 
 	flag.Parse()
@@ -51,12 +76,48 @@ func main() {
 		return
 	}
 
+	if *importConfig != "" {
+		bundle, err := config.ReadBundle(*importConfig)
+		if err != nil {
+			log.Fatalf("Failed to import config: %v", err)
+		}
+		path, err := bundle.Restore(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to import config: %v", err)
+		}
+		fmt.Printf("Restored the configuration to %s", path)
+		var extras []string
+		if len(bundle.Connections) > 0 {
+			extras = append(extras, fmt.Sprintf("%d connections files", len(bundle.Connections)))
+		}
+		if len(bundle.Snapshots) > 0 {
+			extras = append(extras, fmt.Sprintf("%d snapshots", len(bundle.Snapshots)))
+		}
+		if len(extras) > 0 {
+			fmt.Printf(" with %s", strings.Join(extras, " and "))
+		}
+		fmt.Println()
+		return
+	}
+
 	// Load configuration
 	var cfg *config.Config
 	var err error
 	var actualConfigPath string
+	var firstRun bool // No config file exists yet, so the setup wizard is shown
 
 	if *configPath != "" || (*host == "" && *baseDN == "") {
+		// Carry over an ldap-cli config on first run so the user's settings aren't lost
+		if *configPath == "" {
+			from, to, err := config.MigrateLegacyConfig()
+			// Written to stderr so query mode output on stdout stays clean
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not migrate legacy configuration: %v\n", err)
+			} else if from != "" {
+				fmt.Fprintf(os.Stderr, "Migrated legacy configuration from %s to %s\n", from, to)
+			}
+		}
+
 		// Try to load from config file
 		cfg, actualConfigPath, err = config.Load(*configPath)
 		if err != nil {
@@ -66,6 +127,7 @@ func main() {
 			// No config file specified and none found, use defaults
 			cfg = config.Default()
 			actualConfigPath = config.GetDefaultConfigPath()
+			firstRun = true
 		}
 	} else {
 		// Use command line arguments
@@ -73,6 +135,25 @@ func main() {
 		actualConfigPath = config.GetDefaultConfigPath()
 	}
 
+	// Export the config as loaded, before the command line overrides it
+	if *exportConfig != "" {
+		if firstRun {
+			log.Fatalf("Failed to export config: no configuration file found")
+		}
+		bundle, err := config.NewBundle(cfg, actualConfigPath, *exportPasswords)
+		if err != nil {
+			log.Fatalf("Failed to export config: %v", err)
+		}
+		if err := config.WriteBundle(*exportConfig, bundle); err != nil {
+			log.Fatalf("Failed to export config: %v", err)
+		}
+		fmt.Printf("Exported %s to %s\n", actualConfigPath, *exportConfig)
+		if !*exportPasswords {
+			fmt.Println("Bind passwords were left out; add -export-passwords to include them.")
+		}
+		return
+	}
+
 	// Override config with command line arguments if provided
 	if *host != "" {
 		cfg.LDAP.Host = *host
@@ -98,6 +179,32 @@ func main() {
 	if *pageSize != 0 {
 		cfg.Pagination.PageSize = uint32(*pageSize)
 	}
+	if *noEmoji {
+		cfg.Theme.NoEmoji = true
+	}
+	if *autoConnect {
+		cfg.LDAP.AutoConnect = true
+	}
+	if *readOnly {
+		cfg.ReadOnly = true
+	}
+
+	if *query != "" {
+		os.Exit(runQuery(cfg, queryOptions{
+			filter:     *query,
+			baseDN:     *queryBase,
+			scope:      *queryScope,
+			attributes: parseAttributes(*queryAttrs),
+			output:     *output,
+			encoding:   *encoding,
+			file:       *outputFile,
+			allPages:   *allPages,
+			annotate:   *annotate,
+			deref:      *deref,
+			confirm:    terminalInput(),
+			progress:   isTerminal(os.Stderr),
+		}, os.Stdout, os.Stderr))
+	}
 
 	// Get the active connection for validation display
 	activeConn := cfg.GetActiveConnection()
@@ -110,15 +217,36 @@ func main() {
 		fmt.Println("You can configure these in the start page.")
 	}
 
-	// Skip immediate LDAP connection - user will connect from start view
+	// Skip immediate LDAP connection - user will connect from start view,
+	// unless auto-connect starts the connect flow as the TUI opens
 	var client *ldap.Client = nil
-	fmt.Println("Starting in configuration mode - use the start screen to connect to LDAP...")
+	if cfg.LDAP.AutoConnect && activeConn.Complete() && !firstRun {
+		fmt.Println("Connecting to the active connection...")
+	} else {
+		fmt.Println("Starting in configuration mode - use the start screen to connect to LDAP...")
+	}
 
 	// Create and run the TUI
 	model := tui.NewModelWithUpdateCheckAndConfigPath(client, cfg, *checkUpdates, actualConfigPath)
-	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	if firstRun {
+		model.ShowSetupWizard()
+	}
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithoutSignalHandler())
 
-	if _, err := program.Run(); err != nil {
+	// Quit on SIGINT and SIGTERM like the quit key, so the connections are
+	// still closed below instead of left open on the server
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		if _, ok := <-signals; ok {
+			program.Quit()
+		}
+	}()
+
+	_, err = program.Run()
+	model.Close()
+	if err != nil {
 		log.Fatalf("Error running program: %v", err)
 	}
 }
@@ -140,10 +268,31 @@ func printHelp() {
 	fmt.Println("  -password string   Bind password (will prompt if user provided but password not)")
 	fmt.Println("  -page-size int     Number of entries per page for paginated queries (default: 50)")
 	fmt.Println("  -check-updates     Enable automatic update checking")
+	fmt.Println("  -no-emoji          Use ASCII instead of emoji in the UI (theme.no_emoji)")
+	fmt.Println("  -auto-connect      Connect on startup when the active connection is complete (ldap.auto_connect)")
+	fmt.Println("  -read-only         Turn off edits, deletes, new entries and bulk modifications (read_only)")
 	fmt.Println("  -create-config     Create default configuration file in OS-appropriate location")
+	fmt.Println("  -export-config string  Write the configuration and snapshots to a bundle file")
+	fmt.Println("  -export-passwords  Include bind passwords in the exported bundle")
+	fmt.Println("  -import-config string  Restore the configuration and snapshots from a bundle file")
 	fmt.Println("  -version           Show version information")
 	fmt.Println("  -help              Show this help message")
 	fmt.Println()
+	fmt.Println("Query mode (runs one search and exits instead of starting the TUI):")
+	fmt.Println("  -query string      LDAP filter to search for")
+	fmt.Println("  -base string       Base DN to search from (default: the connection's base DN)")
+	fmt.Println("  -scope string      base, one or sub (default: sub)")
+	fmt.Println("  -attrs string      Comma separated attributes to return (default: all)")
+	fmt.Println("  -output string     ldif, csv or json (default: ldif; csv needs -attrs)")
+	fmt.Println("  -encoding string   utf-8, utf-8-bom (for Excel) or latin-1 (default: utf-8)")
+	fmt.Println("  -file string       File to write the results to (default: stdout)")
+	fmt.Println("  -all-pages         Read every page of results, not just the first")
+	fmt.Println("  -annotate          Record the search in CSV (a leading # line) and JSON (a search field)")
+	fmt.Println("  -deref string      Follow aliases: never, search, find or always (default: ldap.deref_aliases)")
+	fmt.Println()
+	fmt.Println("  A summary is printed to stderr. Exit codes: 0 success, 1 bad options or")
+	fmt.Println("  output error, 2 connection failed, 3 bind failed, 4 search failed.")
+	fmt.Println()
 	fmt.Println("Configuration file example:")
 	fmt.Println("  ldap:")
 	fmt.Println("    host: ldap.example.com")
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/export"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/tui"
+)
+
+// Exit codes of query mode, so scripts can tell failures apart
+const (
+	exitOK         = 0
+	exitError      = 1 // Bad options, or the output couldn't be written
+	exitConnection = 2 // The server couldn't be reached
+	exitAuth       = 3 // The bind was refused
+	exitSearch     = 4 // The search failed
+)
+
+// queryOptions are the command line options of query mode
+type queryOptions struct {
+	filter     string
+	baseDN     string // Empty searches from the connection's base DN
+	scope      string
+	attributes []string // Empty returns all user attributes
+	output     string
+	encoding   string // Text encoding of the output, see export.Encoding*; empty is UTF-8
+	file       string // Empty writes to stdout
+	allPages   bool
+	annotate   bool      // Record the search in CSV and JSON output too, as LDIF always does
+	deref      string    // Alias dereferencing mode; empty uses the configured one
+	confirm    io.Reader // Answers whether to export past the confirmation threshold; nil never asks
+	progress   bool      // Report on stderr how many entries were exported while every page is read
+}
+
+// exportFile is the file -file is written through. It is written under a
+// temporary name next to the destination and renamed to it once the export
+// is complete, so a failed export neither leaves a partial file nor clobbers
+// an earlier one.
+type exportFile struct {
+	*os.File
+	path      string
+	committed bool
+}
+
+// createExportFile creates the temporary file for an export to path
+func createExportFile(path string) (*exportFile, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	// CreateTemp makes the file private; exports are as readable as os.Create makes them
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	return &exportFile{File: file, path: path}, nil
+}
+
+// commit closes the file and moves it to its destination. It does nothing
+// for a nil file, when the export goes to stdout.
+func (f *exportFile) commit() error {
+	if f == nil {
+		return nil
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		return err
+	}
+	f.committed = true
+	return nil
+}
+
+// discard removes the file unless it was committed
+func (f *exportFile) discard() {
+	if f.committed {
+		return
+	}
+	f.Close()
+	os.Remove(f.Name())
+}
+
+// errExportDeclined stops an export the user chose not to continue
+var errExportDeclined = errors.New("export stopped at the confirmation threshold")
+
+// terminalInput returns stdin when it is a terminal someone can answer
+// prompts on, and nil when it is piped or redirected as in scripts
+func terminalInput() io.Reader {
+	if isTerminal(os.Stdin) {
+		return os.Stdin
+	}
+	return nil
+}
+
+// isTerminal reports whether file is a terminal rather than a pipe or a file
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// exportProgressInterval is how often exportProgress rewrites its line
+const exportProgressInterval = time.Second
+
+// exportProgress keeps one line on stderr up to date with how many entries
+// a long export has written and how fast
+type exportProgress struct {
+	out      io.Writer
+	interval time.Duration
+	started  time.Time
+	last     time.Time
+	shown    bool
+}
+
+// newExportProgress starts reporting on out, or returns nil when disabled
+func newExportProgress(out io.Writer, enabled bool) *exportProgress {
+	if !enabled {
+		return nil
+	}
+	now := time.Now()
+	return &exportProgress{out: out, interval: exportProgressInterval, started: now, last: now}
+}
+
+// update shows written, at most once per interval
+func (p *exportProgress) update(written int) {
+	if p == nil {
+		return
+	}
+	now := time.Now()
+	if now.Sub(p.last) < p.interval {
+		return
+	}
+	p.last = now
+	rate := float64(written) / now.Sub(p.started).Seconds()
+	fmt.Fprintf(p.out, "\r\033[KExported %d entries (%.0f/s)...", written, rate)
+	p.shown = true
+}
+
+// clear removes the progress line so other output starts on a clean line
+func (p *exportProgress) clear() {
+	if p == nil || !p.shown {
+		return
+	}
+	fmt.Fprint(p.out, "\r\033[K")
+	p.shown = false
+}
+
+// confirmLargeExport asks on stderr whether to go on exporting once more than
+// written entries match, and reports whether the answer was yes
+func confirmLargeExport(in io.Reader, stderr io.Writer, written int) bool {
+	fmt.Fprintf(stderr, "More than %d entries match. Export them all? [y/N] ", written)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// parseScope converts a scope name to its LDAP value
+func parseScope(scope string) (int, error) {
+	switch strings.ToLower(scope) {
+	case "base":
+		return goldap.ScopeBaseObject, nil
+	case "one", "onelevel":
+		return goldap.ScopeSingleLevel, nil
+	case "sub", "subtree", "":
+		return goldap.ScopeWholeSubtree, nil
+	default:
+		return 0, fmt.Errorf("unknown scope %q (use base, one or sub)", scope)
+	}
+}
+
+// parseAttributes splits a comma separated list of attribute names
+func parseAttributes(value string) []string {
+	var attributes []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			attributes = append(attributes, name)
+		}
+	}
+	return attributes
+}
+
+// runQuery connects with the active connection, streams the search results
+// to the output and prints a summary to stderr. It returns the exit code.
+func runQuery(cfg *config.Config, opts queryOptions, stdout, stderr io.Writer) int {
+	fail := func(code int, err error) int {
+		fmt.Fprintf(stderr, "Error: %v\n", ldap.Explain(err))
+		return code
+	}
+
+	// Repairs such as capping an oversized page size are reported but don't stop the query
+	for _, warning := range cfg.ValidateAndRepair() {
+		fmt.Fprintf(stderr, "Warning: %s\n", warning)
+	}
+
+	scope, err := parseScope(opts.scope)
+	if err != nil {
+		return fail(exitError, err)
+	}
+	if err := export.CheckFormat(opts.output, opts.attributes); err != nil {
+		return fail(exitError, err)
+	}
+	if err := export.CheckEncoding(opts.output, opts.encoding); err != nil {
+		return fail(exitError, err)
+	}
+	deref := cfg.LDAP.DerefAliases
+	if opts.deref != "" {
+		deref = opts.deref
+	}
+	if _, err := ldap.ParseDerefAliases(deref); err != nil {
+		return fail(exitError, err)
+	}
+
+	activeConn := cfg.GetActiveConnection()
+	baseDN := opts.baseDN
+	if baseDN == "" {
+		baseDN = activeConn.BaseDN
+	}
+	if baseDN, err = ldap.NormalizeDN(baseDN); err != nil {
+		return fail(exitError, fmt.Errorf("invalid base DN: %w", err))
+	}
+
+	clientConfig := tui.ClientConfig(cfg, activeConn)
+	clientConfig.DerefAliases = deref
+	client, err := ldap.NewClient(clientConfig)
+	var bindErr *ldap.BindError
+	if errors.As(err, &bindErr) {
+		return fail(exitAuth, err)
+	}
+	if err != nil {
+		return fail(exitConnection, err)
+	}
+	defer client.Close()
+	progress := newExportProgress(stderr, opts.progress && opts.allPages)
+	client.SetRetryHandler(func(event ldap.RetryEvent) {
+		progress.clear()
+		fmt.Fprintln(stderr, event)
+	})
+
+	// The file is only created once connected, and only replaces -file once
+	// the export is complete, so failures leave nothing behind
+	out := stdout
+	destination := "stdout"
+	var file *exportFile
+	if opts.file != "" {
+		file, err = createExportFile(opts.file)
+		if err != nil {
+			return fail(exitError, err)
+		}
+		defer file.discard()
+		out = file
+		destination = opts.file
+	}
+	encoder, err := export.NewEncoder(out, opts.encoding)
+	if err != nil {
+		return fail(exitError, err)
+	}
+	search := ldap.SearchParams{BaseDN: baseDN, Scope: scope, Filter: opts.filter, Attributes: opts.attributes}
+	var writer export.Writer
+	if opts.annotate {
+		writer, err = export.NewAnnotatedWriter(opts.output, encoder, opts.attributes, search)
+	} else {
+		writer, err = export.NewWriter(opts.output, encoder, opts.attributes, search.String())
+	}
+	if err != nil {
+		return fail(exitError, err)
+	}
+	finish := func() error {
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	start := time.Now()
+	var writeErr error
+	written, asked := 0, false
+	count, err := client.SearchStream(baseDN, opts.filter, scope, opts.attributes, cfg.Pagination.PageSize, opts.allPages, func(entry *ldap.Entry) error {
+		if opts.confirm != nil && !asked && config.NeedsConfirmation(cfg.ConfirmThreshold, written+1) {
+			asked = true
+			progress.clear()
+			if !confirmLargeExport(opts.confirm, stderr, written) {
+				return errExportDeclined
+			}
+		}
+		if writeErr = writer.Write(entry); writeErr != nil {
+			writeErr = fmt.Errorf("%s: %w", entry.DN, writeErr)
+		}
+		written++
+		progress.update(written)
+		return writeErr
+	})
+	progress.clear()
+	if errors.Is(err, errExportDeclined) {
+		// Keep what was written so far well formed
+		if err := finish(); err != nil {
+			return fail(exitError, fmt.Errorf("failed to write %s: %w", destination, err))
+		}
+		if err := file.commit(); err != nil {
+			return fail(exitError, fmt.Errorf("failed to write %s: %w", destination, err))
+		}
+		return fail(exitError, fmt.Errorf("%w, after %d entries", errExportDeclined, written))
+	}
+	if writeErr != nil {
+		return fail(exitError, fmt.Errorf("failed to write %s: %w", destination, writeErr))
+	}
+	if err != nil {
+		return fail(exitSearch, err)
+	}
+	if err := finish(); err != nil {
+		return fail(exitError, fmt.Errorf("failed to write %s: %w", destination, err))
+	}
+	if err := file.commit(); err != nil {
+		return fail(exitError, fmt.Errorf("failed to write %s: %w", destination, err))
+	}
+
+	fmt.Fprintf(stderr, "Exported %d entries to %s in %s\n", count, destination, time.Since(start).Round(time.Millisecond))
+	return exitOK
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	goldap "github.com/go-ldap/ldap/v3"
+
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+// newQueryServer serves ou=people with count users and returns a config
+// pointing at it
+func newQueryServer(t *testing.T, count int) (*ldaptest.Server, *config.Config) {
+	t.Helper()
+	entries := []ldaptest.Entry{
+		{DN: "dc=example,dc=com", Attributes: map[string][]string{"dc": {"example"}}},
+		{DN: "ou=people,dc=example,dc=com", Attributes: map[string][]string{"ou": {"people"}}},
+	}
+	for i := 0; i < count; i++ {
+		entries = append(entries, ldaptest.Entry{
+			DN: fmt.Sprintf("uid=user%02d,ou=people,dc=example,dc=com", i),
+			Attributes: map[string][]string{
+				"uid":  {fmt.Sprintf("user%02d", i)},
+				"cn":   {fmt.Sprintf("User %d", i)},
+				"mail": {fmt.Sprintf("user%02d@example.com", i)},
+			},
+		})
+	}
+	server := ldaptest.NewServer(t, entries...)
+	server.BindDN = "cn=admin,dc=example,dc=com"
+	server.BindPassword = "secret"
+
+	cfg := config.Default()
+	cfg.LDAP.Host = "127.0.0.1"
+	cfg.LDAP.Port = server.Port()
+	cfg.LDAP.BaseDN = "dc=example,dc=com"
+	cfg.LDAP.BindUser = server.BindDN
+	cfg.LDAP.BindPass = server.BindPassword
+	cfg.Pagination.PageSize = 10
+	cfg.Retry.Enabled = false
+	return server, cfg
+}
+
+func TestRunQuery_ExportsAllPagesToFile(t *testing.T) {
+	server, cfg := newQueryServer(t, 25)
+	path := filepath.Join(t.TempDir(), "out.ldif")
+
+	var stdout, stderr bytes.Buffer
+	code := runQuery(cfg, queryOptions{
+		filter:     "(uid=*)",
+		baseDN:     "ou=people,dc=example,dc=com",
+		scope:      "one",
+		attributes: []string{"mail", "cn"},
+		output:     "ldif",
+		file:       path,
+		allPages:   true,
+	}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("Expected nothing on stdout when writing a file, got %q", stdout.String())
+	}
+	if server.Searches() != 3 {
+		t.Errorf("Expected 3 pages to be read, got %d", server.Searches())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ldif := string(data)
+	if got := strings.Count(ldif, "\ndn: "); got != 25 {
+		t.Errorf("Expected 25 entries in the LDIF, got %d", got)
+	}
+	if !strings.Contains(ldif, "dn: uid=user00,ou=people,dc=example,dc=com\nmail: user00@example.com\ncn: User 0\n") {
+		t.Errorf("Expected the requested attributes in order, got:\n%s", ldif[:min(len(ldif), 300)])
+	}
+	if !strings.HasPrefix(ldif, "version: 1\n# base: ou=people,dc=example,dc=com | scope: one | filter: (uid=*) | attributes: mail, cn\n") {
+		t.Errorf("Expected the search to be noted in a comment, got:\n%s", ldif[:min(len(ldif), 200)])
+	}
+	if strings.Contains(ldif, "uid: ") {
+		t.Error("Expected only the requested attributes")
+	}
+	if !strings.HasPrefix(stderr.String(), "Exported 25 entries to "+path+" in ") {
+		t.Errorf("Expected a summary on stderr, got %q", stderr.String())
+	}
+}
+
+func TestRunQuery_FirstPageToStdout(t *testing.T) {
+	_, cfg := newQueryServer(t, 25)
+
+	var stdout, stderr bytes.Buffer
+	code := runQuery(cfg, queryOptions{filter: "(uid=*)", scope: "sub", output: "json"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+
+	var entries []struct {
+		DN string `json:"dn"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &entries); err != nil {
+		t.Fatalf("Expected JSON on stdout: %v\n%s", err, stdout.String())
+	}
+	if len(entries) != 10 {
+		t.Errorf("Expected only the first page without -all-pages, got %d entries", len(entries))
+	}
+}
+
+func TestRunQuery_CSV(t *testing.T) {
+	_, cfg := newQueryServer(t, 2)
+
+	var stdout, stderr bytes.Buffer
+	code := runQuery(cfg, queryOptions{filter: "(uid=*)", baseDN: "ou=people,dc=example,dc=com", scope: "one", attributes: []string{"uid", "mail"}, output: "csv"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+	want := "dn,uid,mail\n" +
+		"\"uid=user00,ou=people,dc=example,dc=com\",user00,user00@example.com\n" +
+		"\"uid=user01,ou=people,dc=example,dc=com\",user01,user01@example.com\n"
+	if stdout.String() != want {
+		t.Errorf("Unexpected CSV:\n%s", stdout.String())
+	}
+}
+
+func TestRunQuery_CSVWithBOM(t *testing.T) {
+	_, cfg := newQueryServer(t, 1)
+
+	var stdout, stderr bytes.Buffer
+	code := runQuery(cfg, queryOptions{filter: "(uid=*)", baseDN: "ou=people,dc=example,dc=com", scope: "one", attributes: []string{"uid"}, output: "csv", encoding: "utf-8-bom"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+	if want := "\ufeffdn,uid\n"; !strings.HasPrefix(stdout.String(), want) {
+		t.Errorf("Expected the output to start with a byte order mark, got %q", stdout.String())
+	}
+}
+
+func TestRunQuery_ConfirmsLargeExports(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		answer    string
+		wantCode  int
+		wantCount int
+		wantAsked bool
+	}{
+		{"below the threshold", 25, "", exitOK, 25, false},
+		{"accepted", 20, "y\n", exitOK, 25, true},
+		{"declined", 20, "n\n", exitError, 20, true},
+		{"no answer", 20, "", exitError, 20, true},
+		{"never confirm", -1, "", exitOK, 25, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cfg := newQueryServer(t, 25)
+			cfg.ConfirmThreshold = tt.threshold
+
+			var stdout, stderr bytes.Buffer
+			code := runQuery(cfg, queryOptions{
+				filter:   "(uid=*)",
+				baseDN:   "ou=people,dc=example,dc=com",
+				scope:    "one",
+				output:   "json",
+				allPages: true,
+				confirm:  strings.NewReader(tt.answer),
+			}, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Fatalf("Expected exit code %d, got %d: %s", tt.wantCode, code, stderr.String())
+			}
+			if asked := strings.Contains(stderr.String(), "More than 20 entries match. Export them all? [y/N]"); asked != tt.wantAsked {
+				t.Errorf("Expected asked = %v, stderr: %q", tt.wantAsked, stderr.String())
+			}
+
+			// A declined export is still valid JSON
+			var entries []struct {
+				DN string `json:"dn"`
+			}
+			if err := json.Unmarshal(stdout.Bytes(), &entries); err != nil {
+				t.Fatalf("Expected JSON on stdout: %v\n%s", err, stdout.String())
+			}
+			if len(entries) != tt.wantCount {
+				t.Errorf("Expected %d entries, got %d", tt.wantCount, len(entries))
+			}
+		})
+	}
+
+	// Without a terminal to answer on, scripts are never asked
+	_, cfg := newQueryServer(t, 25)
+	cfg.ConfirmThreshold = 5
+	var stdout, stderr bytes.Buffer
+	if code := runQuery(cfg, queryOptions{filter: "(uid=*)", scope: "sub", output: "json", allPages: true}, &stdout, &stderr); code != exitOK {
+		t.Errorf("Expected a non-interactive export to run unasked, got exit code %d: %s", code, stderr.String())
+	}
+}
+
+func TestExportProgress(t *testing.T) {
+	var stderr bytes.Buffer
+	if progress := newExportProgress(&stderr, false); progress != nil {
+		t.Fatal("Expected no progress when it is turned off")
+	}
+	var off *exportProgress
+	off.update(10)
+	off.clear()
+
+	progress := newExportProgress(&stderr, true)
+	progress.update(1)
+	if stderr.Len() != 0 {
+		t.Errorf("Expected no line before the interval passed, got %q", stderr.String())
+	}
+	progress.interval = 0
+	progress.update(2)
+	progress.update(3)
+	if got := stderr.String(); !strings.Contains(got, "Exported 3 entries") || strings.Count(got, "\r") != 2 {
+		t.Errorf("Expected the line to be rewritten in place, got %q", got)
+	}
+	progress.clear()
+	if !strings.HasSuffix(stderr.String(), "\r\033[K") {
+		t.Error("Expected the line to be cleared at the end")
+	}
+
+	// runQuery reports progress only while reading every page
+	_, cfg := newQueryServer(t, 3)
+	stderr.Reset()
+	var stdout bytes.Buffer
+	if code := runQuery(cfg, queryOptions{filter: "(uid=*)", scope: "sub", output: "json", progress: true}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+	if strings.Contains(stderr.String(), "\r") {
+		t.Errorf("Expected no progress for the first page only, got %q", stderr.String())
+	}
+}
+
+func TestRunQuery_DerefAliases(t *testing.T) {
+	server, cfg := newQueryServer(t, 3)
+	cfg.LDAP.DerefAliases = "search"
+
+	run := func(opts queryOptions) {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if code := runQuery(cfg, opts, &stdout, &stderr); code != exitOK {
+			t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+		}
+	}
+	run(queryOptions{filter: "(uid=*)", output: "ldif"})
+	run(queryOptions{filter: "(uid=*)", output: "ldif", deref: "always"})
+
+	got := server.DerefAliases()
+	want := []int{goldap.DerefInSearching, goldap.DerefAlways}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected the configured mode and then the -deref override, got %v, want %v", got, want)
+	}
+}
+
+func TestRunQuery_FailedExportLeavesTheFileAlone(t *testing.T) {
+	_, cfg := newQueryServer(t, 1)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.ldif")
+	if err := os.WriteFile(path, []byte("previous export\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := runQuery(cfg, queryOptions{filter: "(uid=*)", baseDN: "ou=missing,dc=example,dc=com", file: path}, &stdout, &stderr)
+	if code != exitSearch {
+		t.Fatalf("Expected the search to fail, got exit code %d: %s", code, stderr.String())
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "previous export\n" {
+		t.Errorf("Expected the earlier export to be kept, got %q (%v)", data, err)
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 1 {
+		t.Errorf("Expected no temporary file to be left behind, got %v", files)
+	}
+
+	code = runQuery(cfg, queryOptions{filter: "(uid=*)", baseDN: "ou=people,dc=example,dc=com", file: path}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "dn: uid=user00,") {
+		t.Errorf("Expected the export to replace the file, got %q", data)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("Expected the export to be readable like any file, got %v (%v)", info.Mode(), err)
+	}
+}
+
+func TestRunQuery_ExitCodes(t *testing.T) {
+	_, cfg := newQueryServer(t, 1)
+
+	unreachable := config.Default()
+	unreachable.LDAP.Host = "127.0.0.1"
+	unreachable.LDAP.Port = 1
+	unreachable.LDAP.BaseDN = "dc=example,dc=com"
+	unreachable.Retry.Enabled = false
+
+	badPassword := *cfg
+	badPassword.LDAP.BindPass = "wrong"
+
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		opts queryOptions
+		want int
+	}{
+		{"unknown scope", cfg, queryOptions{filter: "(uid=*)", scope: "deep"}, exitError},
+		{"unknown deref mode", cfg, queryOptions{filter: "(uid=*)", deref: "sometimes"}, exitError},
+		{"csv without attributes", cfg, queryOptions{filter: "(uid=*)", output: "csv"}, exitError},
+		{"unknown encoding", cfg, queryOptions{filter: "(uid=*)", encoding: "ebcdic"}, exitError},
+		{"json in latin-1", cfg, queryOptions{filter: "(uid=*)", output: "json", encoding: "latin-1"}, exitError},
+		{"unreachable server", unreachable, queryOptions{filter: "(uid=*)"}, exitConnection},
+		{"wrong password", &badPassword, queryOptions{filter: "(uid=*)"}, exitAuth},
+		{"missing base", cfg, queryOptions{filter: "(uid=*)", baseDN: "ou=missing,dc=example,dc=com"}, exitSearch},
+		{"invalid filter", cfg, queryOptions{filter: "(uid=*"}, exitSearch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := runQuery(tt.cfg, tt.opts, &stdout, &stderr); code != tt.want {
+				t.Errorf("Expected exit code %d, got %d: %s", tt.want, code, stderr.String())
+			}
+			if !strings.HasPrefix(stderr.String(), "Error: ") {
+				t.Errorf("Expected the error on stderr, got %q", stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunQuery_Annotate(t *testing.T) {
+	_, cfg := newQueryServer(t, 2)
+
+	var stdout, stderr bytes.Buffer
+	code := runQuery(cfg, queryOptions{filter: "(uid=*)", baseDN: "ou=people,dc=example,dc=com", scope: "one", attributes: []string{"uid"}, output: "csv", annotate: true}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "# base: ou=people,dc=example,dc=com | scope: one | filter: (uid=*) | attributes: uid\ndn,uid\n") {
+		t.Errorf("Expected the search on a comment line before the header, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	code = runQuery(cfg, queryOptions{filter: "(uid=*)", scope: "sub", output: "json", annotate: true}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+	var annotated struct {
+		Search struct {
+			BaseDN string `json:"base"`
+			Filter string `json:"filter"`
+		} `json:"search"`
+		Entries []struct {
+			DN string `json:"dn"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &annotated); err != nil {
+		t.Fatalf("Expected JSON on stdout: %v\n%s", err, stdout.String())
+	}
+	if annotated.Search.BaseDN != "dc=example,dc=com" || annotated.Search.Filter != "(uid=*)" || len(annotated.Entries) == 0 {
+		t.Errorf("Expected the search alongside the entries, got %+v", annotated)
+	}
+}
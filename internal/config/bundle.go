@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BundleVersion is the format version of the bundles WriteBundle writes.
+// Bundles from a newer version are refused rather than half understood.
+const BundleVersion = 1
+
+// Bundle is the whole app configuration in one file, for moving moribito to
+// another machine: the config with every saved connection, template and
+// display setting, along with the connections directory files and the query
+// snapshots kept next to it
+type Bundle struct {
+	Version     int                          `yaml:"version"`
+	Exported    time.Time                    `yaml:"exported"`
+	Config      *Config                      `yaml:"config"`
+	Connections map[string][]SavedConnection `yaml:"connections,omitempty"` // Connections directory file name to the connections it adds
+	Snapshots   map[string]string            `yaml:"snapshots,omitempty"`   // Snapshot file name to its contents
+}
+
+// NewBundle bundles cfg with the snapshots stored beside configPath. The
+// connections cfg read from the connections directory are bundled by the
+// file they came from, so restoring puts them back in their own files
+// instead of the config. Bind passwords are left out unless withPasswords is
+// set, so a bundle can be handed around without giving the credentials away.
+func NewBundle(cfg *Config, configPath string, withPasswords bool) (*Bundle, error) {
+	bundled := *cfg.withoutIncludedConnections()
+	if !withPasswords {
+		bundled = *bundled.withoutPasswords()
+	}
+	bundle := &Bundle{Version: BundleVersion, Exported: time.Now().UTC(), Config: &bundled}
+	for _, conn := range cfg.LDAP.SavedConnections {
+		if conn.Source == "" {
+			continue
+		}
+		if !withPasswords {
+			conn.BindPass = ""
+		}
+		if bundle.Connections == nil {
+			bundle.Connections = make(map[string][]SavedConnection)
+		}
+		name := filepath.Base(conn.Source)
+		bundle.Connections[name] = append(bundle.Connections[name], conn)
+	}
+
+	dir := SnapshotDir(configPath)
+	files, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read snapshots in %s: %w", dir, err)
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot %s: %w", file.Name(), err)
+		}
+		if bundle.Snapshots == nil {
+			bundle.Snapshots = make(map[string]string)
+		}
+		bundle.Snapshots[file.Name()] = string(data)
+	}
+	return bundle, nil
+}
+
+// WriteBundle writes a bundle to path as YAML. The file is only readable by
+// the owner, since it may hold passwords.
+func WriteBundle(path string, bundle *Bundle) error {
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config bundle: %w", err)
+	}
+	header := "# Moribito configuration bundle, restore with: moribito -import-config " + filepath.Base(path) + "\n\n"
+	if err := os.WriteFile(path, []byte(header+string(data)), 0600); err != nil {
+		return fmt.Errorf("failed to write config bundle %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadBundle reads a bundle written by WriteBundle
+func ReadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config bundle %s: %w", path, err)
+	}
+	var bundle Bundle
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse config bundle %s: %w", path, err)
+	}
+	switch {
+	case bundle.Version == 0 || bundle.Config == nil:
+		return nil, fmt.Errorf("%s is not a moribito config bundle", path)
+	case bundle.Version > BundleVersion:
+		return nil, fmt.Errorf("config bundle %s is version %d, newer than this moribito reads (%d); upgrade moribito first", path, bundle.Version, BundleVersion)
+	}
+	for name := range bundle.Snapshots {
+		if !isBundledFileName(name) {
+			return nil, fmt.Errorf("config bundle %s has an invalid snapshot name %q", path, name)
+		}
+	}
+	for name := range bundle.Connections {
+		if ext := filepath.Ext(name); !isBundledFileName(name) || (ext != ".yaml" && ext != ".yml") {
+			return nil, fmt.Errorf("config bundle %s has an invalid connections file name %q", path, name)
+		}
+	}
+	return &bundle, nil
+}
+
+// isBundledFileName reports whether a file name from a bundle stays within
+// the directory it is restored to and isn't hidden
+func isBundledFileName(name string) bool {
+	return name == filepath.Base(name) && !strings.HasPrefix(name, ".")
+}
+
+// Restore writes the bundled config to configPath (the default location when
+// empty) and its connections directory files and snapshots beside it,
+// returning the path written. A config or connections file already there is
+// replaced, not merged, and kept as a .bak file first. Connections files may
+// hold passwords and snapshots hold directory data, so like the ones the
+// query view saves they are only readable by the owner.
+func (b *Bundle) Restore(configPath string) (string, error) {
+	if configPath == "" {
+		configPath = GetDefaultConfigPath()
+	}
+	if err := backUp(configPath); err != nil {
+		return "", err
+	}
+	if err := b.Config.Save(configPath); err != nil {
+		return "", err
+	}
+
+	if len(b.Connections) > 0 {
+		dir := ConnectionsDir(configPath)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return "", fmt.Errorf("failed to create connections directory %s: %w", dir, err)
+		}
+		for name, connections := range b.Connections {
+			data, err := yaml.Marshal(connections)
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal connections file %s: %w", name, err)
+			}
+			path := filepath.Join(dir, name)
+			if err := backUp(path); err != nil {
+				return "", err
+			}
+			if err := os.WriteFile(path, data, 0600); err != nil {
+				return "", fmt.Errorf("failed to write connections file %s: %w", name, err)
+			}
+		}
+	}
+
+	if len(b.Snapshots) == 0 {
+		return configPath, nil
+	}
+	dir := SnapshotDir(configPath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory %s: %w", dir, err)
+	}
+	for name, data := range b.Snapshots {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			return "", fmt.Errorf("failed to write snapshot %s: %w", name, err)
+		}
+	}
+	return configPath, nil
+}
+
+// backUp keeps the file at path as a .bak file and removes it, so it can be
+// replaced. A missing file needs no backup.
+func backUp(path string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	if err := os.WriteFile(path+".bak", existing, 0600); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fullConfig returns a config with every section set
+func fullConfig() *Config {
+	return &Config{
+		LDAP: LDAPConfig{
+			Host: "ldap.example.com", Port: 636, BaseDN: "dc=example,dc=com", UseSSL: true,
+			BindUser: "cn=admin,dc=example,dc=com", BindPass: "secret",
+			VerifyTLS: true, CACertPath: "/etc/ssl/ldap-ca.pem", DerefAliases: "search",
+			SavedConnections: []SavedConnection{
+				{Name: "Production", Host: "ldap.example.com", Port: 636, BaseDN: "dc=example,dc=com", UseSSL: true,
+					BindUser: "cn=admin,dc=example,dc=com", BindPass: "secret", LastUsed: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+				{Name: "Staging", Host: "ldap.staging.example.com", Port: 389, BaseDN: "dc=staging,dc=example,dc=com",
+					BindMethod: "gssapi", KerberosRealm: "EXAMPLE.COM"},
+			},
+			SelectedConnection: 1,
+		},
+		Pagination:       PaginationConfig{PageSize: 200},
+		Retry:            RetryConfig{MaxAttempts: 5, InitialDelayMs: 100, MaxDelayMs: 2000, Enabled: true},
+		Throttle:         ThrottleConfig{OperationsPerSecond: 10},
+		ConfirmThreshold: 25,
+		EntryTemplates: []EntryTemplate{
+			{Name: "User", RDN: "uid=${uid}", ObjectClasses: []string{"inetOrgPerson"}, Attributes: map[string][]string{"cn": {"${cn}"}}},
+		},
+		Audit: AuditConfig{LogPath: "~/moribito-audit.jsonl"},
+		Display: DisplayConfig{
+			MultiValue: MultiValueCount, HideEmpty: true, QueryColumnRatio: 0.5,
+			VisibleAttributes: []string{"cn", "mail"}, CodeLanguage: "python", QueryTab: QueryTabSmart, AbbreviateDNs: true,
+		},
+		Theme: ThemeConfig{NoEmoji: true},
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := fullConfig()
+	if err := cfg.Save(source); err != nil {
+		t.Fatal(err)
+	}
+	snapshots := SnapshotDir(source)
+	if err := os.MkdirAll(snapshots, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(snapshots, "people.json"), []byte(`{"name":"people"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := NewBundle(cfg, source, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundlePath := filepath.Join(t.TempDir(), "bundle.yaml")
+	if err := WriteBundle(bundlePath, bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadBundle(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Version != BundleVersion {
+		t.Errorf("Expected version %d, got %d", BundleVersion, read.Version)
+	}
+	target := filepath.Join(t.TempDir(), "moribito", "config.yaml")
+	if _, err := read.Restore(target); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, _, err := Load(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored, cfg) {
+		t.Errorf("Expected every section to survive the round trip\ngot:  %+v\nwant: %+v", restored, cfg)
+	}
+	data, err := os.ReadFile(filepath.Join(SnapshotDir(target), "people.json"))
+	if err != nil || string(data) != `{"name":"people"}` {
+		t.Errorf("Expected the snapshot to be restored, got %q (%v)", data, err)
+	}
+	for path, want := range map[string]os.FileMode{
+		SnapshotDir(target): 0700,
+		filepath.Join(SnapshotDir(target), "people.json"): 0600,
+	} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != want {
+			t.Errorf("Expected %s restored with mode %o, got %o", path, want, info.Mode().Perm())
+		}
+	}
+}
+
+func TestBundleKeepsIncludedConnectionsInTheirFiles(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(source, []byte("ldap:\n  saved_connections:\n    - name: Production\n      host: ldap.example.com\n  selected_connection: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(ConnectionsDir(source), 0755); err != nil {
+		t.Fatal(err)
+	}
+	staging := "host: ldap.staging.example.com\nbind_user: cn=admin,dc=example,dc=com\nbind_pass: secret\n"
+	if err := os.WriteFile(filepath.Join(ConnectionsDir(source), "staging.yaml"), []byte(staging), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _, err := Load(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := NewBundle(cfg, source, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bundle.Config.LDAP.SavedConnections; len(got) != 1 || got[0].Name != "Production" {
+		t.Errorf("Expected only the config file's own connection in the bundled config, got %+v", got)
+	}
+	if got := bundle.Connections["staging.yaml"]; len(got) != 1 || got[0].Name != "staging" || got[0].BindPass != "" {
+		t.Errorf("Expected the included connection bundled by its file without its password, got %+v", got)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.yaml")
+	if err := WriteBundle(bundlePath, bundle); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadBundle(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(t.TempDir(), "moribito", "config.yaml")
+	if _, err := read.Restore(target); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := os.ReadFile(target)
+	if err != nil || strings.Contains(string(written), "staging") {
+		t.Errorf("Expected the included connection kept out of the config file, got %q (%v)", written, err)
+	}
+	restored, _, err := Load(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connections := restored.LDAP.SavedConnections
+	if len(connections) != 2 || connections[1].Name != "staging" || connections[1].Host != "ldap.staging.example.com" {
+		t.Fatalf("Expected the included connection loaded from its file again, got %+v", connections)
+	}
+	if connections[1].Source != filepath.Join(ConnectionsDir(target), "staging.yaml") {
+		t.Errorf("Expected the connection to come from the restored file, got %q", connections[1].Source)
+	}
+	if restored.LDAP.SelectedConnection != 1 {
+		t.Errorf("Expected the included connection to stay selected, got %d", restored.LDAP.SelectedConnection)
+	}
+}
+
+func TestBundleStripsPasswords(t *testing.T) {
+	cfg := fullConfig()
+	bundle, err := NewBundle(cfg, filepath.Join(t.TempDir(), "config.yaml"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bundle.Config.LDAP.BindPass != "" || bundle.Config.LDAP.SavedConnections[0].BindPass != "" {
+		t.Error("Expected the bundle to leave out bind passwords")
+	}
+	if cfg.LDAP.SavedConnections[0].BindPass != "secret" {
+		t.Error("Expected the config itself to keep its passwords")
+	}
+}
+
+func TestBundleRestoreBacksUpExistingConfig(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(target, []byte("ldap:\n  host: old.example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bundle := &Bundle{Version: BundleVersion, Config: fullConfig()}
+	if _, err := bundle.Restore(target); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := os.ReadFile(target + ".bak")
+	if err != nil || !strings.Contains(string(backup), "old.example.com") {
+		t.Errorf("Expected the old config to be kept as a backup, got %q (%v)", backup, err)
+	}
+	restored, _, err := Load(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.LDAP.Host != "ldap.example.com" {
+		t.Errorf("Expected the bundled config to replace the old one, got host %q", restored.LDAP.Host)
+	}
+}
+
+func TestReadBundleRejectsUnknownVersions(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"newer.yaml":       "version: 99\nconfig:\n  ldap:\n    host: x\n",
+		"config.yaml":      "ldap:\n  host: x\n",
+		"escaping.yaml":    "version: 1\nconfig:\n  ldap:\n    host: x\nsnapshots:\n  ../evil.json: '{}'\n",
+		"connections.yaml": "version: 1\nconfig:\n  ldap:\n    host: x\nconnections:\n  ../evil.yaml: []\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadBundle(path); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+}
@@ -5,59 +5,227 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
 )
 
+// Application names used to build config file locations. LegacyAppName is the
+// tool's former name; its locations are still read and can be migrated.
+const (
+	AppName       = "moribito"
+	LegacyAppName = "ldap-cli"
+)
+
 // Config represents the LDAP CLI configuration
 type Config struct {
-	LDAP       LDAPConfig       `yaml:"ldap"`
-	Pagination PaginationConfig `yaml:"pagination"`
-	Retry      RetryConfig      `yaml:"retry"`
+	LDAP             LDAPConfig       `yaml:"ldap" toml:"ldap"`
+	Pagination       PaginationConfig `yaml:"pagination" toml:"pagination"`
+	Retry            RetryConfig      `yaml:"retry" toml:"retry"`
+	Throttle         ThrottleConfig   `yaml:"throttle,omitempty" toml:"throttle,omitempty"`
+	ConfirmThreshold int              `yaml:"confirm_threshold,omitempty" toml:"confirm_threshold,omitempty"` // Exports and loading every page of more entries than this ask first; 0 uses the default, negative never asks
+	ReadOnly         bool             `yaml:"read_only,omitempty" toml:"read_only,omitempty"`                 // Turn off edits, deletes, new entries and bulk modifications
+	EntryTemplates   []EntryTemplate  `yaml:"entry_templates,omitempty" toml:"entry_templates,omitempty"`
+	Audit            AuditConfig      `yaml:"audit,omitempty" toml:"audit,omitempty"`
+	Display          DisplayConfig    `yaml:"display,omitempty" toml:"display,omitempty"`
+	Theme            ThemeConfig      `yaml:"theme,omitempty" toml:"theme,omitempty"`
+
+	loadWarnings []string // Repairs Load made, reported by ValidateAndRepair
 }
 
 // SavedConnection represents a single saved LDAP connection profile
 type SavedConnection struct {
-	Name     string `yaml:"name"`
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	BaseDN   string `yaml:"base_dn"`
-	UseSSL   bool   `yaml:"use_ssl"`
-	UseTLS   bool   `yaml:"use_tls"`
-	BindUser string `yaml:"bind_user"`
-	BindPass string `yaml:"bind_pass"`
+	Name     string `yaml:"name" toml:"name"`
+	Host     string `yaml:"host" toml:"host"`
+	Port     int    `yaml:"port" toml:"port"`
+	BaseDN   string `yaml:"base_dn" toml:"base_dn"`
+	UseSSL   bool   `yaml:"use_ssl" toml:"use_ssl"`
+	UseTLS   bool   `yaml:"use_tls" toml:"use_tls"`
+	BindUser string `yaml:"bind_user" toml:"bind_user"`
+	BindPass string `yaml:"bind_pass" toml:"bind_pass"`
+
+	VerifyTLS  bool   `yaml:"verify_tls,omitempty" toml:"verify_tls,omitempty"`     // Verify the server certificate and host name
+	CACertPath string `yaml:"ca_cert_path,omitempty" toml:"ca_cert_path,omitempty"` // PEM file or directory of CAs to trust for verification, instead of the system store
+
+	BindMethod     string `yaml:"bind_method,omitempty" toml:"bind_method,omitempty"`         // "simple" (default) or "gssapi" for Kerberos
+	KerberosRealm  string `yaml:"kerberos_realm,omitempty" toml:"kerberos_realm,omitempty"`   // Realm of the bind user; empty uses the krb5.conf default
+	KerberosKeytab string `yaml:"kerberos_keytab,omitempty" toml:"kerberos_keytab,omitempty"` // Keytab to authenticate with instead of the credential cache
+	KerberosConfig string `yaml:"kerberos_config,omitempty" toml:"kerberos_config,omitempty"` // krb5.conf path; empty uses $KRB5_CONFIG or /etc/krb5.conf
+
+	LastUsed time.Time `yaml:"last_used,omitempty" toml:"last_used,omitempty"` // When the connection was last connected to
+
+	Source string `yaml:"-" toml:"-"` // File in the connections directory the connection was read from; empty for the config file's own
 }
 
 // LDAPConfig contains LDAP connection settings
 type LDAPConfig struct {
 	// Current/default connection settings (for backward compatibility)
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	BaseDN   string `yaml:"base_dn"`
-	UseSSL   bool   `yaml:"use_ssl"`
-	UseTLS   bool   `yaml:"use_tls"`
-	BindUser string `yaml:"bind_user"`
-	BindPass string `yaml:"bind_pass"`
+	Host     string `yaml:"host" toml:"host"`
+	Port     int    `yaml:"port" toml:"port"`
+	BaseDN   string `yaml:"base_dn" toml:"base_dn"`
+	UseSSL   bool   `yaml:"use_ssl" toml:"use_ssl"`
+	UseTLS   bool   `yaml:"use_tls" toml:"use_tls"`
+	BindUser string `yaml:"bind_user" toml:"bind_user"`
+	BindPass string `yaml:"bind_pass" toml:"bind_pass"`
+
+	VerifyTLS  bool   `yaml:"verify_tls,omitempty" toml:"verify_tls,omitempty"`     // Verify the server certificate and host name
+	CACertPath string `yaml:"ca_cert_path,omitempty" toml:"ca_cert_path,omitempty"` // PEM file or directory of CAs to trust for verification, instead of the system store
+
+	BindMethod     string `yaml:"bind_method,omitempty" toml:"bind_method,omitempty"`         // "simple" (default) or "gssapi" for Kerberos
+	KerberosRealm  string `yaml:"kerberos_realm,omitempty" toml:"kerberos_realm,omitempty"`   // Realm of the bind user; empty uses the krb5.conf default
+	KerberosKeytab string `yaml:"kerberos_keytab,omitempty" toml:"kerberos_keytab,omitempty"` // Keytab to authenticate with instead of the credential cache
+	KerberosConfig string `yaml:"kerberos_config,omitempty" toml:"kerberos_config,omitempty"` // krb5.conf path; empty uses $KRB5_CONFIG or /etc/krb5.conf
+
+	AllowPlaintextBind bool `yaml:"allow_plaintext_bind,omitempty" toml:"allow_plaintext_bind,omitempty"` // Connect without warning when a password would be sent unencrypted
+	AutoConnect        bool `yaml:"auto_connect,omitempty" toml:"auto_connect,omitempty"`                 // Connect to the active connection on startup when it is complete
+	NeverSavePasswords bool `yaml:"never_save_passwords,omitempty" toml:"never_save_passwords,omitempty"` // Keep bind passwords in memory only; they are left out of the config file
+
+	DerefAliases string `yaml:"deref_aliases,omitempty" toml:"deref_aliases,omitempty"` // How searches follow alias entries: never (default), search, find or always
 
 	// Multiple saved connections (new feature)
-	SavedConnections   []SavedConnection `yaml:"saved_connections,omitempty"`
-	SelectedConnection int               `yaml:"selected_connection,omitempty"` // Index into SavedConnections, -1 means use default
+	SavedConnections   []SavedConnection `yaml:"saved_connections,omitempty" toml:"saved_connections,omitempty"`
+	SelectedConnection int               `yaml:"selected_connection,omitempty" toml:"selected_connection,omitempty"` // Index into SavedConnections, -1 means use default
 }
 
 // PaginationConfig contains pagination settings
 type PaginationConfig struct {
-	PageSize uint32 `yaml:"page_size"`
+	PageSize    uint32 `yaml:"page_size" toml:"page_size"`
+	MaxPageSize uint32 `yaml:"max_page_size,omitempty" toml:"max_page_size,omitempty"` // Largest page size used, larger sizes are reduced to it; 0 uses DefaultMaxPageSize
+}
+
+// DefaultMaxPageSize caps the page size when max_page_size isn't set. Many
+// servers refuse larger pages (Active Directory's MaxPageSize is 1000), and
+// each page is held in memory while it is read.
+const DefaultMaxPageSize = 1000
+
+// MaxSize returns the largest page size searches use
+func (p PaginationConfig) MaxSize() uint32 {
+	if p.MaxPageSize == 0 {
+		return DefaultMaxPageSize
+	}
+	return p.MaxPageSize
+}
+
+// EffectivePageSize returns the page size searches use: the configured size,
+// capped at MaxSize
+func (p PaginationConfig) EffectivePageSize() uint32 {
+	return min(p.PageSize, p.MaxSize())
+}
+
+// ClampPageSize reduces a page size above MaxSize to it, returning a warning
+// when it does and an empty string otherwise
+func (p *PaginationConfig) ClampPageSize() string {
+	limit := p.MaxSize()
+	if p.PageSize <= limit {
+		return ""
+	}
+	warning := fmt.Sprintf("Page size %d is above the maximum of %d. Using %d; raise pagination.max_page_size if the server allows larger pages.", p.PageSize, limit, limit)
+	p.PageSize = limit
+	return warning
 }
 
 // RetryConfig contains retry settings for LDAP operations
 type RetryConfig struct {
-	MaxAttempts    int  `yaml:"max_attempts"`     // Maximum number of retry attempts
-	InitialDelayMs int  `yaml:"initial_delay_ms"` // Initial delay in milliseconds
-	MaxDelayMs     int  `yaml:"max_delay_ms"`     // Maximum delay in milliseconds
-	Enabled        bool `yaml:"enabled"`          // Whether retries are enabled
+	MaxAttempts    int  `yaml:"max_attempts" toml:"max_attempts"`         // Maximum number of retry attempts
+	InitialDelayMs int  `yaml:"initial_delay_ms" toml:"initial_delay_ms"` // Initial delay in milliseconds
+	MaxDelayMs     int  `yaml:"max_delay_ms" toml:"max_delay_ms"`         // Maximum delay in milliseconds
+	Enabled        bool `yaml:"enabled" toml:"enabled"`                   // Whether retries are enabled
+}
+
+// DefaultOperationsPerSecond is the throttle rate used when none is configured,
+// generous enough not to slow down everyday use
+const DefaultOperationsPerSecond = 50
+
+// ThrottleConfig limits the rate of bulk and recursive operations, such as
+// bulk modify, exports of every page and expand all, to spare shared servers
+type ThrottleConfig struct {
+	OperationsPerSecond float64 `yaml:"operations_per_second,omitempty" toml:"operations_per_second,omitempty"` // 0 uses the default; negative turns the limit off
+}
+
+// Rate returns the operations allowed per second, 0 when unlimited
+func (t ThrottleConfig) Rate() float64 {
+	switch {
+	case t.OperationsPerSecond < 0:
+		return 0
+	case t.OperationsPerSecond == 0:
+		return DefaultOperationsPerSecond
+	default:
+		return t.OperationsPerSecond
+	}
+}
+
+// DefaultConfirmThreshold is the number of entries an export or the loading
+// of every query page may reach without asking for confirmation when no
+// threshold is configured
+const DefaultConfirmThreshold = 1000
+
+// NeedsConfirmation reports whether an export or the loading of every query
+// page reaching count entries must be confirmed first under the configured
+// threshold: 0 uses DefaultConfirmThreshold and a negative threshold never
+// asks. Bulk modify and find and replace don't use it, as they always have
+// the number of entries typed before writing.
+func NeedsConfirmation(threshold, count int) bool {
+	switch {
+	case threshold < 0:
+		return false
+	case threshold == 0:
+		threshold = DefaultConfirmThreshold
+	}
+	return count > threshold
+}
+
+// AuditConfig contains audit log settings
+type AuditConfig struct {
+	LogPath string `yaml:"log_path,omitempty" toml:"log_path,omitempty"` // JSON lines file recording every write; empty disables the log
+}
+
+// Multi-valued attribute display styles in the record view
+const (
+	MultiValueInline = "inline" // All values on the attribute row, joined by the separator
+	MultiValueLines  = "lines"  // One value per line below the attribute
+	MultiValueCount  = "count"  // Only the number of values until expanded
+)
+
+// DefaultMultiValueSeparator joins inline values when no separator is configured
+const DefaultMultiValueSeparator = " • "
+
+// What Tab does in the query view's filter input
+const (
+	QueryTabSwitch = "switch" // Leave the filter, switching views as elsewhere
+	QueryTabIndent = "indent" // Insert indentation; Ctrl+] moves to the results
+	QueryTabSmart  = "smart"  // Move to the results at the end of the filter, otherwise indent
+)
+
+// Bounds of the column width ratios; zero selects the default split
+const (
+	MinColumnRatio = 0.1
+	MaxColumnRatio = 0.9
+)
+
+// DisplayConfig contains record, query and tree view display settings
+type DisplayConfig struct {
+	MultiValue              string   `yaml:"multi_value,omitempty" toml:"multi_value,omitempty"`                             // "inline" (default), "lines" or "count"
+	MultiValueSeparator     string   `yaml:"multi_value_separator,omitempty" toml:"multi_value_separator,omitempty"`         // Separator for inline values; empty uses " • "
+	HideEmpty               bool     `yaml:"hide_empty,omitempty" toml:"hide_empty,omitempty"`                               // Start with empty and operational attributes hidden
+	RecordColumnRatio       float64  `yaml:"record_column_ratio,omitempty" toml:"record_column_ratio,omitempty"`             // Share of the record table for the attribute column; 0 uses a third
+	QueryColumnRatio        float64  `yaml:"query_column_ratio,omitempty" toml:"query_column_ratio,omitempty"`               // Share of the query table for the DN column; 0 uses a third
+	VisibleAttributes       []string `yaml:"visible_attributes,omitempty" toml:"visible_attributes,omitempty"`               // Attributes the record view shows until the rest are revealed; empty shows all
+	NormalizeAttributeNames bool     `yaml:"normalize_attribute_names,omitempty" toml:"normalize_attribute_names,omitempty"` // Show attribute names in the case the schema defines
+	AllNamingContexts       bool     `yaml:"all_naming_contexts,omitempty" toml:"all_naming_contexts,omitempty"`             // Tree shows every naming context as a root instead of the base DN
+	CodeLanguage            string   `yaml:"code_language,omitempty" toml:"code_language,omitempty"`                         // Language the record view copies entries as code in: "go" (default) or "python"
+	TreeLabelAttribute      string   `yaml:"tree_label_attribute,omitempty" toml:"tree_label_attribute,omitempty"`           // Attribute the tree labels entries with, such as displayName; empty or missing shows the RDN
+	TreeChildFilter         string   `yaml:"tree_child_filter,omitempty" toml:"tree_child_filter,omitempty"`                 // Filter the tree lists children with, such as (!(objectClass=computer)); empty lists every child
+	QueryTab                string   `yaml:"query_tab,omitempty" toml:"query_tab,omitempty"`                                 // What Tab does in the query filter: "switch" (default), "indent" or "smart"
+	AbbreviateDNs           bool     `yaml:"abbreviate_dns,omitempty" toml:"abbreviate_dns,omitempty"`                       // Query results show DNs as their RDN values only, the selected row in full
+	TreeMaxAutoDepth        int      `yaml:"tree_max_auto_depth,omitempty" toml:"tree_max_auto_depth,omitempty"`             // Levels the tree's expand all loads below the selected entry; 0 uses the default, negative has no limit
+	MultilineAttributes     []string `yaml:"multiline_attributes,omitempty" toml:"multiline_attributes,omitempty"`           // Attributes the record view always wraps across lines instead of truncating
+	TreeCollapseRoot        bool     `yaml:"tree_collapse_root,omitempty" toml:"tree_collapse_root,omitempty"`               // Tree loads with the base DN collapsed instead of listing its children, for very large bases
 }
 
-// Load loads configuration from a YAML file and returns the config and actual path used
+// Load loads configuration from a YAML or TOML file and returns the config and actual path used
 func Load(configPath string) (*Config, string, error) {
 	// If no config path provided, look for default locations
 	if configPath == "" {
@@ -70,7 +238,7 @@ func Load(configPath string) (*Config, string, error) {
 	}
 
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	if err := unmarshalConfig(configPath, data, &config); err != nil {
 		return nil, "", fmt.Errorf("failed to parse config file %s: %w", configPath, err)
 	}
 
@@ -87,6 +255,11 @@ func Load(configPath string) (*Config, string, error) {
 	if config.Pagination.PageSize == 0 {
 		config.Pagination.PageSize = 50
 	}
+	if warning := config.Pagination.ClampPageSize(); warning != "" {
+		config.loadWarnings = append(config.loadWarnings, warning)
+	}
+
+	config.loadConnectionsDir(ConnectionsDir(configPath))
 
 	// Set retry defaults
 	if !config.Retry.Enabled && config.Retry.MaxAttempts == 0 && config.Retry.InitialDelayMs == 0 {
@@ -109,6 +282,36 @@ func Load(configPath string) (*Config, string, error) {
 	return &config, configPath, nil
 }
 
+// AuditLogPath returns the audit log file, with a leading ~ expanded to the
+// home directory. It is empty when auditing is disabled.
+func (c *Config) AuditLogPath() string {
+	path := c.Audit.LogPath
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(homeDir, path[1:])
+		}
+	}
+	return path
+}
+
+// SnapshotDir returns the directory query result snapshots are kept in, next
+// to the config file at configPath (the default location when empty)
+func SnapshotDir(configPath string) string {
+	if configPath == "" {
+		configPath = GetDefaultConfigPath()
+	}
+	return filepath.Join(filepath.Dir(configPath), "snapshots")
+}
+
+// CaptureDir returns the directory screen captures are saved in, next to the
+// config file at configPath (the default location when empty)
+func CaptureDir(configPath string) string {
+	if configPath == "" {
+		configPath = GetDefaultConfigPath()
+	}
+	return filepath.Join(filepath.Dir(configPath), "captures")
+}
+
 // GetActiveConnection returns the currently active LDAP connection settings
 func (c *Config) GetActiveConnection() LDAPConnection {
 	// If no saved connections or selected connection is -1, use default
@@ -122,6 +325,14 @@ func (c *Config) GetActiveConnection() LDAPConnection {
 			UseTLS:   c.LDAP.UseTLS,
 			BindUser: c.LDAP.BindUser,
 			BindPass: c.LDAP.BindPass,
+
+			VerifyTLS:  c.LDAP.VerifyTLS,
+			CACertPath: c.LDAP.CACertPath,
+
+			BindMethod:     c.LDAP.BindMethod,
+			KerberosRealm:  c.LDAP.KerberosRealm,
+			KerberosKeytab: c.LDAP.KerberosKeytab,
+			KerberosConfig: c.LDAP.KerberosConfig,
 		}
 	}
 
@@ -130,7 +341,13 @@ func (c *Config) GetActiveConnection() LDAPConnection {
 		c.LDAP.SelectedConnection = 0
 	}
 
-	saved := c.LDAP.SavedConnections[c.LDAP.SelectedConnection]
+	return c.SavedConnectionSettings(c.LDAP.SelectedConnection)
+}
+
+// SavedConnectionSettings returns the settings of the saved connection at
+// index, whether or not it is the active one
+func (c *Config) SavedConnectionSettings(index int) LDAPConnection {
+	saved := c.LDAP.SavedConnections[index]
 	return LDAPConnection{
 		Name:     saved.Name,
 		Host:     saved.Host,
@@ -140,6 +357,14 @@ func (c *Config) GetActiveConnection() LDAPConnection {
 		UseTLS:   saved.UseTLS,
 		BindUser: saved.BindUser,
 		BindPass: saved.BindPass,
+
+		VerifyTLS:  saved.VerifyTLS,
+		CACertPath: saved.CACertPath,
+
+		BindMethod:     saved.BindMethod,
+		KerberosRealm:  saved.KerberosRealm,
+		KerberosKeytab: saved.KerberosKeytab,
+		KerberosConfig: saved.KerberosConfig,
 	}
 }
 
@@ -153,6 +378,14 @@ type LDAPConnection struct {
 	UseTLS   bool
 	BindUser string
 	BindPass string
+
+	VerifyTLS  bool
+	CACertPath string
+
+	BindMethod     string
+	KerberosRealm  string
+	KerberosKeytab string
+	KerberosConfig string
 }
 
 // SetActiveConnection updates the current connection settings from a saved connection
@@ -173,6 +406,12 @@ func (c *Config) SetActiveConnection(index int) {
 	c.LDAP.UseTLS = saved.UseTLS
 	c.LDAP.BindUser = saved.BindUser
 	c.LDAP.BindPass = saved.BindPass
+	c.LDAP.VerifyTLS = saved.VerifyTLS
+	c.LDAP.CACertPath = saved.CACertPath
+	c.LDAP.BindMethod = saved.BindMethod
+	c.LDAP.KerberosRealm = saved.KerberosRealm
+	c.LDAP.KerberosKeytab = saved.KerberosKeytab
+	c.LDAP.KerberosConfig = saved.KerberosConfig
 }
 
 // AddSavedConnection adds a new saved connection
@@ -201,6 +440,38 @@ func (c *Config) RemoveSavedConnection(index int) {
 	)
 }
 
+// MarkConnectionUsed records that the saved connection at index was connected to at t
+func (c *Config) MarkConnectionUsed(index int, t time.Time) {
+	if index < 0 || index >= len(c.LDAP.SavedConnections) {
+		return
+	}
+	c.LDAP.SavedConnections[index].LastUsed = t
+}
+
+// SetActiveBaseDN changes the base DN of the active connection, updating the
+// selected saved connection as well when there is one
+func (c *Config) SetActiveBaseDN(baseDN string) {
+	c.LDAP.BaseDN = baseDN
+	index := c.LDAP.SelectedConnection
+	if index >= 0 && index < len(c.LDAP.SavedConnections) {
+		c.LDAP.SavedConnections[index].BaseDN = baseDN
+	}
+}
+
+// RecentConnections returns the indices of the saved connections ordered by
+// most recently used. Connections that have never been used keep their
+// configured order after the used ones.
+func (c *Config) RecentConnections() []int {
+	indices := make([]int, len(c.LDAP.SavedConnections))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		return c.LDAP.SavedConnections[indices[a]].LastUsed.After(c.LDAP.SavedConnections[indices[b]].LastUsed)
+	})
+	return indices
+}
+
 // UpdateSavedConnection updates a saved connection by index
 func (c *Config) UpdateSavedConnection(index int, conn SavedConnection) {
 	if index < 0 || index >= len(c.LDAP.SavedConnections) {
@@ -215,9 +486,20 @@ func (c *Config) UpdateSavedConnection(index int, conn SavedConnection) {
 	}
 }
 
+// ThemeConfig contains settings for how the UI is drawn
+type ThemeConfig struct {
+	NoEmoji bool `yaml:"no_emoji,omitempty" toml:"no_emoji,omitempty"` // Use ASCII instead of emoji, for terminals that render emoji poorly
+}
+
 // ValidateAndRepair checks the config for issues and repairs them, returning warnings
 func (c *Config) ValidateAndRepair() []string {
-	var warnings []string
+	warnings := c.loadWarnings
+	c.loadWarnings = nil
+
+	// Page sizes set after loading, such as with -page-size, are capped too
+	if warning := c.Pagination.ClampPageSize(); warning != "" {
+		warnings = append(warnings, warning)
+	}
 
 	// Check if selected connection index is out of bounds
 	if len(c.LDAP.SavedConnections) > 0 && c.LDAP.SelectedConnection >= len(c.LDAP.SavedConnections) {
@@ -226,6 +508,53 @@ func (c *Config) ValidateAndRepair() []string {
 		warnings = append(warnings, fmt.Sprintf("Selected connection index %d was invalid (only %d connections exist). Reset to first connection.", oldIndex, len(c.LDAP.SavedConnections)))
 	}
 
+	// StartTLS can't be negotiated on a connection that is already TLS
+	if c.LDAP.UseSSL && c.LDAP.UseTLS {
+		c.LDAP.UseTLS = false
+		warnings = append(warnings, "use_ssl and use_tls are both set. Using SSL (LDAPS) and disabling StartTLS.")
+	}
+	for i := range c.LDAP.SavedConnections {
+		conn := &c.LDAP.SavedConnections[i]
+		if conn.UseSSL && conn.UseTLS {
+			conn.UseTLS = false
+			warnings = append(warnings, fmt.Sprintf("Connection %q has both use_ssl and use_tls set. Using SSL (LDAPS) and disabling StartTLS.", conn.Name))
+		}
+	}
+
+	switch c.Display.MultiValue {
+	case "", MultiValueInline, MultiValueLines, MultiValueCount:
+	default:
+		warnings = append(warnings, fmt.Sprintf("Multi-value display %q is not supported (use inline, lines or count). Using inline.", c.Display.MultiValue))
+		c.Display.MultiValue = MultiValueInline
+	}
+
+	switch c.Display.QueryTab {
+	case "", QueryTabSwitch, QueryTabIndent, QueryTabSmart:
+	default:
+		warnings = append(warnings, fmt.Sprintf("Query tab behavior %q is not supported (use switch, indent or smart). Using switch.", c.Display.QueryTab))
+		c.Display.QueryTab = QueryTabSwitch
+	}
+
+	switch strings.ToLower(c.Display.CodeLanguage) {
+	case "", "go", "python":
+	default:
+		warnings = append(warnings, fmt.Sprintf("Code language %q is not supported (use go or python). Using go.", c.Display.CodeLanguage))
+		c.Display.CodeLanguage = ""
+	}
+
+	for _, ratio := range []struct {
+		name  string
+		value *float64
+	}{
+		{"record_column_ratio", &c.Display.RecordColumnRatio},
+		{"query_column_ratio", &c.Display.QueryColumnRatio},
+	} {
+		if *ratio.value != 0 && (*ratio.value < MinColumnRatio || *ratio.value > MaxColumnRatio) {
+			warnings = append(warnings, fmt.Sprintf("Display %s %g is outside %g-%g. Using the default column widths.", ratio.name, *ratio.value, MinColumnRatio, MaxColumnRatio))
+			*ratio.value = 0
+		}
+	}
+
 	return warnings
 }
 
@@ -235,10 +564,12 @@ func findConfigFile() string {
 	candidates := []string{
 		"./config.yaml",
 		"./config.yml",
-		"./moribito.yaml", // New format
-		"./moribito.yml",  // New format
-		"./ldap-cli.yaml", // Legacy support
-		"./ldap-cli.yml",  // Legacy support
+		"./" + AppName + ".yaml", // New format
+		"./" + AppName + ".yml",  // New format
+		"./config.toml",
+		"./" + AppName + ".toml",
+		"./" + LegacyAppName + ".yaml", // Legacy support
+		"./" + LegacyAppName + ".yml",  // Legacy support
 	}
 
 	// Add OS-specific and home directory paths
@@ -267,27 +598,31 @@ func getOSSpecificConfigPaths() []string {
 		// Windows: Use APPDATA for user-specific config
 		if appData := os.Getenv("APPDATA"); appData != "" {
 			candidates = append(candidates,
-				filepath.Join(appData, "moribito", "config.yaml"),
-				filepath.Join(appData, "moribito", "config.yml"),
+				filepath.Join(appData, AppName, "config.yaml"),
+				filepath.Join(appData, AppName, "config.yml"),
+				filepath.Join(appData, AppName, "config.toml"),
 			)
 		}
 		// Fallback to user profile directory
 		candidates = append(candidates,
-			filepath.Join(homeDir, ".moribito.yaml"),
-			filepath.Join(homeDir, ".moribito.yml"),
+			filepath.Join(homeDir, "."+AppName+".yaml"),
+			filepath.Join(homeDir, "."+AppName+".yml"),
 		)
 
 	case "darwin":
 		// macOS: Prefer ~/.moribito/ as requested in the issue, with fallbacks
 		candidates = append(candidates,
-			filepath.Join(homeDir, ".moribito", "config.yaml"), // Primary choice as per issue
-			filepath.Join(homeDir, ".moribito", "config.yml"),
-			filepath.Join(homeDir, "Library", "Application Support", "moribito", "config.yaml"), // macOS standard
-			filepath.Join(homeDir, "Library", "Application Support", "moribito", "config.yml"),
-			filepath.Join(homeDir, ".moribito.yaml"), // Fallback
-			filepath.Join(homeDir, ".moribito.yml"),
-			filepath.Join(homeDir, ".config", "moribito", "config.yaml"), // XDG fallback
-			filepath.Join(homeDir, ".config", "moribito", "config.yml"),
+			filepath.Join(homeDir, "."+AppName, "config.yaml"), // Primary choice as per issue
+			filepath.Join(homeDir, "."+AppName, "config.yml"),
+			filepath.Join(homeDir, "."+AppName, "config.toml"),
+			filepath.Join(homeDir, "Library", "Application Support", AppName, "config.yaml"), // macOS standard
+			filepath.Join(homeDir, "Library", "Application Support", AppName, "config.yml"),
+			filepath.Join(homeDir, "Library", "Application Support", AppName, "config.toml"),
+			filepath.Join(homeDir, "."+AppName+".yaml"), // Fallback
+			filepath.Join(homeDir, "."+AppName+".yml"),
+			filepath.Join(homeDir, ".config", AppName, "config.yaml"), // XDG fallback
+			filepath.Join(homeDir, ".config", AppName, "config.yml"),
+			filepath.Join(homeDir, ".config", AppName, "config.toml"),
 		)
 
 	default:
@@ -298,45 +633,55 @@ func getOSSpecificConfigPaths() []string {
 		}
 
 		candidates = append(candidates,
-			filepath.Join(xdgConfigHome, "moribito", "config.yaml"), // XDG standard
-			filepath.Join(xdgConfigHome, "moribito", "config.yml"),
-			filepath.Join(homeDir, ".moribito", "config.yaml"), // Also support directory approach
-			filepath.Join(homeDir, ".moribito", "config.yml"),
-			filepath.Join(homeDir, ".moribito.yaml"), // Fallback
-			filepath.Join(homeDir, ".moribito.yml"),
+			filepath.Join(xdgConfigHome, AppName, "config.yaml"), // XDG standard
+			filepath.Join(xdgConfigHome, AppName, "config.yml"),
+			filepath.Join(xdgConfigHome, AppName, "config.toml"),
+			filepath.Join(homeDir, "."+AppName, "config.yaml"), // Also support directory approach
+			filepath.Join(homeDir, "."+AppName, "config.yml"),
+			filepath.Join(homeDir, "."+AppName, "config.toml"),
+			filepath.Join(homeDir, "."+AppName+".yaml"), // Fallback
+			filepath.Join(homeDir, "."+AppName+".yml"),
 		)
 
 		// System-wide config for Unix systems
 		candidates = append(candidates,
-			"/etc/moribito/config.yaml",
-			"/etc/moribito/config.yml",
+			filepath.Join("/etc", AppName, "config.yaml"),
+			filepath.Join("/etc", AppName, "config.yml"),
+			filepath.Join("/etc", AppName, "config.toml"),
 		)
 	}
 
 	// Add legacy support for all platforms
-	candidates = append(candidates,
-		filepath.Join(homeDir, ".ldap-cli.yaml"),
-		filepath.Join(homeDir, ".ldap-cli.yml"),
-	)
+	candidates = append(candidates, legacyUserConfigPaths(homeDir)...)
+
+	if runtime.GOOS != "windows" && runtime.GOOS != "darwin" {
+		// System-wide legacy support for Linux
+		candidates = append(candidates,
+			filepath.Join("/etc", LegacyAppName, "config.yaml"),
+			filepath.Join("/etc", LegacyAppName, "config.yml"),
+		)
+	}
+
+	return candidates
+}
 
-	// Add legacy XDG support for Unix-like systems
+// legacyUserConfigPaths returns the per-user locations used by ldap-cli, the tool's former name
+func legacyUserConfigPaths(homeDir string) []string {
+	candidates := []string{
+		filepath.Join(homeDir, "."+LegacyAppName+".yaml"),
+		filepath.Join(homeDir, "."+LegacyAppName+".yml"),
+	}
+
+	// Legacy XDG support for Unix-like systems
 	if runtime.GOOS != "windows" {
 		xdgConfigHome := os.Getenv("XDG_CONFIG_HOME")
 		if xdgConfigHome == "" {
 			xdgConfigHome = filepath.Join(homeDir, ".config")
 		}
 		candidates = append(candidates,
-			filepath.Join(xdgConfigHome, "ldap-cli", "config.yaml"),
-			filepath.Join(xdgConfigHome, "ldap-cli", "config.yml"),
+			filepath.Join(xdgConfigHome, LegacyAppName, "config.yaml"),
+			filepath.Join(xdgConfigHome, LegacyAppName, "config.yml"),
 		)
-
-		if runtime.GOOS != "darwin" {
-			// System-wide legacy support for Linux
-			candidates = append(candidates,
-				"/etc/ldap-cli/config.yaml",
-				"/etc/ldap-cli/config.yml",
-			)
-		}
 	}
 
 	return candidates
@@ -352,17 +697,17 @@ func GetDefaultConfigPath() string {
 	switch runtime.GOOS {
 	case "windows":
 		if appData := os.Getenv("APPDATA"); appData != "" {
-			return filepath.Join(appData, "moribito", "config.yaml")
+			return filepath.Join(appData, AppName, "config.yaml")
 		}
-		return filepath.Join(homeDir, ".moribito.yaml")
+		return filepath.Join(homeDir, "."+AppName+".yaml")
 	case "darwin":
-		return filepath.Join(homeDir, ".moribito", "config.yaml")
+		return filepath.Join(homeDir, "."+AppName, "config.yaml")
 	default:
 		xdgConfigHome := os.Getenv("XDG_CONFIG_HOME")
 		if xdgConfigHome == "" {
 			xdgConfigHome = filepath.Join(homeDir, ".config")
 		}
-		return filepath.Join(xdgConfigHome, "moribito", "config.yaml")
+		return filepath.Join(xdgConfigHome, AppName, "config.yaml")
 	}
 }
 
@@ -412,8 +757,25 @@ func (c *Config) Save(configPath string) error {
 		return fmt.Errorf("failed to create config directory %s: %w", configDir, err)
 	}
 
-	// Marshal the config to YAML
-	data, err := yaml.Marshal(c)
+	// Update a YAML file in place, keeping what the user wrote around the values
+	written := c.withoutUnsavedSecrets().withoutIncludedConnections()
+	if !isTOMLPath(configPath) {
+		if existing, err := os.ReadFile(configPath); err == nil {
+			data, ok, err := mergeYAML(existing, written)
+			if err != nil {
+				return fmt.Errorf("failed to marshal config: %w", err)
+			}
+			if ok {
+				if err := os.WriteFile(configPath, data, 0644); err != nil {
+					return fmt.Errorf("failed to write config file %s: %w", configPath, err)
+				}
+				return nil
+			}
+		}
+	}
+
+	// Marshal the config in the format matching the file extension
+	data, err := marshalConfig(configPath, written)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -429,6 +791,49 @@ func (c *Config) Save(configPath string) error {
 	return nil
 }
 
+// withoutUnsavedSecrets returns the config as it is written to disk. With
+// never_save_passwords set that is a copy without any bind password, so the
+// passwords typed in stay in c for the session only.
+func (c *Config) withoutUnsavedSecrets() *Config {
+	if !c.LDAP.NeverSavePasswords {
+		return c
+	}
+	return c.withoutPasswords()
+}
+
+// withoutPasswords returns a copy of the config with every bind password removed
+func (c *Config) withoutPasswords() *Config {
+	stripped := *c
+	stripped.LDAP.BindPass = ""
+	stripped.LDAP.SavedConnections = append([]SavedConnection(nil), c.LDAP.SavedConnections...)
+	for i := range stripped.LDAP.SavedConnections {
+		stripped.LDAP.SavedConnections[i].BindPass = ""
+	}
+	return &stripped
+}
+
+// isTOMLPath reports whether a config path should be read and written as TOML
+func isTOMLPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".toml")
+}
+
+// unmarshalConfig decodes config data using the format implied by the file extension.
+// Anything that isn't .toml is treated as YAML.
+func unmarshalConfig(path string, data []byte, config *Config) error {
+	if isTOMLPath(path) {
+		return toml.Unmarshal(data, config)
+	}
+	return yaml.Unmarshal(data, config)
+}
+
+// marshalConfig encodes a config using the format implied by the file extension
+func marshalConfig(path string, config *Config) ([]byte, error) {
+	if isTOMLPath(path) {
+		return toml.Marshal(config)
+	}
+	return yaml.Marshal(config)
+}
+
 // Default returns a default configuration
 func Default() *Config {
 	return &Config{
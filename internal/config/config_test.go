@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -682,3 +683,533 @@ func TestLoadReturnsActualPathWhenAutoDiscovered(t *testing.T) {
 		t.Errorf("Expected host 'currentdir.example.com', got '%s' - loaded wrong file?", cfg.LDAP.Host)
 	}
 }
+
+func TestConfigLoadTOML(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.toml")
+	configContent := `[ldap]
+host = "toml.example.com"
+port = 636
+base_dn = "dc=toml,dc=com"
+use_ssl = true
+
+[pagination]
+page_size = 75
+
+[retry]
+enabled = true
+max_attempts = 7
+initial_delay_ms = 250
+max_delay_ms = 8000
+`
+
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, _, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load TOML config: %v", err)
+	}
+
+	if cfg.LDAP.Host != "toml.example.com" || cfg.LDAP.Port != 636 || !cfg.LDAP.UseSSL {
+		t.Errorf("Unexpected LDAP settings: %+v", cfg.LDAP)
+	}
+	if cfg.Pagination.PageSize != 75 {
+		t.Errorf("Expected page size 75, got %d", cfg.Pagination.PageSize)
+	}
+	if cfg.Retry.MaxAttempts != 7 || cfg.Retry.InitialDelayMs != 250 || cfg.Retry.MaxDelayMs != 8000 {
+		t.Errorf("Unexpected retry settings: %+v", cfg.Retry)
+	}
+}
+
+func TestConfigTOMLRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.toml")
+
+	original := Default()
+	original.LDAP.Host = "roundtrip.example.com"
+	original.Pagination.PageSize = 123
+	original.Retry.Enabled = true
+	original.Retry.MaxAttempts = 5
+	original.Retry.InitialDelayMs = 100
+	original.Retry.MaxDelayMs = 2000
+	original.AddSavedConnection(SavedConnection{
+		Name:   "Production",
+		Host:   "prod.example.com",
+		Port:   636,
+		BaseDN: "dc=prod,dc=com",
+		UseSSL: true,
+	})
+
+	if err := original.Save(configPath); err != nil {
+		t.Fatalf("Failed to save TOML config: %v", err)
+	}
+
+	// The saved file must be TOML, not YAML
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read saved config: %v", err)
+	}
+	if !strings.Contains(string(data), "[pagination]") || !strings.Contains(string(data), "[retry]") {
+		t.Errorf("Expected TOML tables in saved config, got:\n%s", data)
+	}
+
+	loaded, _, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load saved TOML config: %v", err)
+	}
+
+	if loaded.LDAP.Host != original.LDAP.Host {
+		t.Errorf("Expected host %s, got %s", original.LDAP.Host, loaded.LDAP.Host)
+	}
+	if loaded.Pagination != original.Pagination {
+		t.Errorf("Expected pagination %+v, got %+v", original.Pagination, loaded.Pagination)
+	}
+	if loaded.Retry != original.Retry {
+		t.Errorf("Expected retry %+v, got %+v", original.Retry, loaded.Retry)
+	}
+	if len(loaded.LDAP.SavedConnections) != 1 || loaded.LDAP.SavedConnections[0] != original.LDAP.SavedConnections[0] {
+		t.Errorf("Expected saved connections to round-trip, got %+v", loaded.LDAP.SavedConnections)
+	}
+}
+
+func TestRecentConnections(t *testing.T) {
+	cfg := Default()
+	cfg.LDAP.SavedConnections = []SavedConnection{
+		{Name: "never"},
+		{Name: "older"},
+		{Name: "newest"},
+		{Name: "also-never"},
+	}
+
+	now := time.Now()
+	cfg.MarkConnectionUsed(1, now.Add(-time.Hour))
+	cfg.MarkConnectionUsed(2, now)
+	cfg.MarkConnectionUsed(10, now) // Out of range indices are ignored
+
+	got := cfg.RecentConnections()
+	want := []int{2, 1, 0, 3}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Expected order %v, got %v", want, got)
+	}
+}
+
+func TestSetActiveBaseDN(t *testing.T) {
+	cfg := Default()
+	cfg.LDAP.SavedConnections = []SavedConnection{
+		{Name: "first", BaseDN: "dc=example,dc=com"},
+		{Name: "second", BaseDN: "dc=other,dc=com"},
+	}
+	cfg.SetActiveConnection(1)
+
+	cfg.SetActiveBaseDN("ou=people,dc=other,dc=com")
+
+	if cfg.LDAP.BaseDN != "ou=people,dc=other,dc=com" {
+		t.Errorf("Expected active base DN to change, got %q", cfg.LDAP.BaseDN)
+	}
+	if got := cfg.LDAP.SavedConnections[1].BaseDN; got != "ou=people,dc=other,dc=com" {
+		t.Errorf("Expected selected saved connection to change, got %q", got)
+	}
+	if got := cfg.LDAP.SavedConnections[0].BaseDN; got != "dc=example,dc=com" {
+		t.Errorf("Expected other saved connections to be unchanged, got %q", got)
+	}
+
+	cfg.SetActiveConnection(-1)
+	cfg.SetActiveBaseDN("dc=default,dc=com")
+	if cfg.LDAP.BaseDN != "dc=default,dc=com" {
+		t.Errorf("Expected default base DN to change, got %q", cfg.LDAP.BaseDN)
+	}
+	if got := cfg.LDAP.SavedConnections[1].BaseDN; got != "ou=people,dc=other,dc=com" {
+		t.Errorf("Expected saved connections to be unchanged without a selection, got %q", got)
+	}
+}
+
+func TestLastUsedPersists(t *testing.T) {
+	lastUsed := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+	for _, name := range []string{"config.yaml", "config.toml"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			cfg := Default()
+			cfg.LDAP.SavedConnections = []SavedConnection{{Name: "used", Host: "a"}, {Name: "unused", Host: "b"}}
+			cfg.MarkConnectionUsed(0, lastUsed)
+
+			if err := cfg.Save(path); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+			data, _ := os.ReadFile(path)
+			if strings.Count(string(data), "last_used") != 1 {
+				t.Errorf("Expected last_used only on the used connection:\n%s", data)
+			}
+
+			loaded, _, err := Load(path)
+			if err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if !loaded.LDAP.SavedConnections[0].LastUsed.Equal(lastUsed) {
+				t.Errorf("Expected LastUsed %v, got %v", lastUsed, loaded.LDAP.SavedConnections[0].LastUsed)
+			}
+			if !loaded.LDAP.SavedConnections[1].LastUsed.IsZero() {
+				t.Errorf("Expected unused connection to have no LastUsed")
+			}
+		})
+	}
+}
+
+func TestAuditConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+	configContent := `ldap:
+  host: test.example.com
+  base_dn: dc=test,dc=com
+audit:
+  log_path: /var/log/moribito/audit.jsonl
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, _, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if got := cfg.AuditLogPath(); got != "/var/log/moribito/audit.jsonl" {
+		t.Errorf("Expected audit log path from config, got %q", got)
+	}
+
+	if Default().AuditLogPath() != "" {
+		t.Error("Expected auditing to be off by default")
+	}
+
+	t.Setenv("HOME", tempDir)
+	cfg.Audit.LogPath = "~/audit.jsonl"
+	if got := cfg.AuditLogPath(); got != filepath.Join(tempDir, "audit.jsonl") {
+		t.Errorf("Expected ~ to expand to the home directory, got %q", got)
+	}
+}
+
+func TestTLSVerificationSettings(t *testing.T) {
+	cfg := Default()
+	cfg.AddSavedConnection(SavedConnection{
+		Name:       "Verified",
+		Host:       "ldap.example.com",
+		Port:       389,
+		UseTLS:     true,
+		VerifyTLS:  true,
+		CACertPath: "/etc/ssl/corp-ca.pem",
+	})
+	cfg.SetActiveConnection(0)
+
+	if !cfg.LDAP.VerifyTLS || cfg.LDAP.CACertPath != "/etc/ssl/corp-ca.pem" {
+		t.Error("Expected TLS settings to be copied to the current settings")
+	}
+	active := cfg.GetActiveConnection()
+	if !active.VerifyTLS || active.CACertPath != "/etc/ssl/corp-ca.pem" {
+		t.Errorf("Expected TLS settings on the active connection, got %+v", active)
+	}
+
+	cfg.SetActiveConnection(-1)
+	cfg.LDAP.VerifyTLS = false
+	if cfg.GetActiveConnection().VerifyTLS {
+		t.Error("Expected the default connection's own setting")
+	}
+}
+
+func TestKerberosSettings(t *testing.T) {
+	cfg := Default()
+	cfg.AddSavedConnection(SavedConnection{
+		Name:           "AD",
+		Host:           "dc1.corp.example.com",
+		BindMethod:     "gssapi",
+		KerberosRealm:  "CORP.EXAMPLE.COM",
+		KerberosKeytab: "/etc/moribito.keytab",
+		KerberosConfig: "/etc/krb5-corp.conf",
+	})
+	cfg.SetActiveConnection(0)
+
+	active := cfg.GetActiveConnection()
+	if active.BindMethod != "gssapi" || active.KerberosRealm != "CORP.EXAMPLE.COM" ||
+		active.KerberosKeytab != "/etc/moribito.keytab" || active.KerberosConfig != "/etc/krb5-corp.conf" {
+		t.Errorf("Expected Kerberos settings on the active connection, got %+v", active)
+	}
+	if cfg.LDAP.BindMethod != "gssapi" || cfg.LDAP.KerberosRealm != "CORP.EXAMPLE.COM" {
+		t.Error("Expected Kerberos settings to be copied to the current settings")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	loaded, _, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if got := loaded.LDAP.SavedConnections[0]; got.BindMethod != "gssapi" || got.KerberosKeytab != "/etc/moribito.keytab" {
+		t.Errorf("Expected Kerberos settings to round trip, got %+v", got)
+	}
+}
+
+func TestDisplayConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+	configContent := `ldap:
+  host: test.example.com
+  base_dn: dc=test,dc=com
+display:
+  multi_value: count
+  multi_value_separator: " | "
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, _, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Display.MultiValue != MultiValueCount || cfg.Display.MultiValueSeparator != " | " {
+		t.Errorf("Expected display settings from config, got %+v", cfg.Display)
+	}
+	if warnings := cfg.ValidateAndRepair(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	cfg.Display.MultiValue = "table"
+	if warnings := cfg.ValidateAndRepair(); len(warnings) != 1 || cfg.Display.MultiValue != MultiValueInline {
+		t.Errorf("Expected an unknown style to be repaired to inline, got %v and %q", warnings, cfg.Display.MultiValue)
+	}
+}
+
+func TestValidateAndRepairCodeLanguage(t *testing.T) {
+	cfg := Default()
+	cfg.Display.CodeLanguage = "Python"
+	if warnings := cfg.ValidateAndRepair(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	cfg.Display.CodeLanguage = "ruby"
+	if warnings := cfg.ValidateAndRepair(); len(warnings) != 1 || cfg.Display.CodeLanguage != "" {
+		t.Errorf("Expected an unknown language to be reset, got %v and %q", warnings, cfg.Display.CodeLanguage)
+	}
+}
+
+func TestValidateAndRepairQueryTab(t *testing.T) {
+	cfg := Default()
+	for _, mode := range []string{"", QueryTabSwitch, QueryTabIndent, QueryTabSmart} {
+		cfg.Display.QueryTab = mode
+		if warnings := cfg.ValidateAndRepair(); len(warnings) != 0 {
+			t.Errorf("Expected no warnings for %q, got %v", mode, warnings)
+		}
+	}
+
+	cfg.Display.QueryTab = "spaces"
+	if warnings := cfg.ValidateAndRepair(); len(warnings) != 1 || cfg.Display.QueryTab != QueryTabSwitch {
+		t.Errorf("Expected an unknown tab behavior to be repaired to switch, got %v and %q", warnings, cfg.Display.QueryTab)
+	}
+}
+
+func TestPageSizeLimit(t *testing.T) {
+	tempDir := t.TempDir()
+	load := func(content string) *Config {
+		t.Helper()
+		configPath := filepath.Join(tempDir, "config.yaml")
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+		cfg, _, err := Load(configPath)
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		return cfg
+	}
+
+	// A reasonable size passes through unchanged and without a warning
+	cfg := load("pagination:\n  page_size: 500\n")
+	if cfg.Pagination.PageSize != 500 || cfg.Pagination.EffectivePageSize() != 500 {
+		t.Errorf("Expected page size 500 to be kept, got %d", cfg.Pagination.PageSize)
+	}
+	if warnings := cfg.ValidateAndRepair(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	// An oversized one is capped at the default maximum when loading, and the
+	// warning is reported once
+	cfg = load("pagination:\n  page_size: 100000\n")
+	if cfg.Pagination.PageSize != DefaultMaxPageSize {
+		t.Errorf("Expected page size to be capped at %d, got %d", DefaultMaxPageSize, cfg.Pagination.PageSize)
+	}
+	warnings := cfg.ValidateAndRepair()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "Page size 100000 is above the maximum of 1000") {
+		t.Errorf("Expected a warning about the capped page size, got %v", warnings)
+	}
+	if warnings := cfg.ValidateAndRepair(); len(warnings) != 0 {
+		t.Errorf("Expected the warning to be reported once, got %v", warnings)
+	}
+
+	// The maximum can be raised
+	cfg = load("pagination:\n  page_size: 2000\n  max_page_size: 5000\n")
+	if cfg.Pagination.PageSize != 2000 {
+		t.Errorf("Expected page size 2000 to be allowed under max_page_size 5000, got %d", cfg.Pagination.PageSize)
+	}
+
+	// Sizes set after loading, such as with -page-size, are capped by
+	// ValidateAndRepair, and EffectivePageSize never exceeds the maximum
+	cfg = Default()
+	cfg.Pagination.PageSize = 20000
+	if got := cfg.Pagination.EffectivePageSize(); got != DefaultMaxPageSize {
+		t.Errorf("Expected the effective page size to be %d, got %d", DefaultMaxPageSize, got)
+	}
+	if warnings := cfg.ValidateAndRepair(); len(warnings) != 1 || cfg.Pagination.PageSize != DefaultMaxPageSize {
+		t.Errorf("Expected the page size to be capped with a warning, got %d and %v", cfg.Pagination.PageSize, warnings)
+	}
+	cfg.Pagination.PageSize = DefaultMaxPageSize
+	if warning := cfg.Pagination.ClampPageSize(); warning != "" {
+		t.Errorf("Expected the maximum itself to be allowed, got %q", warning)
+	}
+}
+
+func TestDisplayColumnRatios(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := Default()
+	cfg.Display.RecordColumnRatio = 0.25
+	cfg.Display.QueryColumnRatio = 0.6
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	loaded, _, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if loaded.Display.RecordColumnRatio != 0.25 || loaded.Display.QueryColumnRatio != 0.6 {
+		t.Errorf("Expected column ratios to round-trip, got %+v", loaded.Display)
+	}
+
+	loaded.Display.QueryColumnRatio = 1.5
+	if warnings := loaded.ValidateAndRepair(); len(warnings) != 1 || loaded.Display.QueryColumnRatio != 0 {
+		t.Errorf("Expected an out of range ratio to be reset, got %v and %g", warnings, loaded.Display.QueryColumnRatio)
+	}
+	if loaded.Display.RecordColumnRatio != 0.25 {
+		t.Error("Expected a valid ratio to be kept")
+	}
+}
+
+func TestValidateAndRepairSSLAndTLS(t *testing.T) {
+	cfg := Default()
+	cfg.LDAP.UseSSL = true
+	cfg.LDAP.UseTLS = true
+	cfg.LDAP.SavedConnections = []SavedConnection{
+		{Name: "Both", UseSSL: true, UseTLS: true},
+		{Name: "StartTLS", UseTLS: true},
+	}
+
+	warnings := cfg.ValidateAndRepair()
+	if len(warnings) != 2 {
+		t.Fatalf("Expected a warning for the current and saved connection, got %v", warnings)
+	}
+	if !cfg.LDAP.UseSSL || cfg.LDAP.UseTLS {
+		t.Errorf("Expected SSL to be kept and StartTLS cleared, got ssl=%v tls=%v", cfg.LDAP.UseSSL, cfg.LDAP.UseTLS)
+	}
+	if both := cfg.LDAP.SavedConnections[0]; !both.UseSSL || both.UseTLS {
+		t.Errorf("Expected the saved connection to be repaired, got %+v", both)
+	}
+	if !cfg.LDAP.SavedConnections[1].UseTLS {
+		t.Error("Expected a StartTLS-only connection to be kept")
+	}
+}
+
+func TestThrottleConfig_Rate(t *testing.T) {
+	tests := []struct {
+		configured float64
+		want       float64
+	}{
+		{0, DefaultOperationsPerSecond},
+		{5, 5},
+		{0.5, 0.5},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		if got := (ThrottleConfig{OperationsPerSecond: tt.configured}).Rate(); got != tt.want {
+			t.Errorf("Rate() with %v configured = %v, want %v", tt.configured, got, tt.want)
+		}
+	}
+}
+
+func TestNeedsConfirmation(t *testing.T) {
+	tests := []struct {
+		threshold int
+		count     int
+		want      bool
+	}{
+		{0, DefaultConfirmThreshold, false},
+		{0, DefaultConfirmThreshold + 1, true},
+		{10, 10, false},
+		{10, 11, true},
+		{1, 1, false},
+		{-1, 1000000, false},
+	}
+	for _, tt := range tests {
+		if got := NeedsConfirmation(tt.threshold, tt.count); got != tt.want {
+			t.Errorf("NeedsConfirmation(%d, %d) = %v, want %v", tt.threshold, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestSnapshotDir(t *testing.T) {
+	configPath := filepath.Join("home", "me", ".config", "moribito", "work.yaml")
+	if got, want := SnapshotDir(configPath), filepath.Join("home", "me", ".config", "moribito", "snapshots"); got != want {
+		t.Errorf("Expected snapshots next to the config file in %s, got %s", want, got)
+	}
+	if got, want := SnapshotDir(""), filepath.Join(filepath.Dir(GetDefaultConfigPath()), "snapshots"); got != want {
+		t.Errorf("Expected the default config directory %s, got %s", want, got)
+	}
+}
+
+func TestCaptureDir(t *testing.T) {
+	configPath := filepath.Join("home", "me", ".config", "moribito", "work.yaml")
+	if got, want := CaptureDir(configPath), filepath.Join("home", "me", ".config", "moribito", "captures"); got != want {
+		t.Errorf("Expected captures next to the config file in %s, got %s", want, got)
+	}
+}
+
+func TestNeverSavePasswords(t *testing.T) {
+	for _, name := range []string{"config.yaml", "config.toml"} {
+		t.Run(name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), name)
+			cfg := Default()
+			cfg.LDAP.BindUser = "cn=admin,dc=example,dc=com"
+			cfg.LDAP.BindPass = "secret"
+			cfg.AddSavedConnection(SavedConnection{Name: "Production", Host: "prod.example.com", BindUser: "cn=admin,dc=prod,dc=com", BindPass: "prod-secret"})
+
+			// Written once with the password, as an existing file would be
+			if err := cfg.Save(configPath); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+			cfg.LDAP.NeverSavePasswords = true
+			if err := cfg.Save(configPath); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatalf("Failed to read saved config: %v", err)
+			}
+			if strings.Contains(string(data), "secret") {
+				t.Errorf("Expected no passwords in the file, got:\n%s", data)
+			}
+			loaded, _, err := Load(configPath)
+			if err != nil {
+				t.Fatalf("Failed to load saved config: %v", err)
+			}
+			if !loaded.LDAP.NeverSavePasswords || loaded.LDAP.SavedConnections[0].BindUser != "cn=admin,dc=prod,dc=com" {
+				t.Errorf("Expected the rest of the settings to be saved, got %+v", loaded.LDAP)
+			}
+
+			// The passwords stay in memory for the session
+			if cfg.LDAP.BindPass != "secret" || cfg.LDAP.SavedConnections[0].BindPass != "prod-secret" {
+				t.Errorf("Expected the in-memory passwords to be kept, got %q and %q", cfg.LDAP.BindPass, cfg.LDAP.SavedConnections[0].BindPass)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConnectionsDirName is the directory next to the config file whose YAML
+// files each add saved connections, config.d style
+const ConnectionsDirName = "connections.d"
+
+// ConnectionsDir returns the connections directory for the config file at
+// configPath (the default location when empty)
+func ConnectionsDir(configPath string) string {
+	if configPath == "" {
+		configPath = GetDefaultConfigPath()
+	}
+	return filepath.Join(filepath.Dir(configPath), ConnectionsDirName)
+}
+
+// loadConnectionsDir adds the connections in every *.yaml and *.yml file of
+// dir to the saved connections, in file name order. A file holds one
+// connection, named after the file unless it sets a name, or a list of them.
+// Names already taken are skipped with a warning, so the main config wins
+// over the directory and earlier files over later ones. A missing directory
+// adds nothing.
+func (c *Config) loadConnectionsDir(dir string) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	more, _ := filepath.Glob(filepath.Join(dir, "*.yml"))
+	paths = append(paths, more...)
+	sort.Strings(paths)
+
+	taken := make(map[string]string, len(c.LDAP.SavedConnections))
+	for _, conn := range c.LDAP.SavedConnections {
+		taken[strings.ToLower(conn.Name)] = "the config file"
+	}
+
+	for _, path := range paths {
+		connections, err := readConnectionFile(path)
+		if err != nil {
+			c.loadWarnings = append(c.loadWarnings, fmt.Sprintf("Skipped %s: %v", path, err))
+			continue
+		}
+		for _, conn := range connections {
+			key := strings.ToLower(conn.Name)
+			if source, ok := taken[key]; ok {
+				c.loadWarnings = append(c.loadWarnings, fmt.Sprintf("Connection %q in %s is already defined in %s. Skipped it.", conn.Name, filepath.Base(path), source))
+				continue
+			}
+			taken[key] = filepath.Base(path)
+			conn.Source = path
+			c.LDAP.SavedConnections = append(c.LDAP.SavedConnections, conn)
+		}
+	}
+}
+
+// readConnectionFile reads the connections in one file of the connections
+// directory
+func readConnectionFile(path string) ([]SavedConnection, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	var connections []SavedConnection
+	if doc.Content[0].Kind == yaml.SequenceNode {
+		err = doc.Content[0].Decode(&connections)
+	} else {
+		var conn SavedConnection
+		err = doc.Content[0].Decode(&conn)
+		if conn.Name == "" {
+			conn.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		connections = []SavedConnection{conn}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, conn := range connections {
+		if strings.TrimSpace(conn.Name) == "" {
+			return nil, fmt.Errorf("connection %d has no name", i+1)
+		}
+	}
+	return connections, nil
+}
+
+// withoutIncludedConnections returns the config as it is written to the
+// config file: without the connections read from the connections directory,
+// which stay in their own files. The selected connection is renumbered to
+// the index it gets when the file and directory are loaded again.
+func (c *Config) withoutIncludedConnections() *Config {
+	var kept, included []int
+	for i, conn := range c.LDAP.SavedConnections {
+		if conn.Source == "" {
+			kept = append(kept, i)
+		} else {
+			included = append(included, i)
+		}
+	}
+	if len(included) == 0 {
+		return c
+	}
+
+	stripped := *c
+	stripped.LDAP.SavedConnections = make([]SavedConnection, 0, len(kept))
+	for _, i := range kept {
+		stripped.LDAP.SavedConnections = append(stripped.LDAP.SavedConnections, c.LDAP.SavedConnections[i])
+	}
+	for position, i := range append(kept, included...) {
+		if i == c.LDAP.SelectedConnection {
+			stripped.LDAP.SelectedConnection = position
+		}
+	}
+	return &stripped
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConnectionsDir(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	writeFile(configPath, `
+ldap:
+    host: ldap.example.com
+    base_dn: dc=example,dc=com
+    selected_connection: 2
+    saved_connections:
+        - name: Production
+          host: ldap.prod.example.com
+          base_dn: dc=prod,dc=example,dc=com
+`)
+
+	connectionsDir := filepath.Join(dir, ConnectionsDirName)
+	if err := os.Mkdir(connectionsDir, 0700); err != nil {
+		t.Fatalf("Failed to create %s: %v", connectionsDir, err)
+	}
+	// A single connection named after its file
+	writeFile(filepath.Join(connectionsDir, "staging.yaml"), `
+host: ldap.staging.example.com
+base_dn: dc=staging,dc=example,dc=com
+`)
+	// A list, with a name the config file already uses
+	writeFile(filepath.Join(connectionsDir, "team.yml"), `
+- name: production
+  host: elsewhere.example.com
+- name: Lab
+  host: ldap.lab.example.com
+`)
+	writeFile(filepath.Join(connectionsDir, "broken.yaml"), "host: [unclosed")
+	writeFile(filepath.Join(connectionsDir, "notes.txt"), "host: ignored.example.com")
+
+	cfg, _, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	var names []string
+	for _, conn := range cfg.LDAP.SavedConnections {
+		names = append(names, conn.Name)
+	}
+	if strings.Join(names, ",") != "Production,staging,Lab" {
+		t.Fatalf("Expected the config's connection then the directory's in file order, got %v", names)
+	}
+	if cfg.LDAP.SavedConnections[0].Source != "" || cfg.LDAP.SavedConnections[1].Source != filepath.Join(connectionsDir, "staging.yaml") {
+		t.Errorf("Expected included connections to record their file, got %+v", cfg.LDAP.SavedConnections)
+	}
+	if cfg.GetActiveConnection().Host != "ldap.lab.example.com" {
+		t.Errorf("Expected selected_connection to reach an included connection, got %s", cfg.GetActiveConnection().Host)
+	}
+
+	warnings := strings.Join(cfg.ValidateAndRepair(), "\n")
+	for _, want := range []string{"broken.yaml", `Connection "production" in team.yml is already defined in the config file`} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Expected a warning containing %q, got:\n%s", want, warnings)
+		}
+	}
+
+	// Saving leaves the included connections in their own files
+	cfg.LDAP.SelectedConnection = 1
+	cfg.AddSavedConnection(SavedConnection{Name: "New", Host: "ldap.new.example.com"})
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read saved config: %v", err)
+	}
+	if strings.Contains(string(data), "staging") || strings.Contains(string(data), "Lab") {
+		t.Errorf("Expected included connections not to be written, got:\n%s", data)
+	}
+	if len(cfg.LDAP.SavedConnections) != 4 {
+		t.Errorf("Expected the in-memory connections to be kept, got %d", len(cfg.LDAP.SavedConnections))
+	}
+
+	// Loading again selects the same connection
+	reloaded, _, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	names = nil
+	for _, conn := range reloaded.LDAP.SavedConnections {
+		names = append(names, conn.Name)
+	}
+	if strings.Join(names, ",") != "Production,New,staging,Lab" {
+		t.Fatalf("Expected the saved connections then the included ones, got %v", names)
+	}
+	if reloaded.GetActiveConnection().Host != "ldap.staging.example.com" {
+		t.Errorf("Expected the selection to survive saving, got %s", reloaded.GetActiveConnection().Host)
+	}
+}
+
+func TestLoadConnectionsDirMissing(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("ldap:\n    host: ldap.example.com\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	cfg, _, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(cfg.LDAP.SavedConnections) != 0 || len(cfg.ValidateAndRepair()) != 0 {
+		t.Errorf("Expected no connections or warnings without the directory, got %+v", cfg.LDAP.SavedConnections)
+	}
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// legacyRenames moves the settings ldap-cli wrote under names moribito no
+// longer reads, as key paths from the top of the file. ldap-cli kept the
+// connection settings at the top level and named the bind settings after
+// the bind DN and password.
+var legacyRenames = []struct{ from, to []string }{
+	{[]string{"host"}, []string{"ldap", "host"}},
+	{[]string{"port"}, []string{"ldap", "port"}},
+	{[]string{"base_dn"}, []string{"ldap", "base_dn"}},
+	{[]string{"use_ssl"}, []string{"ldap", "use_ssl"}},
+	{[]string{"use_tls"}, []string{"ldap", "use_tls"}},
+	{[]string{"bind_dn"}, []string{"ldap", "bind_user"}},
+	{[]string{"bind_password"}, []string{"ldap", "bind_pass"}},
+	{[]string{"connections"}, []string{"ldap", "saved_connections"}},
+	{[]string{"page_size"}, []string{"pagination", "page_size"}},
+	{[]string{"ldap", "bind_dn"}, []string{"ldap", "bind_user"}},
+	{[]string{"ldap", "bind_password"}, []string{"ldap", "bind_pass"}},
+	{[]string{"ldap", "connections"}, []string{"ldap", "saved_connections"}},
+}
+
+// legacyConnectionRenames renames the bind settings of each saved connection
+var legacyConnectionRenames = []struct{ from, to string }{
+	{"bind_dn", "bind_user"},
+	{"bind_password", "bind_pass"},
+}
+
+// MigrateLegacyConfig copies an ldap-cli configuration to the moribito default path
+// when it is the only configuration found. Settings ldap-cli named differently are
+// converted to the current names, see legacyRenames; a file already using them is
+// copied unchanged. Comments and every other setting survive, and the legacy file
+// is left in place.
+// It returns the source and destination paths, or empty strings if nothing was migrated.
+func MigrateLegacyConfig() (from, to string, err error) {
+	found := findConfigFile()
+	if found == "" {
+		return "", "", nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", nil
+	}
+
+	if !isLegacyConfigPath(found, homeDir) {
+		return "", "", nil
+	}
+
+	data, err := os.ReadFile(found)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read legacy config file %s: %w", found, err)
+	}
+
+	// Refuse to migrate a file we couldn't load anyway
+	var cfg Config
+	if err := unmarshalConfig(found, data, &cfg); err != nil {
+		return "", "", fmt.Errorf("failed to parse legacy config file %s: %w", found, err)
+	}
+	if !isTOMLPath(found) {
+		if data, err = convertLegacyConfig(data); err != nil {
+			return "", "", fmt.Errorf("failed to convert legacy config file %s: %w", found, err)
+		}
+	}
+
+	to = GetDefaultConfigPath()
+	if _, err := os.Stat(to); err == nil {
+		return "", "", nil
+	}
+
+	configDir := filepath.Dir(to)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create config directory %s: %w", configDir, err)
+	}
+
+	if err := os.WriteFile(to, data, 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write config file %s: %w", to, err)
+	}
+
+	return found, to, nil
+}
+
+// isLegacyConfigPath reports whether path is one of the per-user ldap-cli config locations
+func isLegacyConfigPath(path, homeDir string) bool {
+	for _, legacy := range legacyUserConfigPaths(homeDir) {
+		if filepath.Clean(path) == filepath.Clean(legacy) {
+			return true
+		}
+	}
+	return false
+}
+
+// convertLegacyConfig renames the ldap-cli settings in a YAML config to the
+// current schema, keeping comments and everything else as written. A
+// setting already present under its current name wins over the legacy one.
+// Data without legacy settings is returned unchanged.
+func convertLegacyConfig(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, nil
+	}
+	root := doc.Content[0]
+
+	changed := false
+	for _, rename := range legacyRenames {
+		if moveYAMLKey(root, rename.from, rename.to) {
+			changed = true
+		}
+	}
+	if ldap := mappingValue(root, "ldap"); ldap != nil && ldap.Kind == yaml.MappingNode {
+		if connections := mappingValue(ldap, "saved_connections"); connections != nil && connections.Kind == yaml.SequenceNode {
+			for _, conn := range connections.Content {
+				if conn.Kind != yaml.MappingNode {
+					continue
+				}
+				for _, rename := range legacyConnectionRenames {
+					if moveYAMLKey(conn, []string{rename.from}, []string{rename.to}) {
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	if !changed {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(yamlIndent(data))
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// moveYAMLKey moves the value at the key path from to the key path to
+// within the mapping root, creating the mappings to leads through where the
+// value was. Nothing moves when from is absent or to is already set. It
+// reports whether the value moved.
+func moveYAMLKey(root *yaml.Node, from, to []string) bool {
+	parent := root
+	for _, key := range from[:len(from)-1] {
+		if parent = mappingValue(parent, key); parent == nil || parent.Kind != yaml.MappingNode {
+			return false
+		}
+	}
+	index := -1
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == from[len(from)-1] {
+			index = i
+		}
+	}
+	if index < 0 {
+		return false
+	}
+
+	// Leave the value be when the current setting is there, or a section on
+	// the way to it isn't a mapping
+	existing := root
+	for _, name := range to[:len(to)-1] {
+		next := mappingValue(existing, name)
+		if next == nil {
+			existing = nil
+			break
+		}
+		if next.Kind != yaml.MappingNode {
+			return false
+		}
+		existing = next
+	}
+	if existing != nil && mappingValue(existing, to[len(to)-1]) != nil {
+		return false
+	}
+
+	key, value := parent.Content[index], parent.Content[index+1]
+	parent.Content = append(parent.Content[:index:index], parent.Content[index+2:]...)
+
+	target := root
+	for _, name := range to[:len(to)-1] {
+		next := mappingValue(target, name)
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			section := []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, next}
+			at := len(target.Content)
+			if target == parent {
+				at = index
+				// A comment heading the file stays ahead of the new section
+				section[0].HeadComment, key.HeadComment = key.HeadComment, ""
+			}
+			target.Content = append(target.Content[:at:at], append(section, target.Content[at:]...)...)
+		}
+		target = next
+	}
+	key.Value = to[len(to)-1]
+	target.Content = append(target.Content, key, value)
+	return true
+}
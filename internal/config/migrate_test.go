@@ -0,0 +1,244 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const legacyConfigContent = `# My old ldap-cli settings
+ldap:
+  host: legacy.example.com
+  port: 636
+  base_dn: dc=legacy,dc=com
+  use_ssl: true
+  bind_user: cn=admin,dc=legacy,dc=com
+  bind_pass: secret
+  saved_connections:
+    - name: Staging
+      host: staging.example.com
+      port: 389
+      base_dn: dc=staging,dc=com
+pagination:
+  page_size: 200
+retry:
+  enabled: true
+  max_attempts: 4
+  initial_delay_ms: 100
+  max_delay_ms: 1000
+`
+
+// isolateConfigDirs points the home and XDG directories at a fresh temp dir and
+// runs the test from an empty working directory so no real config files are found
+func isolateConfigDirs(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("config locations on Windows depend on APPDATA")
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(oldWd) })
+
+	return home
+}
+
+func TestMigrateLegacyConfig(t *testing.T) {
+	for _, legacyRel := range []string{
+		".ldap-cli.yaml",
+		filepath.Join(".config", "ldap-cli", "config.yaml"),
+	} {
+		t.Run(legacyRel, func(t *testing.T) {
+			home := isolateConfigDirs(t)
+
+			legacyPath := filepath.Join(home, legacyRel)
+			if err := os.MkdirAll(filepath.Dir(legacyPath), 0755); err != nil {
+				t.Fatalf("Failed to create legacy dir: %v", err)
+			}
+			if err := os.WriteFile(legacyPath, []byte(legacyConfigContent), 0644); err != nil {
+				t.Fatalf("Failed to write legacy config: %v", err)
+			}
+
+			from, to, err := MigrateLegacyConfig()
+			if err != nil {
+				t.Fatalf("Migration failed: %v", err)
+			}
+			if from != legacyPath {
+				t.Errorf("Expected migration from %s, got %s", legacyPath, from)
+			}
+			if to != GetDefaultConfigPath() {
+				t.Errorf("Expected migration to %s, got %s", GetDefaultConfigPath(), to)
+			}
+
+			// The file is copied byte for byte, comments included
+			migrated, err := os.ReadFile(to)
+			if err != nil {
+				t.Fatalf("Failed to read migrated config: %v", err)
+			}
+			if string(migrated) != legacyConfigContent {
+				t.Errorf("Migrated config differs from legacy config:\n%s", migrated)
+			}
+
+			// The legacy file is left in place
+			if _, err := os.Stat(legacyPath); err != nil {
+				t.Errorf("Expected legacy config to remain: %v", err)
+			}
+
+			// Auto-discovery now picks up the migrated file with all settings intact
+			cfg, path, err := Load("")
+			if err != nil {
+				t.Fatalf("Failed to load migrated config: %v", err)
+			}
+			if path != to {
+				t.Errorf("Expected Load to use %s, got %s", to, path)
+			}
+			if cfg.LDAP.Host != "legacy.example.com" || cfg.LDAP.BindPass != "secret" || !cfg.LDAP.UseSSL {
+				t.Errorf("LDAP settings lost in migration: %+v", cfg.LDAP)
+			}
+			if len(cfg.LDAP.SavedConnections) != 1 || cfg.LDAP.SavedConnections[0].Host != "staging.example.com" {
+				t.Errorf("Saved connections lost in migration: %+v", cfg.LDAP.SavedConnections)
+			}
+			if cfg.Pagination.PageSize != 200 || cfg.Retry.MaxAttempts != 4 {
+				t.Errorf("Pagination/retry lost in migration: %+v %+v", cfg.Pagination, cfg.Retry)
+			}
+
+			// A second run has nothing to do
+			from, to, err = MigrateLegacyConfig()
+			if err != nil || from != "" || to != "" {
+				t.Errorf("Expected no second migration, got %q -> %q (%v)", from, to, err)
+			}
+		})
+	}
+}
+
+// oldSchemaConfigContent is an ldap-cli config with the connection settings
+// at the top level and the old bind setting names
+const oldSchemaConfigContent = `# Written by ldap-cli
+host: legacy.example.com # the main server
+port: 636
+base_dn: dc=legacy,dc=com
+use_ssl: true
+bind_dn: cn=admin,dc=legacy,dc=com
+bind_password: secret
+page_size: 200
+connections:
+  - name: Staging
+    host: staging.example.com
+    bind_dn: cn=reader,dc=staging,dc=com
+    bind_password: reader-secret
+retry:
+  enabled: true
+  max_attempts: 4
+`
+
+func TestMigrateLegacyConfigConvertsOldSchema(t *testing.T) {
+	home := isolateConfigDirs(t)
+	if err := os.WriteFile(filepath.Join(home, ".ldap-cli.yaml"), []byte(oldSchemaConfigContent), 0644); err != nil {
+		t.Fatalf("Failed to write legacy config: %v", err)
+	}
+
+	_, to, err := MigrateLegacyConfig()
+	if err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+	migrated, err := os.ReadFile(to)
+	if err != nil {
+		t.Fatalf("Failed to read migrated config: %v", err)
+	}
+	for _, kept := range []string{"# Written by ldap-cli", "# the main server"} {
+		if !strings.Contains(string(migrated), kept) {
+			t.Errorf("Expected the comment %q kept, got:\n%s", kept, migrated)
+		}
+	}
+	for _, old := range []string{"bind_dn", "bind_password", "\nconnections:"} {
+		if strings.Contains(string(migrated), old) {
+			t.Errorf("Expected %q converted, got:\n%s", old, migrated)
+		}
+	}
+
+	cfg, _, err := Load(to)
+	if err != nil {
+		t.Fatalf("Failed to load migrated config: %v", err)
+	}
+	ldap := cfg.LDAP
+	if ldap.Host != "legacy.example.com" || ldap.Port != 636 || ldap.BaseDN != "dc=legacy,dc=com" || !ldap.UseSSL {
+		t.Errorf("Connection settings lost in migration: %+v", ldap)
+	}
+	if ldap.BindUser != "cn=admin,dc=legacy,dc=com" || ldap.BindPass != "secret" {
+		t.Errorf("Bind settings lost in migration: %q %q", ldap.BindUser, ldap.BindPass)
+	}
+	if len(ldap.SavedConnections) != 1 || ldap.SavedConnections[0].BindUser != "cn=reader,dc=staging,dc=com" || ldap.SavedConnections[0].BindPass != "reader-secret" {
+		t.Errorf("Saved connections lost in migration: %+v", ldap.SavedConnections)
+	}
+	if cfg.Pagination.PageSize != 200 || cfg.Retry.MaxAttempts != 4 {
+		t.Errorf("Pagination/retry lost in migration: %+v %+v", cfg.Pagination, cfg.Retry)
+	}
+}
+
+func TestConvertLegacyConfigPrefersCurrentNames(t *testing.T) {
+	converted, err := convertLegacyConfig([]byte("bind_dn: cn=old\nldap:\n  bind_user: cn=new\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg Config
+	if err := unmarshalConfig("config.yaml", converted, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LDAP.BindUser != "cn=new" {
+		t.Errorf("Expected the current setting to win, got %q", cfg.LDAP.BindUser)
+	}
+}
+
+func TestMigrateLegacyConfigSkipsWhenNewConfigExists(t *testing.T) {
+	home := isolateConfigDirs(t)
+
+	if err := os.WriteFile(filepath.Join(home, ".ldap-cli.yaml"), []byte(legacyConfigContent), 0644); err != nil {
+		t.Fatalf("Failed to write legacy config: %v", err)
+	}
+	if err := Default().Save(GetDefaultConfigPath()); err != nil {
+		t.Fatalf("Failed to write current config: %v", err)
+	}
+
+	from, to, err := MigrateLegacyConfig()
+	if err != nil || from != "" || to != "" {
+		t.Errorf("Expected no migration when a moribito config exists, got %q -> %q (%v)", from, to, err)
+	}
+}
+
+func TestMigrateLegacyConfigNoConfig(t *testing.T) {
+	isolateConfigDirs(t)
+
+	from, to, err := MigrateLegacyConfig()
+	if err != nil || from != "" || to != "" {
+		t.Errorf("Expected no migration without any config, got %q -> %q (%v)", from, to, err)
+	}
+	if _, err := os.Stat(GetDefaultConfigPath()); err == nil {
+		t.Error("Expected no config file to be created")
+	}
+}
+
+func TestMigrateLegacyConfigInvalidFile(t *testing.T) {
+	home := isolateConfigDirs(t)
+
+	if err := os.WriteFile(filepath.Join(home, ".ldap-cli.yaml"), []byte("ldap: [unclosed"), 0644); err != nil {
+		t.Fatalf("Failed to write legacy config: %v", err)
+	}
+
+	if _, _, err := MigrateLegacyConfig(); err == nil {
+		t.Error("Expected an error for an unparseable legacy config")
+	}
+	if _, err := os.Stat(GetDefaultConfigPath()); err == nil {
+		t.Error("Expected nothing to be written for an unparseable legacy config")
+	}
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mergeYAML writes config into the YAML document existing, changing only the
+// values that differ, so the comments, key order and keys moribito doesn't
+// know about in a hand-maintained file survive a save. ok is false when
+// existing isn't a YAML mapping to merge into.
+func mergeYAML(existing []byte, config *Config) (data []byte, ok bool, err error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(existing, &doc); err != nil {
+		return nil, false, nil
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, false, nil
+	}
+
+	var updated yaml.Node
+	if err := updated.Encode(config); err != nil {
+		return nil, false, err
+	}
+	mergeNode(doc.Content[0], &updated, reflect.TypeOf(*config))
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(yamlIndent(existing))
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, false, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, false, err
+	}
+	return buf.Bytes(), true, nil
+}
+
+// mergeNode updates dst in place to hold the value of src, keeping the
+// comments attached to dst. t is the Go type src was encoded from; for
+// structs, keys of dst that aren't fields are left alone while fields src
+// left out (empty omitempty values) are removed.
+func mergeNode(dst, src *yaml.Node, t reflect.Type) {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface) {
+		t = t.Elem()
+	}
+
+	switch {
+	case dst.Kind == yaml.MappingNode && src.Kind == yaml.MappingNode:
+		mergeMapping(dst, src, t)
+	case dst.Kind == yaml.SequenceNode && src.Kind == yaml.SequenceNode:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		for i, item := range src.Content {
+			if i < len(dst.Content) {
+				mergeNode(dst.Content[i], item, elem)
+			} else {
+				dst.Content = append(dst.Content, item)
+			}
+		}
+		dst.Content = dst.Content[:len(src.Content)]
+	case dst.Kind == yaml.ScalarNode && src.Kind == yaml.ScalarNode:
+		// Unchanged values keep the file's quoting
+		if dst.Value != src.Value || dst.ShortTag() != src.ShortTag() {
+			dst.Value, dst.Tag, dst.Style = src.Value, src.Tag, src.Style
+		}
+	default:
+		head, line, foot := dst.HeadComment, dst.LineComment, dst.FootComment
+		*dst = *src
+		dst.HeadComment, dst.LineComment, dst.FootComment = head, line, foot
+	}
+}
+
+// mergeMapping merges the keys of src into the mapping dst, see mergeNode
+func mergeMapping(dst, src *yaml.Node, t reflect.Type) {
+	fields := yamlFields(t)
+	srcKeys := make(map[string]bool)
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		srcKeys[key.Value] = true
+		var fieldType reflect.Type
+		if fields != nil {
+			fieldType = fields[key.Value]
+		} else if t != nil && t.Kind() == reflect.Map {
+			fieldType = t.Elem()
+		}
+
+		if existing := mappingValue(dst, key.Value); existing != nil {
+			mergeNode(existing, value, fieldType)
+		} else {
+			dst.Content = append(dst.Content, key, value)
+		}
+	}
+
+	// Drop what the config no longer sets, but only keys it knows about and
+	// that don't already spell out the empty value. The comment above a
+	// dropped key often introduces the keys after it, so it moves to the next.
+	content := dst.Content[:0]
+	pending := ""
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key := dst.Content[i]
+		_, known := fields[key.Value]
+		if !srcKeys[key.Value] && (known || fields == nil) && !isEmptyScalar(dst.Content[i+1]) {
+			pending = joinComments(pending, key.HeadComment)
+			continue
+		}
+		key.HeadComment = joinComments(pending, key.HeadComment)
+		pending = ""
+		content = append(content, key, dst.Content[i+1])
+	}
+	if pending != "" && len(content) > 0 {
+		last := content[len(content)-2]
+		last.FootComment = joinComments(last.FootComment, pending)
+	}
+	dst.Content = content
+}
+
+// isEmptyScalar reports whether node is a scalar holding a zero value, which
+// a config left out because its field is omitempty
+func isEmptyScalar(node *yaml.Node) bool {
+	if node.Kind != yaml.ScalarNode {
+		return false
+	}
+	switch node.Value {
+	case "", "0", "false", "null", "~":
+		return true
+	}
+	return false
+}
+
+// joinComments joins two comment blocks, either of which may be empty
+func joinComments(first, second string) string {
+	if first == "" || second == "" {
+		return first + second
+	}
+	return first + "\n" + second
+}
+
+// mappingValue returns the value of key in a mapping node, nil if absent
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlFields maps the YAML keys of a struct's fields to their types, nil
+// when t isn't a struct
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// yamlIndent returns the indentation a YAML file uses, from its first
+// indented key, so a merged save doesn't re-indent the whole file
+func yamlIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		if indent == 0 || trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+		if indent >= 2 && indent <= 8 {
+			return indent
+		}
+		break
+	}
+	return 4
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const handWrittenConfig = `# Work directory, ask the helpdesk before changing
+ldap:
+  host: "ldap.example.com" # primary DC
+  port: 389
+  base_dn: dc=example,dc=com
+  ca_cert_path: /etc/ssl/corp-ca.pem
+  site: emea # not a moribito setting
+pagination:
+  page_size: 100
+retry:
+  enabled: true
+  max_attempts: 3
+  initial_delay_ms: 500
+  max_delay_ms: 5000
+# Shared with the reporting scripts
+reporting:
+  owner: it-ops
+`
+
+func TestSaveKeepsCommentsAndUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(handWrittenConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.LDAP.Port = 636
+	cfg.LDAP.UseSSL = true
+	cfg.LDAP.CACertPath = ""
+	cfg.Display.HideEmpty = true
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := string(data)
+	for _, want := range []string{
+		"# Work directory, ask the helpdesk before changing\n",
+		`host: "ldap.example.com" # primary DC`,
+		"site: emea # not a moribito setting",
+		"# Shared with the reporting scripts\nreporting:\n  owner: it-ops",
+		"  port: 636\n",
+		"  page_size: 100\n",
+	} {
+		if !strings.Contains(saved, want) {
+			t.Errorf("Expected the saved file to contain %q, got:\n%s", want, saved)
+		}
+	}
+	if strings.Contains(saved, "ca_cert_path") {
+		t.Errorf("Expected the cleared CA path to be removed, got:\n%s", saved)
+	}
+	if strings.Contains(saved, "# Moribito Configuration") {
+		t.Error("Expected no header to be added to an existing file")
+	}
+
+	reloaded, _, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.LDAP.Port != 636 || !reloaded.LDAP.UseSSL || reloaded.LDAP.CACertPath != "" || !reloaded.Display.HideEmpty {
+		t.Errorf("Expected the changes to be saved, got %+v", reloaded.LDAP)
+	}
+}
+
+func TestSaveMergesSavedConnections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `ldap:
+    host: localhost
+    port: 389
+    base_dn: dc=example,dc=com
+    saved_connections:
+        # Production, read only account
+        - name: prod
+          host: prod.example.com
+          port: 636
+          base_dn: dc=example,dc=com
+          use_ssl: true
+          use_tls: false
+          bind_user: ""
+          bind_pass: ""
+          notes: keep # unknown to moribito
+        - name: lab
+          host: lab.example.com
+          port: 389
+          base_dn: dc=lab,dc=com
+          use_ssl: false
+          use_tls: false
+          bind_user: ""
+          bind_pass: ""
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.LDAP.SavedConnections[0].Port = 3269
+	cfg.RemoveSavedConnection(1)
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	saved := string(data)
+	for _, want := range []string{"# Production, read only account", "notes: keep # unknown to moribito", "port: 3269"} {
+		if !strings.Contains(saved, want) {
+			t.Errorf("Expected the saved file to contain %q, got:\n%s", want, saved)
+		}
+	}
+	if strings.Contains(saved, "lab.example.com") {
+		t.Errorf("Expected the removed connection to be gone, got:\n%s", saved)
+	}
+}
+
+func TestSaveReplacesUnreadableYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("- not\n- a mapping\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Default()
+	cfg.LDAP.Host = "ldap.example.com"
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, _, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.LDAP.Host != "ldap.example.com" {
+		t.Errorf("Expected the config to be written out in full, got host %q", reloaded.LDAP.Host)
+	}
+}
+
+func TestYAMLIndent(t *testing.T) {
+	tests := []struct {
+		data string
+		want int
+	}{
+		{"ldap:\n  host: x\n", 2},
+		{"# comment\n\nldap:\n    host: x\n", 4},
+		{"list:\n- a\nldap:\n   host: x\n", 3},
+		{"host: x\n", 4},
+	}
+	for _, tt := range tests {
+		if got := yamlIndent([]byte(tt.data)); got != tt.want {
+			t.Errorf("yamlIndent(%q) = %d, want %d", tt.data, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// EntryTemplate is a named blueprint for creating similar entries. The RDN and
+// attribute values may contain ${placeholder} references that are filled in
+// when an entry is created from the template.
+type EntryTemplate struct {
+	Name          string              `yaml:"name" toml:"name"`
+	RDN           string              `yaml:"rdn" toml:"rdn"` // e.g. "uid=${uid}", joined with the parent DN
+	ObjectClasses []string            `yaml:"object_classes" toml:"object_classes"`
+	Attributes    map[string][]string `yaml:"attributes,omitempty" toml:"attributes,omitempty"`
+}
+
+// placeholderPattern matches ${name} references in template values
+var placeholderPattern = regexp.MustCompile(`\$\{([A-Za-z0-9_.-]+)\}`)
+
+// Placeholders returns the unique placeholder names used by the template, in the
+// order they first appear: the RDN first, then attributes sorted by name
+func (t EntryTemplate) Placeholders() []string {
+	var names []string
+	seen := make(map[string]bool)
+	collect := func(s string) {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+	}
+
+	collect(t.RDN)
+	for _, attr := range t.sortedAttributeNames() {
+		for _, value := range t.Attributes[attr] {
+			collect(value)
+		}
+	}
+
+	return names
+}
+
+// Render substitutes the placeholder values and returns the DN and attributes of
+// the entry to create under parentDN. Every placeholder must have a non-empty value.
+// The objectClass attribute and the RDN's naming value are always included.
+// escapeDNValue escapes the values in the DN, such as ldap.EscapeDNValue.
+func (t EntryTemplate) Render(parentDN string, values map[string]string, escapeDNValue func(string) string) (string, map[string][]string, error) {
+	if strings.TrimSpace(t.RDN) == "" {
+		return "", nil, fmt.Errorf("template %q has no rdn", t.Name)
+	}
+	if len(t.ObjectClasses) == 0 {
+		return "", nil, fmt.Errorf("template %q has no object classes", t.Name)
+	}
+
+	for _, name := range t.Placeholders() {
+		if strings.TrimSpace(values[name]) == "" {
+			return "", nil, fmt.Errorf("missing value for ${%s}", name)
+		}
+	}
+
+	// The values are escaped in the DN and added to the attributes as they
+	// were entered
+	rdn := SubstitutePlaceholders(t.RDN, values)
+	dn := substitutePlaceholders(t.RDN, values, escapeDNValue)
+	if parentDN != "" {
+		dn += "," + parentDN
+	}
+
+	attributes := map[string][]string{
+		"objectClass": append([]string(nil), t.ObjectClasses...),
+	}
+	for _, attr := range t.sortedAttributeNames() {
+		rendered := make([]string, 0, len(t.Attributes[attr]))
+		for _, value := range t.Attributes[attr] {
+			rendered = append(rendered, SubstitutePlaceholders(value, values))
+		}
+		attributes[attr] = rendered
+	}
+
+	// Servers reject entries whose naming attribute isn't among its values
+	if attr, value, ok := strings.Cut(rdn, "="); ok {
+		attr, value = strings.TrimSpace(attr), strings.TrimSpace(value)
+		for name := range attributes {
+			if strings.EqualFold(name, attr) {
+				attr = name
+				break
+			}
+		}
+		if !containsFold(attributes[attr], value) {
+			attributes[attr] = append(attributes[attr], value)
+		}
+	}
+
+	return dn, attributes, nil
+}
+
+// SubstitutePlaceholders replaces ${name} references in s with their values.
+// References without a value are left as-is.
+func SubstitutePlaceholders(s string, values map[string]string) string {
+	return substitutePlaceholders(s, values, func(value string) string { return value })
+}
+
+// substitutePlaceholders replaces ${name} references in s with their values
+// passed through escape
+func substitutePlaceholders(s string, values map[string]string, escape func(string) string) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if value, ok := values[name]; ok && value != "" {
+			return escape(value)
+		}
+		return match
+	})
+}
+
+// sortedAttributeNames returns the template's attribute names in a stable order
+func (t EntryTemplate) sortedAttributeNames() []string {
+	names := make([]string, 0, len(t.Attributes))
+	for name := range t.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func userTemplate() EntryTemplate {
+	return EntryTemplate{
+		Name:          "Standard user",
+		RDN:           "uid=${uid}",
+		ObjectClasses: []string{"inetOrgPerson", "posixAccount"},
+		Attributes: map[string][]string{
+			"cn":            {"${first} ${last}"},
+			"sn":            {"${last}"},
+			"mail":          {"${uid}@example.com"},
+			"homeDirectory": {"/home/${uid}"},
+			"loginShell":    {"/bin/bash"},
+		},
+	}
+}
+
+func TestEntryTemplatePlaceholders(t *testing.T) {
+	got := userTemplate().Placeholders()
+	// RDN first, then attributes in name order: cn, homeDirectory, loginShell, mail, sn
+	want := []string{"uid", "first", "last"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected placeholders %v, got %v", want, got)
+	}
+}
+
+func TestEntryTemplateRender(t *testing.T) {
+	dn, attrs, err := userTemplate().Render("ou=people,dc=example,dc=com", map[string]string{
+		"uid":   "jdoe",
+		"first": "Jane",
+		"last":  "Doe",
+	}, ldap.EscapeDNValue)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if dn != "uid=jdoe,ou=people,dc=example,dc=com" {
+		t.Errorf("Unexpected DN: %s", dn)
+	}
+
+	want := map[string][]string{
+		"objectClass":   {"inetOrgPerson", "posixAccount"},
+		"cn":            {"Jane Doe"},
+		"sn":            {"Doe"},
+		"mail":          {"jdoe@example.com"},
+		"homeDirectory": {"/home/jdoe"},
+		"loginShell":    {"/bin/bash"},
+		"uid":           {"jdoe"}, // added from the RDN
+	}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Errorf("Unexpected attributes:\n got %v\nwant %v", attrs, want)
+	}
+}
+
+func TestEntryTemplateRenderKeepsExistingNamingValue(t *testing.T) {
+	tmpl := EntryTemplate{
+		Name:          "Group",
+		RDN:           "CN=${name}",
+		ObjectClasses: []string{"groupOfNames"},
+		Attributes:    map[string][]string{"cn": {"${name}"}},
+	}
+
+	_, attrs, err := tmpl.Render("ou=groups,dc=example,dc=com", map[string]string{"name": "admins"}, ldap.EscapeDNValue)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if !reflect.DeepEqual(attrs["cn"], []string{"admins"}) {
+		t.Errorf("Expected naming value not to be duplicated, got %v", attrs["cn"])
+	}
+	if _, ok := attrs["CN"]; ok {
+		t.Error("Expected naming attribute to match the existing attribute case-insensitively")
+	}
+}
+
+func TestEntryTemplateRenderEscapesDNValues(t *testing.T) {
+	tmpl := EntryTemplate{Name: "Contact", RDN: "cn=${name}", ObjectClasses: []string{"person"}}
+	dn, attrs, err := tmpl.Render("ou=people,dc=example,dc=com", map[string]string{"name": "Doe, Jane"}, ldap.EscapeDNValue)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	// The comma stays in the value instead of starting a new RDN
+	if want := `cn=Doe\, Jane,ou=people,dc=example,dc=com`; dn != want {
+		t.Errorf("Expected DN %s, got %s", want, dn)
+	}
+	if !reflect.DeepEqual(attrs["cn"], []string{"Doe, Jane"}) {
+		t.Errorf("Expected the naming value as entered, got %v", attrs["cn"])
+	}
+
+	// Only the values are escaped, so the template's own + still joins a
+	// multi-valued RDN
+	tmpl.RDN = "cn=${name}+sn=${last}"
+	dn, _, err = tmpl.Render("ou=people,dc=example,dc=com", map[string]string{"name": "#1", "last": "Doe, Jr."}, ldap.EscapeDNValue)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if want := `cn=\#1+sn=Doe\, Jr.,ou=people,dc=example,dc=com`; dn != want {
+		t.Errorf("Expected DN %s, got %s", want, dn)
+	}
+}
+
+func TestEntryTemplateRenderMissingValue(t *testing.T) {
+	_, _, err := userTemplate().Render("ou=people,dc=example,dc=com", map[string]string{
+		"uid":   "jdoe",
+		"first": "Jane",
+		"last":  "  ",
+	}, ldap.EscapeDNValue)
+	if err == nil || err.Error() != "missing value for ${last}" {
+		t.Errorf("Expected missing value error for last, got %v", err)
+	}
+}
+
+func TestEntryTemplateRenderInvalidTemplate(t *testing.T) {
+	if _, _, err := (EntryTemplate{Name: "x", ObjectClasses: []string{"top"}}).Render("", nil, ldap.EscapeDNValue); err == nil {
+		t.Error("Expected error for template without rdn")
+	}
+	if _, _, err := (EntryTemplate{Name: "x", RDN: "cn=x"}).Render("", nil, ldap.EscapeDNValue); err == nil {
+		t.Error("Expected error for template without object classes")
+	}
+}
+
+func TestSubstitutePlaceholders(t *testing.T) {
+	values := map[string]string{"uid": "jdoe", "empty": ""}
+
+	tests := []struct {
+		input, want string
+	}{
+		{"${uid}", "jdoe"},
+		{"/home/${uid}/${uid}", "/home/jdoe/jdoe"},
+		{"${missing}", "${missing}"},
+		{"${empty}", "${empty}"},
+		{"no placeholders", "no placeholders"},
+		{"$uid {uid}", "$uid {uid}"},
+	}
+
+	for _, tt := range tests {
+		if got := SubstitutePlaceholders(tt.input, values); got != tt.want {
+			t.Errorf("SubstitutePlaceholders(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestConfigLoadEntryTemplates(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `ldap:
+  host: test.example.com
+  base_dn: dc=example,dc=com
+entry_templates:
+  - name: Standard user
+    rdn: uid=${uid}
+    object_classes: [inetOrgPerson]
+    attributes:
+      cn: ["${first} ${last}"]
+      sn: ["${last}"]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	cfg, _, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if len(cfg.EntryTemplates) != 1 {
+		t.Fatalf("Expected 1 entry template, got %d", len(cfg.EntryTemplates))
+	}
+	tmpl := cfg.EntryTemplates[0]
+	if tmpl.Name != "Standard user" || tmpl.RDN != "uid=${uid}" {
+		t.Errorf("Unexpected template: %+v", tmpl)
+	}
+	if !reflect.DeepEqual(tmpl.Placeholders(), []string{"uid", "first", "last"}) {
+		t.Errorf("Unexpected placeholders: %v", tmpl.Placeholders())
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Validate checks the connection settings before connecting and returns every
+// problem found, so they can all be fixed in one pass
+func (c LDAPConnection) Validate() []string {
+	var problems []string
+
+	if strings.TrimSpace(c.Host) == "" {
+		problems = append(problems, "LDAP host is required")
+	}
+
+	if c.Port < 1 || c.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("Port %d is out of range (1-65535)", c.Port))
+	}
+
+	if strings.TrimSpace(c.BaseDN) == "" {
+		problems = append(problems, "Base DN is required")
+	} else if _, err := ldap.ParseDN(c.BaseDN); err != nil {
+		problems = append(problems, fmt.Sprintf("Base DN %q is not a valid DN", c.BaseDN))
+	}
+
+	if c.UseSSL && c.UseTLS {
+		problems = append(problems, "Use SSL and Use TLS can't both be enabled: StartTLS can't run on an SSL connection")
+	}
+
+	if c.CACertPath != "" {
+		if file, err := os.Open(c.CACertPath); err != nil {
+			problems = append(problems, fmt.Sprintf("CA certificate %s can't be read: %v", c.CACertPath, err))
+		} else {
+			file.Close()
+		}
+	}
+
+	switch strings.ToLower(c.BindMethod) {
+	case "", "simple":
+		if c.BindUser != "" && !isBindIdentity(c.BindUser) {
+			problems = append(problems, fmt.Sprintf("Bind user %q should be a DN (cn=admin,dc=example,dc=com) or a UPN (admin@example.com)", c.BindUser))
+		}
+	case "gssapi":
+		// Kerberos principals are plain user names, so only the keytab is checked
+		if c.KerberosKeytab != "" {
+			if c.BindUser == "" {
+				problems = append(problems, "Bind user is required with a Kerberos keytab: it names the principal to authenticate as")
+			}
+			if file, err := os.Open(c.KerberosKeytab); err != nil {
+				problems = append(problems, fmt.Sprintf("Kerberos keytab %s can't be read: %v", c.KerberosKeytab, err))
+			} else {
+				file.Close()
+			}
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("Bind method %q is not supported (use simple or gssapi)", c.BindMethod))
+	}
+
+	return problems
+}
+
+// PlaintextBind reports whether connecting sends the bind password in clear
+// text: a simple bind with credentials over a connection without SSL or
+// StartTLS. Anonymous binds have no secret to leak, and Kerberos never sends
+// the password to the server.
+func (c LDAPConnection) PlaintextBind() bool {
+	if c.UseSSL || c.UseTLS || c.BindUser == "" || c.BindPass == "" {
+		return false
+	}
+	switch strings.ToLower(c.BindMethod) {
+	case "", "simple":
+		return true
+	default:
+		return false
+	}
+}
+
+// Complete reports whether the connection can be connected to as it is,
+// without the user filling anything in: its settings are valid and a simple
+// bind as a user has a password. Anonymous and Kerberos binds need none.
+func (c LDAPConnection) Complete() bool {
+	if len(c.Validate()) > 0 {
+		return false
+	}
+	switch strings.ToLower(c.BindMethod) {
+	case "", "simple":
+		return c.BindUser == "" || c.BindPass != ""
+	default:
+		return true
+	}
+}
+
+// isBindIdentity reports whether user looks like a DN, a user principal name
+// or an Active Directory down-level logon name (DOMAIN\user)
+func isBindIdentity(user string) bool {
+	if strings.Contains(user, "=") {
+		_, err := ldap.ParseDN(user)
+		return err == nil
+	}
+
+	if name, domain, ok := strings.Cut(user, "@"); ok {
+		return name != "" && domain != "" && !strings.ContainsAny(domain, "@ ")
+	}
+
+	if domain, name, ok := strings.Cut(user, `\`); ok {
+		return domain != "" && name != "" && !strings.Contains(name, `\`)
+	}
+
+	return false
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func validConnection() LDAPConnection {
+	return LDAPConnection{
+		Host:     "ldap.example.com",
+		Port:     389,
+		BaseDN:   "dc=example,dc=com",
+		UseTLS:   true,
+		BindUser: "cn=admin,dc=example,dc=com",
+	}
+}
+
+func TestLDAPConnectionValidate(t *testing.T) {
+	if problems := validConnection().Validate(); len(problems) != 0 {
+		t.Errorf("Expected a valid connection, got %v", problems)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *LDAPConnection)
+		want   string
+	}{
+		{"missing host", func(c *LDAPConnection) { c.Host = " " }, "host is required"},
+		{"port zero", func(c *LDAPConnection) { c.Port = 0 }, "Port 0 is out of range"},
+		{"port too large", func(c *LDAPConnection) { c.Port = 70000 }, "Port 70000 is out of range"},
+		{"missing base DN", func(c *LDAPConnection) { c.BaseDN = "" }, "Base DN is required"},
+		{"invalid base DN", func(c *LDAPConnection) { c.BaseDN = "example.com" }, "not a valid DN"},
+		{"ssl and tls", func(c *LDAPConnection) { c.UseSSL = true }, "can't both be enabled"},
+		{"bare bind user", func(c *LDAPConnection) { c.BindUser = "admin" }, "should be a DN"},
+		{"invalid bind DN", func(c *LDAPConnection) { c.BindUser = "cn=admin,dc" }, "should be a DN"},
+		{"missing CA file", func(c *LDAPConnection) { c.CACertPath = "/nonexistent/ca.pem" }, "CA certificate /nonexistent/ca.pem can't be read"},
+		{"unknown bind method", func(c *LDAPConnection) { c.BindMethod = "ntlm" }, `Bind method "ntlm" is not supported`},
+		{"keytab without user", func(c *LDAPConnection) {
+			c.BindMethod, c.BindUser, c.KerberosKeytab = "gssapi", "", "validate_test.go" // Any readable file
+		}, "Bind user is required with a Kerberos keytab"},
+		{"missing keytab", func(c *LDAPConnection) {
+			c.BindMethod, c.KerberosKeytab = "gssapi", "/nonexistent/ldap.keytab"
+		}, "Kerberos keytab /nonexistent/ldap.keytab can't be read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := validConnection()
+			tt.modify(&conn)
+			problems := conn.Validate()
+			if len(problems) != 1 || !strings.Contains(problems[0], tt.want) {
+				t.Errorf("Expected one problem containing %q, got %v", tt.want, problems)
+			}
+		})
+	}
+}
+
+func TestLDAPConnectionValidate_GSSAPIPrincipal(t *testing.T) {
+	// Kerberos principals don't need to be DNs or UPNs
+	conn := validConnection()
+	conn.BindMethod = "gssapi"
+	conn.BindUser = "svc-moribito"
+	if problems := conn.Validate(); len(problems) != 0 {
+		t.Errorf("Expected a plain principal name to be accepted, got %v", problems)
+	}
+}
+
+func TestLDAPConnectionValidate_ReportsAllProblems(t *testing.T) {
+	conn := LDAPConnection{Port: -1, UseSSL: true, UseTLS: true, BindUser: "admin"}
+	if problems := conn.Validate(); len(problems) != 5 {
+		t.Errorf("Expected 5 problems, got %v", problems)
+	}
+}
+
+func TestIsBindIdentity(t *testing.T) {
+	tests := map[string]bool{
+		"cn=admin,dc=example,dc=com": true,
+		"uid=jdoe,ou=people,o=corp":  true,
+		"admin@example.com":          true,
+		`EXAMPLE\admin`:              true,
+		"admin":                      false,
+		"@example.com":               false,
+		"admin@":                     false,
+		`EXAMPLE\`:                   false,
+	}
+	for user, want := range tests {
+		if got := isBindIdentity(user); got != want {
+			t.Errorf("isBindIdentity(%q) = %v, want %v", user, got, want)
+		}
+	}
+}
+
+func TestLDAPConnectionComplete(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *LDAPConnection)
+		want   bool
+	}{
+		{"user and password", func(c *LDAPConnection) {}, true},
+		{"anonymous", func(c *LDAPConnection) { c.BindUser, c.BindPass = "", "" }, true},
+		{"user without password", func(c *LDAPConnection) { c.BindPass = "" }, false},
+		{"kerberos without password", func(c *LDAPConnection) { c.BindMethod, c.BindPass = "gssapi", "" }, true},
+		{"no host", func(c *LDAPConnection) { c.Host = "" }, false},
+		{"no base DN", func(c *LDAPConnection) { c.BaseDN = "" }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := validConnection()
+			conn.BindPass = "secret"
+			tt.modify(&conn)
+			if got := conn.Complete(); got != tt.want {
+				t.Errorf("Expected Complete() = %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLDAPConnectionPlaintextBind(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *LDAPConnection)
+		want   bool
+	}{
+		{"credentials without encryption", func(c *LDAPConnection) { c.UseTLS = false }, true},
+		{"explicit simple bind", func(c *LDAPConnection) { c.UseTLS, c.BindMethod = false, "Simple" }, true},
+		{"StartTLS", func(c *LDAPConnection) {}, false},
+		{"SSL", func(c *LDAPConnection) { c.UseTLS, c.UseSSL = false, true }, false},
+		{"anonymous", func(c *LDAPConnection) { c.UseTLS, c.BindUser, c.BindPass = false, "", "" }, false},
+		{"user without password", func(c *LDAPConnection) { c.UseTLS, c.BindPass = false, "" }, false},
+		{"kerberos", func(c *LDAPConnection) { c.UseTLS, c.BindMethod = false, "gssapi" }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := validConnection()
+			conn.BindPass = "secret"
+			tt.modify(&conn)
+			if got := conn.PlaintextBind(); got != tt.want {
+				t.Errorf("Expected PlaintextBind() = %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
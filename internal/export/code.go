@@ -0,0 +1,121 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// Languages entries can be rendered as source code in
+const (
+	LanguageGo     = "go"
+	LanguagePython = "python"
+)
+
+// EntryCode renders entry as a literal in language, for pasting into tests
+// and scripts. An empty language is Go.
+func EntryCode(entry *ldap.Entry, language string) (string, error) {
+	switch strings.ToLower(language) {
+	case LanguageGo, "":
+		return GoMap(entry), nil
+	case LanguagePython:
+		return PythonDict(entry), nil
+	default:
+		return "", fmt.Errorf("unknown code language %q (use go or python)", language)
+	}
+}
+
+// GoMap renders entry as a map[string][]string literal, headed by a comment
+// with its DN. Values that aren't valid UTF-8 are written with \x escapes so
+// the bytes are kept exactly.
+func GoMap(entry *ldap.Entry) string {
+	var b strings.Builder
+	b.WriteString("// " + commentSafe(entry.DN) + "\n")
+	b.WriteString("map[string][]string{\n")
+	for _, name := range attributeNames(entry, nil) {
+		quoted := make([]string, len(entry.Attributes[name]))
+		for i, value := range entry.Attributes[name] {
+			quoted[i] = strconv.Quote(value)
+		}
+		fmt.Fprintf(&b, "\t%s: {%s},\n", strconv.Quote(name), strings.Join(quoted, ", "))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// PythonDict renders entry as a dict of lists, headed by a comment with its
+// DN. Values that aren't valid UTF-8, such as objectGUID, become bytes
+// literals since a str can't hold them.
+func PythonDict(entry *ldap.Entry) string {
+	var b strings.Builder
+	b.WriteString("# " + commentSafe(entry.DN) + "\n")
+	b.WriteString("{\n")
+	for _, name := range attributeNames(entry, nil) {
+		quoted := make([]string, len(entry.Attributes[name]))
+		for i, value := range entry.Attributes[name] {
+			quoted[i] = pythonQuote(value)
+		}
+		fmt.Fprintf(&b, "    %s: [%s],\n", pythonQuote(name), strings.Join(quoted, ", "))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// commentSafe keeps a DN on its comment line
+func commentSafe(dn string) string {
+	return strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(dn)
+}
+
+// pythonQuote returns value as a double-quoted Python str literal, or as a
+// bytes literal when it isn't valid UTF-8
+func pythonQuote(value string) string {
+	binary := !utf8.ValidString(value)
+
+	var b strings.Builder
+	if binary {
+		b.WriteString("b")
+	}
+	b.WriteString(`"`)
+	if binary {
+		for i := 0; i < len(value); i++ {
+			c := value[i]
+			switch {
+			case c == '"' || c == '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case c >= 0x20 && c < 0x7f:
+				b.WriteByte(c)
+			default:
+				fmt.Fprintf(&b, `\x%02x`, c)
+			}
+		}
+	} else {
+		for _, r := range value {
+			switch {
+			case r == '"' || r == '\\':
+				b.WriteRune('\\')
+				b.WriteRune(r)
+			case r == '\n':
+				b.WriteString(`\n`)
+			case r == '\r':
+				b.WriteString(`\r`)
+			case r == '\t':
+				b.WriteString(`\t`)
+			case unicode.IsPrint(r):
+				b.WriteRune(r)
+			case r < 0x100:
+				fmt.Fprintf(&b, `\x%02x`, r)
+			case r < 0x10000:
+				fmt.Fprintf(&b, `\u%04x`, r)
+			default:
+				fmt.Fprintf(&b, `\U%08x`, r)
+			}
+		}
+	}
+	b.WriteString(`"`)
+	return b.String()
+}
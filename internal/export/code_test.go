@@ -0,0 +1,87 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func codeEntry() *ldap.Entry {
+	return &ldap.Entry{
+		DN: "cn=Smith\\, Alice,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"cn":          {"Smith, Alice"},
+			"description": {`Says "hi" \o/`, "line one\nline two\ttabbed"},
+			"objectGUID":  {"\x9c\x1b\xff\x00"},
+			"sn":          {"Müller"},
+		},
+		Order: []string{"cn", "sn", "description", "objectGUID"},
+	}
+}
+
+func TestGoMap(t *testing.T) {
+	got := GoMap(codeEntry())
+	want := `// cn=Smith\, Alice,ou=people,dc=example,dc=com
+map[string][]string{
+	"cn": {"Smith, Alice"},
+	"sn": {"Müller"},
+	"description": {"Says \"hi\" \\o/", "line one\nline two\ttabbed"},
+	"objectGUID": {"\x9c\x1b\xff\x00"},
+}`
+	if got != want {
+		t.Errorf("Unexpected Go code:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPythonDict(t *testing.T) {
+	got := PythonDict(codeEntry())
+	want := `# cn=Smith\, Alice,ou=people,dc=example,dc=com
+{
+    "cn": ["Smith, Alice"],
+    "sn": ["Müller"],
+    "description": ["Says \"hi\" \\o/", "line one\nline two\ttabbed"],
+    "objectGUID": [b"\x9c\x1b\xff\x00"],
+}`
+	if got != want {
+		t.Errorf("Unexpected Python code:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPythonQuote(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", `"plain"`},
+		{"bell\a", `"bell\x07"`},
+		{"zero\u200bwidth", `"zero\u200bwidth"`},
+		{"b\"in\\\xfe", `b"b\"in\\\xfe"`},
+		{"", `""`},
+	}
+	for _, tt := range tests {
+		if got := pythonQuote(tt.value); got != tt.want {
+			t.Errorf("pythonQuote(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEntryCode(t *testing.T) {
+	entry := codeEntry()
+	if got, err := EntryCode(entry, ""); err != nil || got != GoMap(entry) {
+		t.Errorf("Expected Go by default, got %q, %v", got, err)
+	}
+	if got, err := EntryCode(entry, "Python"); err != nil || got != PythonDict(entry) {
+		t.Errorf("Expected Python, got %q, %v", got, err)
+	}
+	if _, err := EntryCode(entry, "ruby"); err == nil {
+		t.Error("Expected an error for an unknown language")
+	}
+}
+
+func TestGoMap_DNOnOneLine(t *testing.T) {
+	entry := &ldap.Entry{DN: "cn=a\nb,dc=example,dc=com", Attributes: map[string][]string{}}
+	want := "// cn=a\\nb,dc=example,dc=com\nmap[string][]string{\n}"
+	if got := GoMap(entry); got != want {
+		t.Errorf("Expected the DN kept on the comment line, got:\n%s", got)
+	}
+}
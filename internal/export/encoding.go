@@ -0,0 +1,126 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Output encodings
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF8BOM = "utf-8-bom" // UTF-8 led by a byte order mark, which Excel needs to detect it
+	EncodingLatin1  = "latin-1"   // ISO 8859-1
+)
+
+// utf8BOM is the UTF-8 encoding of the byte order mark
+const utf8BOM = "\ufeff"
+
+// encodingAliases maps the spellings accepted for each encoding to its name
+var encodingAliases = map[string]string{
+	"":           EncodingUTF8,
+	"utf-8":      EncodingUTF8,
+	"utf8":       EncodingUTF8,
+	"utf-8-bom":  EncodingUTF8BOM,
+	"utf8-bom":   EncodingUTF8BOM,
+	"latin-1":    EncodingLatin1,
+	"latin1":     EncodingLatin1,
+	"iso-8859-1": EncodingLatin1,
+}
+
+// CheckEncoding reports whether output in format can be written in encoding.
+// An empty encoding is UTF-8. JSON is always UTF-8 without a byte order mark,
+// as RFC 8259 requires.
+func CheckEncoding(format, encoding string) error {
+	name, err := encodingName(encoding)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(format, FormatJSON) && name != EncodingUTF8 {
+		return fmt.Errorf("json output is always utf-8")
+	}
+	return nil
+}
+
+// encodingName returns the name of encoding, one of the Encoding* constants
+func encodingName(encoding string) (string, error) {
+	name, ok := encodingAliases[strings.ToLower(strings.TrimSpace(encoding))]
+	if !ok {
+		return "", fmt.Errorf("unknown output encoding %q (use utf-8, utf-8-bom or latin-1)", encoding)
+	}
+	return name, nil
+}
+
+// NewEncoder returns a writer that writes the UTF-8 text it is given to w in
+// encoding, for the output of a Writer. UTF-8 passes through unchanged. LDIF
+// base64-encodes values that aren't plain ASCII, so the encoding only changes
+// its comments; CSV cells are written in it as they are. Text Latin-1 can't
+// hold is an error rather than being replaced. Close reports a character left
+// incomplete and doesn't close w.
+func NewEncoder(w io.Writer, encoding string) (io.WriteCloser, error) {
+	name, err := encodingName(encoding)
+	if err != nil {
+		return nil, err
+	}
+	return &encoder{w: w, encoding: name}, nil
+}
+
+// encoder converts text to its encoding as it is written
+type encoder struct {
+	w        io.Writer
+	encoding string
+	started  bool
+	pending  []byte // Start of a character split across writes
+}
+
+// Write writes p in the encoding, reporting all of p as written once its
+// whole characters are
+func (e *encoder) Write(p []byte) (int, error) {
+	var out []byte
+	if !e.started {
+		e.started = true
+		if e.encoding == EncodingUTF8BOM {
+			out = append(out, utf8BOM...)
+		}
+	}
+	if e.encoding != EncodingLatin1 {
+		out = append(out, p...)
+	} else {
+		text := append(e.pending, p...)
+		e.pending = nil
+		for len(text) > 0 {
+			r, size := utf8.DecodeRune(text)
+			if r == utf8.RuneError && size <= 1 {
+				if !utf8.FullRune(text) {
+					// Completed by the next write
+					e.pending = append([]byte(nil), text...)
+					break
+				}
+				return 0, fmt.Errorf("output holds bytes that aren't UTF-8 text and can't be written in latin-1")
+			}
+			if r > 0xff {
+				return 0, fmt.Errorf("%q can't be written in latin-1", r)
+			}
+			out = append(out, byte(r))
+			text = text[size:]
+		}
+	}
+	if _, err := e.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes the byte order mark of empty output and reports a character
+// left incomplete
+func (e *encoder) Close() error {
+	if len(e.pending) > 0 {
+		return fmt.Errorf("output ends in an incomplete UTF-8 character")
+	}
+	if !e.started {
+		_, err := e.Write(nil)
+		return err
+	}
+	return nil
+}
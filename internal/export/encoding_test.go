@@ -0,0 +1,146 @@
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// encodedEntries are entries with accented values, which Latin-1 can hold
+func encodedEntries() []*ldap.Entry {
+	return []*ldap.Entry{
+		{DN: "uid=jose,ou=people,dc=example,dc=com", Attributes: map[string][]string{"cn": {"José Müller"}, "l": {"Zürich"}}},
+		{DN: "uid=bob,ou=people,dc=example,dc=com", Attributes: map[string][]string{"cn": {"Bob"}}},
+	}
+}
+
+// writeEncoded writes entries in format and encoding
+func writeEncoded(t *testing.T, format, encoding string, attributes []string, entries []*ldap.Entry) ([]byte, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, encoding)
+	if err != nil {
+		t.Fatalf("NewEncoder failed: %v", err)
+	}
+	w, err := NewWriter(format, enc, attributes, "filter: (cn=José*)")
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	for _, entry := range entries {
+		if err := w.Write(entry); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+// decodeLatin1 reads Latin-1 bytes back as a string
+func decodeLatin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+func TestEncoderBOM(t *testing.T) {
+	data, err := writeEncoded(t, FormatCSV, EncodingUTF8BOM, []string{"cn"}, encodedEntries())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte{0xef, 0xbb, 0xbf}) || bytes.Count(data, []byte{0xef, 0xbb, 0xbf}) != 1 {
+		t.Fatalf("Expected a single leading byte order mark, got %q", data)
+	}
+	if !strings.Contains(string(data[3:]), "José Müller") {
+		t.Errorf("Expected the rest to be UTF-8, got %q", data)
+	}
+
+	// Plain UTF-8 has none, and empty output still gets one when asked
+	if data, _ := writeEncoded(t, FormatCSV, EncodingUTF8, []string{"cn"}, encodedEntries()); bytes.HasPrefix(data, []byte{0xef}) {
+		t.Errorf("Expected no byte order mark in plain UTF-8, got %q", data)
+	}
+	var buf bytes.Buffer
+	enc, _ := NewEncoder(&buf, "UTF8-BOM")
+	if err := enc.Close(); err != nil || buf.String() != utf8BOM {
+		t.Errorf("Expected empty output to hold the byte order mark, got %q (%v)", buf.String(), err)
+	}
+}
+
+func TestEncoderLatin1RoundTrip(t *testing.T) {
+	data, err := writeEncoded(t, FormatCSV, "latin1", []string{"cn", "l"}, encodedEntries())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bytes.Contains(data, []byte("é")) || !bytes.Contains(data, []byte{'J', 'o', 's', 0xe9}) {
+		t.Fatalf("Expected one byte per accented character, got %q", data)
+	}
+	records, err := csv.NewReader(strings.NewReader(decodeLatin1(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read the CSV back: %v", err)
+	}
+	if records[1][1] != "José Müller" || records[1][2] != "Zürich" {
+		t.Errorf("Expected the values back unchanged, got %v", records[1])
+	}
+
+	// LDIF keeps values as base64 of their UTF-8, so only the comment changes
+	data, err = writeEncoded(t, FormatLDIF, EncodingLatin1, nil, encodedEntries()[:1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	text := decodeLatin1(data)
+	if !strings.Contains(text, "# filter: (cn=José*)") {
+		t.Errorf("Expected the comment in Latin-1, got %q", text)
+	}
+	encoded := "cn:: " + base64.StdEncoding.EncodeToString([]byte("José Müller"))
+	if !strings.Contains(text, encoded) {
+		t.Errorf("Expected %q, got %q", encoded, text)
+	}
+}
+
+func TestEncoderLatin1Errors(t *testing.T) {
+	entries := []*ldap.Entry{{DN: "uid=chen,dc=example,dc=com", Attributes: map[string][]string{"cn": {"陈"}}}}
+	if _, err := writeEncoded(t, FormatCSV, EncodingLatin1, []string{"cn"}, entries); err == nil || !strings.Contains(err.Error(), "can't be written in latin-1") {
+		t.Errorf("Expected characters outside Latin-1 to be refused, got %v", err)
+	}
+
+	// A character split across writes is joined up; one left incomplete is reported
+	var buf bytes.Buffer
+	enc, _ := NewEncoder(&buf, EncodingLatin1)
+	enc.Write([]byte{'J', 'o', 's', 0xc3})
+	enc.Write([]byte{0xa9})
+	if err := enc.Close(); err != nil || buf.String() != "Jos\xe9" {
+		t.Errorf("Expected the split character encoded, got %q (%v)", buf.String(), err)
+	}
+	enc, _ = NewEncoder(&buf, EncodingLatin1)
+	enc.Write([]byte{0xc3})
+	if err := enc.Close(); err == nil {
+		t.Error("Expected an incomplete character to be reported")
+	}
+}
+
+func TestCheckEncoding(t *testing.T) {
+	tests := []struct {
+		format, encoding string
+		wantErr          string
+	}{
+		{FormatLDIF, "", ""},
+		{FormatCSV, "ISO-8859-1", ""},
+		{FormatLDIF, "utf-8-bom", ""},
+		{FormatJSON, "utf8", ""},
+		{FormatJSON, "latin-1", "json output is always utf-8"},
+		{FormatCSV, "utf-16", "unknown output encoding"},
+	}
+	for _, tt := range tests {
+		err := CheckEncoding(tt.format, tt.encoding)
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("CheckEncoding(%q, %q): expected %q, got %v", tt.format, tt.encoding, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+// Package export writes LDAP entries as LDIF, CSV or JSON, one entry at a
+// time so search results can be streamed to a file.
+package export
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// Output formats
+const (
+	FormatLDIF = "ldif"
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// csvValueSeparator joins the values of a multi-valued attribute in a CSV cell
+const csvValueSeparator = "; "
+
+// ldifLineWidth is the width LDIF lines are folded at, per RFC 2849
+const ldifLineWidth = 76
+
+// Writer writes entries in one of the export formats
+type Writer interface {
+	// Write writes one entry
+	Write(entry *ldap.Entry) error
+	// Close finishes the output, without closing the underlying writer
+	Close() error
+}
+
+// NewWriter returns a Writer for format. CSV needs the attribute names up
+// front since they make the header row; LDIF and JSON write whatever
+// attributes each entry has and attributes only orders them. Comment lines,
+// such as how the entries were searched for, head LDIF output; CSV and JSON
+// have no comment syntax and leave them out, see NewAnnotatedWriter.
+func NewWriter(format string, w io.Writer, attributes []string, comment ...string) (Writer, error) {
+	if err := CheckFormat(format, attributes); err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w), attributes: attributes}, nil
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	default:
+		return &ldifWriter{w: w, attributes: attributes, comment: comment}, nil
+	}
+}
+
+// NewAnnotatedWriter returns a Writer for format that records the search the
+// entries came from in every format. LDIF has it as a comment as NewWriter
+// writes it. CSV starts with a "# " line before the header row, which many
+// readers can be told to skip as a comment. JSON becomes an object with the
+// search and an entries array, instead of the bare array.
+func NewAnnotatedWriter(format string, w io.Writer, attributes []string, search ldap.SearchParams) (Writer, error) {
+	writer, err := NewWriter(format, w, attributes, search.String())
+	if err != nil {
+		return nil, err
+	}
+	switch writer := writer.(type) {
+	case *csvWriter:
+		writer.comment = "# " + search.String() + "\n"
+		writer.raw = w
+	case *jsonWriter:
+		attributes := search.Attributes
+		if len(attributes) == 0 {
+			attributes = []string{"*"}
+		}
+		writer.search = &jsonSearch{
+			BaseDN:     search.BaseDN,
+			Scope:      ldap.ScopeName(search.Scope),
+			Filter:     search.OneLineFilter(),
+			Attributes: attributes,
+		}
+	}
+	return writer, nil
+}
+
+// CheckFormat reports whether NewWriter accepts format with attributes, so
+// options can be checked before any work is done. An empty format is LDIF.
+func CheckFormat(format string, attributes []string) error {
+	switch strings.ToLower(format) {
+	case FormatLDIF, FormatJSON, "":
+		return nil
+	case FormatCSV:
+		if len(attributes) == 0 {
+			return fmt.Errorf("csv output needs the attributes to use as columns")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (use ldif, csv or json)", format)
+	}
+}
+
+// attributeNames returns the names to write for entry: those in attributes
+// first, in that order, then the rest in server order
+func attributeNames(entry *ldap.Entry, attributes []string) []string {
+	seen := make(map[string]bool, len(entry.Attributes))
+	var names []string
+	add := func(name string) {
+		if _, ok := entry.Attributes[name]; ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, wanted := range attributes {
+		for name := range entry.Attributes {
+			if strings.EqualFold(name, wanted) {
+				add(name)
+			}
+		}
+	}
+	for _, name := range entry.Order {
+		add(name)
+	}
+	var rest []string
+	for name := range entry.Attributes {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// ldifWriter writes LDIF content records
+type ldifWriter struct {
+	w          io.Writer
+	attributes []string
+	comment    []string
+	started    bool
+}
+
+// Write writes entry as a content record
+func (lw *ldifWriter) Write(entry *ldap.Entry) error {
+	var b strings.Builder
+	if !lw.started {
+		lw.writeHeader(&b)
+	}
+	b.WriteString("\n")
+	writeLDIFLine(&b, "dn", entry.DN)
+	for _, name := range attributeNames(entry, lw.attributes) {
+		for _, value := range entry.Attributes[name] {
+			writeLDIFLine(&b, name, value)
+		}
+	}
+	_, err := io.WriteString(lw.w, b.String())
+	return err
+}
+
+// Close writes the header if no entries were written
+func (lw *ldifWriter) Close() error {
+	if lw.started {
+		return nil
+	}
+	var b strings.Builder
+	lw.writeHeader(&b)
+	_, err := io.WriteString(lw.w, b.String())
+	return err
+}
+
+// writeHeader writes the version line and the comment lines
+func (lw *ldifWriter) writeHeader(b *strings.Builder) {
+	lw.started = true
+	b.WriteString("version: 1\n")
+	for _, line := range lw.comment {
+		b.WriteString("# " + line + "\n")
+	}
+}
+
+// LDIFLine returns one attribute line as an LDIF file holds it, ending in a
+// newline, base64-encoded and folded as needed
+func LDIFLine(name, value string) string {
+	var b strings.Builder
+	writeLDIFLine(&b, name, value)
+	return b.String()
+}
+
+// writeLDIFLine writes one attribute line, base64-encoding values LDIF
+// can't hold as plain text and folding long lines
+func writeLDIFLine(b *strings.Builder, name, value string) {
+	line := name + ": " + value
+	if !isSafeLDIFString(value) {
+		line = name + ":: " + base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	for len(line) > ldifLineWidth {
+		b.WriteString(line[:ldifLineWidth])
+		b.WriteString("\n ")
+		line = line[ldifLineWidth:]
+	}
+	b.WriteString(line)
+	b.WriteString("\n")
+}
+
+// isSafeLDIFString reports whether value can be written as-is: printable
+// ASCII not starting with a space, colon or less-than sign and not ending
+// with a space
+func isSafeLDIFString(value string) bool {
+	if value == "" {
+		return true
+	}
+	if value[0] == ' ' || value[0] == ':' || value[0] == '<' || value[len(value)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if value[i] < 0x20 || value[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// csvWriter writes a header row then one row per entry
+type csvWriter struct {
+	w          *csv.Writer
+	attributes []string
+	started    bool
+	comment    string    // Line written before the header row, empty for none
+	raw        io.Writer // Where w writes, for the comment line
+}
+
+// Write writes entry as a row, joining multiple values in one cell
+func (cw *csvWriter) Write(entry *ldap.Entry) error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+
+	row := make([]string, 0, len(cw.attributes)+1)
+	row = append(row, entry.DN)
+	for _, wanted := range cw.attributes {
+		var values []string
+		for name, v := range entry.Attributes {
+			if strings.EqualFold(name, wanted) {
+				values = v
+			}
+		}
+		row = append(row, strings.Join(values, csvValueSeparator))
+	}
+	if err := cw.w.Write(row); err != nil {
+		return err
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// Close writes the header if no entries were written
+func (cw *csvWriter) Close() error {
+	if err := cw.writeHeader(); err != nil {
+		return err
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// writeHeader writes the header row once
+func (cw *csvWriter) writeHeader() error {
+	if cw.started {
+		return nil
+	}
+	cw.started = true
+	if cw.comment != "" {
+		if _, err := io.WriteString(cw.raw, cw.comment); err != nil {
+			return err
+		}
+	}
+	return cw.w.Write(append([]string{"dn"}, cw.attributes...))
+}
+
+// jsonEntry is how an entry is written in JSON output. Attributes with a
+// value JSON strings can't hold, such as a jpegPhoto, are in Base64 instead,
+// with every value base64-encoded as LDIF writes them.
+type jsonEntry struct {
+	DN         string              `json:"dn"`
+	Attributes map[string][]string `json:"attributes"`
+	Base64     map[string][]string `json:"base64,omitempty"`
+}
+
+// newJSONEntry splits entry's attributes into those written as text and
+// those that need base64
+func newJSONEntry(entry *ldap.Entry) jsonEntry {
+	je := jsonEntry{DN: entry.DN, Attributes: make(map[string][]string, len(entry.Attributes))}
+	for name, values := range entry.Attributes {
+		if !slices.ContainsFunc(values, func(value string) bool { return !utf8.ValidString(value) }) {
+			je.Attributes[name] = values
+			continue
+		}
+		encoded := make([]string, len(values))
+		for i, value := range values {
+			encoded[i] = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+		if je.Base64 == nil {
+			je.Base64 = make(map[string][]string)
+		}
+		je.Base64[name] = encoded
+	}
+	return je
+}
+
+// jsonSearch is how the search is written in annotated JSON output
+type jsonSearch struct {
+	BaseDN     string   `json:"base"`
+	Scope      string   `json:"scope"`
+	Filter     string   `json:"filter"`
+	Attributes []string `json:"attributes"`
+}
+
+// jsonWriter writes a JSON array with one object per entry, inside an
+// object with the search when annotated
+type jsonWriter struct {
+	w      io.Writer
+	count  int
+	search *jsonSearch // Written ahead of the entries, nil for the bare array
+}
+
+// Write writes entry as the next array element
+func (jw *jsonWriter) Write(entry *ldap.Entry) error {
+	data, err := json.Marshal(newJSONEntry(entry))
+	if err != nil {
+		return err
+	}
+
+	indent := "\n  "
+	if jw.search != nil {
+		indent = "\n    "
+	}
+	prefix := "," + indent
+	if jw.count == 0 {
+		open, err := jw.open()
+		if err != nil {
+			return err
+		}
+		prefix = open + "[" + indent
+	}
+	jw.count++
+	_, err = io.WriteString(jw.w, prefix+string(data))
+	return err
+}
+
+// open returns what comes before the array: nothing for the bare array, or
+// the start of the object holding the search
+func (jw *jsonWriter) open() (string, error) {
+	if jw.search == nil {
+		return "", nil
+	}
+	search, err := json.Marshal(jw.search)
+	if err != nil {
+		return "", err
+	}
+	return "{\n  \"search\": " + string(search) + ",\n  \"entries\": ", nil
+}
+
+// Close ends the array, and the object around it when annotated
+func (jw *jsonWriter) Close() error {
+	end := "\n]"
+	if jw.search != nil {
+		end = "\n  ]"
+	}
+	if jw.count == 0 {
+		open, err := jw.open()
+		if err != nil {
+			return err
+		}
+		end = open + "[]"
+	}
+	if jw.search != nil {
+		end += "\n}"
+	}
+	_, err := io.WriteString(jw.w, end+"\n")
+	return err
+}
@@ -0,0 +1,217 @@
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func testEntries() []*ldap.Entry {
+	return []*ldap.Entry{
+		{
+			DN:         "uid=alice,ou=people,dc=example,dc=com",
+			Attributes: map[string][]string{"uid": {"alice"}, "cn": {"Alice Smith"}, "mail": {"alice@example.com", "a.smith@example.com"}},
+			Order:      []string{"uid", "cn", "mail"},
+		},
+		{
+			DN:         "uid=bob,ou=people,dc=example,dc=com",
+			Attributes: map[string][]string{"uid": {"bob"}, "description": {" leading space"}},
+		},
+	}
+}
+
+func writeAll(t *testing.T, format string, attributes []string, entries []*ldap.Entry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, attributes)
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	for _, entry := range entries {
+		if err := w.Write(entry); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return buf.String()
+}
+
+func TestLDIFWriter(t *testing.T) {
+	got := writeAll(t, FormatLDIF, []string{"mail"}, testEntries())
+	want := `version: 1
+
+dn: uid=alice,ou=people,dc=example,dc=com
+mail: alice@example.com
+mail: a.smith@example.com
+uid: alice
+cn: Alice Smith
+
+dn: uid=bob,ou=people,dc=example,dc=com
+description:: IGxlYWRpbmcgc3BhY2U=
+uid: bob
+`
+	if got != want {
+		t.Errorf("Unexpected LDIF:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLDIFWriter_Comment(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatLDIF, &buf, nil, "base: dc=example,dc=com | scope: sub")
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := buf.String(); got != "version: 1\n# base: dc=example,dc=com | scope: sub\n" {
+		t.Errorf("Expected the comment after the version line, got %q", got)
+	}
+}
+
+func TestLDIFWriter_FoldsLongLines(t *testing.T) {
+	entry := &ldap.Entry{DN: "cn=long", Attributes: map[string][]string{"description": {strings.Repeat("x", 100)}}}
+	got := writeAll(t, FormatLDIF, nil, []*ldap.Entry{entry})
+
+	for _, line := range strings.Split(got, "\n") {
+		if len(line) > ldifLineWidth+1 {
+			t.Errorf("Expected lines to be folded, got %d characters", len(line))
+		}
+	}
+	unfolded := strings.ReplaceAll(got, "\n ", "")
+	if !strings.Contains(unfolded, "description: "+strings.Repeat("x", 100)+"\n") {
+		t.Errorf("Expected the folded line to unfold to the value, got %q", got)
+	}
+}
+
+func TestLDIFLine(t *testing.T) {
+	if got := LDIFLine("cn", "alice"); got != "cn: alice\n" {
+		t.Errorf("Unexpected line %q", got)
+	}
+	if got := LDIFLine("description", "café"); got != "description:: Y2Fmw6k=\n" {
+		t.Errorf("Expected a non-ASCII value base64-encoded, got %q", got)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	got := writeAll(t, FormatCSV, []string{"uid", "mail"}, testEntries())
+	want := "dn,uid,mail\n" +
+		"\"uid=alice,ou=people,dc=example,dc=com\",alice,alice@example.com; a.smith@example.com\n" +
+		"\"uid=bob,ou=people,dc=example,dc=com\",bob,\n"
+	if got != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := NewWriter(FormatCSV, &bytes.Buffer{}, nil); err == nil {
+		t.Error("Expected CSV without attributes to be refused")
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	var decoded []jsonEntry
+	if err := json.Unmarshal([]byte(writeAll(t, FormatJSON, nil, testEntries())), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0].DN != "uid=alice,ou=people,dc=example,dc=com" || len(decoded[0].Attributes["mail"]) != 2 {
+		t.Errorf("Unexpected JSON entries: %+v", decoded)
+	}
+
+	if got := writeAll(t, FormatJSON, nil, nil); got != "[]\n" {
+		t.Errorf("Expected an empty array without entries, got %q", got)
+	}
+}
+
+func TestJSONWriter_Base64EncodesBinaryValues(t *testing.T) {
+	photo := "\xff\xd8\xff\xe0JFIF"
+	entry := &ldap.Entry{
+		DN:         "uid=alice,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{"uid": {"alice"}, "jpegPhoto": {photo}},
+	}
+	var decoded []jsonEntry
+	if err := json.Unmarshal([]byte(writeAll(t, FormatJSON, nil, []*ldap.Entry{entry})), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON: %v", err)
+	}
+	if len(decoded) != 1 || decoded[0].Attributes["uid"][0] != "alice" {
+		t.Fatalf("Unexpected JSON entries: %+v", decoded)
+	}
+	if _, ok := decoded[0].Attributes["jpegPhoto"]; ok {
+		t.Error("Expected the binary attribute to be left out of the text attributes")
+	}
+	values := decoded[0].Base64["jpegPhoto"]
+	if len(values) != 1 {
+		t.Fatalf("Expected the binary attribute base64-encoded, got %+v", decoded[0].Base64)
+	}
+	if data, err := base64.StdEncoding.DecodeString(values[0]); err != nil || string(data) != photo {
+		t.Errorf("Expected the photo to survive the round trip, got %q (%v)", data, err)
+	}
+}
+
+// writeAnnotated writes entries with NewAnnotatedWriter
+func writeAnnotated(t *testing.T, format string, attributes []string, entries []*ldap.Entry) string {
+	t.Helper()
+	search := ldap.SearchParams{BaseDN: "ou=people,dc=example,dc=com", Scope: 2, Filter: "(uid=*)", Attributes: attributes}
+	var buf bytes.Buffer
+	w, err := NewAnnotatedWriter(format, &buf, attributes, search)
+	if err != nil {
+		t.Fatalf("NewAnnotatedWriter failed: %v", err)
+	}
+	for _, entry := range entries {
+		if err := w.Write(entry); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return buf.String()
+}
+
+func TestAnnotatedCSV(t *testing.T) {
+	got := writeAnnotated(t, FormatCSV, []string{"uid"}, testEntries()[:1])
+	want := "# base: ou=people,dc=example,dc=com | scope: sub | filter: (uid=*) | attributes: uid\n" +
+		"dn,uid\n" +
+		"\"uid=alice,ou=people,dc=example,dc=com\",alice\n"
+	if got != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAnnotatedJSON(t *testing.T) {
+	for _, entries := range [][]*ldap.Entry{testEntries(), nil} {
+		var decoded struct {
+			Search  jsonSearch  `json:"search"`
+			Entries []jsonEntry `json:"entries"`
+		}
+		got := writeAnnotated(t, FormatJSON, nil, entries)
+		if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+			t.Fatalf("Expected valid JSON: %v\n%s", err, got)
+		}
+		want := jsonSearch{BaseDN: "ou=people,dc=example,dc=com", Scope: "sub", Filter: "(uid=*)", Attributes: []string{"*"}}
+		if !reflect.DeepEqual(decoded.Search, want) {
+			t.Errorf("Expected the search %+v, got %+v", want, decoded.Search)
+		}
+		if len(decoded.Entries) != len(entries) {
+			t.Errorf("Expected %d entries, got %d", len(entries), len(decoded.Entries))
+		}
+	}
+}
+
+func TestAnnotatedLDIF(t *testing.T) {
+	got := writeAnnotated(t, FormatLDIF, nil, nil)
+	if got != "version: 1\n# base: ou=people,dc=example,dc=com | scope: sub | filter: (uid=*) | attributes: *\n" {
+		t.Errorf("Expected the search as a comment, got %q", got)
+	}
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	if _, err := NewWriter("xml", &bytes.Buffer{}, nil); err == nil {
+		t.Error("Expected an unknown format to be refused")
+	}
+}
@@ -0,0 +1,45 @@
+package ldap
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Add creates a new entry with the given attributes
+func (c *Client) Add(dn string, attributes map[string][]string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	addRequest := buildAddRequest(dn, attributes)
+
+	return c.audited(AuditAdd, dn, summarizeAdd(attributes), func() error {
+		return c.withRetry(func() error {
+			if err := c.connection().Add(addRequest); err != nil {
+				return fmt.Errorf("add failed: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+// buildAddRequest converts an attribute map into a go-ldap add request.
+// Attributes are sorted so requests are deterministic.
+func buildAddRequest(dn string, attributes map[string][]string) *ldap.AddRequest {
+	addRequest := ldap.NewAddRequest(dn, nil)
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if values := attributes[name]; len(values) > 0 {
+			addRequest.Attribute(name, values)
+		}
+	}
+
+	return addRequest
+}
@@ -0,0 +1,30 @@
+package ldap
+
+import "testing"
+
+func TestBuildAddRequest(t *testing.T) {
+	req := buildAddRequest("uid=jdoe,ou=people,dc=example,dc=com", map[string][]string{
+		"uid":         {"jdoe"},
+		"objectClass": {"inetOrgPerson", "posixAccount"},
+		"cn":          {"Jane Doe"},
+		"description": nil,
+	})
+
+	if req.DN != "uid=jdoe,ou=people,dc=example,dc=com" {
+		t.Errorf("Expected DN to be preserved, got %s", req.DN)
+	}
+
+	// Attributes are sorted and empty ones are dropped
+	expected := []string{"cn", "objectClass", "uid"}
+	if len(req.Attributes) != len(expected) {
+		t.Fatalf("Expected %d attributes, got %d", len(expected), len(req.Attributes))
+	}
+	for i, attr := range req.Attributes {
+		if attr.Type != expected[i] {
+			t.Errorf("Attribute %d: expected %s, got %s", i, expected[i], attr.Type)
+		}
+	}
+	if len(req.Attributes[1].Vals) != 2 {
+		t.Errorf("Expected both objectClass values, got %v", req.Attributes[1].Vals)
+	}
+}
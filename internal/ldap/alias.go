@@ -0,0 +1,72 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Alias dereferencing modes of Config.DerefAliases (RFC 4511 4.5.1.3)
+const (
+	DerefNever  = "never"  // Return alias entries themselves
+	DerefSearch = "search" // Follow aliases found below the search base
+	DerefFind   = "find"   // Follow an alias used as the search base
+	DerefAlways = "always" // Follow aliases both when finding the base and searching
+)
+
+// DerefModes lists the alias dereferencing modes in order of how much they follow
+var DerefModes = []string{DerefNever, DerefSearch, DerefFind, DerefAlways}
+
+// AliasedObjectNameAttribute holds the DN an alias entry points to
+const AliasedObjectNameAttribute = "aliasedObjectName"
+
+// ParseDerefAliases converts a dereferencing mode name to its LDAP value.
+// An empty mode is never, so searches return aliases as they are stored.
+func ParseDerefAliases(mode string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case DerefNever, "":
+		return ldap.NeverDerefAliases, nil
+	case DerefSearch:
+		return ldap.DerefInSearching, nil
+	case DerefFind:
+		return ldap.DerefFindingBaseObj, nil
+	case DerefAlways:
+		return ldap.DerefAlways, nil
+	default:
+		return 0, fmt.Errorf("unknown alias dereferencing mode %q (use %s)", mode, strings.Join(DerefModes, ", "))
+	}
+}
+
+// AliasTarget returns the DN an alias entry points to, empty for entries
+// that aren't aliases
+func (e *Entry) AliasTarget() string {
+	if e == nil {
+		return ""
+	}
+	for name, values := range e.Attributes {
+		if strings.EqualFold(name, AliasedObjectNameAttribute) && len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+	}
+	return ""
+}
+
+// IsAlias reports whether the entry is an alias, by its objectClass or the
+// aliasedObjectName only aliases carry
+func (e *Entry) IsAlias() bool {
+	if e == nil {
+		return false
+	}
+	for name, values := range e.Attributes {
+		if !strings.EqualFold(name, "objectClass") {
+			continue
+		}
+		for _, value := range values {
+			if strings.EqualFold(strings.TrimSpace(value), "alias") {
+				return true
+			}
+		}
+	}
+	return e.AliasTarget() != ""
+}
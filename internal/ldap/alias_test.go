@@ -0,0 +1,102 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestParseDerefAliases(t *testing.T) {
+	tests := []struct {
+		mode string
+		want int
+	}{
+		{"", ldap.NeverDerefAliases},
+		{"never", ldap.NeverDerefAliases},
+		{"search", ldap.DerefInSearching},
+		{"Find", ldap.DerefFindingBaseObj},
+		{" always ", ldap.DerefAlways},
+	}
+	for _, tt := range tests {
+		got, err := ParseDerefAliases(tt.mode)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDerefAliases(%q) = %d, %v, want %d", tt.mode, got, err, tt.want)
+		}
+	}
+	if _, err := ParseDerefAliases("sometimes"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+}
+
+func TestEntryAlias(t *testing.T) {
+	alias := &Entry{Attributes: map[string][]string{
+		"objectClass":       {"alias", "extensibleObject"},
+		"aliasedobjectname": {"uid=jdoe,ou=people,dc=example,dc=com"},
+	}}
+	if !alias.IsAlias() || alias.AliasTarget() != "uid=jdoe,ou=people,dc=example,dc=com" {
+		t.Errorf("Expected an alias of uid=jdoe, got %v %q", alias.IsAlias(), alias.AliasTarget())
+	}
+
+	person := &Entry{Attributes: map[string][]string{"objectClass": {"inetOrgPerson"}}}
+	if person.IsAlias() || person.AliasTarget() != "" {
+		t.Error("Expected a person not to be an alias")
+	}
+	var missing *Entry
+	if missing.IsAlias() {
+		t.Error("Expected a nil entry not to be an alias")
+	}
+}
+
+func TestClientDerefAliases(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"objectClass": {"inetOrgPerson"}}},
+		ldaptest.Entry{DN: "cn=jane,dc=example,dc=com", Attributes: map[string][]string{
+			"objectClass":       {"alias", "extensibleObject"},
+			"aliasedObjectName": {"uid=jdoe,dc=example,dc=com"},
+		}},
+	)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com", DerefAliases: DerefAlways})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.CustomSearch("(objectClass=*)"); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if _, err := client.CustomSearchPaged("(objectClass=*)", 10, nil); err != nil {
+		t.Fatalf("Paged search failed: %v", err)
+	}
+
+	// The tree shows aliases as stored, with the DN they point to
+	children, err := client.GetChildren("")
+	if err != nil {
+		t.Fatalf("GetChildren failed: %v", err)
+	}
+	targets := make(map[string]string)
+	for _, child := range children {
+		targets[child.Name] = child.Alias
+	}
+	if targets["cn=jane"] != "uid=jdoe,dc=example,dc=com" || targets["uid=jdoe"] != "" {
+		t.Errorf("Expected only cn=jane to be marked as an alias, got %v", targets)
+	}
+
+	got := server.DerefAliases()
+	want := []int{ldap.DerefAlways, ldap.DerefAlways, ldap.NeverDerefAliases}
+	if len(got) < len(want) {
+		t.Fatalf("Expected at least %d searches, got %v", len(want), got)
+	}
+	got = got[len(got)-len(want):]
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected dereferencing modes %v, got %v", want, got)
+			break
+		}
+	}
+
+	if _, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), DerefAliases: "sometimes"}); err == nil {
+		t.Error("Expected an unknown dereferencing mode to be rejected")
+	}
+}
@@ -0,0 +1,53 @@
+package ldap
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// IsAnonymous reports whether config binds without credentials: a simple
+// bind with no bind user
+func (config Config) IsAnonymous() bool {
+	method := strings.ToLower(config.BindMethod)
+	return strings.TrimSpace(config.BindUser) == "" && (method == "" || method == BindMethodSimple)
+}
+
+// explainAnonymous attaches guidance to a failed bind when the server
+// refused an anonymous connection, and otherwise returns err unchanged
+func (config Config) explainAnonymous(err error) error {
+	if explanation, ok := ExplainAnonymousBind(err, config); ok {
+		return &ExplainedError{Explanation: explanation, Err: err}
+	}
+	return err
+}
+
+// ExplainAnonymousBind looks up guidance for a server refusing the anonymous
+// bind of config. Hardened servers answer it with inappropriateAuthentication,
+// or with confidentialityRequired or strongerAuthRequired when they want an
+// authenticated, encrypted connection; the raw codes don't say that a bind
+// user is what's missing.
+func ExplainAnonymousBind(err error, config Config) (Explanation, bool) {
+	var ldapErr *ldap.Error
+	if err == nil || !config.IsAnonymous() || !errors.As(err, &ldapErr) {
+		return Explanation{}, false
+	}
+
+	encrypted := config.UseSSL || config.UseTLS
+	switch ldapErr.ResultCode {
+	case ldap.LDAPResultInappropriateAuthentication:
+		return Explanation{"This server requires authentication", "Anonymous binds are disabled; set a bind user and password for this connection"}, true
+	case ldap.LDAPResultConfidentialityRequired:
+		if encrypted {
+			return Explanation{"This server requires authentication", "Set a bind user and password for this connection"}, true
+		}
+		return Explanation{"This server requires an authenticated, encrypted connection", "Set a bind user and password, and enable StartTLS or SSL for this connection"}, true
+	case ldap.LDAPResultStrongAuthRequired:
+		if encrypted {
+			return Explanation{"This server requires authentication", "Set a bind user and password, or use Kerberos"}, true
+		}
+		return Explanation{"This server requires stronger authentication", "Set a bind user and password and enable StartTLS or SSL, or use Kerberos"}, true
+	}
+	return Explanation{}, false
+}
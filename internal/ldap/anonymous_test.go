@@ -0,0 +1,77 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestExplainAnonymousBind(t *testing.T) {
+	refused := func(code uint16) error {
+		return fmt.Errorf("failed to bind: %w", ldap.NewError(code, errors.New("anonymous bind disallowed")))
+	}
+
+	tests := []struct {
+		name        string
+		err         error
+		config      Config
+		wantSummary string // Empty when no guidance applies
+		wantFix     string
+	}{
+		{"anonymous binds disabled", refused(ldap.LDAPResultInappropriateAuthentication), Config{}, "requires authentication", "set a bind user"},
+		{"confidentiality required", refused(ldap.LDAPResultConfidentialityRequired), Config{}, "authenticated, encrypted connection", "enable StartTLS or SSL"},
+		{"confidentiality required over TLS", refused(ldap.LDAPResultConfidentialityRequired), Config{UseTLS: true}, "requires authentication", "Set a bind user"},
+		{"stronger auth required", refused(ldap.LDAPResultStrongAuthRequired), Config{BindMethod: BindMethodSimple}, "stronger authentication", "use Kerberos"},
+		{"stronger auth required over SSL", refused(ldap.LDAPResultStrongAuthRequired), Config{UseSSL: true}, "requires authentication", "Set a bind user"},
+		{"bind user set", refused(ldap.LDAPResultInappropriateAuthentication), Config{BindUser: "cn=admin,dc=example,dc=com"}, "", ""},
+		{"Kerberos", refused(ldap.LDAPResultStrongAuthRequired), Config{BindMethod: BindMethodGSSAPI}, "", ""},
+		{"other result code", refused(ldap.LDAPResultInvalidCredentials), Config{}, "", ""},
+		{"not an LDAP error", errors.New("connection refused"), Config{}, "", ""},
+		{"no error", nil, Config{}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation, ok := ExplainAnonymousBind(tt.err, tt.config)
+			if tt.wantSummary == "" {
+				if ok {
+					t.Errorf("Expected no guidance, got %+v", explanation)
+				}
+				return
+			}
+			if !ok || !strings.Contains(explanation.Summary, tt.wantSummary) || !strings.Contains(explanation.Fix, tt.wantFix) {
+				t.Errorf("Expected %q with fix %q, got %+v", tt.wantSummary, tt.wantFix, explanation)
+			}
+		})
+	}
+}
+
+func TestNewClient_AnonymousBindRefused(t *testing.T) {
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	server.RefuseAnonymous = ldap.LDAPResultInappropriateAuthentication
+
+	_, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port()})
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("Expected a bind error, got %v", err)
+	}
+	var explained *ExplainedError
+	if !errors.As(err, &explained) || explained.Summary != "This server requires authentication" {
+		t.Errorf("Expected the refusal to be explained, got %v", err)
+	}
+	if !ldap.IsErrorWithCode(err, ldap.LDAPResultInappropriateAuthentication) {
+		t.Errorf("Expected the result code to be kept, got %v", err)
+	}
+
+	// Servers that allow anonymous access still connect
+	server.RefuseAnonymous = 0
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port()})
+	if err != nil {
+		t.Fatalf("Expected an anonymous connection, got %v", err)
+	}
+	client.Close()
+}
@@ -0,0 +1,176 @@
+package ldap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditOperation identifies the kind of write recorded in the audit log
+type AuditOperation string
+
+const (
+	AuditAdd    AuditOperation = "add"
+	AuditModify AuditOperation = "modify"
+	AuditDelete AuditOperation = "delete"
+)
+
+// AuditEntry is a single line of the audit log
+type AuditEntry struct {
+	Timestamp  time.Time      `json:"timestamp"`
+	Connection string         `json:"connection"`
+	Operation  AuditOperation `json:"operation"`
+	DN         string         `json:"dn"`
+	Changes    []string       `json:"changes,omitempty"`
+	Error      string         `json:"error,omitempty"` // Set when the operation itself failed
+}
+
+// sensitiveAttributes are written to the audit log without their values
+var sensitiveAttributes = map[string]bool{
+	"userpassword":    true,
+	"unicodepwd":      true,
+	"sambantpassword": true,
+	"sambalmpassword": true,
+}
+
+// AuditLog appends a JSON line for every write operation to a file
+type AuditLog struct {
+	path       string
+	connection string
+	now        func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewAuditLog creates an audit log that appends to path, labelling entries
+// with the connection name
+func NewAuditLog(path, connection string) *AuditLog {
+	return &AuditLog{
+		path:       path,
+		connection: connection,
+		now:        time.Now,
+	}
+}
+
+// Path returns the file the audit log is written to
+func (l *AuditLog) Path() string {
+	return l.path
+}
+
+// Record appends an entry for a write operation. opErr is the result of the
+// operation, so failed attempts are recorded too.
+func (l *AuditLog) Record(operation AuditOperation, dn string, changes []string, opErr error) error {
+	entry := AuditEntry{
+		Timestamp:  l.now().UTC(),
+		Connection: l.connection,
+		Operation:  operation,
+		DN:         dn,
+		Changes:    changes,
+	}
+	if opErr != nil {
+		entry.Error = opErr.Error()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if dir := filepath.Dir(l.path); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("failed to create audit log directory: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// SetAuditLog sets the audit log write operations are recorded to. A nil log
+// disables auditing.
+func (c *Client) SetAuditLog(log *AuditLog) {
+	c.audit = log
+}
+
+// TakeAuditError returns the last failure to write the audit log, if any, and
+// clears it. Audit failures never fail the write operation itself.
+func (c *Client) TakeAuditError() error {
+	c.auditMu.Lock()
+	defer c.auditMu.Unlock()
+
+	err := c.auditErr
+	c.auditErr = nil
+	return err
+}
+
+// audited runs a write operation and records it in the audit log
+func (c *Client) audited(operation AuditOperation, dn string, changes []string, run func() error) error {
+	err := run()
+	if c.audit == nil {
+		return err
+	}
+
+	if auditErr := c.audit.Record(operation, dn, changes, err); auditErr != nil {
+		c.auditMu.Lock()
+		c.auditErr = auditErr
+		c.auditMu.Unlock()
+	}
+	return err
+}
+
+// summarizeValues renders attribute values for the audit log, hiding passwords
+func summarizeValues(attribute string, values []string) string {
+	if sensitiveAttributes[strings.ToLower(attribute)] {
+		return fmt.Sprintf("<%d value(s) redacted>", len(values))
+	}
+	return strings.Join(values, ", ")
+}
+
+// summarizeModify describes attribute changes for the audit log
+func summarizeModify(changes []AttributeChange) []string {
+	summary := make([]string, 0, len(changes))
+	for _, change := range changes {
+		var op string
+		switch change.Operation {
+		case ModifyAdd:
+			op = "add"
+		case ModifyDelete:
+			op = "delete"
+		case ModifyReplace:
+			op = "replace"
+		}
+
+		line := op + " " + change.Attribute
+		if len(change.Values) > 0 {
+			line += ": " + summarizeValues(change.Attribute, change.Values)
+		}
+		summary = append(summary, line)
+	}
+	return summary
+}
+
+// summarizeAdd describes the attributes of a new entry for the audit log
+func summarizeAdd(attributes map[string][]string) []string {
+	request := buildAddRequest("", attributes)
+
+	summary := make([]string, 0, len(request.Attributes))
+	for _, attr := range request.Attributes {
+		summary = append(summary, attr.Type+": "+summarizeValues(attr.Type, attr.Vals))
+	}
+	return summary
+}
@@ -0,0 +1,214 @@
+package ldap
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// newFakeServerClient returns a client connected to an in-memory server that
+// answers every add, modify and delete request with resultCode
+func newFakeServerClient(t *testing.T, resultCode uint16) *Client {
+	t.Helper()
+
+	clientSide, serverSide := net.Pipe()
+	go func() {
+		defer serverSide.Close()
+		for {
+			packet, err := ber.ReadPacket(serverSide)
+			if err != nil || len(packet.Children) < 2 {
+				return
+			}
+			messageID := packet.Children[0].Value.(int64)
+			tag := packet.Children[1].Tag
+			if tag == ldap.ApplicationUnbindRequest {
+				return
+			}
+
+			response := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+			result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag+1, nil, "Response")
+			result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(resultCode), "resultCode"))
+			result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+			result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+			response.AppendChild(result)
+
+			if _, err := serverSide.Write(response.Bytes()); err != nil {
+				return
+			}
+		}
+	}()
+
+	conn := ldap.NewConn(clientSide, false)
+	conn.Start()
+	client := &Client{conn: conn}
+	t.Cleanup(client.Close)
+	return client
+}
+
+// readAuditLog parses every line of the audit log at path
+func readAuditLog(t *testing.T, path string) []AuditEntry {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid audit line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditLog_RecordsEachWriteType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	client := newFakeServerClient(t, ldap.LDAPResultSuccess)
+	client.SetAuditLog(NewAuditLog(path, "Production"))
+
+	dn := "uid=jdoe,ou=people,dc=example,dc=com"
+	if err := client.Add(dn, map[string][]string{"cn": {"John Doe"}, "userPassword": {"secret"}}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := client.Modify(dn, []AttributeChange{
+		{Operation: ModifyReplace, Attribute: "mail", Values: []string{"jdoe@example.com"}},
+		{Operation: ModifyDelete, Attribute: "description"},
+	}); err != nil {
+		t.Fatalf("Modify failed: %v", err)
+	}
+	if err := client.Delete(dn); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	client.capabilities = &Capabilities{Controls: map[string]bool{OIDTreeDelete: true}}
+	if err := client.DeleteSubtree("ou=people,dc=example,dc=com"); err != nil {
+		t.Fatalf("DeleteSubtree failed: %v", err)
+	}
+
+	if err := client.TakeAuditError(); err != nil {
+		t.Errorf("Expected no audit error, got %v", err)
+	}
+
+	entries := readAuditLog(t, path)
+	want := []struct {
+		operation AuditOperation
+		changes   []string
+	}{
+		{AuditAdd, []string{"cn: John Doe", "userPassword: <1 value(s) redacted>"}},
+		{AuditModify, []string{"replace mail: jdoe@example.com", "delete description"}},
+		{AuditDelete, nil},
+		{AuditDelete, []string{"subtree"}},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d audit entries, got %+v", len(want), entries)
+	}
+	for i, w := range want {
+		entry := entries[i]
+		if entry.Operation != w.operation || entry.Connection != "Production" || entry.Error != "" {
+			t.Errorf("Entry %d: unexpected %+v", i, entry)
+		}
+		if strings.Join(entry.Changes, "|") != strings.Join(w.changes, "|") {
+			t.Errorf("Entry %d: expected changes %v, got %v", i, w.changes, entry.Changes)
+		}
+		if entry.Timestamp.IsZero() {
+			t.Errorf("Entry %d: expected a timestamp", i)
+		}
+	}
+	if entries[0].DN != dn || entries[3].DN != "ou=people,dc=example,dc=com" {
+		t.Error("Expected entries to record the target DN")
+	}
+}
+
+func TestAuditLog_RecordsFailedOperations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	client := newFakeServerClient(t, ldap.LDAPResultInsufficientAccessRights)
+	client.SetAuditLog(NewAuditLog(path, "Production"))
+
+	if err := client.Delete("cn=test,dc=example,dc=com"); err == nil {
+		t.Fatal("Expected delete to fail")
+	}
+
+	entries := readAuditLog(t, path)
+	if len(entries) != 1 || !strings.Contains(entries[0].Error, "delete failed") {
+		t.Errorf("Expected the failure to be recorded, got %+v", entries)
+	}
+}
+
+func TestAuditLog_WriteFailureDoesNotBlockOperation(t *testing.T) {
+	// A directory can't be opened as the log file
+	client := newFakeServerClient(t, ldap.LDAPResultSuccess)
+	client.SetAuditLog(NewAuditLog(t.TempDir(), "Production"))
+
+	if err := client.ReplaceAttribute("cn=test,dc=example,dc=com", "mail", []string{"a@example.com"}); err != nil {
+		t.Fatalf("Expected the modify to succeed, got %v", err)
+	}
+
+	if err := client.TakeAuditError(); err == nil || !strings.Contains(err.Error(), "audit log") {
+		t.Errorf("Expected the audit failure to be reported, got %v", err)
+	}
+	if err := client.TakeAuditError(); err != nil {
+		t.Errorf("Expected the audit error to be cleared, got %v", err)
+	}
+}
+
+func TestAuditLog_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log := NewAuditLog(path, "Staging")
+	log.now = func() time.Time { return time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC) }
+
+	if err := log.Record(AuditModify, "cn=old,dc=example,dc=com", []string{"replace description"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Record(AuditDelete, "cn=new,dc=example,dc=com", nil, errors.New("refused")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", data)
+	}
+	want := `{"timestamp":"2024-01-31T12:00:00Z","connection":"Staging","operation":"modify","dn":"cn=old,dc=example,dc=com","changes":["replace description"]}`
+	if lines[0] != want {
+		t.Errorf("Expected %s, got %s", want, lines[0])
+	}
+	if !strings.Contains(lines[1], `"error":"refused"`) {
+		t.Errorf("Expected the error to be recorded, got %s", lines[1])
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the log to be private, got %v", info.Mode().Perm())
+	}
+}
+
+func TestClient_NoAuditLogByDefault(t *testing.T) {
+	client := newFakeServerClient(t, ldap.LDAPResultSuccess)
+	if err := client.Delete("cn=test,dc=example,dc=com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.TakeAuditError(); err != nil {
+		t.Errorf("Expected no audit error without a log, got %v", err)
+	}
+}
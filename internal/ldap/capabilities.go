@@ -0,0 +1,158 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Well-known control and extended operation OIDs advertised in the root DSE
+const (
+	OIDPagedResults       = "1.2.840.113556.1.4.319"    // Simple paged results control (RFC 2696)
+	OIDTreeDelete         = "1.2.840.113556.1.4.805"    // Tree delete control
+	OIDStartTLS           = "1.3.6.1.4.1.1466.20037"    // StartTLS extended operation (RFC 4511)
+	OIDPasswordModify     = "1.3.6.1.4.1.4203.1.11.1"   // Password modify extended operation (RFC 3062)
+	OIDWhoAmI             = "1.3.6.1.4.1.4203.1.11.3"   // Who am I? extended operation (RFC 4532)
+	OIDGetEffectiveRights = "1.3.6.1.4.1.42.2.27.9.5.2" // Get effective rights control (389-ds, Oracle DSEE)
+)
+
+// rootDSECapabilityAttributes are the root DSE attributes read by LoadCapabilities
+var rootDSECapabilityAttributes = []string{
+	"supportedControl",
+	"supportedExtension",
+	"supportedFeatures",
+	"vendorName",
+	"vendorVersion",
+	"namingContexts",
+	"defaultNamingContext",
+}
+
+// Capabilities describes the controls, extended operations and features the
+// server advertises in its root DSE
+type Capabilities struct {
+	Controls      map[string]bool
+	Extensions    map[string]bool
+	Features      map[string]bool
+	VendorName    string
+	VendorVersion string
+
+	NamingContexts       []string // Suffixes of the directory trees the server holds
+	DefaultNamingContext string   // Preferred naming context, advertised by Active Directory
+}
+
+// Supports reports whether the OID is listed as a supported control,
+// extended operation or feature
+func (caps *Capabilities) Supports(oid string) bool {
+	if caps == nil {
+		return false
+	}
+	oid = strings.TrimSpace(oid)
+	return caps.Controls[oid] || caps.Extensions[oid] || caps.Features[oid]
+}
+
+// SuggestedBaseDN returns the naming context to use as the base DN: the
+// default naming context when advertised, otherwise the first one listed
+func (caps *Capabilities) SuggestedBaseDN() string {
+	if caps == nil {
+		return ""
+	}
+	if caps.DefaultNamingContext != "" {
+		return caps.DefaultNamingContext
+	}
+	if len(caps.NamingContexts) > 0 {
+		return caps.NamingContexts[0]
+	}
+	return ""
+}
+
+// LoadCapabilities reads the root DSE and stores the advertised capabilities on
+// the client. Servers that hide the root DSE leave the capabilities unknown.
+func (c *Client) LoadCapabilities() (*Capabilities, error) {
+	entries, err := c.Search("", "(objectClass=*)", ldap.ScopeBaseObject, rootDSECapabilityAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read root DSE: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("root DSE not readable")
+	}
+
+	caps := parseCapabilities(entries[0].Attributes)
+	c.cacheMu.Lock()
+	c.capabilities = caps
+	c.cacheMu.Unlock()
+	return caps, nil
+}
+
+// Capabilities returns the capabilities loaded by LoadCapabilities, or nil if
+// they haven't been read
+func (c *Client) Capabilities() *Capabilities {
+	if c == nil {
+		return nil
+	}
+	c.cacheMu.RLock()
+	defer c.cacheMu.RUnlock()
+	return c.capabilities
+}
+
+// Supports reports whether the server supports the control, extended operation
+// or feature identified by oid. When the root DSE couldn't be read the answer
+// is unknown and Supports returns true, so operations are attempted rather than
+// blocked; callers that need to tell the cases apart can check Capabilities.
+func (c *Client) Supports(oid string) bool {
+	caps := c.Capabilities()
+	if caps == nil {
+		return true
+	}
+	return caps.Supports(oid)
+}
+
+// parseCapabilities builds Capabilities from root DSE attribute values.
+// Attribute names are matched case-insensitively since servers vary in casing.
+func parseCapabilities(attributes map[string][]string) *Capabilities {
+	caps := &Capabilities{
+		Controls:   make(map[string]bool),
+		Extensions: make(map[string]bool),
+		Features:   make(map[string]bool),
+	}
+
+	for name, values := range attributes {
+		switch strings.ToLower(name) {
+		case "supportedcontrol":
+			addOIDs(caps.Controls, values)
+		case "supportedextension":
+			addOIDs(caps.Extensions, values)
+		case "supportedfeatures":
+			addOIDs(caps.Features, values)
+		case "vendorname":
+			if len(values) > 0 {
+				caps.VendorName = values[0]
+			}
+		case "vendorversion":
+			if len(values) > 0 {
+				caps.VendorVersion = values[0]
+			}
+		case "namingcontexts":
+			for _, value := range values {
+				if value = strings.TrimSpace(value); value != "" {
+					caps.NamingContexts = append(caps.NamingContexts, value)
+				}
+			}
+		case "defaultnamingcontext":
+			if len(values) > 0 {
+				caps.DefaultNamingContext = strings.TrimSpace(values[0])
+			}
+		}
+	}
+
+	return caps
+}
+
+// addOIDs adds the non-empty OIDs in values to set
+func addOIDs(set map[string]bool, values []string) {
+	for _, value := range values {
+		if oid := strings.TrimSpace(value); oid != "" {
+			set[oid] = true
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package ldap
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+func TestParseCapabilities(t *testing.T) {
+	caps := parseCapabilities(map[string][]string{
+		"supportedControl":   {OIDPagedResults, " " + OIDTreeDelete + " ", ""},
+		"supportedextension": {OIDWhoAmI},
+		"supportedFeatures":  {"1.3.6.1.4.1.4203.1.5.1"},
+		"vendorName":         {"Example Directory"},
+		"vendorVersion":      {"1.2.3"},
+	})
+
+	for _, oid := range []string{OIDPagedResults, OIDTreeDelete, OIDWhoAmI, "1.3.6.1.4.1.4203.1.5.1"} {
+		if !caps.Supports(oid) {
+			t.Errorf("Expected %s to be supported", oid)
+		}
+	}
+	if caps.Supports(OIDPasswordModify) {
+		t.Error("Expected password modify to be unsupported")
+	}
+	if len(caps.Controls) != 2 {
+		t.Errorf("Expected empty OIDs to be skipped, got %v", caps.Controls)
+	}
+	if caps.VendorName != "Example Directory" || caps.VendorVersion != "1.2.3" {
+		t.Errorf("Unexpected vendor info: %q %q", caps.VendorName, caps.VendorVersion)
+	}
+}
+
+func TestClientSupports(t *testing.T) {
+	client := &Client{}
+	if client.Capabilities() != nil {
+		t.Fatal("Expected capabilities to be unknown before loading")
+	}
+	if !client.Supports(OIDPasswordModify) {
+		t.Error("Expected unknown capabilities not to block operations")
+	}
+
+	client.capabilities = parseCapabilities(map[string][]string{
+		"supportedControl": {OIDPagedResults},
+	})
+	if !client.Supports(OIDPagedResults) {
+		t.Error("Expected advertised control to be supported")
+	}
+	if client.Supports(OIDPasswordModify) {
+		t.Error("Expected unadvertised extension to be unsupported")
+	}
+
+	var nilClient *Client
+	if nilClient.Capabilities() != nil {
+		t.Error("Expected nil client to have no capabilities")
+	}
+}
+
+func TestSuggestedBaseDN(t *testing.T) {
+	caps := parseCapabilities(map[string][]string{
+		"namingContexts": {"dc=example,dc=com", " o=other "},
+	})
+	if len(caps.NamingContexts) != 2 || caps.NamingContexts[1] != "o=other" {
+		t.Errorf("Expected naming contexts to be parsed, got %v", caps.NamingContexts)
+	}
+	if got := caps.SuggestedBaseDN(); got != "dc=example,dc=com" {
+		t.Errorf("Expected the first naming context, got %q", got)
+	}
+
+	caps = parseCapabilities(map[string][]string{
+		"namingContexts":       {"CN=Configuration,DC=corp,DC=example", "DC=corp,DC=example"},
+		"defaultNamingContext": {"DC=corp,DC=example"},
+	})
+	if got := caps.SuggestedBaseDN(); got != "DC=corp,DC=example" {
+		t.Errorf("Expected the default naming context, got %q", got)
+	}
+
+	var none *Capabilities
+	if none.SuggestedBaseDN() != "" {
+		t.Error("Expected no suggestion without capabilities")
+	}
+}
+
+func TestClientCapabilitiesLoadedConcurrently(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "", Attributes: map[string][]string{"supportedControl": {OIDPagedResults}}},
+		ldaptest.Entry{DN: "cn=Subschema", Attributes: map[string][]string{"objectClass": {"subschema"}}},
+	)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port()})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	// The TUI loads both in the background while the UI reads them; run
+	// with -race to check the cache is guarded
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if _, err := client.LoadCapabilities(); err != nil {
+			t.Errorf("LoadCapabilities failed: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if _, err := client.GetSchema(); err != nil {
+			t.Errorf("GetSchema failed: %v", err)
+		}
+	}()
+	for range 100 {
+		client.Supports(OIDPagedResults)
+	}
+	wg.Wait()
+
+	if !client.Supports(OIDPagedResults) || client.Supports(OIDTreeDelete) {
+		t.Errorf("Expected the loaded capabilities, got %+v", client.Capabilities())
+	}
+}
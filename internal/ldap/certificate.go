@@ -0,0 +1,87 @@
+package ldap
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// certificateAttributes are the attributes holding DER-encoded X.509
+// certificates (RFC 4523), by lower-cased name without options
+var certificateAttributes = map[string]bool{
+	"usercertificate": true,
+	"cacertificate":   true,
+}
+
+// IsCertificateAttribute reports whether attribute holds DER-encoded X.509
+// certificates. Options such as ;binary are ignored.
+func IsCertificateAttribute(attribute string) bool {
+	name, _, _ := strings.Cut(attribute, ";")
+	return certificateAttributes[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// CertificateSummary is what a certificate value says about itself
+type CertificateSummary struct {
+	Subject      string
+	Issuer       string
+	SerialNumber string // Colon-separated hex
+	NotBefore    time.Time
+	NotAfter     time.Time
+	Fingerprint  string // SHA-256 of the DER encoding, colon-separated hex
+}
+
+// SummarizeCertificate parses a DER-encoded certificate value
+func SummarizeCertificate(value string) (CertificateSummary, error) {
+	cert, err := x509.ParseCertificate([]byte(value))
+	if err != nil {
+		return CertificateSummary{}, fmt.Errorf("not an X.509 certificate: %w", err)
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	return CertificateSummary{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: colonHex(cert.SerialNumber.Bytes()),
+		NotBefore:    cert.NotBefore.UTC(),
+		NotAfter:     cert.NotAfter.UTC(),
+		Fingerprint:  colonHex(fingerprint[:]),
+	}, nil
+}
+
+// Validity describes whether the certificate is valid at now, e.g. "expires
+// in 30 days" or "expired 2 days ago"
+func (s CertificateSummary) Validity(now time.Time) string {
+	switch {
+	case now.Before(s.NotBefore):
+		return "not valid until " + s.NotBefore.Format("2006-01-02")
+	case now.After(s.NotAfter):
+		return "expired " + formatDays(now.Sub(s.NotAfter)) + " ago"
+	default:
+		return "expires in " + formatDays(s.NotAfter.Sub(now))
+	}
+}
+
+// CertificatePEM wraps a DER-encoded certificate value in a PEM block
+func CertificatePEM(value string) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte(value)})
+}
+
+// formatDays renders a duration in whole days, rounding down
+func formatDays(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// colonHex renders bytes as upper-case hex pairs separated by colons
+func colonHex(b []byte) string {
+	pairs := make([]string, len(b))
+	for i, octet := range b {
+		pairs[i] = fmt.Sprintf("%02X", octet)
+	}
+	return strings.Join(pairs, ":")
+}
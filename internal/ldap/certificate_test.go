@@ -0,0 +1,75 @@
+package ldap
+
+import (
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsCertificateAttribute(t *testing.T) {
+	for _, name := range []string{"userCertificate", "userCertificate;binary", "cACertificate", " CACERTIFICATE;binary"} {
+		if !IsCertificateAttribute(name) {
+			t.Errorf("Expected %q to hold certificates", name)
+		}
+	}
+	for _, name := range []string{"userSMIMECertificate", "cn", "certificateRevocationList;binary", ""} {
+		if IsCertificateAttribute(name) {
+			t.Errorf("Expected %q not to hold certificates", name)
+		}
+	}
+}
+
+func TestSummarizeCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	der := ca.issue(t, "ldap.example.com").Certificate[0]
+
+	summary, err := SummarizeCertificate(string(der))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.Subject != "CN=ldap.example.com" || summary.Issuer != "CN=moribito test CA" {
+		t.Errorf("Unexpected subject %q and issuer %q", summary.Subject, summary.Issuer)
+	}
+	if summary.SerialNumber != "02" {
+		t.Errorf("Expected serial 02, got %q", summary.SerialNumber)
+	}
+	if !summary.NotAfter.After(summary.NotBefore) || summary.NotAfter.Location() != time.UTC {
+		t.Errorf("Unexpected validity %v to %v", summary.NotBefore, summary.NotAfter)
+	}
+	if len(summary.Fingerprint) != 32*3-1 || strings.Count(summary.Fingerprint, ":") != 31 {
+		t.Errorf("Expected a colon-separated SHA-256 fingerprint, got %q", summary.Fingerprint)
+	}
+
+	if _, err := SummarizeCertificate("not a certificate"); err == nil {
+		t.Error("Expected an error for a value that isn't a certificate")
+	}
+}
+
+func TestCertificateValidity(t *testing.T) {
+	summary := CertificateSummary{
+		NotBefore: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), "not valid until 2025-01-01"},
+		{time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "expires in 1 day"},
+		{time.Date(2026, 1, 11, 12, 0, 0, 0, time.UTC), "expired 10 days ago"},
+	}
+	for _, tt := range tests {
+		if got := summary.Validity(tt.now); got != tt.want {
+			t.Errorf("At %v expected %q, got %q", tt.now, tt.want, got)
+		}
+	}
+}
+
+func TestCertificatePEM(t *testing.T) {
+	der := newTestCA(t).cert.Raw
+	block, rest := pem.Decode(CertificatePEM(string(der)))
+	if block == nil || block.Type != "CERTIFICATE" || string(block.Bytes) != string(der) || len(rest) != 0 {
+		t.Errorf("Expected a single CERTIFICATE block holding the DER, got %+v", block)
+	}
+}
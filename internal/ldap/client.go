@@ -1,21 +1,43 @@
 package ldap
 
 import (
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-ldap/ldap/v3"
 )
 
+// ErrEntryNotFound is returned when an entry read by its DN doesn't exist
+var ErrEntryNotFound = errors.New("entry not found")
+
 // Client wraps the LDAP connection and provides higher-level operations
 type Client struct {
-	conn   *ldap.Conn
-	baseDN string
-	config Config // Store the configuration for reconnection
+	conn         *ldap.Conn
+	connMu       sync.RWMutex // Guards conn, which reconnect replaces while background operations use it
+	reconnectMu  sync.Mutex   // Lets one reconnect run at a time
+	baseDN       string
+	config       Config                      // Store the configuration for reconnection
+	schema       *Schema                     // Cached server schema, loaded on demand
+	capabilities *Capabilities               // Root DSE capabilities, nil until loaded
+	rights       map[string]*EffectiveRights // Bound user's effective rights by lower-cased DN, see CachedRights
+	cacheMu      sync.RWMutex                // Guards schema, capabilities and rights, which are loaded in the background
+	onRetry      func(RetryEvent)
+	limiter      *rateLimiter // Paces bulk and recursive operations, nil without a limit
+	labelAttr    string       // Attribute GetChildren reads tree labels from, empty for the RDN
+	childFilter  string       // Filter GetChildren lists children with, empty for every child
+	deref        int          // Alias dereferencing of Search and SearchPaged, see Config.DerefAliases
+	userOnly     atomic.Bool  // GetEntry leaves operational attributes out, see SetOperationalAttributes
+
+	audit    *AuditLog // Records write operations, nil when auditing is off
+	auditErr error     // Last audit log write failure, see TakeAuditError
+	auditMu  sync.Mutex
 }
 
 // Config contains LDAP connection parameters
@@ -25,20 +47,111 @@ type Config struct {
 	BaseDN         string
 	UseSSL         bool
 	UseTLS         bool
+	VerifyTLS      bool   // Verify the server certificate and host name for LDAPS and StartTLS
+	CACertPath     string // PEM file or directory of CAs to trust instead of the system roots
 	BindUser       string
 	BindPass       string
 	RetryEnabled   bool
 	MaxRetries     int
 	InitialDelayMs int
 	MaxDelayMs     int
+
+	// Bulk and recursive operations run at most this many times a second; 0 for no limit
+	OperationsPerSecond float64
+
+	// How searches follow alias entries: never (the default), search, find or always
+	DerefAliases string
+
+	// Add, Modify and Delete are refused with ErrReadOnly, for browsing
+	// directories that must not be changed
+	ReadOnly bool
+
+	// SASL GSSAPI (Kerberos) settings, used when BindMethod is BindMethodGSSAPI
+	BindMethod     string
+	KerberosRealm  string // Realm of BindUser; empty uses the krb5.conf default
+	KerberosKeytab string // Keytab holding BindUser's key
+	KerberosConfig string // krb5.conf path; empty uses $KRB5_CONFIG or /etc/krb5.conf
+
+	// Audit log settings; write operations are not recorded when AuditLogPath is empty
+	AuditLogPath   string
+	ConnectionName string
 }
 
 // Entry represents an LDAP entry with its attributes
 type Entry struct {
 	DN         string
 	Attributes map[string][]string
+	Order      []string // Attribute names in the order the server returned them
+
+	// Offset of the next values of attributes the server returned only in
+	// part (Active Directory range retrieval), see LoadMoreValues
+	MoreValues map[string]int
+
+	// Set when the entry came back without attributes, to explain why
+	EmptyReason EmptyReason
+	Referrals   []string // Referral URLs when EmptyReason is EmptyReasonReferral
+}
+
+// NonEmpty returns a copy of the entry without zero-length values, dropping
+// attributes that have no values left
+func (e *Entry) NonEmpty() *Entry {
+	attributes := make(map[string][]string, len(e.Attributes))
+	for name, values := range e.Attributes {
+		var kept []string
+		for _, value := range values {
+			if value != "" {
+				kept = append(kept, value)
+			}
+		}
+		if len(kept) > 0 {
+			attributes[name] = kept
+		}
+	}
+
+	entry := *e
+	entry.Attributes = attributes
+	return &entry
+}
+
+// AttributeKey returns the name the entry holds the attribute called name
+// under. Attribute names are case-insensitive, so a name differing in case or
+// in surrounding whitespace finds the attribute; an exact match is preferred.
+func (e *Entry) AttributeKey(name string) (string, bool) {
+	return foldKey(e.Attributes, name)
 }
 
+// Lookup returns the values of the attribute called name, matched as
+// AttributeKey does
+func (e *Entry) Lookup(name string) ([]string, bool) {
+	key, ok := e.AttributeKey(name)
+	return e.Attributes[key], ok
+}
+
+// foldKey returns the key of m matching name without regard to case or
+// surrounding whitespace, preferring an exact match
+func foldKey[V any](m map[string]V, name string) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+	name = strings.TrimSpace(name)
+	for key := range m {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// EmptyReason explains why an entry has no attributes
+type EmptyReason int
+
+const (
+	EmptyReasonNone                 EmptyReason = iota // The entry has attributes
+	EmptyReasonNoReadableAttributes                    // The server returned only the DN
+	EmptyReasonAccessDenied                            // Reading the entry was refused
+	EmptyReasonReferral                                // The entry lives on another server
+)
+
 // SearchPage represents a page of search results with pagination info
 type SearchPage struct {
 	Entries    []*Entry
@@ -48,12 +161,95 @@ type SearchPage struct {
 	TotalCount int // -1 if unknown
 }
 
+// SearchParams are the parameters a result set was searched with, so it can
+// be labelled with exactly how it was produced
+type SearchParams struct {
+	BaseDN     string
+	Scope      int
+	Filter     string
+	Attributes []string // Empty requests all user attributes
+}
+
+// filterLineBreak matches a line break in a filter and the indentation around it
+var filterLineBreak = regexp.MustCompile(`[ \t]*\r?\n[ \t]*`)
+
+// String formats the parameters on one line
+func (p SearchParams) String() string {
+	attributes := "*"
+	if len(p.Attributes) > 0 {
+		attributes = strings.Join(p.Attributes, ", ")
+	}
+	return fmt.Sprintf("base: %s | scope: %s | filter: %s | attributes: %s",
+		p.BaseDN, ScopeName(p.Scope), p.OneLineFilter(), attributes)
+}
+
+// OneLineFilter returns the filter on one line. Line breaks from formatting
+// it over several lines are dropped; they don't change what it matches.
+func (p SearchParams) OneLineFilter() string {
+	return filterLineBreak.ReplaceAllString(strings.TrimSpace(p.Filter), "")
+}
+
+// ScopeName returns the short name of a search scope: base, one or sub
+func ScopeName(scope int) string {
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return "base"
+	case ldap.ScopeSingleLevel:
+		return "one"
+	case ldap.ScopeWholeSubtree:
+		return "sub"
+	default:
+		return fmt.Sprintf("%d", scope)
+	}
+}
+
 // TreeNode represents a node in the LDAP tree
 type TreeNode struct {
 	DN       string
 	Name     string
+	Label    string // Value of the tree label attribute, shown instead of Name when set
+	Alias    string // DN the entry points to when it is an alias
 	Children []*TreeNode
 	IsLoaded bool
+	// Collapsed hides loaded children in the tree view without discarding them
+	Collapsed bool
+}
+
+// ShortBindIdentity returns a compact form of the bind identity for display:
+// the first RDN value of a DN, a UPN or DOMAIN\user name as is, and anonymous
+// when there is no bind user
+func (config Config) ShortBindIdentity() string {
+	user := strings.TrimSpace(config.BindUser)
+	if user == "" {
+		if strings.EqualFold(config.BindMethod, BindMethodGSSAPI) {
+			return "Kerberos ticket"
+		}
+		return "anonymous"
+	}
+	if !strings.Contains(user, "=") {
+		return user
+	}
+	if dn, err := ldap.ParseDN(user); err == nil && len(dn.RDNs) > 0 && len(dn.RDNs[0].Attributes) > 0 {
+		return dn.RDNs[0].Attributes[0].Value
+	}
+	return user
+}
+
+// address returns the host and port to dial. IPv6 literals are bracketed,
+// so ::1 dials [::1]:389 rather than the ambiguous ::1:389, and a host that
+// is already bracketed is left as it is.
+func (config Config) address() string {
+	return net.JoinHostPort(config.hostName(), strconv.Itoa(config.Port))
+}
+
+// hostName returns the configured host without the brackets an IPv6
+// literal may be written with
+func (config Config) hostName() string {
+	host := config.Host
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+	return host
 }
 
 // NewClient creates a new LDAP client
@@ -61,23 +257,33 @@ func NewClient(config Config) (*Client, error) {
 	var conn *ldap.Conn
 	var err error
 
-	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	address := config.address()
+
+	deref, err := ParseDerefAliases(config.DerefAliases)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
 
 	if config.UseSSL {
-		conn, err = ldap.DialTLS("tcp", address, &tls.Config{InsecureSkipVerify: true})
+		conn, err = ldap.DialTLS("tcp", address, tlsConfig)
 	} else {
 		conn, err = ldap.Dial("tcp", address)
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to LDAP server: %w", err)
+		return nil, config.explainTLS(fmt.Errorf("failed to connect to LDAP server: %w", err))
 	}
 
 	if config.UseTLS && !config.UseSSL {
-		err = conn.StartTLS(&tls.Config{InsecureSkipVerify: true})
+		err = conn.StartTLS(tlsConfig)
 		if err != nil {
 			conn.Close()
-			return nil, fmt.Errorf("failed to start TLS: %w", err)
+			return nil, config.explainTLS(fmt.Errorf("failed to start TLS: %w", err))
 		}
 	}
 
@@ -85,26 +291,51 @@ func NewClient(config Config) (*Client, error) {
 		conn:   conn,
 		baseDN: config.BaseDN,
 		config: config, // Store config for reconnection
+		deref:  deref,
+	}
+	if config.AuditLogPath != "" {
+		client.audit = NewAuditLog(config.AuditLogPath, config.ConnectionName)
+	}
+	if config.OperationsPerSecond > 0 {
+		client.limiter = newRateLimiter(config.OperationsPerSecond)
 	}
 
 	// Bind with provided credentials
-	if config.BindUser != "" {
-		err = conn.Bind(config.BindUser, config.BindPass)
-		if err != nil {
-			conn.Close()
-			return nil, fmt.Errorf("failed to bind: %w", err)
-		}
+	if err := config.bind(conn); err != nil {
+		conn.Close()
+		return nil, &BindError{Err: config.explainAnonymous(err)}
 	}
 
 	return client, nil
 }
 
+// BindError is returned by NewClient when the server was reached but the
+// bind failed, so callers can tell bad credentials from an unreachable server
+type BindError struct {
+	Err error
+}
+
+// Error returns the bind failure
+func (e *BindError) Error() string {
+	return "failed to bind: " + e.Err.Error()
+}
+
+// Unwrap returns the error from the bind
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
 // isRetryableError checks if an error is retryable (connection-related)
 func (c *Client) isRetryableError(err error) bool {
 	if err == nil {
 		return false
 	}
 
+	// The server announced it was closing the connection
+	if IsNoticeOfDisconnection(err) {
+		return true
+	}
+
 	// Check for common connection errors
 	var netErr net.Error
 	if errors.As(err, &netErr) {
@@ -144,52 +375,132 @@ func (c *Client) isRetryableError(err error) bool {
 	return false
 }
 
-// reconnect attempts to re-establish the LDAP connection
-func (c *Client) reconnect() error {
+// connection returns the current LDAP connection. Operations take it once,
+// so a reconnect on another goroutine doesn't swap it from under them.
+func (c *Client) connection() *ldap.Conn {
+	c.connMu.RLock()
+	defer c.connMu.RUnlock()
+	return c.conn
+}
+
+// reconnect attempts to re-establish the LDAP connection that stale was
+// found broken on. Operations running in the background may find it broken
+// at the same time: only the first reconnects, and the others carry on with
+// the connection it made.
+func (c *Client) reconnect(stale *ldap.Conn) error {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+	if c.connection() != stale {
+		return nil
+	}
+
 	// Close existing connection if any
-	if c.conn != nil {
-		c.conn.Close()
+	if stale != nil {
+		stale.Close()
 	}
 
 	// Re-establish connection using stored config
 	var conn *ldap.Conn
 	var err error
 
-	address := fmt.Sprintf("%s:%d", c.config.Host, c.config.Port)
+	address := c.config.address()
+
+	tlsConfig, err := c.config.tlsConfig()
+	if err != nil {
+		return err
+	}
 
 	if c.config.UseSSL {
-		conn, err = ldap.DialTLS("tcp", address, &tls.Config{InsecureSkipVerify: true})
+		conn, err = ldap.DialTLS("tcp", address, tlsConfig)
 	} else {
 		conn, err = ldap.Dial("tcp", address)
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to reconnect to LDAP server: %w", err)
+		return c.config.explainTLS(fmt.Errorf("failed to reconnect to LDAP server: %w", err))
 	}
 
 	if c.config.UseTLS && !c.config.UseSSL {
-		err = conn.StartTLS(&tls.Config{InsecureSkipVerify: true})
+		err = conn.StartTLS(tlsConfig)
 		if err != nil {
 			conn.Close()
-			return fmt.Errorf("failed to start TLS on reconnect: %w", err)
+			return c.config.explainTLS(fmt.Errorf("failed to start TLS on reconnect: %w", err))
 		}
 	}
 
 	// Re-bind with credentials if needed
-	if c.config.BindUser != "" {
-		err = conn.Bind(c.config.BindUser, c.config.BindPass)
-		if err != nil {
-			conn.Close()
-			return fmt.Errorf("failed to bind on reconnect: %w", err)
-		}
+	if err := c.config.bind(conn); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to bind on reconnect: %w", c.config.explainAnonymous(err))
 	}
 
+	c.connMu.Lock()
 	c.conn = conn
+	c.connMu.Unlock()
 	return nil
 }
 
+// RetryEvent describes an operation that failed with a connection error and
+// is about to be retried, or a reconnect the server asked for
+type RetryEvent struct {
+	Attempt         int           // Number of the attempt about to be made, 2 for the first retry
+	MaxAttempts     int           // Attempts made in total before giving up
+	Delay           time.Duration // Wait before the attempt
+	Err             error         // Error of the failed attempt
+	ServerRequested bool          // The server sent a notice of disconnection
+}
+
+// String describes the retry for the status bar
+func (e RetryEvent) String() string {
+	reason := "Connection lost"
+	if e.ServerRequested {
+		reason = "Server requested reconnect"
+	}
+	if e.Attempt == 0 {
+		return reason + ", reconnecting"
+	}
+	return fmt.Sprintf("%s, retrying (attempt %d/%d, waiting %s)", reason, e.Attempt, e.MaxAttempts, e.Delay)
+}
+
+// SetRetryHandler sets a function called before each retry of an operation.
+// It runs on the goroutine performing the operation, so it must not block.
+func (c *Client) SetRetryHandler(fn func(RetryEvent)) {
+	c.onRetry = fn
+}
+
+// SetTreeLabelAttribute sets the attribute GetChildren reads each child's
+// label from, such as displayName for user containers. Empty labels
+// children by their RDN only.
+func (c *Client) SetTreeLabelAttribute(name string) {
+	c.labelAttr = strings.TrimSpace(name)
+}
+
+// SetTreeChildFilter sets the filter GetChildren lists children with, such
+// as (!(objectClass=computer)) to leave computers out of the tree. Empty
+// lists every child, and so does an invalid filter, which is reported.
+func (c *Client) SetTreeChildFilter(filter string) error {
+	filter = strings.TrimSpace(filter)
+	if filter != "" {
+		if _, err := ldap.CompileFilter(filter); err != nil {
+			c.childFilter = ""
+			return fmt.Errorf("tree child filter %q is invalid: %w", filter, err)
+		}
+	}
+	c.childFilter = filter
+	return nil
+}
+
+// TreeChildFilter returns the filter GetChildren searches with
+func (c *Client) TreeChildFilter() string {
+	if c.childFilter == "" {
+		return "(objectClass=*)"
+	}
+	return c.childFilter
+}
+
 // withRetry executes an operation with retry logic
 func (c *Client) withRetry(operation func() error) error {
+	c.reconnectIfServerRequested()
 	if !c.config.RetryEnabled {
 		return operation()
 	}
@@ -199,6 +510,7 @@ func (c *Client) withRetry(operation func() error) error {
 	maxDelay := time.Duration(c.config.MaxDelayMs) * time.Millisecond
 
 	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+		conn := c.connection()
 		err := operation()
 		if err == nil {
 			return nil // Success
@@ -211,8 +523,12 @@ func (c *Client) withRetry(operation func() error) error {
 			break
 		}
 
+		if c.onRetry != nil {
+			c.onRetry(RetryEvent{Attempt: attempt + 2, MaxAttempts: c.config.MaxRetries + 1, Delay: delay, Err: err, ServerRequested: conn != nil && IsNoticeOfDisconnection(conn.GetLastError())})
+		}
+
 		// Try to reconnect for retryable errors
-		if reconnectErr := c.reconnect(); reconnectErr != nil {
+		if reconnectErr := c.reconnect(conn); reconnectErr != nil {
 			// If reconnection fails, continue with the original error
 			// but don't attempt more retries
 			break
@@ -231,15 +547,31 @@ func (c *Client) withRetry(operation func() error) error {
 	return lastErr
 }
 
-// Close closes the LDAP connection
+// Close unbinds from the server and closes the LDAP connection, so the
+// server isn't left holding it. Closing a closed client does nothing.
 func (c *Client) Close() {
-	if c.conn != nil {
-		c.conn.Close()
+	conn := c.connection()
+	if conn == nil || conn.IsClosing() {
+		return
+	}
+	if err := conn.Unbind(); err != nil {
+		conn.Close()
 	}
 }
 
-// Search performs an LDAP search
+// Closed reports whether the connection has been closed
+func (c *Client) Closed() bool {
+	conn := c.connection()
+	return conn == nil || conn.IsClosing()
+}
+
+// Search performs an LDAP search, following aliases as configured
 func (c *Client) Search(baseDN, filter string, scope int, attributes []string) ([]*Entry, error) {
+	return c.search(baseDN, filter, scope, c.deref, attributes)
+}
+
+// search performs an LDAP search with the given alias dereferencing
+func (c *Client) search(baseDN, filter string, scope, deref int, attributes []string) ([]*Entry, error) {
 	var result *ldap.SearchResult
 	var entries []*Entry
 
@@ -247,7 +579,7 @@ func (c *Client) Search(baseDN, filter string, scope int, attributes []string) (
 		searchRequest := ldap.NewSearchRequest(
 			baseDN,
 			scope,
-			ldap.NeverDerefAliases,
+			deref,
 			0, // No size limit
 			0, // No time limit
 			false,
@@ -257,7 +589,7 @@ func (c *Client) Search(baseDN, filter string, scope int, attributes []string) (
 		)
 
 		var err error
-		result, err = c.conn.Search(searchRequest)
+		result, err = c.connection().Search(searchRequest)
 		if err != nil {
 			return fmt.Errorf("search failed: %w", err)
 		}
@@ -265,16 +597,7 @@ func (c *Client) Search(baseDN, filter string, scope int, attributes []string) (
 		// Process results inside the retry function to ensure consistency
 		entries = make([]*Entry, 0, len(result.Entries))
 		for _, entry := range result.Entries {
-			e := &Entry{
-				DN:         entry.DN,
-				Attributes: make(map[string][]string),
-			}
-
-			for _, attr := range entry.Attributes {
-				e.Attributes[attr.Name] = attr.Values
-			}
-
-			entries = append(entries, e)
+			entries = append(entries, convertEntry(entry))
 		}
 
 		return nil
@@ -283,8 +606,22 @@ func (c *Client) Search(baseDN, filter string, scope int, attributes []string) (
 	return entries, err
 }
 
-// SearchPaged performs a paginated LDAP search
+// SearchPaged performs a paginated LDAP search. Servers known not to support
+// the paged results control get a plain search returned as a single page.
 func (c *Client) SearchPaged(baseDN, filter string, scope int, attributes []string, pageSize uint32, cookie []byte) (*SearchPage, error) {
+	if !c.Supports(OIDPagedResults) {
+		entries, err := c.Search(baseDN, filter, scope, attributes)
+		if err != nil {
+			return nil, err
+		}
+		return &SearchPage{
+			Entries:    entries,
+			HasMore:    false,
+			PageSize:   pageSize,
+			TotalCount: len(entries),
+		}, nil
+	}
+
 	var result *ldap.SearchResult
 	var searchPage *SearchPage
 
@@ -298,7 +635,7 @@ func (c *Client) SearchPaged(baseDN, filter string, scope int, attributes []stri
 		searchRequest := ldap.NewSearchRequest(
 			baseDN,
 			scope,
-			ldap.NeverDerefAliases,
+			c.deref,
 			0, // No size limit - controlled by paging
 			0, // No time limit
 			false,
@@ -308,7 +645,7 @@ func (c *Client) SearchPaged(baseDN, filter string, scope int, attributes []stri
 		)
 
 		var err error
-		result, err = c.conn.Search(searchRequest)
+		result, err = c.connection().Search(searchRequest)
 		if err != nil {
 			return fmt.Errorf("paged search failed: %w", err)
 		}
@@ -316,16 +653,7 @@ func (c *Client) SearchPaged(baseDN, filter string, scope int, attributes []stri
 		// Parse entries inside the retry function
 		entries := make([]*Entry, 0, len(result.Entries))
 		for _, entry := range result.Entries {
-			e := &Entry{
-				DN:         entry.DN,
-				Attributes: make(map[string][]string),
-			}
-
-			for _, attr := range entry.Attributes {
-				e.Attributes[attr.Name] = attr.Values
-			}
-
-			entries = append(entries, e)
+			entries = append(entries, convertEntry(entry))
 		}
 
 		// Extract paging control from response
@@ -356,14 +684,21 @@ func (c *Client) SearchPaged(baseDN, filter string, scope int, attributes []stri
 	return searchPage, err
 }
 
-// GetChildren returns immediate children of a DN
+// GetChildren returns the immediate children of a DN that match the tree's
+// child filter
 func (c *Client) GetChildren(dn string) ([]*TreeNode, error) {
 	searchDN := dn
 	if searchDN == "" {
 		searchDN = c.baseDN
 	}
 
-	entries, err := c.Search(searchDN, "(objectClass=*)", ldap.ScopeSingleLevel, []string{"dn"})
+	// Only aliases have aliasedObjectName, so other entries come back without
+	// attributes. Aliases are never followed: the tree shows them as stored.
+	attributes := []string{AliasedObjectNameAttribute}
+	if c.labelAttr != "" {
+		attributes = append(attributes, c.labelAttr)
+	}
+	entries, err := c.search(searchDN, c.TreeChildFilter(), ldap.ScopeSingleLevel, ldap.NeverDerefAliases, attributes)
 	if err != nil {
 		return nil, err
 	}
@@ -374,6 +709,8 @@ func (c *Client) GetChildren(dn string) ([]*TreeNode, error) {
 		node := &TreeNode{
 			DN:       entry.DN,
 			Name:     name,
+			Label:    entryLabel(entry, c.labelAttr),
+			Alias:    entry.AliasTarget(),
 			Children: nil,
 			IsLoaded: false,
 		}
@@ -383,18 +720,174 @@ func (c *Client) GetChildren(dn string) ([]*TreeNode, error) {
 	return nodes, nil
 }
 
-// GetEntry retrieves a specific LDAP entry with all its attributes
+// IsNoSuchObject reports whether err is the server saying the DN searched or
+// read doesn't exist
+func IsNoSuchObject(err error) bool {
+	return ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject)
+}
+
+// IsEntryNotFound reports whether reading an entry failed because it doesn't
+// exist, whether the server said so or simply returned nothing
+func IsEntryNotFound(err error) bool {
+	return errors.Is(err, ErrEntryNotFound) || IsNoSuchObject(err)
+}
+
+// entryLabel returns the first non-blank value of the label attribute, empty
+// when the entry doesn't have one
+func entryLabel(entry *Entry, attribute string) string {
+	if attribute == "" {
+		return ""
+	}
+	for name, values := range entry.Attributes {
+		if !strings.EqualFold(name, attribute) {
+			continue
+		}
+		for _, value := range values {
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// GetEntry retrieves a specific LDAP entry with all its attributes, or only
+// its user attributes after SetOperationalAttributes(false)
 func (c *Client) GetEntry(dn string) (*Entry, error) {
-	entries, err := c.Search(dn, "(objectClass=*)", ldap.ScopeBaseObject, []string{"*", "+"})
-	if err != nil {
+	if c.userOnly.Load() {
+		return c.getEntry(dn, []string{"*"})
+	}
+	return c.getEntry(dn, []string{"*", "+"})
+}
+
+// SetOperationalAttributes sets whether GetEntry asks for operational
+// attributes along with the user attributes. Without them the server leaves
+// out createTimestamp, entryUUID and the like, so only populated user
+// attributes come back.
+func (c *Client) SetOperationalAttributes(include bool) {
+	if c == nil {
+		return
+	}
+	c.userOnly.Store(!include)
+}
+
+// GetEntryAttributes retrieves an entry requesting the named attributes
+// explicitly, for servers that leave some attributes out of a "*" search
+func (c *Client) GetEntryAttributes(dn string, attributes []string) (*Entry, error) {
+	return c.getEntry(dn, attributes)
+}
+
+// RawEntryAttributes are requested by GetRawEntry: every user and operational
+// attribute, plus the DN attributes some servers only return by name
+var RawEntryAttributes = []string{"*", "+", "entryDN", "distinguishedName"}
+
+// GetRawEntry retrieves an entry with everything the server will return, for
+// inspecting it exactly as stored
+func (c *Client) GetRawEntry(dn string) (*Entry, error) {
+	return c.getEntry(dn, RawEntryAttributes)
+}
+
+// getEntry reads a single entry. Access errors and referrals are returned as
+// an entry without attributes whose EmptyReason says what happened.
+// Aliases are never followed, so an alias is read as stored.
+func (c *Client) getEntry(dn string, attributes []string) (*Entry, error) {
+	var result *ldap.SearchResult
+
+	err := c.withRetry(func() error {
+		searchRequest := ldap.NewSearchRequest(
+			dn,
+			ldap.ScopeBaseObject,
+			ldap.NeverDerefAliases,
+			0, // No size limit
+			0, // No time limit
+			false,
+			"(objectClass=*)",
+			attributes,
+			nil,
+		)
+
+		var err error
+		result, err = c.connection().Search(searchRequest)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+		return nil
+	})
+
+	return entryFromResult(dn, result, err)
+}
+
+// entryFromResult converts the result of a base search for dn into an entry
+func entryFromResult(dn string, result *ldap.SearchResult, err error) (*Entry, error) {
+	var referrals []string
+	if result != nil {
+		referrals = result.Referrals
+	}
+
+	switch {
+	case ldap.IsErrorWithCode(err, ldap.LDAPResultInsufficientAccessRights):
+		return &Entry{DN: dn, Attributes: map[string][]string{}, EmptyReason: EmptyReasonAccessDenied}, nil
+	case ldap.IsErrorWithCode(err, ldap.LDAPResultReferral):
+		return &Entry{DN: dn, Attributes: map[string][]string{}, EmptyReason: EmptyReasonReferral, Referrals: referrals}, nil
+	case err != nil:
 		return nil, err
 	}
 
-	if len(entries) == 0 {
-		return nil, fmt.Errorf("entry not found: %s", dn)
+	if len(result.Entries) == 0 {
+		if len(referrals) > 0 {
+			return &Entry{DN: dn, Attributes: map[string][]string{}, EmptyReason: EmptyReasonReferral, Referrals: referrals}, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, dn)
 	}
 
-	return entries[0], nil
+	return convertEntry(result.Entries[0]), nil
+}
+
+// convertEntry converts a go-ldap entry, noting when it has no attributes
+func convertEntry(entry *ldap.Entry) *Entry {
+	e := &Entry{
+		DN:         entry.DN,
+		Attributes: make(map[string][]string),
+	}
+
+	for _, attr := range entry.Attributes {
+		name := attr.Name
+		if base, _, end, ok := ParseRangeOption(name); ok {
+			name = base
+			if end >= 0 {
+				if e.MoreValues == nil {
+					e.MoreValues = make(map[string]int)
+				}
+				e.MoreValues[name] = end + 1
+			}
+		}
+		if _, seen := e.Attributes[name]; !seen {
+			e.Order = append(e.Order, name)
+		}
+		e.Attributes[name] = attr.Values
+	}
+
+	if len(e.Attributes) == 0 {
+		e.EmptyReason = EmptyReasonNoReadableAttributes
+	}
+
+	return e
+}
+
+// Config returns the settings the client connected with
+func (c *Client) Config() Config {
+	return c.config
+}
+
+// BaseDN returns the base DN the tree and unscoped searches start from
+func (c *Client) BaseDN() string {
+	return c.baseDN
+}
+
+// SetBaseDN changes the base DN used by later tree builds and unscoped
+// searches. The connection itself is unaffected.
+func (c *Client) SetBaseDN(baseDN string) {
+	c.baseDN = baseDN
 }
 
 // BuildTree builds the complete LDAP tree starting from baseDN
@@ -409,6 +902,28 @@ func (c *Client) BuildTree() (*TreeNode, error) {
 	return root, nil
 }
 
+// BuildNamingContextTrees builds one tree root per naming context the server
+// advertises in its root DSE, so directories that hold several suffixes (and
+// cn=config) can be explored side by side. The base DN is not used.
+func (c *Client) BuildNamingContextTrees() ([]*TreeNode, error) {
+	caps := c.Capabilities()
+	if caps == nil {
+		var err error
+		if caps, err = c.LoadCapabilities(); err != nil {
+			return nil, err
+		}
+	}
+	if len(caps.NamingContexts) == 0 {
+		return nil, fmt.Errorf("the server doesn't advertise any naming contexts")
+	}
+
+	roots := make([]*TreeNode, 0, len(caps.NamingContexts))
+	for _, context := range caps.NamingContexts {
+		roots = append(roots, &TreeNode{DN: context, Name: context})
+	}
+	return roots, nil
+}
+
 // LoadChildren loads children for a tree node if not already loaded
 func (c *Client) LoadChildren(node *TreeNode) error {
 	if node.IsLoaded {
@@ -432,7 +947,36 @@ func (c *Client) CustomSearch(filter string) ([]*Entry, error) {
 
 // CustomSearchPaged performs a paginated custom LDAP search with user-provided filter
 func (c *Client) CustomSearchPaged(filter string, pageSize uint32, cookie []byte) (*SearchPage, error) {
-	return c.SearchPaged(c.baseDN, filter, ldap.ScopeWholeSubtree, []string{"*"}, pageSize, cookie)
+	return c.CustomSearchPagedIn("", filter, nil, pageSize, cookie)
+}
+
+// IsPagingCookieError reports whether err is the server refusing a paged
+// results cookie, typically because it expired or the connection was re-established
+func IsPagingCookieError(err error) bool {
+	return ldap.IsErrorAnyOf(err,
+		ldap.LDAPResultUnwillingToPerform,
+		ldap.LDAPResultProtocolError,
+		ldap.LDAPResultOperationsError)
+}
+
+// CustomSearchPagedIn performs a paginated subtree search under baseDN returning
+// the given attributes. An empty baseDN searches from the configured base DN and
+// no attributes returns all user attributes.
+func (c *Client) CustomSearchPagedIn(baseDN, filter string, attributes []string, pageSize uint32, cookie []byte) (*SearchPage, error) {
+	params := c.CustomSearchParams(baseDN, filter, attributes)
+	return c.SearchPaged(params.BaseDN, params.Filter, params.Scope, params.Attributes, pageSize, cookie)
+}
+
+// CustomSearchParams returns the parameters CustomSearchPagedIn searches
+// with, the defaults for an empty base DN and attribute list filled in
+func (c *Client) CustomSearchParams(baseDN, filter string, attributes []string) SearchParams {
+	if baseDN == "" {
+		baseDN = c.baseDN
+	}
+	if len(attributes) == 0 {
+		attributes = []string{"*"}
+	}
+	return SearchParams{BaseDN: baseDN, Scope: ldap.ScopeWholeSubtree, Filter: filter, Attributes: attributes}
 }
 
 // extractName extracts the relative name from a DN
@@ -2,10 +2,13 @@ package ldap
 
 import (
 	"errors"
+	"fmt"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
 	"github.com/go-ldap/ldap/v3"
 )
 
@@ -342,3 +345,394 @@ func TestWithRetryFailureAfterMaxAttempts(t *testing.T) {
 		t.Errorf("Expected original error, got %v", err)
 	}
 }
+
+func TestEntryFromResult(t *testing.T) {
+	dn := "uid=jdoe,ou=people,dc=example,dc=com"
+
+	denied := fmt.Errorf("search failed: %w", ldap.NewError(ldap.LDAPResultInsufficientAccessRights, errors.New("access denied")))
+	entry, err := entryFromResult(dn, nil, denied)
+	if err != nil || entry.EmptyReason != EmptyReasonAccessDenied || entry.DN != dn {
+		t.Errorf("Expected access denied entry, got %+v, %v", entry, err)
+	}
+
+	referral := ldap.NewError(ldap.LDAPResultReferral, errors.New("referral"))
+	entry, err = entryFromResult(dn, &ldap.SearchResult{Referrals: []string{"ldap://other.example.com/" + dn}}, referral)
+	if err != nil || entry.EmptyReason != EmptyReasonReferral || len(entry.Referrals) != 1 {
+		t.Errorf("Expected referral entry, got %+v, %v", entry, err)
+	}
+
+	// Continuation references without entries are also referrals
+	entry, err = entryFromResult(dn, &ldap.SearchResult{Referrals: []string{"ldap://other.example.com/"}}, nil)
+	if err != nil || entry.EmptyReason != EmptyReasonReferral {
+		t.Errorf("Expected referral entry from continuation reference, got %+v, %v", entry, err)
+	}
+
+	if _, err := entryFromResult(dn, &ldap.SearchResult{}, nil); err == nil || !strings.Contains(err.Error(), "entry not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+
+	other := ldap.NewError(ldap.LDAPResultBusy, errors.New("busy"))
+	if _, err := entryFromResult(dn, nil, other); err != other {
+		t.Errorf("Expected other errors to be returned as-is, got %v", err)
+	}
+
+	entry, err = entryFromResult(dn, &ldap.SearchResult{Entries: []*ldap.Entry{ldap.NewEntry(dn, nil)}}, nil)
+	if err != nil || entry.EmptyReason != EmptyReasonNoReadableAttributes {
+		t.Errorf("Expected DN-only entry to be flagged, got %+v, %v", entry, err)
+	}
+
+	entry, _ = entryFromResult(dn, &ldap.SearchResult{Entries: []*ldap.Entry{ldap.NewEntry(dn, map[string][]string{"cn": {"John"}})}}, nil)
+	if entry.EmptyReason != EmptyReasonNone || entry.Attributes["cn"][0] != "John" {
+		t.Errorf("Expected normal entry, got %+v", entry)
+	}
+}
+
+func TestIsPagingCookieError(t *testing.T) {
+	invalid := fmt.Errorf("paged search failed: %w", ldap.NewError(ldap.LDAPResultUnwillingToPerform, errors.New("paged results cookie is invalid")))
+	if !IsPagingCookieError(invalid) {
+		t.Error("Expected a rejected cookie to be recognised")
+	}
+	if IsPagingCookieError(ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("no such object"))) {
+		t.Error("Expected other result codes not to be cookie errors")
+	}
+	if IsPagingCookieError(errors.New("connection reset")) || IsPagingCookieError(nil) {
+		t.Error("Expected non-LDAP errors not to be cookie errors")
+	}
+}
+
+func TestShortBindIdentity(t *testing.T) {
+	tests := []struct {
+		config Config
+		want   string
+	}{
+		{Config{BindUser: "cn=admin,dc=example,dc=com"}, "admin"},
+		{Config{BindUser: `uid=j\, doe,ou=people,dc=example,dc=com`}, "j, doe"},
+		{Config{BindUser: "alice@example.com"}, "alice@example.com"},
+		{Config{BindUser: `CORP\alice`}, `CORP\alice`},
+		{Config{}, "anonymous"},
+		{Config{BindMethod: BindMethodGSSAPI}, "Kerberos ticket"},
+		{Config{BindUser: "cn=broken,=x"}, "cn=broken,=x"},
+	}
+	for _, tt := range tests {
+		if got := tt.config.ShortBindIdentity(); got != tt.want {
+			t.Errorf("ShortBindIdentity(%q) = %q, want %q", tt.config.BindUser, got, tt.want)
+		}
+	}
+}
+
+func TestConfigAddress(t *testing.T) {
+	tests := []struct {
+		host string
+		port int
+		want string
+	}{
+		{"ldap.example.com", 389, "ldap.example.com:389"},
+		{"192.0.2.10", 636, "192.0.2.10:636"},
+		{"::1", 389, "[::1]:389"},
+		{"[::1]", 389, "[::1]:389"},
+		{"fe80::1%eth0", 389, "[fe80::1%eth0]:389"},
+		{"2001:db8::10", 636, "[2001:db8::10]:636"},
+	}
+	for _, tt := range tests {
+		if got := (Config{Host: tt.host, Port: tt.port}).address(); got != tt.want {
+			t.Errorf("address() for %q port %d = %q, want %q", tt.host, tt.port, got, tt.want)
+		}
+	}
+
+	tlsConfig, err := Config{Host: "[2001:db8::10]"}.tlsConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsConfig.ServerName != "2001:db8::10" {
+		t.Errorf("Expected the TLS server name without brackets, got %q", tlsConfig.ServerName)
+	}
+}
+
+func TestNewClientDialsIPv6(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	defer listener.Close()
+	accepted := make(chan net.Addr, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		accepted <- conn.LocalAddr()
+		conn.Close()
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	client, err := NewClient(Config{Host: "::1", Port: port})
+	if err == nil {
+		client.Close()
+	}
+	select {
+	case addr := <-accepted:
+		if want := fmt.Sprintf("[::1]:%d", port); addr.String() != want {
+			t.Errorf("Expected the connection on %s, got %s", want, addr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected ::1 to be dialed, NewClient returned %v", err)
+	}
+}
+
+func TestEntryNonEmpty(t *testing.T) {
+	entry := &Entry{
+		DN: "uid=jdoe,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"cn":          {"John Doe"},
+			"description": {""},
+			"mail":        {"", "jdoe@example.com", ""},
+			"seeAlso":     {},
+			"title":       {" "},
+		},
+	}
+
+	filtered := entry.NonEmpty()
+	if filtered.DN != entry.DN {
+		t.Errorf("Expected the DN to be kept, got %q", filtered.DN)
+	}
+	if len(filtered.Attributes) != 3 {
+		t.Errorf("Expected cn, mail and title to remain, got %v", filtered.Attributes)
+	}
+	if _, ok := filtered.Attributes["description"]; ok {
+		t.Error("Expected an attribute with only empty values to be dropped")
+	}
+	if _, ok := filtered.Attributes["seeAlso"]; ok {
+		t.Error("Expected an attribute without values to be dropped")
+	}
+	if got := filtered.Attributes["mail"]; len(got) != 1 || got[0] != "jdoe@example.com" {
+		t.Errorf("Expected empty mail values to be removed, got %v", got)
+	}
+	if got := filtered.Attributes["title"]; len(got) != 1 {
+		t.Errorf("Expected whitespace values to be kept, got %v", got)
+	}
+
+	// The original entry is left untouched
+	if len(entry.Attributes) != 5 || len(entry.Attributes["mail"]) != 3 {
+		t.Errorf("Expected the original entry to be unchanged, got %v", entry.Attributes)
+	}
+}
+
+func TestConvertEntryKeepsServerOrder(t *testing.T) {
+	entry := convertEntry(&ldap.Entry{
+		DN: "uid=jdoe,dc=example,dc=com",
+		Attributes: []*ldap.EntryAttribute{
+			{Name: "objectClass", Values: []string{"person"}},
+			{Name: "uid", Values: []string{"jdoe"}},
+			{Name: "entryUUID", Values: []string{"d3f2"}},
+			{Name: "cn", Values: []string{"John"}},
+		},
+	})
+
+	want := []string{"objectClass", "uid", "entryUUID", "cn"}
+	if strings.Join(entry.Order, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected attributes in server order %v, got %v", want, entry.Order)
+	}
+}
+
+func TestSearchParamsString(t *testing.T) {
+	client := &Client{baseDN: "dc=example,dc=com"}
+
+	params := client.CustomSearchParams("", "(&\n  (objectClass=person)\n  (cn=John  Doe)\n)", nil)
+	want := "base: dc=example,dc=com | scope: sub | filter: (&(objectClass=person)(cn=John  Doe)) | attributes: *"
+	if got := params.String(); got != want {
+		t.Errorf("Unexpected description:\n got %q\nwant %q", got, want)
+	}
+
+	params = SearchParams{BaseDN: "ou=people,dc=example,dc=com", Scope: ldap.ScopeSingleLevel, Filter: "(uid=*)", Attributes: []string{"cn", "mail"}}
+	want = "base: ou=people,dc=example,dc=com | scope: one | filter: (uid=*) | attributes: cn, mail"
+	if got := params.String(); got != want {
+		t.Errorf("Unexpected description:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestBuildNamingContextTrees(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "", Attributes: map[string][]string{"namingContexts": {"dc=a,dc=com", "dc=b,dc=com", "cn=config"}}},
+		ldaptest.Entry{DN: "dc=a,dc=com"},
+		ldaptest.Entry{DN: "ou=people,dc=a,dc=com"},
+		ldaptest.Entry{DN: "dc=b,dc=com"},
+		ldaptest.Entry{DN: "ou=groups,dc=b,dc=com"},
+		ldaptest.Entry{DN: "cn=config"},
+	)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=a,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	roots, err := client.BuildNamingContextTrees()
+	if err != nil {
+		t.Fatalf("BuildNamingContextTrees failed: %v", err)
+	}
+	if len(roots) != 3 || roots[0].DN != "dc=a,dc=com" || roots[1].DN != "dc=b,dc=com" || roots[2].DN != "cn=config" {
+		t.Fatalf("Expected a root per naming context, got %+v", roots)
+	}
+
+	// Each root expands on its own, outside the base DN
+	if err := client.LoadChildren(roots[1]); err != nil {
+		t.Fatalf("LoadChildren failed: %v", err)
+	}
+	if len(roots[1].Children) != 1 || roots[1].Children[0].DN != "ou=groups,dc=b,dc=com" {
+		t.Errorf("Expected dc=b's children, got %+v", roots[1].Children)
+	}
+}
+
+func TestGetChildrenTreeLabels(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "ou=people,dc=example,dc=com"},
+		ldaptest.Entry{DN: "uid=jdoe,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"jdoe"}, "displayName": {"Jane Doe"}}},
+		ldaptest.Entry{DN: "uid=svc,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"svc"}, "displayName": {"  "}}},
+		ldaptest.Entry{DN: "uid=old,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"old"}}},
+	)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "ou=people,dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	labels := func() map[string]string {
+		children, err := client.GetChildren("")
+		if err != nil {
+			t.Fatalf("GetChildren failed: %v", err)
+		}
+		labels := make(map[string]string)
+		for _, child := range children {
+			labels[child.Name] = child.Label
+		}
+		return labels
+	}
+
+	if got := labels(); got["uid=jdoe"] != "" {
+		t.Errorf("Expected no labels without a label attribute, got %v", got)
+	}
+
+	// The attribute is requested by name, so operational attributes work too
+	client.SetTreeLabelAttribute("displayname")
+	got := labels()
+	if got["uid=jdoe"] != "Jane Doe" {
+		t.Errorf("Expected the displayName label, got %q", got["uid=jdoe"])
+	}
+	if got["uid=svc"] != "" || got["uid=old"] != "" {
+		t.Errorf("Expected blank and missing values to leave the label empty, got %v", got)
+	}
+}
+
+func TestGetChildrenChildFilter(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "ou=people,dc=example,dc=com"},
+	)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	node := &TreeNode{DN: "dc=example,dc=com"}
+	if err := client.LoadChildren(node); err != nil {
+		t.Fatalf("LoadChildren failed: %v", err)
+	}
+	if err := client.SetTreeChildFilter(" (!(objectClass=computer)) "); err != nil {
+		t.Fatalf("SetTreeChildFilter failed: %v", err)
+	}
+	if _, err := client.GetChildren("ou=people,dc=example,dc=com"); err != nil {
+		t.Fatalf("GetChildren failed: %v", err)
+	}
+
+	filters := server.Filters()
+	want := []string{"(objectClass=*)", "(!(objectClass=computer))"}
+	if len(filters) != len(want) || filters[0] != want[0] || filters[1] != want[1] {
+		t.Errorf("Expected the child searches to use %v, got %v", want, filters)
+	}
+
+	client.SetTreeChildFilter("")
+	if got := client.TreeChildFilter(); got != "(objectClass=*)" {
+		t.Errorf("Expected clearing the filter to list every child, got %q", got)
+	}
+
+	if err := client.SetTreeChildFilter("(!(objectClass=computer)"); err == nil {
+		t.Error("Expected an invalid filter to be reported")
+	}
+	if got := client.TreeChildFilter(); got != "(objectClass=*)" {
+		t.Errorf("Expected an invalid filter to list every child, got %q", got)
+	}
+}
+
+func TestBuildNamingContextTrees_NoneAdvertised(t *testing.T) {
+	client := &Client{capabilities: &Capabilities{}}
+	if _, err := client.BuildNamingContextTrees(); err == nil {
+		t.Error("Expected an error when the server lists no naming contexts")
+	}
+}
+
+func TestWithRetryReportsAttempts(t *testing.T) {
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	client, err := NewClient(Config{
+		Host:           "127.0.0.1",
+		Port:           server.Port(),
+		RetryEnabled:   true,
+		MaxRetries:     3,
+		InitialDelayMs: 1,
+		MaxDelayMs:     3,
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	var events []RetryEvent
+	client.SetRetryHandler(func(event RetryEvent) {
+		events = append(events, event)
+	})
+
+	calls := 0
+	err = client.withRetry(func() error {
+		calls++
+		if calls <= 3 {
+			return errors.New("connection reset by peer")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the fourth attempt to succeed, got %v", err)
+	}
+
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}
+	if len(events) != len(want) {
+		t.Fatalf("Expected a report per retry, got %d", len(events))
+	}
+	for i, event := range events {
+		if event.Attempt != i+2 || event.MaxAttempts != 4 || event.Delay != want[i] || event.Err == nil {
+			t.Errorf("Unexpected report %d: %+v", i, event)
+		}
+	}
+	if got := events[0].String(); got != "Connection lost, retrying (attempt 2/4, waiting 1ms)" {
+		t.Errorf("Unexpected description %q", got)
+	}
+}
+
+func TestEntryAttributeKey(t *testing.T) {
+	entry := &Entry{
+		Attributes: map[string][]string{"sAMAccountName": {"jdoe"}, "mail": {"jdoe@example.com"}},
+		MoreValues: map[string]int{"member": 1500},
+	}
+	for _, name := range []string{"sAMAccountName", "samaccountname", " SAMACCOUNTNAME\t"} {
+		if key, ok := entry.AttributeKey(name); !ok || key != "sAMAccountName" {
+			t.Errorf("AttributeKey(%q): expected the stored name, got %q", name, key)
+		}
+	}
+	if values, ok := entry.Lookup("MAIL"); !ok || len(values) != 1 || values[0] != "jdoe@example.com" {
+		t.Errorf("Expected the mail values, got %v", values)
+	}
+	if _, ok := entry.Lookup("cn"); ok {
+		t.Error("Expected a missing attribute not to be found")
+	}
+	if !entry.HasMoreValues("Member") {
+		t.Error("Expected the partial attribute to be found whatever its case")
+	}
+}
@@ -0,0 +1,67 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ErrTreeDeleteUnsupported is returned by DeleteSubtree when the server doesn't
+// advertise the tree delete control in its root DSE
+var ErrTreeDeleteUnsupported = errors.New("server does not support the tree delete control; delete the children first")
+
+// Delete removes a single leaf entry
+func (c *Client) Delete(dn string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	return c.audited(AuditDelete, dn, nil, func() error {
+		return c.withRetry(func() error {
+			if err := c.connection().Del(buildDeleteRequest(dn, false)); err != nil {
+				if ldap.IsErrorWithCode(err, ldap.LDAPResultNotAllowedOnNonLeaf) {
+					return fmt.Errorf("delete failed: entry has children, which must be deleted first: %w", err)
+				}
+				return fmt.Errorf("delete failed: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+// SupportsTreeDelete reports whether the server is known to support the tree
+// delete control. Unlike Supports, unknown capabilities count as unsupported
+// since the operation is destructive.
+func (c *Client) SupportsTreeDelete() bool {
+	return c.Capabilities().Supports(OIDTreeDelete)
+}
+
+// DeleteSubtree removes an entry and everything below it in one operation
+// using the tree delete control
+func (c *Client) DeleteSubtree(dn string) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	if !c.SupportsTreeDelete() {
+		return ErrTreeDeleteUnsupported
+	}
+
+	return c.audited(AuditDelete, dn, []string{"subtree"}, func() error {
+		return c.withRetry(func() error {
+			if err := c.connection().Del(buildDeleteRequest(dn, true)); err != nil {
+				return fmt.Errorf("subtree delete failed: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+// buildDeleteRequest creates a delete request, attaching the tree delete
+// control when the whole subtree should be removed
+func buildDeleteRequest(dn string, subtree bool) *ldap.DelRequest {
+	var controls []ldap.Control
+	if subtree {
+		controls = append(controls, ldap.NewControlSubtreeDelete())
+	}
+	return ldap.NewDelRequest(dn, controls)
+}
@@ -0,0 +1,34 @@
+package ldap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuildDeleteRequest(t *testing.T) {
+	req := buildDeleteRequest("uid=jdoe,dc=example,dc=com", false)
+	if req.DN != "uid=jdoe,dc=example,dc=com" || len(req.Controls) != 0 {
+		t.Errorf("Expected plain delete request, got %+v", req)
+	}
+
+	req = buildDeleteRequest("ou=people,dc=example,dc=com", true)
+	if len(req.Controls) != 1 || req.Controls[0].GetControlType() != OIDTreeDelete {
+		t.Errorf("Expected tree delete control, got %+v", req.Controls)
+	}
+}
+
+func TestDeleteSubtreeRequiresAdvertisedControl(t *testing.T) {
+	// Unknown capabilities don't allow a subtree delete
+	client := &Client{}
+	if client.SupportsTreeDelete() {
+		t.Error("Expected tree delete to be unsupported without capabilities")
+	}
+	if err := client.DeleteSubtree("ou=people,dc=example,dc=com"); !errors.Is(err, ErrTreeDeleteUnsupported) {
+		t.Errorf("Expected ErrTreeDeleteUnsupported, got %v", err)
+	}
+
+	client.capabilities = parseCapabilities(map[string][]string{"supportedControl": {OIDTreeDelete}})
+	if !client.SupportsTreeDelete() {
+		t.Error("Expected advertised tree delete control to be supported")
+	}
+}
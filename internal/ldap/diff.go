@@ -0,0 +1,128 @@
+package ldap
+
+import (
+	"sort"
+	"strings"
+)
+
+// AttributeDiff is an attribute whose values differ between two entries.
+// Left and Right hold the values found only on that side, in the order the
+// server returned them; an attribute missing on one side has all of its
+// values on the other.
+type AttributeDiff struct {
+	Name  string
+	Left  []string
+	Right []string
+}
+
+// DiffEntries compares two entries attribute by attribute, returning the
+// attributes that differ sorted by name. Attribute names are compared
+// without regard to case and values without regard to order, since servers
+// needn't keep either.
+func DiffEntries(left, right *Entry) []AttributeDiff {
+	names := make(map[string]string)
+	leftValues := make(map[string][]string)
+	rightValues := make(map[string][]string)
+	for name, values := range left.Attributes {
+		key := strings.ToLower(name)
+		names[key] = name
+		leftValues[key] = append(leftValues[key], values...)
+	}
+	for name, values := range right.Attributes {
+		key := strings.ToLower(name)
+		if _, ok := names[key]; !ok {
+			names[key] = name
+		}
+		rightValues[key] = append(rightValues[key], values...)
+	}
+
+	var diffs []AttributeDiff
+	for key, name := range names {
+		onlyLeft := missingValues(leftValues[key], rightValues[key])
+		onlyRight := missingValues(rightValues[key], leftValues[key])
+		if len(onlyLeft) > 0 || len(onlyRight) > 0 {
+			diffs = append(diffs, AttributeDiff{Name: name, Left: onlyLeft, Right: onlyRight})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return strings.ToLower(diffs[i].Name) < strings.ToLower(diffs[j].Name)
+	})
+	return diffs
+}
+
+// missingValues returns the values of from that other doesn't hold
+func missingValues(from, other []string) []string {
+	present := make(map[string]int, len(other))
+	for _, value := range other {
+		present[value]++
+	}
+	var missing []string
+	for _, value := range from {
+		if present[value] > 0 {
+			present[value]--
+			continue
+		}
+		missing = append(missing, value)
+	}
+	return missing
+}
+
+// localAttributes are kept by each Active Directory domain controller for
+// itself rather than replicated, so they differ between servers holding
+// identical entries
+var localAttributes = map[string]bool{
+	"badpasswordtime": true,
+	"badpwdcount":     true,
+	"lastlogoff":      true,
+	"lastlogon":       true,
+	"logoncount":      true,
+	"usnchanged":      true,
+	"usncreated":      true,
+	"whenchanged":     true,
+}
+
+// EntryComparison is how one entry compares between two directories. An
+// entry missing on a side is flagged rather than reported as an error.
+type EntryComparison struct {
+	DN           string
+	Diffs        []AttributeDiff
+	MissingLeft  bool
+	MissingRight bool
+	Err          error // Reading the entry failed for another reason
+}
+
+// Identical reports whether the entry exists on both sides with the same values
+func (c EntryComparison) Identical() bool {
+	return c.Err == nil && !c.MissingLeft && !c.MissingRight && len(c.Diffs) == 0
+}
+
+// CompareEntries reads each DN with left and right, such as the GetEntry of
+// clients connected to two servers, and compares the entries. Attributes a
+// domain controller keeps for itself, such as lastLogon and uSNChanged, are
+// left out since they differ even when replication is up to date.
+func CompareEntries(left, right func(dn string) (*Entry, error), dns []string) []EntryComparison {
+	comparisons := make([]EntryComparison, 0, len(dns))
+	for _, dn := range dns {
+		comparison := EntryComparison{DN: dn}
+		leftEntry, leftErr := left(dn)
+		rightEntry, rightErr := right(dn)
+		switch {
+		case leftErr != nil && !IsEntryNotFound(leftErr):
+			comparison.Err = leftErr
+		case rightErr != nil && !IsEntryNotFound(rightErr):
+			comparison.Err = rightErr
+		default:
+			comparison.MissingLeft = leftErr != nil
+			comparison.MissingRight = rightErr != nil
+		}
+		if comparison.Err == nil && leftEntry != nil && rightEntry != nil {
+			for _, diff := range DiffEntries(leftEntry, rightEntry) {
+				if !localAttributes[strings.ToLower(diff.Name)] {
+					comparison.Diffs = append(comparison.Diffs, diff)
+				}
+			}
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons
+}
@@ -0,0 +1,107 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+func TestDiffEntries(t *testing.T) {
+	left := &Entry{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{
+		"uid":             {"jdoe"},
+		"mail":            {"jdoe@example.com", "j.doe@example.com"},
+		"description":     {"Engineering"},
+		"telephoneNumber": {"+1 555 0100"},
+	}}
+	right := &Entry{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{
+		"UID":         {"jdoe"},
+		"mail":        {"j.doe@example.com", "jdoe@example.com"},
+		"description": {"Sales"},
+		"title":       {"Engineer"},
+	}}
+
+	want := []AttributeDiff{
+		{Name: "description", Left: []string{"Engineering"}, Right: []string{"Sales"}},
+		{Name: "telephoneNumber", Left: []string{"+1 555 0100"}},
+		{Name: "title", Right: []string{"Engineer"}},
+	}
+	if got := DiffEntries(left, right); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected differences:\n got %+v\nwant %+v", got, want)
+	}
+	if diffs := DiffEntries(left, left); len(diffs) != 0 {
+		t.Errorf("Expected an entry to match itself, got %+v", diffs)
+	}
+}
+
+// fakeDirectory serves entries by DN the way Client.GetEntry does
+func fakeDirectory(entries ...*Entry) func(string) (*Entry, error) {
+	return func(dn string) (*Entry, error) {
+		for _, entry := range entries {
+			if entry.DN == dn {
+				return entry, nil
+			}
+		}
+		if dn == "cn=broken" {
+			return nil, errors.New("server is busy")
+		}
+		return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, dn)
+	}
+}
+
+func TestCompareEntries(t *testing.T) {
+	prod := fakeDirectory(
+		&Entry{DN: "cn=same", Attributes: map[string][]string{"cn": {"same"}, "uSNChanged": {"1001"}, "lastLogon": {"133485408000000000"}}},
+		&Entry{DN: "cn=drift", Attributes: map[string][]string{"cn": {"drift"}, "member": {"uid=a", "uid=b"}}},
+		&Entry{DN: "cn=prod-only"},
+	)
+	dr := fakeDirectory(
+		&Entry{DN: "cn=same", Attributes: map[string][]string{"cn": {"same"}, "uSNChanged": {"2044"}}},
+		&Entry{DN: "cn=drift", Attributes: map[string][]string{"cn": {"drift"}, "member": {"uid=a"}}},
+		&Entry{DN: "cn=dr-only"},
+	)
+
+	got := CompareEntries(prod, dr, []string{"cn=same", "cn=drift", "cn=prod-only", "cn=dr-only", "cn=broken"})
+	if len(got) != 5 {
+		t.Fatalf("Expected a comparison per DN, got %d", len(got))
+	}
+	if !got[0].Identical() {
+		t.Errorf("Expected per-server attributes to be ignored, got %+v", got[0])
+	}
+	if want := []AttributeDiff{{Name: "member", Left: []string{"uid=b"}}}; !reflect.DeepEqual(got[1].Diffs, want) {
+		t.Errorf("Expected the missing member, got %+v", got[1].Diffs)
+	}
+	if !got[2].MissingRight || got[2].MissingLeft || got[2].Identical() {
+		t.Errorf("Expected the entry to be missing on the right, got %+v", got[2])
+	}
+	if !got[3].MissingLeft || got[3].MissingRight {
+		t.Errorf("Expected the entry to be missing on the left, got %+v", got[3])
+	}
+	if got[4].Err == nil || got[4].MissingLeft || got[4].MissingRight {
+		t.Errorf("Expected a read error, got %+v", got[4])
+	}
+}
+
+func TestCompareEntries_TwoServers(t *testing.T) {
+	connect := func(entries ...ldaptest.Entry) *Client {
+		server := ldaptest.NewServer(t, entries...)
+		client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port()})
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+	prod := connect(ldaptest.Entry{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"uid": {"jdoe"}, "title": {"Engineer"}}})
+	dr := connect(ldaptest.Entry{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"uid": {"jdoe"}, "title": {"Manager"}}})
+
+	got := CompareEntries(prod.GetEntry, dr.GetEntry, []string{"uid=jdoe,dc=example,dc=com", "uid=gone,dc=example,dc=com"})
+	if want := []AttributeDiff{{Name: "title", Left: []string{"Engineer"}, Right: []string{"Manager"}}}; !reflect.DeepEqual(got[0].Diffs, want) {
+		t.Errorf("Unexpected differences %+v", got[0].Diffs)
+	}
+	if !got[1].MissingLeft || !got[1].MissingRight {
+		t.Errorf("Expected the entry to be missing on both servers, got %+v", got[1])
+	}
+}
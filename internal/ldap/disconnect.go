@@ -0,0 +1,48 @@
+package ldap
+
+import (
+	"errors"
+	"strings"
+)
+
+// OIDNoticeOfDisconnection names the unsolicited notification a server sends
+// before closing a connection, for example when shutting down (RFC 4511 4.4.1)
+const OIDNoticeOfDisconnection = "1.3.6.1.4.1.1466.20036"
+
+// go-ldap has no handler for unsolicited notifications: a response with
+// message ID 0 is recorded as the connection's last error and dropped. That
+// error is the only trace of the notice, so it is what gets matched.
+const unsolicitedMessageError = "received unexpected message 0,"
+
+// IsNoticeOfDisconnection reports whether err records an unsolicited
+// notification, which servers only send to announce they are disconnecting
+func IsNoticeOfDisconnection(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if strings.Contains(err.Error(), unsolicitedMessageError) {
+			return true
+		}
+	}
+	return false
+}
+
+// serverRequestedDisconnect reports whether the server sent a notice of
+// disconnection on the current connection
+func (c *Client) serverRequestedDisconnect() bool {
+	conn := c.connection()
+	return conn != nil && IsNoticeOfDisconnection(conn.GetLastError())
+}
+
+// reconnectIfServerRequested re-establishes the connection before an
+// operation when the server has announced it is closing it, so the operation
+// runs on a fresh connection instead of failing on the old one. A failed
+// reconnect is left to the operation and its retries to report.
+func (c *Client) reconnectIfServerRequested() {
+	conn := c.connection()
+	if conn == nil || !IsNoticeOfDisconnection(conn.GetLastError()) {
+		return
+	}
+	if c.onRetry != nil {
+		c.onRetry(RetryEvent{ServerRequested: true, Err: conn.GetLastError()})
+	}
+	_ = c.reconnect(conn)
+}
@@ -0,0 +1,131 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestIsNoticeOfDisconnection(t *testing.T) {
+	// go-ldap records the notice this way when its reader drops it
+	notice := fmt.Errorf("ldap: received unexpected message %d, %v", 0, false)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"notice", notice, true},
+		{"wrapped notice", fmt.Errorf("search failed: %w", notice), true},
+		{"other unexpected message", errors.New("ldap: received unexpected message 12, false"), false},
+		{"connection closed", ldap.NewError(ldap.ErrorNetwork, errors.New("ldap: connection closed")), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNoticeOfDisconnection(tt.err); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	client := &Client{}
+	if !client.isRetryableError(notice) {
+		t.Error("Expected a notice of disconnection to be retried with a reconnect")
+	}
+}
+
+func TestRetryEventString_ServerRequested(t *testing.T) {
+	if got := (RetryEvent{ServerRequested: true}).String(); got != "Server requested reconnect, reconnecting" {
+		t.Errorf("Unexpected description: %q", got)
+	}
+	event := RetryEvent{Attempt: 2, MaxAttempts: 4, Delay: time.Second, ServerRequested: true}
+	if got := event.String(); got != "Server requested reconnect, retrying (attempt 2/4, waiting 1s)" {
+		t.Errorf("Unexpected description: %q", got)
+	}
+}
+
+func TestClient_ReconnectsAfterNoticeOfDisconnection(t *testing.T) {
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com", Attributes: map[string][]string{"dc": {"example"}}})
+	// Retries are off, so only the reconnect the notice asks for can save the search
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	var events []RetryEvent
+	client.SetRetryHandler(func(event RetryEvent) {
+		events = append(events, event)
+	})
+
+	server.Disconnect()
+	deadline := time.Now().Add(2 * time.Second)
+	for !client.serverRequestedDisconnect() {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the client to record the notice of disconnection")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	entries, err := client.Search("dc=example,dc=com", "(objectClass=*)", ldap.ScopeBaseObject, nil)
+	if err != nil {
+		t.Fatalf("Expected the search to run on a new connection, got %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected the entry, got %d entries", len(entries))
+	}
+	if server.Connections() != 2 {
+		t.Errorf("Expected one reconnect, got %d connections", server.Connections())
+	}
+	if len(events) != 1 || !events[0].ServerRequested || events[0].String() != "Server requested reconnect, reconnecting" {
+		t.Errorf("Expected the reconnect to be reported, got %+v", events)
+	}
+	if client.serverRequestedDisconnect() {
+		t.Error("Expected the new connection to start without the notice")
+	}
+}
+
+func TestClient_ReconnectsOnceForConcurrentOperations(t *testing.T) {
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com", Attributes: map[string][]string{"dc": {"example"}}})
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	server.Disconnect()
+	deadline := time.Now().Add(2 * time.Second)
+	for !client.serverRequestedDisconnect() {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the client to record the notice of disconnection")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Searches and pings from the background all find the notice at once
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Search("dc=example,dc=com", "(objectClass=*)", ldap.ScopeBaseObject, nil); err != nil {
+				t.Errorf("Expected the search to run on the new connection, got %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = client.Ping()
+		}()
+	}
+	wg.Wait()
+
+	if server.Connections() != 2 {
+		t.Errorf("Expected a single reconnect, got %d connections", server.Connections())
+	}
+}
@@ -0,0 +1,54 @@
+package ldap
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// lookupSRV resolves SRV records; replaced in tests
+var lookupSRV = net.LookupSRV
+
+// DiscoveredServer is an LDAP server advertised in DNS for a domain
+type DiscoveredServer struct {
+	Host   string
+	Port   int
+	UseSSL bool // Advertised as _ldaps rather than _ldap
+}
+
+// DiscoverServers looks up the _ldaps._tcp and _ldap._tcp SRV records of
+// domain. LDAPS servers are listed first, each service in DNS priority order.
+func DiscoverServers(domain string) ([]DiscoveredServer, error) {
+	domain = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+	if domain == "" {
+		return nil, fmt.Errorf("domain is required for discovery")
+	}
+
+	var servers []DiscoveredServer
+	var lastErr error
+	for _, service := range []struct {
+		name   string
+		useSSL bool
+	}{{"ldaps", true}, {"ldap", false}} {
+		_, records, err := lookupSRV(service.name, "tcp", domain)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, record := range records {
+			servers = append(servers, DiscoveredServer{
+				Host:   strings.TrimSuffix(record.Target, "."),
+				Port:   int(record.Port),
+				UseSSL: service.useSSL,
+			})
+		}
+	}
+
+	if len(servers) == 0 {
+		if lastErr != nil {
+			return nil, fmt.Errorf("no LDAP servers found for %s: %w", domain, lastErr)
+		}
+		return nil, fmt.Errorf("no LDAP servers found for %s", domain)
+	}
+	return servers, nil
+}
@@ -0,0 +1,58 @@
+package ldap
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+func stubLookupSRV(t *testing.T, records map[string][]*net.SRV) {
+	t.Helper()
+	original := lookupSRV
+	lookupSRV = func(service, proto, name string) (string, []*net.SRV, error) {
+		found, ok := records[service+"."+name]
+		if !ok {
+			return "", nil, errors.New("no such host")
+		}
+		return "", found, nil
+	}
+	t.Cleanup(func() { lookupSRV = original })
+}
+
+func TestDiscoverServers(t *testing.T) {
+	stubLookupSRV(t, map[string][]*net.SRV{
+		"ldap.example.com":  {{Target: "dc1.example.com.", Port: 389}, {Target: "dc2.example.com.", Port: 389}},
+		"ldaps.example.com": {{Target: "dc1.example.com.", Port: 636}},
+	})
+
+	servers, err := DiscoverServers(" example.com. ")
+	if err != nil {
+		t.Fatalf("DiscoverServers failed: %v", err)
+	}
+
+	want := []DiscoveredServer{
+		{Host: "dc1.example.com", Port: 636, UseSSL: true},
+		{Host: "dc1.example.com", Port: 389},
+		{Host: "dc2.example.com", Port: 389},
+	}
+	if len(servers) != len(want) {
+		t.Fatalf("Expected %d servers, got %v", len(want), servers)
+	}
+	for i := range want {
+		if servers[i] != want[i] {
+			t.Errorf("Server %d: expected %v, got %v", i, want[i], servers[i])
+		}
+	}
+}
+
+func TestDiscoverServers_NoneFound(t *testing.T) {
+	stubLookupSRV(t, nil)
+
+	if _, err := DiscoverServers("example.com"); err == nil || !strings.Contains(err.Error(), "no LDAP servers found for example.com") {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if _, err := DiscoverServers(""); err == nil {
+		t.Error("Expected an error for an empty domain")
+	}
+}
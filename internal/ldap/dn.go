@@ -0,0 +1,105 @@
+package ldap
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// attributeTypePattern matches an attribute type: a name or a numeric OID
+var attributeTypePattern = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9-]*|[0-9]+(?:\.[0-9]+)*)$`)
+
+// NormalizeDN checks that dn is a well-formed distinguished name and returns
+// it in a canonical form: attribute types in lower case, no spaces around
+// separators and values escaped as RFC 4514 requires. Value case is kept. The
+// empty DN (the root DSE) normalizes to the empty string.
+func NormalizeDN(dn string) (string, error) {
+	dn = strings.TrimSpace(dn)
+	if dn == "" {
+		return "", nil
+	}
+
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return "", fmt.Errorf("invalid DN %q: %w", dn, err)
+	}
+
+	rdns := make([]string, 0, len(parsed.RDNs))
+	for _, rdn := range parsed.RDNs {
+		attributes := make([]string, 0, len(rdn.Attributes))
+		for _, attribute := range rdn.Attributes {
+			if !attributeTypePattern.MatchString(attribute.Type) {
+				return "", fmt.Errorf("invalid DN %q: %q is not an attribute type", dn, attribute.Type)
+			}
+			attributes = append(attributes, strings.ToLower(attribute.Type)+"="+EscapeDNValue(attribute.Value))
+		}
+		rdns = append(rdns, strings.Join(attributes, "+"))
+	}
+	return strings.Join(rdns, ","), nil
+}
+
+// EscapeDNValue escapes an attribute value for use in a DN, so that commas,
+// plus signs and the like stay part of the value. Unlike go-ldap's own
+// normalization, non-ASCII characters are kept readable.
+func EscapeDNValue(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+		switch {
+		case (i == 0 && (char == ' ' || char == '#')) || (i == len(value)-1 && char == ' '):
+			builder.WriteByte('\\')
+			builder.WriteByte(char)
+		case strings.IndexByte(`"+,;<>\`, char) >= 0:
+			builder.WriteByte('\\')
+			builder.WriteByte(char)
+		case char < ' ' || char == 0x7f:
+			fmt.Fprintf(&builder, `\%02x`, char)
+		default:
+			builder.WriteByte(char)
+		}
+	}
+	return builder.String()
+}
+
+// AbbreviateDN shortens dn for display to its RDN values without their
+// attribute types, joined by " / ", so cn=alice,ou=people,dc=example,dc=com
+// reads alice / people / example / com. Multi-valued RDNs keep their values
+// joined by "+". A DN that doesn't parse is returned as it is.
+func AbbreviateDN(dn string) string {
+	parsed, err := ldap.ParseDN(strings.TrimSpace(dn))
+	if err != nil || len(parsed.RDNs) == 0 {
+		return dn
+	}
+
+	values := make([]string, 0, len(parsed.RDNs))
+	for _, rdn := range parsed.RDNs {
+		parts := make([]string, 0, len(rdn.Attributes))
+		for _, attribute := range rdn.Attributes {
+			parts = append(parts, attribute.Value)
+		}
+		values = append(values, strings.Join(parts, "+"))
+	}
+	return strings.Join(values, " / ")
+}
+
+// BuildDN assembles the DN of a new child of parent named by a single
+// attribute and value, escaping the value, and returns it normalized as
+// NormalizeDN does. The parent must be a valid DN; an empty parent makes the
+// RDN a DN of its own.
+func BuildDN(parent, attribute, value string) (string, error) {
+	attribute = strings.TrimSpace(attribute)
+	if !attributeTypePattern.MatchString(attribute) {
+		return "", fmt.Errorf("%q is not an attribute type", attribute)
+	}
+	if strings.TrimSpace(value) == "" {
+		return "", fmt.Errorf("the %s value is empty", attribute)
+	}
+
+	dn := attribute + "=" + EscapeDNValue(strings.TrimSpace(value))
+	if parent = strings.TrimSpace(parent); parent != "" {
+		dn += "," + parent
+	}
+	return NormalizeDN(dn)
+}
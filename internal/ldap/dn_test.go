@@ -0,0 +1,125 @@
+package ldap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeDN(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"dc=example,dc=com", "dc=example,dc=com"},
+		{"  DC=Example, DC=com ", "dc=Example,dc=com"},
+		{"CN = John Doe , OU = People , DC = example , DC = com", "cn=John Doe,ou=People,dc=example,dc=com"},
+		{`cn=Doe\, John,ou=people,dc=example,dc=com`, `cn=Doe\, John,ou=people,dc=example,dc=com`},
+		{`cn=Doe\2C John,dc=example,dc=com`, `cn=Doe\, John,dc=example,dc=com`},
+		{"cn=admin+uid=root,dc=example,dc=com", "cn=admin+uid=root,dc=example,dc=com"},
+		{"cn=José Müller,dc=example,dc=com", "cn=José Müller,dc=example,dc=com"},
+		{`cn=\#hash,dc=example,dc=com`, `cn=\#hash,dc=example,dc=com`},
+		{"2.5.4.3=admin,dc=example,dc=com", "2.5.4.3=admin,dc=example,dc=com"},
+		{"", ""},
+		{"   ", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeDN(tt.input)
+		if err != nil {
+			t.Errorf("NormalizeDN(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeDN(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeDNInvalid(t *testing.T) {
+	for _, input := range []string{
+		"example.com",
+		"dc=example,",
+		"dc=example,,dc=com",
+		"=example,dc=com",
+		"my attr=x,dc=com",
+		"cn=trailing\\",
+	} {
+		if got, err := NormalizeDN(input); err == nil {
+			t.Errorf("Expected NormalizeDN(%q) to fail, got %q", input, got)
+		} else if !strings.Contains(err.Error(), "invalid DN") {
+			t.Errorf("Expected an invalid DN error for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestNormalizeDNRoundTrip(t *testing.T) {
+	// The canonical form parses back to itself
+	for _, input := range []string{`CN=Doe\, John,OU=a\+b,DC=example`, `cn= leading,dc=x`, `cn=a\;b\<c\>d\"e,dc=x`} {
+		once, err := NormalizeDN(input)
+		if err != nil {
+			t.Fatalf("NormalizeDN(%q) returned error: %v", input, err)
+		}
+		twice, err := NormalizeDN(once)
+		if err != nil || twice != once {
+			t.Errorf("Expected %q to be stable, got %q (%v)", once, twice, err)
+		}
+	}
+}
+
+func TestAbbreviateDN(t *testing.T) {
+	tests := []struct {
+		dn, want string
+	}{
+		{"cn=alice,ou=people,dc=example,dc=com", "alice / people / example / com"},
+		{"CN=Doe\\, Jane, OU=Staff,DC=corp", "Doe, Jane / Staff / corp"},
+		{"cn=a+sn=b,dc=example", "a+b / example"},
+		{"dc=com", "com"},
+		{"", ""},
+		{"not a dn", "not a dn"},
+	}
+	for _, tt := range tests {
+		if got := AbbreviateDN(tt.dn); got != tt.want {
+			t.Errorf("AbbreviateDN(%q) = %q, want %q", tt.dn, got, tt.want)
+		}
+	}
+}
+
+func TestBuildDN(t *testing.T) {
+	tests := []struct {
+		parent, attribute, value string
+		want                     string
+	}{
+		{"ou=people,dc=example,dc=com", "uid", "jdoe", "uid=jdoe,ou=people,dc=example,dc=com"},
+		{"OU=People, DC=example, DC=com", "CN", "Jane Doe", "cn=Jane Doe,ou=People,dc=example,dc=com"},
+		{"ou=people,dc=example,dc=com", "cn", "Doe, Jane", `cn=Doe\, Jane,ou=people,dc=example,dc=com`},
+		{"dc=example,dc=com", "cn", "a+b=c", `cn=a\+b=c,dc=example,dc=com`},
+		{"dc=example,dc=com", "ou", "  #staff ", `ou=\#staff,dc=example,dc=com`},
+		{"", "dc", "com", "dc=com"},
+	}
+	for _, tt := range tests {
+		got, err := BuildDN(tt.parent, tt.attribute, tt.value)
+		if err != nil {
+			t.Errorf("BuildDN(%q, %q, %q) returned error: %v", tt.parent, tt.attribute, tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("BuildDN(%q, %q, %q) = %q, want %q", tt.parent, tt.attribute, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestBuildDNInvalid(t *testing.T) {
+	tests := []struct {
+		parent, attribute, value string
+	}{
+		{"dc=example,dc=com", "", "jdoe"},
+		{"dc=example,dc=com", "my attr", "jdoe"},
+		{"dc=example,dc=com", "uid", "  "},
+		{"example.com", "uid", "jdoe"},
+	}
+	for _, tt := range tests {
+		if got, err := BuildDN(tt.parent, tt.attribute, tt.value); err == nil {
+			t.Errorf("Expected BuildDN(%q, %q, %q) to fail, got %q", tt.parent, tt.attribute, tt.value, got)
+		}
+	}
+}
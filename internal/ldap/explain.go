@@ -0,0 +1,148 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Explanation describes an LDAP error in plain words with its likely fix
+type Explanation struct {
+	Summary string
+	Fix     string
+}
+
+// ExplainedError is an error with a friendly explanation attached. The
+// original error is kept and returned by Unwrap.
+type ExplainedError struct {
+	Explanation
+	Err error
+}
+
+// Error returns the explanation followed by the raw error
+func (e *ExplainedError) Error() string {
+	return fmt.Sprintf("%s. %s (%v)", e.Summary, e.Fix, e.Err)
+}
+
+// Unwrap returns the original error
+func (e *ExplainedError) Unwrap() error {
+	return e.Err
+}
+
+// Explain attaches a friendly explanation to err when one is known and
+// otherwise returns err unchanged
+func Explain(err error) error {
+	if err == nil {
+		return nil
+	}
+	var explained *ExplainedError
+	if errors.As(err, &explained) {
+		return err
+	}
+	if explanation, ok := ExplainError(err); ok {
+		return &ExplainedError{Explanation: explanation, Err: err}
+	}
+	return err
+}
+
+// ExplainError looks up an explanation for err. Active Directory bind
+// sub-codes and extended error codes are the most specific and are checked
+// before the standard LDAP result code.
+func ExplainError(err error) (Explanation, bool) {
+	if err == nil {
+		return Explanation{}, false
+	}
+
+	message := err.Error()
+	if match := adBindDataPattern.FindStringSubmatch(message); match != nil {
+		if explanation, ok := adBindErrors[strings.ToLower(match[1])]; ok {
+			return explanation, true
+		}
+	}
+	if match := adExtendedErrorPattern.FindStringSubmatch(message); match != nil {
+		if code, parseErr := strconv.ParseUint(match[1], 16, 32); parseErr == nil {
+			if explanation, ok := adExtendedErrors[uint32(code)]; ok {
+				return explanation, true
+			}
+		}
+	}
+
+	var ldapErr *ldap.Error
+	if errors.As(err, &ldapErr) {
+		if explanation, ok := resultCodeExplanations[ldapErr.ResultCode]; ok {
+			return explanation, true
+		}
+	}
+	return Explanation{}, false
+}
+
+var (
+	// adBindDataPattern finds the sub-code in bind errors such as
+	// "AcceptSecurityContext error, data 52e, v4563"
+	adBindDataPattern = regexp.MustCompile(`(?i)\bdata ([0-9a-f]{3,8})\b`)
+
+	// adExtendedErrorPattern finds the Win32 error code Active Directory puts
+	// in front of its diagnostic messages, such as "00002098: SecErr: ..."
+	adExtendedErrorPattern = regexp.MustCompile(`\b([0-9A-Fa-f]{8}): [A-Za-z]+Err`)
+)
+
+// adBindErrors explains the sub-codes of Active Directory's invalid credentials errors
+var adBindErrors = map[string]Explanation{
+	"525": {"User not found", "Check the bind user name; the account doesn't exist in the directory"},
+	"52e": {"Invalid credentials", "Check the bind user and password"},
+	"530": {"Logon not permitted at this time", "The account has logon hours restrictions; try again later or ask an administrator"},
+	"531": {"Logon not permitted from this workstation", "The account may only log on from specific computers"},
+	"532": {"Password expired", "Change the password before binding again"},
+	"533": {"Account disabled", "Ask an administrator to enable the account"},
+	"568": {"Too many security IDs", "The account belongs to too many groups to log on"},
+	"701": {"Account expired", "Ask an administrator to extend the account's expiry date"},
+	"773": {"Password must be reset", "Set a new password for the account before binding"},
+	"775": {"Account locked out", "Wait for the lockout to expire or ask an administrator to unlock the account"},
+}
+
+// adExtendedErrors explains common Win32 error codes returned by Active Directory
+var adExtendedErrors = map[uint32]Explanation{
+	0x52D:  {"Password doesn't meet the password policy", "Use a longer or more complex password, or one not used recently"},
+	0x2071: {"Entry already exists", "Choose a different name for the new entry"},
+	0x208D: {"Entry not found", "Check the DN; the entry or its parent doesn't exist"},
+	0x2098: {"Insufficient access rights", "The bound user isn't allowed to do this; bind as a user with the needed permissions"},
+	0x202B: {"Referral", "The entry lives in another domain; connect to a domain controller for it or a global catalog"},
+}
+
+// resultCodeExplanations explains the standard LDAP result codes
+var resultCodeExplanations = map[uint16]Explanation{
+	ldap.LDAPResultOperationsError:              {"Operations error", "The server couldn't process the request; Active Directory returns this when a search needs a bind first"},
+	ldap.LDAPResultProtocolError:                {"Protocol error", "The server rejected the request format; check the filter and any controls the server may not support"},
+	ldap.LDAPResultTimeLimitExceeded:            {"Time limit exceeded", "Narrow the search with a more specific filter or base DN"},
+	ldap.LDAPResultSizeLimitExceeded:            {"Size limit exceeded", "The server caps result sizes; narrow the search or use a smaller page size"},
+	ldap.LDAPResultAuthMethodNotSupported:       {"Authentication method not supported", "Try a different bind method"},
+	ldap.LDAPResultStrongAuthRequired:           {"Stronger authentication required", "Enable StartTLS or SSL, or use Kerberos"},
+	ldap.LDAPResultAdminLimitExceeded:           {"Administrative limit exceeded", "Narrow the search; the server limits how much work one request may do"},
+	ldap.LDAPResultUnavailableCriticalExtension: {"Unsupported control", "The server doesn't support a control the request requires"},
+	ldap.LDAPResultConfidentialityRequired:      {"Encrypted connection required", "Enable StartTLS or SSL for this connection"},
+	ldap.LDAPResultNoSuchAttribute:              {"No such attribute", "The entry doesn't have that attribute or value"},
+	ldap.LDAPResultUndefinedAttributeType:       {"Unknown attribute type", "Check the attribute name against the server schema"},
+	ldap.LDAPResultInappropriateMatching:        {"Inappropriate matching", "The attribute doesn't support this kind of comparison in a filter"},
+	ldap.LDAPResultConstraintViolation:          {"Constraint violation", "A value breaks a server rule, such as a password policy or size limit"},
+	ldap.LDAPResultAttributeOrValueExists:       {"Value already exists", "The attribute already has that value"},
+	ldap.LDAPResultInvalidAttributeSyntax:       {"Invalid attribute syntax", "The value isn't in the format the attribute's schema requires"},
+	ldap.LDAPResultNoSuchObject:                 {"Entry not found", "Check the DN or base DN; the entry doesn't exist or isn't visible to the bound user"},
+	ldap.LDAPResultInvalidDNSyntax:              {"Invalid DN", "Check the DN for typos and escape special characters such as commas"},
+	ldap.LDAPResultInappropriateAuthentication:  {"Inappropriate authentication", "Anonymous or unauthenticated binds aren't allowed; enter a bind user and password"},
+	ldap.LDAPResultInvalidCredentials:           {"Invalid credentials", "Check the bind user and password"},
+	ldap.LDAPResultInsufficientAccessRights:     {"Insufficient access rights", "The bound user isn't allowed to do this; bind as a user with the needed permissions"},
+	ldap.LDAPResultBusy:                         {"Server busy", "Try again in a moment"},
+	ldap.LDAPResultUnavailable:                  {"Server unavailable", "The server is shutting down or not ready; try again later or connect to another server"},
+	ldap.LDAPResultUnwillingToPerform:           {"Server unwilling to perform", "The server refuses this operation, for example a password change over an unencrypted connection"},
+	ldap.LDAPResultNamingViolation:              {"Naming violation", "The entry's RDN or location isn't allowed by the schema"},
+	ldap.LDAPResultObjectClassViolation:         {"Object class violation", "A required attribute is missing or an attribute isn't allowed by the entry's object classes"},
+	ldap.LDAPResultNotAllowedOnNonLeaf:          {"Entry has children", "Delete or move the entries below it first"},
+	ldap.LDAPResultNotAllowedOnRDN:              {"Can't change the naming attribute", "Rename the entry instead of modifying its RDN attribute"},
+	ldap.LDAPResultEntryAlreadyExists:           {"Entry already exists", "Choose a different name for the new entry"},
+	ldap.LDAPResultObjectClassModsProhibited:    {"Object class change not allowed", "The server doesn't allow changing this entry's structural object class"},
+	ldap.ErrorNetwork:                           {"Network error", "Check the host and port and that the server is reachable"},
+}
@@ -0,0 +1,73 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestExplainError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		summary string
+	}{
+		{"invalid credentials", ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials")), "Invalid credentials"},
+		{"insufficient access", ldap.NewError(ldap.LDAPResultInsufficientAccessRights, errors.New("no access")), "Insufficient access rights"},
+		{"no such object", ldap.NewError(ldap.LDAPResultNoSuchObject, errors.New("no such object")), "Entry not found"},
+		{"confidentiality required", ldap.NewError(ldap.LDAPResultConfidentialityRequired, errors.New("TLS required")), "Encrypted connection required"},
+		{"non-leaf", ldap.NewError(ldap.LDAPResultNotAllowedOnNonLeaf, errors.New("has children")), "Entry has children"},
+		{"wrapped", fmt.Errorf("search failed: %w", ldap.NewError(ldap.LDAPResultSizeLimitExceeded, errors.New("limit"))), "Size limit exceeded"},
+		{"AD user not found", ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("80090308: LdapErr: DSID-0C09042A, comment: AcceptSecurityContext error, data 525, v3839")), "User not found"},
+		{"AD wrong password", ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("80090308: LdapErr: DSID-0C09042A, comment: AcceptSecurityContext error, data 52e, v3839")), "Invalid credentials"},
+		{"AD locked out", ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("80090308: LdapErr: DSID-0C09042A, comment: AcceptSecurityContext error, data 775, v3839")), "Account locked out"},
+		{"AD unknown data code", ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("AcceptSecurityContext error, data 999, v3839")), "Invalid credentials"},
+		{"AD extended code", ldap.NewError(ldap.LDAPResultInsufficientAccessRights, errors.New("00002098: SecErr: DSID-03150E2E, problem 4003 (INSUFF_ACCESS_RIGHTS), data 0")), "Insufficient access rights"},
+		{"AD password policy", ldap.NewError(ldap.LDAPResultUnwillingToPerform, errors.New("0000052D: SvcErr: DSID-031A12D2, problem 5003 (WILL_NOT_PERFORM), data 0")), "Password doesn't meet the password policy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation, ok := ExplainError(tt.err)
+			if !ok {
+				t.Fatalf("Expected an explanation for %v", tt.err)
+			}
+			if explanation.Summary != tt.summary {
+				t.Errorf("Expected %q, got %q", tt.summary, explanation.Summary)
+			}
+			if explanation.Fix == "" {
+				t.Error("Expected a likely fix")
+			}
+		})
+	}
+
+	if _, ok := ExplainError(errors.New("something else")); ok {
+		t.Error("Expected no explanation for an unknown error")
+	}
+	if _, ok := ExplainError(nil); ok {
+		t.Error("Expected no explanation for nil")
+	}
+}
+
+func TestExplainKeepsRawError(t *testing.T) {
+	raw := ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("data 52e"))
+	err := Explain(fmt.Errorf("failed to bind: %w", raw))
+
+	if !strings.HasPrefix(err.Error(), "Invalid credentials. Check the bind user and password (failed to bind: ") {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+	if !ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		t.Error("Expected the raw error to stay reachable")
+	}
+	if Explain(err) != err {
+		t.Error("Expected an explained error not to be explained twice")
+	}
+
+	plain := errors.New("something else")
+	if Explain(plain) != plain || Explain(nil) != nil {
+		t.Error("Expected unknown errors to be returned unchanged")
+	}
+}
@@ -0,0 +1,149 @@
+package ldap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// generalizedTimeLayouts maps the length of the date/time digits to a layout,
+// since GeneralizedTime allows omitting minutes and seconds (RFC 4517 3.3.13)
+var generalizedTimeLayouts = map[int]string{
+	10: "2006010215",
+	12: "200601021504",
+	14: "20060102150405",
+}
+
+// ParseGeneralizedTime parses an LDAP GeneralizedTime value such as
+// "20240131235959Z", "20240131235959.0Z" or "202401312359+0100"
+func ParseGeneralizedTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	// Split off the timezone designator
+	var digits, zone string
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		digits, zone = value[:len(value)-1], "Z"
+	case len(value) > 5 && (value[len(value)-5] == '+' || value[len(value)-5] == '-'):
+		digits, zone = value[:len(value)-5], value[len(value)-5:]
+	default:
+		return time.Time{}, fmt.Errorf("invalid generalized time %q: missing timezone", value)
+	}
+
+	// Drop fractional seconds, which the UI doesn't need
+	if idx := strings.IndexAny(digits, ".,"); idx >= 0 {
+		digits = digits[:idx]
+	}
+
+	layout, ok := generalizedTimeLayouts[len(digits)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid generalized time %q", value)
+	}
+
+	if zone == "Z" {
+		t, err := time.ParseInLocation(layout, digits, time.UTC)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid generalized time %q: %w", value, err)
+		}
+		return t, nil
+	}
+
+	t, err := time.Parse(layout+"-0700", digits+zone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid generalized time %q: %w", value, err)
+	}
+	return t, nil
+}
+
+// FormatGeneralizedTime formats a time as an LDAP GeneralizedTime value in UTC
+func FormatGeneralizedTime(t time.Time) string {
+	return t.UTC().Format("20060102150405") + "Z"
+}
+
+// FormatGUID formats an Active Directory objectGUID, which is returned as 16
+// raw bytes with the first three groups little-endian. Values that are already
+// textual (such as an OpenLDAP entryUUID) are returned unchanged.
+func FormatGUID(value string) string {
+	b := []byte(value)
+	if len(b) != 16 {
+		return value
+	}
+	return fmt.Sprintf("%02x%02x%02x%02x-%02x%02x-%02x%02x-%02x%02x-%02x%02x%02x%02x%02x%02x",
+		b[3], b[2], b[1], b[0],
+		b[5], b[4],
+		b[7], b[6],
+		b[8], b[9],
+		b[10], b[11], b[12], b[13], b[14], b[15])
+}
+
+// FormatSID formats an Active Directory security identifier, such as
+// objectSid, which is returned in its binary form, as "S-1-5-21-...". It
+// reports false for values that aren't a binary SID.
+func FormatSID(value string) (string, bool) {
+	b := []byte(value)
+	if len(b) < 8 || b[0] != 1 || len(b) != 8+4*int(b[1]) {
+		return "", false
+	}
+	// The identifier authority is 48 bits big-endian, the sub-authorities
+	// that follow 32 bits little-endian
+	var authority uint64
+	for _, octet := range b[2:8] {
+		authority = authority<<8 | uint64(octet)
+	}
+	sid := fmt.Sprintf("S-%d-%d", b[0], authority)
+	for i := 8; i < len(b); i += 4 {
+		sid += "-" + strconv.FormatUint(uint64(binary.LittleEndian.Uint32(b[i:])), 10)
+	}
+	return sid, true
+}
+
+// fileTimeAttributes are the Active Directory attributes holding a Windows
+// FILETIME: 100-nanosecond intervals since 1601 as a decimal integer
+var fileTimeAttributes = map[string]bool{
+	"accountexpires":     true,
+	"badpasswordtime":    true,
+	"lastlogoff":         true,
+	"lastlogon":          true,
+	"lastlogontimestamp": true,
+	"lockouttime":        true,
+	"pwdlastset":         true,
+}
+
+// fileTimeEpoch is the FILETIME of the Unix epoch
+const fileTimeEpoch = 116444736000000000
+
+// ParseFileTime converts a Windows FILETIME value to a time. Zero and the
+// largest value, which Active Directory uses for "never", report false.
+func ParseFileTime(value string) (time.Time, bool) {
+	ticks, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || ticks <= 0 || ticks == math.MaxInt64 {
+		return time.Time{}, false
+	}
+	ticks -= fileTimeEpoch
+	return time.Unix(ticks/10000000, ticks%10000000*100).UTC(), true
+}
+
+// DecodeValue renders a value stored in a machine form readably: GUIDs and
+// SIDs of Active Directory, FILETIME timestamps and GeneralizedTime. It
+// reports false when the attribute holds nothing it knows how to decode.
+func DecodeValue(attribute, value string) (string, bool) {
+	name := strings.ToLower(attribute)
+	switch {
+	case strings.Contains(name, "guid") && len(value) == 16:
+		return FormatGUID(value), true
+	case strings.Contains(name, "sid"):
+		return FormatSID(value)
+	case fileTimeAttributes[name]:
+		if t, ok := ParseFileTime(value); ok {
+			return t.Format("2006-01-02 15:04:05 UTC"), true
+		}
+		return "", false
+	}
+	if t, err := ParseGeneralizedTime(value); err == nil {
+		return t.UTC().Format("2006-01-02 15:04:05 UTC"), true
+	}
+	return "", false
+}
@@ -0,0 +1,109 @@
+package ldap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseGeneralizedTime(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"20240131235959Z", time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)},
+		{"20240131235959.0Z", time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)},
+		{"202401312359Z", time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC)},
+		{"2024013123Z", time.Date(2024, 1, 31, 23, 0, 0, 0, time.UTC)},
+		{"20240201003000+0100", time.Date(2024, 1, 31, 23, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseGeneralizedTime(tt.value)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseGeneralizedTime_Invalid(t *testing.T) {
+	for _, value := range []string{"", "20240131235959", "2024Z", "not-a-timeZ"} {
+		if _, err := ParseGeneralizedTime(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+func TestFormatGeneralizedTime(t *testing.T) {
+	local := time.FixedZone("test", 2*60*60)
+	got := FormatGeneralizedTime(time.Date(2024, 6, 1, 12, 0, 0, 0, local))
+	if got != "20240601100000Z" {
+		t.Errorf("Expected 20240601100000Z, got %s", got)
+	}
+}
+
+func TestFormatGUID(t *testing.T) {
+	raw := string([]byte{0x78, 0x56, 0x34, 0x12, 0xbc, 0x9a, 0xf0, 0xde, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef})
+	if got := FormatGUID(raw); got != "12345678-9abc-def0-0123-456789abcdef" {
+		t.Errorf("Unexpected GUID: %s", got)
+	}
+
+	uuid := "b3c8c5e2-1f0a-103e-8f5d-9b1d2c3a4e5f"
+	if got := FormatGUID(uuid); got != uuid {
+		t.Errorf("Expected textual UUID unchanged, got %s", got)
+	}
+}
+
+func TestFormatSID(t *testing.T) {
+	// S-1-5-21-1004336348-1177238915-682003330-512, Domain Admins
+	raw := string([]byte{
+		0x01, 0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05,
+		0x15, 0x00, 0x00, 0x00,
+		0xdc, 0xf4, 0xdc, 0x3b,
+		0x83, 0x3d, 0x2b, 0x46,
+		0x82, 0x8b, 0xa6, 0x28,
+		0x00, 0x02, 0x00, 0x00,
+	})
+	if got, ok := FormatSID(raw); !ok || got != "S-1-5-21-1004336348-1177238915-682003330-512" {
+		t.Errorf("Unexpected SID: %s (%v)", got, ok)
+	}
+	if _, ok := FormatSID("S-1-5-32-544"); ok {
+		t.Error("Expected a textual SID not to be decoded")
+	}
+}
+
+func TestParseFileTime(t *testing.T) {
+	got, ok := ParseFileTime("133485408000000000")
+	if want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !ok || !got.Equal(want) {
+		t.Errorf("Expected %v, got %v (%v)", want, got, ok)
+	}
+	for _, never := range []string{"0", "9223372036854775807", "never"} {
+		if _, ok := ParseFileTime(never); ok {
+			t.Errorf("Expected %q not to be a time", never)
+		}
+	}
+}
+
+func TestDecodeValue(t *testing.T) {
+	guid := string([]byte{0x78, 0x56, 0x34, 0x12, 0xbc, 0x9a, 0xf0, 0xde, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef})
+	tests := []struct {
+		attribute, value, want string
+		ok                     bool
+	}{
+		{"objectGUID", guid, "12345678-9abc-def0-0123-456789abcdef", true},
+		{"pwdLastSet", "133485408000000000", "2024-01-01 00:00:00 UTC", true},
+		{"modifyTimestamp", "20240131235959Z", "2024-01-31 23:59:59 UTC", true},
+		{"cn", "alice", "", false},
+		{"accountExpires", "9223372036854775807", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := DecodeValue(tt.attribute, tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("DecodeValue(%s) = %q, %v; want %q, %v", tt.attribute, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Bind methods accepted in Config.BindMethod. An empty method is a simple bind.
+const (
+	BindMethodSimple = "simple"
+	BindMethodGSSAPI = "gssapi"
+)
+
+// gssapiClient is a GSSAPI client that holds credentials to release after binding
+type gssapiClient interface {
+	ldap.GSSAPIClient
+	Close() error
+}
+
+// bind authenticates conn with the configured bind method. Simple binds are
+// skipped when no bind user is set, leaving the connection anonymous.
+func (config Config) bind(conn *ldap.Conn) error {
+	switch strings.ToLower(config.BindMethod) {
+	case "", BindMethodSimple:
+		if config.BindUser == "" {
+			// Binding anonymously outright makes servers that refuse
+			// anonymous access say so now rather than on the first search
+			return conn.UnauthenticatedBind("")
+		}
+		return conn.Bind(config.BindUser, config.BindPass)
+	case BindMethodGSSAPI:
+		client, err := config.gssapiClient()
+		if err != nil {
+			return fmt.Errorf("failed to get Kerberos credentials: %w", err)
+		}
+		defer client.Close()
+		return conn.GSSAPIBind(client, config.servicePrincipal(), "")
+	}
+	return fmt.Errorf("unsupported bind method %q", config.BindMethod)
+}
+
+// servicePrincipal returns the LDAP service principal of the configured host
+func (config Config) servicePrincipal() string {
+	return "ldap/" + config.Host
+}
@@ -0,0 +1,47 @@
+//go:build !windows
+
+package ldap
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3/gssapi"
+)
+
+// gssapiClient creates a Kerberos client from, in order of preference, the
+// configured keytab, the bind password, or the user's credential cache as
+// filled by kinit
+func (config Config) gssapiClient() (gssapiClient, error) {
+	krb5conf := config.kerberosConfigPath()
+	switch {
+	case config.KerberosKeytab != "":
+		return gssapi.NewClientWithKeytab(config.BindUser, config.KerberosRealm, config.KerberosKeytab, krb5conf)
+	case config.BindPass != "":
+		return gssapi.NewClientWithPassword(config.BindUser, config.KerberosRealm, config.BindPass, krb5conf)
+	default:
+		return gssapi.NewClientFromCCache(credentialCachePath(), krb5conf)
+	}
+}
+
+// kerberosConfigPath returns the krb5.conf to use: the configured one, then
+// $KRB5_CONFIG, then the system default
+func (config Config) kerberosConfigPath() string {
+	if config.KerberosConfig != "" {
+		return config.KerberosConfig
+	}
+	if path := os.Getenv("KRB5_CONFIG"); path != "" {
+		return path
+	}
+	return "/etc/krb5.conf"
+}
+
+// credentialCachePath returns the user's file credential cache, following
+// $KRB5CCNAME like the MIT Kerberos tools
+func credentialCachePath() string {
+	if name := os.Getenv("KRB5CCNAME"); name != "" {
+		return strings.TrimPrefix(name, "FILE:")
+	}
+	return fmt.Sprintf("/tmp/krb5cc_%d", os.Getuid())
+}
@@ -0,0 +1,82 @@
+//go:build !windows
+
+package ldap
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigBind_Simple(t *testing.T) {
+	client := newFakeServerClient(t, 0)
+	config := Config{BindUser: "cn=admin,dc=example,dc=com", BindPass: "secret"}
+	if err := config.bind(client.conn); err != nil {
+		t.Errorf("Expected simple bind to succeed, got %v", err)
+	}
+
+	client = newFakeServerClient(t, 49) // invalidCredentials
+	config.BindMethod = BindMethodSimple
+	if err := config.bind(client.conn); err == nil {
+		t.Error("Expected rejected credentials to fail the bind")
+	}
+}
+
+func TestConfigBind_AnonymousWithoutUser(t *testing.T) {
+	// An anonymous bind is sent so servers that refuse it say so on connect
+	if err := (Config{}).bind(newFakeServerClient(t, 0).conn); err != nil {
+		t.Errorf("Expected an anonymous connection, got %v", err)
+	}
+	if err := (Config{}).bind(newFakeServerClient(t, 48).conn); err == nil { // inappropriateAuthentication
+		t.Error("Expected a refused anonymous bind to fail")
+	}
+}
+
+func TestConfigBind_UnsupportedMethod(t *testing.T) {
+	err := (Config{BindMethod: "digest-md5"}).bind(nil)
+	if err == nil || !strings.Contains(err.Error(), `unsupported bind method "digest-md5"`) {
+		t.Errorf("Expected an unsupported bind method error, got %v", err)
+	}
+}
+
+func TestConfigBind_GSSAPIWithoutCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("KRB5CCNAME", "FILE:"+filepath.Join(dir, "missing-ccache"))
+
+	config := Config{
+		Host:           "dc1.example.com",
+		BindMethod:     "GSSAPI",
+		KerberosConfig: filepath.Join(dir, "missing-krb5.conf"),
+	}
+	err := config.bind(nil)
+	if err == nil || !strings.Contains(err.Error(), "failed to get Kerberos credentials") {
+		t.Errorf("Expected a Kerberos credentials error, got %v", err)
+	}
+}
+
+func TestKerberosPaths(t *testing.T) {
+	t.Setenv("KRB5CCNAME", "FILE:/tmp/custom_ccache")
+	if got := credentialCachePath(); got != "/tmp/custom_ccache" {
+		t.Errorf("Expected KRB5CCNAME without the FILE: prefix, got %q", got)
+	}
+	t.Setenv("KRB5CCNAME", "")
+	if got := credentialCachePath(); !strings.HasPrefix(got, "/tmp/krb5cc_") {
+		t.Errorf("Expected the default credential cache, got %q", got)
+	}
+
+	t.Setenv("KRB5_CONFIG", "/etc/custom-krb5.conf")
+	if got := (Config{}).kerberosConfigPath(); got != "/etc/custom-krb5.conf" {
+		t.Errorf("Expected $KRB5_CONFIG, got %q", got)
+	}
+	if got := (Config{KerberosConfig: "/opt/krb5.conf"}).kerberosConfigPath(); got != "/opt/krb5.conf" {
+		t.Errorf("Expected the configured krb5.conf, got %q", got)
+	}
+	t.Setenv("KRB5_CONFIG", "")
+	if got := (Config{}).kerberosConfigPath(); got != "/etc/krb5.conf" {
+		t.Errorf("Expected the system krb5.conf, got %q", got)
+	}
+
+	if got := (Config{Host: "dc1.example.com"}).servicePrincipal(); got != "ldap/dc1.example.com" {
+		t.Errorf("Unexpected service principal %q", got)
+	}
+}
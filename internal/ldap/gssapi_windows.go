@@ -0,0 +1,21 @@
+//go:build windows
+
+package ldap
+
+import (
+	"errors"
+
+	"github.com/go-ldap/ldap/v3/gssapi"
+)
+
+// gssapiClient creates a client through Windows SSPI, using the logged on
+// user's credentials unless a bind user and password are configured
+func (config Config) gssapiClient() (gssapiClient, error) {
+	if config.KerberosKeytab != "" {
+		return nil, errors.New("keytabs are not supported on Windows; the logged on user's credentials are used")
+	}
+	if config.BindUser != "" && config.BindPass != "" {
+		return gssapi.NewSSPIClientWithUserCredentials(config.KerberosRealm, config.BindUser, config.BindPass)
+	}
+	return gssapi.NewSSPIClient()
+}
@@ -0,0 +1,373 @@
+// Package ldaptest provides an in-memory LDAP server for tests. It speaks
+// just enough of the protocol for moribito's client: simple binds, searches
+// with the paged results control, unbind, and the notice of disconnection.
+package ldaptest
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Entry is a directory entry served by Server
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// Server is an LDAP server on localhost holding a fixed set of entries.
+// Search filters aren't evaluated: every entry within the requested base and
+// scope matches, which keeps results predictable for tests.
+type Server struct {
+	// BindDN and BindPassword are the only credentials accepted; anonymous
+	// binds are accepted too while BindDN is empty
+	BindDN       string
+	BindPassword string
+
+	// RefuseAnonymous, when set, is the result code anonymous binds are
+	// answered with, as by servers with anonymous access disabled
+	RefuseAnonymous int
+
+	// MaxValueRange, when set, caps the values returned per attribute the way
+	// Active Directory's MaxValRange does: longer attributes come back as
+	// name;range=0-(MaxValueRange-1) and the rest is read with range options
+	MaxValueRange int
+
+	entries  []Entry
+	listener net.Listener
+
+	mu       sync.Mutex
+	searches int
+	derefs   []int             // Alias dereferencing mode of each search
+	filters  []string          // Filter of each search
+	attrs    [][]string        // Attributes each search asked for
+	conns    map[net.Conn]bool // Open client connections
+	accepted int
+}
+
+// NewServer starts a server holding entries and stops it when the test ends
+func NewServer(t testing.TB, entries ...Entry) *Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{entries: entries, listener: listener, conns: make(map[net.Conn]bool)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// Port returns the port the server listens on
+func (s *Server) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Searches returns the number of search requests answered, one per page
+func (s *Server) Searches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.searches
+}
+
+// DerefAliases returns the alias dereferencing mode each search asked for,
+// in the order the searches arrived
+func (s *Server) DerefAliases() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.derefs...)
+}
+
+// Filters returns the filter of each search in the order the searches
+// arrived. They are recorded but not evaluated.
+func (s *Server) Filters() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.filters...)
+}
+
+// Attributes returns the attributes each search asked for, in the order the
+// searches arrived
+func (s *Server) Attributes() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.attrs...)
+}
+
+// Connections returns the number of client connections accepted
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted
+}
+
+// Disconnect sends every open connection a notice of disconnection with the
+// unavailable result code and closes it, the way a server shutting down does
+func (s *Server) Disconnect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		notice := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationExtendedResponse, nil, "Extended Response")
+		notice.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(ldap.LDAPResultUnavailable), "resultCode"))
+		notice.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+		notice.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "server is shutting down", "diagnosticMessage"))
+		notice.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 10, "1.3.6.1.4.1.1466.20036", "responseName"))
+		conn.Write(envelope(0, notice, nil).Bytes())
+		conn.Close()
+		delete(s.conns, conn)
+	}
+}
+
+// serve answers the requests of one client until it unbinds or goes away
+func (s *Server) serve(conn net.Conn) {
+	s.mu.Lock()
+	s.conns[conn] = true
+	s.accepted++
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageID := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+
+		var responses []*ber.Packet
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			responses = []*ber.Packet{s.bind(messageID, request)}
+		case ldap.ApplicationSearchRequest:
+			responses = s.search(messageID, request, packet)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			continue
+		}
+
+		for _, response := range responses {
+			if _, err := conn.Write(response.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// bind checks the credentials of a simple bind
+func (s *Server) bind(messageID int64, request *ber.Packet) *ber.Packet {
+	dn := packetString(request.Children[1])
+	password := packetString(request.Children[2])
+
+	code := ldap.LDAPResultSuccess
+	if dn == "" && password == "" && s.RefuseAnonymous != 0 {
+		code = s.RefuseAnonymous
+	} else if dn != s.BindDN || password != s.BindPassword {
+		code = ldap.LDAPResultInvalidCredentials
+	}
+	return response(messageID, ldap.ApplicationBindResponse, code, nil)
+}
+
+// search returns the entries within the requested base and scope, a page at
+// a time when the client sends the paged results control. The cookie is the
+// offset of the next page.
+func (s *Server) search(messageID int64, request, packet *ber.Packet) []*ber.Packet {
+	base := strings.ToLower(packetString(request.Children[0]))
+	scope := int(request.Children[1].Value.(int64))
+	deref := int(request.Children[2].Value.(int64))
+	filter, _ := ldap.DecompileFilter(request.Children[6])
+
+	s.mu.Lock()
+	s.searches++
+	s.derefs = append(s.derefs, deref)
+	s.filters = append(s.filters, filter)
+	s.mu.Unlock()
+
+	var attributes []string
+	for _, child := range request.Children[7].Children {
+		attributes = append(attributes, packetString(child))
+	}
+	s.mu.Lock()
+	s.attrs = append(s.attrs, attributes)
+	s.mu.Unlock()
+
+	var matches []Entry
+	found := base == ""
+	for _, entry := range s.entries {
+		dn := strings.ToLower(entry.DN)
+		if dn == base {
+			found = true
+		}
+		if inScope(dn, base, scope) {
+			matches = append(matches, entry)
+		}
+	}
+	if !found {
+		return []*ber.Packet{response(messageID, ldap.ApplicationSearchResultDone, ldap.LDAPResultNoSuchObject, nil)}
+	}
+
+	var paging *ldap.ControlPaging
+	if len(packet.Children) > 2 {
+		for _, child := range packet.Children[2].Children {
+			if control, err := ldap.DecodeControl(child); err == nil {
+				if p, ok := control.(*ldap.ControlPaging); ok {
+					paging = p
+				}
+			}
+		}
+	}
+
+	var controls *ber.Packet
+	if paging != nil && paging.PagingSize > 0 {
+		offset, _ := strconv.Atoi(string(paging.Cookie))
+		end := min(offset+int(paging.PagingSize), len(matches))
+		offset = min(offset, end)
+
+		next := ldap.NewControlPaging(paging.PagingSize)
+		if end < len(matches) {
+			next.SetCookie([]byte(strconv.Itoa(end)))
+		}
+		controls = ber.Encode(ber.ClassContext, ber.TypeConstructed, 0, nil, "Controls")
+		controls.AppendChild(next.Encode())
+		matches = matches[offset:end]
+	}
+
+	responses := make([]*ber.Packet, 0, len(matches)+1)
+	for _, entry := range matches {
+		responses = append(responses, s.searchEntry(messageID, entry, attributes))
+	}
+	return append(responses, response(messageID, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, controls))
+}
+
+// inScope reports whether dn is within scope of base, both lower-cased
+func inScope(dn, base string, scope int) bool {
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return dn == base
+	case ldap.ScopeSingleLevel:
+		parent := ""
+		if i := strings.Index(dn, ","); i >= 0 {
+			parent = dn[i+1:]
+		}
+		return dn != base && parent == base
+	default:
+		return dn == base || base == "" || strings.HasSuffix(dn, ","+base)
+	}
+}
+
+// searchEntry encodes entry as a search result with the requested attributes.
+// Attributes requested with a range option, or longer than MaxValueRange,
+// are returned as a range of their values.
+func (s *Server) searchEntry(messageID int64, entry Entry, attributes []string) *ber.Packet {
+	all := len(attributes) == 0
+	wanted := make(map[string]bool, len(attributes))
+	ranges := make(map[string]int)
+	for _, name := range attributes {
+		if name == "*" {
+			all = true
+		}
+		if base, start, ok := rangeRequest(name); ok {
+			ranges[strings.ToLower(base)] = start
+			name = base
+		}
+		wanted[strings.ToLower(name)] = true
+	}
+
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "objectName"))
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for name, values := range entry.Attributes {
+		if !all && !wanted[strings.ToLower(name)] {
+			continue
+		}
+		start, ranged := ranges[strings.ToLower(name)]
+		if ranged || (s.MaxValueRange > 0 && len(values) > s.MaxValueRange) {
+			start = min(start, len(values))
+			end := len(values)
+			if s.MaxValueRange > 0 {
+				end = min(start+s.MaxValueRange, len(values))
+			}
+			last := "*"
+			if end < len(values) {
+				last = strconv.Itoa(end - 1)
+			}
+			name = fmt.Sprintf("%s;range=%d-%s", name, start, last)
+			values = values[start:end]
+		}
+
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "value"))
+		}
+		attribute.AppendChild(set)
+		list.AppendChild(attribute)
+	}
+	result.AppendChild(list)
+	return envelope(messageID, result, nil)
+}
+
+// rangeRequest parses a requested attribute such as member;range=1500-*
+func rangeRequest(name string) (string, int, bool) {
+	base, option, found := strings.Cut(name, ";range=")
+	if !found {
+		return name, 0, false
+	}
+	first, _, _ := strings.Cut(option, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil {
+		return name, 0, false
+	}
+	return base, start, true
+}
+
+// response encodes an LDAP result with the given result code
+func response(messageID int64, tag ber.Tag, code int, controls *ber.Packet) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+	return envelope(messageID, result, controls)
+}
+
+// envelope wraps an operation in an LDAP message
+func envelope(messageID int64, operation, controls *ber.Packet) *ber.Packet {
+	message := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	message.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	message.AppendChild(operation)
+	if controls != nil {
+		message.AppendChild(controls)
+	}
+	return message
+}
+
+// packetString returns the value of an octet string packet
+func packetString(packet *ber.Packet) string {
+	if value, ok := packet.Value.(string); ok {
+		return value
+	}
+	return packet.Data.String()
+}
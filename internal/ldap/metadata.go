@@ -0,0 +1,31 @@
+package ldap
+
+import (
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// MetadataAttributes are the operational attributes describing when and by whom
+// an entry was created and modified, covering both RFC 4512 style servers and
+// Active Directory
+var MetadataAttributes = []string{
+	"createTimestamp", "whenCreated",
+	"modifyTimestamp", "whenChanged",
+	"creatorsName", "modifiersName",
+	"entryUUID", "objectGUID",
+}
+
+// GetMetadata reads the provenance attributes of an entry. Operational
+// attributes are requested with "+" as well as by name, since servers without
+// RFC 3673 support only return them when asked for explicitly.
+func (c *Client) GetMetadata(dn string) (map[string][]string, error) {
+	entries, err := c.Search(dn, "(objectClass=*)", ldap.ScopeBaseObject, append([]string{"+"}, MetadataAttributes...))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, dn)
+	}
+	return entries[0].Attributes, nil
+}
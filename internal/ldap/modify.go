@@ -0,0 +1,70 @@
+package ldap
+
+import (
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ModifyOperation identifies how an AttributeChange is applied
+type ModifyOperation int
+
+const (
+	ModifyAdd ModifyOperation = iota
+	ModifyDelete
+	ModifyReplace
+)
+
+// AttributeChange describes a single change to one attribute of an entry
+type AttributeChange struct {
+	Operation ModifyOperation
+	Attribute string
+	Values    []string
+}
+
+// Modify applies a set of attribute changes to an entry in a single request
+func (c *Client) Modify(dn string, changes []AttributeChange) error {
+	if c.ReadOnly() {
+		return ErrReadOnly
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	modifyRequest := buildModifyRequest(dn, changes)
+
+	return c.audited(AuditModify, dn, summarizeModify(changes), func() error {
+		return c.withRetry(func() error {
+			if err := c.connection().Modify(modifyRequest); err != nil {
+				return fmt.Errorf("modify failed: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+// ReplaceAttribute replaces all values of an attribute. An empty values slice
+// removes the attribute from the entry.
+func (c *Client) ReplaceAttribute(dn, attribute string, values []string) error {
+	return c.Modify(dn, []AttributeChange{
+		{Operation: ModifyReplace, Attribute: attribute, Values: values},
+	})
+}
+
+// buildModifyRequest converts attribute changes into a go-ldap modify request
+func buildModifyRequest(dn string, changes []AttributeChange) *ldap.ModifyRequest {
+	modifyRequest := ldap.NewModifyRequest(dn, nil)
+
+	for _, change := range changes {
+		switch change.Operation {
+		case ModifyAdd:
+			modifyRequest.Add(change.Attribute, change.Values)
+		case ModifyDelete:
+			modifyRequest.Delete(change.Attribute, change.Values)
+		case ModifyReplace:
+			modifyRequest.Replace(change.Attribute, change.Values)
+		}
+	}
+
+	return modifyRequest
+}
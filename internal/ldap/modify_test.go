@@ -0,0 +1,29 @@
+package ldap
+
+import "testing"
+
+func TestBuildModifyRequest(t *testing.T) {
+	req := buildModifyRequest("cn=test,dc=example,dc=com", []AttributeChange{
+		{Operation: ModifyAdd, Attribute: "member", Values: []string{"cn=a,dc=example,dc=com"}},
+		{Operation: ModifyDelete, Attribute: "member", Values: []string{"cn=b,dc=example,dc=com"}},
+		{Operation: ModifyReplace, Attribute: "description", Values: nil},
+	})
+
+	if req.DN != "cn=test,dc=example,dc=com" {
+		t.Errorf("Expected DN to be preserved, got %s", req.DN)
+	}
+	if len(req.Changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %d", len(req.Changes))
+	}
+
+	// go-ldap operation codes: 0 = add, 1 = delete, 2 = replace
+	expectedOps := []uint{0, 1, 2}
+	for i, change := range req.Changes {
+		if change.Operation != expectedOps[i] {
+			t.Errorf("Change %d: expected operation %d, got %d", i, expectedOps[i], change.Operation)
+		}
+	}
+	if req.Changes[2].Modification.Type != "description" || len(req.Changes[2].Modification.Vals) != 0 {
+		t.Errorf("Expected empty replace of description, got %+v", req.Changes[2].Modification)
+	}
+}
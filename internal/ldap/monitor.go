@@ -0,0 +1,168 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ErrMonitorUnavailable is returned by GetMonitorStats when the server has no
+// monitor backend, or none of its statistics could be read
+var ErrMonitorUnavailable = errors.New("server does not publish monitor statistics")
+
+// Monitor statistics, in the order they are shown
+const (
+	MonitorCurrentConnections  = "Current connections"
+	MonitorTotalConnections    = "Total connections"
+	MonitorOperationsInitiated = "Operations initiated"
+	MonitorOperationsCompleted = "Operations completed"
+	MonitorReadWaiters         = "Read waiters"
+	MonitorWriteWaiters        = "Write waiters"
+	MonitorThreads             = "Threads"
+	MonitorStarted             = "Started"
+	MonitorVersion             = "Version"
+)
+
+// MonitorStatNames lists the statistics GetMonitorStats may return, in the
+// order they are shown
+var MonitorStatNames = []string{
+	MonitorCurrentConnections,
+	MonitorTotalConnections,
+	MonitorOperationsInitiated,
+	MonitorOperationsCompleted,
+	MonitorReadWaiters,
+	MonitorWriteWaiters,
+	MonitorThreads,
+	MonitorStarted,
+	MonitorVersion,
+}
+
+// monitorSources lists where OpenLDAP's back-monitor and 389 Directory Server
+// keep each statistic. OpenLDAP spreads them over entries below cn=Monitor;
+// 389-ds puts them all on cn=monitor.
+var monitorSources = []struct {
+	DN        string
+	Attribute string
+	Stat      string
+}{
+	// OpenLDAP
+	{"cn=current,cn=connections,cn=monitor", "monitorCounter", MonitorCurrentConnections},
+	{"cn=total,cn=connections,cn=monitor", "monitorCounter", MonitorTotalConnections},
+	{"cn=operations,cn=monitor", "monitorOpInitiated", MonitorOperationsInitiated},
+	{"cn=operations,cn=monitor", "monitorOpCompleted", MonitorOperationsCompleted},
+	{"cn=read,cn=waiters,cn=monitor", "monitorCounter", MonitorReadWaiters},
+	{"cn=write,cn=waiters,cn=monitor", "monitorCounter", MonitorWriteWaiters},
+	{"cn=active,cn=threads,cn=monitor", "monitoredInfo", MonitorThreads},
+	{"cn=start,cn=time,cn=monitor", "monitorTimestamp", MonitorStarted},
+	{"cn=monitor", "monitoredInfo", MonitorVersion},
+
+	// 389 Directory Server
+	{"cn=monitor", "currentConnections", MonitorCurrentConnections},
+	{"cn=monitor", "totalConnections", MonitorTotalConnections},
+	{"cn=monitor", "opsInitiated", MonitorOperationsInitiated},
+	{"cn=monitor", "opsCompleted", MonitorOperationsCompleted},
+	{"cn=monitor", "readWaiters", MonitorReadWaiters},
+	{"cn=monitor", "threads", MonitorThreads},
+	{"cn=monitor", "startTime", MonitorStarted},
+	{"cn=monitor", "version", MonitorVersion},
+}
+
+// GetMonitorStats reads the server's operational statistics from its monitor
+// backend: connections, operations, waiters and the like, keyed by the
+// Monitor* names. Statistics the server doesn't keep are left out; a server
+// without a monitor backend returns ErrMonitorUnavailable.
+func (c *Client) GetMonitorStats() (map[string]string, error) {
+	// Only the entries of monitorSources are read, each asking for its
+	// statistics by name: OpenLDAP keeps an entry per connection below
+	// cn=monitor, and only returns its monitor attributes when they are
+	// requested. cn=monitor comes first, so a missing monitor backend is
+	// found before anything else is read.
+	var entries []*Entry
+	for _, dn := range monitorSourceDNs() {
+		found, err := c.Search(dn, "(objectClass=*)", ldap.ScopeBaseObject, monitorSourceAttributes(dn))
+		switch {
+		case err == nil:
+			entries = append(entries, found...)
+		case IsNoSuchObject(err) && dn == "cn=monitor":
+			return nil, ErrMonitorUnavailable
+		case IsNoSuchObject(err):
+			// The server doesn't keep these statistics
+		default:
+			return nil, fmt.Errorf("failed to read %s: %w", dn, err)
+		}
+	}
+	stats := parseMonitorStats(entries)
+	if len(stats) == 0 {
+		return nil, ErrMonitorUnavailable
+	}
+	return stats, nil
+}
+
+// monitorSourceDNs lists the entries of monitorSources once each,
+// cn=monitor first
+func monitorSourceDNs() []string {
+	dns := []string{"cn=monitor"}
+	for _, source := range monitorSources {
+		if !slices.Contains(dns, source.DN) {
+			dns = append(dns, source.DN)
+		}
+	}
+	return dns
+}
+
+// monitorSourceAttributes lists the attributes monitorSources reads from dn
+func monitorSourceAttributes(dn string) []string {
+	var attributes []string
+	for _, source := range monitorSources {
+		if source.DN == dn {
+			attributes = append(attributes, source.Attribute)
+		}
+	}
+	return attributes
+}
+
+// parseMonitorStats collects the statistics of monitorSources found in
+// entries. DNs and attribute names are matched case-insensitively and
+// GeneralizedTime timestamps are shown in UTC.
+func parseMonitorStats(entries []*Entry) map[string]string {
+	byDN := make(map[string]*Entry, len(entries))
+	for _, entry := range entries {
+		byDN[normalizeMonitorDN(entry.DN)] = entry
+	}
+
+	stats := make(map[string]string)
+	for _, source := range monitorSources {
+		if _, ok := stats[source.Stat]; ok {
+			continue
+		}
+		entry, ok := byDN[source.DN]
+		if !ok {
+			continue
+		}
+		values, ok := entry.Lookup(source.Attribute)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		value := strings.TrimSpace(values[0])
+		if source.Stat == MonitorStarted {
+			if t, err := ParseGeneralizedTime(value); err == nil {
+				value = t.UTC().Format("2006-01-02 15:04:05 UTC")
+			}
+		}
+		stats[source.Stat] = value
+	}
+	return stats
+}
+
+// normalizeMonitorDN lowercases dn and drops the spaces around its RDNs'
+// separators, so it can be compared with monitorSources
+func normalizeMonitorDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(part))
+	}
+	return strings.Join(parts, ",")
+}
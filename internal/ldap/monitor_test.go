@@ -0,0 +1,131 @@
+package ldap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+func TestParseMonitorStats_OpenLDAP(t *testing.T) {
+	entries := []*Entry{
+		{DN: "cn=Monitor", Attributes: map[string][]string{"monitoredInfo": {"OpenLDAP: slapd 2.6.7"}}},
+		{DN: "cn=Current,cn=Connections,cn=Monitor", Attributes: map[string][]string{"monitorCounter": {"12"}}},
+		{DN: "cn=Total, cn=Connections, cn=Monitor", Attributes: map[string][]string{"monitorCounter": {"4815"}}},
+		{DN: "cn=Operations,cn=Monitor", Attributes: map[string][]string{"monitorOpInitiated": {"99120"}, "monitorOpCompleted": {"99118"}}},
+		{DN: "cn=Bind,cn=Operations,cn=Monitor", Attributes: map[string][]string{"monitorOpInitiated": {"5000"}}},
+		{DN: "cn=Read,cn=Waiters,cn=Monitor", Attributes: map[string][]string{"monitorCounter": {"3"}}},
+		{DN: "cn=Write,cn=Waiters,cn=Monitor", Attributes: map[string][]string{"monitorCounter": {"0"}}},
+		{DN: "cn=Start,cn=Time,cn=Monitor", Attributes: map[string][]string{"monitorTimestamp": {"20261001080000Z"}}},
+	}
+
+	stats := parseMonitorStats(entries)
+	want := map[string]string{
+		MonitorVersion:             "OpenLDAP: slapd 2.6.7",
+		MonitorCurrentConnections:  "12",
+		MonitorTotalConnections:    "4815",
+		MonitorOperationsInitiated: "99120",
+		MonitorOperationsCompleted: "99118",
+		MonitorReadWaiters:         "3",
+		MonitorWriteWaiters:        "0",
+		MonitorStarted:             "2026-10-01 08:00:00 UTC",
+	}
+	if len(stats) != len(want) {
+		t.Errorf("Expected %d statistics, got %v", len(want), stats)
+	}
+	for name, value := range want {
+		if stats[name] != value {
+			t.Errorf("%s: expected %q, got %q", name, value, stats[name])
+		}
+	}
+}
+
+func TestParseMonitorStats_389DS(t *testing.T) {
+	entries := []*Entry{{
+		DN: "cn=monitor",
+		Attributes: map[string][]string{
+			"version":            {"389-Directory/2.4.5 B2024.017.0000"},
+			"threads":            {"17"},
+			"currentconnections": {"7"},
+			"totalconnections":   {"203"},
+			"opsinitiated":       {"1520"},
+			"opscompleted":       {"1519"},
+			"readwaiters":        {"0"},
+			"starttime":          {"20261014063000Z"},
+		},
+	}}
+
+	stats := parseMonitorStats(entries)
+	for name, value := range map[string]string{
+		MonitorVersion:             "389-Directory/2.4.5 B2024.017.0000",
+		MonitorThreads:             "17",
+		MonitorCurrentConnections:  "7",
+		MonitorOperationsCompleted: "1519",
+		MonitorStarted:             "2026-10-14 06:30:00 UTC",
+	} {
+		if stats[name] != value {
+			t.Errorf("%s: expected %q, got %q", name, value, stats[name])
+		}
+	}
+	if _, ok := stats[MonitorWriteWaiters]; ok {
+		t.Error("Expected a statistic the server doesn't keep to be left out")
+	}
+}
+
+func TestGetMonitorStatsUnavailable(t *testing.T) {
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetMonitorStats(); !errors.Is(err, ErrMonitorUnavailable) {
+		t.Errorf("Expected ErrMonitorUnavailable without cn=monitor, got %v", err)
+	}
+
+	// A monitor entry without statistics is no better
+	server = ldaptest.NewServer(t, ldaptest.Entry{DN: "cn=monitor", Attributes: map[string][]string{"objectClass": {"extensibleObject"}}})
+	client, err = NewClient(Config{Host: "127.0.0.1", Port: server.Port()})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	if _, err := client.GetMonitorStats(); !errors.Is(err, ErrMonitorUnavailable) {
+		t.Errorf("Expected ErrMonitorUnavailable for an empty monitor, got %v", err)
+	}
+}
+
+func TestGetMonitorStatsReadsOnlyItsEntries(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "cn=monitor", Attributes: map[string][]string{"monitoredInfo": {"OpenLDAP: slapd 2.6.7"}}},
+		ldaptest.Entry{DN: "cn=current,cn=connections,cn=monitor", Attributes: map[string][]string{"monitorCounter": {"12"}}},
+		ldaptest.Entry{DN: "cn=connection 1001,cn=connections,cn=monitor", Attributes: map[string][]string{"monitorCounter": {"1"}}},
+	)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port()})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	before := server.Searches()
+	stats, err := client.GetMonitorStats()
+	if err != nil {
+		t.Fatalf("Failed to read the statistics: %v", err)
+	}
+	if stats[MonitorVersion] != "OpenLDAP: slapd 2.6.7" || stats[MonitorCurrentConnections] != "12" {
+		t.Errorf("Unexpected statistics: %v", stats)
+	}
+	if _, ok := stats[MonitorWriteWaiters]; ok {
+		t.Error("Expected a missing monitor entry to be left out")
+	}
+	for _, attributes := range server.Attributes() {
+		if slices.Contains(attributes, "+") || slices.Contains(attributes, "*") {
+			t.Errorf("Expected the statistics to be asked for by name, got %v", attributes)
+		}
+	}
+	if got, want := server.Searches()-before, len(monitorSourceDNs()); got != want {
+		t.Errorf("Expected one base search per monitor entry (%d), got %d", want, got)
+	}
+}
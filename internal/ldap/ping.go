@@ -0,0 +1,32 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Ping reads the root DSE without any attributes ("1.1"), the lightest
+// request a server answers, and returns how long the round trip took. It
+// isn't retried, so a connection that is down is reported rather than timed
+// along with a reconnect. A server refusing the read still answered, so
+// result codes such as insufficient access count as a round trip.
+func (c *Client) Ping() (time.Duration, error) {
+	if c.Closed() {
+		return 0, fmt.Errorf("ping failed: not connected")
+	}
+	request := ldap.NewSearchRequest("", ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)", []string{"1.1"}, nil)
+
+	start := time.Now()
+	_, err := c.connection().Search(request)
+	elapsed := time.Since(start)
+
+	var ldapErr *ldap.Error
+	if err != nil && (!errors.As(err, &ldapErr) || ldapErr.ResultCode >= ldap.ErrorNetwork || ldapErr.ResultCode == ldap.LDAPResultUnavailable) {
+		return 0, fmt.Errorf("ping failed: %w", err)
+	}
+	return elapsed, nil
+}
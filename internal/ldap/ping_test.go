@@ -0,0 +1,45 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+func TestClient_Ping(t *testing.T) {
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	before := server.Searches()
+	rtt, err := client.Ping()
+	if err != nil {
+		t.Fatalf("Expected the ping to succeed, got %v", err)
+	}
+	if rtt <= 0 {
+		t.Errorf("Expected a positive round-trip time, got %v", rtt)
+	}
+	if got := server.Searches() - before; got != 1 {
+		t.Errorf("Expected one search per ping, got %d", got)
+	}
+
+	// Once the server has gone away the ping fails instead of reconnecting
+	server.Disconnect()
+	if _, err := client.Ping(); err == nil {
+		t.Error("Expected a ping on a dropped connection to fail")
+	}
+	if server.Connections() != 1 {
+		t.Errorf("Expected the ping not to reconnect, got %d connections", server.Connections())
+	}
+
+	client.Close()
+	if _, err := client.Ping(); err == nil {
+		t.Error("Expected a ping on a closed client to fail")
+	}
+	if _, err := (&Client{}).Ping(); err == nil {
+		t.Error("Expected a ping without a connection to fail")
+	}
+}
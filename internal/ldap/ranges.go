@@ -0,0 +1,96 @@
+package ldap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Active Directory returns at most MaxValRange values of an attribute per
+// search (1500 by default). The returned name carries a range option saying
+// which values came back, e.g. member;range=0-1499, and the rest are read by
+// asking for member;range=1500-*. The last chunk ends in "*".
+
+// ParseRangeOption splits an attribute description such as member;range=0-1499
+// into the attribute name and the range of values it holds. end is -1 for the
+// last chunk (range=1500-*). ok is false when there is no valid range option.
+func ParseRangeOption(description string) (name string, start, end int, ok bool) {
+	parts := strings.Split(description, ";")
+	for i, option := range parts[1:] {
+		if len(option) < len("range=") || !strings.EqualFold(option[:len("range=")], "range=") {
+			continue
+		}
+		first, last, found := strings.Cut(option[len("range="):], "-")
+		if !found {
+			return description, 0, 0, false
+		}
+		start, err := strconv.Atoi(first)
+		if err != nil || start < 0 {
+			return description, 0, 0, false
+		}
+		end := -1
+		if last != "*" {
+			if end, err = strconv.Atoi(last); err != nil || end < start {
+				return description, 0, 0, false
+			}
+		}
+		rest := append(append([]string{}, parts[:i+1]...), parts[i+2:]...)
+		return strings.Join(rest, ";"), start, end, true
+	}
+	return description, 0, 0, false
+}
+
+// HasMoreValues reports whether the server returned only part of the values
+// of the named attribute, matched as AttributeKey does
+func (e *Entry) HasMoreValues(name string) bool {
+	_, ok := foldKey(e.MoreValues, name)
+	return ok
+}
+
+// LoadMoreValues reads the next chunk of values of a partially returned
+// attribute and returns a copy of entry with them appended. The entry itself
+// is left untouched.
+func (c *Client) LoadMoreValues(entry *Entry, attribute string) (*Entry, error) {
+	attribute, ok := foldKey(entry.MoreValues, attribute)
+	if !ok {
+		return entry, nil
+	}
+	start := entry.MoreValues[attribute]
+
+	entries, err := c.Search(entry.DN, "(objectClass=*)", ldap.ScopeBaseObject, []string{fmt.Sprintf("%s;range=%d-*", attribute, start)})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, entry.DN)
+	}
+
+	// The chunk comes back under the plain name once convertEntry strips the range
+	var values []string
+	next, more := 0, false
+	for name, chunk := range entries[0].Attributes {
+		if strings.EqualFold(name, attribute) {
+			values = chunk
+			next, more = entries[0].MoreValues[name]
+		}
+	}
+
+	updated := *entry
+	updated.Attributes = make(map[string][]string, len(entry.Attributes))
+	for name, existing := range entry.Attributes {
+		updated.Attributes[name] = existing
+	}
+	updated.Attributes[attribute] = append(append([]string{}, entry.Attributes[attribute]...), values...)
+
+	updated.MoreValues = make(map[string]int, len(entry.MoreValues))
+	for name, offset := range entry.MoreValues {
+		updated.MoreValues[name] = offset
+	}
+	delete(updated.MoreValues, attribute)
+	if more && len(values) > 0 {
+		updated.MoreValues[attribute] = next
+	}
+	return &updated, nil
+}
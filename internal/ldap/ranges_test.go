@@ -0,0 +1,84 @@
+package ldap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+func TestParseRangeOption(t *testing.T) {
+	tests := []struct {
+		description string
+		name        string
+		start, end  int
+		ok          bool
+	}{
+		{"member;range=0-1499", "member", 0, 1499, true},
+		{"member;range=1500-*", "member", 1500, -1, true},
+		{"member;Range=3000-4499", "member", 3000, 4499, true},
+		{"userCertificate;binary;range=0-9", "userCertificate;binary", 0, 9, true},
+		{"member", "member", 0, 0, false},
+		{"member;binary", "member;binary", 0, 0, false},
+		{"member;range=", "member;range=", 0, 0, false},
+		{"member;range=a-b", "member;range=a-b", 0, 0, false},
+		{"member;range=10-5", "member;range=10-5", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			name, start, end, ok := ParseRangeOption(tt.description)
+			if name != tt.name || start != tt.start || end != tt.end || ok != tt.ok {
+				t.Errorf("ParseRangeOption(%q) = %q, %d, %d, %v; want %q, %d, %d, %v",
+					tt.description, name, start, end, ok, tt.name, tt.start, tt.end, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLoadMoreValues(t *testing.T) {
+	members := make([]string, 25)
+	for i := range members {
+		members[i] = fmt.Sprintf("uid=user%02d,ou=people,dc=example,dc=com", i)
+	}
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "cn=staff,dc=example,dc=com", Attributes: map[string][]string{
+			"cn":     {"staff"},
+			"member": members,
+		}},
+	)
+	server.MaxValueRange = 10
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	entry, err := client.GetEntry("cn=staff,dc=example,dc=com")
+	if err != nil {
+		t.Fatalf("GetEntry failed: %v", err)
+	}
+	if len(entry.Attributes["member"]) != 10 || !entry.HasMoreValues("member") || entry.HasMoreValues("cn") {
+		t.Fatalf("Expected the first 10 members with more to load, got %d (more %v)", len(entry.Attributes["member"]), entry.MoreValues)
+	}
+
+	first := entry
+	for i := 0; entry.HasMoreValues("member"); i++ {
+		if i > 3 {
+			t.Fatal("Expected the values to be complete after three chunks")
+		}
+		if entry, err = client.LoadMoreValues(entry, "member"); err != nil {
+			t.Fatalf("LoadMoreValues failed: %v", err)
+		}
+	}
+	if got := entry.Attributes["member"]; len(got) != 25 || got[0] != members[0] || got[24] != members[24] {
+		t.Errorf("Expected all 25 members in order, got %d", len(got))
+	}
+	if len(first.Attributes["member"]) != 10 || !first.HasMoreValues("member") {
+		t.Error("Expected the original entry to be left untouched")
+	}
+	if entry.Attributes["cn"][0] != "staff" {
+		t.Error("Expected the other attributes to be kept")
+	}
+}
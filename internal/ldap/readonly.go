@@ -0,0 +1,12 @@
+package ldap
+
+import "errors"
+
+// ErrReadOnly is returned by the write operations of a client in read-only
+// mode, see Config.ReadOnly
+var ErrReadOnly = errors.New("read-only mode is on; changes to the directory are turned off")
+
+// ReadOnly reports whether the client refuses to add, modify or delete entries
+func (c *Client) ReadOnly() bool {
+	return c != nil && c.config.ReadOnly
+}
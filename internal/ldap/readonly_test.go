@@ -0,0 +1,32 @@
+package ldap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReadOnlyRefusesWrites(t *testing.T) {
+	// Refused before anything is sent, so no connection is needed
+	client := &Client{config: Config{ReadOnly: true}}
+	if !client.ReadOnly() {
+		t.Fatal("Expected the client to be read-only")
+	}
+
+	dn := "uid=jdoe,dc=example,dc=com"
+	writes := map[string]func() error{
+		"add":            func() error { return client.Add(dn, map[string][]string{"cn": {"jdoe"}}) },
+		"modify":         func() error { return client.ReplaceAttribute(dn, "cn", []string{"John"}) },
+		"delete":         func() error { return client.Delete(dn) },
+		"subtree delete": func() error { return client.DeleteSubtree(dn) },
+	}
+	for name, write := range writes {
+		if err := write(); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("Expected %s to be refused with ErrReadOnly, got %v", name, err)
+		}
+	}
+
+	var none *Client
+	if none.ReadOnly() || (&Client{}).ReadOnly() {
+		t.Error("Expected clients to be writable unless configured read-only")
+	}
+}
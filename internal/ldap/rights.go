@@ -0,0 +1,269 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ErrEffectiveRightsUnsupported is returned by GetEffectiveRights when the
+// server doesn't advertise the get effective rights control in its root DSE
+var ErrEffectiveRightsUnsupported = errors.New("server does not support the get effective rights control")
+
+// Attributes a server answering the get effective rights control adds to the entry
+const (
+	EntryLevelRightsAttribute     = "entryLevelRights"
+	AttributeLevelRightsAttribute = "attributeLevelRights"
+)
+
+// rightName spells out one letter of a rights value
+type rightName struct {
+	Letter byte
+	Name   string
+}
+
+// attributeRightNames and entryRightNames spell out the letters of the rights
+// values, in the order servers list them
+var (
+	attributeRightNames = []rightName{
+		{'r', "read"},
+		{'s', "search"},
+		{'c', "compare"},
+		{'w', "write"},
+		{'o', "delete"},
+		{'W', "self-add"},
+		{'O', "self-delete"},
+	}
+	entryRightNames = []rightName{
+		{'v', "view"},
+		{'a', "add"},
+		{'d', "delete"},
+		{'n', "rename"},
+	}
+)
+
+// EffectiveRights is what an identity may do with an entry, as reported by
+// the get effective rights control
+type EffectiveRights struct {
+	DN         string
+	AuthzID    string            // Identity the rights were evaluated for
+	Entry      string            // Entry-level rights letters, such as "vadn"
+	Attributes map[string]string // Rights letters by attribute name, such as "rscwo"
+}
+
+// Rights returns the rights letters for attribute, ignoring case. An empty
+// result means no rights were reported for it.
+func (r *EffectiveRights) Rights(attribute string) string {
+	if r == nil {
+		return ""
+	}
+	if rights, ok := r.Attributes[attribute]; ok {
+		return rights
+	}
+	for name, rights := range r.Attributes {
+		if strings.EqualFold(name, attribute) {
+			return rights
+		}
+	}
+	return ""
+}
+
+// Names returns the attributes rights were reported for, sorted
+func (r *EffectiveRights) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.Attributes))
+	for name := range r.Attributes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// MayWrite reports whether the rights allow writing attribute. Rights that
+// weren't read, or that say nothing about attribute, don't forbid it.
+func (r *EffectiveRights) MayWrite(attribute string) bool {
+	rights := r.Rights(attribute)
+	return rights == "" || strings.ContainsRune(rights, 'w')
+}
+
+// MayDelete reports whether the rights allow deleting the entry. Rights that
+// weren't read, or without entry-level rights, don't forbid it.
+func (r *EffectiveRights) MayDelete() bool {
+	return r == nil || r.Entry == "" || strings.ContainsRune(r.Entry, 'd')
+}
+
+// DescribeAttributeRights spells out attribute rights letters, such as
+// "read, search, compare" for "rsc". No rights are described as "none".
+func DescribeAttributeRights(rights string) string {
+	return describeRights(rights, attributeRightNames)
+}
+
+// DescribeEntryRights spells out entry rights letters, such as "view, add"
+// for "va"
+func DescribeEntryRights(rights string) string {
+	return describeRights(rights, entryRightNames)
+}
+
+// describeRights spells out the letters of rights with names. Letters
+// without a name are kept as they are so nothing the server said is lost.
+func describeRights(rights string, names []rightName) string {
+	var parts []string
+	for i := 0; i < len(rights); i++ {
+		letter := rights[i]
+		if letter == ' ' || letter == '-' {
+			continue
+		}
+		name := string(letter)
+		for _, known := range names {
+			if known.Letter == letter {
+				name = known.Name
+				break
+			}
+		}
+		parts = append(parts, name)
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseEffectiveRights reads the rights a server added to entry in answer to
+// the get effective rights control. attributeLevelRights is a list of
+// "name:rights" pairs separated by commas, sent as one value or several.
+func ParseEffectiveRights(entry *Entry) *EffectiveRights {
+	rights := &EffectiveRights{Attributes: make(map[string]string)}
+	if entry == nil {
+		return rights
+	}
+	rights.DN = entry.DN
+
+	for name, values := range entry.Attributes {
+		switch {
+		case strings.EqualFold(name, EntryLevelRightsAttribute):
+			if len(values) > 0 {
+				rights.Entry = strings.TrimSpace(values[0])
+			}
+		case strings.EqualFold(name, AttributeLevelRightsAttribute):
+			for _, value := range values {
+				for _, pair := range strings.Split(value, ",") {
+					attribute, letters, ok := strings.Cut(strings.TrimSpace(pair), ":")
+					if !ok || strings.TrimSpace(attribute) == "" {
+						continue
+					}
+					rights.Attributes[strings.TrimSpace(attribute)] = strings.TrimSpace(letters)
+				}
+			}
+		}
+	}
+	return rights
+}
+
+// effectiveRightsAuthzID returns the authorization identity rights are
+// evaluated for: id when given, with "dn:" added to a DN and "u:" to any
+// other name, otherwise the bound user. Anonymous binds are "dn:".
+func effectiveRightsAuthzID(id, bindUser string) string {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		id = strings.TrimSpace(bindUser)
+		if id == "" {
+			return "dn:"
+		}
+	}
+	lower := strings.ToLower(id)
+	switch {
+	case strings.HasPrefix(lower, "dn:"), strings.HasPrefix(lower, "u:"):
+		return id
+	case strings.Contains(id, "="):
+		return "dn:" + id
+	default:
+		return "u:" + id
+	}
+}
+
+// newEffectiveRightsControl builds the get effective rights request control
+// for authzID. Its value is the identity as a BER octet string.
+func newEffectiveRightsControl(authzID string) ldap.Control {
+	value := ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, authzID, "authzId")
+	return ldap.NewControlString(OIDGetEffectiveRights, true, string(value.Bytes()))
+}
+
+// SupportsEffectiveRights reports whether the server is known to support the
+// get effective rights control. Unknown capabilities count as unsupported,
+// since the control is critical and most servers would reject it.
+func (c *Client) SupportsEffectiveRights() bool {
+	return c.Capabilities().Supports(OIDGetEffectiveRights)
+}
+
+// GetEffectiveRights reads the entry at dn with the get effective rights
+// control and returns what authzID may do with it and with each attribute.
+// An empty authzID checks the bound user. The attributes named are asked for
+// alongside the entry's own, so rights are reported for them too.
+func (c *Client) GetEffectiveRights(dn, authzID string, attributes []string) (*EffectiveRights, error) {
+	if !c.SupportsEffectiveRights() {
+		return nil, ErrEffectiveRightsUnsupported
+	}
+
+	authzID = effectiveRightsAuthzID(authzID, c.config.BindUser)
+	requested := append([]string{"*", EntryLevelRightsAttribute, AttributeLevelRightsAttribute}, attributes...)
+
+	var result *ldap.SearchResult
+	err := c.withRetry(func() error {
+		searchRequest := ldap.NewSearchRequest(
+			dn,
+			ldap.ScopeBaseObject,
+			ldap.NeverDerefAliases,
+			0, // No size limit
+			0, // No time limit
+			false,
+			"(objectClass=*)",
+			requested,
+			[]ldap.Control{newEffectiveRightsControl(authzID)},
+		)
+
+		var err error
+		result, err = c.connection().Search(searchRequest)
+		if err != nil {
+			return fmt.Errorf("effective rights search failed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Entries) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEntryNotFound, dn)
+	}
+
+	rights := ParseEffectiveRights(convertEntry(result.Entries[0]))
+	rights.DN = dn
+	rights.AuthzID = authzID
+	if authzID == effectiveRightsAuthzID("", c.config.BindUser) {
+		c.cacheMu.Lock()
+		if c.rights == nil {
+			c.rights = make(map[string]*EffectiveRights)
+		}
+		c.rights[strings.ToLower(dn)] = rights
+		c.cacheMu.Unlock()
+	}
+	return rights, nil
+}
+
+// CachedRights returns the bound user's effective rights on dn from the last
+// time GetEffectiveRights read them, or nil if they haven't been read
+func (c *Client) CachedRights(dn string) *EffectiveRights {
+	if c == nil {
+		return nil
+	}
+	c.cacheMu.RLock()
+	defer c.cacheMu.RUnlock()
+	return c.rights[strings.ToLower(dn)]
+}
@@ -0,0 +1,165 @@
+package ldap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	ber "github.com/go-asn1-ber/asn1-ber"
+)
+
+func TestParseEffectiveRights(t *testing.T) {
+	rights := ParseEffectiveRights(&Entry{
+		DN: "uid=alice,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"cn":                   {"Alice"},
+			"entryLevelRights":     {"vadn"},
+			"attributeLevelRights": {"objectClass:rsc, cn:rscwo, userPassword:wo", "mail:rscwoW"},
+		},
+	})
+
+	if rights.Entry != "vadn" {
+		t.Errorf("Expected entry rights vadn, got %q", rights.Entry)
+	}
+	expected := map[string]string{
+		"objectClass":  "rsc",
+		"cn":           "rscwo",
+		"userPassword": "wo",
+		"mail":         "rscwoW",
+	}
+	if len(rights.Attributes) != len(expected) {
+		t.Fatalf("Expected %d attributes, got %v", len(expected), rights.Attributes)
+	}
+	for name, want := range expected {
+		if got := rights.Rights(name); got != want {
+			t.Errorf("Expected %s rights %q, got %q", name, want, got)
+		}
+	}
+	if got := rights.Rights("USERPASSWORD"); got != "wo" {
+		t.Errorf("Expected rights to be looked up ignoring case, got %q", got)
+	}
+	if got := rights.Names(); len(got) != 4 || got[0] != "cn" || got[3] != "userPassword" {
+		t.Errorf("Expected sorted attribute names, got %v", got)
+	}
+}
+
+func TestParseEffectiveRights_Empty(t *testing.T) {
+	rights := ParseEffectiveRights(&Entry{Attributes: map[string][]string{
+		"attributeLevelRights": {"", " , :r"},
+	}})
+	if rights.Entry != "" || len(rights.Attributes) != 0 {
+		t.Errorf("Expected malformed pairs to be skipped, got %+v", rights)
+	}
+}
+
+func TestDescribeRights(t *testing.T) {
+	tests := []struct {
+		rights string
+		entry  bool
+		want   string
+	}{
+		{"rsc", false, "read, search, compare"},
+		{"rscwo", false, "read, search, compare, write, delete"},
+		{"WO", false, "self-add, self-delete"},
+		{"", false, "none"},
+		{"r-x", false, "read, x"},
+		{"vadn", true, "view, add, delete, rename"},
+	}
+	for _, tt := range tests {
+		describe := DescribeAttributeRights
+		if tt.entry {
+			describe = DescribeEntryRights
+		}
+		if got := describe(tt.rights); got != tt.want {
+			t.Errorf("describe(%q) = %q, want %q", tt.rights, got, tt.want)
+		}
+	}
+}
+
+func TestEffectiveRightsAuthzID(t *testing.T) {
+	tests := []struct {
+		id, bindUser, want string
+	}{
+		{"", "cn=admin,dc=example,dc=com", "dn:cn=admin,dc=example,dc=com"},
+		{"", "", "dn:"},
+		{"uid=bob,dc=example,dc=com", "cn=admin", "dn:uid=bob,dc=example,dc=com"},
+		{"bob", "", "u:bob"},
+		{"DN:uid=bob", "", "DN:uid=bob"},
+		{"u:bob", "", "u:bob"},
+	}
+	for _, tt := range tests {
+		if got := effectiveRightsAuthzID(tt.id, tt.bindUser); got != tt.want {
+			t.Errorf("effectiveRightsAuthzID(%q, %q) = %q, want %q", tt.id, tt.bindUser, got, tt.want)
+		}
+	}
+}
+
+func TestNewEffectiveRightsControl(t *testing.T) {
+	control := newEffectiveRightsControl("dn:cn=admin")
+	if control.GetControlType() != OIDGetEffectiveRights {
+		t.Errorf("Unexpected control type %s", control.GetControlType())
+	}
+	encoded := control.Encode()
+	if len(encoded.Children) != 3 || encoded.Children[1].Value != true {
+		t.Fatalf("Expected a critical control with a value, got %d children", len(encoded.Children))
+	}
+	value := ber.DecodePacket(encoded.Children[2].Data.Bytes())
+	if value.Tag != ber.TagOctetString || value.Value != "dn:cn=admin" {
+		t.Errorf("Expected the authzId as an octet string, got %v", value.Value)
+	}
+}
+
+func TestGetEffectiveRights(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "", Attributes: map[string][]string{
+			"supportedControl": {OIDGetEffectiveRights},
+		}},
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "uid=alice,dc=example,dc=com", Attributes: map[string][]string{
+			"uid":                  {"alice"},
+			"entryLevelRights":     {"v"},
+			"attributeLevelRights": {"uid:rsc"},
+		}},
+	)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetEffectiveRights("uid=alice,dc=example,dc=com", "", nil); !errors.Is(err, ErrEffectiveRightsUnsupported) {
+		t.Fatalf("Expected the control to be refused before the root DSE is read, got %v", err)
+	}
+	if _, err := client.LoadCapabilities(); err != nil {
+		t.Fatal(err)
+	}
+
+	rights, err := client.GetEffectiveRights("uid=alice,dc=example,dc=com", "uid=bob,dc=example,dc=com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rights.AuthzID != "dn:uid=bob,dc=example,dc=com" {
+		t.Errorf("Unexpected authzId %q", rights.AuthzID)
+	}
+	if rights.Entry != "v" || rights.Rights("uid") != "rsc" {
+		t.Errorf("Unexpected rights %+v", rights)
+	}
+}
+
+func TestEffectiveRightsMayWriteAndDelete(t *testing.T) {
+	rights := &EffectiveRights{Entry: "van", Attributes: map[string]string{"cn": "rscwo", "uid": "rsc"}}
+	if !rights.MayWrite("CN") || rights.MayWrite("uid") {
+		t.Error("Expected write rights to follow the w letter")
+	}
+	if !rights.MayWrite("mail") {
+		t.Error("Expected attributes without reported rights not to be forbidden")
+	}
+	if rights.MayDelete() {
+		t.Error("Expected entry rights without d to forbid deleting")
+	}
+
+	var unknown *EffectiveRights
+	if !unknown.MayWrite("cn") || !unknown.MayDelete() || !(&EffectiveRights{}).MayDelete() {
+		t.Error("Expected rights that weren't read not to forbid anything")
+	}
+}
@@ -0,0 +1,296 @@
+package ldap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// Common attribute syntax OIDs (RFC 4517)
+const (
+	SyntaxBoolean         = "1.3.6.1.4.1.1466.115.121.1.7"
+	SyntaxDN              = "1.3.6.1.4.1.1466.115.121.1.12"
+	SyntaxDirectoryString = "1.3.6.1.4.1.1466.115.121.1.15"
+	SyntaxGeneralizedTime = "1.3.6.1.4.1.1466.115.121.1.24"
+	SyntaxIA5String       = "1.3.6.1.4.1.1466.115.121.1.26"
+	SyntaxInteger         = "1.3.6.1.4.1.1466.115.121.1.27"
+	SyntaxOctetString     = "1.3.6.1.4.1.1466.115.121.1.40"
+)
+
+// syntaxNames are the short names of the RFC 4517 syntaxes, and the UUID
+// syntax of RFC 4530, keyed by OID
+var syntaxNames = map[string]string{
+	"1.3.6.1.4.1.1466.115.121.1.3":  "AttributeTypeDescription",
+	"1.3.6.1.4.1.1466.115.121.1.5":  "Binary",
+	"1.3.6.1.4.1.1466.115.121.1.6":  "BitString",
+	SyntaxBoolean:                   "Boolean",
+	"1.3.6.1.4.1.1466.115.121.1.8":  "Certificate",
+	"1.3.6.1.4.1.1466.115.121.1.9":  "CertificateList",
+	"1.3.6.1.4.1.1466.115.121.1.10": "CertificatePair",
+	"1.3.6.1.4.1.1466.115.121.1.11": "CountryString",
+	SyntaxDN:                        "DN",
+	"1.3.6.1.4.1.1466.115.121.1.14": "DeliveryMethod",
+	SyntaxDirectoryString:           "DirectoryString",
+	"1.3.6.1.4.1.1466.115.121.1.21": "EnhancedGuide",
+	"1.3.6.1.4.1.1466.115.121.1.22": "FacsimileTelephoneNumber",
+	"1.3.6.1.4.1.1466.115.121.1.23": "Fax",
+	SyntaxGeneralizedTime:           "GeneralizedTime",
+	"1.3.6.1.4.1.1466.115.121.1.25": "Guide",
+	SyntaxIA5String:                 "IA5String",
+	SyntaxInteger:                   "INTEGER",
+	"1.3.6.1.4.1.1466.115.121.1.28": "JPEG",
+	"1.3.6.1.4.1.1466.115.121.1.34": "NameAndOptionalUID",
+	"1.3.6.1.4.1.1466.115.121.1.36": "NumericString",
+	"1.3.6.1.4.1.1466.115.121.1.37": "ObjectClassDescription",
+	"1.3.6.1.4.1.1466.115.121.1.38": "OID",
+	"1.3.6.1.4.1.1466.115.121.1.39": "OtherMailbox",
+	SyntaxOctetString:               "OctetString",
+	"1.3.6.1.4.1.1466.115.121.1.41": "PostalAddress",
+	"1.3.6.1.4.1.1466.115.121.1.44": "PrintableString",
+	"1.3.6.1.4.1.1466.115.121.1.50": "TelephoneNumber",
+	"1.3.6.1.4.1.1466.115.121.1.52": "TelexNumber",
+	"1.3.6.1.4.1.1466.115.121.1.53": "UTCTime",
+	"1.3.6.1.4.1.1466.115.121.1.54": "LDAPSyntaxDescription",
+	"1.3.6.1.4.1.1466.115.121.1.58": "SubstringAssertion",
+	"1.3.6.1.1.16.1":                "UUID",
+}
+
+// SyntaxName returns the short name of a syntax OID, such as IA5String, or
+// the OID itself for syntaxes without a well-known name
+func SyntaxName(oid string) string {
+	if name, ok := syntaxNames[oid]; ok {
+		return name
+	}
+	return oid
+}
+
+// AttributeType describes a single attributeTypes definition from the server schema
+type AttributeType struct {
+	OID         string
+	Names       []string
+	Description string
+	Syntax      string
+	Superior    string
+	SingleValue bool
+}
+
+// Name returns the primary name of the attribute type, or its OID if it has none
+func (at *AttributeType) Name() string {
+	if len(at.Names) > 0 {
+		return at.Names[0]
+	}
+	return at.OID
+}
+
+// Schema holds the parts of the server schema the UI makes use of
+type Schema struct {
+	AttributeTypes map[string]*AttributeType // Keyed by lowercased name and OID
+}
+
+// AttributeType looks up an attribute type by name or OID (case-insensitive).
+// Returns nil if the schema doesn't define the attribute.
+func (s *Schema) AttributeType(name string) *AttributeType {
+	if s == nil {
+		return nil
+	}
+	return s.AttributeTypes[strings.ToLower(name)]
+}
+
+// AttributeNames returns the names of every attribute type in the schema, sorted
+func (s *Schema) AttributeNames() []string {
+	if s == nil {
+		return nil
+	}
+	seen := make(map[*AttributeType]bool)
+	var names []string
+	for _, at := range s.AttributeTypes {
+		if seen[at] {
+			continue
+		}
+		seen[at] = true
+		names = append(names, at.Names...)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CanonicalName returns name spelled as the schema defines it, keeping the
+// alias that was used. Names the schema doesn't know are returned as-is.
+func (s *Schema) CanonicalName(name string) string {
+	at := s.AttributeType(name)
+	if at == nil {
+		return name
+	}
+	for _, n := range at.Names {
+		if strings.EqualFold(n, name) {
+			return n
+		}
+	}
+	return name
+}
+
+// GetSchema reads the attribute type definitions from the server's subschema
+// subentry. The result is cached on the client after the first successful load.
+func (c *Client) GetSchema() (*Schema, error) {
+	c.cacheMu.RLock()
+	cached := c.schema
+	c.cacheMu.RUnlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	// Locate the subschema subentry via the root DSE, falling back to the common default
+	subschemaDN := "cn=Subschema"
+	rootEntries, err := c.Search("", "(objectClass=*)", ldap.ScopeBaseObject, []string{"subschemaSubentry"})
+	if err == nil && len(rootEntries) > 0 {
+		if values := rootEntries[0].Attributes["subschemaSubentry"]; len(values) > 0 && values[0] != "" {
+			subschemaDN = values[0]
+		}
+	}
+
+	entries, err := c.Search(subschemaDN, "(objectClass=subschema)", ldap.ScopeBaseObject, []string{"attributeTypes"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("schema not found at %s", subschemaDN)
+	}
+
+	schema := parseSchema(entries[0].Attributes["attributeTypes"])
+	c.cacheMu.Lock()
+	c.schema = schema
+	c.cacheMu.Unlock()
+	return schema, nil
+}
+
+// parseSchema builds a Schema from raw attributeTypes values, skipping any
+// definitions that can't be parsed
+func parseSchema(definitions []string) *Schema {
+	schema := &Schema{AttributeTypes: make(map[string]*AttributeType)}
+
+	for _, def := range definitions {
+		at, err := parseAttributeType(def)
+		if err != nil {
+			continue
+		}
+		schema.AttributeTypes[strings.ToLower(at.OID)] = at
+		for _, name := range at.Names {
+			schema.AttributeTypes[strings.ToLower(name)] = at
+		}
+	}
+
+	// Inherit syntax from superior types where the definition omits it (e.g. cn SUP name)
+	for _, at := range schema.AttributeTypes {
+		seen := make(map[*AttributeType]bool)
+		current := at
+		for at.Syntax == "" && current.Superior != "" && !seen[current] {
+			seen[current] = true
+			sup := schema.AttributeTypes[strings.ToLower(current.Superior)]
+			if sup == nil {
+				break
+			}
+			at.Syntax = sup.Syntax
+			current = sup
+		}
+	}
+
+	return schema
+}
+
+// parseAttributeType parses an RFC 4512 AttributeTypeDescription
+func parseAttributeType(def string) (*AttributeType, error) {
+	tokens := tokenizeSchemaDefinition(def)
+	if len(tokens) < 3 || tokens[0] != "(" || tokens[len(tokens)-1] != ")" {
+		return nil, fmt.Errorf("malformed attribute type: %s", def)
+	}
+
+	at := &AttributeType{OID: tokens[1]}
+	tokens = tokens[2 : len(tokens)-1]
+
+	for i := 0; i < len(tokens); i++ {
+		keyword := strings.ToUpper(tokens[i])
+		switch keyword {
+		case "SINGLE-VALUE":
+			at.SingleValue = true
+		case "OBSOLETE", "COLLECTIVE", "NO-USER-MODIFICATION":
+			// Flags without values that the UI doesn't use
+		default:
+			values, next := readSchemaValues(tokens, i+1)
+			i = next - 1
+			if len(values) == 0 {
+				continue
+			}
+			switch keyword {
+			case "NAME":
+				at.Names = values
+			case "DESC":
+				at.Description = values[0]
+			case "SUP":
+				at.Superior = values[0]
+			case "SYNTAX":
+				// Strip the optional length bound, e.g. 1.3.6.1.4.1.1466.115.121.1.15{256}
+				syntax := values[0]
+				if idx := strings.Index(syntax, "{"); idx >= 0 {
+					syntax = syntax[:idx]
+				}
+				at.Syntax = syntax
+			}
+		}
+	}
+
+	return at, nil
+}
+
+// readSchemaValues reads a single value or a parenthesized list of values
+// starting at index start, returning the values and the index after them
+func readSchemaValues(tokens []string, start int) ([]string, int) {
+	if start >= len(tokens) {
+		return nil, start
+	}
+	if tokens[start] != "(" {
+		return []string{tokens[start]}, start + 1
+	}
+
+	var values []string
+	i := start + 1
+	for ; i < len(tokens) && tokens[i] != ")"; i++ {
+		if tokens[i] != "$" {
+			values = append(values, tokens[i])
+		}
+	}
+	return values, i + 1
+}
+
+// tokenizeSchemaDefinition splits a schema definition into parentheses,
+// quoted strings (without quotes), and bare words
+func tokenizeSchemaDefinition(def string) []string {
+	var tokens []string
+	runes := []rune(def)
+
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			continue
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+		case r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			tokens = append(tokens, string(runes[i+1:end]))
+			i = end
+		default:
+			end := i
+			for end < len(runes) && !strings.ContainsRune(" \t\n\r()'", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			i = end - 1
+		}
+	}
+
+	return tokens
+}
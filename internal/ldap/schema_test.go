@@ -0,0 +1,149 @@
+package ldap
+
+import "testing"
+
+func TestParseAttributeType(t *testing.T) {
+	tests := []struct {
+		name        string
+		def         string
+		oid         string
+		names       []string
+		syntax      string
+		singleValue bool
+	}{
+		{
+			name:   "multiple names with length bound",
+			def:    "( 0.9.2342.19200300.100.1.3 NAME ( 'mail' 'rfc822Mailbox' ) DESC 'RFC1274: RFC822 Mailbox' EQUALITY caseIgnoreIA5Match SYNTAX 1.3.6.1.4.1.1466.115.121.1.26{256} )",
+			oid:    "0.9.2342.19200300.100.1.3",
+			names:  []string{"mail", "rfc822Mailbox"},
+			syntax: SyntaxIA5String,
+		},
+		{
+			name:        "single value boolean",
+			def:         "( 1.3.6.1.4.1.9999.1 NAME 'accountLocked' SYNTAX 1.3.6.1.4.1.1466.115.121.1.7 SINGLE-VALUE )",
+			oid:         "1.3.6.1.4.1.9999.1",
+			names:       []string{"accountLocked"},
+			syntax:      SyntaxBoolean,
+			singleValue: true,
+		},
+		{
+			name:   "no names",
+			def:    "( 2.5.4.41 SYNTAX 1.3.6.1.4.1.1466.115.121.1.15 )",
+			oid:    "2.5.4.41",
+			syntax: SyntaxDirectoryString,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at, err := parseAttributeType(tt.def)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if at.OID != tt.oid {
+				t.Errorf("Expected OID %s, got %s", tt.oid, at.OID)
+			}
+			if len(at.Names) != len(tt.names) {
+				t.Fatalf("Expected names %v, got %v", tt.names, at.Names)
+			}
+			for i, name := range tt.names {
+				if at.Names[i] != name {
+					t.Errorf("Expected name %s at %d, got %s", name, i, at.Names[i])
+				}
+			}
+			if at.Syntax != tt.syntax {
+				t.Errorf("Expected syntax %s, got %s", tt.syntax, at.Syntax)
+			}
+			if at.SingleValue != tt.singleValue {
+				t.Errorf("Expected SingleValue %t, got %t", tt.singleValue, at.SingleValue)
+			}
+		})
+	}
+}
+
+func TestParseAttributeType_Malformed(t *testing.T) {
+	if _, err := parseAttributeType("NAME 'broken'"); err == nil {
+		t.Error("Expected error for definition without parentheses")
+	}
+}
+
+func TestParseSchema_InheritsSyntaxFromSuperior(t *testing.T) {
+	schema := parseSchema([]string{
+		"( 2.5.4.41 NAME 'name' SYNTAX 1.3.6.1.4.1.1466.115.121.1.15{32768} )",
+		"( 2.5.4.3 NAME ( 'cn' 'commonName' ) SUP name )",
+		"not a definition",
+	})
+
+	cn := schema.AttributeType("CN")
+	if cn == nil {
+		t.Fatal("Expected cn to be found case-insensitively")
+	}
+	if cn.Syntax != SyntaxDirectoryString {
+		t.Errorf("Expected cn to inherit syntax from name, got %q", cn.Syntax)
+	}
+	if schema.AttributeType("commonName") != cn {
+		t.Error("Expected alias commonName to resolve to the same attribute type")
+	}
+	if schema.AttributeType("2.5.4.3") != cn {
+		t.Error("Expected OID lookup to resolve to the same attribute type")
+	}
+}
+
+func TestSchema_NilLookup(t *testing.T) {
+	var schema *Schema
+	if schema.AttributeType("cn") != nil {
+		t.Error("Expected nil schema lookup to return nil")
+	}
+}
+
+func TestSchemaAttributeNames(t *testing.T) {
+	schema := parseSchema([]string{
+		"( 2.5.4.3 NAME ( 'cn' 'commonName' ) SUP name )",
+		"( 0.9.2342.19200300.100.1.3 NAME 'mail' SYNTAX 1.3.6.1.4.1.1466.115.121.1.26 )",
+		"( 2.5.4.41 SYNTAX 1.3.6.1.4.1.1466.115.121.1.15 )",
+	})
+
+	got := schema.AttributeNames()
+	if len(got) != 3 || got[0] != "cn" || got[1] != "commonName" || got[2] != "mail" {
+		t.Errorf("Unexpected attribute names: %v", got)
+	}
+
+	var nilSchema *Schema
+	if nilSchema.AttributeNames() != nil {
+		t.Error("Expected no names without a schema")
+	}
+}
+
+func TestSchemaCanonicalName(t *testing.T) {
+	schema := parseSchema([]string{
+		"( 2.5.4.3 NAME ( 'cn' 'commonName' ) SUP name )",
+		"( 2.5.4.20 NAME 'telephoneNumber' SYNTAX 1.3.6.1.4.1.1466.115.121.1.50 )",
+	})
+
+	tests := map[string]string{
+		"telephonenumber": "telephoneNumber",
+		"TELEPHONENUMBER": "telephoneNumber",
+		"CN":              "cn",
+		"COMMONNAME":      "commonName",
+		"customAttr":      "customAttr",
+	}
+	for name, want := range tests {
+		if got := schema.CanonicalName(name); got != want {
+			t.Errorf("CanonicalName(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	var nilSchema *Schema
+	if got := nilSchema.CanonicalName("MAIL"); got != "MAIL" {
+		t.Errorf("Expected the name unchanged without a schema, got %q", got)
+	}
+}
+
+func TestSyntaxName(t *testing.T) {
+	if got := SyntaxName(SyntaxIA5String); got != "IA5String" {
+		t.Errorf("Expected IA5String, got %q", got)
+	}
+	if got := SyntaxName("1.2.840.113556.1.4.906"); got != "1.2.840.113556.1.4.906" {
+		t.Errorf("Expected an unknown syntax to keep its OID, got %q", got)
+	}
+}
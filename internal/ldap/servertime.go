@@ -0,0 +1,91 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ErrServerTimeUnavailable is returned by GetServerTime when none of the
+// places servers publish their clock could be read
+var ErrServerTimeUnavailable = errors.New("server does not publish its current time")
+
+// MaxClockSkew is how far the server and local clocks may drift apart before
+// the difference is flagged. It is Kerberos' default tolerance; replication
+// and time-based access rules go wrong well before larger skews.
+const MaxClockSkew = 5 * time.Minute
+
+// serverTimeSources lists where servers publish their current time as a
+// GeneralizedTime, tried in order
+var serverTimeSources = []struct {
+	DN        string
+	Attribute string
+}{
+	{"", "currentTime"},                                   // Active Directory root DSE
+	{"cn=monitor", "currentTime"},                         // 389 Directory Server monitor
+	{"cn=Current,cn=Time,cn=Monitor", "monitorTimestamp"}, // OpenLDAP back-monitor
+}
+
+// parseServerTime reads the GeneralizedTime in attribute, matched
+// case-insensitively. It reports false when the attribute is missing.
+func parseServerTime(attributes map[string][]string, attribute string) (time.Time, bool, error) {
+	for name, values := range attributes {
+		if !strings.EqualFold(name, attribute) || len(values) == 0 {
+			continue
+		}
+		t, err := ParseGeneralizedTime(values[0])
+		if err != nil {
+			return time.Time{}, true, fmt.Errorf("failed to read server time from %s: %w", attribute, err)
+		}
+		return t, true, nil
+	}
+	return time.Time{}, false, nil
+}
+
+// GetServerTime returns the server's current time, from the root DSE's
+// currentTime on Active Directory or the monitor backend elsewhere. Sources
+// that are missing or unreadable are skipped.
+func (c *Client) GetServerTime() (time.Time, error) {
+	for _, source := range serverTimeSources {
+		entries, err := c.Search(source.DN, "(objectClass=*)", ldap.ScopeBaseObject, []string{source.Attribute})
+		if err != nil || len(entries) == 0 {
+			continue
+		}
+		t, found, err := parseServerTime(entries[0].Attributes, source.Attribute)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if found {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrServerTimeUnavailable
+}
+
+// ClockSkew returns how far the server clock is ahead of the local clock,
+// negative when it is behind. Server times are read to the second, so the
+// skew is rounded to whole seconds.
+func ClockSkew(server, local time.Time) time.Duration {
+	return server.Sub(local).Round(time.Second)
+}
+
+// ExcessiveClockSkew reports whether skew, in either direction, exceeds MaxClockSkew
+func ExcessiveClockSkew(skew time.Duration) bool {
+	return skew > MaxClockSkew || skew < -MaxClockSkew
+}
+
+// FormatClockSkew describes a skew for display, such as "3m12s ahead",
+// "45s behind" or "in sync"
+func FormatClockSkew(skew time.Duration) string {
+	switch {
+	case skew == 0:
+		return "in sync"
+	case skew > 0:
+		return skew.String() + " ahead"
+	default:
+		return (-skew).String() + " behind"
+	}
+}
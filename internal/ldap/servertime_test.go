@@ -0,0 +1,99 @@
+package ldap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+func TestParseServerTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"20240131235959.0Z", time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)}, // Active Directory
+		{"20240131235959Z", time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)},   // 389 Directory Server, OpenLDAP
+		{"20240201005959+0100", time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, found, err := parseServerTime(map[string][]string{"CurrentTime": {tt.value}}, "currentTime")
+		if err != nil || !found {
+			t.Errorf("parseServerTime(%q) = %v, %v", tt.value, found, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseServerTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	if _, found, err := parseServerTime(map[string][]string{"vendorName": {"x"}}, "currentTime"); found || err != nil {
+		t.Errorf("Expected a missing attribute to be reported as not found, got %v, %v", found, err)
+	}
+	if _, _, err := parseServerTime(map[string][]string{"currentTime": {"yesterday"}}, "currentTime"); err == nil {
+		t.Error("Expected an invalid time to fail")
+	}
+}
+
+func TestClockSkew(t *testing.T) {
+	local := time.Date(2024, 1, 31, 12, 0, 0, 300*int(time.Millisecond), time.UTC)
+	tests := []struct {
+		server    time.Time
+		want      time.Duration
+		excessive bool
+		text      string
+	}{
+		{time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), 0, false, "in sync"},
+		{time.Date(2024, 1, 31, 12, 3, 12, 0, time.UTC), 3*time.Minute + 12*time.Second, false, "3m12s ahead"},
+		{time.Date(2024, 1, 31, 11, 59, 15, 0, time.UTC), -45 * time.Second, false, "45s behind"},
+		{time.Date(2024, 1, 31, 12, 5, 1, 0, time.UTC), 5*time.Minute + time.Second, true, "5m1s ahead"},
+		{time.Date(2024, 1, 31, 11, 50, 0, 0, time.UTC), -10 * time.Minute, true, "10m0s behind"},
+	}
+	for _, tt := range tests {
+		skew := ClockSkew(tt.server, local)
+		if skew != tt.want {
+			t.Errorf("ClockSkew(%v) = %v, want %v", tt.server, skew, tt.want)
+		}
+		if got := ExcessiveClockSkew(skew); got != tt.excessive {
+			t.Errorf("ExcessiveClockSkew(%v) = %v, want %v", skew, got, tt.excessive)
+		}
+		if got := FormatClockSkew(skew); got != tt.text {
+			t.Errorf("FormatClockSkew(%v) = %q, want %q", skew, got, tt.text)
+		}
+	}
+}
+
+func TestGetServerTime(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "cn=monitor", Attributes: map[string][]string{"currentTime": {"20240131235959Z"}}},
+	)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// The root DSE has no currentTime, so the monitor is read instead
+	got, err := client.GetServerTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestGetServerTimeUnavailable(t *testing.T) {
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetServerTime(); !errors.Is(err, ErrServerTimeUnavailable) {
+		t.Errorf("Expected ErrServerTimeUnavailable, got %v", err)
+	}
+}
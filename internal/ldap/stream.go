@@ -0,0 +1,28 @@
+package ldap
+
+// SearchStream performs a paged search and calls fn with each entry as its
+// page arrives, so results of any size can be processed without holding them
+// all. With allPages false only the first page is read. It returns the number
+// of entries passed to fn; an error from fn stops the search and is returned.
+// Pages after the first are throttled to the configured operation rate.
+func (c *Client) SearchStream(baseDN, filter string, scope int, attributes []string, pageSize uint32, allPages bool, fn func(*Entry) error) (int, error) {
+	count := 0
+	var cookie []byte
+	for {
+		page, err := c.SearchPaged(baseDN, filter, scope, attributes, pageSize, cookie)
+		if err != nil {
+			return count, err
+		}
+		for _, entry := range page.Entries {
+			if err := fn(entry); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if !allPages || !page.HasMore {
+			return count, nil
+		}
+		cookie = page.Cookie
+		c.Throttle()
+	}
+}
@@ -0,0 +1,100 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// newStreamServer serves a base entry with count users below it
+func newStreamServer(t *testing.T, count int) *ldaptest.Server {
+	t.Helper()
+	entries := []ldaptest.Entry{{DN: "dc=example,dc=com", Attributes: map[string][]string{"dc": {"example"}}}}
+	for i := 0; i < count; i++ {
+		entries = append(entries, ldaptest.Entry{
+			DN:         fmt.Sprintf("uid=user%d,dc=example,dc=com", i),
+			Attributes: map[string][]string{"uid": {fmt.Sprintf("user%d", i)}, "mail": {fmt.Sprintf("user%d@example.com", i)}},
+		})
+	}
+	return ldaptest.NewServer(t, entries...)
+}
+
+func TestSearchStream_ReadsAllPages(t *testing.T) {
+	server := newStreamServer(t, 25)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	var dns []string
+	count, err := client.SearchStream("dc=example,dc=com", "(uid=*)", ldap.ScopeSingleLevel, []string{"uid"}, 10, true, func(entry *Entry) error {
+		dns = append(dns, entry.DN)
+		if _, ok := entry.Attributes["mail"]; ok {
+			return errors.New("expected only the requested attributes")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SearchStream failed: %v", err)
+	}
+	if count != 25 || len(dns) != 25 {
+		t.Errorf("Expected 25 entries, got %d (%d streamed)", count, len(dns))
+	}
+	if server.Searches() != 3 {
+		t.Errorf("Expected 3 pages to be requested, got %d", server.Searches())
+	}
+}
+
+func TestSearchStream_FirstPageOnly(t *testing.T) {
+	server := newStreamServer(t, 25)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port()})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	count, err := client.SearchStream("dc=example,dc=com", "(uid=*)", ldap.ScopeSingleLevel, nil, 10, false, func(*Entry) error { return nil })
+	if err != nil || count != 10 {
+		t.Errorf("Expected the first page of 10 entries, got %d, %v", count, err)
+	}
+}
+
+func TestSearchStream_StopsOnCallbackError(t *testing.T) {
+	server := newStreamServer(t, 25)
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port()})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	stop := errors.New("disk full")
+	count, err := client.SearchStream("dc=example,dc=com", "(uid=*)", ldap.ScopeSingleLevel, nil, 10, true, func(*Entry) error { return stop })
+	if !errors.Is(err, stop) || count != 0 {
+		t.Errorf("Expected the callback error to stop the search, got %d, %v", count, err)
+	}
+	if server.Searches() != 1 {
+		t.Errorf("Expected no further pages after the error, got %d searches", server.Searches())
+	}
+}
+
+func TestNewClient_BindError(t *testing.T) {
+	server := newStreamServer(t, 0)
+	server.BindDN = "cn=admin,dc=example,dc=com"
+	server.BindPassword = "secret"
+
+	_, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BindUser: server.BindDN, BindPass: "wrong"})
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) || !ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		t.Errorf("Expected a BindError for invalid credentials, got %v", err)
+	}
+
+	client, err := NewClient(Config{Host: "127.0.0.1", Port: server.Port(), BindUser: server.BindDN, BindPass: "secret"})
+	if err != nil {
+		t.Fatalf("Expected the bind to succeed, got %v", err)
+	}
+	client.Close()
+}
@@ -0,0 +1,66 @@
+package ldap
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket: it holds up to burst tokens, refilled at
+// rate per second, and each operation takes one. Operations wait when the
+// bucket is empty, so a long run settles at rate operations per second.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // Tokens added per second
+	burst  float64 // Most tokens the bucket holds
+	tokens float64 // Negative while waiting operations have reserved tokens
+	last   time.Time
+
+	// Swapped out by tests
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// newRateLimiter creates a limiter allowing rate operations per second, with
+// a burst of up to a second's worth. It starts full.
+func newRateLimiter(rate float64) *rateLimiter {
+	burst := math.Max(1, math.Ceil(rate))
+	return &rateLimiter{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  time.Sleep,
+	}
+}
+
+// Wait blocks until an operation may run
+func (l *rateLimiter) Wait() {
+	l.mu.Lock()
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	// Take the token now and wait for it to be refilled, so operations
+	// waiting together are spaced out rather than released at once
+	l.tokens--
+	wait := time.Duration(0)
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait > 0 {
+		l.sleep(wait)
+	}
+}
+
+// Throttle waits until the configured rate allows another bulk or recursive
+// operation, such as the next entry of a bulk modify. Single operations
+// started by the user aren't throttled. It returns at once when no rate is set.
+func (c *Client) Throttle() {
+	if c.limiter != nil {
+		c.limiter.Wait()
+	}
+}
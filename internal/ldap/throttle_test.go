@@ -0,0 +1,85 @@
+package ldap
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock drives a rateLimiter without real waiting: sleeping advances it
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (c *fakeClock) limiter(rate float64) *rateLimiter {
+	l := newRateLimiter(rate)
+	l.last = c.now
+	l.now = func() time.Time { return c.now }
+	l.sleep = func(d time.Duration) {
+		c.slept = append(c.slept, d)
+		c.now = c.now.Add(d)
+	}
+	return l
+}
+
+func TestRateLimiter_SpacesOutCalls(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := clock.limiter(10)
+
+	// A second's worth of operations runs at once, then they are spaced out
+	for i := 0; i < 10; i++ {
+		l.Wait()
+	}
+	if len(clock.slept) != 0 {
+		t.Fatalf("Expected the burst to run without waiting, waited %v", clock.slept)
+	}
+	start := clock.now
+	for i := 0; i < 5; i++ {
+		l.Wait()
+	}
+	for i, d := range clock.slept {
+		if d != 100*time.Millisecond {
+			t.Errorf("Expected call %d to wait 100ms, waited %v", i+11, d)
+		}
+	}
+	if elapsed := clock.now.Sub(start); elapsed != 500*time.Millisecond {
+		t.Errorf("Expected 5 calls over the limit to take 500ms, took %v", elapsed)
+	}
+
+	// Idle time refills the bucket, but only up to the burst
+	clock.now = clock.now.Add(time.Minute)
+	clock.slept = nil
+	for i := 0; i < 11; i++ {
+		l.Wait()
+	}
+	if len(clock.slept) != 1 {
+		t.Errorf("Expected only the call past the burst to wait, waited %v", clock.slept)
+	}
+}
+
+func TestRateLimiter_SlowRate(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := clock.limiter(0.5)
+
+	l.Wait()
+	l.Wait()
+	if len(clock.slept) != 1 || clock.slept[0] != 2*time.Second {
+		t.Errorf("Expected one operation every 2 seconds, waited %v", clock.slept)
+	}
+}
+
+func TestClient_ThrottleWithoutLimit(t *testing.T) {
+	client := &Client{}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			client.Throttle()
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Throttle to return at once without a rate")
+	}
+}
@@ -0,0 +1,92 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// tlsConfig builds the TLS settings used for both LDAPS and StartTLS.
+// ServerName is always set to the configured host: DialTLS can derive it from
+// the address, but StartTLS upgrades an existing socket and would otherwise
+// skip hostname validation.
+func (config Config) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         config.hostName(),
+		InsecureSkipVerify: !config.VerifyTLS,
+	}
+
+	pool, err := config.rootCAs()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.RootCAs = pool
+
+	return tlsConfig, nil
+}
+
+// rootCAs returns the CAs a verified connection trusts: those in
+// CACertPath, a PEM file or a directory of them, or otherwise the system
+// trust store, so corporate CAs installed system-wide work without a path.
+// Connections that don't verify the certificate need no pool and get nil.
+func (config Config) rootCAs() (*x509.CertPool, error) {
+	if config.CACertPath != "" {
+		return loadCACertPool(config.CACertPath)
+	}
+	if !config.VerifyTLS {
+		return nil, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		// Leaving RootCAs unset makes crypto/tls try the platform verifier itself
+		return nil, nil
+	}
+	return pool, nil
+}
+
+// loadCACertPool reads PEM encoded CA certificates from path. A directory
+// is read file by file, skipping files that hold no certificates, such as
+// the hash links and READMEs found in CA directories.
+func loadCACertPool(path string) (*x509.CertPool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", path)
+		}
+		return pool, nil
+	}
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate directory: %w", err)
+	}
+	found := false
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(path, file.Name()))
+		if err != nil {
+			continue
+		}
+		if pool.AppendCertsFromPEM(data) {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,116 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// certificateValidityPattern finds the dates in the message of an expired or
+// not yet valid certificate, which StartTLS only passes on as text
+var certificateValidityPattern = regexp.MustCompile(`current time (\S+) is (after|before) ([0-9TZ:.+-]+)`)
+
+// certificateNamesPattern finds the names a certificate is valid for in the
+// message of a hostname mismatch
+var certificateNamesPattern = regexp.MustCompile(`certificate is valid for (.+), not \S+`)
+
+// explainTLS attaches an explanation to a failed connection when its cause
+// was the TLS handshake, and otherwise returns err unchanged
+func (config Config) explainTLS(err error) error {
+	if explanation, ok := ExplainTLSError(err, config); ok {
+		return &ExplainedError{Explanation: explanation, Err: err}
+	}
+	return err
+}
+
+// ExplainTLSError looks up an explanation for a TLS handshake failure with
+// the server of config: an expired certificate, one issued by a CA that isn't
+// trusted, one for another host name, or a server that doesn't speak a TLS
+// version in common. go-ldap keeps the x509 and tls error types for LDAPS
+// but only the message for StartTLS, so both are checked.
+func ExplainTLSError(err error, config Config) (Explanation, bool) {
+	if err == nil {
+		return Explanation{}, false
+	}
+	message := err.Error()
+
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) && invalid.Reason == x509.Expired && invalid.Cert != nil {
+		if time.Now().Before(invalid.Cert.NotBefore) {
+			return notYetValidExplanation(invalid.Cert.NotBefore), true
+		}
+		return expiredExplanation(invalid.Cert.NotAfter), true
+	}
+	if match := certificateValidityPattern.FindStringSubmatch(message); match != nil {
+		date, parseErr := time.Parse(time.RFC3339, match[3])
+		if parseErr == nil && match[2] == "before" {
+			return notYetValidExplanation(date), true
+		}
+		if parseErr == nil {
+			return expiredExplanation(date), true
+		}
+	}
+
+	var unknown x509.UnknownAuthorityError
+	if errors.As(err, &unknown) || strings.Contains(message, "certificate signed by unknown authority") {
+		fix := "Set the CA certificate path (ca_cert_path) to the PEM file of the CA that issued it, or turn off certificate verification to connect anyway"
+		if config.CACertPath != "" {
+			fix = fmt.Sprintf("The CAs in %s didn't issue it; point ca_cert_path at the CA that did", config.CACertPath)
+		}
+		return Explanation{"Server certificate signed by an unknown authority", fix}, true
+	}
+
+	var hostname x509.HostnameError
+	if errors.As(err, &hostname) && hostname.Certificate != nil {
+		return hostnameExplanation(config.hostName(), hostname.Certificate.DNSNames), true
+	}
+	if strings.Contains(message, "x509: certificate is valid for") || strings.Contains(message, "x509: certificate is not valid for any names") {
+		var names []string
+		if match := certificateNamesPattern.FindStringSubmatch(message); match != nil {
+			names = strings.Split(match[1], ", ")
+		}
+		return hostnameExplanation(config.hostName(), names), true
+	}
+
+	var header tls.RecordHeaderError
+	if errors.As(err, &header) || strings.Contains(message, "first record does not look like a TLS handshake") {
+		return Explanation{"Server didn't answer with TLS", "The port expects plain LDAP; use SSL on the LDAPS port (usually 636), or StartTLS on the plain port (usually 389)"}, true
+	}
+
+	if strings.Contains(message, "protocol version not supported") || strings.Contains(message, "unsupported protocol version") || strings.Contains(message, "no supported versions satisfy") {
+		return Explanation{"No TLS version in common with the server", "The server only offers versions older than TLS 1.2, which are refused; enable TLS 1.2 or later on the server"}, true
+	}
+	if strings.Contains(message, "tls: handshake failure") {
+		return Explanation{"Server rejected the TLS handshake", "The server may require a client certificate or ciphers this client doesn't offer; check its TLS settings"}, true
+	}
+	return Explanation{}, false
+}
+
+// expiredExplanation explains a server certificate that has expired
+func expiredExplanation(notAfter time.Time) Explanation {
+	return Explanation{
+		fmt.Sprintf("Server certificate expired on %s", notAfter.UTC().Format("2006-01-02 15:04 UTC")),
+		"Renew the server's certificate, or check this computer's clock",
+	}
+}
+
+// notYetValidExplanation explains a server certificate that isn't valid yet
+func notYetValidExplanation(notBefore time.Time) Explanation {
+	return Explanation{
+		fmt.Sprintf("Server certificate isn't valid until %s", notBefore.UTC().Format("2006-01-02 15:04 UTC")),
+		"Check this computer's clock, or wait for the certificate to become valid",
+	}
+}
+
+// hostnameExplanation explains a server certificate issued for other names
+func hostnameExplanation(host string, names []string) Explanation {
+	fix := "Connect using a host name listed in the server's certificate"
+	if len(names) > 0 {
+		fix = fmt.Sprintf("Connect using a host name the certificate is issued for: %s", strings.Join(names, ", "))
+	}
+	return Explanation{fmt.Sprintf("Server certificate doesn't match the host %s", host), fix}
+}
@@ -0,0 +1,92 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// startTLSError wraps err as go-ldap reports a failed StartTLS handshake,
+// keeping only its message
+func startTLSError(err error) error {
+	return fmt.Errorf("failed to start TLS: %w", ldap.NewError(ldap.ErrorNetwork, fmt.Errorf("TLS handshake failed (%v)", err)))
+}
+
+// ldapsError wraps err as go-ldap reports a failed LDAPS dial, keeping its type
+func ldapsError(err error) error {
+	return fmt.Errorf("failed to connect to LDAP server: %w", ldap.NewError(ldap.ErrorNetwork, &tls.CertificateVerificationError{Err: err}))
+}
+
+func TestExplainTLSError(t *testing.T) {
+	config := Config{Host: "ldap.example.com"}
+	expired := &x509.Certificate{
+		NotBefore: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:  time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC),
+	}
+	future := &x509.Certificate{NotBefore: time.Now().Add(48 * time.Hour), NotAfter: time.Now().Add(96 * time.Hour)}
+	expiredErr := x509.CertificateInvalidError{Cert: expired, Reason: x509.Expired, Detail: "current time 2025-01-01T00:00:00Z is after 2024-06-30T12:00:00Z"}
+	hostnameErr := x509.HostnameError{Certificate: &x509.Certificate{DNSNames: []string{"dc1.corp.example.com", "corp.example.com"}}, Host: "ldap.example.com"}
+
+	tests := []struct {
+		name    string
+		err     error
+		summary string
+		fix     string
+	}{
+		{"expired over LDAPS", ldapsError(expiredErr), "Server certificate expired on 2024-06-30 12:00 UTC", "Renew"},
+		{"expired over StartTLS", startTLSError(expiredErr), "Server certificate expired on 2024-06-30 12:00 UTC", "Renew"},
+		{"not yet valid", ldapsError(x509.CertificateInvalidError{Cert: future, Reason: x509.Expired}), "Server certificate isn't valid until", "clock"},
+		{"unknown authority", ldapsError(x509.UnknownAuthorityError{}), "Server certificate signed by an unknown authority", "ca_cert_path"},
+		{"unknown authority over StartTLS", startTLSError(x509.UnknownAuthorityError{}), "Server certificate signed by an unknown authority", "ca_cert_path"},
+		{"hostname mismatch", ldapsError(hostnameErr), "Server certificate doesn't match the host ldap.example.com", "dc1.corp.example.com, corp.example.com"},
+		{"hostname mismatch over StartTLS", startTLSError(hostnameErr), "Server certificate doesn't match the host ldap.example.com", "dc1.corp.example.com, corp.example.com"},
+		{"plain LDAP port", ldapsError(tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}), "Server didn't answer with TLS", "636"},
+		{"old TLS version", startTLSError(errors.New("remote error: tls: protocol version not supported")), "No TLS version in common with the server", "TLS 1.2"},
+		{"handshake rejected", startTLSError(errors.New("remote error: tls: handshake failure")), "Server rejected the TLS handshake", "client certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation, ok := ExplainTLSError(tt.err, config)
+			if !ok {
+				t.Fatalf("Expected an explanation for %v", tt.err)
+			}
+			if !strings.HasPrefix(explanation.Summary, tt.summary) || !strings.Contains(explanation.Fix, tt.fix) {
+				t.Errorf("Unexpected explanation %+v", explanation)
+			}
+		})
+	}
+
+	if _, ok := ExplainTLSError(errors.New("dial tcp 127.0.0.1:636: connect: connection refused"), config); ok {
+		t.Error("Expected no TLS explanation for a refused connection")
+	}
+}
+
+func TestExplainTLSError_NamesCAPath(t *testing.T) {
+	explanation, _ := ExplainTLSError(ldapsError(x509.UnknownAuthorityError{}), Config{CACertPath: "/etc/ssl/corp-ca.pem"})
+	if !strings.Contains(explanation.Fix, "/etc/ssl/corp-ca.pem") {
+		t.Errorf("Expected the fix to name the configured CA file, got %q", explanation.Fix)
+	}
+}
+
+func TestNewClient_ExplainsTLSFailures(t *testing.T) {
+	ca := newTestCA(t)
+	port := startTLSServer(t, ca.issue(t, "ldap.example.com"))
+
+	_, err := NewClient(Config{Host: "localhost", Port: port, UseTLS: true, VerifyTLS: true, CACertPath: ca.writePEM(t)})
+	var explained *ExplainedError
+	if !errors.As(err, &explained) {
+		t.Fatalf("Expected an explained error, got %v", err)
+	}
+	if explained.Summary != "Server certificate doesn't match the host localhost" || !strings.Contains(explained.Fix, "ldap.example.com") {
+		t.Errorf("Unexpected explanation %+v", explained.Explanation)
+	}
+	if !strings.Contains(err.Error(), "failed to start TLS") {
+		t.Errorf("Expected the raw error to be kept, got %v", err)
+	}
+}
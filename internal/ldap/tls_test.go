@@ -0,0 +1,288 @@
+package ldap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// testCA is a certificate authority for issuing test server certificates
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "moribito test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue creates a server certificate for the given DNS names
+func (ca *testCA) issue(t *testing.T, dnsNames ...string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// writePEM writes the CA certificate to a temp file and returns its path
+func (ca *testCA) writePEM(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(path, ca.pem, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// startTLSServer listens on localhost and answers a StartTLS request by
+// upgrading the connection with cert. It returns the port.
+func startTLSServer(t *testing.T, cert tls.Certificate) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveStartTLS(conn, cert)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// serveStartTLS handles one client: a StartTLS extended request, then
+// accepts binds until the client goes away
+func serveStartTLS(conn net.Conn, cert tls.Certificate) {
+	defer conn.Close()
+
+	packet, err := ber.ReadPacket(conn)
+	if err != nil || len(packet.Children) < 2 || packet.Children[1].Tag != ldap.ApplicationExtendedRequest {
+		return
+	}
+	if _, err := conn.Write(successResponse(packet, ldap.ApplicationExtendedResponse).Bytes()); err != nil {
+		return
+	}
+
+	tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err := tlsConn.Handshake(); err != nil {
+		return
+	}
+	for {
+		packet, err := ber.ReadPacket(tlsConn)
+		if err != nil {
+			return
+		}
+		if len(packet.Children) >= 2 && packet.Children[1].Tag == ldap.ApplicationBindRequest {
+			if _, err := tlsConn.Write(successResponse(packet, ldap.ApplicationBindResponse).Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// successResponse answers request with a success result of the given type
+func successResponse(request *ber.Packet, tag ber.Tag) *ber.Packet {
+	messageID := request.Children[0].Value.(int64)
+	response := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(ldap.LDAPResultSuccess), "resultCode"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+	response.AppendChild(result)
+	return response
+}
+
+func TestStartTLS_VerifiesHostname(t *testing.T) {
+	ca := newTestCA(t)
+	caPath := ca.writePEM(t)
+
+	tests := []struct {
+		name    string
+		cert    tls.Certificate
+		verify  bool
+		caPath  string
+		wantErr string
+	}{
+		{"matching host and CA", ca.issue(t, "localhost"), true, caPath, ""},
+		{"hostname mismatch", ca.issue(t, "ldap.example.com"), true, caPath, "localhost"},
+		{"CA directory", ca.issue(t, "localhost"), true, filepath.Dir(caPath), ""},
+		{"unknown CA", ca.issue(t, "localhost"), true, "", "unknown authority"},
+		{"verification off", ca.issue(t, "ldap.example.com"), false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := startTLSServer(t, tt.cert)
+
+			client, err := NewClient(Config{
+				Host:       "localhost",
+				Port:       port,
+				UseTLS:     true,
+				VerifyTLS:  tt.verify,
+				CACertPath: tt.caPath,
+			})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected StartTLS to succeed, got %v", err)
+				}
+				client.Close()
+				return
+			}
+
+			if err == nil {
+				client.Close()
+				t.Fatal("Expected StartTLS to fail")
+			}
+			if !strings.Contains(err.Error(), "failed to start TLS") || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected a TLS error mentioning %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	config := Config{Host: "ldap.example.com", Port: 389}
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsConfig.ServerName != "ldap.example.com" || !tlsConfig.InsecureSkipVerify || tlsConfig.RootCAs != nil {
+		t.Errorf("Unexpected default TLS config: %+v", tlsConfig)
+	}
+
+	// Verifying without a CA path trusts the system store
+	config.VerifyTLS = true
+	tlsConfig, err = config.tlsConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if system, err := x509.SystemCertPool(); err == nil && !tlsConfig.RootCAs.Equal(system) {
+		t.Error("Expected the system trust store without a CA path")
+	}
+
+	config.CACertPath = newTestCA(t).writePEM(t)
+	tlsConfig, err = config.tlsConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsConfig.InsecureSkipVerify || tlsConfig.RootCAs == nil {
+		t.Error("Expected verification with the configured CA")
+	}
+
+	config.CACertPath = filepath.Join(t.TempDir(), "missing.pem")
+	if _, err := config.tlsConfig(); err == nil || !strings.Contains(err.Error(), "failed to read CA certificate") {
+		t.Errorf("Expected a read error, got %v", err)
+	}
+
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config.CACertPath = notPEM
+	if _, err := config.tlsConfig(); err == nil || !strings.Contains(err.Error(), "no PEM certificates") {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+
+	if _, err := NewClient(Config{Host: "localhost", Port: 1, UseTLS: true, CACertPath: config.CACertPath}); err == nil {
+		t.Error("Expected NewClient to fail with an invalid CA file")
+	}
+}
+
+func TestLoadCACertPool_Directory(t *testing.T) {
+	corp, partner := newTestCA(t), newTestCA(t)
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"corp-ca.pem":    corp.pem,
+		"partner-ca.crt": partner.pem,
+		"README":         []byte("CA certificates for the directory servers"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "old"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	pool, err := loadCACertPool(dir)
+	if err != nil {
+		t.Fatalf("Expected the directory to load, got %v", err)
+	}
+	for name, ca := range map[string]*testCA{"corp": corp, "partner": partner} {
+		leaf, err := x509.ParseCertificate(ca.issue(t, "ldap.example.com").Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := leaf.Verify(x509.VerifyOptions{Roots: pool, DNSName: "ldap.example.com"}); err != nil {
+			t.Errorf("Expected a certificate issued by the %s CA to verify, got %v", name, err)
+		}
+	}
+
+	if _, err := loadCACertPool(t.TempDir()); err == nil || !strings.Contains(err.Error(), "no PEM certificates") {
+		t.Errorf("Expected an error for a directory without certificates, got %v", err)
+	}
+}
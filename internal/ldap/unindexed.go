@@ -0,0 +1,77 @@
+package ldap
+
+import (
+	"fmt"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// SubstringAttributes returns the attributes filter matches by substring,
+// such as mail in (mail=*@example.com), once each in the order they appear.
+// Presence filters like (mail=*) aren't substring matches. A filter that
+// doesn't compile has none.
+func SubstringAttributes(filter string) []string {
+	packet, err := ldap.CompileFilter(filter)
+	if err != nil {
+		return nil
+	}
+
+	var attributes []string
+	seen := make(map[string]bool)
+	var walk func(packet *ber.Packet)
+	walk = func(packet *ber.Packet) {
+		switch packet.Tag {
+		case ldap.FilterAnd, ldap.FilterOr, ldap.FilterNot:
+			for _, child := range packet.Children {
+				walk(child)
+			}
+		case ldap.FilterSubstrings:
+			if len(packet.Children) == 0 {
+				return
+			}
+			attribute := packet.Children[0].Data.String()
+			if key := strings.ToLower(attribute); !seen[key] {
+				seen[key] = true
+				attributes = append(attributes, attribute)
+			}
+		}
+	}
+	walk(packet)
+	return attributes
+}
+
+// IsUnindexedSearchError reports whether err looks like the server refusing
+// or cutting short a search that would scan entries without an index. 389
+// Directory Server and OpenDJ say "unindexed" outright; other servers return
+// an administrative or time limit instead.
+func IsUnindexedSearchError(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := strings.ToLower(err.Error())
+	if strings.Contains(message, "unindexed") || strings.Contains(message, "not indexed") {
+		return true
+	}
+	return ldap.IsErrorAnyOf(err, ldap.LDAPResultAdminLimitExceeded, ldap.LDAPResultTimeLimitExceeded)
+}
+
+// UnindexedSearchHint suggests which attributes of filter may lack an index
+// when err is a search failing as IsUnindexedSearchError describes, and is
+// empty otherwise. Limits hit by filters without substrings are left to the
+// error itself, unless the server named the missing index.
+func UnindexedSearchHint(filter string, err error) string {
+	if !IsUnindexedSearchError(err) {
+		return ""
+	}
+	attributes := SubstringAttributes(filter)
+	if len(attributes) == 0 {
+		if !strings.Contains(strings.ToLower(err.Error()), "index") {
+			return ""
+		}
+		return "The server refused an unindexed search; filter on indexed attributes or narrow the base DN"
+	}
+	return fmt.Sprintf("%s may not be indexed for substring searches; try an exact match, a leading value such as (%s=abc*), or a narrower base DN",
+		strings.Join(attributes, ", "), attributes[0])
+}
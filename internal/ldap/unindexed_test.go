@@ -0,0 +1,71 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+func TestSubstringAttributes(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"(mail=*@example.com)", []string{"mail"}},
+		{"(&(objectClass=person)(|(cn=*smith*)(sn=smi*)(CN=*jones)))", []string{"cn", "sn"}},
+		{"(!(description=*temp*))", []string{"description"}},
+		{"(uid=jdoe)", nil},
+		{"(mail=*)", nil},
+		{"(cn=*", nil},
+	}
+	for _, tt := range tests {
+		if got := SubstringAttributes(tt.filter); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SubstringAttributes(%q) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestIsUnindexedSearchError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"389 unindexed", ldap.NewError(ldap.LDAPResultUnwillingToPerform, errors.New("Unindexed search: db configuration requires indexing")), true},
+		{"OpenDJ privilege", ldap.NewError(ldap.LDAPResultInsufficientAccessRights, errors.New("You do not have sufficient privileges to perform an unindexed search")), true},
+		{"admin limit", ldap.NewError(ldap.LDAPResultAdminLimitExceeded, errors.New("")), true},
+		{"wrapped time limit", fmt.Errorf("paged search failed: %w", ldap.NewError(ldap.LDAPResultTimeLimitExceeded, errors.New(""))), true},
+		{"size limit", ldap.NewError(ldap.LDAPResultSizeLimitExceeded, errors.New("")), false},
+		{"access denied", ldap.NewError(ldap.LDAPResultInsufficientAccessRights, errors.New("no read access")), false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsUnindexedSearchError(tt.err); got != tt.want {
+			t.Errorf("%s: IsUnindexedSearchError = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnindexedSearchHint(t *testing.T) {
+	adminLimit := ldap.NewError(ldap.LDAPResultAdminLimitExceeded, errors.New(""))
+
+	hint := UnindexedSearchHint("(&(objectClass=person)(mail=*@example.com))", adminLimit)
+	if !strings.Contains(hint, "mail may not be indexed") || !strings.Contains(hint, "(mail=abc*)") {
+		t.Errorf("Expected a hint naming mail, got %q", hint)
+	}
+
+	if hint := UnindexedSearchHint("(uid=jdoe)", adminLimit); hint != "" {
+		t.Errorf("Expected no hint for a limit on a filter without substrings, got %q", hint)
+	}
+	unindexed := ldap.NewError(ldap.LDAPResultUnwillingToPerform, errors.New("Unindexed search"))
+	if hint := UnindexedSearchHint("(description=x)", unindexed); !strings.Contains(hint, "unindexed search") {
+		t.Errorf("Expected a general hint when the server says the search is unindexed, got %q", hint)
+	}
+	if hint := UnindexedSearchHint("(mail=*x*)", ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New(""))); hint != "" {
+		t.Errorf("Expected no hint for unrelated errors, got %q", hint)
+	}
+}
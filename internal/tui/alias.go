@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// aliasStyle marks alias entries and the DN they point to
+var aliasStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("13")).Italic(true)
+
+// openAliasTarget reads the entry an alias points to and shows it in the
+// record view
+func openAliasTarget(client *ldap.Client, target string) tea.Cmd {
+	if target == "" {
+		return SendError(fmt.Errorf("this entry isn't an alias"))
+	}
+	if client == nil {
+		return SendError(fmt.Errorf("not connected"))
+	}
+	return func() tea.Msg {
+		entry, err := client.GetEntry(target)
+		if err != nil {
+			if ldap.IsEntryNotFound(err) {
+				return EntryNotFoundMsg{DN: target}
+			}
+			return ErrorMsg{Err: fmt.Errorf("failed to read alias target %s: %w", target, err)}
+		}
+		return ShowRecordMsg{Entry: entry}
+	}
+}
+
+// renderAliasLine describes where an alias entry points, for the record view
+func renderAliasLine(entry *ldap.Entry) string {
+	target := entry.AliasTarget()
+	if target == "" {
+		return aliasStyle.Render(iconAlias.String() + " Alias without an aliasedObjectName")
+	}
+	return aliasStyle.Render(fmt.Sprintf("%s Alias of %s • [O] open target", iconAlias.String(), target))
+}
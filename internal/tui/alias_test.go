@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func TestTreeView_MarksAliases(t *testing.T) {
+	tv := NewTreeView(nil)
+	tv.SetSize(80, 10)
+
+	alias := &TreeItem{Node: &ldap.TreeNode{DN: "cn=jane,dc=example,dc=com", Name: "cn=jane", Alias: "uid=jdoe,dc=example,dc=com"}, Level: 1}
+	if got := ansi.Strip(tv.renderTreeItem(alias, false, 80)); !strings.Contains(got, "cn=jane "+iconAlias.String()+" uid=jdoe,dc=example,dc=com") {
+		t.Errorf("Expected the alias target after the name, got %q", got)
+	}
+
+	tv.FlattenedTree = []*TreeItem{{Node: &ldap.TreeNode{DN: "uid=jdoe,dc=example,dc=com", Name: "uid=jdoe"}}}
+	_, cmd := tv.Update(keyRune('o'))
+	if msg, ok := cmd().(ErrorMsg); !ok || !strings.Contains(msg.Err.Error(), "isn't an alias") {
+		t.Errorf("Expected an error for an entry that isn't an alias, got %#v", cmd())
+	}
+}
+
+func TestRecordView_OpensAliasTarget(t *testing.T) {
+	zone.NewGlobal()
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"uid": {"jdoe"}}},
+	)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	rv := NewRecordView()
+	rv.SetClient(client)
+	rv.SetSize(100, 20)
+	rv.SetEntry(&ldap.Entry{
+		DN: "cn=jane,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"objectClass":       {"alias", "extensibleObject"},
+			"aliasedObjectName": {"uid=jdoe,dc=example,dc=com"},
+		},
+	})
+	if view := ansi.Strip(rv.View()); !strings.Contains(view, "Alias of uid=jdoe,dc=example,dc=com") {
+		t.Errorf("Expected the alias target under the DN, got:\n%s", view)
+	}
+
+	_, cmd := rv.Update(keyRune('o'))
+	if cmd == nil {
+		t.Fatal("Expected O to open the alias target")
+	}
+	msg, ok := cmd().(ShowRecordMsg)
+	if !ok || msg.Entry.DN != "uid=jdoe,dc=example,dc=com" {
+		t.Errorf("Expected the target entry to be shown, got %#v", cmd())
+	}
+}
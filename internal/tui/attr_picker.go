@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// commonAttributes are offered by the attribute picker when the server
+// schema couldn't be read, covering RFC 4519, inetOrgPerson, POSIX and
+// Active Directory
+var commonAttributes = []string{
+	"accountExpires", "businessCategory", "c", "cn", "createTimestamp", "dc",
+	"department", "description", "displayName", "employeeNumber", "employeeType",
+	"entryUUID", "gidNumber", "givenName", "homeDirectory", "initials", "l",
+	"lastLogonTimestamp", "loginShell", "mail", "manager", "member", "memberOf",
+	"memberUid", "mobile", "modifyTimestamp", "o", "objectClass", "objectGUID",
+	"ou", "postalAddress", "postalCode", "pwdLastSet", "sAMAccountName", "sn",
+	"st", "street", "telephoneNumber", "title", "uid", "uidNumber",
+	"uniqueMember", "userAccountControl", "userPassword", "userPrincipalName",
+	"whenChanged", "whenCreated",
+}
+
+// attributePickerRows is how many matching attributes the picker lists at once
+const attributePickerRows = 8
+
+// AttributePicker is an overlay for choosing an attribute name by typing the
+// start of it, for inputs that take attribute names
+type AttributePicker struct {
+	input   textinput.Model
+	names   []string // Every attribute offered
+	matches []string // Attributes matching the input, sorted
+	cursor  int
+	schema  *ldap.Schema // Describes the highlighted attribute, may be nil
+}
+
+// NewAttributePicker creates a picker over names, or over common attribute
+// names when there are none. The schema, if known, describes each attribute.
+func NewAttributePicker(names []string, schema *ldap.Schema) *AttributePicker {
+	if len(names) == 0 {
+		names = commonAttributes
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "start typing an attribute name"
+	ti.CharLimit = 0
+	ti.Width = 40
+	ti.Focus()
+
+	p := &AttributePicker{input: ti, names: names, schema: schema}
+	p.filter()
+	return p
+}
+
+// filter lists the attributes matching the input and keeps the cursor on one
+func (p *AttributePicker) filter() {
+	p.matches = matchCandidates(p.names, strings.TrimSpace(p.input.Value()))
+	p.cursor = min(p.cursor, max(len(p.matches)-1, 0))
+}
+
+// Update handles a key press. It returns true when the picker should close,
+// along with the chosen attribute or "" if it was cancelled. A name that
+// matches nothing can still be picked by typing it in full.
+func (p *AttributePicker) Update(msg tea.KeyMsg) (bool, string, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+t":
+		return true, "", nil
+	case "up":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return false, "", nil
+	case "down":
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+		return false, "", nil
+	case "tab":
+		completion := CompleteAttributes(strings.TrimSpace(p.input.Value()), p.matches)
+		p.input.SetValue(completion.Value)
+		p.input.CursorEnd()
+		p.filter()
+		return false, "", nil
+	case "enter":
+		if len(p.matches) > 0 {
+			return true, p.matches[p.cursor], nil
+		}
+		if typed := strings.TrimSpace(p.input.Value()); validAttributeName(typed) {
+			return true, typed, nil
+		}
+		return false, "", nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.cursor = 0
+	p.filter()
+	return false, "", cmd
+}
+
+// View renders the picker as a bordered box
+func (p *AttributePicker) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+
+	sections := []string{
+		titleStyle.Render("Pick an attribute") + "  " + dimStyle.Render(fmt.Sprintf("%d of %d", len(p.matches), len(p.names))),
+		p.input.View(),
+		"",
+	}
+
+	if len(p.matches) == 0 {
+		sections = append(sections, dimStyle.Render("No attribute names match; [Enter] uses the name as typed"))
+	}
+
+	// Keep the cursor in a window of the matches
+	start := max(0, min(p.cursor-attributePickerRows/2, len(p.matches)-attributePickerRows))
+	end := min(start+attributePickerRows, len(p.matches))
+	for i := start; i < end; i++ {
+		name := p.matches[i]
+		if i != p.cursor {
+			sections = append(sections, "  "+name)
+			continue
+		}
+		line := editorFocusStyle.Render("▶ ") + name
+		if at := p.schema.AttributeType(name); at != nil && at.Description != "" {
+			line += "  " + dimStyle.Render(at.Description)
+		}
+		sections = append(sections, line)
+	}
+
+	sections = append(sections, "", helpStyle.Render("[↑↓] select • [Tab] complete • [Enter] pick • [Esc] cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("6")).
+		Padding(0, 1).
+		Render(strings.Join(sections, "\n"))
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeIntoPicker sends text to the picker one key at a time
+func typeIntoPicker(p *AttributePicker, text string) {
+	for _, r := range text {
+		p.Update(keyRune(r))
+	}
+}
+
+func TestAttributePicker_PrefixFiltering(t *testing.T) {
+	p := NewAttributePicker([]string{"mail", "member", "memberOf", "cn", "mobile", "Member"}, nil)
+	if len(p.matches) != 5 {
+		t.Errorf("Expected every distinct name before typing, got %v", p.matches)
+	}
+
+	typeIntoPicker(p, "ME")
+	if want := []string{"member", "memberOf"}; !reflect.DeepEqual(p.matches, want) {
+		t.Errorf("Expected a case-insensitive prefix match, got %v", p.matches)
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	done, name, _ := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !done || name != "memberOf" {
+		t.Errorf("Expected Enter to pick the highlighted attribute, got %q", name)
+	}
+
+	// Narrowing the list keeps the cursor on a match
+	p = NewAttributePicker([]string{"mail", "member", "memberOf"}, nil)
+	p.cursor = 2
+	typeIntoPicker(p, "mai")
+	if len(p.matches) != 1 || p.cursor != 0 {
+		t.Errorf("Expected the cursor on the only match, got %d of %v", p.cursor, p.matches)
+	}
+}
+
+func TestAttributePicker_UnknownNameAndCancel(t *testing.T) {
+	p := NewAttributePicker([]string{"cn"}, nil)
+	typeIntoPicker(p, "msDS-cloudExtensionAttribute1")
+	if len(p.matches) != 0 {
+		t.Fatalf("Expected no matches, got %v", p.matches)
+	}
+	if done, name, _ := p.Update(tea.KeyMsg{Type: tea.KeyEnter}); !done || name != "msDS-cloudExtensionAttribute1" {
+		t.Errorf("Expected a name typed in full to be picked, got %q", name)
+	}
+
+	p = NewAttributePicker([]string{"cn"}, nil)
+	if done, name, _ := p.Update(tea.KeyMsg{Type: tea.KeyEsc}); !done || name != "" {
+		t.Errorf("Expected Esc to cancel, got %v %q", done, name)
+	}
+}
+
+func TestAttributePicker_FallsBackToCommonAttributes(t *testing.T) {
+	p := NewAttributePicker(nil, nil)
+	typeIntoPicker(p, "sAMA")
+	if len(p.matches) != 1 || p.matches[0] != "sAMAccountName" {
+		t.Errorf("Expected the common attributes without a schema, got %v", p.matches)
+	}
+}
+
+func TestQueryView_PickAttribute(t *testing.T) {
+	qv := NewQueryView(nil)
+	qv.SetSize(100, 30)
+	qv.setFocus(queryFieldAttributes)
+	qv.attrsInput.SetValue("cn, ma")
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if qv.picker == nil || !qv.IsCompletingField() {
+		t.Fatal("Expected Ctrl+T to open the picker and keep Tab for it")
+	}
+	qv.Update(keyRune('m'))
+	qv.Update(keyRune('a'))
+	qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if qv.picker != nil || qv.attrsInput.Value() != "cn, mail" {
+		t.Errorf("Expected the picked name to replace the one being typed, got %q", qv.attrsInput.Value())
+	}
+
+	// In the filter the name is inserted at the cursor
+	qv.setFocus(queryFieldFilter)
+	qv.textarea.SetValue("(")
+	qv.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	typeIntoPicker(qv.picker, "uidN")
+	qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := qv.textarea.Value(); got != "(uidNumber" {
+		t.Errorf("Expected the name inserted into the filter, got %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ChangeBaseDNMsg asks to re-root the connection at DN
+type ChangeBaseDNMsg struct {
+	DN string
+}
+
+// ChangeBaseDN sends a message offering DN as the connection's base DN
+func ChangeBaseDN(dn string) tea.Cmd {
+	return func() tea.Msg {
+		return ChangeBaseDNMsg{DN: dn}
+	}
+}
+
+// baseDNAction is the choice made in the base DN prompt
+type baseDNAction int
+
+const (
+	baseDNCancel  baseDNAction = iota
+	baseDNCopy                 // Copy the DN to the clipboard
+	baseDNSession              // Use the DN until the next connect
+	baseDNSave                 // Use the DN and store it in the config
+)
+
+// BaseDNPrompt is an overlay offering to copy an entry's DN or make it the
+// base DN, either for the current session or saved with the connection
+type BaseDNPrompt struct {
+	dn         string
+	current    string // Base DN in use
+	connection string // Where a saved change goes, e.g. the saved connection name
+	canSave    bool
+}
+
+// NewBaseDNPrompt creates a prompt for re-rooting at dn. connection describes
+// where a saved change is written; saving is offered only when canSave is set.
+func NewBaseDNPrompt(dn, current, connection string, canSave bool) *BaseDNPrompt {
+	return &BaseDNPrompt{
+		dn:         dn,
+		current:    current,
+		connection: connection,
+		canSave:    canSave,
+	}
+}
+
+// Update handles a key press. It returns true when the prompt should close,
+// along with the chosen action.
+func (p *BaseDNPrompt) Update(msg tea.KeyMsg) (bool, baseDNAction) {
+	switch msg.String() {
+	case "esc":
+		return true, baseDNCancel
+	case "c", "C":
+		return true, baseDNCopy
+	case "enter":
+		return true, baseDNSession
+	case "s", "S":
+		if p.canSave {
+			return true, baseDNSave
+		}
+	}
+	return false, baseDNCancel
+}
+
+// View renders the prompt as a bordered box
+func (p *BaseDNPrompt) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	detailStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+
+	sections := []string{
+		titleStyle.Render("Set as base DN"),
+		"",
+		editorFocusStyle.Render(p.dn),
+		detailStyle.Render("Current base: " + p.current),
+		"",
+		"[Enter] use for this session only (the config is not changed)",
+	}
+	if p.canSave {
+		sections = append(sections, fmt.Sprintf("[S] use and save to %s", p.connection))
+	} else {
+		sections = append(sections, detailStyle.Render("Saving is unavailable without a config file"))
+	}
+	sections = append(sections, "[C] copy DN to clipboard", "", helpStyle.Render("[Esc] cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("6")).
+		Padding(0, 1).
+		Render(strings.Join(sections, "\n"))
+}
@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func newBaseDNModel(t *testing.T, configPath string) (*Model, *ldap.Client) {
+	cfg := config.Default()
+	cfg.LDAP.SavedConnections = []config.SavedConnection{
+		{Name: "Production", Host: "ldap.example.com", Port: 389, BaseDN: "dc=example,dc=com"},
+	}
+	cfg.SetActiveConnection(0)
+
+	model := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, configPath)
+	model.SetSize(100, 30)
+
+	client := &ldap.Client{}
+	client.SetBaseDN("dc=example,dc=com")
+	model.Update(ConnectMsg{Client: client, Config: cfg})
+	return model, client
+}
+
+func TestTreeView_SetAsBaseKey(t *testing.T) {
+	tv := NewTreeView(&ldap.Client{})
+	tv.FlattenedTree = []*TreeItem{
+		{Node: &ldap.TreeNode{DN: "dc=example,dc=com"}},
+		{Node: &ldap.TreeNode{DN: "ou=people,dc=example,dc=com"}, Level: 1},
+	}
+	tv.cursor = 1
+
+	_, cmd := tv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	if cmd == nil {
+		t.Fatal("Expected B to offer the node as base DN")
+	}
+	if msg, ok := cmd().(ChangeBaseDNMsg); !ok || msg.DN != "ou=people,dc=example,dc=com" {
+		t.Errorf("Expected ChangeBaseDNMsg for the selected node, got %#v", msg)
+	}
+}
+
+func TestModel_SetBaseDNForSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	model, client := newBaseDNModel(t, path)
+
+	model.Update(ChangeBaseDNMsg{DN: "ou=people,dc=example,dc=com"})
+	if model.baseDNPrompt == nil {
+		t.Fatal("Expected the base DN prompt to open")
+	}
+	if !model.isInputActive() {
+		t.Error("Expected the prompt to capture global keys")
+	}
+
+	zone.NewGlobal()
+	view := model.View()
+	if !strings.Contains(view, "this session only") || !strings.Contains(view, `"Production"`) {
+		t.Error("Expected the prompt to explain session and saved changes")
+	}
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.baseDNPrompt != nil {
+		t.Error("Expected the prompt to close")
+	}
+	if cmd == nil {
+		t.Error("Expected the tree to be reloaded")
+	}
+	if client.BaseDN() != "ou=people,dc=example,dc=com" {
+		t.Errorf("Expected the client base DN to change, got %q", client.BaseDN())
+	}
+	if got := model.startView.config.LDAP.SavedConnections[0].BaseDN; got != "dc=example,dc=com" {
+		t.Errorf("Expected the saved connection to be unchanged, got %q", got)
+	}
+	if model.currentView != ViewModeTree || !model.tree.loading {
+		t.Error("Expected the tree view to be shown and reloading")
+	}
+	if !strings.Contains(model.toasts.latest(), "for this session only") {
+		t.Errorf("Unexpected status: %q", model.toasts.latest())
+	}
+	if !strings.Contains(model.renderHelpBar(), "this session only") {
+		t.Error("Expected the help bar to say the base DN is session-only")
+	}
+}
+
+func TestModel_SetBaseDNAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	model, client := newBaseDNModel(t, path)
+
+	model.Update(ChangeBaseDNMsg{DN: "ou=people,dc=example,dc=com"})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+	if client.BaseDN() != "ou=people,dc=example,dc=com" {
+		t.Errorf("Expected the client base DN to change, got %q", client.BaseDN())
+	}
+	if model.sessionBaseDN {
+		t.Error("Expected a saved base DN not to be marked session-only")
+	}
+
+	saved, _, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+	if got := saved.LDAP.SavedConnections[0].BaseDN; got != "ou=people,dc=example,dc=com" {
+		t.Errorf("Expected the saved connection base DN to be written, got %q", got)
+	}
+}
+
+func TestModel_SetBaseDNSaveFailureKeepsTheBase(t *testing.T) {
+	// A directory can't be written as the config file
+	model, client := newBaseDNModel(t, t.TempDir())
+
+	model.Update(ChangeBaseDNMsg{DN: "ou=people,dc=example,dc=com"})
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+	if client.BaseDN() != "dc=example,dc=com" {
+		t.Errorf("Expected the client base DN to stay when saving fails, got %q", client.BaseDN())
+	}
+	cfg := model.startView.config
+	if cfg.LDAP.BaseDN != "dc=example,dc=com" || cfg.LDAP.SavedConnections[0].BaseDN != "dc=example,dc=com" {
+		t.Errorf("Expected the config to be put back, got %q and %q", cfg.LDAP.BaseDN, cfg.LDAP.SavedConnections[0].BaseDN)
+	}
+	reported := false
+	for _, msg := range runCmd(cmd) {
+		if tagged, ok := msg.(tabMsg); ok {
+			msg = tagged.msg
+		}
+		if msg, ok := msg.(ErrorMsg); ok && strings.Contains(msg.Err.Error(), "base DN left at dc=example,dc=com") {
+			reported = true
+		}
+	}
+	if !reported {
+		t.Error("Expected an error saying the base DN is unchanged")
+	}
+}
+
+func TestModel_SetBaseDNWithoutConfigFile(t *testing.T) {
+	model, client := newBaseDNModel(t, "")
+
+	model.Update(ChangeBaseDNMsg{DN: "ou=people,dc=example,dc=com"})
+	view := model.baseDNPrompt.View()
+	if !strings.Contains(view, "unavailable without a config file") {
+		t.Error("Expected saving to be shown as unavailable")
+	}
+
+	// S does nothing when the change cannot be saved
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if model.baseDNPrompt == nil {
+		t.Fatal("Expected the prompt to stay open")
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.baseDNPrompt != nil || client.BaseDN() != "dc=example,dc=com" {
+		t.Error("Expected Esc to close the prompt without changing the base DN")
+	}
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func TestCapabilityWarning(t *testing.T) {
+	if got := capabilityWarning(nil); got != "" {
+		t.Errorf("Expected no warning for unknown capabilities, got %q", got)
+	}
+
+	full := &ldap.Capabilities{
+		Controls:   map[string]bool{ldap.OIDPagedResults: true},
+		Extensions: map[string]bool{ldap.OIDPasswordModify: true},
+	}
+	if got := capabilityWarning(full); got != "" {
+		t.Errorf("Expected no warning when everything is supported, got %q", got)
+	}
+
+	limited := &ldap.Capabilities{
+		Controls: map[string]bool{ldap.OIDPagedResults: true},
+	}
+	got := capabilityWarning(limited)
+	if !strings.Contains(got, "password modify") {
+		t.Errorf("Expected missing password modify to be reported, got %q", got)
+	}
+	if strings.Contains(got, "paged results") {
+		t.Errorf("Expected supported paging not to be reported, got %q", got)
+	}
+}
+
+func TestModel_CapabilitiesLoadedSetsWarning(t *testing.T) {
+	model := NewModel(nil, nil)
+	model.notify(ToastSuccess, "Successfully connected to LDAP server")
+
+	// Capabilities for a different (stale) connection are ignored
+	other := &ldap.Client{}
+	model.Update(CapabilitiesLoadedMsg{Client: other, Capabilities: &ldap.Capabilities{}})
+	if model.toasts.latest() != "Successfully connected to LDAP server" {
+		t.Errorf("Expected stale capabilities to be ignored, got %q", model.toasts.latest())
+	}
+
+	model.Update(CapabilitiesLoadedMsg{Client: nil, Capabilities: &ldap.Capabilities{}})
+	if !strings.Contains(model.toasts.latest(), "Server limitations") {
+		t.Errorf("Expected limitations in the status bar, got %q", model.toasts.latest())
+	}
+}
@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+)
+
+// Tables whose column split can be adjusted
+const (
+	columnTableRecord = "record"
+	columnTableQuery  = "query"
+)
+
+// columnRatioStep is how far one press of < or > moves the column divider
+const columnRatioStep = 0.05
+
+// ColumnRatioChangedMsg is sent when the user moves a table's column divider,
+// so the new ratio can be saved to the config
+type ColumnRatioChangedMsg struct {
+	Table string
+	Ratio float64
+}
+
+// splitColumnWidths divides contentWidth between a table's two columns,
+// giving the first ratio of it (a third when ratio is zero) while keeping
+// both columns at least their minimum widths
+func splitColumnWidths(contentWidth int, ratio float64, minFirst, minSecond int) (first, second int) {
+	if ratio == 0 {
+		first = contentWidth / 3
+	} else {
+		first = int(math.Round(float64(contentWidth) * ratio))
+	}
+	if first < minFirst {
+		first = minFirst
+	}
+	second = contentWidth - first - 4 // Account for borders and spacing
+	if second < minSecond {
+		second = minSecond
+		first = contentWidth - second - 4
+	}
+	return first, second
+}
+
+// adjustColumnRatio moves ratio by steps of columnRatioStep, starting from a
+// third when ratio is zero, and keeps it within the configurable bounds
+func adjustColumnRatio(ratio float64, steps int) float64 {
+	if ratio == 0 {
+		ratio = 1.0 / 3
+	}
+	ratio = math.Round((ratio+float64(steps)*columnRatioStep)*100) / 100
+	return math.Max(config.MinColumnRatio, math.Min(config.MaxColumnRatio, ratio))
+}
+
+// columnRatioChanged reports a new column ratio for table
+func columnRatioChanged(table string, ratio float64) tea.Cmd {
+	return func() tea.Msg {
+		return ColumnRatioChangedMsg{Table: table, Ratio: ratio}
+	}
+}
+
+// columnRatioStatus describes a column ratio for the status bar
+func columnRatioStatus(column string, ratio float64) string {
+	return fmt.Sprintf("%s column: %d%% of the width", column, int(math.Round(ratio*100)))
+}
+
+// tableColumns is the column a table's keyboard resizing applies to and the
+// widths chosen that way. They last for the session only; the saved ratio
+// still sets the layout until a focused column is resized.
+type tableColumns struct {
+	focus  int   // Focused column, -1 for none
+	widths []int // Widths chosen this session, nil to follow the ratio
+}
+
+// newTableColumns returns a table's columns with none focused
+func newTableColumns() tableColumns {
+	return tableColumns{focus: -1}
+}
+
+// cycleFocus moves the focus to the next of count columns, and after the
+// last one back to none
+func (tc *tableColumns) cycleFocus(count int) {
+	tc.focus++
+	if tc.focus >= count {
+		tc.focus = -1
+	}
+}
+
+// focused reports whether a column has the focus
+func (tc *tableColumns) focused() bool {
+	return tc.focus >= 0
+}
+
+// title marks the focused column's header
+func (tc *tableColumns) title(index int, title string) string {
+	if index != tc.focus {
+		return title
+	}
+	return iconColumnFocus.String() + " " + title
+}
+
+// layout returns the widths to draw the columns at: base, the split from the
+// saved ratio, until a column is resized, and after that the session widths
+// fitted to base's total
+func (tc *tableColumns) layout(base, mins []int) []int {
+	if len(tc.widths) != len(base) {
+		return base
+	}
+	return fitColumnWidths(tc.widths, mins, sumWidths(base))
+}
+
+// resize grows the focused column by steps of columnRatioStep of the width
+// (shrinks it when negative), starting from the current layout
+func (tc *tableColumns) resize(base, mins []int, steps int) {
+	if !tc.focused() || tc.focus >= len(base) {
+		return
+	}
+	total := sumWidths(base)
+	delta := steps * max(int(math.Round(float64(total)*columnRatioStep)), 1)
+	tc.widths = resizeColumn(tc.layout(base, mins), mins, tc.focus, delta)
+}
+
+// resizeColumn returns widths with column index delta cells wider, taking
+// them from (or giving them to) the other columns, nearest first, so the
+// total stays the same and no column drops below its minimum
+func resizeColumn(widths, mins []int, index, delta int) []int {
+	resized := append([]int(nil), widths...)
+	if delta < 0 {
+		// Shrinking hands the cells to the next column, or the previous one
+		// for the last column
+		delta = -min(-delta, resized[index]-mins[index])
+		if delta == 0 || len(resized) < 2 {
+			return resized
+		}
+		neighbour := index + 1
+		if neighbour == len(resized) {
+			neighbour = index - 1
+		}
+		resized[index] += delta
+		resized[neighbour] -= delta
+		return resized
+	}
+
+	for distance := 1; delta > 0 && distance < len(resized); distance++ {
+		for _, other := range []int{index + distance, index - distance} {
+			if other < 0 || other >= len(resized) || delta == 0 {
+				continue
+			}
+			taken := min(delta, max(resized[other]-mins[other], 0))
+			resized[other] -= taken
+			resized[index] += taken
+			delta -= taken
+		}
+	}
+	return resized
+}
+
+// fitColumnWidths scales widths to add up to total, keeping every column at
+// least its minimum, for when the table's width changes after a resize
+func fitColumnWidths(widths, mins []int, total int) []int {
+	sum := sumWidths(widths)
+	if sum == total {
+		return widths
+	}
+	fitted := make([]int, len(widths))
+	for i, width := range widths {
+		if sum > 0 {
+			fitted[i] = width * total / sum
+		}
+		fitted[i] = max(fitted[i], mins[i])
+	}
+
+	// Settle rounding and the minimums one cell at a time on the widest
+	// column that can take the change
+	for used := sumWidths(fitted); used != total; {
+		widest := -1
+		for i := range fitted {
+			if (used < total || fitted[i] > mins[i]) && (widest < 0 || fitted[i] > fitted[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break // Narrower than the minimums allow
+		}
+		if used < total {
+			fitted[widest]++
+			used++
+		} else {
+			fitted[widest]--
+			used--
+		}
+	}
+	return fitted
+}
+
+// sumWidths adds up column widths
+func sumWidths(widths []int) int {
+	total := 0
+	for _, width := range widths {
+		total += width
+	}
+	return total
+}
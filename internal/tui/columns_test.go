@@ -0,0 +1,257 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func TestSplitColumnWidths(t *testing.T) {
+	tests := []struct {
+		name          string
+		width         int
+		ratio         float64
+		first, second int
+	}{
+		{"default third", 90, 0, 30, 56},
+		{"saved ratio", 100, 0.5, 50, 46},
+		{"same ratio on a wider terminal", 200, 0.5, 100, 96},
+		{"first column minimum", 40, 0.1, 15, 21},
+		{"second column minimum", 60, 0.9, 36, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := splitColumnWidths(tt.width, tt.ratio, 15, 20)
+			if first != tt.first || second != tt.second {
+				t.Errorf("Expected %d/%d, got %d/%d", tt.first, tt.second, first, second)
+			}
+		})
+	}
+}
+
+func TestAdjustColumnRatio(t *testing.T) {
+	if got := adjustColumnRatio(0, 1); got != 0.38 {
+		t.Errorf("Expected the first step to start from a third, got %g", got)
+	}
+	if got := adjustColumnRatio(0.5, -2); got != 0.4 {
+		t.Errorf("Expected two steps narrower, got %g", got)
+	}
+	if got := adjustColumnRatio(0.85, 3); got != config.MaxColumnRatio {
+		t.Errorf("Expected the ratio to stop at the maximum, got %g", got)
+	}
+	if got := adjustColumnRatio(0.15, -3); got != config.MinColumnRatio {
+		t.Errorf("Expected the ratio to stop at the minimum, got %g", got)
+	}
+}
+
+func TestRecordView_ColumnRatioFollowsResize(t *testing.T) {
+	rv := NewRecordView()
+	rv.SetColumnRatio(0.5)
+
+	for _, width := range []int{80, 120, 200} {
+		rv.SetSize(width, 30)
+		contentWidth, _ := rv.container.GetContentDimensions()
+		columns := rv.table.Columns()
+		if want, _ := splitColumnWidths(contentWidth, 0.5, 15, 20); columns[0].Width != want {
+			t.Errorf("Width %d: expected the attribute column to keep half the width (%d), got %d", width, want, columns[0].Width)
+		}
+	}
+}
+
+func TestRecordView_MoveColumnDivider(t *testing.T) {
+	rv := newMultiValueRecordView()
+	before := rv.table.Columns()[0].Width
+
+	_, cmd := rv.Update(keyRune('>'))
+	if rv.table.Columns()[0].Width <= before {
+		t.Errorf("Expected > to widen the attribute column from %d, got %d", before, rv.table.Columns()[0].Width)
+	}
+	if cmd == nil {
+		t.Fatal("Expected the new ratio to be reported")
+	}
+	msg, ok := cmd().(ColumnRatioChangedMsg)
+	if !ok || msg.Table != columnTableRecord || msg.Ratio != 0.38 {
+		t.Errorf("Expected a record ratio change to 0.38, got %#v", msg)
+	}
+}
+
+func TestQueryView_MoveColumnDivider(t *testing.T) {
+	qv := browsingQueryView([]*ldap.Entry{{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"uid": {"jdoe"}}}})
+	qv.SetSize(120, 40)
+	before := qv.table.Columns()[0].Width
+
+	_, cmd := qv.Update(keyRune('<'))
+	if qv.table.Columns()[0].Width >= before {
+		t.Errorf("Expected < to narrow the DN column from %d, got %d", before, qv.table.Columns()[0].Width)
+	}
+	if msg, ok := cmd().(ColumnRatioChangedMsg); !ok || msg.Table != columnTableQuery || msg.Ratio != 0.28 {
+		t.Errorf("Expected a query ratio change to 0.28, got %#v", msg)
+	}
+}
+
+func TestModel_SavesColumnRatio(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	model, _ := newBaseDNModel(t, path)
+
+	model.Update(ColumnRatioChangedMsg{Table: columnTableQuery, Ratio: 0.45})
+
+	saved, _, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Expected the config to be saved: %v", err)
+	}
+	if saved.Display.QueryColumnRatio != 0.45 {
+		t.Errorf("Expected the query ratio to be saved, got %g", saved.Display.QueryColumnRatio)
+	}
+	if model.toasts.latest() != "DN column: 45% of the width, saved" {
+		t.Errorf("Unexpected status %q", model.toasts.latest())
+	}
+}
+
+func TestTableColumns_CycleFocus(t *testing.T) {
+	tc := newTableColumns()
+	var order []int
+	for i := 0; i < 4; i++ {
+		tc.cycleFocus(2)
+		order = append(order, tc.focus)
+	}
+	if want := []int{0, 1, -1, 0}; fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("Expected focus to cycle %v, got %v", want, order)
+	}
+	if got := tc.title(0, "DN"); got != iconColumnFocus.String()+" DN" {
+		t.Errorf("Expected the focused header to be marked, got %q", got)
+	}
+	if got := tc.title(1, "Summary"); got != "Summary" {
+		t.Errorf("Expected other headers unmarked, got %q", got)
+	}
+}
+
+func TestResizeColumn_StaysWithinWidth(t *testing.T) {
+	mins := []int{15, 20}
+	tests := []struct {
+		name   string
+		index  int
+		delta  int
+		widths []int
+	}{
+		{"grow the first column", 0, 10, []int{40, 46}},
+		{"grow past the other's minimum", 0, 100, []int{66, 20}},
+		{"shrink the first column", 0, -10, []int{20, 66}},
+		{"shrink below the minimum", 0, -100, []int{15, 71}},
+		{"grow the last column", 1, 6, []int{24, 62}},
+		{"shrink the last column", 1, -6, []int{36, 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resizeColumn([]int{30, 56}, mins, tt.index, tt.delta)
+			if fmt.Sprint(got) != fmt.Sprint(tt.widths) {
+				t.Errorf("Expected %v, got %v", tt.widths, got)
+			}
+			if sumWidths(got) != 86 {
+				t.Errorf("Expected the total width to stay 86, got %d", sumWidths(got))
+			}
+		})
+	}
+}
+
+func TestFitColumnWidths(t *testing.T) {
+	mins := []int{15, 20}
+	if got := fitColumnWidths([]int{40, 46}, mins, 172); fmt.Sprint(got) != "[80 92]" {
+		t.Errorf("Expected the widths to scale with the table, got %v", got)
+	}
+	got := fitColumnWidths([]int{60, 26}, mins, 50)
+	if got[0] < mins[0] || got[1] < mins[1] || sumWidths(got) != 50 {
+		t.Errorf("Expected the minimums kept within 50 cells, got %v", got)
+	}
+}
+
+func TestRecordView_ResizeFocusedColumn(t *testing.T) {
+	zone.NewGlobal()
+	rv := newMultiValueRecordView()
+	contentWidth, _ := rv.container.GetContentDimensions()
+	total := func() int {
+		columns := rv.table.Columns()
+		return columns[0].Width + columns[1].Width
+	}
+	before := total()
+
+	rv.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if rv.columns.focus != 0 {
+		t.Fatalf("Expected shift+tab to focus the attribute column, got %d", rv.columns.focus)
+	}
+	if !strings.Contains(rv.renderTable(), iconColumnFocus.String()+" Attribute") {
+		t.Error("Expected the focused column's header to be marked")
+	}
+
+	nameWidth := rv.table.Columns()[0].Width
+	_, cmd := rv.Update(keyRune('>'))
+	if rv.table.Columns()[0].Width <= nameWidth {
+		t.Errorf("Expected > to widen the focused column from %d, got %d", nameWidth, rv.table.Columns()[0].Width)
+	}
+	if _, saved := cmd().(ColumnRatioChangedMsg); saved {
+		t.Error("Expected a session resize not to be saved")
+	}
+	if total() != before {
+		t.Errorf("Expected the columns to keep sharing %d cells, got %d", before, total())
+	}
+
+	// Shrinking the value column hands its cells back to the attribute column
+	rv.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	for i := 0; i < 30; i++ {
+		rv.Update(keyRune('<'))
+	}
+	columns := rv.table.Columns()
+	if columns[1].Width != recordColumnMins[1] || total() != before {
+		t.Errorf("Expected the value column to stop at its minimum within %d cells, got %d/%d", before, columns[0].Width, columns[1].Width)
+	}
+
+	// The session widths follow the table to a new size
+	rv.SetSize(160, 30)
+	wider, _ := rv.container.GetContentDimensions()
+	if got := total(); got != before+wider-contentWidth {
+		t.Errorf("Expected the columns to fill the wider table, got %d", got)
+	}
+
+	// Without a focused column < and > move the saved divider again
+	rv.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if _, cmd := rv.Update(keyRune('<')); cmd == nil {
+		t.Fatal("Expected the saved ratio to be reported")
+	} else if _, ok := cmd().(ColumnRatioChangedMsg); !ok {
+		t.Error("Expected < without a focused column to change the saved ratio")
+	}
+	if rv.columns.widths != nil {
+		t.Error("Expected moving the divider to drop the session widths")
+	}
+}
+
+func TestQueryView_ResizeFocusedColumn(t *testing.T) {
+	qv := browsingQueryView([]*ldap.Entry{{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"uid": {"jdoe"}}}})
+	qv.SetSize(120, 40)
+	before := qv.table.Columns()
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	qv.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if qv.columns.focus != 1 {
+		t.Fatalf("Expected the summary column focused, got %d", qv.columns.focus)
+	}
+	if !strings.Contains(qv.renderTable(), iconColumnFocus.String()+" Summary") {
+		t.Error("Expected the focused column's header to be marked")
+	}
+
+	qv.Update(keyRune('>'))
+	after := qv.table.Columns()
+	if after[1].Width <= before[1].Width || after[0].Width >= before[0].Width {
+		t.Errorf("Expected the summary column to grow at the DN column's expense, got %d/%d from %d/%d",
+			after[0].Width, after[1].Width, before[0].Width, before[1].Width)
+	}
+	if after[0].Width+after[1].Width != before[0].Width+before[1].Width {
+		t.Error("Expected the columns to keep sharing the same width")
+	}
+}
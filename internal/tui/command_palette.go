@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// viewAny marks palette commands that run wherever the user is
+const viewAny ViewMode = -1
+
+// paletteCommand is an action the command palette can run. Views list their
+// own through a Commands method, naming the key that runs them directly.
+type paletteCommand struct {
+	title string
+	key   string   // Key that runs the command in its view, shown as a reminder
+	view  ViewMode // View shown before the command runs, viewAny to stay put
+	run   func() tea.Cmd
+}
+
+// paletteScopes names the view a command belongs to in the palette
+var paletteScopes = map[ViewMode]string{
+	viewAny:        "General",
+	ViewModeStart:  "Start",
+	ViewModeTree:   "Tree",
+	ViewModeRecord: "Record",
+	ViewModeQuery:  "Query",
+}
+
+// stay is the action of palette commands that only switch view
+func stay() tea.Cmd { return nil }
+
+// paletteHeight is how many matching commands the palette lists at once
+const paletteHeight = 10
+
+// fuzzyMatch reports whether the characters of query appear in text in order,
+// ignoring case and spaces, with a score that favours runs of consecutive
+// characters and matches at the start of words. An empty query matches all.
+func fuzzyMatch(query, text string) (int, bool) {
+	query = strings.ToLower(strings.ReplaceAll(query, " ", ""))
+	target := []rune(strings.ToLower(text))
+	score, next, run := 0, 0, 0
+	for _, want := range query {
+		for next < len(target) && target[next] != want {
+			next++
+			run = 0
+		}
+		if next == len(target) {
+			return 0, false
+		}
+		run++
+		score += run
+		if next == 0 || !unicode.IsLetter(target[next-1]) {
+			score += 3
+		}
+		next++
+	}
+	return score, true
+}
+
+// CommandPaletteView is an overlay searching the commands of every view by
+// name and running the chosen one
+type CommandPaletteView struct {
+	commands []paletteCommand
+	matches  []paletteCommand
+	input    textinput.Model
+	cursor   int
+}
+
+// NewCommandPaletteView creates a palette over commands, listed in order
+// until something is typed
+func NewCommandPaletteView(commands []paletteCommand) *CommandPaletteView {
+	ti := textinput.New()
+	ti.Placeholder = "type a command"
+	ti.CharLimit = 0
+	ti.Width = 50
+	ti.Focus()
+	p := &CommandPaletteView{commands: commands, input: ti}
+	p.filter()
+	return p
+}
+
+// filter lists the commands matching the typed text, best matches first.
+// Ties keep the order the commands were registered in.
+func (p *CommandPaletteView) filter() {
+	type scored struct {
+		command paletteCommand
+		score   int
+	}
+	var found []scored
+	for _, command := range p.commands {
+		if score, ok := fuzzyMatch(p.input.Value(), command.title); ok {
+			found = append(found, scored{command, score})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+
+	p.matches = p.matches[:0]
+	for _, match := range found {
+		p.matches = append(p.matches, match.command)
+	}
+	p.cursor = min(p.cursor, max(len(p.matches)-1, 0))
+}
+
+// Update handles a key press. It returns true when the palette should close,
+// along with the chosen command or nil if it was cancelled.
+func (p *CommandPaletteView) Update(msg tea.KeyMsg) (bool, *paletteCommand) {
+	switch msg.String() {
+	case "esc", "ctrl+x":
+		return true, nil
+	case "enter":
+		if len(p.matches) == 0 {
+			return false, nil
+		}
+		command := p.matches[p.cursor]
+		return true, &command
+	case "up", "ctrl+p":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return false, nil
+	case "down", "ctrl+n":
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+		return false, nil
+	}
+
+	p.input, _ = p.input.Update(msg)
+	p.cursor = 0
+	p.filter()
+	return false, nil
+}
+
+// View renders the palette as a bordered box
+func (p *CommandPaletteView) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	detailStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+
+	sections := []string{titleStyle.Render("Commands"), "", p.input.View(), ""}
+	if len(p.matches) == 0 {
+		sections = append(sections, detailStyle.Render("No matching commands"))
+	}
+	start := max(0, min(p.cursor-paletteHeight+1, len(p.matches)-paletteHeight))
+	for i := start; i < min(start+paletteHeight, len(p.matches)); i++ {
+		command := p.matches[i]
+		detail := paletteScopes[command.view]
+		if command.key != "" {
+			detail += " • " + command.key
+		}
+		line := fmt.Sprintf("%-36s %s", command.title, detailStyle.Render(detail))
+		if i == p.cursor {
+			sections = append(sections, editorFocusStyle.Render("▶ ")+line)
+		} else {
+			sections = append(sections, "  "+line)
+		}
+	}
+	if len(p.matches) > paletteHeight {
+		sections = append(sections, detailStyle.Render(fmt.Sprintf("%d of %d commands", min(paletteHeight, len(p.matches)), len(p.matches))))
+	}
+
+	sections = append(sections, "", helpStyle.Render("[↑↓] select • [Enter] run • [Esc] cancel"))
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("6")).
+		Padding(0, 1).
+		Render(strings.Join(sections, "\n"))
+}
+
+// paletteCommands lists the commands of the whole window and of each view
+// that is available, general ones first
+func (m *Model) paletteCommands() []paletteCommand {
+	commands := []paletteCommand{
+		{title: "Go to start view", key: "1", view: ViewModeStart, run: stay},
+		{title: "Switch connection", key: "Ctrl+K", view: viewAny, run: m.openSwitcher},
+		{title: "Server info and clock skew", key: "Ctrl+G", view: viewAny, run: m.openServerInfo},
+		{title: "Server monitor statistics", view: viewAny, run: m.openMonitor},
+		{title: "Measure the round-trip time", key: "Ctrl+T", view: viewAny, run: m.measureLatency},
+		{title: "Save the screen as SVG", key: "Ctrl+S", view: viewAny, run: m.captureView},
+		{title: "Quit", key: "q", view: viewAny, run: m.quit},
+	}
+	if m.pinned != nil {
+		commands = append(commands, paletteCommand{title: "Open the pinned entry", key: "Ctrl+B", view: viewAny, run: m.openPinned})
+	}
+	if m.client == nil {
+		commands = append(commands, paletteCommand{title: "Connect", view: viewAny, run: m.startConnect})
+	} else {
+		commands = append(commands,
+			paletteCommand{title: "Reconnect", key: "Ctrl+R", view: viewAny, run: m.reconnect},
+			paletteCommand{title: "Next connection tab", key: "Ctrl+N", view: viewAny, run: m.nextTab},
+			paletteCommand{title: "Replace with the next saved connection", key: "}", view: viewAny, run: func() tea.Cmd { return m.cycleConnection(1) }},
+			paletteCommand{title: "Replace with the previous saved connection", key: "{", view: viewAny, run: func() tea.Cmd { return m.cycleConnection(-1) }},
+			paletteCommand{title: "Close connection tab", key: "Ctrl+W", view: viewAny, run: m.closeTab},
+			paletteCommand{title: "Compare entries with another connection", view: viewAny, run: m.openCompare},
+		)
+	}
+
+	add := func(view ViewMode, viewCommands []paletteCommand) {
+		for _, command := range viewCommands {
+			command.view = view
+			commands = append(commands, command)
+		}
+	}
+	if m.tree != nil {
+		add(ViewModeTree, append([]paletteCommand{{title: "Go to tree view", key: "2", run: stay}}, m.tree.Commands()...))
+	}
+	if m.recordView != nil {
+		add(ViewModeRecord, append([]paletteCommand{{title: "Go to record view", key: "3", run: stay}}, m.recordView.Commands()...))
+	}
+	if m.queryView != nil {
+		add(ViewModeQuery, append([]paletteCommand{{title: "Go to query view", key: "4", run: stay}}, m.queryView.Commands()...))
+	}
+	return commands
+}
+
+// openPalette shows the command palette
+func (m *Model) openPalette() tea.Cmd {
+	m.palette = NewCommandPaletteView(m.paletteCommands())
+	return textinput.Blink
+}
+
+// handlePaletteKey forwards a key to the command palette and runs the chosen
+// command, first showing the view it belongs to
+func (m *Model) handlePaletteKey(msg tea.KeyMsg) tea.Cmd {
+	done, command := m.palette.Update(msg)
+	if !done {
+		return nil
+	}
+	m.palette = nil
+	if command == nil {
+		return nil
+	}
+	if command.view != viewAny {
+		m.currentView = command.view
+	}
+	return command.run()
+}
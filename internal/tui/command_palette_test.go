@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query, text string
+		match       bool
+	}{
+		{"", "Run the query", true},
+		{"run", "Run the query", true},
+		{"rtq", "Run the query", true},
+		{"RUN QUERY", "Run the query", true},
+		{"yr", "Run the query", false},
+		{"export", "Run the query", false},
+	}
+	for _, tt := range tests {
+		if _, ok := fuzzyMatch(tt.query, tt.text); ok != tt.match {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.query, tt.text, ok, tt.match)
+		}
+	}
+}
+
+func TestFuzzyMatch_PrefersWordStartsAndRuns(t *testing.T) {
+	words, _ := fuzzyMatch("sc", "Switch connection")
+	inside, _ := fuzzyMatch("sc", "Desktop icons")
+	if words <= inside {
+		t.Errorf("Expected word starts to score higher, got %d and %d", words, inside)
+	}
+
+	run, _ := fuzzyMatch("quit", "Quit")
+	scattered, _ := fuzzyMatch("quit", "Query using indexed terms")
+	if run <= scattered {
+		t.Errorf("Expected consecutive characters to score higher, got %d and %d", run, scattered)
+	}
+}
+
+func TestCommandPaletteView_FiltersAndSelects(t *testing.T) {
+	ran := ""
+	command := func(title string) paletteCommand {
+		return paletteCommand{title: title, view: viewAny, run: func() tea.Cmd {
+			ran = title
+			return nil
+		}}
+	}
+	palette := NewCommandPaletteView([]paletteCommand{command("Reconnect"), command("Run the query"), command("Quit")})
+	if len(palette.matches) != 3 {
+		t.Fatalf("Expected every command before typing, got %d", len(palette.matches))
+	}
+
+	for _, r := range "ru" {
+		palette.Update(keyRune(r))
+	}
+	if len(palette.matches) != 1 || palette.matches[0].title != "Run the query" {
+		t.Fatalf("Expected only 'Run the query' to match, got %v", palette.matches)
+	}
+
+	done, chosen := palette.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !done || chosen == nil {
+		t.Fatal("Expected enter to choose the command")
+	}
+	chosen.run()
+	if ran != "Run the query" {
+		t.Errorf("Expected the chosen command to run, got %q", ran)
+	}
+
+	if done, chosen := NewCommandPaletteView(nil).Update(tea.KeyMsg{Type: tea.KeyEsc}); !done || chosen != nil {
+		t.Error("Expected esc to close the palette without a command")
+	}
+}
+
+func TestModel_CommandPaletteDispatches(t *testing.T) {
+	zone.NewGlobal()
+	model, _ := newBaseDNModel(t, "")
+	model.currentView = ViewModeTree
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	if model.palette == nil {
+		t.Fatal("Expected ctrl+x to open the command palette")
+	}
+	if !strings.Contains(model.View(), "Commands") {
+		t.Error("Expected the palette to be drawn")
+	}
+
+	// Global keys are typed into the palette while it is open
+	for _, r := range "go to query" {
+		model.Update(keyRune(r))
+	}
+	if model.quitting || model.currentView != ViewModeTree {
+		t.Fatal("Expected typing to stay in the palette")
+	}
+	if got := model.palette.matches[0].title; got != "Go to query view" {
+		t.Fatalf("Expected 'Go to query view' first, got %q", got)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.palette != nil {
+		t.Error("Expected the palette to close after running a command")
+	}
+	if model.currentView != ViewModeQuery {
+		t.Errorf("Expected the command to switch to the query view, got %v", model.currentView)
+	}
+}
+
+func TestModel_CommandPaletteListsViewCommands(t *testing.T) {
+	model, _ := newBaseDNModel(t, "")
+	titles := map[string]paletteCommand{}
+	for _, command := range model.paletteCommands() {
+		titles[command.title] = command
+	}
+
+	for title, view := range map[string]ViewMode{
+		"Switch connection":           viewAny,
+		"Collapse all":                ViewModeTree,
+		"Run the query":               ViewModeQuery,
+		"Presence query presets":      ViewModeQuery,
+		"Connect":                     -2, // Only offered while disconnected
+		"Edit the selected attribute": -2, // Only offered once a record is shown
+	} {
+		command, ok := titles[title]
+		if view == -2 {
+			if ok {
+				t.Errorf("Expected %q not to be listed", title)
+			}
+			continue
+		}
+		if !ok || command.view != view {
+			t.Errorf("Expected %q to be listed for view %v, got %+v", title, view, command)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// ConnectionsComparedMsg carries the comparison of entries between the
+// active connection and another saved one
+type ConnectionsComparedMsg struct {
+	Results []ldap.EntryComparison
+	Err     error
+}
+
+// compareStep is where the comparison overlay is: choosing the other
+// connection, entering DNs, waiting for the servers or showing the results
+type compareStep int
+
+const (
+	compareChooseConnection compareStep = iota
+	compareEnterDNs
+	compareRunning
+	compareResults
+)
+
+// compareHeight is how many lines of results the overlay shows at once
+const compareHeight = 16
+
+// ConnectionCompare is an overlay comparing entries between the active
+// connection and another saved one, for checking that a replica or DR
+// environment matches production
+type ConnectionCompare struct {
+	config  *config.Config
+	current string // Name of the active connection
+	indices []int  // Saved connections to compare with, by most recent use
+	cursor  int
+	other   int // Saved connection chosen, -1 until one is
+	input   textinput.Model
+	step    compareStep
+	run     func(other int, dns []string) tea.Cmd
+
+	results []ldap.EntryComparison
+	err     error
+	scroll  int
+}
+
+// NewConnectionCompare creates the overlay, offering every saved connection
+// but the active one. dn fills in the DNs to compare, and run starts the
+// comparison with the chosen connection.
+func NewConnectionCompare(cfg *config.Config, current, dn string, run func(other int, dns []string) tea.Cmd) *ConnectionCompare {
+	var indices []int
+	for _, index := range cfg.RecentConnections() {
+		if index != cfg.LDAP.SelectedConnection {
+			indices = append(indices, index)
+		}
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "uid=jdoe,ou=people,dc=example,dc=com; cn=admins,ou=groups,..."
+	ti.CharLimit = 0
+	ti.Width = 60
+	ti.SetValue(dn)
+	return &ConnectionCompare{config: cfg, current: current, indices: indices, other: -1, input: ti, run: run}
+}
+
+// splitDNs splits the DNs entered, separated by semicolons
+func splitDNs(value string) []string {
+	var dns []string
+	for _, dn := range strings.Split(value, ";") {
+		if dn = strings.TrimSpace(dn); dn != "" {
+			dns = append(dns, dn)
+		}
+	}
+	return dns
+}
+
+// Update handles a key press. It returns true when the overlay should close.
+func (cc *ConnectionCompare) Update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if msg.String() == "esc" {
+		return true, nil
+	}
+
+	switch cc.step {
+	case compareChooseConnection:
+		switch msg.String() {
+		case "up", "k":
+			if cc.cursor > 0 {
+				cc.cursor--
+			}
+		case "down", "j":
+			if cc.cursor < len(cc.indices)-1 {
+				cc.cursor++
+			}
+		case "enter":
+			if len(cc.indices) == 0 {
+				return true, nil
+			}
+			cc.other = cc.indices[cc.cursor]
+			cc.step = compareEnterDNs
+			return false, cc.input.Focus()
+		}
+
+	case compareEnterDNs:
+		if msg.String() != "enter" {
+			var cmd tea.Cmd
+			cc.input, cmd = cc.input.Update(msg)
+			return false, cmd
+		}
+		dns := splitDNs(cc.input.Value())
+		if len(dns) == 0 {
+			cc.err = fmt.Errorf("enter at least one DN")
+			return false, nil
+		}
+		cc.err = nil
+		cc.step = compareRunning
+		cc.input.Blur()
+		return false, cc.run(cc.other, dns)
+
+	case compareResults:
+		switch msg.String() {
+		case "up", "k":
+			cc.scroll = max(cc.scroll-1, 0)
+		case "down", "j":
+			cc.scroll = min(cc.scroll+1, max(len(cc.resultLines())-compareHeight, 0))
+		case "enter", "n":
+			// Compare other entries with the same connection
+			cc.step = compareEnterDNs
+			cc.results, cc.err, cc.scroll = nil, nil, 0
+			return false, cc.input.Focus()
+		}
+	}
+	return false, nil
+}
+
+// SetResults shows the outcome of a comparison
+func (cc *ConnectionCompare) SetResults(msg ConnectionsComparedMsg) {
+	cc.step = compareResults
+	cc.results = msg.Results
+	cc.err = msg.Err
+	cc.scroll = 0
+}
+
+// otherName names the connection compared with
+func (cc *ConnectionCompare) otherName() string {
+	if cc.other < 0 || cc.other >= len(cc.config.LDAP.SavedConnections) {
+		return ""
+	}
+	return cc.config.LDAP.SavedConnections[cc.other].Name
+}
+
+// resultLines describes each compared entry: identical, missing on a side,
+// or its differing attributes with the values only found on each side
+func (cc *ConnectionCompare) resultLines() []string {
+	sameStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	diffStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	detailStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+
+	var lines []string
+	for _, result := range cc.results {
+		switch {
+		case result.Err != nil:
+			lines = append(lines, errorStyle.Render(iconError.String()+" "+result.DN), "    "+ldap.Explain(result.Err).Error())
+		case result.MissingLeft && result.MissingRight:
+			lines = append(lines, errorStyle.Render(iconError.String()+" "+result.DN), "    not found on either connection")
+		case result.MissingLeft || result.MissingRight:
+			missing := cc.current
+			if result.MissingRight {
+				missing = cc.otherName()
+			}
+			lines = append(lines, diffStyle.Render(iconWarning.String()+" "+result.DN), "    missing on "+missing)
+		case result.Identical():
+			lines = append(lines, sameStyle.Render(iconSuccess.String()+" "+result.DN+" matches"))
+		default:
+			summary := fmt.Sprintf("%d attributes differ", len(result.Diffs))
+			if len(result.Diffs) == 1 {
+				summary = "1 attribute differs"
+			}
+			lines = append(lines, diffStyle.Render(fmt.Sprintf("%s %s: %s", iconWarning.String(), result.DN, summary)))
+			for _, diff := range result.Diffs {
+				lines = append(lines, "    "+diff.Name)
+				if len(diff.Left) > 0 {
+					lines = append(lines, detailStyle.Render(fmt.Sprintf("      only on %s: %s", cc.current, strings.Join(diff.Left, ", "))))
+				}
+				if len(diff.Right) > 0 {
+					lines = append(lines, detailStyle.Render(fmt.Sprintf("      only on %s: %s", cc.otherName(), strings.Join(diff.Right, ", "))))
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// View renders the overlay as a bordered box
+func (cc *ConnectionCompare) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	detailStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+
+	title := "Compare " + cc.current + " with"
+	if name := cc.otherName(); name != "" {
+		title += " " + name
+	}
+	sections := []string{titleStyle.Render(title), ""}
+	help := "[↑↓] select • [Enter] choose • [Esc] cancel"
+
+	switch cc.step {
+	case compareChooseConnection:
+		if len(cc.indices) == 0 {
+			sections = append(sections, detailStyle.Render("No other saved connections to compare with"))
+		}
+		for i, index := range cc.indices {
+			conn := cc.config.LDAP.SavedConnections[index]
+			line := fmt.Sprintf("%s  %s", conn.Name, detailStyle.Render(fmt.Sprintf("%s:%d", conn.Host, conn.Port)))
+			if i == cc.cursor {
+				sections = append(sections, editorFocusStyle.Render("▶ ")+line)
+			} else {
+				sections = append(sections, "  "+line)
+			}
+		}
+	case compareEnterDNs:
+		sections = append(sections, editorLabelStyle.Render("DNs to compare, separated by ;"), cc.input.View())
+		help = "[Enter] compare • [Esc] cancel"
+	case compareRunning:
+		sections = append(sections, iconLoading.String()+" Reading the entries from both connections...")
+		help = "[Esc] close"
+	case compareResults:
+		lines := cc.resultLines()
+		if cc.err != nil {
+			lines = []string{editorErrorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, ldap.Explain(cc.err).Error()))}
+		}
+		end := min(cc.scroll+compareHeight, len(lines))
+		sections = append(sections, lines[min(cc.scroll, end):end]...)
+		if len(lines) > compareHeight {
+			sections = append(sections, detailStyle.Render(fmt.Sprintf("lines %d-%d of %d", cc.scroll+1, end, len(lines))))
+		}
+		help = "[↑↓] scroll • [Enter] compare other entries • [Esc] close"
+	}
+
+	if cc.err != nil && cc.step == compareEnterDNs {
+		sections = append(sections, "", editorErrorStyle.Render(cc.err.Error()))
+	}
+	sections = append(sections, "", helpStyle.Render(help))
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("6")).
+		Padding(0, 1).
+		Render(strings.Join(sections, "\n"))
+}
+
+// openCompare shows the comparison overlay, filled in with the DN of the
+// record being viewed
+func (m *Model) openCompare() tea.Cmd {
+	if m.client == nil {
+		return SendError(fmt.Errorf("connect first to compare entries with another connection"))
+	}
+	if len(m.startView.config.LDAP.SavedConnections) < 2 {
+		m.notify(ToastInfo, "Save another connection to compare entries with")
+		return nil
+	}
+	dn := ""
+	if m.recordView.entry != nil {
+		dn = m.recordView.entry.DN
+	}
+	m.compare = NewConnectionCompare(m.startView.config, m.connection.ConnectionName, dn, m.compareEntries)
+	return nil
+}
+
+// compareEntries connects to another saved connection, reads the DNs from
+// it and from the active connection and compares them, then disconnects
+func (m *Model) compareEntries(other int, dns []string) tea.Cmd {
+	conn := m.startView.config.SavedConnectionSettings(other)
+	if problems := conn.Validate(); len(problems) > 0 {
+		return func() tea.Msg { return ConnectionsComparedMsg{Err: fmt.Errorf("%s: %s", conn.Name, problems[0])} }
+	}
+	if !conn.Complete() {
+		return func() tea.Msg {
+			return ConnectionsComparedMsg{Err: fmt.Errorf("enter the bind password of %s in the start view first", conn.Name)}
+		}
+	}
+	if m.startView.needsPlaintextConfirmation(conn) {
+		return func() tea.Msg {
+			return ConnectionsComparedMsg{Err: fmt.Errorf("%s binds over an unencrypted connection; connect to it once from the start view to accept that", conn.Name)}
+		}
+	}
+
+	client := m.client
+	ldapConfig := ClientConfig(m.startView.config, conn)
+	return func() tea.Msg {
+		otherClient, err := connectWithTimeout(ldapConfig, connectTimeout)
+		if err != nil {
+			return ConnectionsComparedMsg{Err: fmt.Errorf("failed to connect to %s: %w", conn.Name, err)}
+		}
+		defer otherClient.Close()
+		return ConnectionsComparedMsg{Results: ldap.CompareEntries(client.GetEntry, otherClient.GetEntry, dns)}
+	}
+}
+
+// handleCompareKey forwards a key to the comparison overlay
+func (m *Model) handleCompareKey(msg tea.KeyMsg) tea.Cmd {
+	done, cmd := m.compare.Update(msg)
+	if done {
+		m.compare = nil
+	}
+	return cmd
+}
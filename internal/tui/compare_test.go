@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func TestSplitDNs(t *testing.T) {
+	got := splitDNs(" uid=a,dc=example,dc=com ; ;cn=b,dc=example,dc=com")
+	want := []string{"uid=a,dc=example,dc=com", "cn=b,dc=example,dc=com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestConnectionCompare_Flow(t *testing.T) {
+	cfg := config.Default()
+	cfg.LDAP.SavedConnections = []config.SavedConnection{{Name: "Production"}, {Name: "DR"}, {Name: "Staging"}}
+	cfg.LDAP.SelectedConnection = 0
+
+	var ranWith int
+	var ranDNs []string
+	cc := NewConnectionCompare(cfg, "Production", "uid=jdoe,dc=example,dc=com", func(other int, dns []string) tea.Cmd {
+		ranWith, ranDNs = other, dns
+		return nil
+	})
+	for _, index := range cc.indices {
+		if index == 0 {
+			t.Fatal("Expected the active connection not to be offered")
+		}
+	}
+
+	// Choose DR, then add a second DN to the one filled in
+	for cc.indices[cc.cursor] != 1 {
+		cc.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	cc.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cc.step != compareEnterDNs {
+		t.Fatalf("Expected to be asked for DNs, got step %d", cc.step)
+	}
+	cc.input.SetValue(cc.input.Value() + "; cn=admins,dc=example,dc=com")
+	cc.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if ranWith != 1 || !reflect.DeepEqual(ranDNs, []string{"uid=jdoe,dc=example,dc=com", "cn=admins,dc=example,dc=com"}) {
+		t.Fatalf("Expected the comparison to run with DR and both DNs, got %d %v", ranWith, ranDNs)
+	}
+
+	cc.SetResults(ConnectionsComparedMsg{Results: []ldap.EntryComparison{
+		{DN: "uid=jdoe,dc=example,dc=com", Diffs: []ldap.AttributeDiff{{Name: "title", Left: []string{"Engineer"}, Right: []string{"Manager"}}}},
+		{DN: "cn=admins,dc=example,dc=com", MissingRight: true},
+	}})
+	view := cc.View()
+	for _, want := range []string{"Compare Production with DR", "1 attribute differs", "only on Production: Engineer", "only on DR: Manager", "missing on DR"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the results, got:\n%s", want, view)
+		}
+	}
+
+	if done, _ := cc.Update(tea.KeyMsg{Type: tea.KeyEsc}); !done {
+		t.Error("Expected esc to close the overlay")
+	}
+}
+
+func TestModel_CompareEntriesAcrossConnections(t *testing.T) {
+	zone.NewGlobal()
+	prod := ldaptest.NewServer(t, ldaptest.Entry{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"uid": {"jdoe"}, "title": {"Engineer"}}})
+	dr := ldaptest.NewServer(t, ldaptest.Entry{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"uid": {"jdoe"}, "title": {"Engineer"}}})
+
+	cfg := config.Default()
+	cfg.LDAP.SavedConnections = []config.SavedConnection{
+		{Name: "Production", Host: "127.0.0.1", Port: prod.Port(), BaseDN: "dc=example,dc=com"},
+		{Name: "DR", Host: "127.0.0.1", Port: dr.Port(), BaseDN: "dc=example,dc=com"},
+	}
+	cfg.SetActiveConnection(0)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: prod.Port(), ConnectionName: "Production"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, "")
+	model.SetSize(120, 40)
+	model.Update(ConnectMsg{Client: client, Config: cfg})
+	defer model.Close()
+	model.recordView.SetEntry(&ldap.Entry{DN: "uid=jdoe,dc=example,dc=com"})
+
+	model.openCompare()
+	if model.compare == nil {
+		t.Fatal("Expected the comparison overlay to open")
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd := model.compare.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected the comparison to start")
+	}
+	model.Update(cmd())
+
+	if len(model.compare.results) != 1 || !model.compare.results[0].Identical() {
+		t.Fatalf("Expected the entry to match on both servers, got %+v (%v)", model.compare.results, model.compare.err)
+	}
+	if !strings.Contains(model.View(), "uid=jdoe,dc=example,dc=com matches") {
+		t.Error("Expected the match to be shown")
+	}
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// Completion is the result of completing an input value
+type Completion struct {
+	Value   string   // Input with the completed text applied
+	Options []string // Candidates when the completion is ambiguous
+}
+
+// matchCandidates returns the candidates matching prefix, ignoring case. A
+// prefix containing '*' is treated as a wildcard pattern with an implied
+// trailing '*', so "*name" matches "givenName" and "displayName".
+func matchCandidates(candidates []string, prefix string) []string {
+	lowerPrefix := strings.ToLower(prefix)
+	wildcard := strings.Contains(prefix, "*")
+
+	seen := make(map[string]bool)
+	var matches []string
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		if seen[lower] {
+			continue
+		}
+
+		var ok bool
+		if wildcard {
+			ok, _ = path.Match(lowerPrefix+"*", lower)
+		} else {
+			ok = strings.HasPrefix(lower, lowerPrefix)
+		}
+		if ok {
+			seen[lower] = true
+			matches = append(matches, candidate)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return strings.ToLower(matches[i]) < strings.ToLower(matches[j])
+	})
+	return matches
+}
+
+// commonPrefix returns the longest prefix shared by all values, ignoring case.
+// The casing of the first value is kept.
+func commonPrefix(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	prefix := values[0]
+	for _, value := range values[1:] {
+		n := 0
+		for n < len(prefix) && n < len(value) && strings.EqualFold(prefix[n:n+1], value[n:n+1]) {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return prefix
+}
+
+// CompleteAttributes completes the last name in a comma or space separated
+// attribute list from the given attribute names
+func CompleteAttributes(input string, names []string) Completion {
+	start := strings.LastIndexAny(input, ", ") + 1
+	head, word := input[:start], input[start:]
+
+	matches := matchCandidates(names, word)
+	switch len(matches) {
+	case 0:
+		return Completion{Value: input}
+	case 1:
+		return Completion{Value: head + matches[0]}
+	}
+
+	// Extend to the shared prefix unless a wildcard makes it meaningless
+	completed := word
+	if !strings.Contains(word, "*") {
+		if prefix := commonPrefix(matches); len(prefix) > len(word) {
+			completed = prefix
+		}
+	}
+	return Completion{Value: head + completed, Options: matches}
+}
+
+// CompleteDN completes a DN one component at a time from known DNs. Text is
+// matched against the start of each DN and completion stops at the next RDN
+// boundary, so "ou=peo" becomes "ou=people," rather than the full DN.
+func CompleteDN(input string, dns []string) Completion {
+	matches := matchCandidates(dns, input)
+	if len(matches) == 0 || strings.Contains(input, "*") {
+		return Completion{Value: input, Options: matches}
+	}
+
+	if len(matches) == 1 && strings.EqualFold(matches[0], input) {
+		return Completion{Value: input}
+	}
+
+	// Complete through the end of the next component shared by every match
+	prefix := commonPrefix(matches)
+	if len(prefix) < len(input) {
+		prefix = input
+	}
+	if idx := strings.Index(prefix[len(input):], ","); idx >= 0 {
+		prefix = prefix[:len(input)+idx+1]
+	}
+	if len(prefix) > len(input) {
+		return Completion{Value: prefix}
+	}
+
+	return Completion{Value: input, Options: nextDNComponents(matches, input)}
+}
+
+// nextDNComponents lists the distinct next components after input in dns
+func nextDNComponents(dns []string, input string) []string {
+	seen := make(map[string]bool)
+	var components []string
+	for _, dn := range dns {
+		if len(dn) < len(input) {
+			continue
+		}
+		rest := dn[len(input):]
+		if idx := strings.Index(rest, ","); idx >= 0 {
+			rest = rest[:idx+1]
+		}
+		component := input + rest
+		if !seen[strings.ToLower(component)] {
+			seen[strings.ToLower(component)] = true
+			components = append(components, component)
+		}
+	}
+	return components
+}
@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+var testAttributeNames = []string{"cn", "commonName", "mail", "givenName", "displayName", "member", "memberOf", "MAIL"}
+
+func TestMatchCandidates(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"mem", []string{"member", "memberOf"}},
+		{"MEM", []string{"member", "memberOf"}},
+		{"ma", []string{"mail"}}, // Duplicates differing only in case are dropped
+		{"*name", []string{"commonName", "displayName", "givenName"}},
+		{"m*of", []string{"memberOf"}},
+		{"zzz", nil},
+		{"", []string{"cn", "commonName", "displayName", "givenName", "mail", "member", "memberOf"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got := matchCandidates(testAttributeNames, tt.prefix)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompleteAttributes(t *testing.T) {
+	tests := []struct {
+		input   string
+		value   string
+		options int
+	}{
+		{"mai", "mail", 0},
+		{"cn, giv", "cn, givenName", 0},
+		{"cn,mem", "cn,member", 2}, // Extended to the common prefix, choices listed
+		{"co", "commonName", 0},
+		{"*name", "*name", 3}, // Wildcards only list matches
+		{"unknown", "unknown", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := CompleteAttributes(tt.input, testAttributeNames)
+			if got.Value != tt.value || len(got.Options) != tt.options {
+				t.Errorf("Expected %q with %d options, got %q with %v", tt.value, tt.options, got.Value, got.Options)
+			}
+		})
+	}
+}
+
+func TestCompleteDN(t *testing.T) {
+	dns := []string{
+		"dc=example,dc=com",
+		"ou=people,dc=example,dc=com",
+		"ou=groups,dc=example,dc=com",
+		"uid=jdoe,ou=people,dc=example,dc=com",
+	}
+
+	tests := []struct {
+		input   string
+		value   string
+		options []string
+	}{
+		{"ou=peo", "ou=people,", nil},                        // Stops at the component boundary
+		{"ou=people,", "ou=people,dc=example,", nil},         // Next component
+		{"OU=GR", "ou=groups,", nil},                         // Case-insensitive
+		{"ou=", "ou=", []string{"ou=groups,", "ou=people,"}}, // Ambiguous, next components listed
+		{"dc=example,dc=com", "dc=example,dc=com", nil},      // Already complete
+		{"cn=", "cn=", nil},                                  // No match
+		{"*people", "*people", []string{"ou=people,dc=example,dc=com", "uid=jdoe,ou=people,dc=example,dc=com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := CompleteDN(tt.input, dns)
+			if got.Value != tt.value || strings.Join(got.Options, "|") != strings.Join(tt.options, "|") {
+				t.Errorf("Expected %q %v, got %q %v", tt.value, tt.options, got.Value, got.Options)
+			}
+		})
+	}
+}
+
+func TestQueryView_TabCompletesFields(t *testing.T) {
+	qv := NewQueryView(nil)
+	qv.SetSize(100, 40)
+	qv.SetDNSource(func() []string { return []string{"ou=people,dc=example,dc=com"} })
+	qv.SetResults([]*ldap.Entry{{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"telephoneNumber": {"1"}}}})
+	qv.inputMode = true
+
+	// Without a schema, attribute names seen in results are offered
+	qv.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	qv.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if qv.focus != queryFieldAttributes {
+		t.Fatalf("Expected attributes input focus, got %d", qv.focus)
+	}
+	for _, r := range "tele" {
+		qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	qv.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if qv.attrsInput.Value() != "telephoneNumber" {
+		t.Errorf("Expected attribute completion, got %q", qv.attrsInput.Value())
+	}
+	if attrs := qv.searchAttributes(); strings.Join(attrs, ",") != "telephoneNumber,objectClass" {
+		t.Errorf("Expected objectClass to be requested with explicit attributes, got %v", attrs)
+	}
+
+	// Base DN completes from the tree
+	qv.Update(tea.KeyMsg{Type: tea.KeyShiftTab}) // back to the filter
+	qv.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	for _, r := range "ou=p" {
+		qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	qv.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if qv.baseInput.Value() != "ou=people," {
+		t.Errorf("Expected DN completion, got %q", qv.baseInput.Value())
+	}
+	if strings.Contains(qv.textarea.Value(), "ou=p") {
+		t.Error("Expected typing to go to the base input, not the filter")
+	}
+}
+
+func TestModel_TabCompletesInQueryFields(t *testing.T) {
+	model := NewModel(nil, config.Default())
+	model.queryView = NewQueryView(nil)
+	model.currentView = ViewModeQuery
+
+	model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if model.currentView != ViewModeQuery {
+		t.Error("Expected tab to complete instead of switching views")
+	}
+
+	// From the filter, tab keeps switching views
+	model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if model.currentView == ViewModeQuery {
+		t.Error("Expected tab from the filter to switch views")
+	}
+}
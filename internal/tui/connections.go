@@ -0,0 +1,303 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+// maxConnectionTabs is how many connections can be open at once, one per Alt+digit
+const maxConnectionTabs = 9
+
+// connectionTab is an open connection with its own tree, record and query
+// views. The active tab's state lives in the Model's fields; the others are
+// parked here until they are switched to.
+type connectionTab struct {
+	name          string
+	savedIndex    int // Saved connection the tab was opened from, -1 for the unsaved default
+	client        *ldap.Client
+	connection    ldap.Config
+	sessionBaseDN bool
+	tree          *TreeView
+	recordView    *RecordView
+	queryView     *QueryView
+	currentView   ViewMode
+	latency       latencyReading
+}
+
+// tabMsg is a message produced by a connection tab's views, tagged so it
+// reaches that tab even if another one is active when it arrives
+type tabMsg struct {
+	tab *connectionTab
+	msg tea.Msg
+}
+
+// wrap tags the messages produced by cmd with tab
+func (tab *connectionTab) wrap(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil:
+			return nil
+		case tea.QuitMsg:
+			return msg
+		case tea.BatchMsg:
+			wrapped := make(tea.BatchMsg, len(msg))
+			for i, c := range msg {
+				wrapped[i] = tab.wrap(c)
+			}
+			return wrapped
+		default:
+			return tabMsg{tab: tab, msg: msg}
+		}
+	}
+}
+
+// tagCmd tags cmd with the active tab, so that its result still reaches
+// the tab if another connection is opened or shown in the meantime
+func (m *Model) tagCmd(cmd tea.Cmd) tea.Cmd {
+	if len(m.tabs) == 0 {
+		return cmd
+	}
+	return m.tabs[m.activeTab].wrap(cmd)
+}
+
+// updateTab delivers a tagged message to the tab that produced it. Messages
+// for a background tab are handled with that tab swapped in, so every view
+// sees the same messages it would if it were active.
+func (m *Model) updateTab(msg tabMsg) tea.Cmd {
+	index := m.tabIndex(msg.tab)
+	if index < 0 {
+		// The tab was closed while the command ran
+		return nil
+	}
+	if index == m.activeTab {
+		_, cmd := m.update(msg.msg)
+		return m.tagCmd(cmd)
+	}
+
+	switch msg.msg.(type) {
+	case ConnectMsg, updateCheckMsg, wizardDiscoveryMsg, wizardBaseDNMsg, ColumnRatioChangedMsg:
+		// Not specific to a connection
+		_, cmd := m.update(msg.msg)
+		return m.tagCmd(cmd)
+	}
+
+	active := m.activeTab
+	next := m.toasts.next
+	m.storeTab()
+	m.loadTab(index)
+	_, cmd := m.update(msg.msg)
+	m.storeTab()
+	m.loadTab(active)
+
+	m.toasts.prefixSince(next, fmt.Sprintf("[%s] ", msg.tab.name))
+	return msg.tab.wrap(cmd)
+}
+
+// tabIndex returns the position of tab, or -1 if it has been closed
+func (m *Model) tabIndex(tab *connectionTab) int {
+	for i, t := range m.tabs {
+		if t == tab {
+			return i
+		}
+	}
+	return -1
+}
+
+// storeTab saves the active connection's state into its tab
+func (m *Model) storeTab() {
+	if m.activeTab >= len(m.tabs) {
+		return
+	}
+	tab := m.tabs[m.activeTab]
+	tab.client = m.client
+	tab.connection = m.connection
+	tab.sessionBaseDN = m.sessionBaseDN
+	tab.tree = m.tree
+	tab.recordView = m.recordView
+	tab.queryView = m.queryView
+	tab.currentView = m.currentView
+	tab.latency = m.latency
+}
+
+// loadTab makes the tab at index the active connection
+func (m *Model) loadTab(index int) {
+	tab := m.tabs[index]
+	m.activeTab = index
+	m.client = tab.client
+	m.connection = tab.connection
+	m.sessionBaseDN = tab.sessionBaseDN
+	m.tree = tab.tree
+	m.recordView = tab.recordView
+	m.queryView = tab.queryView
+	m.currentView = tab.currentView
+	m.latency = tab.latency
+}
+
+// switchTab shows the connection tab at index
+func (m *Model) switchTab(index int) tea.Cmd {
+	if index < 0 || index >= len(m.tabs) {
+		m.notify(ToastInfo, fmt.Sprintf("No connection tab %d", index+1))
+		return nil
+	}
+	if index == m.activeTab {
+		return nil
+	}
+
+	m.storeTab()
+	m.showTab(index)
+	m.notify(ToastInfo, fmt.Sprintf("Switched to %s", m.tabs[index].name))
+	return nil
+}
+
+// showTab makes the tab at index active. The start view follows so that
+// reconnecting and editing settings apply to the shown connection.
+func (m *Model) showTab(index int) {
+	m.loadTab(index)
+	if saved := m.tabs[index].savedIndex; saved >= 0 && saved < len(m.startView.config.LDAP.SavedConnections) {
+		m.startView.config.SetActiveConnection(saved)
+		m.startView.connectionCursor = saved
+	}
+	m.SetSize(m.width, m.height)
+}
+
+// nextTab cycles to the next connection tab
+func (m *Model) nextTab() tea.Cmd {
+	if len(m.tabs) < 2 {
+		m.notify(ToastInfo, "Only one connection is open; pick another with [Ctrl+K] to open it in a new tab")
+		return nil
+	}
+	return m.switchTab((m.activeTab + 1) % len(m.tabs))
+}
+
+// cycleConnection replaces the active connection with the saved connection
+// step places along the list, wrapping around, and stays in the current
+// view. A connection open in another tab is switched to instead.
+func (m *Model) cycleConnection(step int) tea.Cmd {
+	cfg := m.startView.config
+	saved := cfg.LDAP.SavedConnections
+	if len(saved) == 0 {
+		m.notify(ToastInfo, "No saved connections to switch to")
+		return nil
+	}
+
+	current := cfg.LDAP.SelectedConnection
+	if m.client != nil && len(m.tabs) > 0 {
+		current = m.tabs[m.activeTab].savedIndex
+	}
+	next := 0
+	switch {
+	case current >= 0 && current < len(saved):
+		next = ((current+step)%len(saved) + len(saved)) % len(saved)
+	case step < 0:
+		next = len(saved) - 1
+	}
+	if m.client != nil && next == current {
+		m.notify(ToastInfo, "Only one saved connection; add another in the start view")
+		return nil
+	}
+	if open := m.findTab(next); open >= 0 && m.client != nil {
+		return m.switchTab(open)
+	}
+
+	view := m.currentView
+	keepView := func(connect *ConnectMsg) {
+		connect.KeepView = true
+		connect.View = view
+	}
+	cfg.SetActiveConnection(next)
+	m.startView.connectionCursor = next
+	m.notify(ToastInfo, fmt.Sprintf("Switching to %s...", saved[next].Name))
+	cmd := m.startConnect()
+	if prompt := m.startView.plaintextPrompt; prompt != nil {
+		prompt.connect = adjustConnect(prompt.connect, keepView)
+	}
+	return adjustConnect(cmd, keepView)
+}
+
+// closeTab disconnects the active connection tab and shows the previous one.
+// The last tab stays open.
+func (m *Model) closeTab() tea.Cmd {
+	if len(m.tabs) < 2 {
+		m.notify(ToastInfo, "Only one connection is open")
+		return nil
+	}
+
+	closed := m.tabs[m.activeTab]
+	if m.client != nil {
+		m.client.Close()
+	}
+	m.tabs = append(m.tabs[:m.activeTab], m.tabs[m.activeTab+1:]...)
+	m.showTab(max(m.activeTab-1, 0))
+	m.notify(ToastInfo, fmt.Sprintf("Closed %s", closed.name))
+	return nil
+}
+
+// Close unbinds and closes the connection of every open tab. It is safe to
+// call more than once, so the program can close whatever is left on exit.
+func (m *Model) Close() {
+	if m.client != nil {
+		m.client.Close()
+	}
+	for _, tab := range m.tabs {
+		if tab.client != nil {
+			tab.client.Close()
+		}
+	}
+}
+
+// findTab returns the open tab for the saved connection at index, or -1
+func (m *Model) findTab(savedIndex int) int {
+	for i, tab := range m.tabs {
+		if tab.savedIndex >= 0 && tab.savedIndex == savedIndex {
+			return i
+		}
+	}
+	return -1
+}
+
+// tabName names a connection for its tab: the saved connection's name, or
+// the host for the unsaved default connection
+func tabName(conn config.LDAPConnection) string {
+	if conn.Name != "" && conn.Name != "Default" {
+		return conn.Name
+	}
+	return conn.Host
+}
+
+// renderConnectionTabs renders the open connections, the active one
+// highlighted, in place of the tab bar's hint line
+func (m *Model) renderConnectionTabs() string {
+	activeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color("12")).
+		Bold(true).
+		Padding(0, 1)
+	inactiveStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("12")).
+		Padding(0, 1)
+
+	labels := []string{" Connections:"}
+	for i, tab := range m.tabs {
+		style := inactiveStyle
+		if i == m.activeTab {
+			style = activeStyle
+		}
+		labels = append(labels, zone.Mark(fmt.Sprintf("conn-tab-%d", i), style.Render(fmt.Sprintf("[Alt+%d] %s", i+1, tab.name))))
+	}
+
+	hint := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true).
+		Padding(0, 1).
+		Render("[Ctrl+N] next • [Ctrl+W] close")
+	return strings.Join(labels, " ") + hint
+}
@@ -0,0 +1,282 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+// newTwoTabModel connects to Production, then opens Staging in a second tab
+func newTwoTabModel(t *testing.T) (*Model, *ldap.Client, *ldap.Client) {
+	t.Helper()
+	cfg := config.Default()
+	cfg.LDAP.SavedConnections = []config.SavedConnection{
+		{Name: "Production", Host: "ldap.example.com", Port: 389, BaseDN: "dc=example,dc=com"},
+		{Name: "Staging", Host: "ldap.staging.example.com", Port: 389, BaseDN: "dc=staging,dc=example,dc=com"},
+	}
+	model := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, "")
+	model.SetSize(120, 30)
+
+	production := &ldap.Client{}
+	cfg.SetActiveConnection(0)
+	model.Update(ConnectMsg{Client: production, Config: cfg})
+
+	staging := &ldap.Client{}
+	cfg.SetActiveConnection(1)
+	model.Update(ConnectMsg{Client: staging, Config: cfg, NewTab: true})
+	return model, production, staging
+}
+
+func TestModel_ConnectReplacesByDefault(t *testing.T) {
+	zone.NewGlobal()
+	model, _ := newBaseDNModel(t, "")
+	first := model.client
+
+	replacement := &ldap.Client{}
+	model.Update(ConnectMsg{Client: replacement, Config: model.startView.config})
+	if len(model.tabs) != 1 || model.client != replacement {
+		t.Fatalf("Expected a plain connect to replace the connection in one tab, got %d tabs", len(model.tabs))
+	}
+	if model.tabs[0].client == first {
+		t.Error("Expected the replaced client to be dropped")
+	}
+	if strings.Contains(model.renderTabBar(), "Connections:") {
+		t.Error("Expected no connection tabs with a single connection")
+	}
+}
+
+func TestModel_NewTabKeepsOtherConnection(t *testing.T) {
+	zone.NewGlobal()
+	model, production, staging := newTwoTabModel(t)
+
+	if len(model.tabs) != 2 || model.activeTab != 1 || model.client != staging {
+		t.Fatalf("Expected Staging to open in a second, active tab, got %d tabs with tab %d active", len(model.tabs), model.activeTab)
+	}
+	first := model.tabs[0]
+	if first.client != production || first.tree == model.tree || first.recordView == model.recordView || first.queryView == model.queryView {
+		t.Error("Expected each connection to keep its own client and views")
+	}
+
+	tabBar := model.renderTabBar()
+	for _, want := range []string{"Connections:", "[Alt+1] Production", "[Alt+2] Staging"} {
+		if !strings.Contains(tabBar, want) {
+			t.Errorf("Expected the tab bar to show %q, got %q", want, tabBar)
+		}
+	}
+}
+
+func TestModel_SwitchAndCloseTabs(t *testing.T) {
+	model, production, staging := newTwoTabModel(t)
+	model.currentView = ViewModeQuery
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if model.client != production || model.currentView != ViewModeTree {
+		t.Fatalf("Expected Ctrl+N to show Production in its own view, got view %v", model.currentView)
+	}
+	if model.startView.config.LDAP.SelectedConnection != 0 {
+		t.Error("Expected the start view to follow the shown connection")
+	}
+	if model.toasts.latest() != "Switched to Production" {
+		t.Errorf("Unexpected status %q", model.toasts.latest())
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}, Alt: true})
+	if model.client != staging || model.currentView != ViewModeQuery {
+		t.Fatal("Expected Alt+2 to return to Staging and the view it was left on")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	if len(model.tabs) != 1 || model.client != production {
+		t.Fatalf("Expected Ctrl+W to close Staging and show Production, got %d tabs", len(model.tabs))
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	if len(model.tabs) != 1 || !strings.Contains(model.toasts.latest(), "Only one connection") {
+		t.Error("Expected the last connection to stay open")
+	}
+}
+
+func TestModel_BackgroundTabMessages(t *testing.T) {
+	model, _, _ := newTwoTabModel(t)
+	background := model.tabs[0]
+	activeRecord := model.recordView
+
+	// A result for the hidden Production tab is applied to its views only
+	entry := &ldap.Entry{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"uid": {"jdoe"}}}
+	model.Update(tabMsg{tab: background, msg: ShowRecordMsg{Entry: entry}})
+	if background.recordView.entry != entry || background.currentView != ViewModeRecord {
+		t.Error("Expected the background tab to show the record")
+	}
+	if model.recordView != activeRecord || activeRecord.entry != nil || model.currentView != ViewModeTree {
+		t.Error("Expected the active tab to be untouched")
+	}
+
+	model.Update(tabMsg{tab: background, msg: StatusMsg{Message: "Copied"}})
+	if model.toasts.latest() != "[Production] Copied" {
+		t.Errorf("Expected background status to name its connection, got %q", model.toasts.latest())
+	}
+
+	// Commands are tagged with the tab that started them
+	if msg, ok := model.tagCmd(SendStatus("done"))().(tabMsg); !ok || msg.tab != model.tabs[model.activeTab] {
+		t.Errorf("Expected a tagged message, got %#v", msg)
+	}
+}
+
+func TestModel_SwitcherOpensNewTab(t *testing.T) {
+	model, _, staging := newTwoTabModel(t)
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=test,dc=example,dc=com"})
+	cfg := model.startView.config
+	cfg.LDAP.SavedConnections = append(cfg.LDAP.SavedConnections, config.SavedConnection{
+		Name: "Test", Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=test,dc=example,dc=com",
+	})
+
+	// Picking a connection that is already open switches to its tab
+	model.openSwitcher()
+	model.switcher.cursor = indexOfConnection(model.switcher, 0)
+	if cmd := model.handleSwitcherKey(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || model.activeTab != 0 {
+		t.Errorf("Expected the open Production tab to be shown, got tab %d", model.activeTab)
+	}
+	if len(model.tabs) != 2 || model.tabs[1].client != staging {
+		t.Error("Expected no connection to be opened or replaced")
+	}
+
+	// Any other connection opens in a new tab once connected
+	model.openSwitcher()
+	model.switcher.cursor = indexOfConnection(model.switcher, 2)
+	cmd := model.handleSwitcherKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected a connect attempt")
+	}
+	msg, ok := cmd().(ConnectMsg)
+	if !ok || !msg.NewTab {
+		t.Fatalf("Expected the connection to open in a new tab, got %#v", msg)
+	}
+	defer msg.Client.Close()
+
+	model.Update(msg)
+	if len(model.tabs) != 3 || model.activeTab != 2 || model.tabs[2].name != "Test" {
+		t.Errorf("Expected a third tab for Test, got %d tabs", len(model.tabs))
+	}
+}
+
+// indexOfConnection returns the switcher row of the saved connection at index
+func indexOfConnection(qs *QuickSwitcher, index int) int {
+	for row, i := range qs.indices {
+		if i == index {
+			return row
+		}
+	}
+	return -1
+}
+
+func TestModel_CycleConnection(t *testing.T) {
+	zone.NewGlobal()
+	production := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	staging := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=staging,dc=example,dc=com"})
+	cfg := config.Default()
+	cfg.LDAP.SavedConnections = []config.SavedConnection{
+		{Name: "Production", Host: "127.0.0.1", Port: production.Port(), BaseDN: "dc=example,dc=com"},
+		{Name: "Staging", Host: "127.0.0.1", Port: staging.Port(), BaseDN: "dc=staging,dc=example,dc=com"},
+	}
+	model := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, "")
+	model.SetSize(120, 30)
+	defer model.Close()
+
+	// cycle presses key and applies the connection it makes
+	cycle := func(key rune) {
+		t.Helper()
+		var connect *ConnectMsg
+		_, cmd := model.Update(keyRune(key))
+		for _, msg := range runCmd(cmd) {
+			if tagged, ok := msg.(tabMsg); ok {
+				msg = tagged.msg
+			}
+			if msg, ok := msg.(ConnectMsg); ok {
+				connect = &msg
+			}
+		}
+		if connect == nil {
+			t.Fatalf("Expected %q to connect", key)
+		}
+		if !connect.KeepView {
+			t.Errorf("Expected %q to keep the current view", key)
+		}
+		model.Update(*connect)
+	}
+
+	// Not connected yet, so the press connects to the one after the selected
+	// connection, wrapping around
+	cfg.SetActiveConnection(1)
+	cycle('}')
+	first := model.client
+	if cfg.LDAP.SelectedConnection != 0 || first == nil || model.client.BaseDN() != "dc=example,dc=com" {
+		t.Fatalf("Expected Production to be connected, got connection %d", cfg.LDAP.SelectedConnection)
+	}
+
+	model.currentView = ViewModeQuery
+	model.queryView.inputMode = false
+	cycle('}')
+	if cfg.LDAP.SelectedConnection != 1 || model.client == first || model.client.BaseDN() != "dc=staging,dc=example,dc=com" {
+		t.Fatalf("Expected Staging to replace Production, got connection %d", cfg.LDAP.SelectedConnection)
+	}
+	if model.currentView != ViewModeQuery {
+		t.Errorf("Expected the query view to stay shown, got view %d", model.currentView)
+	}
+	if len(model.tabs) != 1 || model.tabs[0].savedIndex != 1 || model.tabs[0].name != "Staging" {
+		t.Errorf("Expected the one tab to hold Staging, got %d tabs", len(model.tabs))
+	}
+	if got := model.toasts.latest(); got != "Switched to Staging" {
+		t.Errorf("Expected a toast naming the new connection, got %q", got)
+	}
+
+	// Going back wraps around; the record view's entry belongs to the old
+	// server, so the tree is shown instead
+	model.currentView = ViewModeRecord
+	cycle('{')
+	if cfg.LDAP.SelectedConnection != 0 || model.client.BaseDN() != "dc=example,dc=com" {
+		t.Errorf("Expected Production again, got connection %d", cfg.LDAP.SelectedConnection)
+	}
+	if model.currentView != ViewModeTree {
+		t.Errorf("Expected the tree view after cycling from a record, got view %d", model.currentView)
+	}
+}
+
+func TestModel_CycleConnectionToOpenTab(t *testing.T) {
+	zone.NewGlobal()
+	model, production, staging := newTwoTabModel(t)
+
+	// Production is open in the other tab, so it is switched to, not reopened
+	if cmd := model.cycleConnection(1); cmd != nil {
+		t.Error("Expected no connect attempt for a connection that is already open")
+	}
+	if model.activeTab != 0 || model.client != production || model.tabs[1].client != staging {
+		t.Errorf("Expected the Production tab to be shown, got tab %d", model.activeTab)
+	}
+}
+
+func TestModel_CycleConnectionSingle(t *testing.T) {
+	zone.NewGlobal()
+	model, _ := newBaseDNModel(t, "")
+	client := model.client
+	model.startView.config.LDAP.SavedConnections = model.startView.config.LDAP.SavedConnections[:1]
+
+	// Typing in the query view isn't taken for cycling
+	model.currentView = ViewModeQuery
+	model.queryView.inputMode = true
+	model.Update(keyRune('}'))
+	if got := model.toasts.latest(); strings.Contains(got, "Only one saved connection") {
+		t.Errorf("Expected the key to go to the query, got toast %q", got)
+	}
+
+	model.currentView = ViewModeTree
+	if cmd := model.cycleConnection(1); cmd != nil || model.client != client {
+		t.Error("Expected no switch with a single saved connection")
+	}
+	if got := model.toasts.latest(); !strings.Contains(got, "Only one saved connection") {
+		t.Errorf("Unexpected toast %q", got)
+	}
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// EntryDeletedMsg is sent when an entry, or its whole subtree, has been deleted
+type EntryDeletedMsg struct {
+	DN      string
+	Subtree bool
+}
+
+// EntryDeleteErrorMsg is sent when deleting an entry fails
+type EntryDeleteErrorMsg struct {
+	DN  string
+	Err error
+}
+
+// DeleteConfirm asks the user to type the entry's RDN before deleting it.
+// Subtree deletes attach the tree delete control and remove every descendant.
+type DeleteConfirm struct {
+	client  *ldap.Client
+	dn      string
+	rdn     string
+	subtree bool
+
+	confirm  *TypeToConfirmView
+	err      error
+	deleting bool
+}
+
+// NewDeleteConfirm creates a confirmation prompt for deleting dn
+func NewDeleteConfirm(client *ldap.Client, dn string, subtree bool) *DeleteConfirm {
+	rdn, _ := splitRDN(dn)
+	d := &DeleteConfirm{
+		client:  client,
+		dn:      dn,
+		rdn:     rdn,
+		subtree: subtree,
+	}
+	d.confirm = NewTypeToConfirmView(rdn, d.delete)
+	return d
+}
+
+// splitRDN splits a DN into its first RDN and the parent DN, honouring
+// escaped commas in attribute values
+func splitRDN(dn string) (string, string) {
+	for i := 0; i < len(dn); i++ {
+		switch dn[i] {
+		case '\\':
+			i++ // Skip the escaped character
+		case ',':
+			return strings.TrimSpace(dn[:i]), strings.TrimSpace(dn[i+1:])
+		}
+	}
+	return strings.TrimSpace(dn), ""
+}
+
+// Update handles a key press. It returns true when the prompt should be closed.
+func (d *DeleteConfirm) Update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if d.deleting {
+		return false, nil
+	}
+
+	if msg.String() == "esc" {
+		return true, nil
+	}
+
+	cmd, err := d.confirm.Update(msg)
+	if err != nil {
+		d.err = err
+	}
+	return false, cmd
+}
+
+// delete starts deleting the entry once its RDN has been typed
+func (d *DeleteConfirm) delete() tea.Cmd {
+	if d.client == nil {
+		d.err = fmt.Errorf("not connected to an LDAP server")
+		return nil
+	}
+
+	client := d.client
+	dn := d.dn
+	subtree := d.subtree
+	d.deleting = true
+	d.err = nil
+
+	return func() tea.Msg {
+		var err error
+		if subtree {
+			err = client.DeleteSubtree(dn)
+		} else {
+			err = client.Delete(dn)
+		}
+		if err != nil {
+			return EntryDeleteErrorMsg{DN: dn, Err: err}
+		}
+		return EntryDeletedMsg{DN: dn, Subtree: subtree}
+	}
+}
+
+// View renders the prompt
+func (d *DeleteConfirm) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("9")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+
+	title := "Delete entry"
+	warning := "This permanently deletes the entry."
+	if d.subtree {
+		title = "Delete subtree"
+		warning = "This permanently deletes the entry and EVERY entry below it in one operation."
+	}
+
+	sections := []string{
+		titleStyle.Render(iconWarning.String() + " " + title),
+		d.dn,
+		"",
+		warning,
+		d.confirm.View(),
+	}
+
+	if d.deleting {
+		sections = append(sections, "", lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Italic(true).
+			Render(iconLoading.String()+" Deleting..."))
+	} else if d.err != nil {
+		sections = append(sections, "", editorErrorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, ldap.Explain(d.err).Error())))
+	}
+
+	sections = append(sections, "", helpStyle.Render("[Enter] delete • [Esc] cancel"))
+	return strings.Join(sections, "\n")
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func TestSplitRDN(t *testing.T) {
+	tests := []struct {
+		dn, rdn, parent string
+	}{
+		{"uid=jdoe,ou=people,dc=example,dc=com", "uid=jdoe", "ou=people,dc=example,dc=com"},
+		{`cn=Doe\, John,ou=people,dc=example`, `cn=Doe\, John`, "ou=people,dc=example"},
+		{"dc=com", "dc=com", ""},
+	}
+	for _, tt := range tests {
+		rdn, parent := splitRDN(tt.dn)
+		if rdn != tt.rdn || parent != tt.parent {
+			t.Errorf("splitRDN(%q) = %q, %q; want %q, %q", tt.dn, rdn, parent, tt.rdn, tt.parent)
+		}
+	}
+}
+
+func TestDeleteConfirm_RequiresTypedRDN(t *testing.T) {
+	d := NewDeleteConfirm(nil, "ou=people,dc=example,dc=com", true)
+	if !strings.Contains(d.View(), "EVERY entry below it") {
+		t.Error("Expected a subtree warning")
+	}
+
+	typeIntoDelete(d, "ou=peopl")
+	if _, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("Expected no delete with a mistyped RDN")
+	}
+	if d.err == nil || !strings.Contains(d.err.Error(), "type ou=people exactly") {
+		t.Errorf("Expected confirmation error, got %v", d.err)
+	}
+
+	typeIntoDelete(d, "e")
+	d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if d.err == nil || !strings.Contains(d.err.Error(), "not connected") {
+		t.Errorf("Expected a matching RDN to reach the client check, got %v", d.err)
+	}
+
+	if done, _ := d.Update(tea.KeyMsg{Type: tea.KeyEsc}); !done {
+		t.Error("Expected Esc to close the prompt")
+	}
+}
+
+func typeIntoDelete(d *DeleteConfirm, text string) {
+	for _, r := range text {
+		d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func newDeleteTreeView() *TreeView {
+	var client *ldap.Client
+	tv := NewTreeView(client)
+	tv.SetSize(80, 20)
+	tv.roots = []*ldap.TreeNode{{DN: "dc=example,dc=com", Name: "dc=example,dc=com", IsLoaded: true,
+		Children: []*ldap.TreeNode{
+			{DN: "ou=people,dc=example,dc=com", Name: "ou=people", IsLoaded: true,
+				Children: []*ldap.TreeNode{{DN: "uid=jdoe,ou=people,dc=example,dc=com", Name: "uid=jdoe", IsLoaded: true}},
+			},
+			{DN: "ou=groups,dc=example,dc=com", Name: "ou=groups"},
+		},
+	}}
+	tv.rebuildFlattenedTree()
+	return tv
+}
+
+func TestTreeView_DeleteGuards(t *testing.T) {
+	tv := newDeleteTreeView()
+	deleteKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}}
+
+	// The base DN is never deleted
+	_, cmd := tv.Update(deleteKey)
+	if tv.deleteConfirm != nil || cmd == nil {
+		t.Fatal("Expected deleting the root to be refused")
+	}
+	if msg, ok := cmd().(ErrorMsg); !ok || !strings.Contains(msg.Err.Error(), "base DN") {
+		t.Errorf("Expected base DN error, got %v", msg)
+	}
+
+	// Without the tree delete control, a node with children can't be deleted
+	tv.cursor = 1
+	_, cmd = tv.Update(deleteKey)
+	if tv.deleteConfirm != nil || cmd == nil {
+		t.Fatal("Expected non-leaf delete to be refused")
+	}
+	if msg, ok := cmd().(ErrorMsg); !ok || !errors.Is(msg.Err, ldap.ErrTreeDeleteUnsupported) {
+		t.Errorf("Expected tree delete unsupported error, got %v", msg)
+	}
+
+	// A leaf gets a plain delete prompt
+	tv.cursor = 2
+	tv.Update(deleteKey)
+	if tv.deleteConfirm == nil || tv.deleteConfirm.subtree || tv.deleteConfirm.rdn != "uid=jdoe" {
+		t.Fatalf("Expected a leaf delete prompt, got %+v", tv.deleteConfirm)
+	}
+	if !tv.IsFormActive() || !strings.Contains(tv.View(), "Type uid=jdoe to confirm") {
+		t.Error("Expected the prompt to be rendered in place of the tree")
+	}
+}
+
+func TestTreeView_EntryDeletedRemovesNode(t *testing.T) {
+	tv := newDeleteTreeView()
+	tv.cursor = 2
+	tv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	tv.deleteConfirm.deleting = true
+
+	tv.Update(EntryDeleteErrorMsg{DN: "uid=jdoe,ou=people,dc=example,dc=com", Err: errTest})
+	if tv.deleteConfirm == nil || tv.deleteConfirm.deleting || tv.deleteConfirm.err != errTest {
+		t.Fatal("Expected the error to be shown in the still-open prompt")
+	}
+
+	_, cmd := tv.Update(EntryDeletedMsg{DN: "uid=jdoe,ou=people,dc=example,dc=com"})
+	if tv.deleteConfirm != nil {
+		t.Error("Expected the prompt to close after deleting")
+	}
+	if cmd == nil {
+		t.Error("Expected a status command")
+	}
+	if len(tv.FlattenedTree) != 3 || len(tv.roots[0].Children[0].Children) != 0 {
+		t.Errorf("Expected the deleted node to be removed, got %d items", len(tv.FlattenedTree))
+	}
+	if tv.cursor >= len(tv.FlattenedTree) {
+		t.Error("Expected cursor to stay within the tree")
+	}
+}
+
+func TestTreeView_DeleteReadsChildrenOfUnexpandedNode(t *testing.T) {
+	deleteKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}}
+
+	// ou=groups hasn't been expanded, so its children are read before asking
+	tv := newDeleteTreeView()
+	tv.cursor = 3
+	groups := tv.FlattenedTree[3].Node
+	_, cmd := tv.Update(deleteKey)
+	if tv.deleteConfirm != nil || cmd == nil || tv.deleteProbe != groups {
+		t.Fatal("Expected the children to be read before the prompt opens")
+	}
+	children := []*ldap.TreeNode{{DN: "cn=admins,ou=groups,dc=example,dc=com", Name: "cn=admins"}}
+	_, cmd = tv.Update(NodeChildrenLoadedMsg{Node: groups, Children: children})
+	if tv.deleteConfirm != nil || cmd == nil {
+		t.Fatal("Expected a node with children to be refused without the tree delete control")
+	}
+	if msg, ok := cmd().(ErrorMsg); !ok || !errors.Is(msg.Err, ldap.ErrTreeDeleteUnsupported) {
+		t.Errorf("Expected tree delete unsupported error, got %v", msg)
+	}
+	if !groups.Collapsed || len(tv.FlattenedTree) != 4 {
+		t.Error("Expected the children read for the delete to stay collapsed")
+	}
+
+	// Without children it is a leaf
+	tv = newDeleteTreeView()
+	tv.cursor = 3
+	groups = tv.FlattenedTree[3].Node
+	tv.Update(deleteKey)
+	tv.Update(NodeChildrenLoadedMsg{Node: groups})
+	if tv.deleteConfirm == nil || tv.deleteConfirm.subtree || tv.deleteConfirm.rdn != "ou=groups" {
+		t.Fatalf("Expected a leaf delete prompt once no children were found, got %+v", tv.deleteConfirm)
+	}
+}
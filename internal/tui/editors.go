@@ -0,0 +1,353 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// AttributeEditor is an input widget used to edit the values of a single attribute
+type AttributeEditor interface {
+	// Update handles a key press while the editor is active
+	Update(msg tea.KeyMsg) tea.Cmd
+	// View renders the editor
+	View() string
+	// Values returns the edited values, or an error if the input is invalid
+	Values() ([]string, error)
+	// Help returns the key hints shown below the editor
+	Help() string
+}
+
+// enterHandler is implemented by editors that use Enter themselves
+// (e.g. to add a list item) rather than to save the edit
+type enterHandler interface {
+	HandlesEnter() bool
+}
+
+// editorFactory creates an editor for an attribute with its current values
+type editorFactory func(name string, values []string) AttributeEditor
+
+// editorRegistry maps attribute syntax OIDs to specialized editors.
+// Syntaxes not listed here fall back to a plain text input.
+var editorRegistry = map[string]editorFactory{
+	ldap.SyntaxBoolean:         newBoolEditor,
+	ldap.SyntaxGeneralizedTime: newTimeEditor,
+}
+
+// newAttributeEditor picks the editor for an attribute based on its schema definition.
+// Multi-valued attributes get a list editor; when no schema is available the
+// current number of values decides.
+func newAttributeEditor(name string, values []string, attrType *ldap.AttributeType) AttributeEditor {
+	multiValued := len(values) > 1
+	if attrType != nil {
+		multiValued = !attrType.SingleValue
+	}
+
+	if multiValued {
+		return newListEditor(name, values)
+	}
+
+	return newValueEditor(name, values, attrType)
+}
+
+// newValueEditor picks the editor for a single value of an attribute based on its syntax
+func newValueEditor(name string, values []string, attrType *ldap.AttributeType) AttributeEditor {
+	if attrType != nil {
+		if factory, ok := editorRegistry[attrType.Syntax]; ok {
+			return factory(name, values)
+		}
+	}
+
+	return newTextEditor(name, values)
+}
+
+var (
+	editorLabelStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("14")).
+				Bold(true)
+
+	editorFocusStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("0")).
+				Background(lipgloss.Color("11")).
+				Bold(true)
+
+	editorErrorStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("9"))
+)
+
+// textEditor edits a single value with a plain text input
+type textEditor struct {
+	name  string
+	input textinput.Model
+	help  string
+}
+
+func newTextEditor(name string, values []string) AttributeEditor {
+	ti := textinput.New()
+	ti.CharLimit = 0
+	ti.Width = 50
+	if len(values) > 0 {
+		ti.SetValue(values[0])
+	}
+	ti.Focus()
+
+	return &textEditor{
+		name:  name,
+		input: ti,
+		help:  "[Enter] save • [Esc] cancel • an empty value removes the attribute",
+	}
+}
+
+func (e *textEditor) Update(msg tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return cmd
+}
+
+func (e *textEditor) View() string {
+	return editorLabelStyle.Render(e.name+":") + " " + e.input.View()
+}
+
+func (e *textEditor) Values() ([]string, error) {
+	value := e.input.Value()
+	if value == "" {
+		return nil, nil
+	}
+	return []string{value}, nil
+}
+
+func (e *textEditor) Help() string {
+	return e.help
+}
+
+// boolEditor edits an LDAP Boolean as a TRUE/FALSE toggle
+type boolEditor struct {
+	name  string
+	value bool
+}
+
+func newBoolEditor(name string, values []string) AttributeEditor {
+	value := len(values) > 0 && strings.EqualFold(values[0], "TRUE")
+	return &boolEditor{name: name, value: value}
+}
+
+func (e *boolEditor) Update(msg tea.KeyMsg) tea.Cmd {
+	switch strings.ToLower(msg.String()) {
+	case " ", "left", "right", "h", "l":
+		e.value = !e.value
+	case "y", "t", "1":
+		e.value = true
+	case "n", "f", "0":
+		e.value = false
+	}
+	return nil
+}
+
+func (e *boolEditor) View() string {
+	trueLabel, falseLabel := " TRUE ", " FALSE "
+	if e.value {
+		trueLabel = editorFocusStyle.Render(trueLabel)
+	} else {
+		falseLabel = editorFocusStyle.Render(falseLabel)
+	}
+	return editorLabelStyle.Render(e.name+":") + " " + trueLabel + " " + falseLabel
+}
+
+func (e *boolEditor) Values() ([]string, error) {
+	if e.value {
+		return []string{"TRUE"}, nil
+	}
+	return []string{"FALSE"}, nil
+}
+
+func (e *boolEditor) Help() string {
+	return "[Space] toggle • [Y/N] set • [Enter] save • [Esc] cancel"
+}
+
+// timeField identifies the component of a timestamp being adjusted
+type timeField int
+
+const (
+	timeFieldYear timeField = iota
+	timeFieldMonth
+	timeFieldDay
+	timeFieldHour
+	timeFieldMinute
+	timeFieldSecond
+	timeFieldCount
+)
+
+// timeEditor edits a GeneralizedTime value with a field-by-field date picker
+type timeEditor struct {
+	name  string
+	value time.Time
+	field timeField
+	err   error
+}
+
+func newTimeEditor(name string, values []string) AttributeEditor {
+	e := &timeEditor{name: name, value: time.Now().UTC().Truncate(time.Second)}
+	if len(values) > 0 {
+		if t, err := ldap.ParseGeneralizedTime(values[0]); err == nil {
+			e.value = t.UTC()
+		} else {
+			e.err = err
+		}
+	}
+	return e
+}
+
+func (e *timeEditor) Update(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "left", "h":
+		if e.field > 0 {
+			e.field--
+		}
+	case "right", "l":
+		if e.field < timeFieldCount-1 {
+			e.field++
+		}
+	case "up", "k", "+":
+		e.adjust(1)
+	case "down", "j", "-":
+		e.adjust(-1)
+	case "t":
+		e.value = time.Now().UTC().Truncate(time.Second)
+		e.err = nil
+	}
+	return nil
+}
+
+// adjust moves the focused component of the timestamp by delta
+func (e *timeEditor) adjust(delta int) {
+	switch e.field {
+	case timeFieldYear:
+		e.value = e.value.AddDate(delta, 0, 0)
+	case timeFieldMonth:
+		e.value = e.value.AddDate(0, delta, 0)
+	case timeFieldDay:
+		e.value = e.value.AddDate(0, 0, delta)
+	case timeFieldHour:
+		e.value = e.value.Add(time.Duration(delta) * time.Hour)
+	case timeFieldMinute:
+		e.value = e.value.Add(time.Duration(delta) * time.Minute)
+	case timeFieldSecond:
+		e.value = e.value.Add(time.Duration(delta) * time.Second)
+	}
+	e.err = nil
+}
+
+func (e *timeEditor) View() string {
+	parts := []string{
+		fmt.Sprintf("%04d", e.value.Year()),
+		fmt.Sprintf("%02d", int(e.value.Month())),
+		fmt.Sprintf("%02d", e.value.Day()),
+		fmt.Sprintf("%02d", e.value.Hour()),
+		fmt.Sprintf("%02d", e.value.Minute()),
+		fmt.Sprintf("%02d", e.value.Second()),
+	}
+	parts[e.field] = editorFocusStyle.Render(parts[e.field])
+
+	picker := parts[0] + "-" + parts[1] + "-" + parts[2] + " " +
+		parts[3] + ":" + parts[4] + ":" + parts[5] + " UTC"
+
+	view := editorLabelStyle.Render(e.name+":") + " " + picker
+	if e.err != nil {
+		view += "\n" + editorErrorStyle.Render(fmt.Sprintf("Current value could not be parsed: %v", e.err))
+	}
+	return view
+}
+
+func (e *timeEditor) Values() ([]string, error) {
+	return []string{ldap.FormatGeneralizedTime(e.value)}, nil
+}
+
+func (e *timeEditor) Help() string {
+	return "[←→] select field • [↑↓] adjust • [T] now • [Enter] save • [Esc] cancel"
+}
+
+// listEditor edits a multi-valued attribute as a list of values
+type listEditor struct {
+	name   string
+	values []string
+	cursor int
+	input  textinput.Model
+}
+
+func newListEditor(name string, values []string) AttributeEditor {
+	ti := textinput.New()
+	ti.Placeholder = "New value"
+	ti.CharLimit = 0
+	ti.Width = 50
+	ti.Focus()
+
+	return &listEditor{
+		name:   name,
+		values: append([]string(nil), values...),
+		input:  ti,
+	}
+}
+
+func (e *listEditor) HandlesEnter() bool {
+	return true
+}
+
+func (e *listEditor) Update(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up":
+		if e.cursor > 0 {
+			e.cursor--
+		}
+		return nil
+	case "down":
+		if e.cursor < len(e.values)-1 {
+			e.cursor++
+		}
+		return nil
+	case "enter":
+		if value := e.input.Value(); value != "" {
+			e.values = append(e.values, value)
+			e.cursor = len(e.values) - 1
+			e.input.SetValue("")
+		}
+		return nil
+	case "ctrl+d", "delete":
+		if len(e.values) > 0 {
+			e.values = append(e.values[:e.cursor], e.values[e.cursor+1:]...)
+			if e.cursor >= len(e.values) && e.cursor > 0 {
+				e.cursor--
+			}
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return cmd
+}
+
+func (e *listEditor) View() string {
+	lines := []string{editorLabelStyle.Render(fmt.Sprintf("%s (%d values):", e.name, len(e.values)))}
+	for i, value := range e.values {
+		if i == e.cursor {
+			lines = append(lines, editorFocusStyle.Render("▶ "+value))
+		} else {
+			lines = append(lines, "  "+value)
+		}
+	}
+	lines = append(lines, "", "Add: "+e.input.View())
+	return strings.Join(lines, "\n")
+}
+
+func (e *listEditor) Values() ([]string, error) {
+	return append([]string(nil), e.values...), nil
+}
+
+func (e *listEditor) Help() string {
+	return "[↑↓] select • [Enter] add typed value • [Ctrl+D] remove selected • [Ctrl+S] save • [Esc] cancel"
+}
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func TestNewAttributeEditor_SelectsBySyntax(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		attrType *ldap.AttributeType
+		expected string
+	}{
+		{"boolean syntax", []string{"TRUE"}, &ldap.AttributeType{Syntax: ldap.SyntaxBoolean, SingleValue: true}, "*tui.boolEditor"},
+		{"generalized time syntax", []string{"20240101000000Z"}, &ldap.AttributeType{Syntax: ldap.SyntaxGeneralizedTime, SingleValue: true}, "*tui.timeEditor"},
+		{"multi-valued attribute", []string{"a"}, &ldap.AttributeType{Syntax: ldap.SyntaxDirectoryString}, "*tui.listEditor"},
+		{"unknown syntax", []string{"x"}, &ldap.AttributeType{Syntax: "1.2.3.4", SingleValue: true}, "*tui.textEditor"},
+		{"no schema single value", []string{"x"}, nil, "*tui.textEditor"},
+		{"no schema multiple values", []string{"x", "y"}, nil, "*tui.listEditor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			editor := newAttributeEditor("attr", tt.values, tt.attrType)
+			if got := typeName(editor); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case *boolEditor:
+		return "*tui.boolEditor"
+	case *timeEditor:
+		return "*tui.timeEditor"
+	case *listEditor:
+		return "*tui.listEditor"
+	case *textEditor:
+		return "*tui.textEditor"
+	}
+	return "unknown"
+}
+
+func TestBoolEditor_Toggle(t *testing.T) {
+	editor := newBoolEditor("enabled", []string{"FALSE"})
+
+	editor.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	values, _ := editor.Values()
+	if len(values) != 1 || values[0] != "TRUE" {
+		t.Errorf("Expected TRUE after toggle, got %v", values)
+	}
+
+	editor.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	values, _ = editor.Values()
+	if values[0] != "FALSE" {
+		t.Errorf("Expected FALSE after 'n', got %v", values)
+	}
+}
+
+func TestTimeEditor_AdjustFields(t *testing.T) {
+	editor := newTimeEditor("expires", []string{"20240131120000Z"})
+
+	// Year is focused first
+	editor.Update(tea.KeyMsg{Type: tea.KeyUp})
+	values, _ := editor.Values()
+	if values[0] != "20250131120000Z" {
+		t.Errorf("Expected year increment, got %s", values[0])
+	}
+
+	// Move to the day field and roll over into February
+	editor.Update(tea.KeyMsg{Type: tea.KeyRight})
+	editor.Update(tea.KeyMsg{Type: tea.KeyRight})
+	editor.Update(tea.KeyMsg{Type: tea.KeyUp})
+	values, _ = editor.Values()
+	if values[0] != "20250201120000Z" {
+		t.Errorf("Expected day increment, got %s", values[0])
+	}
+}
+
+func TestTimeEditor_UnparseableValue(t *testing.T) {
+	editor := newTimeEditor("expires", []string{"garbage"})
+	if !strings.Contains(editor.View(), "could not be parsed") {
+		t.Error("Expected parse error to be shown for an invalid existing value")
+	}
+}
+
+func TestListEditor_AddAndRemove(t *testing.T) {
+	editor := newListEditor("member", []string{"cn=a", "cn=b"}).(*listEditor)
+
+	for _, r := range "cn=c" {
+		editor.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	editor.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	values, _ := editor.Values()
+	if len(values) != 3 || values[2] != "cn=c" {
+		t.Fatalf("Expected cn=c to be added, got %v", values)
+	}
+
+	// Cursor is on the new value; move up and remove cn=b
+	editor.Update(tea.KeyMsg{Type: tea.KeyUp})
+	editor.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+
+	values, _ = editor.Values()
+	if len(values) != 2 || values[0] != "cn=a" || values[1] != "cn=c" {
+		t.Errorf("Expected [cn=a cn=c], got %v", values)
+	}
+}
+
+func TestTextEditor_EmptyRemovesAttribute(t *testing.T) {
+	editor := newTextEditor("description", nil)
+	values, err := editor.Values()
+	if err != nil || values != nil {
+		t.Errorf("Expected nil values for empty input, got %v (err %v)", values, err)
+	}
+}
+
+func TestRecordView_EditFlow(t *testing.T) {
+	rv := NewRecordView()
+	rv.SetSize(80, 20)
+	rv.SetEntry(&ldap.Entry{
+		DN:         "cn=test,dc=example,dc=com",
+		Attributes: map[string][]string{"cn": {"test"}},
+	})
+
+	rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if !rv.IsEditing() {
+		t.Fatal("Expected record view to enter edit mode on 'e'")
+	}
+	if !strings.Contains(rv.View(), "Editing cn") {
+		t.Error("Expected editor to be rendered")
+	}
+
+	// Saving without a client reports an error and keeps the editor open
+	rv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !rv.IsEditing() || rv.editError == nil {
+		t.Error("Expected save without client to keep editor open with an error")
+	}
+
+	rv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if rv.IsEditing() {
+		t.Error("Expected Esc to cancel editing")
+	}
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// identifyingAttributes are tried in order when building a filter that
+// matches one entry. Account names come first since they make readable
+// filters; the server-assigned IDs are unique but opaque.
+var identifyingAttributes = []string{
+	"sAMAccountName",
+	"uid",
+	"userPrincipalName",
+	"entryUUID",
+	"objectGUID",
+	"nsUniqueId",
+	"ipaUniqueID",
+}
+
+// matchingFilter builds a filter for entry from its primary objectClass and
+// an identifying attribute. Without one it falls back to the entry's RDN,
+// which is only unique among its siblings; unique reports which was used.
+func matchingFilter(entry *ldap.Entry) (filter string, unique bool) {
+	var parts []string
+	if class := primaryObjectClass(entry); class != "" {
+		parts = append(parts, equalityFilter("objectClass", class))
+	}
+
+	for _, name := range identifyingAttributes {
+		if values, _ := entry.Lookup(name); len(values) > 0 && values[0] != "" {
+			parts = append(parts, equalityFilter(name, values[0]))
+			unique = true
+			break
+		}
+	}
+	if !unique {
+		if dn, err := goldap.ParseDN(entry.DN); err == nil && len(dn.RDNs) > 0 {
+			for _, attribute := range dn.RDNs[0].Attributes {
+				parts = append(parts, equalityFilter(attribute.Type, attribute.Value))
+			}
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return "(objectClass=*)", false
+	case 1:
+		return parts[0], unique
+	default:
+		return "(&" + strings.Join(parts, "") + ")", unique
+	}
+}
+
+// primaryObjectClass returns the entry's objectClass value that identifies
+// its kind, such as person or group, in the case the server returned it
+func primaryObjectClass(entry *ldap.Entry) string {
+	classes := make(map[string]string)
+	objectClasses, _ := entry.Lookup("objectClass")
+	for _, value := range objectClasses {
+		classes[strings.ToLower(strings.TrimSpace(value))] = strings.TrimSpace(value)
+	}
+	for _, info := range entryKinds {
+		for _, class := range info.ObjectClasses {
+			if value, ok := classes[class]; ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// equalityFilter returns (name=value) with the value escaped
+func equalityFilter(name, value string) string {
+	return "(" + name + "=" + goldap.EscapeFilter(value) + ")"
+}
+
+// copyMatchingFilter copies a filter matching the record to the clipboard
+func (rv *RecordView) copyMatchingFilter() tea.Cmd {
+	if rv.entry == nil {
+		return SendError(fmt.Errorf("no record selected"))
+	}
+	filter, unique := matchingFilter(rv.entry)
+	if err := clipboard.WriteAll(filter); err != nil {
+		return SendError(fmt.Errorf("failed to copy to clipboard: %w", err))
+	}
+	return SendSuccess("Copied filter " + filter + filterUniquenessNote(unique))
+}
+
+// queryMatchingFilter opens the query view with a filter matching the record
+func (rv *RecordView) queryMatchingFilter() tea.Cmd {
+	if rv.entry == nil {
+		return SendError(fmt.Errorf("no record selected"))
+	}
+	filter, unique := matchingFilter(rv.entry)
+	return LoadQuery(filter, filterUniquenessNote(unique))
+}
+
+// filterUniquenessNote warns when a filter was built from the RDN alone
+func filterUniquenessNote(unique bool) string {
+	if unique {
+		return ""
+	}
+	return " (built from the RDN, so it may match entries elsewhere in the tree)"
+}
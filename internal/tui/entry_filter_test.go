@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func TestMatchingFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		entry  *ldap.Entry
+		want   string
+		unique bool
+	}{
+		{
+			name: "active directory user",
+			entry: &ldap.Entry{DN: "CN=Alice Smith,OU=Users,DC=corp,DC=example,DC=com", Attributes: map[string][]string{
+				"objectClass":       {"top", "person", "organizationalPerson", "user"},
+				"sAMAccountName":    {"alice"},
+				"userPrincipalName": {"alice@corp.example.com"},
+			}},
+			want:   "(&(objectClass=person)(sAMAccountName=alice))",
+			unique: true,
+		},
+		{
+			name: "active directory computer",
+			entry: &ldap.Entry{DN: "CN=WS01,OU=Computers,DC=corp,DC=example,DC=com", Attributes: map[string][]string{
+				"objectClass":    {"top", "person", "organizationalPerson", "user", "computer"},
+				"sAMAccountName": {"WS01$"},
+			}},
+			want:   "(&(objectClass=computer)(sAMAccountName=WS01$))",
+			unique: true,
+		},
+		{
+			name: "openldap user, names in other case",
+			entry: &ldap.Entry{DN: "uid=jdoe,ou=people,dc=example,dc=com", Attributes: map[string][]string{
+				"objectclass": {"inetOrgPerson", "posixAccount"},
+				"UID":         {"jdoe"},
+				"entryUUID":   {"6c1a2f3e-0000-4000-8000-000000000001"},
+			}},
+			want:   "(&(objectClass=inetOrgPerson)(uid=jdoe))",
+			unique: true,
+		},
+		{
+			name: "server-assigned ID only",
+			entry: &ldap.Entry{DN: "cn=printer,dc=example,dc=com", Attributes: map[string][]string{
+				"entryUUID": {"6c1a2f3e-0000-4000-8000-000000000002"},
+			}},
+			want:   "(entryUUID=6c1a2f3e-0000-4000-8000-000000000002)",
+			unique: true,
+		},
+		{
+			name: "group falls back to the RDN",
+			entry: &ldap.Entry{DN: "cn=admins,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+				"objectClass": {"top", "groupOfNames"},
+				"member":      {"uid=jdoe,ou=people,dc=example,dc=com"},
+			}},
+			want: "(&(objectClass=groupOfNames)(cn=admins))",
+		},
+		{
+			name: "special characters are escaped",
+			entry: &ldap.Entry{DN: "uid=a(b)*,dc=example,dc=com", Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"a(b)*"},
+			}},
+			want:   `(&(objectClass=person)(uid=a\28b\29\2a))`,
+			unique: true,
+		},
+		{
+			name:  "nothing to go on",
+			entry: &ldap.Entry{DN: "", Attributes: map[string][]string{}},
+			want:  "(objectClass=*)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unique := matchingFilter(tt.entry)
+			if got != tt.want || unique != tt.unique {
+				t.Errorf("matchingFilter() = %q, %v; want %q, %v", got, unique, tt.want, tt.unique)
+			}
+		})
+	}
+}
+
+func TestModel_QueryForRecord(t *testing.T) {
+	model, _ := newBaseDNModel(t, "")
+	model.recordView.SetEntry(&ldap.Entry{DN: "cn=admins,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+		"objectClass": {"groupOfNames"},
+	}})
+	model.currentView = ViewModeRecord
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	model.Update(cmd())
+	if model.currentView != ViewModeQuery || !model.queryView.IsInputMode() {
+		t.Fatal("Expected Shift+F to open the query view ready to run")
+	}
+	if got := model.queryView.textarea.Value(); got != "(&(objectClass=groupOfNames)(cn=admins))" {
+		t.Errorf("Unexpected filter %q", got)
+	}
+	if !strings.Contains(model.toasts.latest(), "may match entries elsewhere") {
+		t.Errorf("Expected an RDN filter to be flagged as possibly not unique, got %q", model.toasts.latest())
+	}
+}
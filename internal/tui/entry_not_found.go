@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// EntryNotFoundMsg is sent when an entry opened by its DN no longer exists,
+// usually because it was moved, renamed or deleted since it was listed
+type EntryNotFoundMsg struct {
+	DN string
+}
+
+// readEntryError reports a failure to read the entry at dn, offering the
+// recovery prompt when the entry doesn't exist instead of a bare error
+func readEntryError(dn string, err error) tea.Msg {
+	if ldap.IsEntryNotFound(err) {
+		return EntryNotFoundMsg{DN: dn}
+	}
+	return ErrorMsg{Err: err}
+}
+
+// EntryNotFound is an overlay explaining that an entry has gone, offering to
+// search for it elsewhere by its RDN and to drop it from the tree
+type EntryNotFound struct {
+	dn     string
+	filter string // Finds the entry if it was moved under another parent
+	inTree bool   // The stale DN is still listed in the tree
+}
+
+// NewEntryNotFound creates the overlay for the missing entry at dn
+func NewEntryNotFound(dn string, inTree bool) *EntryNotFound {
+	filter, _ := matchingFilter(&ldap.Entry{DN: dn})
+	return &EntryNotFound{dn: dn, filter: filter, inTree: inTree}
+}
+
+// Update handles a key press, returning true when the overlay should close
+// along with the command to carry out the chosen action
+func (e *EntryNotFound) Update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		return true, nil
+	case "enter", "f", "F":
+		return true, LoadQuery(e.filter, " to look for the entry under another parent")
+	case "r", "R":
+		if !e.inTree {
+			return false, nil
+		}
+		return true, func() tea.Msg { return StaleEntryRemovedMsg{DN: e.dn} }
+	}
+	return false, nil
+}
+
+// View renders the overlay as a bordered box
+func (e *EntryNotFound) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("11")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+
+	sections := []string{
+		titleStyle.Render(iconWarning.String() + " Entry not found"),
+		"",
+		e.dn,
+		"",
+		"It may have been moved, renamed or deleted since it was listed.",
+		"",
+		fmt.Sprintf("[Enter/F] search for %s", editorFocusStyle.Render(e.filter)),
+	}
+	if e.inTree {
+		sections = append(sections, "[R] remove it from the tree")
+	}
+	sections = append(sections, helpStyle.Render("[Esc] close"))
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("11")).
+		Padding(0, 1).
+		Render(strings.Join(sections, "\n"))
+}
+
+// StaleEntryRemovedMsg asks the tree to drop a DN that no longer exists
+type StaleEntryRemovedMsg struct {
+	DN string
+}
+
+// openEntryNotFound shows the recovery overlay for a DN that couldn't be read
+func (m *Model) openEntryNotFound(dn string) {
+	inTree := m.tree != nil && slices.Contains(m.tree.LoadedDNs(), dn)
+	m.entryNotFound = NewEntryNotFound(dn, inTree)
+}
+
+// handleEntryNotFoundKey forwards a key to the entry not found overlay
+func (m *Model) handleEntryNotFoundKey(msg tea.KeyMsg) tea.Cmd {
+	done, cmd := m.entryNotFound.Update(msg)
+	if done {
+		m.entryNotFound = nil
+	}
+	return cmd
+}
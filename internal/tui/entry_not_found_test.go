@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+// newStaleEntryModel returns a model whose tree lists uid=jdoe, which the
+// server no longer has, under the cursor
+func newStaleEntryModel(t *testing.T) *Model {
+	t.Helper()
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+
+	cfg := config.Default()
+	model := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, "")
+	model.SetSize(120, 30)
+	model.Update(ConnectMsg{Client: client, Config: cfg})
+	model.tree.roots = []*ldap.TreeNode{{DN: "dc=example,dc=com", Name: "dc=example,dc=com", IsLoaded: true,
+		Children: []*ldap.TreeNode{{DN: "uid=jdoe,dc=example,dc=com", Name: "uid=jdoe", IsLoaded: true}},
+	}}
+	model.tree.rebuildFlattenedTree()
+	model.tree.cursor = 1
+	return model
+}
+
+func TestTreeView_OpenMissingEntryOffersRecovery(t *testing.T) {
+	zone.NewGlobal()
+	model := newStaleEntryModel(t)
+
+	msg := model.tree.viewRecord()()
+	notFound, ok := msg.(EntryNotFoundMsg)
+	if !ok {
+		t.Fatalf("Expected a missing entry to produce EntryNotFoundMsg, got %#v", msg)
+	}
+	if notFound.DN != "uid=jdoe,dc=example,dc=com" {
+		t.Errorf("Expected the stale DN, got %q", notFound.DN)
+	}
+
+	model.Update(notFound)
+	if model.entryNotFound == nil {
+		t.Fatal("Expected the entry not found overlay")
+	}
+	view := model.View()
+	for _, want := range []string{"Entry not found", "uid=jdoe,dc=example,dc=com", "(uid=jdoe)", "[R] remove it from the tree"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected the overlay to show %q, got:\n%s", want, view)
+		}
+	}
+}
+
+func TestEntryNotFound_RemoveFromTree(t *testing.T) {
+	model := newStaleEntryModel(t)
+	model.Update(EntryNotFoundMsg{DN: "uid=jdoe,dc=example,dc=com"})
+
+	_, cmd := model.Update(keyRune('r'))
+	if model.entryNotFound != nil || cmd == nil {
+		t.Fatal("Expected R to close the overlay and remove the entry")
+	}
+	for _, msg := range runCmd(cmd) {
+		model.Update(msg)
+	}
+	if len(model.tree.roots[0].Children) != 0 {
+		t.Error("Expected the stale entry to be removed from the tree")
+	}
+}
+
+func TestEntryNotFound_SearchByRDN(t *testing.T) {
+	prompt := NewEntryNotFound("uid=jdoe,ou=people,dc=example,dc=com", false)
+
+	_, cmd := prompt.Update(keyRune('r'))
+	if cmd != nil {
+		t.Error("Expected R to do nothing when the entry isn't in the tree")
+	}
+	if strings.Contains(prompt.View(), "remove it from the tree") {
+		t.Error("Expected no remove option for an entry outside the tree")
+	}
+
+	done, cmd := prompt.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !done || cmd == nil {
+		t.Fatal("Expected Enter to close the overlay and start a search")
+	}
+	msg, ok := cmd().(LoadQueryMsg)
+	if !ok || msg.Filter != "(uid=jdoe)" {
+		t.Errorf("Expected a query for the RDN, got %#v", msg)
+	}
+}
@@ -0,0 +1,302 @@
+package tui
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/config"
+)
+
+// Geometry of a captured view: every terminal cell is drawn the same size so
+// the tables and borders line up as they do on screen
+const (
+	svgFontSize   = 14
+	svgCellWidth  = 8.4
+	svgLineHeight = 18
+	svgPadding    = 12
+)
+
+// Colours for text and background the terminal would choose itself
+const (
+	svgDefaultForeground = "#d0d0d0"
+	svgDefaultBackground = "#1c1c1c"
+)
+
+// svgBasicColors are the xterm values of the 16 basic ANSI colours
+var svgBasicColors = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// cellStyle is the SGR state a run of text is drawn with. Empty colours use
+// the terminal defaults.
+type cellStyle struct {
+	fg, bg    string
+	bold      bool
+	faint     bool
+	italic    bool
+	underline bool
+	strike    bool
+	reverse   bool
+}
+
+// colors returns the foreground and background the style is drawn in
+func (s cellStyle) colors() (fg, bg string) {
+	fg, bg = s.fg, s.bg
+	if s.reverse {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = svgDefaultBackground
+		}
+		if bg == "" {
+			bg = svgDefaultForeground
+		}
+	}
+	if fg == "" {
+		fg = svgDefaultForeground
+	}
+	return fg, bg
+}
+
+// ansi256Color returns the hex value of a colour of the 256-colour palette
+func ansi256Color(index int) string {
+	switch {
+	case index < 0 || index > 255:
+		return ""
+	case index < 16:
+		return svgBasicColors[index]
+	case index < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		index -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[index/36], levels[index/6%6], levels[index%6])
+	default:
+		level := 8 + (index-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", level, level, level)
+	}
+}
+
+// applySGR updates style with the parameters of a Select Graphic Rendition
+// sequence, such as "1;38;5;12" from ESC[1;38;5;12m
+func applySGR(style cellStyle, params string) cellStyle {
+	var codes []int
+	for _, param := range strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' }) {
+		code, err := strconv.Atoi(param)
+		if err != nil {
+			code = 0
+		}
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return cellStyle{}
+	}
+
+	// extended reads a 256-colour or RGB colour starting at codes[i]
+	extended := func(i int) (string, int) {
+		switch {
+		case i+1 < len(codes) && codes[i] == 5:
+			return ansi256Color(codes[i+1]), i + 1
+		case i+3 < len(codes) && codes[i] == 2:
+			return fmt.Sprintf("#%02x%02x%02x", codes[i+1]&0xff, codes[i+2]&0xff, codes[i+3]&0xff), i + 3
+		}
+		return "", len(codes)
+	}
+
+	for i := 0; i < len(codes); i++ {
+		switch code := codes[i]; {
+		case code == 0:
+			style = cellStyle{}
+		case code == 1:
+			style.bold = true
+		case code == 2:
+			style.faint = true
+		case code == 3:
+			style.italic = true
+		case code == 4:
+			style.underline = true
+		case code == 7:
+			style.reverse = true
+		case code == 9:
+			style.strike = true
+		case code == 22:
+			style.bold, style.faint = false, false
+		case code == 23:
+			style.italic = false
+		case code == 24:
+			style.underline = false
+		case code == 27:
+			style.reverse = false
+		case code == 29:
+			style.strike = false
+		case code >= 30 && code <= 37:
+			style.fg = svgBasicColors[code-30]
+		case code == 38:
+			style.fg, i = extended(i + 1)
+		case code == 39:
+			style.fg = ""
+		case code >= 40 && code <= 47:
+			style.bg = svgBasicColors[code-40]
+		case code == 48:
+			style.bg, i = extended(i + 1)
+		case code == 49:
+			style.bg = ""
+		case code >= 90 && code <= 97:
+			style.fg = svgBasicColors[code-90+8]
+		case code >= 100 && code <= 107:
+			style.bg = svgBasicColors[code-100+8]
+		}
+	}
+	return style
+}
+
+// svgRun is text drawn in one style, starting at a cell of its line
+type svgRun struct {
+	style cellStyle
+	text  string
+	cell  int
+	width int
+}
+
+// parseStyledLine splits one line of terminal output into styled runs,
+// starting from style and returning the style left at the end of the line.
+// Escape sequences other than colours and attributes are dropped.
+func parseStyledLine(line string, style cellStyle) ([]svgRun, cellStyle, int) {
+	var runs []svgRun
+	var text strings.Builder
+	start, cell := 0, 0
+	flush := func() {
+		if text.Len() > 0 {
+			runs = append(runs, svgRun{style: style, text: text.String(), cell: start, width: cell - start})
+			text.Reset()
+		}
+		start = cell
+	}
+
+	var state byte
+	for len(line) > 0 {
+		seq, width, n, newState := ansi.DecodeSequence(line, state, nil)
+		state = newState
+		line = line[n:]
+		switch {
+		case strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m"):
+			flush()
+			style = applySGR(style, seq[2:len(seq)-1])
+		case width > 0:
+			text.WriteString(seq)
+			cell += width
+		case seq == "\t":
+			text.WriteString("    ")
+			cell += 4
+		}
+	}
+	flush()
+	return runs, style, cell
+}
+
+// svgNumber formats a coordinate without float noise
+func svgNumber(value float64) string {
+	return strconv.FormatFloat(float64(int(value*10+0.5))/10, 'f', -1, 64)
+}
+
+// renderViewSVG draws styled terminal output, such as a view's rendered
+// string, as an SVG document preserving its colours and text attributes
+func renderViewSVG(view string) string {
+	lines := strings.Split(strings.TrimSuffix(view, "\n"), "\n")
+
+	var body strings.Builder
+	var style cellStyle
+	columns := 0
+	for row, line := range lines {
+		var runs []svgRun
+		var width int
+		runs, style, width = parseStyledLine(line, style)
+		columns = max(columns, width)
+
+		y := float64(svgPadding + row*svgLineHeight)
+		for _, run := range runs {
+			fg, bg := run.style.colors()
+			x := svgPadding + float64(run.cell)*svgCellWidth
+			length := float64(run.width) * svgCellWidth
+			if bg != "" {
+				fmt.Fprintf(&body, `<rect x="%s" y="%s" width="%s" height="%d" fill="%s"/>`+"\n",
+					svgNumber(x), svgNumber(y), svgNumber(length), svgLineHeight, bg)
+			}
+			if strings.TrimSpace(run.text) == "" && !run.style.underline && !run.style.strike {
+				continue
+			}
+
+			attributes := fmt.Sprintf(`x="%s" y="%s" textLength="%s" lengthAdjust="spacingAndGlyphs" fill="%s"`, svgNumber(x), svgNumber(y+svgFontSize), svgNumber(length), fg)
+			if run.style.bold {
+				attributes += ` font-weight="bold"`
+			}
+			if run.style.italic {
+				attributes += ` font-style="italic"`
+			}
+			if run.style.faint {
+				attributes += ` opacity="0.6"`
+			}
+			var decorations []string
+			if run.style.underline {
+				decorations = append(decorations, "underline")
+			}
+			if run.style.strike {
+				decorations = append(decorations, "line-through")
+			}
+			if len(decorations) > 0 {
+				attributes += fmt.Sprintf(` text-decoration="%s"`, strings.Join(decorations, " "))
+			}
+			fmt.Fprintf(&body, "<text %s>%s</text>\n", attributes, html.EscapeString(run.text))
+		}
+	}
+
+	width := 2*svgPadding + float64(columns)*svgCellWidth
+	height := 2*svgPadding + len(lines)*svgLineHeight
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%d" viewBox="0 0 %s %d">`+"\n",
+		svgNumber(width), height, svgNumber(width), height)
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgDefaultBackground)
+	fmt.Fprintf(&svg, `<g font-family="Menlo, Consolas, 'DejaVu Sans Mono', monospace" font-size="%d" xml:space="preserve">`+"\n", svgFontSize)
+	svg.WriteString(body.String())
+	svg.WriteString("</g>\n</svg>\n")
+	return svg.String()
+}
+
+// viewName names the current view in capture file names
+func (m *Model) viewName() string {
+	switch m.currentView {
+	case ViewModeTree:
+		return "tree"
+	case ViewModeRecord:
+		return "record"
+	case ViewModeQuery:
+		return "query"
+	default:
+		return "start"
+	}
+}
+
+// captureView saves the screen as it is now to an SVG file in the captures
+// directory next to the config file, for runbooks and documentation
+func (m *Model) captureView() tea.Cmd {
+	svg := renderViewSVG(m.View())
+	dir := config.CaptureDir(m.startView.configPath)
+	path := filepath.Join(dir, fmt.Sprintf("moribito-%s-%s.svg", m.viewName(), time.Now().Format("20060102-150405")))
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return func() tea.Msg {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return ErrorMsg{Err: fmt.Errorf("failed to save the view to %s: %w", path, err)}
+		}
+		if err := os.WriteFile(path, []byte(svg), 0644); err != nil {
+			return ErrorMsg{Err: fmt.Errorf("failed to save the view to %s: %w", path, err)}
+		}
+		return StatusMsg{Message: "Saved the view to " + path, Level: ToastSuccess}
+	}
+}
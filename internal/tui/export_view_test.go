@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+)
+
+func TestRenderViewSVG(t *testing.T) {
+	view := "\x1b[1;38;5;9mError\x1b[0m <ok>\n\x1b[48;2;0;0;255m  \x1b[m\x1b[4mnext\x1b[24m \x1b[7mrev\x1b[27m\n"
+	svg := renderViewSVG(view)
+
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="108" height="60"`,
+		`fill="#ff0000" font-weight="bold">Error</text>`,
+		`fill="#d0d0d0"> &lt;ok&gt;</text>`,
+		`<rect x="12" y="30" width="16.8" height="18" fill="#0000ff"/>`,
+		`x="28.8" y="44" textLength="33.6" lengthAdjust="spacingAndGlyphs" fill="#d0d0d0" text-decoration="underline">next</text>`,
+		`<rect x="70.8" y="30" width="25.2" height="18" fill="#d0d0d0"/>`,
+		`fill="#1c1c1c">rev</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Expected the SVG to contain %q, got:\n%s", want, svg)
+		}
+	}
+	if strings.Contains(svg, "\x1b") {
+		t.Error("Expected no escape sequences in the SVG")
+	}
+}
+
+func TestAnsi256Color(t *testing.T) {
+	tests := map[int]string{1: "#cd0000", 12: "#5c5cff", 16: "#000000", 21: "#0000ff", 196: "#ff0000", 232: "#080808", 255: "#eeeeee"}
+	for index, want := range tests {
+		if got := ansi256Color(index); got != want {
+			t.Errorf("ansi256Color(%d) = %q, want %q", index, got, want)
+		}
+	}
+}
+
+func TestModel_CaptureView(t *testing.T) {
+	dir := t.TempDir()
+	m := NewModelWithUpdateCheckAndConfigPath(nil, config.Default(), false, filepath.Join(dir, "config.yaml"))
+	m.Init()
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("Expected Ctrl+S to capture the view")
+	}
+	msg, ok := cmd().(StatusMsg)
+	if !ok || !strings.Contains(msg.Message, "Saved the view to "+filepath.Join(dir, "captures")) {
+		t.Fatalf("Expected a saved status naming the captures directory, got %#v", msg)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "captures", "moribito-start-*.svg"))
+	if len(files) != 1 {
+		t.Fatalf("Expected one capture file, got %v", files)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svg := string(data); !strings.HasPrefix(svg, "<svg ") || !strings.Contains(svg, "Start") {
+		t.Errorf("Expected the start view as SVG, got:\n%s", svg[:min(len(svg), 300)])
+	}
+
+	// Text inputs keep Ctrl+S
+	m.queryView = NewQueryView(nil)
+	m.currentView = ViewModeQuery
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS}); cmd != nil {
+		if _, ok := cmd().(StatusMsg); ok {
+			t.Error("Expected Ctrl+S in the query input not to capture the view")
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package tui
+
+import "github.com/ericschmar/moribito/internal/config"
+
+// icon is a symbol shown in the UI, with a plain ASCII form for terminals
+// and SSH sessions that render emoji poorly
+type icon struct {
+	emoji string
+	ascii string
+}
+
+// noEmoji selects the ASCII form of every icon, see setNoEmoji
+var noEmoji bool
+
+// setNoEmoji switches every icon to its ASCII form, or back to emoji
+func setNoEmoji(enabled bool) {
+	noEmoji = enabled
+}
+
+// applyTheme applies the theme settings of cfg, if any
+func applyTheme(cfg *config.Config) {
+	if cfg != nil {
+		setNoEmoji(cfg.Theme.NoEmoji)
+	}
+}
+
+// String returns the form of the icon to render
+func (i icon) String() string {
+	if noEmoji {
+		return i.ascii
+	}
+	return i.emoji
+}
+
+// Icons used across the views. Render sites refer to these rather than
+// spelling out emoji so the no-emoji setting reaches all of them.
+var (
+	// Tab bar
+	iconStart  = icon{"🏠", "[H]"}
+	iconTree   = icon{"🌲", "[T]"}
+	iconRecord = icon{"📄", "[R]"}
+	iconQuery  = icon{"🔍", "[Q]"}
+
+	// Status bar
+	iconIdentity  = icon{"🔗", "@"}
+	iconSecure    = icon{"🔒", "[S]"}
+	iconPlaintext = icon{"⚠ 🔓", "[!]"}
+	iconUpdate    = icon{"🔄", "[^]"}
+	iconLatency   = icon{"⏱", "rtt"}
+
+	// Messages
+	iconError   = icon{"❌", "[x]"}
+	iconWarning = icon{"⚠", "[!]"}
+	iconLoading = icon{"⏳", "..."}
+	iconFailed  = icon{"✗", "x"}
+	iconSuccess = icon{"✓", "+"}
+
+	// Entries
+	iconAlias      = icon{"↪", "->"}
+	iconDepthLimit = icon{"⋯", "..."}
+
+	// Tables
+	iconColumnFocus = icon{"▸", ">"}
+)
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+// isPlainRune reports whether r renders at a known width on limited
+// terminals: ASCII, the bullet separator, or the box-drawing characters
+// used for borders
+func isPlainRune(r rune) bool {
+	return r < 0x80 || r == '•' || (r >= 0x2500 && r <= 0x257F)
+}
+
+func TestNoEmoji_TabBarAndStatus(t *testing.T) {
+	zone.NewGlobal()
+
+	cfg := config.Default()
+	cfg.Theme.NoEmoji = true
+	model := NewModel(nil, cfg)
+	t.Cleanup(func() { setNoEmoji(false) })
+	model.width = 120
+
+	tabBar := model.renderTabBar()
+	for _, r := range tabBar {
+		if !isPlainRune(r) {
+			t.Fatalf("Tab bar contains %q with no_emoji set: %q", r, tabBar)
+		}
+	}
+	if !strings.Contains(tabBar, "[T] Tree") {
+		t.Errorf("Expected ASCII tree tab, got %q", tabBar)
+	}
+
+	if security, _ := connectionSecurity(ldap.Config{UseSSL: true}); security != "[S] LDAPS" {
+		t.Errorf("Expected [S] LDAPS, got %q", security)
+	}
+}
+
+func TestNoEmoji_DefaultKeepsEmoji(t *testing.T) {
+	setNoEmoji(false)
+	if iconError.String() != "❌" {
+		t.Errorf("Expected emoji by default, got %q", iconError.String())
+	}
+}
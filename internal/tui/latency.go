@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// pingInterval is how often the active connection's round-trip time is
+// measured for the status bar
+const pingInterval = 30 * time.Second
+
+// slowRoundTrip is the round-trip time above which the status bar shows it
+// as a warning
+const slowRoundTrip = 500 * time.Millisecond
+
+// PingMsg is sent when a connection's round-trip time has been measured.
+// Scheduled pings re-arm the next one; the others were asked for.
+type PingMsg struct {
+	Client    *ldap.Client
+	RTT       time.Duration
+	Err       error
+	Scheduled bool
+}
+
+// pingTickMsg starts the next scheduled ping of Client
+type pingTickMsg struct {
+	Client *ldap.Client
+}
+
+// latencyReading is the last round-trip time measured for a connection
+type latencyReading struct {
+	rtt      time.Duration
+	err      error
+	measured bool
+}
+
+// pingCmd measures client's round-trip time in the background
+func pingCmd(client *ldap.Client, scheduled bool) tea.Cmd {
+	if client == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		rtt, err := client.Ping()
+		return PingMsg{Client: client, RTT: rtt, Err: err, Scheduled: scheduled}
+	}
+}
+
+// schedulePing waits pingInterval before pinging client again
+func schedulePing(client *ldap.Client) tea.Cmd {
+	return tea.Tick(pingInterval, func(time.Time) tea.Msg {
+		return pingTickMsg{Client: client}
+	})
+}
+
+// formatRoundTrip renders a round-trip time to the millisecond
+func formatRoundTrip(rtt time.Duration) string {
+	if rtt < time.Millisecond {
+		return "<1ms"
+	}
+	return rtt.Round(time.Millisecond).String()
+}
+
+// describe renders the reading for the server info overlay and toasts
+func (r latencyReading) describe() string {
+	switch {
+	case !r.measured:
+		return "not measured yet"
+	case r.err != nil:
+		return "no reply: " + ldap.Explain(r.err).Error()
+	default:
+		return formatRoundTrip(r.rtt)
+	}
+}
+
+// render draws the reading as a status bar segment, empty until measured
+func (r latencyReading) render() string {
+	if !r.measured {
+		return ""
+	}
+	style := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color("10")).
+		Padding(0, 1)
+	text := formatRoundTrip(r.rtt)
+	switch {
+	case r.err != nil:
+		style = style.Background(lipgloss.Color("9"))
+		text = "no reply"
+	case r.rtt > slowRoundTrip:
+		style = style.Background(lipgloss.Color("11"))
+	}
+	return style.Render(iconLatency.String() + " " + text)
+}
+
+// handlePing records a measured round-trip time for the connection it
+// belongs to. Pings of a client that has since been replaced are dropped,
+// which ends their schedule; the new client starts its own.
+func (m *Model) handlePing(msg PingMsg) tea.Cmd {
+	if msg.Client != m.client {
+		return nil
+	}
+	m.latency = latencyReading{rtt: msg.RTT, err: msg.Err, measured: true}
+	if m.serverInfo != nil && m.serverInfo.client == msg.Client {
+		m.serverInfo.latency = m.latency
+	}
+	if msg.Scheduled {
+		return schedulePing(msg.Client)
+	}
+	if m.serverInfo == nil {
+		level := ToastInfo
+		if msg.Err != nil {
+			level = ToastError
+		}
+		m.notify(level, fmt.Sprintf("Round trip to %s: %s", m.connection.Host, m.latency.describe()))
+	}
+	return nil
+}
+
+// measureLatency pings the active connection now, for Ctrl+T
+func (m *Model) measureLatency() tea.Cmd {
+	if m.client == nil {
+		m.notify(ToastInfo, "Connect to a server to measure its round-trip time")
+		return nil
+	}
+	return pingCmd(m.client, false)
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func TestModel_PingShowsInStatusBar(t *testing.T) {
+	zone.NewGlobal()
+	model, client := newBaseDNModel(t, "")
+
+	if strings.Contains(ansi.Strip(model.renderStatusBar()), iconLatency.String()) {
+		t.Error("Expected no round-trip time before the first ping")
+	}
+
+	if cmd := model.handlePing(PingMsg{Client: client, RTT: 42 * time.Millisecond, Scheduled: true}); cmd == nil {
+		t.Error("Expected a scheduled ping to schedule the next one")
+	}
+	if bar := ansi.Strip(model.renderStatusBar()); !strings.Contains(bar, iconLatency.String()+" 42ms") {
+		t.Errorf("Expected the round-trip time in the status bar, got %q", bar)
+	}
+
+	model.handlePing(PingMsg{Client: client, Err: errors.New("connection closed"), Scheduled: true})
+	if bar := ansi.Strip(model.renderStatusBar()); !strings.Contains(bar, "no reply") {
+		t.Errorf("Expected a failed ping to show in the status bar, got %q", bar)
+	}
+
+	// Pings of a replaced client are dropped, which ends their schedule
+	if cmd := model.handlePing(PingMsg{Client: &ldap.Client{}, RTT: time.Second, Scheduled: true}); cmd != nil || !model.latency.measured || model.latency.err == nil {
+		t.Error("Expected a ping of another client to be ignored")
+	}
+	if _, cmd := model.Update(pingTickMsg{Client: &ldap.Client{}}); cmd != nil {
+		t.Error("Expected the schedule of a replaced client to stop")
+	}
+}
+
+func TestModel_PingOnDemand(t *testing.T) {
+	zone.NewGlobal()
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	model, _ := newBaseDNModel(t, "")
+	model.client = client
+	model.connection = client.Config()
+	model.currentView = ViewModeTree
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	var ping *PingMsg
+	for _, msg := range runCmd(cmd) {
+		if tagged, ok := msg.(tabMsg); ok {
+			msg = tagged.msg
+		}
+		if msg, ok := msg.(PingMsg); ok {
+			ping = &msg
+		}
+	}
+	if ping == nil || ping.Err != nil || ping.Scheduled {
+		t.Fatalf("Expected Ctrl+T to ping the server, got %+v", ping)
+	}
+	if cmd := model.handlePing(*ping); cmd != nil {
+		t.Error("Expected an asked-for ping not to start another schedule")
+	}
+	if got := model.toasts.latest(); !strings.HasPrefix(got, "Round trip to 127.0.0.1: ") {
+		t.Errorf("Expected a toast with the round-trip time, got %q", got)
+	}
+
+	// The query inputs keep Ctrl+T for the attribute picker
+	model.currentView = ViewModeQuery
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if model.queryView.picker == nil {
+		t.Error("Expected Ctrl+T in the query inputs to open the attribute picker")
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	tests := map[time.Duration]string{
+		300 * time.Microsecond:                     "<1ms",
+		12*time.Millisecond + 400*time.Microsecond: "12ms",
+		1500 * time.Millisecond:                    "1.5s",
+	}
+	for rtt, want := range tests {
+		if got := formatRoundTrip(rtt); got != want {
+			t.Errorf("formatRoundTrip(%v) = %q, want %q", rtt, got, want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/atotto/clipboard"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ericschmar/moribito/internal/config"
@@ -35,10 +36,33 @@ type (
 		err       error
 	}
 
-	// ConnectMsg is sent when the start view successfully connects to LDAP
+	// ConnectMsg is sent when the start view successfully connects to LDAP.
+	// NewTab opens the connection in a new tab instead of replacing the
+	// active one. KeepView shows View afterwards instead of the tree view.
 	ConnectMsg struct {
+		Client   *ldap.Client
+		Config   *config.Config
+		NewTab   bool
+		KeepView bool
+		View     ViewMode
+	}
+
+	// AuthenticationRequiredMsg is sent when a connection without a bind user
+	// fails because the server doesn't allow anonymous access
+	AuthenticationRequiredMsg struct {
+		Explanation ldap.Explanation
+	}
+
+	// SchemaLoadedMsg is sent when the schema for a connection has been read
+	SchemaLoadedMsg struct {
 		Client *ldap.Client
-		Config *config.Config
+		Schema *ldap.Schema
+	}
+
+	// CapabilitiesLoadedMsg is sent when the root DSE of a connection has been read
+	CapabilitiesLoadedMsg struct {
+		Client       *ldap.Client
+		Capabilities *ldap.Capabilities
 	}
 )
 
@@ -71,6 +95,70 @@ func checkForUpdatesCmd() tea.Cmd {
 	}
 }
 
+// loadSchemaCmd fetches the server schema in the background. Failures are
+// ignored since editing falls back to plain text inputs without a schema.
+func loadSchemaCmd(client *ldap.Client) tea.Cmd {
+	return func() tea.Msg {
+		schema, err := client.GetSchema()
+		if err != nil {
+			return nil
+		}
+		return SchemaLoadedMsg{Client: client, Schema: schema}
+	}
+}
+
+// loadCapabilitiesCmd reads the server's root DSE in the background. Failures
+// are ignored; operations are attempted as usual when capabilities are unknown.
+func loadCapabilitiesCmd(client *ldap.Client) tea.Cmd {
+	return func() tea.Msg {
+		caps, err := client.LoadCapabilities()
+		if err != nil {
+			return nil
+		}
+		return CapabilitiesLoadedMsg{Client: client, Capabilities: caps}
+	}
+}
+
+// auditFailureCmd reports a failure to write the audit log after a write
+// operation. The operation itself is unaffected.
+func (m *Model) auditFailureCmd() tea.Cmd {
+	if m.client == nil {
+		return nil
+	}
+	if err := m.client.TakeAuditError(); err != nil {
+		return SendError(fmt.Errorf("audit log not written: %w", err))
+	}
+	return nil
+}
+
+// gatedCapabilities lists the capabilities the UI depends on, with the
+// behaviour users see when the server doesn't advertise them
+var gatedCapabilities = []struct {
+	OID     string
+	Missing string
+}{
+	{ldap.OIDPagedResults, "paged results unavailable, searches return all entries at once"},
+	{ldap.OIDPasswordModify, "password modify extended operation unavailable"},
+}
+
+// capabilityWarning describes the gated capabilities the server lacks, or
+// returns an empty string when everything is supported
+func capabilityWarning(caps *ldap.Capabilities) string {
+	if caps == nil {
+		return ""
+	}
+	var missing []string
+	for _, gated := range gatedCapabilities {
+		if !caps.Supports(gated.OID) {
+			missing = append(missing, gated.Missing)
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	return iconWarning.String() + " Server limitations: " + strings.Join(missing, "; ")
+}
+
 // Model represents the main TUI model
 type Model struct {
 	client       *ldap.Client
@@ -82,14 +170,38 @@ type Model struct {
 	width        int
 	height       int
 	err          error
-	statusMsg    string
+	toasts       toastQueue // Transient notifications shown above the status bar
 	quitting     bool
 	checkUpdates bool
 	updateStatus string
+	switcher     *QuickSwitcher    // Recent connections overlay, nil when closed
+	wizard       *SetupWizardView  // First-run setup wizard, nil when closed
+	baseDNPrompt *BaseDNPrompt     // Set as base DN overlay, nil when closed
+	serverInfo   *ServerInfo       // Server info and clock skew overlay, nil when closed
+	monitor      *MonitorDashboard // cn=monitor statistics overlay, nil when closed
+
+	entryNotFound *EntryNotFound      // Recovery overlay for an entry that has gone, nil when closed
+	palette       *CommandPaletteView // Command palette overlay, nil when closed
+	compare       *ConnectionCompare  // Entry comparison with another connection, nil when closed
+	confirmQuit   bool                // Asking before quitting with unsaved changes
+
+	sessionBaseDN bool           // The base DN was changed for this session without saving
+	connection    ldap.Config    // Settings of the active connection, for the status bar
+	latency       latencyReading // Round-trip time of the active connection, for the status bar
+
+	tabs      []*connectionTab // Open connections, one per tab; the active one is mirrored in the fields above
+	activeTab int
+
+	retries chan RetryStatusMsg // Retry reports from the open connections, nil until the first connect
+
+	pinned    *ldap.Entry    // Entry kept in view while navigating, nil when none is pinned
+	pinnedTab *connectionTab // Tab the pinned entry was pinned in
 }
 
 // NewModel creates a new model
 func NewModel(client *ldap.Client, cfg *config.Config) *Model {
+	applyTheme(cfg)
+
 	model := &Model{
 		client:      client,
 		startView:   NewStartView(cfg),
@@ -100,7 +212,7 @@ func NewModel(client *ldap.Client, cfg *config.Config) *Model {
 	// Initialize tree and query views if client is available
 	if client != nil {
 		model.tree = NewTreeView(client)
-		model.queryView = NewQueryViewWithPageSize(client, cfg.Pagination.PageSize)
+		model.queryView = NewQueryViewWithPageSize(client, cfg.Pagination.EffectivePageSize())
 	}
 
 	return model
@@ -113,6 +225,8 @@ func NewModelWithPageSize(client *ldap.Client, cfg *config.Config) *Model {
 
 // NewModelWithUpdateCheck creates a new model with page size configuration and update checking
 func NewModelWithUpdateCheck(client *ldap.Client, cfg *config.Config, checkUpdates bool) *Model {
+	applyTheme(cfg)
+
 	model := &Model{
 		client:       client,
 		startView:    NewStartView(cfg),
@@ -124,7 +238,7 @@ func NewModelWithUpdateCheck(client *ldap.Client, cfg *config.Config, checkUpdat
 	// Initialize tree and query views if client is available
 	if client != nil {
 		model.tree = NewTreeView(client)
-		model.queryView = NewQueryViewWithPageSize(client, cfg.Pagination.PageSize)
+		model.queryView = NewQueryViewWithPageSize(client, cfg.Pagination.EffectivePageSize())
 	}
 
 	return model
@@ -132,6 +246,8 @@ func NewModelWithUpdateCheck(client *ldap.Client, cfg *config.Config, checkUpdat
 
 // NewModelWithUpdateCheckAndConfigPath creates a new model with page size configuration, update checking, and config path
 func NewModelWithUpdateCheckAndConfigPath(client *ldap.Client, cfg *config.Config, checkUpdates bool, configPath string) *Model {
+	applyTheme(cfg)
+
 	model := &Model{
 		client:       client,
 		startView:    NewStartViewWithConfigPath(cfg, configPath),
@@ -143,12 +259,39 @@ func NewModelWithUpdateCheckAndConfigPath(client *ldap.Client, cfg *config.Confi
 	// Initialize tree and query views if client is available
 	if client != nil {
 		model.tree = NewTreeView(client)
-		model.queryView = NewQueryViewWithPageSize(client, cfg.Pagination.PageSize)
+		model.queryView = NewQueryViewWithPageSize(client, cfg.Pagination.EffectivePageSize())
 	}
 
 	return model
 }
 
+// ShowSetupWizard opens the first-run setup wizard in place of the start view
+func (m *Model) ShowSetupWizard() {
+	m.wizard = NewSetupWizardView(m.startView.config)
+	m.wizard.SetSize(m.width, m.height-5)
+	m.currentView = ViewModeStart
+}
+
+// autoConnect starts connecting on startup when auto_connect is set and the
+// active connection is complete, so the tree view opens once it succeeds.
+// Otherwise, and when the attempt fails, the start view stays up.
+func (m *Model) autoConnect() tea.Cmd {
+	cfg := m.startView.config
+	if !cfg.LDAP.AutoConnect || m.client != nil || m.wizard != nil {
+		return nil
+	}
+	activeConn := cfg.GetActiveConnection()
+	if !activeConn.Complete() {
+		return nil
+	}
+	name := activeConn.Name
+	if name == "" {
+		name = activeConn.Host
+	}
+	m.notify(ToastInfo, fmt.Sprintf("Connecting to %s...", name))
+	return m.startConnect()
+}
+
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
 	// Initialize bubblezone manager to prevent panics
@@ -182,6 +325,11 @@ func (m *Model) Init() tea.Cmd {
 		cmds = append(cmds, checkForUpdatesCmd())
 	}
 
+	if cmd := m.autoConnect(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	cmds = append(cmds, toastTick())
+
 	return tea.Batch(cmds...)
 }
 
@@ -207,8 +355,31 @@ func (m *Model) SetSize(width, height int) {
 	}
 }
 
-// Update handles messages
+// Update handles messages. Once connected, commands are tagged with the
+// connection tab they came from so their results are routed back to it.
+// Toasts belong to the whole window, so their expiry ticks aren't tagged.
+// Neither are retry reports: one waiter serves every connection, and it must
+// be started again even when the tab it was tagged with has been closed.
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ToastTickMsg:
+		m.toasts.expire(msg.Time)
+		return m, toastTick()
+	case RetryStatusMsg:
+		m.notify(ToastWarning, m.retryStatus(msg))
+		return m, waitForRetry(m.retries)
+	case tabMsg:
+		if retry, ok := msg.msg.(RetryStatusMsg); ok {
+			return m.Update(retry)
+		}
+		return m, m.updateTab(msg)
+	}
+	_, cmd := m.update(msg)
+	return m, m.tagCmd(cmd)
+}
+
+// update handles a message for the active connection
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -226,31 +397,113 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.queryView != nil {
 			m.queryView.SetSize(msg.Width, contentHeight)
 		}
+		if m.wizard != nil {
+			m.wizard.SetSize(msg.Width, contentHeight)
+		}
+
+	case wizardDiscoveryMsg, wizardBaseDNMsg:
+		if m.wizard != nil {
+			return m, m.updateWizard(msg)
+		}
+		return m, nil
 
 	case tea.KeyMsg:
+		if m.confirmQuit {
+			return m, m.handleQuitConfirmKey(msg)
+		}
+		if m.wizard != nil && msg.String() != "ctrl+c" {
+			return m, m.updateWizard(msg)
+		}
+		if m.switcher != nil && msg.String() != "ctrl+c" {
+			return m, m.handleSwitcherKey(msg)
+		}
+		if m.palette != nil && msg.String() != "ctrl+c" {
+			return m, m.handlePaletteKey(msg)
+		}
+		if m.serverInfo != nil && msg.String() != "ctrl+c" {
+			return m, m.handleServerInfoKey(msg)
+		}
+		if m.monitor != nil && msg.String() != "ctrl+c" {
+			return m, m.handleMonitorKey(msg)
+		}
+		if m.compare != nil && msg.String() != "ctrl+c" {
+			return m, m.handleCompareKey(msg)
+		}
+		if m.entryNotFound != nil && msg.String() != "ctrl+c" {
+			return m, m.handleEntryNotFoundKey(msg)
+		}
+		if m.baseDNPrompt != nil && msg.String() != "ctrl+c" {
+			return m, m.handleBaseDNPromptKey(msg)
+		}
+		if m.currentView == ViewModeStart && m.startView.confirmingPlaintextBind() && msg.String() != "ctrl+c" {
+			_, cmd := m.startView.Update(msg)
+			return m, cmd
+		}
+
 		switch msg.String() {
 		case "ctrl+c":
-			m.quitting = true
-			return m, tea.Quit
+			return m, m.quit()
+		case "ctrl+k":
+			return m, m.openSwitcher()
+		case "ctrl+x":
+			// Terminals send Ctrl+Shift+P as Ctrl+P, which the query view
+			// keeps for its presets
+			return m, m.openPalette()
+		case "ctrl+g":
+			return m, m.openServerInfo()
+		case "ctrl+t":
+			// Views with attribute inputs keep Ctrl+T for the attribute picker
+			if m.isInputActive() {
+				break
+			}
+			return m, m.measureLatency()
+		case "ctrl+r":
+			return m, m.reconnect()
+		case "ctrl+b":
+			// Text inputs keep Ctrl+B for moving back a character
+			if m.isInputActive() {
+				break
+			}
+			return m, m.openPinned()
+		case "ctrl+s":
+			// Terminals send Ctrl+Shift+S as Ctrl+S too. Text inputs keep it,
+			// such as the record editor and entry forms for saving.
+			if m.isInputActive() {
+				break
+			}
+			return m, m.captureView()
+		case "ctrl+n":
+			return m, m.nextTab()
+		case "{", "}":
+			// [ and ] move between naming contexts in the tree
+			if m.isInputActive() {
+				break
+			}
+			if msg.String() == "{" {
+				return m, m.cycleConnection(-1)
+			}
+			return m, m.cycleConnection(1)
+		case "ctrl+w":
+			return m, m.closeTab()
+		case "alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9":
+			return m, m.switchTab(int(msg.Runes[0] - '1'))
 		case "q":
 			// Skip global quit key if we're in an input mode
-			if m.currentView == ViewModeQuery && m.queryView != nil && m.queryView.IsInputMode() {
-				break // Let the query view handle the input
+			if m.isInputActive() {
+				break // Let the current view handle the input
 			}
-			if m.currentView == ViewModeStart && m.startView != nil && m.startView.IsEditing() {
-				break // Let the start view handle the input
-			}
-			m.quitting = true
-			return m, tea.Quit
+			return m, m.quit()
 		case "tab":
+			// Tab completes the query view's base DN and attribute inputs, and
+			// can be set to indent the filter
+			if m.currentView == ViewModeQuery && m.queryView != nil && m.queryView.CapturesTab() {
+				break
+			}
 			return m.switchView(), nil
 		case "1", "2", "3", "4":
 			// Skip global navigation keys if we're in an input mode
-			if m.currentView == ViewModeQuery && m.queryView != nil && m.queryView.IsInputMode() {
-				break // Let the query view handle the input
-			}
-			if m.currentView == ViewModeStart && m.startView != nil && m.startView.IsEditing() {
-				break // Let the start view handle the input
+			if m.isInputActive() {
+				break // Let the current view handle the input
 			}
 			// Handle navigation keys for view switching
 			switch msg.String() {
@@ -278,24 +531,56 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ErrorMsg:
 		m.err = msg.Err
+		if msg.Err != nil {
+			m.notify(ToastError, fmt.Sprintf("Error: %v", ldap.Explain(msg.Err)))
+		}
 		return m, nil
 
 	case StatusMsg:
-		m.statusMsg = msg.Message
+		m.notify(msg.Level, msg.Message)
+		return m, nil
+
+	case AuthenticationRequiredMsg:
+		m.startView.showAuthenticationRequired(msg.Explanation)
+		m.currentView = ViewModeStart
+		m.notify(ToastError, "Connection failed: "+msg.Explanation.Summary)
 		return m, nil
 
 	case ShowRecordMsg:
 		m.recordView.SetEntry(msg.Entry)
 		m.currentView = ViewModeRecord
+		return m, m.recordView.loadBoundRights()
+
+	case PinEntryMsg:
+		m.togglePin(msg.Entry)
 		return m, nil
 
+	case EntryNotFoundMsg:
+		m.openEntryNotFound(msg.DN)
+		return m, nil
+
+	case ChangeBaseDNMsg:
+		return m, m.openBaseDNPrompt(msg.DN)
+
+	case LoadQueryMsg:
+		if m.queryView == nil {
+			return m, SendError(fmt.Errorf("query view requires an LDAP connection"))
+		}
+		m.queryView.SetFilter(msg.Filter)
+		m.currentView = ViewModeQuery
+		m.notify(ToastInfo, "Loaded filter into the query view, press Enter to run it"+msg.Note)
+		return m, nil
+
+	case ColumnRatioChangedMsg:
+		return m, m.saveColumnRatio(msg)
+
 	case updateCheckMsg:
 		if msg.err != nil {
 			// Silently ignore update check errors - don't disturb user experience
 			return m, nil
 		}
 		if msg.available {
-			m.updateStatus = fmt.Sprintf("🔄 Update available: %s", msg.version)
+			m.updateStatus = fmt.Sprintf("%s Update available: %s", iconUpdate, msg.version)
 		} else {
 			m.updateStatus = ""
 		}
@@ -303,33 +588,206 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ConnectMsg:
 		// Handle successful LDAP connection from start view
-		if m.client != nil {
+		if msg.NewTab && m.client != nil {
+			// Park the active connection and give the new one its own views
+			m.storeTab()
+			m.tabs = append(m.tabs, &connectionTab{})
+			m.activeTab = len(m.tabs) - 1
+			m.recordView = NewRecordView()
+			m.recordView.SetSize(m.width, m.height-5)
+		} else if m.client != nil {
 			m.client.Close() // Close existing connection if any
 		}
+		if len(m.tabs) == 0 {
+			m.tabs = []*connectionTab{{}}
+			m.activeTab = 0
+		}
 
 		m.client = msg.Client
+		m.sessionBaseDN = false
+		m.latency = latencyReading{}
+		if msg.Client != nil {
+			m.connection = msg.Client.Config()
+			msg.Client.SetTreeLabelAttribute(msg.Config.Display.TreeLabelAttribute)
+			if err := msg.Client.SetTreeChildFilter(msg.Config.Display.TreeChildFilter); err != nil {
+				m.notify(ToastWarning, fmt.Sprintf("%v. Showing every child.", err))
+			}
+		}
+		m.startView.markActiveConnectionUsed()
+		m.recordView.SetClient(msg.Client)
+		m.recordView.SetSchema(nil)
+		m.recordView.SetMultiValueDisplay(msg.Config.Display.MultiValue, msg.Config.Display.MultiValueSeparator)
+		m.recordView.SetHideEmpty(msg.Config.Display.HideEmpty)
+		m.recordView.SetColumnRatio(msg.Config.Display.RecordColumnRatio)
+		m.recordView.SetVisibleAttributes(msg.Config.Display.VisibleAttributes)
+		m.recordView.SetMultilineAttributes(msg.Config.Display.MultilineAttributes)
+		m.recordView.SetNormalizeAttributeNames(msg.Config.Display.NormalizeAttributeNames)
+		m.recordView.SetCodeLanguage(msg.Config.Display.CodeLanguage)
 
 		// Initialize tree and query views with new client
 		m.tree = NewTreeView(msg.Client)
-		m.queryView = NewQueryViewWithPageSize(msg.Client, msg.Config.Pagination.PageSize)
+		m.tree.SetTemplates(msg.Config.EntryTemplates)
+		m.tree.SetAllNamingContexts(msg.Config.Display.AllNamingContexts)
+		m.tree.SetMaxAutoDepth(msg.Config.Display.TreeMaxAutoDepth)
+		m.tree.SetCollapseRoot(msg.Config.Display.TreeCollapseRoot)
+		m.tree.SetConnectionName(tabName(msg.Config.GetActiveConnection()))
+		m.queryView = NewQueryViewWithPageSize(msg.Client, msg.Config.Pagination.EffectivePageSize())
+		m.queryView.SetDNSource(func() []string {
+			if m.tree == nil {
+				return nil
+			}
+			return m.tree.LoadedDNs()
+		})
+		m.queryView.SetColumnRatio(msg.Config.Display.QueryColumnRatio)
+		m.queryView.SetTabBehavior(msg.Config.Display.QueryTab)
+		m.queryView.SetAbbreviateDNs(msg.Config.Display.AbbreviateDNs)
+		m.queryView.SetConfirmThreshold(msg.Config.ConfirmThreshold)
+		m.queryView.SetSnapshotDir(config.SnapshotDir(m.startView.configPath))
 
 		// Set sizes for the new views (reserve space for tab bar, status bar, and help bar)
 		contentHeight := m.height - 5
 		m.tree.SetSize(m.width, contentHeight)
 		m.queryView.SetSize(m.width, contentHeight)
 
-		// Switch to tree view
-		m.currentView = ViewModeTree
-		m.statusMsg = "Successfully connected to LDAP server"
+		tab := m.tabs[m.activeTab]
+		tab.name = tabName(msg.Config.GetActiveConnection())
+
+		// Switch to tree view, unless the connection was cycled from another
+		// view. The record view's entry came from the old server, so it
+		// makes way for the tree.
+		if msg.KeepView {
+			m.currentView = msg.View
+			if m.currentView == ViewModeRecord {
+				m.currentView = ViewModeTree
+			}
+			m.notify(ToastSuccess, fmt.Sprintf("Switched to %s", tab.name))
+		} else {
+			m.currentView = ViewModeTree
+			m.notify(ToastSuccess, "Successfully connected to LDAP server")
+		}
+		tab.savedIndex = msg.Config.LDAP.SelectedConnection
+		m.storeTab()
 
-		// Initialize the tree view to start loading the tree
+		// Initialize the tree view to start loading the tree, and fetch the
+		// schema in the background for type-aware attribute editing
 		treeInitCmd := m.tree.Init()
 
-		return m, treeInitCmd
+		var retryCmd tea.Cmd
+		if msg.Client != nil {
+			retryCmd = m.watchRetries(msg.Client, tab.name)
+		}
+
+		return m, tea.Batch(treeInitCmd, loadSchemaCmd(msg.Client), loadCapabilitiesCmd(msg.Client), retryCmd, pingCmd(msg.Client, true))
+
+	case SchemaLoadedMsg:
+		if msg.Client == m.client {
+			m.recordView.SetSchema(msg.Schema)
+			if m.tree != nil {
+				m.tree.SetSchema(msg.Schema)
+			}
+			if m.queryView != nil {
+				m.queryView.SetSchema(msg.Schema)
+			}
+		}
+		return m, nil
+
+	case ServerTimeMsg:
+		if m.serverInfo != nil && msg.Client == m.serverInfo.client {
+			m.serverInfo.setTime(msg)
+		}
+		return m, nil
+
+	case MonitorStatsMsg:
+		if msg.Dashboard == m.monitor {
+			return m, m.monitor.setStats(msg)
+		}
+		return m, nil
+
+	case monitorTickMsg:
+		if msg.Dashboard == m.monitor {
+			return m, m.monitor.tick(msg)
+		}
+		return m, nil
+
+	case PingMsg:
+		return m, m.handlePing(msg)
+
+	case pingTickMsg:
+		if msg.Client != m.client {
+			return m, nil
+		}
+		return m, pingCmd(msg.Client, true)
+
+	case ConnectionsComparedMsg:
+		if m.compare != nil {
+			m.compare.SetResults(msg)
+		}
+		return m, nil
+
+	case CapabilitiesLoadedMsg:
+		if msg.Client == m.client {
+			if warning := capabilityWarning(msg.Capabilities); warning != "" {
+				m.notify(ToastWarning, warning)
+			}
+		}
+		return m, nil
+
+	// Attribute edits may complete after the user has switched views
+	case EntryModifiedMsg:
+		newModel, cmd := m.recordView.Update(msg)
+		m.recordView = newModel.(*RecordView)
+		m.notify(ToastSuccess, fmt.Sprintf("Updated %s", msg.Attribute))
+		return m, tea.Batch(cmd, m.auditFailureCmd())
+
+	case LoadAllPageMsg:
+		if m.queryView != nil {
+			newModel, cmd := m.queryView.Update(msg)
+			m.queryView = newModel.(*QueryView)
+			return m, cmd
+		}
+		return m, nil
+
+	case BulkModifyStepMsg:
+		if m.queryView != nil {
+			newModel, cmd := m.queryView.Update(msg)
+			m.queryView = newModel.(*QueryView)
+			return m, tea.Batch(cmd, m.auditFailureCmd())
+		}
+		return m, nil
+
+	case ProgressTickMsg:
+		// Keep long-running operations refreshing while another view is shown
+		if m.queryView != nil {
+			newModel, cmd := m.queryView.Update(msg)
+			m.queryView = newModel.(*QueryView)
+			return m, cmd
+		}
+		return m, nil
+
+	case EntryInfoLoadedMsg, EntryInfoErrorMsg, EntryReloadedMsg, RawEntryLoadedMsg, MoreValuesLoadedMsg, ChangeHighlightExpiredMsg:
+		newModel, cmd := m.recordView.Update(msg)
+		m.recordView = newModel.(*RecordView)
+		return m, cmd
+
+	case EntryModifyErrorMsg:
+		newModel, cmd := m.recordView.Update(msg)
+		m.recordView = newModel.(*RecordView)
+		m.notify(ToastError, fmt.Sprintf("Failed to update %s: %v", msg.Attribute, ldap.Explain(msg.Err)))
+		return m, tea.Batch(cmd, m.auditFailureCmd())
 
 	// Handle tree-specific messages regardless of current view
 	// This ensures tree loading works even when user switches away before completion
-	case RootNodeLoadedMsg, NodeChildrenLoadedMsg:
+	case RootNodeLoadedMsg, NodeChildrenLoadedMsg, DeleteRightsLoadedMsg, LoadingTimerTickMsg, EntryAddedMsg, EntryAddErrorMsg, EntryDeletedMsg, EntryDeleteErrorMsg, StaleEntryRemovedMsg:
+		switch msg := msg.(type) {
+		case EntryAddedMsg, EntryDeletedMsg:
+			cmds = append(cmds, m.auditFailureCmd())
+		case EntryAddErrorMsg:
+			m.notify(ToastError, fmt.Sprintf("Failed to create %s: %v", msg.DN, ldap.Explain(msg.Err)))
+			cmds = append(cmds, m.auditFailureCmd())
+		case EntryDeleteErrorMsg:
+			m.notify(ToastError, fmt.Sprintf("Failed to delete %s: %v", msg.DN, ldap.Explain(msg.Err)))
+			cmds = append(cmds, m.auditFailureCmd())
+		}
 		if m.tree != nil {
 			newModel, cmd := m.tree.Update(msg)
 			m.tree = newModel.(*TreeView)
@@ -404,7 +862,11 @@ func (m *Model) View() string {
 
 	switch m.currentView {
 	case ViewModeStart:
-		content = m.startView.View()
+		if m.wizard != nil {
+			content = m.wizard.View()
+		} else {
+			content = m.startView.View()
+		}
 	case ViewModeTree:
 		if m.tree != nil {
 			content = m.tree.View()
@@ -421,6 +883,32 @@ func (m *Model) View() string {
 		}
 	}
 
+	// The quick-switcher is drawn over whichever view is active
+	if m.switcher != nil {
+		content = lipgloss.Place(m.width, m.height-5, lipgloss.Center, lipgloss.Center, m.switcher.View())
+	}
+	if m.palette != nil {
+		content = lipgloss.Place(m.width, m.height-5, lipgloss.Center, lipgloss.Center, m.palette.View())
+	}
+	if m.compare != nil {
+		content = lipgloss.Place(m.width, m.height-5, lipgloss.Center, lipgloss.Center, m.compare.View())
+	}
+	if m.serverInfo != nil {
+		content = lipgloss.Place(m.width, m.height-5, lipgloss.Center, lipgloss.Center, m.serverInfo.View())
+	}
+	if m.monitor != nil {
+		content = lipgloss.Place(m.width, m.height-5, lipgloss.Center, lipgloss.Center, m.monitor.View())
+	}
+	if m.entryNotFound != nil {
+		content = lipgloss.Place(m.width, m.height-5, lipgloss.Center, lipgloss.Center, m.entryNotFound.View())
+	}
+	if m.baseDNPrompt != nil {
+		content = lipgloss.Place(m.width, m.height-5, lipgloss.Center, lipgloss.Center, m.baseDNPrompt.View())
+	}
+	if m.confirmQuit {
+		content = lipgloss.Place(m.width, m.height-5, lipgloss.Center, lipgloss.Center, m.renderQuitConfirm())
+	}
+
 	// Status bar
 	status := m.renderStatusBar()
 
@@ -446,6 +934,8 @@ func (m *Model) View() string {
 				Render("... (content truncated, resize terminal)")
 		}
 	}
+	contentLines = m.overlayPinned(contentLines, contentMaxLines)
+	contentLines = m.overlayToasts(contentLines)
 	content = strings.Join(contentLines, "\n")
 
 	// Build the layout with strictly controlled heights
@@ -476,6 +966,250 @@ func (m *Model) View() string {
 	return zone.Scan(finalView)
 }
 
+// isInputActive reports whether the current view is capturing text input,
+// in which case global single-key shortcuts are passed through to it
+func (m *Model) isInputActive() bool {
+	if m.switcher != nil || m.wizard != nil || m.baseDNPrompt != nil || m.serverInfo != nil || m.monitor != nil || m.entryNotFound != nil || m.palette != nil || m.compare != nil {
+		return true
+	}
+	switch m.currentView {
+	case ViewModeQuery:
+		return m.queryView != nil && m.queryView.IsInputMode()
+	case ViewModeStart:
+		return m.startView != nil && m.startView.IsEditing()
+	case ViewModeRecord:
+		// The copy menu takes single keys to pick a form
+		return m.recordView != nil && (m.recordView.IsEditing() || m.recordView.IsCopyMenuOpen())
+	case ViewModeTree:
+		return m.tree != nil && m.tree.IsFormActive()
+	}
+	return false
+}
+
+// openSwitcher shows the recent connections quick-switcher
+func (m *Model) openSwitcher() tea.Cmd {
+	if len(m.startView.config.LDAP.SavedConnections) == 0 {
+		m.notify(ToastInfo, "No saved connections to switch to")
+		return nil
+	}
+	m.switcher = NewQuickSwitcher(m.startView.config)
+	return nil
+}
+
+// handleSwitcherKey forwards a key to the quick-switcher and connects to the
+// chosen connection. The current client stays open until the new connection
+// succeeds, at which point ConnectMsg replaces it and shows the tree view.
+func (m *Model) handleSwitcherKey(msg tea.KeyMsg) tea.Cmd {
+	done, index := m.switcher.Update(msg)
+	if !done {
+		return nil
+	}
+	m.switcher = nil
+	if index < 0 {
+		return nil
+	}
+
+	// A connection that is already open is switched to rather than opened twice
+	if open := m.findTab(index); open >= 0 {
+		return m.switchTab(open)
+	}
+	if m.client != nil && len(m.tabs) >= maxConnectionTabs {
+		m.notify(ToastWarning, fmt.Sprintf("At most %d connections can be open; close one with [Ctrl+W] first", maxConnectionTabs))
+		return nil
+	}
+
+	m.startView.config.SetActiveConnection(index)
+	m.startView.connectionCursor = index
+	m.notify(ToastInfo, fmt.Sprintf("Connecting to %s...", m.startView.config.LDAP.SavedConnections[index].Name))
+	cmd := m.startConnect()
+	if m.client == nil {
+		return cmd
+	}
+
+	// Connected already, so the new connection opens in its own tab
+	if prompt := m.startView.plaintextPrompt; prompt != nil {
+		prompt.connect = inNewTab(prompt.connect)
+	}
+	return inNewTab(cmd)
+}
+
+// inNewTab makes a connect command open its connection in a new tab
+func inNewTab(cmd tea.Cmd) tea.Cmd {
+	return adjustConnect(cmd, func(connect *ConnectMsg) {
+		connect.NewTab = true
+	})
+}
+
+// adjustConnect applies adjust to the ConnectMsg a connect command produces
+func adjustConnect(cmd tea.Cmd, adjust func(*ConnectMsg)) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		if connect, ok := msg.(ConnectMsg); ok {
+			adjust(&connect)
+			return connect
+		}
+		return msg
+	}
+}
+
+// startConnect runs the start view's connect flow. A clear-text bind has to
+// be accepted in the start view first, so it is shown when the warning is up.
+func (m *Model) startConnect() tea.Cmd {
+	_, cmd := m.startView.handleConnect()
+	if m.startView.confirmingPlaintextBind() {
+		m.currentView = ViewModeStart
+	}
+	return cmd
+}
+
+// reconnect re-runs the connect flow for the active connection. The current
+// client stays open until the new one is bound, then ConnectMsg replaces it
+// and hands the new client to every view.
+func (m *Model) reconnect() tea.Cmd {
+	if m.client == nil {
+		m.notify(ToastInfo, "Not connected; choose Connect in the start view")
+		return nil
+	}
+
+	name := m.connection.ConnectionName
+	if name == "" {
+		name = m.connection.Host
+	}
+	m.notify(ToastInfo, fmt.Sprintf("Reconnecting to %s...", name))
+	return m.startConnect()
+}
+
+// openBaseDNPrompt shows the base DN prompt for dn
+func (m *Model) openBaseDNPrompt(dn string) tea.Cmd {
+	if m.client == nil {
+		return SendError(fmt.Errorf("not connected to an LDAP server"))
+	}
+
+	cfg := m.startView.config
+	connection := "the default connection"
+	if index := cfg.LDAP.SelectedConnection; index >= 0 && index < len(cfg.LDAP.SavedConnections) {
+		connection = fmt.Sprintf("connection %q", cfg.LDAP.SavedConnections[index].Name)
+	}
+	m.baseDNPrompt = NewBaseDNPrompt(dn, m.client.BaseDN(), connection, m.startView.configPath != "")
+	return nil
+}
+
+// handleBaseDNPromptKey forwards a key to the base DN prompt and applies the choice
+func (m *Model) handleBaseDNPromptKey(msg tea.KeyMsg) tea.Cmd {
+	done, action := m.baseDNPrompt.Update(msg)
+	if !done {
+		return nil
+	}
+	dn := m.baseDNPrompt.dn
+	m.baseDNPrompt = nil
+
+	switch action {
+	case baseDNCopy:
+		if err := clipboard.WriteAll(dn); err != nil {
+			return SendError(fmt.Errorf("failed to copy to clipboard: %w", err))
+		}
+		return SendSuccess("Copied DN to clipboard")
+	case baseDNSession:
+		return m.setBaseDN(dn, false)
+	case baseDNSave:
+		return m.setBaseDN(dn, true)
+	}
+	return nil
+}
+
+// setBaseDN re-roots the connection at dn and reloads the tree from it. The
+// config is only changed when persist is set, and a failed save leaves the
+// base DN as it was; otherwise the new base lasts until the next connect.
+func (m *Model) setBaseDN(dn string, persist bool) tea.Cmd {
+	if persist {
+		// The config is only saved as a whole, so it is changed first and
+		// put back as it was when saving fails
+		cfg := m.startView.config
+		previous := cfg.LDAP.BaseDN
+		index := cfg.LDAP.SelectedConnection
+		var previousSaved string
+		if index >= 0 && index < len(cfg.LDAP.SavedConnections) {
+			previousSaved = cfg.LDAP.SavedConnections[index].BaseDN
+		}
+
+		cfg.SetActiveBaseDN(dn)
+		m.startView.saveConfigToDisk()
+		if err := m.startView.saveError; err != nil {
+			cfg.LDAP.BaseDN = previous
+			if index >= 0 && index < len(cfg.LDAP.SavedConnections) {
+				cfg.LDAP.SavedConnections[index].BaseDN = previousSaved
+			}
+			return SendError(fmt.Errorf("base DN left at %s: %w", m.client.BaseDN(), err))
+		}
+		m.notify(ToastSuccess, fmt.Sprintf("Base DN set to %s and saved", dn))
+	} else {
+		m.notify(ToastInfo, fmt.Sprintf("Base DN set to %s for this session only", dn))
+	}
+
+	m.client.SetBaseDN(dn)
+	m.sessionBaseDN = !persist
+	return m.reloadTree()
+}
+
+// saveColumnRatio stores a table's new column ratio in the config file so the
+// widths survive terminal resizes and restarts
+func (m *Model) saveColumnRatio(msg ColumnRatioChangedMsg) tea.Cmd {
+	cfg := m.startView.config
+	column := "Attribute"
+	switch msg.Table {
+	case columnTableRecord:
+		cfg.Display.RecordColumnRatio = msg.Ratio
+	case columnTableQuery:
+		cfg.Display.QueryColumnRatio = msg.Ratio
+		column = "DN"
+	default:
+		return nil
+	}
+
+	status := columnRatioStatus(column, msg.Ratio)
+	if m.startView.configPath == "" {
+		m.notify(ToastInfo, status+" for this session")
+		return nil
+	}
+	m.startView.saveConfigToDisk()
+	if err := m.startView.saveError; err != nil {
+		m.notify(ToastInfo, status+" for this session")
+		return SendError(err)
+	}
+	m.notify(ToastSuccess, status+", saved")
+	return nil
+}
+
+// reloadTree rebuilds the tree from the client's base DN and shows it
+func (m *Model) reloadTree() tea.Cmd {
+	if m.tree == nil {
+		return nil
+	}
+	m.currentView = ViewModeTree
+	return m.tree.Reload()
+}
+
+// updateWizard forwards a message to the setup wizard. When the wizard
+// finishes its settings are saved and the connection is started; skipping it
+// leaves the start view's config form.
+func (m *Model) updateWizard(msg tea.Msg) tea.Cmd {
+	result, cmd := m.wizard.Update(msg)
+	switch result {
+	case wizardSkipped:
+		m.wizard = nil
+		m.notify(ToastInfo, "Fill in the connection settings below, then choose Connect")
+	case wizardFinished:
+		m.wizard = nil
+		m.startView.saveConfigToDisk()
+		m.notify(ToastInfo, "Settings saved, connecting...")
+		cmd = m.startConnect()
+	}
+	return cmd
+}
+
 // switchView switches to the next view
 func (m *Model) switchView() *Model {
 	switch m.currentView {
@@ -504,19 +1238,14 @@ func (m *Model) renderStatusBar() string {
 	// Create right side with connection status
 	var rightContent string
 	if m.client != nil {
-		connStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("0")).
-			Background(lipgloss.Color("10")).
-			Bold(true).
-			Padding(0, 1)
-		rightContent = connStyle.Render("🔗 Connected")
+		rightContent = m.renderConnectionIndicator()
 	} else {
 		connStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("0")).
 			Background(lipgloss.Color("9")).
 			Bold(true).
 			Padding(0, 1)
-		rightContent = connStyle.Render("❌ Disconnected")
+		rightContent = connStyle.Render(iconError.String() + " Disconnected")
 	}
 
 	// Create status message in the middle - prioritize update notifications
@@ -529,11 +1258,6 @@ func (m *Model) renderStatusBar() string {
 			Bold(true).
 			Padding(0, 1)
 		statusContent = updateStyle.Render(m.updateStatus)
-	} else if m.statusMsg != "" {
-		statusStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("15")).
-			Padding(0, 1)
-		statusContent = statusStyle.Render(m.statusMsg)
 	}
 
 	// Calculate spacing for center alignment
@@ -558,20 +1282,63 @@ func (m *Model) renderStatusBar() string {
 	return middleContent + rightContent
 }
 
+// renderConnectionIndicator shows whether the active connection is encrypted,
+// which identity it is bound as and whether it is read-only
+func (m *Model) renderConnectionIndicator() string {
+	security, encrypted := connectionSecurity(m.connection)
+	securityStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color("10")).
+		Bold(true).
+		Padding(0, 1)
+	if !encrypted {
+		securityStyle = securityStyle.Background(lipgloss.Color("11"))
+	}
+
+	connStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color("10")).
+		Bold(true).
+		Padding(0, 1)
+	indicator := m.latency.render() + securityStyle.Render(security) + connStyle.Render(iconIdentity.String()+" "+m.connection.ShortBindIdentity())
+	if m.client.ReadOnly() {
+		readOnlyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("0")).
+			Background(lipgloss.Color("12")).
+			Bold(true).
+			Padding(0, 1)
+		indicator = readOnlyStyle.Render("Read-only") + indicator
+	}
+	return indicator
+}
+
+// connectionSecurity describes the transport security of a connection and
+// reports whether it is encrypted
+func connectionSecurity(cfg ldap.Config) (string, bool) {
+	switch {
+	case cfg.UseSSL:
+		return iconSecure.String() + " LDAPS", true
+	case cfg.UseTLS:
+		return iconSecure.String() + " StartTLS", true
+	default:
+		return iconPlaintext.String() + " Plaintext", false
+	}
+}
+
 // renderTabBar creates the tab navigation bar
 func (m *Model) renderTabBar() string {
 	tabs := []struct {
 		name     string
-		emoji    string
+		icon     icon
 		key      string
 		viewMode ViewMode
 		enabled  bool
 		color    string
 	}{
-		{"Start", "🏠", "1", ViewModeStart, true, "12"},
-		{"Tree", "🌲", "2", ViewModeTree, m.client != nil, "10"},
-		{"Record", "📄", "3", ViewModeRecord, true, "11"},
-		{"Query", "🔍", "4", ViewModeQuery, m.client != nil, "13"},
+		{"Start", iconStart, "1", ViewModeStart, true, "12"},
+		{"Tree", iconTree, "2", ViewModeTree, m.client != nil, "10"},
+		{"Record", iconRecord, "3", ViewModeRecord, true, "11"},
+		{"Query", iconQuery, "4", ViewModeQuery, m.client != nil, "13"},
 	}
 
 	var tabButtons []string
@@ -605,7 +1372,7 @@ func (m *Model) renderTabBar() string {
 				BorderForeground(lipgloss.Color("8"))
 		}
 
-		tabText := fmt.Sprintf("[%s] %s %s", tab.key, tab.emoji, tab.name)
+		tabText := fmt.Sprintf("[%s] %s %s", tab.key, tab.icon, tab.name)
 		renderedTab := style.Render(tabText)
 
 		// Add clickable zone for enabled tabs
@@ -620,7 +1387,11 @@ func (m *Model) renderTabBar() string {
 	// Join tabs with small spacing
 	tabRow := lipgloss.JoinHorizontal(lipgloss.Top, tabButtons...)
 
-	// Add some spacing and instructions
+	// Add some spacing and instructions, or the open connections when there
+	// are several
+	if len(m.tabs) > 1 {
+		return tabRow + "\n" + m.renderConnectionTabs() + "\n"
+	}
 	instructions := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("8")).
 		Italic(true).
@@ -636,15 +1407,72 @@ func (m *Model) renderHelpBar() string {
 
 	switch m.currentView {
 	case ViewModeStart:
-		helpText = "Configure LDAP settings • [↑↓] navigate • [Enter] edit"
+		if m.wizard != nil {
+			helpText = "First-run setup • [Enter] next • [Esc] back • [Ctrl+X] skip to manual config"
+		} else {
+			helpText = "Configure LDAP settings • [↑↓] navigate • [Enter] edit • [Ctrl+K] switch connection • [{ }] cycle • [Ctrl+R] reconnect • [Ctrl+X] commands"
+		}
 	case ViewModeTree:
-		if m.tree != nil {
-			helpText = "Browse LDAP tree • [↑↓] navigate • [Enter] expand • [Space] view record"
+		if m.tree != nil && m.tree.deleteConfirm != nil {
+			helpText = "Delete entry • type the RDN and press [Enter] • [Esc] cancel"
+		} else if m.tree != nil && m.tree.IsFormActive() {
+			helpText = "New entry from template • [Esc] cancel"
+		} else if m.tree != nil {
+			helpText = "Browse LDAP tree • [↑↓] navigate • [Enter] expand • [G] collapse all • [Space] view record • [B] set as base • [O] open alias target • [T] new from template • [Shift+D] delete • [N] naming contexts"
+			if len(m.tree.roots) > 1 {
+				helpText += " • [[/]] previous/next context"
+			}
+			if m.sessionBaseDN {
+				helpText += " • base DN changed for this session only"
+			}
 		} else {
 			helpText = "Tree view requires LDAP connection"
 		}
 	case ViewModeRecord:
-		helpText = "View LDAP record details • [↑↓] navigate attributes"
+		if m.recordView.IsRightsOpen() {
+			helpText = "Effective rights • type an identity or leave empty for the bound user • [Enter] check • [↑↓] scroll • [Esc] close"
+		} else if m.recordView.IsEditing() {
+			helpText = "Editing attribute • [Esc] cancel"
+		} else if m.recordView.IsCopyMenuOpen() {
+			helpText = "Copy as • [↑↓] select • [Enter] copy • [R/A/L/D/B] copy that form • [Esc] cancel"
+		} else if m.recordView.IsCertificateOpen() {
+			helpText = "Certificate • [←→] previous/next value • [D] save as .der • [P] save as .pem • [Esc] close"
+		} else if m.recordView.IsInfoOpen() {
+			helpText = "Entry info • [↑↓] select • [C] copy • [I/Esc] close"
+		} else {
+			helpText = "View LDAP record details • [↑↓] navigate attributes • [C] copy • [M] copy as • [E] edit • [I] info • [P] certificate • [T] pin • [G] effective rights • [R] raw mode • [N] retry with attribute names • [B] set as base • [O] open alias target • [Space] expand • [V] value style • [H] hide empty and operational • [A] all attributes • [S] OIDs • [+/-] add/remove value • [</>] column width • [Shift+Tab] focus column • [F] copy filter • [Shift+F] query for entry • [Y] copy as code"
+			if entry := m.recordView.entry; entry != nil && len(entry.MoreValues) > 0 {
+				helpText += " • [L] load more values"
+			}
+		}
+	}
+
+	if m.switcher != nil {
+		helpText = "Switch connection • [↑↓] select • [Enter] connect • [Esc] cancel"
+		if m.client != nil {
+			helpText = "Open connection • [↑↓] select • [Enter] open in a new tab • [Esc] cancel"
+		}
+	}
+	if m.palette != nil {
+		helpText = "Command palette • type to search • [↑↓] select • [Enter] run • [Esc] cancel"
+	}
+	if m.compare != nil {
+		helpText = "Compare entries with another connection • [Esc] close"
+	}
+	if m.serverInfo != nil {
+		helpText = "Server info • [R] read the clock again • [M] monitor • [Esc] close"
+	}
+	if m.monitor != nil {
+		helpText = "Server monitor • [R] read again • [Esc] close"
+	}
+	if m.entryNotFound != nil {
+		helpText = "Entry not found • [Enter/F] search by RDN • [R] remove from tree • [Esc] close"
+	}
+	if m.baseDNPrompt != nil {
+		helpText = "Set as base DN • [Enter] this session • [S] save • [C] copy • [Esc] cancel"
+	}
+	if m.confirmQuit {
+		helpText = "Quit with unsaved changes • [Y] quit • [N/Esc] keep working"
 	}
 
 	style := lipgloss.NewStyle().
@@ -670,6 +1498,11 @@ func (m *Model) handleZoneMessage(msg zone.MsgZoneInBounds) (tea.Model, tea.Cmd)
 			return m, nil
 		}
 	}
+	for i := range m.tabs {
+		if zoneInfo := zone.Get(fmt.Sprintf("conn-tab-%d", i)); zoneInfo != nil && zoneInfo.InBounds(msg.Event) {
+			return m, m.switchTab(i)
+		}
+	}
 
 	// Forward to current view's zone handler based on view mode
 	switch m.currentView {
@@ -695,10 +1528,10 @@ func (m *Model) handleZoneMessage(msg zone.MsgZoneInBounds) (tea.Model, tea.Cmd)
 
 	case ViewModeRecord:
 		// Check for record row clicks
-		for i := 0; i < 100; i++ { // reasonable upper bound for attributes
+		for i := 0; i < len(m.recordView.renderedRows); i++ {
 			zoneID := fmt.Sprintf("record-row-%d", i)
 			if zoneInfo := zone.Get(zoneID); zoneInfo != nil && zoneInfo.InBounds(msg.Event) {
-				return m.handleRecordViewClick(zoneID)
+				return m.handleRecordViewClick(zoneID, msg.Event)
 			}
 		}
 
@@ -760,14 +1593,19 @@ func (m *Model) handleTreeViewClick(zoneID string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// handleRecordViewClick handles clicks in the record view
-func (m *Model) handleRecordViewClick(zoneID string) (tea.Model, tea.Cmd) {
+// handleRecordViewClick handles clicks in the record view. The first click selects
+// a row; clicking the selected row again, or modifier-clicking any row, copies its value.
+func (m *Model) handleRecordViewClick(zoneID string, event tea.MouseMsg) (tea.Model, tea.Cmd) {
 	// Handle record row clicks - match the zone ID format used in record.go
 	if len(zoneID) > 11 && zoneID[:11] == "record-row-" {
 		if attrIndex, err := strconv.Atoi(zoneID[11:]); err == nil {
 			// Set table cursor to clicked attribute
 			if attrIndex < len(m.recordView.renderedRows) {
+				alreadySelected := m.recordView.table.Cursor() == attrIndex
 				m.recordView.table.SetCursor(attrIndex)
+				if alreadySelected || event.Ctrl || event.Alt {
+					return m, m.recordView.copyCurrentValue()
+				}
 			}
 		}
 	}
@@ -785,7 +1623,7 @@ func (m *Model) handleQueryViewClick(zoneID string) (tea.Model, tea.Cmd) {
 		if lineIndex, err := strconv.Atoi(zoneID[13:]); err == nil {
 			// Since we're using a table now, we need to set the table cursor
 			// The lineIndex should correspond to the row index
-			if lineIndex < len(m.queryView.results) {
+			if lineIndex < len(m.queryView.results) && m.queryView.snapshot == nil {
 				// Set the table cursor to the clicked row
 				// Note: We can't directly set table cursor, but we can trigger selection
 				// For now, let's just show the record directly
@@ -801,9 +1639,10 @@ type ErrorMsg struct {
 	Err error
 }
 
-// StatusMsg represents a status message
+// StatusMsg represents a status message, shown as a toast of its level
 type StatusMsg struct {
 	Message string
+	Level   ToastLevel
 }
 
 // ShowRecordMsg represents a message to show a record
@@ -825,6 +1664,13 @@ func SendStatus(message string) tea.Cmd {
 	}
 }
 
+// SendSuccess sends a status message confirming that something was done
+func SendSuccess(message string) tea.Cmd {
+	return func() tea.Msg {
+		return StatusMsg{Message: message, Level: ToastSuccess}
+	}
+}
+
 // ShowRecord sends a message to show a record
 func ShowRecord(entry *ldap.Entry) tea.Cmd {
 	return func() tea.Msg {
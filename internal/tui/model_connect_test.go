@@ -6,6 +6,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
 )
 
 // TestModel_ConnectFlow tests the complete connection flow from StartView to Model
@@ -29,7 +31,13 @@ func TestModel_ConnectFlow(t *testing.T) {
 	m.startView.cursor = FieldConnect
 
 	// Simulate pressing Enter on Connect button
-	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// The password would go out unencrypted, so that is confirmed first
+	if !m.startView.confirmingPlaintextBind() {
+		t.Fatal("Expected the clear-text bind warning before connecting")
+	}
+	updatedModel, cmd := m.Update(keyRune('y'))
 
 	if cmd == nil {
 		t.Fatal("Expected command to be returned when Connect is pressed")
@@ -94,7 +102,156 @@ func TestModel_StatusMsgHandling(t *testing.T) {
 	updatedModel, _ := m.Update(testMsg)
 
 	resultModel := updatedModel.(*Model)
-	if resultModel.statusMsg != "Test status message" {
-		t.Errorf("Expected statusMsg to be set to 'Test status message', got: %s", resultModel.statusMsg)
+	if resultModel.toasts.latest() != "Test status message" {
+		t.Errorf("Expected a toast of 'Test status message', got: %s", resultModel.toasts.latest())
+	}
+}
+
+// runCmd runs cmd and the commands of any batch it returns, collecting the
+// messages they produce
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, cmd := range batch {
+			msgs = append(msgs, runCmd(cmd)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func TestModel_AutoConnect(t *testing.T) {
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com", Attributes: map[string][]string{"dc": {"example"}}})
+	cfg := config.Default()
+	cfg.LDAP.Host = "127.0.0.1"
+	cfg.LDAP.Port = server.Port()
+	cfg.LDAP.BaseDN = "dc=example,dc=com"
+	cfg.LDAP.AutoConnect = true
+	cfg.Display.TreeChildFilter = "(!(objectClass=computer))"
+	m := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, "")
+
+	var connect *ConnectMsg
+	for _, msg := range runCmd(m.Init()) {
+		if msg, ok := msg.(ConnectMsg); ok {
+			connect = &msg
+		}
+	}
+	if connect == nil {
+		t.Fatal("Expected a complete connection to be connected to on init")
+	}
+	defer connect.Client.Close()
+
+	m.Update(*connect)
+	if m.currentView != ViewModeTree {
+		t.Errorf("Expected to land on the tree view, got %v", m.currentView)
+	}
+	if got := connect.Client.TreeChildFilter(); got != cfg.Display.TreeChildFilter {
+		t.Errorf("Expected the tree to list children with the configured filter, got %q", got)
+	}
+}
+
+func TestModel_ConnectAnonymousRefused(t *testing.T) {
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	server.RefuseAnonymous = 48 // inappropriateAuthentication
+	cfg := config.Default()
+	cfg.LDAP.Host = "127.0.0.1"
+	cfg.LDAP.Port = server.Port()
+	cfg.LDAP.BaseDN = "dc=example,dc=com"
+	m := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, "")
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	var refused *AuthenticationRequiredMsg
+	for _, msg := range runCmd(m.startConnect()) {
+		if msg, ok := msg.(AuthenticationRequiredMsg); ok {
+			refused = &msg
+		}
+	}
+	if refused == nil {
+		t.Fatal("Expected the refused anonymous bind to be recognised")
+	}
+
+	m.currentView = ViewModeTree
+	m.Update(*refused)
+	if m.currentView != ViewModeStart || m.startView.cursor != FieldBindUser {
+		t.Errorf("Expected the start view with the bind user selected, got view %v, field %d", m.currentView, m.startView.cursor)
+	}
+	if got := m.toasts.latest(); got != "Connection failed: This server requires authentication" {
+		t.Errorf("Unexpected toast %q", got)
+	}
+	if view := m.startView.renderInstructions(); !strings.Contains(view, "set a bind user and password") {
+		t.Errorf("Expected guidance in the start view, got:\n%s", view)
+	}
+
+	// The guidance lasts until the next attempt
+	cfg.LDAP.BindUser = "cn=admin,dc=example,dc=com"
+	cfg.LDAP.BindPass = "secret"
+	m.startConnect()
+	if len(m.startView.connectProblems) != 0 {
+		t.Errorf("Expected the guidance cleared on the next connect, got %v", m.startView.connectProblems)
+	}
+}
+
+func TestModel_AutoConnectSkipped(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *config.Config)
+	}{
+		{"not enabled", func(cfg *config.Config) { cfg.LDAP.AutoConnect = false }},
+		{"missing password", func(cfg *config.Config) { cfg.LDAP.BindPass = "" }},
+		{"missing base DN", func(cfg *config.Config) { cfg.LDAP.BaseDN = "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.LDAP.Host = "127.0.0.1"
+			cfg.LDAP.Port = 1
+			cfg.LDAP.BaseDN = "dc=example,dc=com"
+			cfg.LDAP.BindUser = "cn=admin,dc=example,dc=com"
+			cfg.LDAP.BindPass = "secret"
+			cfg.LDAP.AutoConnect = true
+			tt.modify(cfg)
+			m := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, "")
+
+			if cmd := m.autoConnect(); cmd != nil {
+				t.Error("Expected no connection attempt")
+			}
+			if m.currentView != ViewModeStart {
+				t.Errorf("Expected the start view, got %v", m.currentView)
+			}
+		})
+	}
+}
+
+func TestModel_Reconnect(t *testing.T) {
+	cfg := config.Default()
+	cfg.LDAP.Host = "127.0.0.1"
+	cfg.LDAP.Port = 1 // Nothing listens here, so the attempt fails at once
+	cfg.LDAP.BaseDN = "dc=example,dc=com"
+	m := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, "")
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR}); cmd != nil {
+		t.Error("Expected no connect attempt before connecting")
+	}
+	if !strings.Contains(m.toasts.latest(), "Not connected") {
+		t.Errorf("Expected a hint to connect first, got %q", m.toasts.latest())
+	}
+
+	m.client = &ldap.Client{}
+	m.connection = ldap.Config{Host: "127.0.0.1", ConnectionName: "Production"}
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if cmd == nil {
+		t.Fatal("Expected Ctrl+R to start a connect attempt")
+	}
+	if m.toasts.latest() != "Reconnecting to Production..." {
+		t.Errorf("Unexpected status %q", m.toasts.latest())
+	}
+
+	msg, ok := cmd().(StatusMsg)
+	if !ok || !strings.HasPrefix(msg.Message, "Connection failed") {
+		t.Errorf("Expected the failure to be reported like a normal connect, got %#v", msg)
 	}
 }
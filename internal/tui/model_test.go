@@ -1,11 +1,14 @@
 package tui
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbletea"
 	"github.com/ericschmar/moribito/internal/config"
 	"github.com/ericschmar/moribito/internal/ldap"
+	goldap "github.com/go-ldap/ldap/v3"
 )
 
 func TestModel_NavigationKeysWithQueryInputMode(t *testing.T) {
@@ -249,7 +252,7 @@ func TestModel_TreeLoadingHandledRegardlessOfCurrentView(t *testing.T) {
 		Children: nil,
 		IsLoaded: false,
 	}
-	rootLoadedMsg := RootNodeLoadedMsg{Node: mockTreeNode}
+	rootLoadedMsg := RootNodeLoadedMsg{Roots: []*ldap.TreeNode{mockTreeNode}}
 
 	// Update the model with the message
 	_, _ = model.Update(rootLoadedMsg)
@@ -259,7 +262,7 @@ func TestModel_TreeLoadingHandledRegardlessOfCurrentView(t *testing.T) {
 		t.Error("Tree should not be in loading state after RootNodeLoadedMsg")
 	}
 
-	if model.tree.root != mockTreeNode {
+	if len(model.tree.roots) != 1 || model.tree.roots[0] != mockTreeNode {
 		t.Error("Tree root should have been set to the loaded node")
 	}
 
@@ -304,3 +307,48 @@ func TestModel_NodeChildrenLoadingHandledRegardlessOfCurrentView(t *testing.T) {
 		t.Error("Tree should not be in loading state after NodeChildrenLoadedMsg")
 	}
 }
+
+func TestModel_ErrorsAreExplainedInStatusBar(t *testing.T) {
+	model := NewModel(nil, config.Default())
+
+	raw := goldap.NewError(goldap.LDAPResultInsufficientAccessRights, errors.New("00002098: SecErr: DSID-03150E2E, problem 4003 (INSUFF_ACCESS_RIGHTS), data 0"))
+	model.Update(ErrorMsg{Err: raw})
+
+	if !strings.HasPrefix(model.toasts.latest(), "Error: Insufficient access rights. ") {
+		t.Errorf("Expected an explanation in the status bar, got %q", model.toasts.latest())
+	}
+	if !strings.Contains(model.toasts.latest(), "00002098") {
+		t.Errorf("Expected the raw error to be kept, got %q", model.toasts.latest())
+	}
+	if model.err != raw {
+		t.Error("Expected the raw error to be stored")
+	}
+}
+
+func TestModel_ConnectionIndicator(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ldap.Config
+		want   []string
+	}{
+		{"ldaps", ldap.Config{UseSSL: true, BindUser: "cn=admin,dc=example,dc=com"}, []string{"🔒 LDAPS", "🔗 admin"}},
+		{"starttls", ldap.Config{UseTLS: true, BindUser: "alice@example.com"}, []string{"🔒 StartTLS", "🔗 alice@example.com"}},
+		{"plaintext", ldap.Config{}, []string{"⚠ 🔓 Plaintext", "🔗 anonymous"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(nil, config.Default())
+			model.SetSize(120, 30)
+			model.client = &ldap.Client{}
+			model.connection = tt.config
+
+			bar := model.renderStatusBar()
+			for _, want := range tt.want {
+				if !strings.Contains(bar, want) {
+					t.Errorf("Expected status bar to contain %q, got %q", want, bar)
+				}
+			}
+		})
+	}
+}
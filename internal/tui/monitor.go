@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// monitorInterval is how often the monitor dashboard reads the statistics again
+const monitorInterval = 5 * time.Second
+
+// MonitorStatsMsg is sent when the monitor statistics have been read for
+// Dashboard
+type MonitorStatsMsg struct {
+	Dashboard *MonitorDashboard
+	Stats     map[string]string
+	Read      time.Time
+	Err       error
+}
+
+// monitorTickMsg starts the next scheduled read of Dashboard, unless a read
+// since has scheduled another
+type monitorTickMsg struct {
+	Dashboard *MonitorDashboard
+	Seq       int
+}
+
+// MonitorDashboard is an overlay showing the server's operational statistics
+// from cn=monitor, read again every few seconds while it is open
+type MonitorDashboard struct {
+	client     *ldap.Client
+	connection ldap.Config
+
+	loading  bool
+	stats    map[string]string
+	previous map[string]string // Statistics of the read before, for the change since
+	read     time.Time
+	err      error
+	seq      int // Identifies the scheduled read, see monitorTickMsg
+}
+
+// NewMonitorDashboard creates the dashboard for client and starts the first read
+func NewMonitorDashboard(client *ldap.Client, connection ldap.Config) (*MonitorDashboard, tea.Cmd) {
+	dashboard := &MonitorDashboard{client: client, connection: connection, loading: true}
+	return dashboard, dashboard.readCmd()
+}
+
+// readCmd reads the statistics in the background
+func (md *MonitorDashboard) readCmd() tea.Cmd {
+	client := md.client
+	return func() tea.Msg {
+		stats, err := client.GetMonitorStats()
+		return MonitorStatsMsg{Dashboard: md, Stats: stats, Read: time.Now(), Err: err}
+	}
+}
+
+// tickCmd schedules the next read, replacing any scheduled before
+func (md *MonitorDashboard) tickCmd() tea.Cmd {
+	md.seq++
+	seq := md.seq
+	return tea.Tick(monitorInterval, func(time.Time) tea.Msg {
+		return monitorTickMsg{Dashboard: md, Seq: seq}
+	})
+}
+
+// tick starts the scheduled read msg is for
+func (md *MonitorDashboard) tick(msg monitorTickMsg) tea.Cmd {
+	if msg.Seq != md.seq || md.loading {
+		return nil
+	}
+	md.loading = true
+	return md.readCmd()
+}
+
+// Update handles a key press. It returns true when the overlay should close.
+func (md *MonitorDashboard) Update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+g":
+		return true, nil
+	case "r", "R":
+		if md.loading {
+			return false, nil
+		}
+		md.loading = true
+		return false, md.readCmd()
+	}
+	return false, nil
+}
+
+// setStats shows the result of a read and schedules the next one. A server
+// without a monitor backend isn't asked again.
+func (md *MonitorDashboard) setStats(msg MonitorStatsMsg) tea.Cmd {
+	md.loading = false
+	md.read, md.err = msg.Read, msg.Err
+	if msg.Err != nil {
+		if errors.Is(msg.Err, ldap.ErrMonitorUnavailable) {
+			return nil
+		}
+		return md.tickCmd()
+	}
+	if md.stats != nil {
+		md.previous = md.stats
+	}
+	md.stats = msg.Stats
+	return md.tickCmd()
+}
+
+// change describes how a counter moved since the previous read, such as
+// "+12", or "" when it didn't or isn't a number
+func (md *MonitorDashboard) change(name string) string {
+	now, err := strconv.ParseInt(md.stats[name], 10, 64)
+	if err != nil {
+		return ""
+	}
+	before, err := strconv.ParseInt(md.previous[name], 10, 64)
+	if err != nil || before == now {
+		return ""
+	}
+	return fmt.Sprintf("%+d", now-before)
+}
+
+// View renders the overlay as a bordered box
+func (md *MonitorDashboard) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	detailStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+	row := func(label, value string) string {
+		return editorLabelStyle.Render(fmt.Sprintf("%-20s", label)) + " " + value
+	}
+
+	sections := []string{titleStyle.Render("Server monitor"), ""}
+	sections = append(sections, row("Server", net.JoinHostPort(md.connection.Host, strconv.Itoa(md.connection.Port))))
+
+	unavailable := errors.Is(md.err, ldap.ErrMonitorUnavailable)
+	switch {
+	case unavailable:
+		sections = append(sections, "", detailStyle.Render("This server doesn't publish statistics under cn=monitor."),
+			detailStyle.Render("OpenLDAP needs the monitor backend enabled; 389 Directory Server has it built in."))
+	case md.stats == nil && md.loading:
+		sections = append(sections, "", lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Italic(true).
+			Render(iconLoading.String()+" Reading cn=monitor..."))
+	default:
+		sections = append(sections, "")
+		for _, name := range ldap.MonitorStatNames {
+			value, ok := md.stats[name]
+			if !ok {
+				continue
+			}
+			if change := md.change(name); change != "" {
+				value += " " + detailStyle.Render("("+change+")")
+			}
+			sections = append(sections, row(name, value))
+		}
+		if md.err != nil {
+			sections = append(sections, "", editorErrorStyle.Render(fmt.Sprintf("%s %v", iconError, ldap.Explain(md.err))))
+		}
+		if !md.read.IsZero() {
+			sections = append(sections, "", detailStyle.Render(fmt.Sprintf("Read at %s, again every %s", md.read.Format(time.TimeOnly), monitorInterval)))
+		}
+	}
+
+	help := "[R] read again • [Esc] close"
+	if unavailable {
+		help = "[Esc] close"
+	}
+	sections = append(sections, "", helpStyle.Render(help))
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("6")).
+		Padding(0, 1).
+		Render(strings.Join(sections, "\n"))
+}
+
+// openMonitor shows the monitor dashboard for the active connection
+func (m *Model) openMonitor() tea.Cmd {
+	if m.client == nil {
+		m.notify(ToastInfo, "Connect to a server to see its statistics")
+		return nil
+	}
+	m.serverInfo = nil
+	var cmd tea.Cmd
+	m.monitor, cmd = NewMonitorDashboard(m.client, m.connection)
+	return cmd
+}
+
+// handleMonitorKey forwards a key to the monitor dashboard
+func (m *Model) handleMonitorKey(msg tea.KeyMsg) tea.Cmd {
+	done, cmd := m.monitor.Update(msg)
+	if done {
+		m.monitor = nil
+	}
+	return cmd
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+// newMonitorModel connects a model to a server holding entries
+func newMonitorModel(t *testing.T, entries ...ldaptest.Entry) *Model {
+	t.Helper()
+	server := ldaptest.NewServer(t, entries...)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port()})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	model := NewModel(nil, config.Default())
+	model.client = client
+	model.connection = client.Config()
+	model.width, model.height = 120, 40
+	return model
+}
+
+func TestModel_MonitorDashboard(t *testing.T) {
+	model := newMonitorModel(t, ldaptest.Entry{DN: "cn=monitor", Attributes: map[string][]string{
+		"version":            {"389-Directory/2.4.5"},
+		"currentconnections": {"7"},
+		"opscompleted":       {"1519"},
+	}})
+
+	// Opened from the server info overlay
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	_, cmd := model.Update(keyRune('m'))
+	if model.monitor == nil || model.serverInfo != nil {
+		t.Fatal("Expected M to replace server info with the monitor dashboard")
+	}
+	_, next := model.Update(cmd())
+	if next == nil {
+		t.Error("Expected the next read to be scheduled")
+	}
+	view := ansi.Strip(model.monitor.View())
+	for _, want := range []string{"Server monitor", "Current connections  7", "Operations completed 1519", "Version              389-Directory/2.4.5", "again every 5s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the dashboard, got:\n%s", want, view)
+		}
+	}
+
+	// Each read shows how the counters moved since the one before
+	stats := map[string]string{ldap.MonitorCurrentConnections: "5", ldap.MonitorOperationsCompleted: "1600", ldap.MonitorVersion: "389-Directory/2.4.5"}
+	model.Update(MonitorStatsMsg{Dashboard: model.monitor, Stats: stats})
+	view = ansi.Strip(model.monitor.View())
+	if !strings.Contains(view, "5 (-2)") || !strings.Contains(view, "1600 (+81)") {
+		t.Errorf("Expected the changes shown, got:\n%s", view)
+	}
+
+	// Ticks scheduled before the latest read are dropped
+	if cmd := model.monitor.tick(monitorTickMsg{Dashboard: model.monitor, Seq: model.monitor.seq - 1}); cmd != nil {
+		t.Error("Expected an outdated tick to be ignored")
+	}
+	if cmd := model.monitor.tick(monitorTickMsg{Dashboard: model.monitor, Seq: model.monitor.seq}); cmd == nil {
+		t.Error("Expected the scheduled tick to read again")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.monitor != nil {
+		t.Error("Expected Esc to close the dashboard")
+	}
+}
+
+func TestModel_MonitorDashboardUnavailable(t *testing.T) {
+	model := newMonitorModel(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	cmd := model.openMonitor()
+	if _, next := model.Update(cmd()); next != nil {
+		t.Error("Expected a server without a monitor not to be asked again")
+	}
+	if view := ansi.Strip(model.monitor.View()); !strings.Contains(view, "doesn't publish statistics under cn=monitor") {
+		t.Errorf("Expected the missing monitor explained, got:\n%s", view)
+	}
+}
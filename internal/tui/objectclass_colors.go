@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// entryKind groups entries by their primary objectClass for colouring
+type entryKind int
+
+const (
+	entryKindOther entryKind = iota
+	entryKindPerson
+	entryKindGroup
+	entryKindOU
+	entryKindComputer
+)
+
+// entryKindInfo describes how an entry kind is recognised and displayed
+type entryKindInfo struct {
+	Kind          entryKind
+	Label         string
+	Color         lipgloss.Color
+	ObjectClasses []string // Lowercased objectClass values that identify the kind
+}
+
+// entryKinds lists the recognised kinds in match order. Computers come first
+// because Active Directory computer objects also carry the user/person classes.
+var entryKinds = []entryKindInfo{
+	{entryKindComputer, "computer", lipgloss.Color("10"), []string{"computer", "device", "iphost"}},
+	{entryKindGroup, "group", lipgloss.Color("13"), []string{"group", "groupofnames", "groupofuniquenames", "posixgroup", "groupofurls"}},
+	{entryKindPerson, "person", lipgloss.Color("12"), []string{"person", "organizationalperson", "inetorgperson", "user", "posixaccount"}},
+	{entryKindOU, "organizationalUnit", lipgloss.Color("11"), []string{"organizationalunit", "organization", "container", "domain", "dcobject"}},
+}
+
+// classifyEntry returns the kind of entry based on its objectClass values
+func classifyEntry(entry *ldap.Entry) entryKind {
+	if entry == nil {
+		return entryKindOther
+	}
+
+	classes := make(map[string]bool)
+	for name, values := range entry.Attributes {
+		if !strings.EqualFold(name, "objectClass") {
+			continue
+		}
+		for _, value := range values {
+			classes[strings.ToLower(strings.TrimSpace(value))] = true
+		}
+	}
+
+	for _, info := range entryKinds {
+		for _, class := range info.ObjectClasses {
+			if classes[class] {
+				return info.Kind
+			}
+		}
+	}
+	return entryKindOther
+}
+
+// entryKindStyle returns the foreground style for an entry kind. Unrecognised
+// entries keep the default style.
+func entryKindStyle(kind entryKind) lipgloss.Style {
+	for _, info := range entryKinds {
+		if info.Kind == kind {
+			return lipgloss.NewStyle().Foreground(info.Color)
+		}
+	}
+	return lipgloss.NewStyle()
+}
+
+// renderEntryKindLegend renders a one-line key of the entry kind colours
+func renderEntryKindLegend() string {
+	parts := make([]string, 0, len(entryKinds))
+	for _, info := range entryKinds {
+		parts = append(parts, lipgloss.NewStyle().Foreground(info.Color).Render("● "+info.Label))
+	}
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func entryWithClasses(dn string, classes ...string) *ldap.Entry {
+	return &ldap.Entry{DN: dn, Attributes: map[string][]string{"objectClass": classes}}
+}
+
+func TestClassifyEntry(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry *ldap.Entry
+		want  entryKind
+	}{
+		{"person", entryWithClasses("uid=jdoe", "top", "person", "inetOrgPerson"), entryKindPerson},
+		{"group", entryWithClasses("cn=admins", "top", "groupOfNames"), entryKindGroup},
+		{"ou", entryWithClasses("ou=people", "top", "organizationalUnit"), entryKindOU},
+		{"ad computer also has user classes", entryWithClasses("cn=ws01", "top", "person", "user", "computer"), entryKindComputer},
+		{"case insensitive", &ldap.Entry{Attributes: map[string][]string{"objectclass": {"PosixGroup"}}}, entryKindGroup},
+		{"unknown", entryWithClasses("cn=thing", "top", "applicationProcess"), entryKindOther},
+		{"no objectClass", &ldap.Entry{DN: "cn=x"}, entryKindOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyEntry(tt.entry); got != tt.want {
+				t.Errorf("Expected kind %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func newColoredQueryView() *QueryView {
+	qv := NewQueryView(nil)
+	qv.SetSize(100, 40)
+	qv.SetResults([]*ldap.Entry{
+		entryWithClasses("uid=jdoe,ou=people,dc=example,dc=com", "inetOrgPerson"),
+		entryWithClasses("cn=admins,ou=groups,dc=example,dc=com", "groupOfNames"),
+		entryWithClasses("ou=people,dc=example,dc=com", "organizationalUnit"),
+	})
+	return qv
+}
+
+func TestQueryView_ColorRowsToggle(t *testing.T) {
+	qv := newColoredQueryView()
+	if len(qv.rowKinds) != 3 || qv.rowKinds[1] != entryKindGroup {
+		t.Fatalf("Expected row kinds to follow results, got %v", qv.rowKinds)
+	}
+
+	view := qv.renderTable()
+	if !strings.Contains(ansi.Strip(view), "● person") {
+		t.Error("Expected a legend while colouring is enabled")
+	}
+
+	// Switch to browse mode and toggle colouring off
+	qv.inputMode = false
+	qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if qv.colorRows {
+		t.Fatal("Expected 'c' to disable colouring")
+	}
+	if strings.Contains(ansi.Strip(qv.renderTable()), "● person") {
+		t.Error("Expected no legend in monochrome mode")
+	}
+}
+
+func TestQueryView_RenderTableKeepsRowsAndWidth(t *testing.T) {
+	qv := newColoredQueryView()
+	view := ansi.Strip(qv.renderTable())
+
+	for _, entry := range qv.results {
+		if !strings.Contains(view, entry.DN[:10]) {
+			t.Errorf("Expected %s in the results table", entry.DN)
+		}
+	}
+	for _, line := range strings.Split(qv.renderTable(), "\n") {
+		if w := lipgloss.Width(line); w > 100 {
+			t.Errorf("Line exceeds view width (%d): %q", w, line)
+		}
+	}
+}
+
+func TestQueryView_RenderTableScrollsWithCursor(t *testing.T) {
+	qv := NewQueryView(nil)
+	qv.SetSize(100, 20)
+	var entries []*ldap.Entry
+	for i := 0; i < 30; i++ {
+		entries = append(entries, entryWithClasses("cn=entry"+string(rune('A'+i))+",dc=example,dc=com", "person"))
+	}
+	qv.SetResults(entries)
+
+	qv.table.SetCursor(25)
+	view := ansi.Strip(qv.renderTable())
+	if !strings.Contains(view, "cn=entry"+string(rune('A'+25))) {
+		t.Error("Expected the selected row to be visible")
+	}
+	if strings.Contains(view, "cn=entryA,") {
+		t.Error("Expected the first row to scroll out of view")
+	}
+}
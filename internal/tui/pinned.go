@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// pinnedAttributes are shown in the pinned-entry panel, the first
+// pinnedAttributeCount of them the entry has
+var pinnedAttributes = []string{"cn", "uid", "mail", "displayName", "ou", "description"}
+
+const pinnedAttributeCount = 2
+
+// PinEntryMsg asks to pin Entry, or to unpin it if it is already pinned
+type PinEntryMsg struct {
+	Entry *ldap.Entry
+}
+
+// PinEntry sends a message toggling the pin on entry
+func PinEntry(entry *ldap.Entry) tea.Cmd {
+	return func() tea.Msg {
+		return PinEntryMsg{Entry: entry}
+	}
+}
+
+// togglePin pins entry so that it stays in view while navigating, or unpins
+// it if it is the pinned entry
+func (m *Model) togglePin(entry *ldap.Entry) {
+	if entry == nil {
+		m.notify(ToastError, "Error: no record selected")
+		return
+	}
+	if m.pinned != nil && m.pinned.DN == entry.DN {
+		m.pinned, m.pinnedTab = nil, nil
+		m.notify(ToastInfo, "Unpinned "+entry.DN)
+		return
+	}
+	m.pinned = entry
+	m.pinnedTab = nil
+	if m.activeTab < len(m.tabs) {
+		m.pinnedTab = m.tabs[m.activeTab]
+	}
+	m.notify(ToastSuccess, "Pinned "+entry.DN+", press [Ctrl+B] to return to it")
+}
+
+// openPinned shows the pinned entry in full, switching back to the tab it
+// was pinned in
+func (m *Model) openPinned() tea.Cmd {
+	if m.pinned == nil {
+		m.notify(ToastInfo, "No entry pinned; press [T] in the record view to pin one")
+		return nil
+	}
+	if m.pinnedTab != nil {
+		index := -1
+		for i, tab := range m.tabs {
+			if tab == m.pinnedTab {
+				index = i
+			}
+		}
+		if index < 0 {
+			dn := m.pinned.DN
+			m.pinned, m.pinnedTab = nil, nil
+			return SendError(fmt.Errorf("the connection %s was pinned in has been closed", dn))
+		}
+		if index != m.activeTab {
+			m.storeTab()
+			m.showTab(index)
+		}
+	}
+	return ShowRecord(m.pinned)
+}
+
+// showsPinned reports whether the pinned entry is already in full view
+func (m *Model) showsPinned() bool {
+	return m.currentView == ViewModeRecord && m.recordView.entry != nil && m.recordView.entry.DN == m.pinned.DN
+}
+
+// renderPinned draws the pinned-entry panel, at most width wide: the DN and
+// a few identifying attributes
+func (m *Model) renderPinned(width int) string {
+	innerWidth := max(width-4, 10)
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Render("Pinned") + lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(" • [Ctrl+B] open"),
+		ansi.Truncate(m.pinned.DN, innerWidth, "…"),
+	}
+	shown := 0
+	for _, name := range pinnedAttributes {
+		if shown == pinnedAttributeCount {
+			break
+		}
+		for attribute, values := range m.pinned.Attributes {
+			if strings.EqualFold(attribute, name) && len(values) > 0 {
+				line := attribute + ": " + strings.Join(values, ", ")
+				lines = append(lines, ansi.Truncate(line, innerWidth, "…"))
+				shown++
+				break
+			}
+		}
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("12")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// overlayPinned draws the pinned-entry panel right-aligned over the first
+// lines of content, at most maxLines of them, so the layout doesn't move. The
+// record view showing the pinned entry needs no panel.
+func (m *Model) overlayPinned(contentLines []string, maxLines int) []string {
+	if m.pinned == nil || m.showsPinned() {
+		return contentLines
+	}
+	panel := strings.Split(m.renderPinned(min(m.width/2, 60)), "\n")
+	for i, row := range panel {
+		if i >= maxLines {
+			break
+		}
+		if i == len(contentLines) {
+			contentLines = append(contentLines, "")
+		}
+		left := max(m.width-lipgloss.Width(row)-1, 0)
+		line := ansi.Truncate(contentLines[i], left, "")
+		if gap := left - lipgloss.Width(line); gap > 0 {
+			line += strings.Repeat(" ", gap)
+		}
+		contentLines[i] = line + row
+	}
+	return contentLines
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func TestModel_PinnedEntry(t *testing.T) {
+	zone.NewGlobal()
+	model := NewModel(nil, config.Default())
+	model.SetSize(140, 30)
+	entry := &ldap.Entry{
+		DN:         "uid=jdoe,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{"objectClass": {"person"}, "cn": {"John Doe"}, "mail": {"jdoe@example.com"}},
+	}
+	model.Update(ShowRecordMsg{Entry: entry})
+
+	_, cmd := model.Update(keyRune('t'))
+	if cmd == nil {
+		t.Fatal("Expected T to pin the entry")
+	}
+	model.Update(cmd())
+	if model.pinned != entry {
+		t.Fatalf("Expected the model to keep the entry, got %v", model.pinned)
+	}
+
+	// The panel follows to other views, but not to the record it shows
+	if view := ansi.Strip(model.View()); strings.Contains(view, "[Ctrl+B] open") {
+		t.Errorf("Expected no panel over the pinned record, got:\n%s", view)
+	}
+	model.Update(keyRune('2'))
+	view := ansi.Strip(model.View())
+	for _, want := range []string{"Pinned • [Ctrl+B] open", entry.DN, "cn: John Doe", "mail: jdoe@example.com"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the tree view, got:\n%s", want, view)
+		}
+	}
+
+	// Ctrl+B brings back the pinned record after another was opened
+	model.Update(ShowRecordMsg{Entry: &ldap.Entry{DN: "uid=other,dc=example,dc=com"}})
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if cmd == nil {
+		t.Fatal("Expected Ctrl+B to open the pinned entry")
+	}
+	model.Update(cmd())
+	if model.currentView != ViewModeRecord || model.recordView.entry != entry {
+		t.Errorf("Expected the pinned record shown, got %v", model.recordView.entry)
+	}
+
+	// Pinning it again unpins it
+	_, cmd = model.Update(keyRune('t'))
+	model.Update(cmd())
+	if model.pinned != nil {
+		t.Error("Expected T on the pinned entry to unpin it")
+	}
+	model.Update(keyRune('2'))
+	if view := ansi.Strip(model.View()); strings.Contains(view, "Pinned") {
+		t.Errorf("Expected the panel gone, got:\n%s", view)
+	}
+}
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ProgressTickMsg refreshes the progress display of a long-running operation
+type ProgressTickMsg struct {
+	Time time.Time
+}
+
+// progressTickCmd schedules the next progress refresh
+func progressTickCmd() tea.Cmd {
+	return tea.Tick(250*time.Millisecond, func(t time.Time) tea.Msg {
+		return ProgressTickMsg{Time: t}
+	})
+}
+
+// progressSpinner frames are shown when the total amount of work is unknown
+var progressSpinner = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// operationProgress counts the entries a long-running operation has
+// processed. LDAP rarely reports totals up front, so total may be zero, in
+// which case a counter with the rate is shown instead of a bar.
+type operationProgress struct {
+	done    int
+	total   int
+	started time.Time
+	now     time.Time // Time of the last refresh
+}
+
+// newOperationProgress starts tracking an operation over total entries, or an
+// unknown number when total is zero
+func newOperationProgress(total int) *operationProgress {
+	now := time.Now()
+	return &operationProgress{total: total, started: now, now: now}
+}
+
+// Advance records that one more entry was processed
+func (p *operationProgress) Advance() {
+	p.AdvanceBy(1)
+}
+
+// AdvanceBy records that n more entries were processed, such as a page of
+// search results
+func (p *operationProgress) AdvanceBy(n int) {
+	p.done += n
+	p.now = time.Now()
+}
+
+// Tick refreshes the elapsed time
+func (p *operationProgress) Tick(now time.Time) {
+	p.now = now
+}
+
+// elapsed returns how long the operation has been running
+func (p *operationProgress) elapsed() time.Duration {
+	return p.now.Sub(p.started)
+}
+
+// rate returns the entries processed per second
+func (p *operationProgress) rate() float64 {
+	seconds := p.elapsed().Seconds()
+	if seconds <= 0 {
+		return 0
+	}
+	return float64(p.done) / seconds
+}
+
+// View renders the progress within width columns
+func (p *operationProgress) View(width int) string {
+	stats := fmt.Sprintf("%.1fs • %.1f/s", p.elapsed().Seconds(), p.rate())
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+
+	if p.total <= 0 {
+		frame := progressSpinner[int(p.elapsed()/(100*time.Millisecond))%len(progressSpinner)]
+		return truncateToWidth(style.Render(fmt.Sprintf("%s %d entries • %s", frame, p.done, stats)), width)
+	}
+
+	counts := fmt.Sprintf(" %d of %d entries • %s", p.done, p.total, stats)
+	barWidth := width - lipgloss.Width(counts) - 2
+	if barWidth > 40 {
+		barWidth = 40
+	}
+	if barWidth < 10 {
+		barWidth = 10
+	}
+	filled := barWidth * p.done / p.total
+	if filled > barWidth {
+		filled = barWidth
+	}
+	bar := lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(strings.Repeat("░", barWidth-filled))
+	return truncateToWidth("["+bar+"]"+style.Render(counts), width)
+}
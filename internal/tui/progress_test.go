@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestOperationProgress_KnownTotal(t *testing.T) {
+	p := newOperationProgress(4)
+	p.Advance()
+	p.Advance()
+	p.Tick(p.started.Add(4 * time.Second))
+
+	view := p.View(80)
+	for _, want := range []string{"2 of 4 entries", "4.0s", "0.5/s", "█"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in %q", want, view)
+		}
+	}
+	if lipgloss.Width(view) > 80 {
+		t.Errorf("Expected the progress to fit 80 columns, got %d", lipgloss.Width(view))
+	}
+}
+
+func TestOperationProgress_UnknownTotal(t *testing.T) {
+	p := newOperationProgress(0)
+	for i := 0; i < 30; i++ {
+		p.Advance()
+	}
+	p.Tick(p.started.Add(10 * time.Second))
+
+	view := p.View(80)
+	if !strings.Contains(view, "30 entries • 10.0s • 3.0/s") {
+		t.Errorf("Expected a counter with the rate, got %q", view)
+	}
+	if strings.Contains(view, "of") || strings.Contains(view, "█") {
+		t.Errorf("Expected no bar without a total, got %q", view)
+	}
+}
+
+func TestBulkModify_ProgressRefreshesWhileApplying(t *testing.T) {
+	qv := bulkTestView()
+	qv.startBulkModify()
+	qv.bulk.attribute.SetValue("description")
+	qv.bulk.change, _ = qv.bulk.buildChange()
+	qv.bulk.stage = bulkStagePreview
+	qv.bulk.confirm.SetValue("3")
+	qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if qv.bulk.progress == nil {
+		t.Fatal("Expected progress to be tracked while applying")
+	}
+	if cmd := qv.handleBulkProgressTick(ProgressTickMsg{Time: time.Now()}); cmd == nil {
+		t.Error("Expected the progress to keep refreshing while applying")
+	}
+	qv.handleBulkStep(BulkModifyStepMsg{Index: 0, DN: qv.bulk.dns[0]})
+	if !strings.Contains(qv.renderBulk(100, 20), "1 of 3 entries") {
+		t.Error("Expected the progress to count the modified entry")
+	}
+
+	// Esc stops after the current entry and the refresh ends with the report
+	qv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	qv.handleBulkStep(BulkModifyStepMsg{Index: 1, DN: qv.bulk.dns[1]})
+	if cmd := qv.handleBulkProgressTick(ProgressTickMsg{Time: time.Now()}); cmd != nil {
+		t.Error("Expected the refresh to stop once finished")
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/config"
 	"github.com/ericschmar/moribito/internal/ldap"
 )
 
@@ -20,13 +22,40 @@ type QueryResultsMsg struct {
 type QueryPageMsg struct {
 	Page        *ldap.SearchPage
 	IsFirstPage bool
+	Index       int               // Zero-based page number
+	StartCookie []byte            // Cookie the page was fetched with, nil for the first page
+	Restarted   bool              // The server rejected the cookie and paging began again
+	Search      ldap.SearchParams // What the page was searched with
 }
 
+// LoadQueryMsg asks to open the query view with Filter ready to run
+type LoadQueryMsg struct {
+	Filter string
+	Note   string // Added to the status message
+}
+
+// LoadQuery sends a message opening the query view with filter
+func LoadQuery(filter, note string) tea.Cmd {
+	return func() tea.Msg {
+		return LoadQueryMsg{Filter: filter, Note: note}
+	}
+}
+
+// queryField identifies the focused input of the query form
+type queryField int
+
+const (
+	queryFieldFilter     queryField = iota // LDAP filter textarea
+	queryFieldBase                         // Search base DN
+	queryFieldAttributes                   // Attributes to return
+)
+
 // QueryView provides an interface for LDAP queries
 type QueryView struct {
 	client      *ldap.Client
 	textarea    textarea.Model
 	results     []*ldap.Entry
+	search      ldap.SearchParams // What the results were searched with, empty when unknown
 	table       table.Model
 	ResultLines []string // Keep for backward compatibility during transition
 	width       int
@@ -34,13 +63,62 @@ type QueryView struct {
 	inputMode   bool
 	loading     bool
 	error       error
+	hint        string          // Suggestion shown under the error, such as a likely missing index
+	unindexed   map[string]bool // Lower-cased attributes whose substring searches hit a limit this session
 	container   *ViewContainer
 
+	// Optional search base and attribute list, both with tab completion
+	baseInput   textinput.Model
+	attrsInput  textinput.Model
+	focus       queryField
+	tabBehavior string // What Tab does in the filter input, see config.QueryTab*
+	schema      *ldap.Schema
+	dnSource    func() []string // Known DNs offered when completing the base, e.g. from the tree
+	completions []string        // Candidates left by an ambiguous completion
+
+	// Result rows are coloured by their primary objectClass unless toggled off
+	colorRows   bool
+	rowKinds    []entryKind
+	rowCache    map[*ldap.Entry]*resultRow // Formatted rows of the results, reused by the next rebuild
+	viewport    int                        // First visible result row
+	columnRatio float64                    // Share of the table width for the DN column, 0 for a third
+	columns     tableColumns               // Column focused for resizing and its session widths
+	abbreviate  bool                       // DNs show only their RDN values, except on the selected row
+	compact     bool                       // List results one line each by RDN instead of the table
+
+	// Value breakdown of one attribute over the results, and its prompt
+	facetInput *textinput.Model
+	facets     *facetPanel
+
+	// Bulk modify of every result, nil when closed
+	bulk *bulkModify
+
+	// Loading of every page into the results, nil when not loading, and the
+	// result count it asks to go on past, see config.NeedsConfirmation
+	loadAll          *loadAllPages
+	confirmThreshold int
+
+	// Presence query presets menu, nil when closed
+	presets *presetMenu
+
+	// Saved result snapshots: where they are kept, the name prompt and list
+	// when open, and the snapshot shown read-only in place of live results
+	snapshotDir   string
+	snapshotInput *textinput.Model
+	snapshots     *snapshotBrowser
+	snapshot      *querySnapshot
+
+	// Attribute picker and how its choice is applied, nil when closed
+	picker      *AttributePicker
+	pickerApply func(string)
+
 	// Pagination state
 	pageSize        uint32
 	hasMore         bool
 	currentCookie   []byte
 	loadingNextPage bool
+	page            int      // Zero-based number of the page shown
+	pageCookies     [][]byte // Starting cookie of each page up to the current one
 }
 
 // NewQueryView creates a new query view
@@ -64,25 +142,18 @@ func NewQueryView(client *ldap.Client) *QueryView {
 		table.WithHeight(10),
 	)
 
-	// Style the table
-	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240")).
-		BorderBottom(true).
-		Bold(false)
-	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("15")).
-		Background(lipgloss.Color(GetGradientColor(0.3))).
-		Bold(false)
-	t.SetStyles(s)
+	t.SetStyles(queryTableStyles())
 
 	return &QueryView{
-		client:    client,
-		textarea:  ta,
-		table:     t,
-		inputMode: true,
-		pageSize:  50, // Default page size
+		client:     client,
+		textarea:   ta,
+		table:      t,
+		inputMode:  true,
+		pageSize:   50, // Default page size
+		colorRows:  true,
+		baseInput:  newQueryInput("Base DN (default: connection base)"),
+		attrsInput: newQueryInput("Attributes, comma separated (default: *)"),
+		columns:    newTableColumns(),
 	}
 }
 
@@ -107,7 +178,32 @@ func NewQueryViewWithPageSize(client *ldap.Client, pageSize uint32) *QueryView {
 		table.WithHeight(10),
 	)
 
-	// Style the table
+	t.SetStyles(queryTableStyles())
+
+	return &QueryView{
+		client:     client,
+		textarea:   ta,
+		table:      t,
+		inputMode:  true,
+		pageSize:   pageSize,
+		colorRows:  true,
+		baseInput:  newQueryInput("Base DN (default: connection base)"),
+		attrsInput: newQueryInput("Attributes, comma separated (default: *)"),
+		columns:    newTableColumns(),
+	}
+}
+
+// newQueryInput creates a single-line input for the query form
+func newQueryInput(placeholder string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = 0
+	ti.Prompt = ""
+	return ti
+}
+
+// queryTableStyles returns the styles used for the results table
+func queryTableStyles() table.Styles {
 	s := table.DefaultStyles()
 	s.Header = s.Header.
 		BorderStyle(lipgloss.NormalBorder()).
@@ -118,20 +214,283 @@ func NewQueryViewWithPageSize(client *ldap.Client, pageSize uint32) *QueryView {
 		Foreground(lipgloss.Color("15")).
 		Background(lipgloss.Color(GetGradientColor(0.3))).
 		Bold(false)
-	t.SetStyles(s)
-
-	return &QueryView{
-		client:    client,
-		textarea:  ta,
-		table:     t,
-		inputMode: true,
-		pageSize:  pageSize,
-	}
+	return s
 }
 
 // IsInputMode returns whether the query view is in input mode
 func (qv *QueryView) IsInputMode() bool {
-	return qv.inputMode
+	return qv.inputMode || qv.facetInput != nil || qv.bulk != nil || qv.presets != nil || qv.picker != nil || qv.snapshotInput != nil || qv.snapshots != nil
+}
+
+// HasUnsavedChanges reports whether a bulk modify is being defined or
+// applied. Its report is only information, so it doesn't count.
+func (qv *QueryView) HasUnsavedChanges() bool {
+	return qv.bulk != nil && qv.bulk.stage != bulkStageReport
+}
+
+// SetFilter replaces the filter and focuses it, keeping the base DN and
+// attributes inputs, so it can be refined or run
+func (qv *QueryView) SetFilter(filter string) {
+	qv.presets = nil
+	qv.facetInput = nil
+	qv.completions = nil
+	qv.textarea.SetValue(filter)
+	qv.inputMode = true
+	qv.table.Blur()
+	qv.setFocus(queryFieldFilter)
+}
+
+// SetSchema sets the server schema used to complete attribute names
+func (qv *QueryView) SetSchema(schema *ldap.Schema) {
+	qv.schema = schema
+}
+
+// SetDNSource sets the function listing known DNs for base DN completion
+func (qv *QueryView) SetDNSource(source func() []string) {
+	qv.dnSource = source
+}
+
+// IsCompletingField returns true when tab should complete the focused input
+// rather than switch views
+func (qv *QueryView) IsCompletingField() bool {
+	return (qv.inputMode && qv.focus != queryFieldFilter) || qv.facetInput != nil || qv.bulk != nil || qv.presets != nil || qv.picker != nil
+}
+
+// tabInstructions describes what Tab and Ctrl+] do in the query form
+func (qv *QueryView) tabInstructions() string {
+	switch qv.tabBehavior {
+	case config.QueryTabIndent:
+		return "[Tab] indent filter or complete field • [Ctrl+]] browse results"
+	case config.QueryTabSmart:
+		return "[Tab] indent, complete field or browse results at the end • [Ctrl+]] browse results"
+	default:
+		return "[Tab] complete field • [Ctrl+]] browse results"
+	}
+}
+
+// queryIndent is inserted by Tab when it indents the filter, matching the
+// indentation of formatted queries
+const queryIndent = "  "
+
+// SetAbbreviateDNs sets whether the results table shows DNs by their RDN
+// values alone, as ldap.AbbreviateDN does. The selected row keeps its full DN.
+func (qv *QueryView) SetAbbreviateDNs(abbreviate bool) {
+	qv.abbreviate = abbreviate
+	qv.buildTableRows()
+}
+
+// SetTabBehavior sets what Tab does in the filter input, see config.QueryTab*.
+// Unknown behaviors switch views as Tab does elsewhere.
+func (qv *QueryView) SetTabBehavior(behavior string) {
+	switch behavior {
+	case config.QueryTabIndent, config.QueryTabSmart:
+		qv.tabBehavior = behavior
+	default:
+		qv.tabBehavior = config.QueryTabSwitch
+	}
+}
+
+// CapturesTab returns true when the query view handles tab itself instead of
+// it switching views: to complete an input, or to indent the filter
+func (qv *QueryView) CapturesTab() bool {
+	if qv.IsCompletingField() {
+		return true
+	}
+	indents := qv.tabBehavior == config.QueryTabIndent || qv.tabBehavior == config.QueryTabSmart
+	return qv.inputMode && qv.focus == queryFieldFilter && indents
+}
+
+// filterCursorAtEnd reports whether the filter input's cursor is after its last character
+func (qv *QueryView) filterCursorAtEnd() bool {
+	lines := strings.Split(qv.textarea.Value(), "\n")
+	if qv.textarea.Line() < len(lines)-1 {
+		return false
+	}
+	info := qv.textarea.LineInfo()
+	return info.StartColumn+info.ColumnOffset >= len([]rune(lines[len(lines)-1]))
+}
+
+// browseResults moves from the query form to the results, if there are any
+func (qv *QueryView) browseResults() {
+	if len(qv.results) == 0 {
+		return
+	}
+	qv.inputMode = false
+	qv.textarea.Blur()
+	qv.table.Focus()
+}
+
+// setFocus moves input focus within the query form
+func (qv *QueryView) setFocus(field queryField) {
+	qv.focus = field
+	qv.completions = nil
+	qv.textarea.Blur()
+	qv.baseInput.Blur()
+	qv.attrsInput.Blur()
+	switch field {
+	case queryFieldBase:
+		qv.baseInput.Focus()
+	case queryFieldAttributes:
+		qv.attrsInput.Focus()
+	default:
+		qv.textarea.Focus()
+	}
+}
+
+// complete applies tab completion to the focused base DN or attributes input
+func (qv *QueryView) complete() {
+	var completion Completion
+	switch qv.focus {
+	case queryFieldBase:
+		completion = CompleteDN(qv.baseInput.Value(), qv.dnCandidates())
+		qv.baseInput.SetValue(completion.Value)
+		qv.baseInput.CursorEnd()
+	case queryFieldAttributes:
+		completion = CompleteAttributes(qv.attrsInput.Value(), qv.attributeCandidates())
+		qv.attrsInput.SetValue(completion.Value)
+		qv.attrsInput.CursorEnd()
+	}
+	qv.completions = completion.Options
+}
+
+// openPicker shows the attribute picker over names; apply receives the
+// attribute picked
+func (qv *QueryView) openPicker(names []string, apply func(string)) tea.Cmd {
+	qv.picker = NewAttributePicker(names, qv.schema)
+	qv.pickerApply = apply
+	qv.completions = nil
+	return textinput.Blink
+}
+
+// handlePickerKey handles key presses while the attribute picker is open
+func (qv *QueryView) handlePickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	done, name, cmd := qv.picker.Update(msg)
+	if !done {
+		return qv, cmd
+	}
+	apply := qv.pickerApply
+	qv.picker = nil
+	qv.pickerApply = nil
+	if name != "" {
+		apply(name)
+	}
+	return qv, nil
+}
+
+// pickForField opens the attribute picker for the focused query input: the
+// picked name is inserted into the filter at the cursor, or replaces the
+// name being typed in the attributes list
+func (qv *QueryView) pickForField() tea.Cmd {
+	switch qv.focus {
+	case queryFieldFilter:
+		return qv.openPicker(qv.attributeCandidates(), func(name string) {
+			qv.textarea.InsertString(name)
+		})
+	case queryFieldAttributes:
+		return qv.openPicker(qv.attributeCandidates(), func(name string) {
+			qv.attrsInput.SetValue(replaceLastAttribute(qv.attrsInput.Value(), name))
+			qv.attrsInput.CursorEnd()
+		})
+	}
+	return nil
+}
+
+// replaceLastAttribute replaces the last name in a comma or space separated
+// attribute list with name
+func replaceLastAttribute(input, name string) string {
+	return input[:strings.LastIndexAny(input, ", ")+1] + name
+}
+
+// dnCandidates lists the DNs known from the tree and the current results
+func (qv *QueryView) dnCandidates() []string {
+	var dns []string
+	if qv.dnSource != nil {
+		dns = append(dns, qv.dnSource()...)
+	}
+	for _, entry := range qv.results {
+		dns = append(dns, entry.DN)
+	}
+	return dns
+}
+
+// attributeCandidates lists the schema's attribute names, or those seen in
+// results when no schema is available
+func (qv *QueryView) attributeCandidates() []string {
+	names := qv.schema.AttributeNames()
+	for _, entry := range qv.results {
+		for name := range entry.Attributes {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// searchAttributes parses the attributes input into a list of names. An
+// explicit list always includes objectClass so results can still be coloured.
+func (qv *QueryView) searchAttributes() []string {
+	attributes := parseAttributeList(qv.attrsInput.Value())
+	if len(attributes) == 0 {
+		return nil
+	}
+	for _, attr := range attributes {
+		if attr == "*" || strings.EqualFold(attr, "objectClass") {
+			return attributes
+		}
+	}
+	return append(attributes, "objectClass")
+}
+
+// SetColumnRatio sets the share of the table width given to the DN column,
+// zero for the default third
+func (qv *QueryView) SetColumnRatio(ratio float64) {
+	qv.columnRatio = ratio
+	if qv.width > 0 {
+		qv.SetSize(qv.width, qv.height)
+	}
+}
+
+// queryColumnTitles and queryColumnMins are the results table's column
+// headers and minimum widths
+var (
+	queryColumnTitles = []string{"DN", "Summary"}
+	queryColumnMins   = []int{20, 30}
+)
+
+// ratioWidths returns the column widths the saved ratio gives contentWidth
+func (qv *QueryView) ratioWidths(contentWidth int) []int {
+	dnWidth, summaryWidth := splitColumnWidths(contentWidth, qv.columnRatio, queryColumnMins[0], queryColumnMins[1])
+	return []int{dnWidth, summaryWidth}
+}
+
+// cycleColumnFocus focuses the next results column for resizing with < and >
+func (qv *QueryView) cycleColumnFocus() string {
+	qv.columns.cycleFocus(len(queryColumnTitles))
+	if qv.width > 0 {
+		qv.SetSize(qv.width, qv.height)
+	}
+	if !qv.columns.focused() {
+		return "Column focus cleared; < and > move the saved divider"
+	}
+	return fmt.Sprintf("%s column focused; < and > resize it for this session", queryColumnTitles[qv.columns.focus])
+}
+
+// resizeColumn grows the focused results column by steps (shrinks it when
+// negative) for this session. Without a focused column it moves the divider
+// between the DN and summary columns and reports the ratio so it can be saved.
+func (qv *QueryView) resizeColumn(steps int) tea.Cmd {
+	if !qv.columns.focused() {
+		qv.columns.widths = nil
+		qv.SetColumnRatio(adjustColumnRatio(qv.columnRatio, steps))
+		return columnRatioChanged(columnTableQuery, qv.columnRatio)
+	}
+	if qv.container == nil {
+		return nil
+	}
+	contentWidth, _ := qv.container.GetContentDimensions()
+	qv.columns.resize(qv.ratioWidths(contentWidth), queryColumnMins, steps)
+	qv.SetSize(qv.width, qv.height)
+	widths := qv.table.Columns()
+	return SendStatus(fmt.Sprintf("%s column: %d cells wide", queryColumnTitles[qv.columns.focus], widths[qv.columns.focus].Width))
 }
 
 // SetResults sets the results for testing purposes
@@ -158,29 +517,24 @@ func (qv *QueryView) SetSize(width, height int) {
 	qv.textarea.SetWidth(contentWidth - 4) // Account for border and padding
 	// Allow the textarea to be multi-line but limit height reasonably
 	qv.textarea.SetHeight(3)
+	qv.baseInput.Width = contentWidth - 14 // Account for the field label
+	qv.attrsInput.Width = contentWidth - 14
 
 	// Configure table dimensions
-	// Reserve space for title, query, status, instructions (rough estimate ~10 lines)
-	tableHeight := contentHeight - 12
+	// Reserve space for title, query, base/attribute inputs, status, the
+	// search annotation and instructions (rough estimate ~13 lines)
+	tableHeight := contentHeight - 15
 	if tableHeight < 3 {
 		tableHeight = 3
 	}
 
 	// Calculate column widths based on available content space
-	dnWidth := contentWidth / 3
-	if dnWidth < 20 {
-		dnWidth = 20
-	}
-	summaryWidth := contentWidth - dnWidth - 4 // Account for borders and spacing
-	if summaryWidth < 30 {
-		summaryWidth = 30
-		dnWidth = contentWidth - summaryWidth - 4
-	}
+	widths := qv.columns.layout(qv.ratioWidths(contentWidth), queryColumnMins)
 
 	// Update table dimensions and columns
 	columns := []table.Column{
-		{Title: "DN", Width: dnWidth},
-		{Title: "Summary", Width: summaryWidth},
+		{Title: qv.columns.title(0, queryColumnTitles[0]), Width: widths[0]},
+		{Title: qv.columns.title(1, queryColumnTitles[1]), Width: widths[1]},
 	}
 	qv.table.SetColumns(columns)
 	qv.table.SetHeight(tableHeight)
@@ -193,15 +547,38 @@ func (qv *QueryView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if qv.picker != nil {
+			return qv.handlePickerKey(msg)
+		}
+		if qv.presets != nil {
+			return qv.handlePresetKey(msg)
+		}
+		if qv.snapshots != nil {
+			return qv.handleSnapshotBrowserKey(msg)
+		}
 		if qv.inputMode {
 			return qv.handleInputMode(msg)
 		} else {
 			return qv.handleBrowseMode(msg)
 		}
 
+	case BulkModifyStepMsg:
+		return qv, qv.handleBulkStep(msg)
+
+	case ProgressTickMsg:
+		if qv.loadAll != nil {
+			return qv, qv.handleLoadAllProgressTick(msg)
+		}
+		return qv, qv.handleBulkProgressTick(msg)
+
+	case LoadAllPageMsg:
+		return qv, qv.handleLoadAllPage(msg)
+
 	case QueryResultsMsg:
 		// Legacy non-paginated results (fallback)
 		qv.results = msg.Results
+		qv.search = ldap.SearchParams{}
+		qv.snapshot = nil
 		qv.loading = false
 		qv.error = nil
 		qv.inputMode = false
@@ -209,20 +586,27 @@ func (qv *QueryView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		qv.table.Focus()   // Focus the table when browsing results
 		qv.hasMore = false
 		qv.currentCookie = nil
+		qv.page = 0
+		qv.pageCookies = nil
 		qv.buildTableRows()
 		return qv, SendStatus(fmt.Sprintf("Found %d results", len(qv.results)))
 
 	case QueryPageMsg:
-		// Handle paginated results
+		// Handle paginated results, one page at a time
+		index := msg.Index
 		if msg.IsFirstPage {
-			// First page - replace existing results
-			qv.results = msg.Page.Entries
-		} else {
-			// Subsequent page - append to existing results
-			qv.results = append(qv.results, msg.Page.Entries...)
+			index = 0
 		}
+		if index > len(qv.pageCookies) {
+			index = len(qv.pageCookies)
+		}
+		qv.results = msg.Page.Entries
+		qv.search = msg.Search
+		qv.snapshot = nil
 
-		// Update pagination state
+		// Update pagination state, remembering how to fetch this page again
+		qv.page = index
+		qv.pageCookies = append(qv.pageCookies[:index], msg.StartCookie)
 		qv.hasMore = msg.Page.HasMore
 		qv.currentCookie = msg.Page.Cookie
 		qv.loading = false
@@ -236,15 +620,22 @@ func (qv *QueryView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		totalResults := len(qv.results)
 		statusMsg := fmt.Sprintf("Found %d results", totalResults)
+		if qv.page > 0 || qv.hasMore {
+			statusMsg = fmt.Sprintf("Page %d: %d results", qv.page+1, totalResults)
+		}
 		if qv.hasMore {
 			statusMsg += " (more available)"
 		}
+		if msg.Restarted {
+			statusMsg = "The server no longer accepts the paging cookie, restarted from page 1. " + statusMsg
+		}
 		return qv, SendStatus(statusMsg)
 
 	case ErrorMsg:
 		qv.loading = false
 		qv.loadingNextPage = false
 		qv.error = msg.Err
+		qv.noteSearchError(msg.Err)
 		return qv, nil
 	}
 
@@ -272,24 +663,55 @@ func (qv *QueryView) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Clear results and reset to input mode
 		qv.results = nil
 		qv.ResultLines = nil
+		qv.facets = nil
 		qv.table.SetRows([]table.Row{})
 		qv.hasMore = false
 		qv.currentCookie = nil
+		qv.page = 0
+		qv.pageCookies = nil
 		qv.inputMode = true
 		qv.table.Blur()
-		qv.textarea.Focus()
+		qv.setFocus(queryFieldFilter)
 		return qv, nil
 
 	case "ctrl+c":
 		return qv, tea.Quit
 
+	case "ctrl+p":
+		return qv, qv.openPresets()
+
+	case "ctrl+o":
+		return qv, qv.openSnapshots()
+
+	case "ctrl+t":
+		return qv, qv.pickForField()
+
+	case "shift+tab":
+		// Cycle between the filter, base DN and attributes inputs
+		qv.setFocus((qv.focus + 1) % 3)
+		return qv, nil
+
 	case "tab":
-		// Switch to browse mode if we have results
-		if len(qv.results) > 0 {
-			qv.inputMode = false
-			qv.textarea.Blur()
-			qv.table.Focus()
+		if qv.focus != queryFieldFilter {
+			qv.complete()
+			return qv, nil
+		}
+		// Indent the filter unless Tab is to move to the results here
+		switch qv.tabBehavior {
+		case config.QueryTabIndent:
+			qv.textarea.InsertString(queryIndent)
+			return qv, nil
+		case config.QueryTabSmart:
+			if len(qv.results) == 0 || !qv.filterCursorAtEnd() {
+				qv.textarea.InsertString(queryIndent)
+				return qv, nil
+			}
 		}
+		qv.browseResults()
+		return qv, nil
+
+	case "ctrl+]":
+		qv.browseResults()
 		return qv, nil
 
 	case "ctrl+v", "cmd+v", "shift+insert", "insert":
@@ -307,8 +729,16 @@ func (qv *QueryView) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return qv, nil
 	}
 
-	// Forward to textarea
-	qv.textarea, cmd = qv.textarea.Update(msg)
+	// Forward to the focused input
+	qv.completions = nil
+	switch qv.focus {
+	case queryFieldBase:
+		qv.baseInput, cmd = qv.baseInput.Update(msg)
+	case queryFieldAttributes:
+		qv.attrsInput, cmd = qv.attrsInput.Update(msg)
+	default:
+		qv.textarea, cmd = qv.textarea.Update(msg)
+	}
 	return qv, cmd
 }
 
@@ -316,9 +746,28 @@ func (qv *QueryView) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (qv *QueryView) handleBrowseMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if qv.bulk != nil {
+		return qv.handleBulkKey(msg)
+	}
+	if qv.loadAll != nil {
+		return qv.handleLoadAllKey(msg)
+	}
+	if qv.facetInput != nil {
+		return qv.handleFacetPromptKey(msg)
+	}
+	if qv.snapshotInput != nil {
+		return qv.handleSnapshotPromptKey(msg)
+	}
+	if qv.facets != nil {
+		return qv.handleFacetKey(msg)
+	}
+
 	switch msg.String() {
 	case "enter", " ":
 		// Show record details for selected entry
+		if qv.snapshot != nil {
+			return qv, SendStatus("Snapshot results are read-only; press [Esc] and run the query to open live entries")
+		}
 		selectedRow := qv.table.Cursor()
 		if selectedRow < len(qv.results) {
 			return qv, ShowRecord(qv.results[selectedRow])
@@ -329,12 +778,73 @@ func (qv *QueryView) handleBrowseMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		qv.table.Blur()
 		qv.textarea.Focus()
 		return qv, nil
+	case "c":
+		// Toggle objectClass colouring of the results
+		qv.colorRows = !qv.colorRows
+		return qv, nil
+	case "a":
+		// Switch the DN column between full and abbreviated DNs
+		qv.SetAbbreviateDNs(!qv.abbreviate)
+		return qv, nil
+	case "v", "V":
+		// Switch between the results table and the compact list
+		qv.toggleCompact()
+		return qv, nil
+	case "f", "F":
+		// Count the values of an attribute across the results
+		return qv, qv.startFacetPrompt()
+	case "M":
+		// Apply one attribute change to every result
+		if qv.snapshot != nil {
+			return qv, SendStatus("Snapshot results are read-only; run the query to modify live entries")
+		}
+		return qv, qv.startBulkModify()
+	case "R":
+		// Replace text in one attribute's values across the results
+		if qv.snapshot != nil {
+			return qv, SendStatus("Snapshot results are read-only; run the query to modify live entries")
+		}
+		return qv, qv.startFindReplace()
+	case "y", "Y":
+		// Copy a one-line summary of the query and its results
+		return qv, qv.copyResultSummary()
+	case "S":
+		// Save the results and their query as a snapshot
+		return qv, qv.startSnapshotPrompt()
+	case "ctrl+o":
+		// Reopen a saved snapshot
+		return qv, qv.openSnapshots()
+	case "ctrl+p":
+		// Run a presence query on an attribute
+		return qv, qv.openPresets()
+	case "shift+tab":
+		// Focus a column to resize for this session
+		return qv, SendStatus(qv.cycleColumnFocus())
+	case "<", ">":
+		// Resize the focused column, or move the saved divider
+		steps := 1
+		if msg.String() == "<" {
+			steps = -1
+		}
+		return qv, qv.resizeColumn(steps)
 	case "n":
 		// Load next page if available
 		if qv.hasMore && !qv.loadingNextPage {
 			qv.loadingNextPage = true
 			return qv, qv.loadNextPage()
 		}
+	case "p":
+		// Fetch the previous page again
+		if qv.page > 0 && !qv.loadingNextPage {
+			qv.loadingNextPage = true
+			return qv, qv.loadPreviousPage()
+		}
+	case "L":
+		// Load every page into the results
+		if qv.snapshot != nil {
+			return qv, SendStatus("Snapshot results are saved pages; run the query to load every page")
+		}
+		return qv, qv.startLoadAll()
 	default:
 		// Forward navigation keys to the table
 		qv.table, cmd = qv.table.Update(msg)
@@ -369,22 +879,61 @@ func (qv *QueryView) View() string {
 	textareaContent := textareaStyle.Render(qv.textarea.View())
 	sections = append(sections, textareaContent)
 
+	// Search base and attributes inputs
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	focusedLabelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Bold(true)
+	for _, field := range []struct {
+		label string
+		input textinput.Model
+		focus queryField
+	}{
+		{"Base DN:    ", qv.baseInput, queryFieldBase},
+		{"Attributes: ", qv.attrsInput, queryFieldAttributes},
+	} {
+		style := labelStyle
+		if qv.inputMode && qv.focus == field.focus {
+			style = focusedLabelStyle
+		}
+		sections = append(sections, style.Render(field.label)+field.input.View())
+	}
+	if qv.presets != nil {
+		sections = append(sections, qv.renderPresets())
+	}
+	if qv.snapshots != nil {
+		sections = append(sections, qv.renderSnapshots())
+	}
+	if qv.picker != nil {
+		sections = append(sections, qv.picker.View())
+	}
+	if len(qv.completions) > 0 {
+		contentWidth, _ := qv.container.GetContentDimensions()
+		sections = append(sections, truncateToWidth(labelStyle.Render("Completions: "+strings.Join(qv.completions, "  ")), contentWidth))
+	}
+
 	// Status/loading information
 	if qv.loading {
 		loadingStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("11")).
 			Italic(true)
-		sections = append(sections, loadingStyle.Render("⏳ Executing query..."))
+		sections = append(sections, loadingStyle.Render(iconLoading.String()+" Executing query..."))
+	} else if qv.loadAll != nil {
+		sections = append(sections, qv.renderLoadAll())
 	} else if qv.loadingNextPage {
 		loadingStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("11")).
 			Italic(true)
-		sections = append(sections, loadingStyle.Render("⏳ Loading next page..."))
+		sections = append(sections, loadingStyle.Render(iconLoading.String()+" Loading page..."))
 	} else if qv.error != nil {
 		errorStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("9")).
 			Bold(true)
-		sections = append(sections, errorStyle.Render(fmt.Sprintf("❌ Error: %s", qv.error.Error())))
+		sections = append(sections, errorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, ldap.Explain(qv.error).Error())))
+		if qv.hint != "" {
+			hintStyle := lipgloss.NewStyle().
+				Foreground(lipgloss.Color("11")).
+				Italic(true)
+			sections = append(sections, hintStyle.Render(fmt.Sprintf("%s Hint: %s", iconWarning, qv.hint)))
+		}
 	}
 
 	// Results area
@@ -395,6 +944,19 @@ func (qv *QueryView) View() string {
 			Margin(1, 0, 0, 0).
 			Render("Results:")
 		sections = append(sections, resultsHeader)
+		if qv.snapshot != nil {
+			sections = append(sections, qv.renderSnapshotBanner())
+		}
+		if qv.search.Filter != "" {
+			contentWidth, _ := qv.container.GetContentDimensions()
+			sections = append(sections, truncateToWidth(labelStyle.Render(qv.search.String()), contentWidth))
+		}
+		if qv.facetInput != nil {
+			sections = append(sections, focusedLabelStyle.Render("Facet by:   ")+qv.facetInput.View())
+		}
+		if qv.snapshotInput != nil {
+			sections = append(sections, focusedLabelStyle.Render("Snapshot:   ")+qv.snapshotInput.View())
+		}
 
 		// Calculate remaining height dynamically based on content built so far
 		_, contentHeight := qv.container.GetContentDimensions()
@@ -414,23 +976,66 @@ func (qv *QueryView) View() string {
 		}
 
 		if remainingHeight > 0 {
-			resultsContent := qv.renderTable()
-			sections = append(sections, resultsContent)
+			if qv.bulk != nil {
+				contentWidth, _ := qv.container.GetContentDimensions()
+				sections = append(sections, qv.renderBulk(contentWidth, remainingHeight))
+			} else if qv.facets != nil {
+				contentWidth, _ := qv.container.GetContentDimensions()
+				sections = append(sections, qv.renderFacets(contentWidth, remainingHeight))
+			} else if qv.compact {
+				sections = append(sections, qv.renderCompact())
+			} else {
+				resultsContent := qv.renderTable()
+				sections = append(sections, resultsContent)
+			}
 		}
 	}
 
 	// Instructions
 	var instructions string
-	if qv.inputMode {
-		instructions = "Press [Enter] to execute • [Esc] to clear • [Tab] to browse results"
+	if qv.picker != nil {
+		instructions = "Type to filter attribute names • [↑↓] select • [Enter] pick • [Esc] cancel"
+	} else if qv.presets != nil {
+		instructions = "Press [↑↓] to choose a preset • [Tab] complete attribute • [Ctrl+T] pick attribute • [Enter] run query • [Esc] cancel"
+	} else if qv.snapshots != nil {
+		instructions = "Press [↑↓] to choose a snapshot • [Enter] open read-only • [Esc] cancel"
+	} else if qv.inputMode {
+		instructions = "Press [Enter] to execute • [Esc] to clear • [Shift+Tab] next field • " + qv.tabInstructions() + " • [Ctrl+T] pick attribute • [Ctrl+P] presets • [Ctrl+O] snapshots"
 		if len(qv.results) > 0 {
 			instructions += " • [Ctrl+V/Cmd+V] to paste"
 		}
+	} else if qv.bulk != nil {
+		instructions = qv.bulkInstructions()
+	} else if qv.facetInput != nil {
+		instructions = "Press [Enter] to count values • [Tab] complete attribute • [Ctrl+T] pick attribute • [Esc] cancel"
+	} else if qv.snapshotInput != nil {
+		instructions = "Press [Enter] to save the results as a snapshot • [Esc] cancel"
+	} else if qv.facets != nil {
+		instructions = "Press [↑↓] to scroll • [F] facet another attribute • [Esc] back to results"
 	} else {
-		instructions = "Press [↑↓] to navigate • [Enter/Space] to view record • [Esc] to edit query"
+		layout := "[V] compact list • [</>] column width • [Shift+Tab] focus column"
+		if qv.compact {
+			layout = "[V] table"
+		}
+		instructions = "Press [↑↓] to navigate • [Enter/Space] to view record • [C] toggle colours • [A] abbreviate DNs • " + layout + " • [F] facets • [Shift+M] bulk modify • [Shift+R] find and replace • [Shift+S] save snapshot • [Ctrl+O] snapshots • [Ctrl+P] presets • [Y] copy summary • [Esc] to edit query"
+		if qv.snapshot != nil {
+			instructions = "Press [↑↓] to navigate • [C] toggle colours • [A] abbreviate DNs • " + layout + " • [F] facets • [Ctrl+O] snapshots • [Y] copy summary • [Esc] to edit and run the query"
+		}
+		if qv.page > 0 {
+			instructions += " • [P] for previous page"
+		}
 		if qv.hasMore {
 			instructions += " • [N] for next page"
 		}
+		if qv.snapshot == nil && (qv.page > 0 || qv.hasMore) {
+			instructions += " • [Shift+L] load every page"
+		}
+	}
+	if qv.loadAll != nil {
+		instructions = "Press [Esc] to stop loading pages"
+		if qv.loadAll.confirming {
+			instructions = "Press [Y] to load every page • [N/Esc] keep the results loaded so far"
+		}
 	}
 
 	instructionStyle := lipgloss.NewStyle().
@@ -450,68 +1055,134 @@ func (qv *QueryView) executeQuery() tea.Cmd {
 	if query == "" {
 		return SendError(fmt.Errorf("query cannot be empty"))
 	}
+	// A malformed base DN is reported inline instead of by the server
+	baseDN, err := ldap.NormalizeDN(qv.baseInput.Value())
+	if err != nil {
+		qv.loading = false
+		qv.error = err
+		return nil
+	}
+	attributes := qv.searchAttributes()
 
-	return func() tea.Msg {
-		page, err := qv.client.CustomSearchPaged(query, qv.pageSize, nil)
+	return tea.Batch(qv.unindexedWarning(query), func() tea.Msg {
+		search := qv.client.CustomSearchParams(baseDN, query, attributes)
+		page, err := qv.client.CustomSearchPagedIn(baseDN, query, attributes, qv.pageSize, nil)
 		if err != nil {
 			return ErrorMsg{Err: err}
 		}
-		return QueryPageMsg{Page: page, IsFirstPage: true}
-	}
+		return QueryPageMsg{Page: page, IsFirstPage: true, Search: search}
+	})
 }
 
 // loadNextPage loads the next page of results
 func (qv *QueryView) loadNextPage() tea.Cmd {
+	return qv.loadPage(qv.page+1, qv.currentCookie)
+}
+
+// loadPreviousPage fetches the page before the current one again. LDAP paging
+// only moves forward, so this resends the cookie that page was first fetched with.
+func (qv *QueryView) loadPreviousPage() tea.Cmd {
+	return qv.loadPage(qv.page-1, qv.pageCookies[qv.page-1])
+}
+
+// loadPage fetches page index of the results starting from cookie. When the
+// server rejects the cookie, paging restarts from the first page.
+func (qv *QueryView) loadPage(index int, cookie []byte) tea.Cmd {
 	query := strings.TrimSpace(qv.textarea.Value())
 	if query == "" {
 		return SendError(fmt.Errorf("query cannot be empty"))
 	}
+	baseDN, err := ldap.NormalizeDN(qv.baseInput.Value())
+	if err != nil {
+		return SendError(err)
+	}
+	attributes := qv.searchAttributes()
 
 	return func() tea.Msg {
-		page, err := qv.client.CustomSearchPaged(query, qv.pageSize, qv.currentCookie)
+		search := qv.client.CustomSearchParams(baseDN, query, attributes)
+		page, err := qv.client.CustomSearchPagedIn(baseDN, query, attributes, qv.pageSize, cookie)
+		if err != nil && cookie != nil && ldap.IsPagingCookieError(err) {
+			page, err = qv.client.CustomSearchPagedIn(baseDN, query, attributes, qv.pageSize, nil)
+			if err == nil {
+				return QueryPageMsg{Page: page, IsFirstPage: true, Restarted: true, Search: search}
+			}
+		}
 		if err != nil {
 			return ErrorMsg{Err: err}
 		}
-		return QueryPageMsg{Page: page, IsFirstPage: false}
+		return QueryPageMsg{Page: page, Index: index, StartCookie: cookie, Search: search}
+	}
+}
+
+// resultRow is a result formatted for the table, kept between rebuilds so
+// a new page, a toggle or a reorder only formats the entries not seen before
+type resultRow struct {
+	full        table.Row // DN and summary
+	abbreviated table.Row // Abbreviated DN and summary, built when first shown
+	kind        entryKind
+	lines       []string // Lines of the legacy ResultLines
+}
+
+// newResultRow formats entry for the results table
+func newResultRow(entry *ldap.Entry) *resultRow {
+	// Create summary column with key attributes
+	var summaryParts []string
+	lines := []string{fmt.Sprintf("DN: %s", entry.DN)}
+	for attrName, attrValues := range entry.Attributes {
+		if len(attrValues) > 0 {
+			summary := fmt.Sprintf("%s: %s", attrName, attrValues[0])
+			if len(attrValues) > 1 {
+				summary += fmt.Sprintf(" (+%d more)", len(attrValues)-1)
+			}
+			lines = append(lines, "  "+summary)
+			// Limit to first few attributes to keep summary concise
+			if len(summaryParts) < 3 {
+				summaryParts = append(summaryParts, summary)
+			}
+		}
+	}
+
+	summary := strings.Join(summaryParts, " | ")
+	if summary == "" {
+		summary = "(no attributes)"
+	}
+	return &resultRow{full: table.Row{entry.DN, summary}, kind: classifyEntry(entry), lines: lines}
+}
+
+// tableRow returns the cells shown for the result
+func (r *resultRow) tableRow(abbreviate bool) table.Row {
+	if !abbreviate {
+		return r.full
 	}
+	if r.abbreviated == nil {
+		r.abbreviated = table.Row{ldap.AbbreviateDN(r.full[0]), r.full[1]}
+	}
+	return r.abbreviated
 }
 
-// buildTableRows builds the table rows from results
+// buildTableRows builds the table rows from results. Entries formatted by
+// the previous rebuild are reused; the rest are formatted now.
 func (qv *QueryView) buildTableRows() {
+	qv.rowKinds = qv.rowKinds[:0]
+	qv.refreshFacets()
 	if len(qv.results) == 0 {
+		qv.rowCache = nil
 		qv.table.SetRows([]table.Row{})
 		return
 	}
 
-	var rows []table.Row
-
-	for _, entry := range qv.results {
-		// Create DN column
-		dn := entry.DN
-
-		// Create summary column with key attributes
-		var summaryParts []string
-		for attrName, attrValues := range entry.Attributes {
-			if len(attrValues) > 0 {
-				summary := fmt.Sprintf("%s: %s", attrName, attrValues[0])
-				if len(attrValues) > 1 {
-					summary += fmt.Sprintf(" (+%d more)", len(attrValues)-1)
-				}
-				summaryParts = append(summaryParts, summary)
-				// Limit to first few attributes to keep summary concise
-				if len(summaryParts) >= 3 {
-					break
-				}
-			}
-		}
-
-		summary := strings.Join(summaryParts, " | ")
-		if summary == "" {
-			summary = "(no attributes)"
+	cache := make(map[*ldap.Entry]*resultRow, len(qv.results))
+	rows := make([]table.Row, len(qv.results))
+	for i, entry := range qv.results {
+		row, ok := qv.rowCache[entry]
+		if !ok {
+			row = newResultRow(entry)
 		}
-
-		rows = append(rows, table.Row{dn, summary})
+		cache[entry] = row
+		rows[i] = row.tableRow(qv.abbreviate)
+		qv.rowKinds = append(qv.rowKinds, row.kind)
 	}
+	qv.rowCache = cache
 
 	qv.table.SetRows(rows)
 
@@ -519,25 +1190,107 @@ func (qv *QueryView) buildTableRows() {
 	qv.buildResultLines()
 }
 
-// renderTable renders the table with proper styling and pagination info
+// renderTable renders the table with proper styling and pagination info.
+// Rows are drawn here rather than by the table model so each DN can be
+// coloured by its objectClass; the table model still tracks the cursor.
 func (qv *QueryView) renderTable() string {
 	if len(qv.results) == 0 {
 		return "No results"
 	}
 
-	result := qv.table.View()
+	styles := queryTableStyles()
+	columns := qv.table.Columns()
+	rows := qv.table.Rows()
+	cursor := qv.table.Cursor()
 
-	// Add pagination info if applicable
-	if qv.hasMore {
-		paginationInfo := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("8")).
-			Italic(true).
-			Render(fmt.Sprintf("Showing %d of %d+ results • Press [N] for next page",
-				len(qv.results), len(qv.results)))
-		result += "\n" + paginationInfo
+	renderCells := func(values []string, cellStyle lipgloss.Style, valueStyles []lipgloss.Style) string {
+		cells := make([]string, 0, len(columns))
+		for i, col := range columns {
+			if col.Width <= 0 || i >= len(values) {
+				continue
+			}
+			value := truncateToWidth(values[i], col.Width)
+			if i < len(valueStyles) {
+				value = valueStyles[i].Render(value)
+			}
+			cellWidth := lipgloss.NewStyle().Width(col.Width).MaxWidth(col.Width).Inline(true)
+			cells = append(cells, cellStyle.Render(cellWidth.Render(value)))
+		}
+		return lipgloss.JoinHorizontal(lipgloss.Top, cells...)
 	}
 
-	return result
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = col.Title
+	}
+	lines := []string{renderCells(titles, styles.Header, nil)}
+
+	end := qv.scrollToCursor(qv.table.Height(), len(rows))
+	for i := qv.viewport; i < end; i++ {
+		if i == cursor {
+			// Leave the selection highlight uncoloured so it stays readable,
+			// and show its DN in full when the others are abbreviated
+			row := rows[i]
+			if qv.abbreviate && i < len(qv.results) {
+				row = append(table.Row{qv.results[i].DN}, row[1:]...)
+			}
+			lines = append(lines, styles.Selected.Render(renderCells(row, styles.Cell, nil)))
+			continue
+		}
+
+		var valueStyles []lipgloss.Style
+		if qv.colorRows && i < len(qv.rowKinds) {
+			valueStyles = []lipgloss.Style{entryKindStyle(qv.rowKinds[i])}
+		}
+		lines = append(lines, renderCells(rows[i], styles.Cell, valueStyles))
+	}
+
+	result := strings.Join(lines, "\n")
+
+	if qv.colorRows {
+		result += "\n" + renderEntryKindLegend()
+	}
+	return result + qv.renderPaginationInfo()
+}
+
+// scrollToCursor moves the viewport so the cursor is within height rows of
+// total, returning the end of the visible rows
+func (qv *QueryView) scrollToCursor(height, total int) int {
+	if height < 1 {
+		height = 1
+	}
+	cursor := qv.table.Cursor()
+	if cursor < qv.viewport {
+		qv.viewport = cursor
+	} else if cursor >= qv.viewport+height {
+		qv.viewport = cursor - height + 1
+	}
+	if qv.viewport > total-height {
+		qv.viewport = total - height
+	}
+	if qv.viewport < 0 {
+		qv.viewport = 0
+	}
+	return min(qv.viewport+height, total)
+}
+
+// renderPaginationInfo renders the page line below the results, or nothing
+// when everything fits on one page
+func (qv *QueryView) renderPaginationInfo() string {
+	if qv.page == 0 && !qv.hasMore {
+		return ""
+	}
+	info := fmt.Sprintf("Page %d • Showing %d results", qv.page+1, len(qv.results))
+	if qv.page > 0 {
+		info += " • Press [P] for previous page"
+	}
+	if qv.hasMore {
+		info += " • Press [N] for next page"
+	}
+	return "\n" + lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true).
+		Render(info)
 }
 
 // buildResultLines builds the display lines from results (kept for backward compatibility)
@@ -545,20 +1298,11 @@ func (qv *QueryView) buildResultLines() {
 	qv.ResultLines = qv.ResultLines[:0] // Clear but keep capacity
 
 	for _, entry := range qv.results {
-		// Format entry for display
-		line := fmt.Sprintf("DN: %s", entry.DN)
-		qv.ResultLines = append(qv.ResultLines, line)
-
-		// Add a few key attributes for preview
-		for attrName, attrValues := range entry.Attributes {
-			if len(attrValues) > 0 {
-				line = fmt.Sprintf("  %s: %s", attrName, attrValues[0])
-				if len(attrValues) > 1 {
-					line += fmt.Sprintf(" (+%d more)", len(attrValues)-1)
-				}
-				qv.ResultLines = append(qv.ResultLines, line)
-			}
+		row, ok := qv.rowCache[entry]
+		if !ok {
+			row = newResultRow(entry)
 		}
+		qv.ResultLines = append(qv.ResultLines, row.lines...)
 		qv.ResultLines = append(qv.ResultLines, "") // Empty line between entries
 	}
 
@@ -707,3 +1451,48 @@ func (qv *QueryView) parseSubExpressions(remaining string) ([]string, bool) {
 
 	return expressions, true
 }
+
+// Commands lists the query view's actions for the command palette. Those
+// working on results are listed once there are results, and move to them.
+func (qv *QueryView) Commands() []paletteCommand {
+	commands := []paletteCommand{
+		{title: "Run the query", key: "Enter", run: func() tea.Cmd {
+			if qv.loading {
+				return nil
+			}
+			qv.loading = true
+			qv.error = nil
+			return qv.executeQuery()
+		}},
+		{title: "Presence query presets", key: "Ctrl+P", run: qv.openPresets},
+		{title: "Open a saved snapshot", key: "Ctrl+O", run: qv.openSnapshots},
+	}
+	if len(qv.results) == 0 {
+		return commands
+	}
+	browse := func(action func() tea.Cmd) func() tea.Cmd {
+		return func() tea.Cmd {
+			qv.browseResults()
+			return action()
+		}
+	}
+	commands = append(commands,
+		paletteCommand{title: "Count attribute values", key: "f", run: browse(qv.startFacetPrompt)},
+		paletteCommand{title: "Save the results as a snapshot", key: "S", run: browse(qv.startSnapshotPrompt)},
+		paletteCommand{title: "Toggle the compact list", key: "v", run: browse(func() tea.Cmd {
+			qv.toggleCompact()
+			return nil
+		})},
+		paletteCommand{title: "Abbreviate result DNs", key: "a", run: browse(func() tea.Cmd {
+			qv.SetAbbreviateDNs(!qv.abbreviate)
+			return nil
+		})},
+	)
+	if qv.snapshot == nil {
+		commands = append(commands,
+			paletteCommand{title: "Modify every result", key: "M", run: browse(qv.startBulkModify)},
+			paletteCommand{title: "Find and replace in the results' values", key: "R", run: browse(qv.startFindReplace)},
+		)
+	}
+	return commands
+}
@@ -0,0 +1,502 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// BulkModifyStepMsg is sent after the bulk change has been applied to one entry
+type BulkModifyStepMsg struct {
+	Index int
+	DN    string
+	Err   error
+}
+
+// bulkStage is the step of the bulk modify flow
+type bulkStage int
+
+const (
+	bulkStageEdit     bulkStage = iota // Defining the change
+	bulkStagePreview                   // Listing affected DNs and confirming
+	bulkStageApplying                  // Modifying entries one at a time
+	bulkStageReport                    // Showing the outcome per entry
+)
+
+// bulkOperations lists the modify operations offered, in cycling order
+var bulkOperations = []struct {
+	Operation ldap.ModifyOperation
+	Label     string
+}{
+	{ldap.ModifyReplace, "replace"},
+	{ldap.ModifyAdd, "add"},
+	{ldap.ModifyDelete, "delete"},
+}
+
+// bulk modify form fields
+const (
+	bulkFieldOperation = iota
+	bulkFieldAttribute
+	bulkFieldValue
+	bulkFieldCount
+)
+
+// bulkResult is the outcome of modifying one entry
+type bulkResult struct {
+	DN  string
+	Err error
+}
+
+// bulkModify holds the state of applying one attribute change to every result
+type bulkModify struct {
+	stage     bulkStage
+	dns       []string
+	operation int // Index into bulkOperations
+	focus     int
+	attribute textinput.Model
+	value     textinput.Model
+	confirm   *TypeToConfirmView
+	change    ldap.AttributeChange
+	results   []bulkResult
+	progress  *operationProgress // Set while the change is applying
+	cancelled bool               // Stop after the entry being modified
+	scope     string             // Part of the results acted on, see resultsScope
+	offset    int                // First visible row of the preview or report
+	err       error
+
+	// Find and replace edits each entry's values differently, so it has its
+	// own form and a change per entry in place of change
+	replace *valueReplace
+	edits   []valueEdit
+}
+
+// buildChange validates the form and returns the change it describes. An
+// empty value replaces or deletes every value of the attribute.
+func (b *bulkModify) buildChange() (ldap.AttributeChange, error) {
+	attribute := strings.TrimSpace(b.attribute.Value())
+	if attribute == "" {
+		return ldap.AttributeChange{}, fmt.Errorf("attribute name is required")
+	}
+	change := ldap.AttributeChange{
+		Operation: bulkOperations[b.operation].Operation,
+		Attribute: attribute,
+	}
+	if value := b.value.Value(); value != "" {
+		change.Values = []string{value}
+	} else if change.Operation == ldap.ModifyAdd {
+		return ldap.AttributeChange{}, fmt.Errorf("a value is required to add")
+	}
+	return change, nil
+}
+
+// description renders what the bulk modify does as a short sentence
+func (b *bulkModify) description() string {
+	if b.replace != nil {
+		return b.replace.describe()
+	}
+	return describeChange(b.change)
+}
+
+// describeChange renders a change as a short sentence
+func describeChange(change ldap.AttributeChange) string {
+	value := ""
+	if len(change.Values) > 0 {
+		value = fmt.Sprintf("%q", change.Values[0])
+	}
+	switch change.Operation {
+	case ldap.ModifyAdd:
+		return fmt.Sprintf("add %s to %s", value, change.Attribute)
+	case ldap.ModifyDelete:
+		if value == "" {
+			return fmt.Sprintf("delete every %s value", change.Attribute)
+		}
+		return fmt.Sprintf("delete %s from %s", value, change.Attribute)
+	default:
+		if value == "" {
+			return fmt.Sprintf("remove %s", change.Attribute)
+		}
+		return fmt.Sprintf("set %s to %s", change.Attribute, value)
+	}
+}
+
+// failures returns the number of entries the change could not be applied to
+func (b *bulkModify) failures() int {
+	count := 0
+	for _, result := range b.results {
+		if result.Err != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// setFocus moves input focus within the bulk modify form
+func (b *bulkModify) setFocus(field int) {
+	b.focus = field
+	b.attribute.Blur()
+	b.value.Blur()
+	switch field {
+	case bulkFieldAttribute:
+		b.attribute.Focus()
+	case bulkFieldValue:
+		b.value.Focus()
+	}
+}
+
+// startBulkModify opens the form for changing every entry in the results
+func (qv *QueryView) startBulkModify() tea.Cmd {
+	if len(qv.results) == 0 {
+		return nil
+	}
+	if qv.client == nil {
+		return SendError(fmt.Errorf("not connected to an LDAP server"))
+	}
+	if cmd := refuseWrite(qv.client); cmd != nil {
+		return cmd
+	}
+
+	b := &bulkModify{
+		attribute: newBulkInput("attribute, e.g. accountExpires"),
+		value:     newBulkInput("value, empty for all values"),
+		scope:     qv.resultsScope(),
+	}
+	for _, entry := range qv.results {
+		b.dns = append(b.dns, entry.DN)
+	}
+	b.confirm = NewTypeToConfirmView(strconv.Itoa(len(b.dns)), qv.applyBulk)
+	b.confirm.Blur()
+	b.setFocus(bulkFieldAttribute)
+	qv.bulk = b
+	qv.completions = nil
+	return textinput.Blink
+}
+
+// newBulkInput returns an input of the bulk modify forms
+func newBulkInput(placeholder string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = 0
+	ti.Width = 40
+	return ti
+}
+
+// applyBulk starts applying the change to every result, once confirmed
+func (qv *QueryView) applyBulk() tea.Cmd {
+	b := qv.bulk
+	b.err = nil
+	b.stage = bulkStageApplying
+	b.offset = 0
+	b.progress = newOperationProgress(len(b.dns))
+	return tea.Batch(qv.applyBulkStep(0), progressTickCmd())
+}
+
+// handleBulkKey handles key presses while the bulk modify flow is open
+func (qv *QueryView) handleBulkKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b := qv.bulk
+	switch b.stage {
+	case bulkStageEdit:
+		if b.replace != nil {
+			return qv.handleReplaceEditKey(msg)
+		}
+		return qv.handleBulkEditKey(msg)
+
+	case bulkStagePreview:
+		switch msg.String() {
+		case "esc":
+			b.stage = bulkStageEdit
+			b.err = nil
+			b.confirm.Blur()
+			if b.replace != nil {
+				b.replace.setFocus(replaceFieldAttribute)
+			} else {
+				b.setFocus(b.focus)
+			}
+			return qv, nil
+		case "up":
+			if b.offset > 0 {
+				b.offset--
+			}
+			return qv, nil
+		case "down":
+			if b.offset < len(b.dns)-1 {
+				b.offset++
+			}
+			return qv, nil
+		}
+		cmd, err := b.confirm.Update(msg)
+		if err != nil {
+			b.err = err
+		}
+		return qv, cmd
+
+	case bulkStageApplying:
+		if msg.String() == "esc" {
+			b.cancelled = true
+		}
+		return qv, nil
+
+	case bulkStageReport:
+		switch msg.String() {
+		case "esc", "enter":
+			qv.bulk = nil
+		case "up", "k":
+			if b.offset > 0 {
+				b.offset--
+			}
+		case "down", "j":
+			if b.offset < len(b.results)-1 {
+				b.offset++
+			}
+		}
+	}
+	return qv, nil
+}
+
+// handleBulkEditKey handles key presses while the change is being defined
+func (qv *QueryView) handleBulkEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b := qv.bulk
+	switch msg.String() {
+	case "esc":
+		qv.bulk = nil
+		qv.completions = nil
+		return qv, nil
+	case "up", "shift+tab":
+		b.setFocus((b.focus + bulkFieldCount - 1) % bulkFieldCount)
+		qv.completions = nil
+		return qv, nil
+	case "down":
+		b.setFocus((b.focus + 1) % bulkFieldCount)
+		qv.completions = nil
+		return qv, nil
+	case "tab":
+		if b.focus == bulkFieldAttribute {
+			completion := CompleteAttributes(b.attribute.Value(), qv.attributeCandidates())
+			b.attribute.SetValue(completion.Value)
+			b.attribute.CursorEnd()
+			qv.completions = completion.Options
+		} else {
+			b.setFocus((b.focus + 1) % bulkFieldCount)
+		}
+		return qv, nil
+	case "ctrl+t":
+		if b.focus != bulkFieldAttribute {
+			return qv, nil
+		}
+		return qv, qv.openPicker(qv.attributeCandidates(), func(name string) {
+			b.attribute.SetValue(name)
+			b.attribute.CursorEnd()
+		})
+	case "enter":
+		change, err := b.buildChange()
+		if err != nil {
+			b.err = err
+			return qv, nil
+		}
+		b.change = change
+		b.err = nil
+		b.stage = bulkStagePreview
+		b.offset = 0
+		b.attribute.Blur()
+		b.value.Blur()
+		qv.completions = nil
+		b.confirm.SetValue("")
+		return qv, tea.Batch(b.confirm.Focus(), textinput.Blink)
+	}
+
+	if b.focus == bulkFieldOperation {
+		switch msg.String() {
+		case " ", "right", "l":
+			b.operation = (b.operation + 1) % len(bulkOperations)
+		case "left", "h":
+			b.operation = (b.operation + len(bulkOperations) - 1) % len(bulkOperations)
+		}
+		return qv, nil
+	}
+
+	qv.completions = nil
+	var cmd tea.Cmd
+	if b.focus == bulkFieldAttribute {
+		b.attribute, cmd = b.attribute.Update(msg)
+	} else {
+		b.value, cmd = b.value.Update(msg)
+	}
+	return qv, cmd
+}
+
+// applyBulkStep modifies the entry at index. Entries are changed one at a
+// time so progress can be shown and a failure doesn't stop the rest.
+func (qv *QueryView) applyBulkStep(index int) tea.Cmd {
+	client := qv.client
+	dn := qv.bulk.dns[index]
+	change := qv.bulk.change
+	if qv.bulk.edits != nil {
+		change = qv.bulk.edits[index].change()
+	}
+	return func() tea.Msg {
+		client.Throttle()
+		err := client.Modify(dn, []ldap.AttributeChange{change})
+		return BulkModifyStepMsg{Index: index, DN: dn, Err: err}
+	}
+}
+
+// handleBulkStep records the outcome for one entry and moves on to the next
+func (qv *QueryView) handleBulkStep(msg BulkModifyStepMsg) tea.Cmd {
+	b := qv.bulk
+	if b == nil || b.stage != bulkStageApplying {
+		return nil
+	}
+
+	b.results = append(b.results, bulkResult{DN: msg.DN, Err: msg.Err})
+	if b.progress != nil {
+		b.progress.Advance()
+	}
+	next := msg.Index + 1
+	if next < len(b.dns) && !b.cancelled {
+		return qv.applyBulkStep(next)
+	}
+
+	b.stage = bulkStageReport
+	b.offset = 0
+	return SendStatus(qv.bulkSummary())
+}
+
+// handleBulkProgressTick refreshes the progress display while the change is
+// applying and stops refreshing once it has finished
+func (qv *QueryView) handleBulkProgressTick(msg ProgressTickMsg) tea.Cmd {
+	b := qv.bulk
+	if b == nil || b.stage != bulkStageApplying || b.progress == nil {
+		return nil
+	}
+	b.progress.Tick(msg.Time)
+	return progressTickCmd()
+}
+
+// bulkSummary describes the outcome of a finished bulk modify
+func (qv *QueryView) bulkSummary() string {
+	b := qv.bulk
+	failed := b.failures()
+	summary := fmt.Sprintf("Modified %d of %d entries", len(b.results)-failed, len(b.dns))
+	if failed > 0 {
+		summary += fmt.Sprintf(", %d failed", failed)
+	}
+	if skipped := len(b.dns) - len(b.results); skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped after cancelling", skipped)
+	}
+	return summary
+}
+
+// bulkInstructions returns the key help for the current bulk modify step
+func (qv *QueryView) bulkInstructions() string {
+	switch qv.bulk.stage {
+	case bulkStageEdit:
+		if qv.bulk.replace != nil {
+			return "Press [↑↓] move between fields • [←→] toggle regex • [Tab] complete attribute • [Ctrl+T] pick attribute • [Enter] preview • [Esc] cancel"
+		}
+		return "Press [↑↓] move between fields • [←→] change operation • [Tab] complete attribute • [Ctrl+T] pick attribute • [Enter] preview • [Esc] cancel"
+	case bulkStagePreview:
+		return "Type the entry count and press [Enter] to apply • [↑↓] scroll • [Esc] back to the change"
+	case bulkStageApplying:
+		return "Press [Esc] to stop after the current entry"
+	default:
+		return "Press [↑↓] to scroll • [Enter/Esc] close • re-run the query to see the changes"
+	}
+}
+
+// renderBulk renders the bulk modify flow within height lines
+func (qv *QueryView) renderBulk(width, height int) string {
+	b := qv.bulk
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+
+	var lines []string
+	switch b.stage {
+	case bulkStageEdit:
+		if b.replace != nil {
+			lines = qv.renderReplaceForm(titleStyle)
+			break
+		}
+		lines = append(lines, titleStyle.Render(fmt.Sprintf("Bulk modify %d entries%s", len(b.dns), b.scope)))
+		lines = append(lines, renderScopeHint(b.scope)...)
+		fields := []struct {
+			label string
+			value string
+		}{
+			{"Operation: ", "◀ " + bulkOperations[b.operation].Label + " ▶"},
+			{"Attribute: ", b.attribute.View()},
+			{"Value:     ", b.value.View()},
+		}
+		for i, field := range fields {
+			label := editorLabelStyle.Render(field.label)
+			if i == b.focus {
+				label = editorFocusStyle.Render(field.label)
+			}
+			lines = append(lines, label+field.value)
+		}
+
+	case bulkStagePreview:
+		lines = append(lines,
+			titleStyle.Render(fmt.Sprintf("%s This will %s on %d entries%s:", iconWarning, b.description(), len(b.dns), b.scope)),
+		)
+		visible := height - 5
+		if visible < 1 {
+			visible = 1
+		}
+		if b.edits != nil {
+			lines = append(lines, qv.renderReplacePreview(width, visible)...)
+		} else {
+			end := b.offset + visible
+			if end > len(b.dns) {
+				end = len(b.dns)
+			}
+			for _, dn := range b.dns[b.offset:end] {
+				lines = append(lines, "  "+truncateToWidth(dn, width-2))
+			}
+			if hidden := len(b.dns) - (end - b.offset); hidden > 0 {
+				lines = append(lines, dimStyle.Render(fmt.Sprintf("  ... %d more ([↑↓] scroll)", hidden)))
+			}
+		}
+		lines = append(lines, b.confirm.View())
+
+	case bulkStageApplying:
+		lines = append(lines,
+			titleStyle.Render(fmt.Sprintf("Applying: %s", b.description())),
+		)
+		if b.progress != nil {
+			lines = append(lines, b.progress.View(width))
+		}
+		if b.cancelled {
+			lines = append(lines, dimStyle.Render("Stopping after the current entry..."))
+		}
+
+	case bulkStageReport:
+		lines = append(lines, titleStyle.Render(qv.bulkSummary()))
+		visible := height - 1
+		if visible < 1 {
+			visible = 1
+		}
+		end := b.offset + visible
+		if end > len(b.results) {
+			end = len(b.results)
+		}
+		for _, result := range b.results[b.offset:end] {
+			if result.Err != nil {
+				lines = append(lines, truncateToWidth(editorErrorStyle.Render(iconFailed.String()+" "+result.DN+": "+ldap.Explain(result.Err).Error()), width))
+			} else {
+				lines = append(lines, truncateToWidth(iconSuccess.String()+" "+result.DN, width))
+			}
+		}
+	}
+
+	if b.err != nil {
+		lines = append(lines, editorErrorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, ldap.Explain(b.err).Error())))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func bulkTestView() *QueryView {
+	qv := browsingQueryView([]*ldap.Entry{
+		{DN: "uid=a,ou=people,dc=example,dc=com", Attributes: map[string][]string{"accountExpires": {"0"}}},
+		{DN: "uid=b,ou=people,dc=example,dc=com", Attributes: map[string][]string{"accountExpires": {"0"}}},
+		{DN: "uid=c,ou=people,dc=example,dc=com", Attributes: map[string][]string{"mail": {"c@example.com"}}},
+	})
+	qv.client = &ldap.Client{}
+	return qv
+}
+
+func TestBulkModify_BuildChange(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation int
+		attribute string
+		value     string
+		want      string
+		wantErr   string
+	}{
+		{"replace", 0, "accountExpires", "133500000000000000", `set accountExpires to "133500000000000000"`, ""},
+		{"replace empty removes", 0, "description", "", "remove description", ""},
+		{"add", 1, "memberOf", "cn=staff,dc=example,dc=com", `add "cn=staff,dc=example,dc=com" to memberOf`, ""},
+		{"add needs value", 1, "memberOf", "", "", "a value is required"},
+		{"delete value", 2, "mail", "old@example.com", `delete "old@example.com" from mail`, ""},
+		{"delete all", 2, "mail", "", "delete every mail value", ""},
+		{"missing attribute", 0, "  ", "x", "", "attribute name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qv := bulkTestView()
+			qv.startBulkModify()
+			qv.bulk.operation = tt.operation
+			qv.bulk.attribute.SetValue(tt.attribute)
+			qv.bulk.value.SetValue(tt.value)
+
+			change, err := qv.bulk.buildChange()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := describeChange(change); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBulkModify_ConfirmAndReport(t *testing.T) {
+	qv := bulkTestView()
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	if qv.bulk == nil {
+		t.Fatal("Expected Shift+M to open bulk modify")
+	}
+	if !qv.IsInputMode() || !qv.IsCompletingField() {
+		t.Error("Expected bulk modify to capture global keys")
+	}
+
+	typeFacetKeys(qv, "accountExpires")
+	qv.Update(tea.KeyMsg{Type: tea.KeyDown})
+	typeFacetKeys(qv, "0")
+	qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if qv.bulk.stage != bulkStagePreview {
+		t.Fatalf("Expected the preview, got stage %d (err %v)", qv.bulk.stage, qv.bulk.err)
+	}
+
+	preview := qv.renderBulk(100, 20)
+	for _, want := range []string{`set accountExpires to "0" on 3 entries`, "uid=a,ou=people", "uid=c,ou=people", "Type 3 to confirm"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("Expected preview to contain %q, got:\n%s", want, preview)
+		}
+	}
+
+	// The entry count must be typed exactly
+	typeFacetKeys(qv, "2")
+	if _, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || qv.bulk.err == nil {
+		t.Error("Expected a wrong count to be rejected")
+	}
+	qv.bulk.confirm.SetValue("3")
+	_, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if qv.bulk.stage != bulkStageApplying || cmd == nil {
+		t.Fatal("Expected the change to start applying")
+	}
+
+	// Failures don't stop the remaining entries
+	if cmd := qv.handleBulkStep(BulkModifyStepMsg{Index: 0, DN: qv.bulk.dns[0]}); cmd == nil {
+		t.Error("Expected the second entry to be modified next")
+	}
+	if cmd := qv.handleBulkStep(BulkModifyStepMsg{Index: 1, DN: qv.bulk.dns[1], Err: errors.New("insufficient access")}); cmd == nil {
+		t.Error("Expected to continue after a failure")
+	}
+	cmd = qv.handleBulkStep(BulkModifyStepMsg{Index: 2, DN: qv.bulk.dns[2]})
+	if qv.bulk.stage != bulkStageReport {
+		t.Fatal("Expected the report after the last entry")
+	}
+	if msg, ok := cmd().(StatusMsg); !ok || msg.Message != "Modified 2 of 3 entries, 1 failed" {
+		t.Errorf("Unexpected summary: %v", msg)
+	}
+
+	report := qv.renderBulk(100, 20)
+	if !strings.Contains(report, "✗ uid=b,ou=people,dc=example,dc=com: insufficient access") || !strings.Contains(report, "✓ uid=c") {
+		t.Errorf("Expected a per-entry report, got:\n%s", report)
+	}
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if qv.bulk != nil {
+		t.Error("Expected Esc to close the report")
+	}
+}
+
+func TestBulkModify_EnterAloneNeverApplies(t *testing.T) {
+	// However few the results, a write isn't made without the count typed
+	qv := bulkTestView()
+	qv.results = qv.results[:1]
+	qv.startBulkModify()
+	qv.bulk.attribute.SetValue("description")
+	qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if qv.bulk.stage != bulkStagePreview {
+		t.Fatalf("Expected the preview, got stage %d (err %v)", qv.bulk.stage, qv.bulk.err)
+	}
+	if preview := qv.renderBulk(100, 20); !strings.Contains(preview, "Type 1 to confirm") {
+		t.Errorf("Expected the count prompt, got:\n%s", preview)
+	}
+	if _, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyEnter}); qv.bulk.stage != bulkStagePreview || cmd != nil {
+		t.Error("Expected Enter without the count to be refused")
+	}
+}
+
+func TestBulkModify_CancelStopsAfterCurrentEntry(t *testing.T) {
+	qv := bulkTestView()
+	qv.startBulkModify()
+	qv.bulk.attribute.SetValue("description")
+	qv.bulk.change, _ = qv.bulk.buildChange()
+	qv.bulk.stage = bulkStageApplying
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd := qv.handleBulkStep(BulkModifyStepMsg{Index: 0, DN: qv.bulk.dns[0]}); cmd == nil {
+		t.Fatal("Expected a summary")
+	}
+	if qv.bulk.stage != bulkStageReport {
+		t.Error("Expected cancelling to stop after the current entry")
+	}
+	if got := qv.bulkSummary(); got != "Modified 1 of 3 entries, 2 skipped after cancelling" {
+		t.Errorf("Unexpected summary %q", got)
+	}
+}
+
+func TestBulkModify_EscCancelsForm(t *testing.T) {
+	qv := bulkTestView()
+	qv.startBulkModify()
+	qv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if qv.bulk != nil {
+		t.Error("Expected Esc to close the form without changes")
+	}
+}
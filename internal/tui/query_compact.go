@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// compactKeyAttributes are tried in order for the value shown beside each RDN
+// in the compact results list, after any attributes the search asked for
+var compactKeyAttributes = []string{
+	"mail",
+	"displayName",
+	"cn",
+	"uid",
+	"sAMAccountName",
+	"description",
+	"ou",
+}
+
+// compactKeyValue returns "name: value" for the attribute shown beside the
+// entry's RDN, skipping attributes the RDN already shows. It is empty when
+// the entry has none of them.
+func compactKeyValue(entry *ldap.Entry, requested []string) string {
+	rdn, _ := splitRDN(entry.DN)
+	inRDN := func(name string) bool {
+		for _, part := range strings.Split(rdn, "+") {
+			if attribute, _, ok := strings.Cut(part, "="); ok && strings.EqualFold(strings.TrimSpace(attribute), name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, name := range append(append([]string{}, requested...), compactKeyAttributes...) {
+		if name == "*" || name == "+" || inRDN(name) {
+			continue
+		}
+		for attribute, values := range entry.Attributes {
+			if strings.EqualFold(attribute, name) && len(values) > 0 && values[0] != "" {
+				return attribute + ": " + values[0]
+			}
+		}
+	}
+	return ""
+}
+
+// toggleCompact switches the results between the DN and summary table and
+// the compact list
+func (qv *QueryView) toggleCompact() {
+	qv.compact = !qv.compact
+}
+
+// compactHeight is how many results the compact list shows: the table's
+// rows plus the header and legend lines it does without
+func (qv *QueryView) compactHeight() int {
+	height := qv.table.Height() + 1
+	if qv.colorRows {
+		height++
+	}
+	return height
+}
+
+// renderCompact renders the results one line each, with the RDN and one key
+// attribute, so more of them fit in narrow and short terminals
+func (qv *QueryView) renderCompact() string {
+	if len(qv.results) == 0 {
+		return "No results"
+	}
+
+	width := qv.table.Width()
+	if qv.container != nil {
+		width, _ = qv.container.GetContentDimensions()
+	}
+	styles := queryTableStyles()
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	cursor := qv.table.Cursor()
+	end := qv.scrollToCursor(qv.compactHeight(), len(qv.results))
+
+	// Line the key values up after the longest RDN on screen, within half the width
+	rdns := make([]string, len(qv.results))
+	rdnWidth := 0
+	for i := qv.viewport; i < end; i++ {
+		rdns[i], _ = splitRDN(qv.results[i].DN)
+		rdnWidth = max(rdnWidth, lipgloss.Width(rdns[i]))
+	}
+	rdnWidth = min(rdnWidth, max(width/2, 10))
+
+	var lines []string
+	for i := qv.viewport; i < end; i++ {
+		rdn := truncateToWidth(rdns[i], rdnWidth)
+		key := compactKeyValue(qv.results[i], qv.search.Attributes)
+		padding := strings.Repeat(" ", rdnWidth-lipgloss.Width(rdn))
+		line := truncateToWidth(rdn+padding+"  "+key, width)
+
+		if i == cursor {
+			lines = append(lines, styles.Selected.Width(width).Render(line))
+			continue
+		}
+		rdnStyle := lipgloss.NewStyle()
+		if qv.colorRows && i < len(qv.rowKinds) {
+			rdnStyle = entryKindStyle(qv.rowKinds[i])
+		}
+		rest := strings.TrimPrefix(line, rdn)
+		lines = append(lines, rdnStyle.Render(rdn)+keyStyle.Render(rest))
+	}
+
+	return strings.Join(lines, "\n") + qv.renderPaginationInfo()
+}
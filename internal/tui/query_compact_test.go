@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// newCompactQueryView returns a query view browsing count people in the
+// compact list
+func newCompactQueryView(count, width, height int) *QueryView {
+	qv := NewQueryView(nil)
+	qv.SetSize(width, height)
+	var entries []*ldap.Entry
+	for i := 0; i < count; i++ {
+		entries = append(entries, &ldap.Entry{
+			DN: fmt.Sprintf("uid=user%02d,ou=people,dc=example,dc=com", i),
+			Attributes: map[string][]string{
+				"objectClass": {"inetOrgPerson"},
+				"uid":         {fmt.Sprintf("user%02d", i)},
+				"mail":        {fmt.Sprintf("user%02d@example.com", i)},
+			},
+		})
+	}
+	qv.SetResults(entries)
+	qv.inputMode = false
+	qv.Update(keyRune('v'))
+	return qv
+}
+
+func TestQueryView_CompactOneLinePerResult(t *testing.T) {
+	qv := newCompactQueryView(5, 100, 40)
+	if !qv.compact {
+		t.Fatal("Expected v to switch to the compact list")
+	}
+
+	lines := strings.Split(ansi.Strip(qv.renderCompact()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected one line per result, got %d:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	for i, line := range lines {
+		want := fmt.Sprintf("uid=user%02d  mail: user%02d@example.com", i, i)
+		if strings.TrimRight(line, " ") != want {
+			t.Errorf("Expected line %d to be %q, got %q", i, want, line)
+		}
+	}
+
+	qv.Update(keyRune('v'))
+	if qv.compact {
+		t.Error("Expected v to switch back to the table")
+	}
+}
+
+func TestQueryView_CompactRespectsHeight(t *testing.T) {
+	qv := newCompactQueryView(60, 80, 30)
+	height := qv.compactHeight()
+	if height <= qv.table.Height() {
+		t.Errorf("Expected the compact list to fit more rows than the table's %d, got %d", qv.table.Height(), height)
+	}
+
+	lines := strings.Split(qv.renderCompact(), "\n")
+	if len(lines) != height {
+		t.Errorf("Expected %d rows, got %d", height, len(lines))
+	}
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w > 80 {
+			t.Errorf("Line exceeds view width (%d): %q", w, line)
+		}
+	}
+	if view := qv.View(); lipgloss.Height(view) > 30 {
+		t.Errorf("Expected the view to fit in 30 lines, got %d", lipgloss.Height(view))
+	}
+
+	// The list scrolls to the cursor and Enter still opens the entry
+	qv.table.SetCursor(50)
+	view := ansi.Strip(qv.renderCompact())
+	if !strings.Contains(view, "uid=user50") || strings.Contains(view, "uid=user00") {
+		t.Errorf("Expected the list to scroll to the cursor, got:\n%s", view)
+	}
+	_, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected Enter to open the record")
+	}
+	if msg, ok := cmd().(ShowRecordMsg); !ok || msg.Entry.DN != "uid=user50,ou=people,dc=example,dc=com" {
+		t.Errorf("Expected the selected entry to be shown, got %#v", cmd())
+	}
+}
+
+func TestCompactKeyValue(t *testing.T) {
+	entry := &ldap.Entry{
+		DN:         "cn=Alice Smith,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{"cn": {"Alice Smith"}, "displayName": {"Alice"}, "title": {"Engineer"}},
+	}
+	if got := compactKeyValue(entry, nil); got != "displayName: Alice" {
+		t.Errorf("Expected the first key attribute not in the RDN, got %q", got)
+	}
+	if got := compactKeyValue(entry, []string{"cn", "Title"}); got != "title: Engineer" {
+		t.Errorf("Expected a requested attribute to come first, got %q", got)
+	}
+	if got := compactKeyValue(&ldap.Entry{DN: "ou=people,dc=example,dc=com"}, nil); got != "" {
+		t.Errorf("Expected nothing for an entry without key attributes, got %q", got)
+	}
+}
@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// facetCount is the number of results holding one value of an attribute
+type facetCount struct {
+	Value string
+	Count int
+}
+
+// facetPanel holds a value breakdown of one attribute over the query results
+type facetPanel struct {
+	attribute string
+	counts    []facetCount
+	missing   int // Results without the attribute
+	total     int
+	scope     string // Part of the results counted, see resultsScope
+	offset    int    // First visible row
+}
+
+// countAttributeValues counts how many entries hold each value of attribute.
+// A value repeated within one entry is counted once. Counts are sorted from
+// most to least common, ties by value.
+func countAttributeValues(entries []*ldap.Entry, attribute string) ([]facetCount, int) {
+	counts := make(map[string]int)
+	missing := 0
+
+	for _, entry := range entries {
+		values, _ := entry.Lookup(attribute)
+		if len(values) == 0 {
+			missing++
+			continue
+		}
+		seen := make(map[string]bool, len(values))
+		for _, value := range values {
+			if !seen[value] {
+				seen[value] = true
+				counts[value]++
+			}
+		}
+	}
+
+	result := make([]facetCount, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, facetCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result, missing
+}
+
+// startFacetPrompt asks for the attribute to break the results down by
+func (qv *QueryView) startFacetPrompt() tea.Cmd {
+	if len(qv.results) == 0 {
+		return nil
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "attribute, e.g. department"
+	ti.CharLimit = 0
+	ti.Width = 40
+	ti.Focus()
+	if qv.facets != nil {
+		ti.SetValue(qv.facets.attribute)
+		ti.CursorEnd()
+	}
+	qv.facetInput = &ti
+	qv.completions = nil
+	return textinput.Blink
+}
+
+// handleFacetPromptKey handles key presses while the facet attribute prompt is open
+func (qv *QueryView) handleFacetPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		qv.facetInput = nil
+		qv.completions = nil
+		return qv, nil
+	case "tab":
+		completion := CompleteAttributes(qv.facetInput.Value(), qv.resultAttributeNames())
+		qv.facetInput.SetValue(completion.Value)
+		qv.facetInput.CursorEnd()
+		qv.completions = completion.Options
+		return qv, nil
+	case "ctrl+t":
+		return qv, qv.openPicker(qv.resultAttributeNames(), func(name string) {
+			qv.facetInput.SetValue(name)
+			qv.facetInput.CursorEnd()
+		})
+	case "enter":
+		attribute := strings.TrimSpace(qv.facetInput.Value())
+		if attribute == "" {
+			return qv, nil
+		}
+		qv.facetInput = nil
+		qv.completions = nil
+		qv.showFacets(attribute)
+		return qv, nil
+	}
+
+	qv.completions = nil
+	var cmd tea.Cmd
+	*qv.facetInput, cmd = qv.facetInput.Update(msg)
+	return qv, cmd
+}
+
+// resultAttributeNames lists the attribute names present in the results
+func (qv *QueryView) resultAttributeNames() []string {
+	var names []string
+	for _, entry := range qv.results {
+		for name := range entry.Attributes {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// showFacets computes the value breakdown of attribute over the loaded results
+func (qv *QueryView) showFacets(attribute string) {
+	counts, missing := countAttributeValues(qv.results, attribute)
+	qv.facets = &facetPanel{
+		attribute: attribute,
+		counts:    counts,
+		missing:   missing,
+		total:     len(qv.results),
+		scope:     qv.resultsScope(),
+	}
+}
+
+// refreshFacets recounts an open breakdown after the results change, e.g.
+// when another page is loaded, and closes it when there are no results
+func (qv *QueryView) refreshFacets() {
+	if qv.facets == nil {
+		return
+	}
+	if len(qv.results) == 0 {
+		qv.facets = nil
+		qv.facetInput = nil
+		return
+	}
+	offset := qv.facets.offset
+	qv.showFacets(qv.facets.attribute)
+	if offset < len(qv.facets.counts) {
+		qv.facets.offset = offset
+	}
+}
+
+// handleFacetKey handles key presses while the facet breakdown is shown
+func (qv *QueryView) handleFacetKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		qv.facets = nil
+	case "f", "F":
+		return qv, qv.startFacetPrompt()
+	case "up", "k":
+		if qv.facets.offset > 0 {
+			qv.facets.offset--
+		}
+	case "down", "j":
+		if qv.facets.offset < len(qv.facets.counts)-1 {
+			qv.facets.offset++
+		}
+	}
+	return qv, nil
+}
+
+// renderFacets renders the value breakdown as horizontal bars within height lines
+func (qv *QueryView) renderFacets(width, height int) string {
+	panel := qv.facets
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+	barStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("12"))
+
+	header := fmt.Sprintf("%s across %d results%s • %d distinct values", panel.attribute, panel.total, panel.scope, len(panel.counts))
+	if panel.missing > 0 {
+		header += fmt.Sprintf(" • %d without it", panel.missing)
+	}
+	lines := []string{headerStyle.Render(header)}
+
+	if len(panel.counts) == 0 {
+		lines = append(lines, dimStyle.Render("No result has this attribute"))
+		return strings.Join(lines, "\n")
+	}
+
+	// Size the value column to the longest visible value, within limits
+	labelWidth := 0
+	for _, count := range panel.counts {
+		if w := lipgloss.Width(count.Value); w > labelWidth {
+			labelWidth = w
+		}
+	}
+	if labelWidth > width/3 {
+		labelWidth = width / 3
+	}
+	if labelWidth < 8 {
+		labelWidth = 8
+	}
+	barWidth := width - labelWidth - 18
+	if barWidth < 5 {
+		barWidth = 5
+	}
+
+	maxCount := panel.counts[0].Count
+	visible := height - 1
+	if visible < 1 {
+		visible = 1
+	}
+	end := panel.offset + visible
+	if end > len(panel.counts) {
+		end = len(panel.counts)
+	}
+
+	for _, count := range panel.counts[panel.offset:end] {
+		bar := strings.Repeat("█", max(1, count.Count*barWidth/maxCount))
+		label := truncateToWidth(count.Value, labelWidth)
+		label += strings.Repeat(" ", labelWidth-lipgloss.Width(label))
+		percent := float64(count.Count) * 100 / float64(panel.total)
+		line := fmt.Sprintf("%s %s %s", label, barStyle.Render(bar), dimStyle.Render(fmt.Sprintf("%d (%.0f%%)", count.Count, percent)))
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
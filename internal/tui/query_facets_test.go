@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func facetTestEntries() []*ldap.Entry {
+	return []*ldap.Entry{
+		{DN: "cn=a,dc=example,dc=com", Attributes: map[string][]string{"department": {"Sales"}}},
+		{DN: "cn=b,dc=example,dc=com", Attributes: map[string][]string{"Department": {"Engineering", "Sales", "Sales"}}},
+		{DN: "cn=c,dc=example,dc=com", Attributes: map[string][]string{"department": {"Engineering"}}},
+		{DN: "cn=d,dc=example,dc=com", Attributes: map[string][]string{"department": {"Accounting"}}},
+		{DN: "cn=e,dc=example,dc=com", Attributes: map[string][]string{"mail": {"e@example.com"}}},
+	}
+}
+
+func TestCountAttributeValues(t *testing.T) {
+	counts, missing := countAttributeValues(facetTestEntries(), "DEPARTMENT")
+
+	expected := []facetCount{
+		{Value: "Engineering", Count: 2},
+		{Value: "Sales", Count: 2},
+		{Value: "Accounting", Count: 1},
+	}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("Expected counts %v, got %v", expected, counts)
+	}
+	if missing != 1 {
+		t.Errorf("Expected 1 entry without the attribute, got %d", missing)
+	}
+}
+
+func TestCountAttributeValues_NoMatches(t *testing.T) {
+	counts, missing := countAttributeValues(facetTestEntries(), "title")
+	if len(counts) != 0 {
+		t.Errorf("Expected no counts, got %v", counts)
+	}
+	if missing != 5 {
+		t.Errorf("Expected all 5 entries missing the attribute, got %d", missing)
+	}
+}
+
+func browsingQueryView(entries []*ldap.Entry) *QueryView {
+	qv := NewQueryView(nil)
+	qv.SetSize(100, 40)
+	qv.SetResults(entries)
+	qv.inputMode = false
+	return qv
+}
+
+func typeFacetKeys(qv *QueryView, text string) {
+	for _, r := range text {
+		qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestQueryView_FacetPromptAndPanel(t *testing.T) {
+	qv := browsingQueryView(facetTestEntries())
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if qv.facetInput == nil {
+		t.Fatal("Expected F to open the facet prompt")
+	}
+	if !qv.IsInputMode() || !qv.IsCompletingField() {
+		t.Error("Expected the facet prompt to capture typing and tab")
+	}
+
+	typeFacetKeys(qv, "dep")
+	qv.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := qv.facetInput.Value(); !strings.EqualFold(got, "department") {
+		t.Errorf("Expected tab to complete to department, got %q", got)
+	}
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if qv.facetInput != nil {
+		t.Error("Expected enter to close the prompt")
+	}
+	if qv.facets == nil {
+		t.Fatal("Expected enter to show the facet panel")
+	}
+	if qv.IsInputMode() {
+		t.Error("Expected the panel not to count as input mode")
+	}
+
+	view := qv.renderFacets(100, 20)
+	for _, want := range []string{"across 5 results", "3 distinct values", "1 without it", "Engineering", "2 (40%)", "Accounting", "1 (20%)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected facet panel to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if qv.facets != nil {
+		t.Error("Expected esc to close the facet panel")
+	}
+	if qv.inputMode {
+		t.Error("Expected closing the panel to return to the results")
+	}
+}
+
+func TestQueryView_FacetPromptCancel(t *testing.T) {
+	qv := browsingQueryView(facetTestEntries())
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	typeFacetKeys(qv, "mail")
+	qv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	if qv.facetInput != nil || qv.facets != nil {
+		t.Error("Expected esc to cancel the prompt without showing a panel")
+	}
+}
+
+func TestQueryView_FacetsRefreshWithNewPage(t *testing.T) {
+	qv := browsingQueryView(facetTestEntries()[:2])
+	qv.showFacets("department")
+
+	qv.Update(QueryPageMsg{Page: &ldap.SearchPage{Entries: facetTestEntries()[2:]}, Index: 1})
+
+	if qv.facets == nil {
+		t.Fatal("Expected the facet panel to stay open")
+	}
+	if qv.facets.total != 3 {
+		t.Errorf("Expected the counts to cover the 3 results on the new page, got %d", qv.facets.total)
+	}
+}
+
+func TestQueryView_FacetsIgnoredWithoutResults(t *testing.T) {
+	qv := browsingQueryView(nil)
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	if qv.facetInput != nil {
+		t.Error("Expected no facet prompt without results")
+	}
+}
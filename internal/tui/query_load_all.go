@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// LoadAllPageMsg carries the next page of the results while every page is
+// being loaded
+type LoadAllPageMsg struct {
+	Page *ldap.SearchPage
+	Err  error
+}
+
+// loadAllPages holds the state of loading every page of the results
+type loadAllPages struct {
+	confirming bool // Paused at the confirmation threshold, waiting for an answer
+	asked      bool // The threshold was confirmed, so loading goes on without asking again
+	cancelled  bool // Stop once the page being fetched arrives
+	progress   *operationProgress
+}
+
+// SetConfirmThreshold sets how many results loading every page may reach
+// before asking to go on, see config.NeedsConfirmation
+func (qv *QueryView) SetConfirmThreshold(threshold int) {
+	qv.confirmThreshold = threshold
+}
+
+// startLoadAll loads every page of the results into one list, so bulk
+// modify, find and replace and facets cover them all. Loading starts over
+// from the first page unless that is the one shown.
+func (qv *QueryView) startLoadAll() tea.Cmd {
+	if qv.loadingNextPage {
+		return nil
+	}
+	if !qv.hasMore && qv.page == 0 {
+		return SendStatus("Every result is already loaded")
+	}
+	if qv.page > 0 {
+		qv.results = nil
+		qv.currentCookie = nil
+		qv.page = 0
+		qv.pageCookies = nil
+	}
+	qv.loadAll = &loadAllPages{progress: newOperationProgress(0)}
+	qv.loadAll.progress.AdvanceBy(len(qv.results))
+	return tea.Batch(qv.continueLoadAll(), progressTickCmd())
+}
+
+// handleLoadAllProgressTick refreshes the elapsed time while pages load
+func (qv *QueryView) handleLoadAllProgressTick(msg ProgressTickMsg) tea.Cmd {
+	if qv.loadAll == nil {
+		return nil
+	}
+	qv.loadAll.progress.Tick(msg.Time)
+	return progressTickCmd()
+}
+
+// continueLoadAll fetches the next page, first asking to go on when it
+// would take the results past the confirmation threshold
+func (qv *QueryView) continueLoadAll() tea.Cmd {
+	l := qv.loadAll
+	if !l.asked && len(qv.results) > 0 && config.NeedsConfirmation(qv.confirmThreshold, len(qv.results)+1) {
+		l.confirming = true
+		qv.loadingNextPage = false
+		return nil
+	}
+	qv.loadingNextPage = true
+
+	client := qv.client
+	search := qv.search
+	pageSize := qv.pageSize
+	cookie := qv.currentCookie
+	return func() tea.Msg {
+		page, err := client.SearchPaged(search.BaseDN, search.Filter, search.Scope, search.Attributes, pageSize, cookie)
+		return LoadAllPageMsg{Page: page, Err: err}
+	}
+}
+
+// handleLoadAllPage adds a loaded page to the results and fetches the next
+func (qv *QueryView) handleLoadAllPage(msg LoadAllPageMsg) tea.Cmd {
+	l := qv.loadAll
+	if l == nil {
+		return nil
+	}
+	if msg.Err != nil {
+		qv.finishLoadAll()
+		return SendError(fmt.Errorf("stopped loading pages after %d results: %w", len(qv.results), msg.Err))
+	}
+
+	qv.results = append(qv.results, msg.Page.Entries...)
+	l.progress.AdvanceBy(len(msg.Page.Entries))
+	if qv.pageCookies == nil {
+		qv.pageCookies = [][]byte{nil}
+	}
+	qv.hasMore = msg.Page.HasMore
+	qv.currentCookie = msg.Page.Cookie
+	qv.buildTableRows()
+
+	switch {
+	case !qv.hasMore:
+		qv.finishLoadAll()
+		return SendStatus(fmt.Sprintf("Loaded every page: %d results", len(qv.results)))
+	case l.cancelled:
+		qv.finishLoadAll()
+		return SendStatus(fmt.Sprintf("Stopped loading pages at %d results (more available)", len(qv.results)))
+	}
+	return qv.continueLoadAll()
+}
+
+// finishLoadAll ends loading pages, keeping the results loaded so far
+func (qv *QueryView) finishLoadAll() {
+	qv.loadAll = nil
+	qv.loadingNextPage = false
+}
+
+// handleLoadAllKey handles key presses while every page is being loaded
+func (qv *QueryView) handleLoadAllKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := qv.loadAll
+	if l.confirming {
+		switch msg.String() {
+		case "y", "Y":
+			l.confirming = false
+			l.asked = true
+			return qv, qv.continueLoadAll()
+		case "n", "N", "esc", "enter":
+			qv.finishLoadAll()
+			return qv, SendStatus(fmt.Sprintf("Stopped loading pages at %d results (more available)", len(qv.results)))
+		}
+		return qv, nil
+	}
+	if msg.String() == "esc" {
+		l.cancelled = true
+	}
+	return qv, nil
+}
+
+// resultsScope names the part of the results bulk modify, find and replace
+// and facets act on while more pages exist, such as " on this page". It is
+// empty once every result is loaded.
+func (qv *QueryView) resultsScope() string {
+	switch {
+	case qv.page == 0 && !qv.hasMore:
+		return ""
+	case qv.pageSize > 0 && len(qv.results) > int(qv.pageSize):
+		return " in the loaded pages"
+	default:
+		return " on this page"
+	}
+}
+
+// renderScopeHint renders the reminder that an action leaves out the
+// results not loaded, or nothing when every result is loaded
+func renderScopeHint(scope string) []string {
+	if scope == "" {
+		return nil
+	}
+	return []string{lipgloss.NewStyle().Foreground(lipgloss.Color("11")).
+		Render(fmt.Sprintf("%s Only the results%s are included; [Shift+L] loads every page first", iconWarning, scope))}
+}
+
+// renderLoadAll renders the loading line or the question asked at the
+// confirmation threshold
+func (qv *QueryView) renderLoadAll() string {
+	l := qv.loadAll
+	if l.confirming {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Bold(true).
+			Render(fmt.Sprintf("%s More than %d results match. Load them all? [y/N]", iconWarning, len(qv.results)))
+	}
+	status := iconLoading.String() + " Loading every page ([Esc] stops)..."
+	if l.cancelled {
+		status = "Stopping after the page being loaded..."
+	}
+	width := qv.width
+	if qv.container != nil {
+		width, _ = qv.container.GetContentDimensions()
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Italic(true).Render(status) + "\n" + l.progress.View(width)
+}
@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+// newLoadAllTestView returns a query view showing the first of three pages
+// of six results
+func newLoadAllTestView(t *testing.T, threshold int) *QueryView {
+	t.Helper()
+	zone.NewGlobal()
+	entries := []ldaptest.Entry{{DN: "dc=example,dc=com"}}
+	for i := 1; i <= 5; i++ {
+		entries = append(entries, ldaptest.Entry{
+			DN:         fmt.Sprintf("uid=user%d,dc=example,dc=com", i),
+			Attributes: map[string][]string{"uid": {fmt.Sprintf("user%d", i)}},
+		})
+	}
+	server := ldaptest.NewServer(t, entries...)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	qv := NewQueryViewWithPageSize(client, 2)
+	qv.SetSize(120, 40)
+	qv.SetConfirmThreshold(threshold)
+	for _, msg := range runCmd(qv.executeQuery()) {
+		qv.Update(msg)
+	}
+	if len(qv.results) != 2 || !qv.hasMore {
+		t.Fatalf("Expected the first page of 2 results, got %d", len(qv.results))
+	}
+	return qv
+}
+
+// loadPages delivers the pages fetched until loading pauses or ends. The
+// progress ticks batched with the first fetch are left out.
+func loadPages(qv *QueryView, cmd tea.Cmd) {
+	for cmd != nil {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			cmd = batch[0]
+			continue
+		}
+		if _, ok := msg.(LoadAllPageMsg); !ok {
+			return
+		}
+		_, cmd = qv.Update(msg)
+	}
+}
+
+func TestQueryView_LoadsEveryPage(t *testing.T) {
+	qv := newLoadAllTestView(t, -1)
+
+	_, cmd := qv.Update(keyRune('L'))
+	loadPages(qv, cmd)
+
+	if len(qv.results) != 6 || qv.hasMore {
+		t.Errorf("Expected all 6 results loaded, got %d (more: %v)", len(qv.results), qv.hasMore)
+	}
+	if qv.loadAll != nil || qv.loadingNextPage {
+		t.Error("Expected loading to be finished")
+	}
+	if _, cmd := qv.Update(keyRune('L')); cmd == nil {
+		t.Error("Expected a status saying every result is loaded")
+	}
+}
+
+func TestQueryView_LoadAllAsksPastThreshold(t *testing.T) {
+	qv := newLoadAllTestView(t, 3)
+
+	_, cmd := qv.Update(keyRune('L'))
+	loadPages(qv, cmd)
+	if qv.loadAll == nil || !qv.loadAll.confirming {
+		t.Fatal("Expected loading to stop and ask past the threshold")
+	}
+	if len(qv.results) != 4 {
+		t.Errorf("Expected the 4 results up to the threshold, got %d", len(qv.results))
+	}
+	if view := ansi.Strip(qv.View()); !strings.Contains(view, "More than 4 results match. Load them all? [y/N]") {
+		t.Errorf("Expected the question with the count, got:\n%s", view)
+	}
+
+	_, cmd = qv.Update(keyRune('y'))
+	loadPages(qv, cmd)
+	if len(qv.results) != 6 || qv.loadAll != nil {
+		t.Errorf("Expected the rest loaded without asking again, got %d results", len(qv.results))
+	}
+}
+
+func TestQueryView_LoadAllDeclined(t *testing.T) {
+	qv := newLoadAllTestView(t, 3)
+
+	_, cmd := qv.Update(keyRune('L'))
+	loadPages(qv, cmd)
+	qv.Update(keyRune('n'))
+
+	if qv.loadAll != nil {
+		t.Error("Expected loading to stop once declined")
+	}
+	if len(qv.results) != 4 || !qv.hasMore {
+		t.Errorf("Expected the 4 results loaded so far kept, got %d (more: %v)", len(qv.results), qv.hasMore)
+	}
+}
+
+func TestQueryView_LoadAllBelowThresholdDoesNotAsk(t *testing.T) {
+	qv := newLoadAllTestView(t, 10)
+
+	_, cmd := qv.Update(keyRune('L'))
+	loadPages(qv, cmd)
+
+	if len(qv.results) != 6 || qv.loadAll != nil {
+		t.Errorf("Expected every page loaded without asking, got %d results", len(qv.results))
+	}
+}
+
+func TestQueryView_ActionsNameThePageTheyCover(t *testing.T) {
+	qv := newLoadAllTestView(t, -1)
+
+	if got := qv.resultsScope(); got != " on this page" {
+		t.Errorf("Expected the first of several pages to be named, got %q", got)
+	}
+	qv.startBulkModify()
+	if view := ansi.Strip(qv.View()); !strings.Contains(view, "Bulk modify 2 entries on this page") || !strings.Contains(view, "[Shift+L] loads every page first") {
+		t.Errorf("Expected bulk modify to say it covers this page only, got:\n%s", view)
+	}
+	qv.bulk = nil
+	qv.showFacets("uid")
+	if !strings.Contains(ansi.Strip(qv.View()), "uid across 2 results on this page") {
+		t.Error("Expected the facets to say they count this page only")
+	}
+	qv.facets = nil
+
+	_, cmd := qv.Update(keyRune('L'))
+	loadPages(qv, cmd)
+	if got := qv.resultsScope(); got != "" {
+		t.Errorf("Expected no scope once every page is loaded, got %q", got)
+	}
+	qv.startFindReplace()
+	if view := ansi.Strip(qv.View()); !strings.Contains(view, "Find and replace in 6 entries") || strings.Contains(view, "Shift+L") {
+		t.Errorf("Expected find and replace to cover every result, got:\n%s", view)
+	}
+}
+
+func TestQueryView_LoadAllShowsProgress(t *testing.T) {
+	qv := newLoadAllTestView(t, -1)
+
+	_, cmd := qv.Update(keyRune('L'))
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("Expected the first fetch to come with progress ticks")
+	}
+	if view := ansi.Strip(qv.View()); !strings.Contains(view, "Loading every page") || !strings.Contains(view, "2 entries") {
+		t.Errorf("Expected the results loaded so far in the progress, got:\n%s", view)
+	}
+
+	_, next := qv.Update(batch[0]())
+	if !strings.Contains(ansi.Strip(qv.View()), "4 entries") {
+		t.Error("Expected the progress to count the page that arrived")
+	}
+	if _, tick := qv.Update(ProgressTickMsg{}); tick == nil {
+		t.Error("Expected the progress to keep refreshing while pages load")
+	}
+
+	loadPages(qv, next)
+	if _, tick := qv.Update(ProgressTickMsg{}); tick != nil {
+		t.Error("Expected the refreshes to stop once every page is loaded")
+	}
+}
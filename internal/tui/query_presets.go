@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// queryPreset builds a common audit filter around one attribute
+type queryPreset struct {
+	Label  string
+	Filter func(attribute string) string
+}
+
+// queryPresets lists the presence queries offered by the presets menu
+var queryPresets = []queryPreset{
+	{"Entries with the attribute set", func(attr string) string { return presenceFilter(attr, true) }},
+	{"Entries missing the attribute", func(attr string) string { return presenceFilter(attr, false) }},
+	{"Users missing the attribute", func(attr string) string {
+		return "(&(objectClass=person)" + presenceFilter(attr, false) + ")"
+	}},
+	{"Groups missing the attribute", func(attr string) string {
+		return "(&(|(objectClass=group)(objectClass=groupOfNames)(objectClass=groupOfUniqueNames)(objectClass=posixGroup))" + presenceFilter(attr, false) + ")"
+	}},
+}
+
+// presenceFilter returns a filter matching entries that have, or lack, attribute
+func presenceFilter(attribute string, present bool) string {
+	filter := "(" + attribute + "=*)"
+	if !present {
+		return "(!" + filter + ")"
+	}
+	return filter
+}
+
+// validAttributeName reports whether name can be used as an attribute
+// description in a filter: a name or OID, optionally with ;options
+func validAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.', r == ';':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// presetMenu holds the state of the query presets menu
+type presetMenu struct {
+	cursor int
+	input  textinput.Model
+	err    error
+}
+
+// openPresets shows the query presets menu
+func (qv *QueryView) openPresets() tea.Cmd {
+	ti := textinput.New()
+	ti.Placeholder = "attribute, e.g. mail"
+	ti.CharLimit = 0
+	ti.Width = 40
+	ti.Focus()
+	qv.presets = &presetMenu{input: ti}
+	qv.completions = nil
+	return textinput.Blink
+}
+
+// handlePresetKey handles key presses while the presets menu is open
+func (qv *QueryView) handlePresetKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	menu := qv.presets
+	switch msg.String() {
+	case "esc", "ctrl+p":
+		qv.presets = nil
+		qv.completions = nil
+		return qv, nil
+	case "up":
+		if menu.cursor > 0 {
+			menu.cursor--
+		}
+		return qv, nil
+	case "down":
+		if menu.cursor < len(queryPresets)-1 {
+			menu.cursor++
+		}
+		return qv, nil
+	case "tab":
+		completion := CompleteAttributes(menu.input.Value(), qv.attributeCandidates())
+		menu.input.SetValue(completion.Value)
+		menu.input.CursorEnd()
+		qv.completions = completion.Options
+		return qv, nil
+	case "ctrl+t":
+		return qv, qv.openPicker(qv.attributeCandidates(), func(name string) {
+			menu.input.SetValue(name)
+			menu.input.CursorEnd()
+		})
+	case "enter":
+		return qv, qv.runPreset()
+	}
+
+	qv.completions = nil
+	var cmd tea.Cmd
+	menu.input, cmd = menu.input.Update(msg)
+	return qv, cmd
+}
+
+// runPreset fills in the filter from the chosen preset and runs it like a
+// typed query, keeping the base DN and attributes inputs
+func (qv *QueryView) runPreset() tea.Cmd {
+	menu := qv.presets
+	attribute := strings.TrimSpace(menu.input.Value())
+	if !validAttributeName(attribute) {
+		menu.err = fmt.Errorf("enter an attribute name such as mail")
+		return nil
+	}
+
+	qv.SetFilter(queryPresets[menu.cursor].Filter(attribute))
+	if qv.loading {
+		return nil
+	}
+	qv.loading = true
+	qv.error = nil
+	return qv.executeQuery()
+}
+
+// renderPresets renders the query presets menu
+func (qv *QueryView) renderPresets() string {
+	menu := qv.presets
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+
+	attribute := strings.TrimSpace(menu.input.Value())
+	if !validAttributeName(attribute) {
+		attribute = "X"
+	}
+
+	lines := []string{titleStyle.Render("Query presets")}
+	for i, preset := range queryPresets {
+		line := preset.Label + "  " + dimStyle.Render(preset.Filter(attribute))
+		if i == menu.cursor {
+			lines = append(lines, editorFocusStyle.Render("▶ ")+line)
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	lines = append(lines, editorLabelStyle.Render("Attribute: ")+menu.input.View())
+	if menu.err != nil {
+		lines = append(lines, editorErrorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, menu.err.Error())))
+	}
+	return strings.Join(lines, "\n")
+}
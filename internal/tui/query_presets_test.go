@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func TestQueryPresets_Filters(t *testing.T) {
+	want := []string{
+		"(mail=*)",
+		"(!(mail=*))",
+		"(&(objectClass=person)(!(mail=*)))",
+		"(&(|(objectClass=group)(objectClass=groupOfNames)(objectClass=groupOfUniqueNames)(objectClass=posixGroup))(!(mail=*)))",
+	}
+	for i, preset := range queryPresets {
+		if got := preset.Filter("mail"); got != want[i] {
+			t.Errorf("%s: expected %q, got %q", preset.Label, want[i], got)
+		}
+	}
+}
+
+func TestValidAttributeName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"mail":                   true,
+		"userCertificate;binary": true,
+		"2.5.4.3":                true,
+		"":                       false,
+		"mail=*":                 false,
+		"cn)(uid":                false,
+		"given name":             false,
+	} {
+		if got := validAttributeName(name); got != want {
+			t.Errorf("validAttributeName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestQueryView_PresetRunsQuery(t *testing.T) {
+	qv := NewQueryView(nil)
+	qv.baseInput.SetValue("ou=people,dc=example,dc=com")
+	qv.attrsInput.SetValue("cn")
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if qv.presets == nil {
+		t.Fatal("Expected Ctrl+P to open the presets menu")
+	}
+	if !qv.IsInputMode() || !qv.IsCompletingField() {
+		t.Error("Expected the presets menu to capture global keys")
+	}
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyDown})
+	qv.Update(tea.KeyMsg{Type: tea.KeyDown})
+	typeFacetKeys(qv, "mail")
+	if view := qv.renderPresets(); !strings.Contains(view, "(&(objectClass=person)(!(mail=*)))") {
+		t.Errorf("Expected the menu to preview the filter, got:\n%s", view)
+	}
+
+	_, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected the preset query to run")
+	}
+	if qv.presets != nil {
+		t.Error("Expected the menu to close")
+	}
+	if got := qv.textarea.Value(); got != "(&(objectClass=person)(!(mail=*)))" {
+		t.Errorf("Expected the filter to be filled in, got %q", got)
+	}
+	if !qv.loading {
+		t.Error("Expected the query to be loading")
+	}
+	if qv.baseInput.Value() != "ou=people,dc=example,dc=com" || qv.attrsInput.Value() != "cn" {
+		t.Error("Expected the base DN and attributes to be kept")
+	}
+}
+
+func TestQueryView_PresetRejectsInvalidAttribute(t *testing.T) {
+	qv := NewQueryView(nil)
+	qv.textarea.SetValue("(uid=alice)")
+	qv.openPresets()
+	typeFacetKeys(qv, "mail)(uid=*")
+
+	if _, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("Expected an invalid attribute not to run a query")
+	}
+	if qv.presets == nil || qv.presets.err == nil {
+		t.Fatal("Expected the menu to stay open with an error")
+	}
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if qv.presets != nil || qv.textarea.Value() != "(uid=alice)" {
+		t.Error("Expected Esc to close the menu and keep the filter")
+	}
+}
+
+func TestQueryView_PresetFromResults(t *testing.T) {
+	qv := browsingQueryView([]*ldap.Entry{
+		{DN: "uid=a,dc=example,dc=com", Attributes: map[string][]string{"telephoneNumber": {"1"}}},
+	})
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	typeFacetKeys(qv, "tele")
+	qv.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := qv.presets.input.Value(); got != "telephoneNumber" {
+		t.Errorf("Expected tab to complete the attribute from the results, got %q", got)
+	}
+}
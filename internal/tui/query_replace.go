@@ -0,0 +1,311 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// find and replace form fields
+const (
+	replaceFieldAttribute = iota
+	replaceFieldFind
+	replaceFieldReplacement
+	replaceFieldRegex
+	replaceFieldCount
+)
+
+// valueReplace is the find and replace form. It is kept while the preview is
+// shown so Esc can return to it.
+type valueReplace struct {
+	focus       int
+	attribute   textinput.Model
+	find        textinput.Model
+	replacement textinput.Model
+	regex       bool // Find is a regular expression and the replacement may use $1
+}
+
+// setFocus moves input focus within the find and replace form
+func (r *valueReplace) setFocus(field int) {
+	r.focus = field
+	r.attribute.Blur()
+	r.find.Blur()
+	r.replacement.Blur()
+	switch field {
+	case replaceFieldAttribute:
+		r.attribute.Focus()
+	case replaceFieldFind:
+		r.find.Focus()
+	case replaceFieldReplacement:
+		r.replacement.Focus()
+	}
+}
+
+// describe renders the replacement as a short sentence
+func (r *valueReplace) describe() string {
+	kind := "text"
+	if r.regex {
+		kind = "pattern"
+	}
+	return fmt.Sprintf("replace %s %q with %q in %s", kind, r.find.Value(), r.replacement.Value(), strings.TrimSpace(r.attribute.Value()))
+}
+
+// valueEdit is the change find and replace makes to one entry's values
+type valueEdit struct {
+	DN        string
+	Attribute string // As the entry names it
+	Before    []string
+	After     []string
+}
+
+// change returns the modify change that gives the entry its new values
+func (e valueEdit) change() ldap.AttributeChange {
+	return ldap.AttributeChange{Operation: ldap.ModifyReplace, Attribute: e.Attribute, Values: e.After}
+}
+
+// newReplacer returns the function that replaces every match of find in a
+// value. With useRegex find is a regular expression and the replacement may
+// refer to its groups as $1 or ${name}.
+func newReplacer(find, replacement string, useRegex bool) (func(string) string, error) {
+	if find == "" {
+		return nil, fmt.Errorf("text to find is required")
+	}
+	if !useRegex {
+		return func(value string) string {
+			return strings.ReplaceAll(value, find, replacement)
+		}, nil
+	}
+	pattern, err := regexp.Compile(find)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return func(value string) string {
+		return pattern.ReplaceAllString(value, replacement)
+	}, nil
+}
+
+// replaceValues computes the edits replacing find with replacement in the
+// values of attribute makes to entries. Entries with no changed value are
+// left out. Values left empty are removed and duplicates merged, as LDAP
+// allows neither.
+func replaceValues(entries []*ldap.Entry, attribute, find, replacement string, useRegex bool) ([]valueEdit, error) {
+	attribute = strings.TrimSpace(attribute)
+	if attribute == "" {
+		return nil, fmt.Errorf("attribute name is required")
+	}
+	replace, err := newReplacer(find, replacement, useRegex)
+	if err != nil {
+		return nil, err
+	}
+
+	var edits []valueEdit
+	for _, entry := range entries {
+		for name, values := range entry.Attributes {
+			if !strings.EqualFold(name, attribute) {
+				continue
+			}
+			after := make([]string, 0, len(values))
+			changed := false
+			for _, value := range values {
+				replaced := replace(value)
+				changed = changed || replaced != value
+				if replaced != "" && !slices.Contains(after, replaced) {
+					after = append(after, replaced)
+				}
+			}
+			if !changed {
+				continue
+			}
+			if entry.HasMoreValues(name) {
+				// Replacing the values read would drop the rest
+				return nil, fmt.Errorf("%s has more %s values than the results hold; open it in the record view to load them", entry.DN, name)
+			}
+			edits = append(edits, valueEdit{DN: entry.DN, Attribute: name, Before: values, After: after})
+		}
+	}
+	return edits, nil
+}
+
+// startFindReplace opens the find and replace form for the results
+func (qv *QueryView) startFindReplace() tea.Cmd {
+	if len(qv.results) == 0 {
+		return nil
+	}
+	if qv.client == nil {
+		return SendError(fmt.Errorf("not connected to an LDAP server"))
+	}
+	if cmd := refuseWrite(qv.client); cmd != nil {
+		return cmd
+	}
+
+	r := &valueReplace{
+		attribute:   newBulkInput("attribute, e.g. mail"),
+		find:        newBulkInput("text to find, e.g. @old.example.com"),
+		replacement: newBulkInput("replacement, empty removes the match"),
+	}
+	r.setFocus(replaceFieldAttribute)
+	qv.bulk = &bulkModify{replace: r, scope: qv.resultsScope()}
+	qv.completions = nil
+	return textinput.Blink
+}
+
+// handleReplaceEditKey handles key presses while the find and replace form
+// is open
+func (qv *QueryView) handleReplaceEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	b := qv.bulk
+	r := b.replace
+	switch msg.String() {
+	case "esc":
+		qv.bulk = nil
+		qv.completions = nil
+		return qv, nil
+	case "up", "shift+tab":
+		r.setFocus((r.focus + replaceFieldCount - 1) % replaceFieldCount)
+		qv.completions = nil
+		return qv, nil
+	case "down":
+		r.setFocus((r.focus + 1) % replaceFieldCount)
+		qv.completions = nil
+		return qv, nil
+	case "tab":
+		if r.focus == replaceFieldAttribute {
+			completion := CompleteAttributes(r.attribute.Value(), qv.attributeCandidates())
+			r.attribute.SetValue(completion.Value)
+			r.attribute.CursorEnd()
+			qv.completions = completion.Options
+		} else {
+			r.setFocus((r.focus + 1) % replaceFieldCount)
+		}
+		return qv, nil
+	case "ctrl+t":
+		if r.focus != replaceFieldAttribute {
+			return qv, nil
+		}
+		return qv, qv.openPicker(qv.attributeCandidates(), func(name string) {
+			r.attribute.SetValue(name)
+			r.attribute.CursorEnd()
+		})
+	case "enter":
+		return qv, qv.previewReplace()
+	}
+
+	if r.focus == replaceFieldRegex {
+		switch msg.String() {
+		case " ", "left", "right", "h", "l":
+			r.regex = !r.regex
+		}
+		return qv, nil
+	}
+
+	qv.completions = nil
+	var cmd tea.Cmd
+	switch r.focus {
+	case replaceFieldAttribute:
+		r.attribute, cmd = r.attribute.Update(msg)
+	case replaceFieldFind:
+		r.find, cmd = r.find.Update(msg)
+	default:
+		r.replacement, cmd = r.replacement.Update(msg)
+	}
+	return qv, cmd
+}
+
+// previewReplace computes the edits and shows them for confirmation, with
+// the count of affected entries to type for large changes
+func (qv *QueryView) previewReplace() tea.Cmd {
+	b := qv.bulk
+	r := b.replace
+	edits, err := replaceValues(qv.results, r.attribute.Value(), r.find.Value(), r.replacement.Value(), r.regex)
+	if err == nil && len(edits) == 0 {
+		err = fmt.Errorf("no %s value in the results contains %q", strings.TrimSpace(r.attribute.Value()), r.find.Value())
+	}
+	if err != nil {
+		b.err = err
+		return nil
+	}
+
+	b.edits = edits
+	b.dns = nil
+	for _, edit := range edits {
+		b.dns = append(b.dns, edit.DN)
+	}
+	b.confirm = NewTypeToConfirmView(strconv.Itoa(len(b.dns)), qv.applyBulk)
+	b.confirm.Blur()
+	b.err = nil
+	b.stage = bulkStagePreview
+	b.offset = 0
+	r.setFocus(-1)
+	qv.completions = nil
+	return tea.Batch(b.confirm.Focus(), textinput.Blink)
+}
+
+// renderReplaceForm renders the find and replace form
+func (qv *QueryView) renderReplaceForm(titleStyle lipgloss.Style) []string {
+	r := qv.bulk.replace
+	regex := "off"
+	if r.regex {
+		regex = "on"
+	}
+	lines := []string{titleStyle.Render(fmt.Sprintf("Find and replace in %d entries%s", len(qv.results), qv.bulk.scope))}
+	lines = append(lines, renderScopeHint(qv.bulk.scope)...)
+	fields := []struct {
+		label string
+		value string
+	}{
+		{"Attribute: ", r.attribute.View()},
+		{"Find:      ", r.find.View()},
+		{"Replace:   ", r.replacement.View()},
+		{"Regex:     ", "◀ " + regex + " ▶"},
+	}
+	for i, field := range fields {
+		label := editorLabelStyle.Render(field.label)
+		if i == r.focus {
+			label = editorFocusStyle.Render(field.label)
+		}
+		lines = append(lines, label+field.value)
+	}
+	return lines
+}
+
+// replacePreviewStyles mark removed and added values in the preview
+var (
+	replaceRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	replaceAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+)
+
+// renderReplacePreview lists each affected entry with the values it loses
+// and gains, starting at the scroll offset, within visible lines
+func (qv *QueryView) renderReplacePreview(width, visible int) []string {
+	b := qv.bulk
+	var lines []string
+	shown := 0
+	for _, edit := range b.edits[b.offset:] {
+		entryLines := []string{"  " + truncateToWidth(edit.DN, width-2)}
+		for _, value := range edit.Before {
+			if !slices.Contains(edit.After, value) {
+				entryLines = append(entryLines, replaceRemovedStyle.Render(truncateToWidth("    - "+value, width)))
+			}
+		}
+		for _, value := range edit.After {
+			if !slices.Contains(edit.Before, value) {
+				entryLines = append(entryLines, replaceAddedStyle.Render(truncateToWidth("    + "+value, width)))
+			}
+		}
+		if shown > 0 && len(lines)+len(entryLines) > visible {
+			break
+		}
+		lines = append(lines, entryLines...)
+		shown++
+	}
+	if hidden := len(b.edits) - shown; hidden > 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(fmt.Sprintf("  ... %d more ([↑↓] scroll)", hidden)))
+	}
+	return lines
+}
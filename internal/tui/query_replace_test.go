@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func TestReplaceValues(t *testing.T) {
+	entries := []*ldap.Entry{
+		{DN: "uid=a,dc=example,dc=com", Attributes: map[string][]string{"mail": {"a@old.example.com"}}},
+		{DN: "uid=b,dc=example,dc=com", Attributes: map[string][]string{"Mail": {"b@new.example.com", "b.alias@old.example.com"}}},
+		{DN: "uid=c,dc=example,dc=com", Attributes: map[string][]string{"mail": {"c@new.example.com"}}},
+		{DN: "uid=d,dc=example,dc=com", Attributes: map[string][]string{"cn": {"old.example.com"}}},
+	}
+
+	tests := []struct {
+		name        string
+		find        string
+		replacement string
+		regex       bool
+		want        map[string][]string // DN to the new values
+		wantErr     string
+	}{
+		{
+			name: "substring", find: "@old.example.com", replacement: "@new.example.com",
+			want: map[string][]string{
+				"uid=a,dc=example,dc=com": {"a@new.example.com"},
+				"uid=b,dc=example,dc=com": {"b@new.example.com", "b.alias@new.example.com"},
+			},
+		},
+		{
+			name: "regex with groups", find: `^([a-z]+)@(old|new)\.`, replacement: "${1}@corp.", regex: true,
+			want: map[string][]string{
+				"uid=a,dc=example,dc=com": {"a@corp.example.com"},
+				"uid=b,dc=example,dc=com": {"b@corp.example.com", "b.alias@old.example.com"},
+				"uid=c,dc=example,dc=com": {"c@corp.example.com"},
+			},
+		},
+		{
+			name: "values made equal are merged", find: `^b(\.alias)?@.*$`, replacement: "b@example.com", regex: true,
+			want: map[string][]string{"uid=b,dc=example,dc=com": {"b@example.com"}},
+		},
+		{
+			name: "values left empty are removed", find: "b.alias@old.example.com", replacement: "",
+			want: map[string][]string{"uid=b,dc=example,dc=com": {"b@new.example.com"}},
+		},
+		{name: "no match", find: "@elsewhere", replacement: "x", want: map[string][]string{}},
+		{name: "missing find", find: "", replacement: "x", wantErr: "text to find is required"},
+		{name: "invalid pattern", find: "([", replacement: "x", regex: true, wantErr: "invalid pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits, err := replaceValues(entries, " mail ", tt.find, tt.replacement, tt.regex)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(edits) != len(tt.want) {
+				t.Fatalf("Expected %d edited entries, got %+v", len(tt.want), edits)
+			}
+			for _, edit := range edits {
+				want, ok := tt.want[edit.DN]
+				if !ok || !slices.Equal(edit.After, want) {
+					t.Errorf("Expected %s to get %v, got %v", edit.DN, want, edit.After)
+				}
+				change := edit.change()
+				if change.Operation != ldap.ModifyReplace || !slices.Equal(change.Values, edit.After) {
+					t.Errorf("Expected a replace of every value, got %+v", change)
+				}
+			}
+		})
+	}
+
+	// The entry's own spelling of the attribute is modified, and the old
+	// values are kept for the preview
+	edits, _ := replaceValues(entries, "MAIL", "old", "new", false)
+	for _, edit := range edits {
+		if edit.DN == "uid=b,dc=example,dc=com" && (edit.Attribute != "Mail" || len(edit.Before) != 2) {
+			t.Errorf("Expected the entry's attribute name and old values, got %+v", edit)
+		}
+	}
+
+	if _, err := replaceValues(entries, "", "old", "new", false); err == nil {
+		t.Error("Expected an attribute name to be required")
+	}
+}
+
+func TestReplaceValues_PartialValues(t *testing.T) {
+	entries := []*ldap.Entry{{
+		DN:         "cn=big,dc=example,dc=com",
+		Attributes: map[string][]string{"member": {"uid=a,ou=old,dc=example,dc=com"}},
+		MoreValues: map[string]int{"member": 1500},
+	}}
+	if _, err := replaceValues(entries, "member", "ou=old", "ou=new", false); err == nil || !strings.Contains(err.Error(), "more member values") {
+		t.Errorf("Expected partially read values to be refused, got %v", err)
+	}
+}
+
+func TestFindReplace_PreviewAndApply(t *testing.T) {
+	qv := bulkTestView()
+	qv.results[2].Attributes["mail"] = []string{"c@old.example.com", "c2@example.com"}
+
+	qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	if qv.bulk == nil || qv.bulk.replace == nil {
+		t.Fatal("Expected Shift+R to open find and replace")
+	}
+	typeFacetKeys(qv, "mail")
+	qv.Update(tea.KeyMsg{Type: tea.KeyDown})
+	typeFacetKeys(qv, "old.example")
+	qv.Update(tea.KeyMsg{Type: tea.KeyDown})
+	typeFacetKeys(qv, "new.example")
+	qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if qv.bulk.stage != bulkStagePreview {
+		t.Fatalf("Expected the preview, got stage %d (err %v)", qv.bulk.stage, qv.bulk.err)
+	}
+
+	// Only the entry with a match is affected, and its values are shown
+	// before and after
+	if !slices.Equal(qv.bulk.dns, []string{"uid=c,ou=people,dc=example,dc=com"}) {
+		t.Errorf("Expected only uid=c to be affected, got %v", qv.bulk.dns)
+	}
+	preview := ansi.Strip(qv.renderBulk(100, 20))
+	for _, want := range []string{`replace text "old.example" with "new.example" in mail on 1 entries`, "- c@old.example.com", "+ c@new.example.com", "Type 1 to confirm"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("Expected preview to contain %q, got:\n%s", want, preview)
+		}
+	}
+	if strings.Contains(preview, "c2@example.com") {
+		t.Errorf("Expected unchanged values to be left out of the preview, got:\n%s", preview)
+	}
+
+	// Esc returns to the form, with the settings kept
+	qv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if qv.bulk.stage != bulkStageEdit || qv.bulk.replace.find.Value() != "old.example" {
+		t.Fatal("Expected Esc to return to the find and replace form")
+	}
+	qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	qv.bulk.confirm.SetValue("1")
+	if _, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyEnter}); qv.bulk.stage != bulkStageApplying || cmd == nil {
+		t.Fatal("Expected the replacement to start applying")
+	}
+}
+
+func TestFindReplace_Errors(t *testing.T) {
+	qv := bulkTestView()
+	qv.startFindReplace()
+	qv.bulk.replace.attribute.SetValue("mail")
+	qv.bulk.replace.find.SetValue("nowhere")
+	qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if qv.bulk.stage != bulkStageEdit || qv.bulk.err == nil || !strings.Contains(qv.bulk.err.Error(), `no mail value in the results contains "nowhere"`) {
+		t.Errorf("Expected no matches to be reported in the form, got %v", qv.bulk.err)
+	}
+
+	// The regex toggle is its own field
+	qv.bulk.replace.setFocus(replaceFieldRegex)
+	qv.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if !qv.bulk.replace.regex {
+		t.Error("Expected Space to turn on regex matching")
+	}
+
+	// Snapshots can't be modified
+	qv = bulkTestView()
+	qv.snapshot = &querySnapshot{}
+	qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	if qv.bulk != nil {
+		t.Error("Expected snapshot results not to be modified")
+	}
+}
@@ -0,0 +1,356 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// snapshotExt is the file extension of saved query snapshots
+const snapshotExt = ".json"
+
+// querySnapshot is a query together with the results it returned at one
+// point in time, saved for audits and before/after comparisons
+type querySnapshot struct {
+	Name       string          `json:"name"`
+	Taken      time.Time       `json:"taken"`
+	Connection string          `json:"connection,omitempty"`
+	BaseDN     string          `json:"base_dn"`
+	Scope      int             `json:"scope"`
+	Filter     string          `json:"filter"`
+	Attributes []string        `json:"attributes,omitempty"` // Empty when all user attributes were requested
+	Entries    []snapshotEntry `json:"entries"`
+
+	path string // File the snapshot was read from
+}
+
+// snapshotEntry is one result of a snapshot
+type snapshotEntry struct {
+	DN         string              `json:"dn"`
+	Attributes map[string][]string `json:"attributes,omitempty"`
+}
+
+// newQuerySnapshot records search and the entries it returned as of taken.
+// An empty name is replaced by the filter.
+func newQuerySnapshot(name, connection string, search ldap.SearchParams, entries []*ldap.Entry, taken time.Time) *querySnapshot {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = search.Filter
+	}
+	snapshot := &querySnapshot{
+		Name:       name,
+		Taken:      taken.UTC().Truncate(time.Second),
+		Connection: connection,
+		BaseDN:     search.BaseDN,
+		Scope:      search.Scope,
+		Filter:     search.Filter,
+		Attributes: search.Attributes,
+		Entries:    make([]snapshotEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		snapshot.Entries = append(snapshot.Entries, snapshotEntry{DN: entry.DN, Attributes: entry.Attributes})
+	}
+	return snapshot
+}
+
+// search returns the search the snapshot was taken with
+func (s *querySnapshot) search() ldap.SearchParams {
+	return ldap.SearchParams{BaseDN: s.BaseDN, Scope: s.Scope, Filter: s.Filter, Attributes: s.Attributes}
+}
+
+// entries returns the snapshot's results as entries for the results table
+func (s *querySnapshot) entries() []*ldap.Entry {
+	entries := make([]*ldap.Entry, 0, len(s.Entries))
+	for _, entry := range s.Entries {
+		attributes := entry.Attributes
+		if attributes == nil {
+			attributes = make(map[string][]string)
+		}
+		entries = append(entries, &ldap.Entry{DN: entry.DN, Attributes: attributes})
+	}
+	return entries
+}
+
+// fileName names the snapshot's file after when it was taken and its name,
+// so a directory listing sorts oldest first
+func (s *querySnapshot) fileName() string {
+	name := s.Taken.Format("20060102-150405")
+	if slug := fileSlug(s.Name); slug != "" {
+		name += "-" + slug
+	}
+	return name + snapshotExt
+}
+
+// fileSlug reduces name to lower-case letters and digits joined by dashes,
+// at most about 40 long, for use in a file name
+func fileSlug(name string) string {
+	var slug strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			slug.WriteRune(r)
+		case slug.Len() > 0 && !strings.HasSuffix(slug.String(), "-"):
+			slug.WriteByte('-')
+		}
+		if slug.Len() >= 40 {
+			break
+		}
+	}
+	return strings.Trim(slug.String(), "-")
+}
+
+// marshalSnapshot encodes a snapshot as indented JSON
+func marshalSnapshot(snapshot *querySnapshot) ([]byte, error) {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// unmarshalSnapshot decodes a snapshot written by marshalSnapshot
+func unmarshalSnapshot(data []byte) (*querySnapshot, error) {
+	var snapshot querySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Filter == "" {
+		return nil, fmt.Errorf("not a query snapshot: no filter")
+	}
+	return &snapshot, nil
+}
+
+// writeSnapshot saves a snapshot to a new file in dir and returns its path.
+// Results can hold personal data, so only the user can read them.
+func writeSnapshot(dir string, snapshot *querySnapshot) (string, error) {
+	data, err := marshalSnapshot(snapshot)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the snapshot: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, snapshot.fileName())
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write snapshot %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// readSnapshot loads the snapshot saved at path
+func readSnapshot(path string) (*querySnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+	snapshot, err := unmarshalSnapshot(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	snapshot.path = path
+	return snapshot, nil
+}
+
+// listSnapshots loads the snapshots saved in dir, newest first. A missing
+// directory has none; files that don't parse are skipped.
+func listSnapshots(dir string) ([]*querySnapshot, error) {
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots in %s: %w", dir, err)
+	}
+
+	var snapshots []*querySnapshot
+	for _, file := range files {
+		if file.IsDir() || !strings.EqualFold(filepath.Ext(file.Name()), snapshotExt) {
+			continue
+		}
+		if snapshot, err := readSnapshot(filepath.Join(dir, file.Name())); err == nil {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Taken.After(snapshots[j].Taken)
+	})
+	return snapshots, nil
+}
+
+// snapshotBrowser lists the saved snapshots to reopen one
+type snapshotBrowser struct {
+	snapshots []*querySnapshot
+	cursor    int
+}
+
+// SetSnapshotDir sets the directory result snapshots are saved in and read
+// from, see config.SnapshotDir
+func (qv *QueryView) SetSnapshotDir(dir string) {
+	qv.snapshotDir = dir
+}
+
+// startSnapshotPrompt asks for the name to save the results under
+func (qv *QueryView) startSnapshotPrompt() tea.Cmd {
+	if len(qv.results) == 0 {
+		return nil
+	}
+	if qv.snapshot != nil {
+		return SendError(fmt.Errorf("these results are already the snapshot %q", qv.snapshot.Name))
+	}
+	if qv.snapshotDir == "" {
+		return SendError(fmt.Errorf("no directory to save snapshots in"))
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "name, e.g. admins before cleanup (default: the filter)"
+	ti.CharLimit = 0
+	ti.Width = 50
+	ti.Focus()
+	qv.snapshotInput = &ti
+	return textinput.Blink
+}
+
+// handleSnapshotPromptKey handles key presses while the snapshot name prompt is open
+func (qv *QueryView) handleSnapshotPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		qv.snapshotInput = nil
+		return qv, nil
+	case "enter":
+		name := qv.snapshotInput.Value()
+		qv.snapshotInput = nil
+		return qv, qv.saveSnapshot(name)
+	}
+
+	input, cmd := qv.snapshotInput.Update(msg)
+	qv.snapshotInput = &input
+	return qv, cmd
+}
+
+// saveSnapshot writes the results shown, with the search that returned them,
+// to a timestamped file in the snapshot directory
+func (qv *QueryView) saveSnapshot(name string) tea.Cmd {
+	connection := ""
+	if qv.client != nil {
+		config := qv.client.Config()
+		connection = config.ConnectionName
+		if connection == "" {
+			connection = config.Host
+		}
+	}
+	search := qv.search
+	if search.Filter == "" {
+		search.Filter = strings.TrimSpace(qv.textarea.Value())
+	}
+	snapshot := newQuerySnapshot(name, connection, search, qv.results, time.Now())
+	dir := qv.snapshotDir
+	return func() tea.Msg {
+		path, err := writeSnapshot(dir, snapshot)
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return StatusMsg{Message: fmt.Sprintf("Saved %d results as snapshot %q to %s", len(snapshot.Entries), snapshot.Name, path), Level: ToastSuccess}
+	}
+}
+
+// openSnapshots shows the saved snapshots to choose one to reopen
+func (qv *QueryView) openSnapshots() tea.Cmd {
+	if qv.snapshotDir == "" {
+		return SendError(fmt.Errorf("no directory to read snapshots from"))
+	}
+	snapshots, err := listSnapshots(qv.snapshotDir)
+	if err != nil {
+		return SendError(err)
+	}
+	if len(snapshots) == 0 {
+		return SendStatus("No snapshots saved in " + qv.snapshotDir)
+	}
+	qv.snapshots = &snapshotBrowser{snapshots: snapshots}
+	return nil
+}
+
+// handleSnapshotBrowserKey handles key presses while the snapshot list is open
+func (qv *QueryView) handleSnapshotBrowserKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	browser := qv.snapshots
+	switch msg.String() {
+	case "esc", "ctrl+o":
+		qv.snapshots = nil
+	case "up", "k":
+		if browser.cursor > 0 {
+			browser.cursor--
+		}
+	case "down", "j":
+		if browser.cursor < len(browser.snapshots)-1 {
+			browser.cursor++
+		}
+	case "enter":
+		qv.snapshots = nil
+		return qv, qv.showSnapshot(browser.snapshots[browser.cursor])
+	}
+	return qv, nil
+}
+
+// showSnapshot shows a snapshot's results read-only in place of live ones.
+// Its query fills the form so it can be run again to compare.
+func (qv *QueryView) showSnapshot(snapshot *querySnapshot) tea.Cmd {
+	qv.snapshot = snapshot
+	qv.results = snapshot.entries()
+	qv.search = snapshot.search()
+	qv.textarea.SetValue(snapshot.Filter)
+	qv.baseInput.SetValue(snapshot.BaseDN)
+	qv.attrsInput.SetValue(strings.Join(snapshot.Attributes, ", "))
+
+	qv.facets = nil
+	qv.facetInput = nil
+	qv.hasMore = false
+	qv.currentCookie = nil
+	qv.page = 0
+	qv.pageCookies = nil
+	qv.error = nil
+	qv.inputMode = false
+	qv.textarea.Blur()
+	qv.table.Focus()
+	qv.table.SetCursor(0)
+	qv.buildTableRows()
+	return SendStatus(fmt.Sprintf("Showing snapshot %q: %d results as of %s", snapshot.Name, len(qv.results), snapshot.Taken.Local().Format(time.DateTime)))
+}
+
+// renderSnapshotBanner marks results shown from a snapshot
+func (qv *QueryView) renderSnapshotBanner() string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("11")).Bold(true).Padding(0, 1)
+	text := fmt.Sprintf("SNAPSHOT %s • %s", qv.snapshot.Name, qv.snapshot.Taken.Local().Format(time.DateTime))
+	if qv.snapshot.Connection != "" {
+		text += " • " + qv.snapshot.Connection
+	}
+	return style.Render(text + " • read-only")
+}
+
+// renderSnapshots renders the list of saved snapshots
+func (qv *QueryView) renderSnapshots() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+
+	lines := []string{titleStyle.Render("Saved snapshots") + dimStyle.Render("  "+qv.snapshotDir)}
+	for i, snapshot := range qv.snapshots.snapshots {
+		line := fmt.Sprintf("%s  %s  %s", snapshot.Taken.Local().Format(time.DateTime), snapshot.Name,
+			dimStyle.Render(fmt.Sprintf("%d results • %s", len(snapshot.Entries), snapshot.Filter)))
+		if i == qv.snapshots.cursor {
+			lines = append(lines, editorFocusStyle.Render("▶ ")+line)
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
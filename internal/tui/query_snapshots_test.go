@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func snapshotEntries() []*ldap.Entry {
+	return []*ldap.Entry{
+		{DN: "uid=alice,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"alice"}, "mail": {"alice@example.com"}}},
+		{DN: "uid=bob,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"bob"}, "memberOf": {"cn=a,dc=example,dc=com", "cn=b,dc=example,dc=com"}}},
+	}
+}
+
+func TestQuerySnapshot_RoundTrip(t *testing.T) {
+	search := ldap.SearchParams{BaseDN: "ou=people,dc=example,dc=com", Scope: 2, Filter: "(uid=*)", Attributes: []string{"uid", "mail", "memberOf"}}
+	taken := time.Date(2026, 3, 14, 9, 26, 53, 589793000, time.UTC)
+	snapshot := newQuerySnapshot("Before cleanup", "prod", search, snapshotEntries(), taken)
+
+	data, err := marshalSnapshot(snapshot)
+	if err != nil {
+		t.Fatalf("Failed to encode the snapshot: %v", err)
+	}
+	decoded, err := unmarshalSnapshot(data)
+	if err != nil {
+		t.Fatalf("Failed to decode the snapshot: %v", err)
+	}
+
+	if decoded.Name != "Before cleanup" || decoded.Connection != "prod" || !decoded.Taken.Equal(taken.Truncate(time.Second)) {
+		t.Errorf("Expected the name, connection and time to survive, got %q %q %s", decoded.Name, decoded.Connection, decoded.Taken)
+	}
+	if !reflect.DeepEqual(decoded.search(), search) {
+		t.Errorf("Expected the search %+v, got %+v", search, decoded.search())
+	}
+	entries := decoded.entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	for i, want := range snapshotEntries() {
+		if entries[i].DN != want.DN || !reflect.DeepEqual(entries[i].Attributes, want.Attributes) {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, want, entries[i])
+		}
+	}
+
+	if _, err := unmarshalSnapshot([]byte(`{"name": "not a snapshot"}`)); err == nil {
+		t.Error("Expected a file without a filter to be rejected")
+	}
+}
+
+func TestQuerySnapshot_FileName(t *testing.T) {
+	taken := time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+	snapshot := newQuerySnapshot("", "", ldap.SearchParams{Filter: "(&(objectClass=person)(!(mail=*)))"}, nil, taken)
+	if snapshot.Name != "(&(objectClass=person)(!(mail=*)))" {
+		t.Errorf("Expected an unnamed snapshot to be named after its filter, got %q", snapshot.Name)
+	}
+	if got := snapshot.fileName(); got != "20260314-092653-objectclass-person-mail.json" {
+		t.Errorf("Expected a timestamped file name, got %q", got)
+	}
+}
+
+func TestQueryView_SaveAndReopenSnapshot(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	qv := browsingQueryView(snapshotEntries())
+	qv.search = ldap.SearchParams{BaseDN: "dc=example,dc=com", Scope: 2, Filter: "(uid=*)"}
+	qv.SetSnapshotDir(dir)
+
+	qv.Update(keyRune('S'))
+	if qv.snapshotInput == nil {
+		t.Fatal("Expected S to ask for the snapshot name")
+	}
+	typeFacetKeys(qv, "audit")
+	_, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(StatusMsg); !ok || !strings.Contains(msg.Message, `"audit"`) {
+		t.Fatalf("Expected the snapshot to be saved, got %#v", cmd())
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 || !strings.HasSuffix(files[0].Name(), "-audit.json") {
+		t.Fatalf("Expected one snapshot file, got %v", files)
+	}
+	if info, _ := files[0].Info(); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the snapshot to be readable only by the user, got %v", info.Mode().Perm())
+	}
+
+	// Reopen it in a fresh view without a server
+	qv = browsingQueryView(nil)
+	qv.SetSnapshotDir(dir)
+	qv.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if qv.snapshots == nil || len(qv.snapshots.snapshots) != 1 {
+		t.Fatal("Expected ctrl+o to list the saved snapshot")
+	}
+	qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if qv.snapshot == nil || len(qv.results) != 2 || qv.results[1].DN != "uid=bob,ou=people,dc=example,dc=com" {
+		t.Fatalf("Expected the snapshot's results, got %v", qv.results)
+	}
+	if qv.textarea.Value() != "(uid=*)" || qv.baseInput.Value() != "dc=example,dc=com" {
+		t.Errorf("Expected the snapshot's query in the form, got %q in %q", qv.textarea.Value(), qv.baseInput.Value())
+	}
+	if !strings.Contains(qv.View(), "SNAPSHOT audit") {
+		t.Error("Expected the results to be marked as a snapshot")
+	}
+
+	// Read-only: entries don't open and can't be bulk modified
+	if _, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Error("Expected a note that snapshot results are read-only")
+	} else if _, ok := cmd().(ShowRecordMsg); ok {
+		t.Error("Expected a snapshot entry not to open in the record view")
+	}
+	qv.Update(keyRune('M'))
+	if qv.bulk != nil {
+		t.Error("Expected bulk modify to be unavailable for a snapshot")
+	}
+
+	// Live results replace the snapshot
+	qv.Update(QueryPageMsg{Page: &ldap.SearchPage{Entries: snapshotEntries()[:1]}, IsFirstPage: true})
+	if qv.snapshot != nil {
+		t.Error("Expected live results to leave the snapshot")
+	}
+}
+
+func TestQueryView_OpenSnapshotsEmpty(t *testing.T) {
+	qv := browsingQueryView(snapshotEntries())
+	qv.SetSnapshotDir(filepath.Join(t.TempDir(), "missing"))
+	_, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if qv.snapshots != nil {
+		t.Error("Expected no list without snapshots")
+	}
+	if msg, ok := cmd().(StatusMsg); !ok || !strings.Contains(msg.Message, "No snapshots") {
+		t.Errorf("Expected a note that none are saved, got %#v", cmd())
+	}
+}
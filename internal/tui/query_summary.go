@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// resultSummary describes the results shown in one line for pasting into a
+// ticket or chat, e.g. "(objectClass=person) → 42 results from
+// ou=people,dc=example,dc=com, scope sub". Results that are one page of
+// several, or come from a snapshot, say so.
+func (qv *QueryView) resultSummary() string {
+	search := qv.search
+	if search.Filter == "" && qv.client != nil {
+		// Results that came without the search they answer
+		search = qv.client.CustomSearchParams(strings.TrimSpace(qv.baseInput.Value()), strings.TrimSpace(qv.textarea.Value()), nil)
+	}
+
+	count := fmt.Sprintf("%d results", len(qv.results))
+	if len(qv.results) == 1 {
+		count = "1 result"
+	}
+	summary := fmt.Sprintf("%s → %s from %s, scope %s", search.OneLineFilter(), count, search.BaseDN, ldap.ScopeName(search.Scope))
+
+	var notes []string
+	switch {
+	case qv.hasMore:
+		notes = append(notes, fmt.Sprintf("page %d, more on the server", qv.page+1))
+	case qv.page > 0:
+		notes = append(notes, fmt.Sprintf("page %d, the last", qv.page+1))
+	}
+	if qv.snapshot != nil {
+		notes = append(notes, fmt.Sprintf("snapshot %q of %s", qv.snapshot.Name, qv.snapshot.Taken.Local().Format(time.DateTime)))
+	}
+	if len(notes) > 0 {
+		summary += " (" + strings.Join(notes, "; ") + ")"
+	}
+	return summary
+}
+
+// copyResultSummary copies the one-line summary of the results to the clipboard
+func (qv *QueryView) copyResultSummary() tea.Cmd {
+	if len(qv.results) == 0 {
+		return SendError(fmt.Errorf("no results to summarize"))
+	}
+	summary := qv.resultSummary()
+	if err := clipboard.WriteAll(summary); err != nil {
+		return SendError(fmt.Errorf("failed to copy to clipboard: %w", err))
+	}
+	return SendSuccess("Copied " + summary)
+}
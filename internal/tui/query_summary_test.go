@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func TestQueryView_ResultSummary(t *testing.T) {
+	qv := NewQueryView(&ldap.Client{})
+	search := ldap.SearchParams{BaseDN: "ou=people,dc=example,dc=com", Scope: 2, Filter: "(&\n  (objectClass=person)\n  (mail=*)\n)"}
+	entries := []*ldap.Entry{{DN: "uid=a,ou=people,dc=example,dc=com"}, {DN: "uid=b,ou=people,dc=example,dc=com"}}
+
+	qv.Update(QueryPageMsg{Page: &ldap.SearchPage{Entries: entries}, Search: search, IsFirstPage: true})
+	want := "(&(objectClass=person)(mail=*)) → 2 results from ou=people,dc=example,dc=com, scope sub"
+	if got := qv.resultSummary(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	// Paged results say which page they are and whether more follow
+	qv.Update(QueryPageMsg{Page: &ldap.SearchPage{Entries: entries[:1], HasMore: true, Cookie: []byte("c2")}, Search: search, Index: 1, StartCookie: []byte("c1")})
+	want = "(&(objectClass=person)(mail=*)) → 1 result from ou=people,dc=example,dc=com, scope sub (page 2, more on the server)"
+	if got := qv.resultSummary(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	qv.Update(QueryPageMsg{Page: &ldap.SearchPage{Entries: entries}, Search: search, Index: 2, StartCookie: []byte("c2")})
+	if got := qv.resultSummary(); !strings.HasSuffix(got, "2 results from ou=people,dc=example,dc=com, scope sub (page 3, the last)") {
+		t.Errorf("Expected the last page noted, got %q", got)
+	}
+
+	// Snapshots are named with when they were taken
+	qv.snapshot = &querySnapshot{Name: "audit", Taken: time.Date(2026, 10, 14, 9, 30, 0, 0, time.Local)}
+	qv.hasMore, qv.page = false, 0
+	if got := qv.resultSummary(); !strings.HasSuffix(got, `scope sub (snapshot "audit" of 2026-10-14 09:30:00)`) {
+		t.Errorf("Expected the snapshot noted, got %q", got)
+	}
+}
+
+func TestQueryView_CopyResultSummary(t *testing.T) {
+	qv := NewQueryView(&ldap.Client{})
+	qv.table.Focus()
+	qv.inputMode = false
+	if _, cmd := qv.Update(keyRune('y')); cmd == nil {
+		t.Fatal("Expected a note that there are no results")
+	} else if _, ok := cmd().(ErrorMsg); !ok {
+		t.Errorf("Expected an error without results, got %#v", cmd())
+	}
+
+	if err := clipboard.WriteAll(""); err != nil {
+		t.Skipf("Clipboard not available in test environment: %v", err)
+	}
+	qv.Update(QueryPageMsg{
+		Page:        &ldap.SearchPage{Entries: []*ldap.Entry{{DN: "uid=a,dc=example,dc=com"}}},
+		Search:      ldap.SearchParams{BaseDN: "dc=example,dc=com", Scope: 1, Filter: "(uid=a)"},
+		IsFirstPage: true,
+	})
+	_, cmd := qv.Update(keyRune('y'))
+	if status, ok := cmd().(StatusMsg); !ok || !strings.HasPrefix(status.Message, "Copied (uid=a)") {
+		t.Fatalf("Expected a copy status, got %#v", cmd())
+	}
+	if copied, _ := clipboard.ReadAll(); copied != "(uid=a) → 1 result from dc=example,dc=com, scope one" {
+		t.Errorf("Unexpected summary copied: %q", copied)
+	}
+}
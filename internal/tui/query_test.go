@@ -1,12 +1,15 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
 	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
 )
 
 func TestQueryView_NumberInputInQueryMode(t *testing.T) {
@@ -412,3 +415,291 @@ func TestQueryView_FormatPreservesSimpleQueries(t *testing.T) {
 		}
 	}
 }
+
+func TestQueryView_PreviousPage(t *testing.T) {
+	qv := NewQueryView(&ldap.Client{})
+	qv.textarea.SetValue("(objectClass=person)")
+	page := func(dn string, cookie string) *ldap.SearchPage {
+		return &ldap.SearchPage{Entries: []*ldap.Entry{{DN: dn}}, HasMore: true, Cookie: []byte(cookie)}
+	}
+
+	qv.Update(QueryPageMsg{Page: page("uid=a,dc=example,dc=com", "c1"), IsFirstPage: true})
+	if _, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}}); cmd != nil {
+		t.Error("Expected no previous page on the first page")
+	}
+	qv.Update(QueryPageMsg{Page: page("uid=b,dc=example,dc=com", "c2"), Index: 1, StartCookie: []byte("c1")})
+	qv.Update(QueryPageMsg{Page: page("uid=c,dc=example,dc=com", "c3"), Index: 2, StartCookie: []byte("c2")})
+
+	// Each page replaces the results instead of appending
+	if qv.page != 2 || len(qv.results) != 1 || qv.results[0].DN != "uid=c,dc=example,dc=com" {
+		t.Fatalf("Expected only the third page, got page %d with %d results", qv.page, len(qv.results))
+	}
+	if view := qv.renderTable(); !strings.Contains(view, "Page 3") || !strings.Contains(view, "[P] for previous page") {
+		t.Errorf("Expected the page number in the footer, got:\n%s", view)
+	}
+
+	_, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	if cmd == nil || !qv.loadingNextPage {
+		t.Fatal("Expected P to fetch the previous page")
+	}
+
+	// Going back re-fetches page 2 and forgets the pages after it
+	qv.Update(QueryPageMsg{Page: page("uid=b,dc=example,dc=com", "c2"), Index: 1, StartCookie: []byte("c1")})
+	if qv.page != 1 || len(qv.pageCookies) != 2 || string(qv.pageCookies[1]) != "c1" {
+		t.Errorf("Expected page 2 with two remembered cookies, got page %d, %q", qv.page+1, qv.pageCookies)
+	}
+
+	// A rejected cookie restarts from page one
+	_, cmd = qv.Update(QueryPageMsg{Page: page("uid=a,dc=example,dc=com", "c1"), IsFirstPage: true, Restarted: true})
+	if qv.page != 0 || len(qv.pageCookies) != 1 {
+		t.Errorf("Expected paging to restart, got page %d", qv.page+1)
+	}
+	if msg, ok := cmd().(StatusMsg); !ok || !strings.Contains(msg.Message, "restarted from page 1") {
+		t.Errorf("Expected the restart to be reported, got %v", msg)
+	}
+}
+
+func TestQueryView_InvalidBaseDNReportedInline(t *testing.T) {
+	qv := NewQueryView(nil)
+	qv.textarea.SetValue("(objectClass=*)")
+	qv.baseInput.SetValue("ou=people,,dc=example")
+	qv.loading = true
+
+	if cmd := qv.executeQuery(); cmd != nil {
+		t.Fatal("Expected no search to be sent for a malformed base DN")
+	}
+	if qv.loading {
+		t.Error("Expected loading to stop")
+	}
+	if qv.error == nil || !strings.Contains(qv.error.Error(), "invalid DN") {
+		t.Errorf("Expected an invalid DN error, got %v", qv.error)
+	}
+}
+
+func TestQueryView_ResultsShowTheirSearch(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "uid=jdoe,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"jdoe"}}},
+	)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	qv := NewQueryView(client)
+	qv.SetSize(120, 40)
+	qv.textarea.SetValue("(uid=jdoe)")
+	qv.attrsInput.SetValue("uid, mail")
+	msg := qv.executeQuery()()
+	page, ok := msg.(QueryPageMsg)
+	if !ok {
+		t.Fatalf("Expected a page of results, got %#v", msg)
+	}
+	qv.Update(page)
+
+	want := "base: dc=example,dc=com | scope: sub | filter: (uid=jdoe) | attributes: uid, mail, objectClass"
+	if qv.search.String() != want {
+		t.Errorf("Expected the executed search to be kept, got %q", qv.search.String())
+	}
+
+	// Editing the inputs afterwards leaves the annotation of the shown results alone
+	qv.textarea.SetValue("(cn=*)")
+	qv.baseInput.SetValue("ou=groups,dc=example,dc=com")
+	if view := qv.View(); !strings.Contains(view, want) {
+		t.Errorf("Expected the results to be annotated with their search, got:\n%s", view)
+	}
+}
+
+// tabTestModel returns a model on the query view with Tab set to behavior
+// and a two-line filter typed into it
+func tabTestModel(behavior string) *Model {
+	model := NewModel(nil, config.Default())
+	model.queryView = NewQueryView(nil)
+	model.queryView.SetTabBehavior(behavior)
+	model.currentView = ViewModeQuery
+	model.queryView.textarea.SetValue("(&\n(cn=a)")
+	return model
+}
+
+func TestQueryView_TabIndents(t *testing.T) {
+	model := tabTestModel(config.QueryTabIndent)
+	model.queryView.results = []*ldap.Entry{{DN: "cn=a,dc=example,dc=com"}}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if model.currentView != ViewModeQuery || !model.queryView.IsInputMode() {
+		t.Fatal("Expected tab to stay in the filter")
+	}
+	if got := model.queryView.textarea.Value(); got != "(&\n(cn=a)  " {
+		t.Errorf("Expected tab to insert indentation, got %q", got)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlCloseBracket})
+	if model.queryView.IsInputMode() {
+		t.Error("Expected Ctrl+] to move to the results")
+	}
+}
+
+func TestQueryView_TabSmart(t *testing.T) {
+	model := tabTestModel(config.QueryTabSmart)
+
+	// Without results tab indents, even at the end of the filter
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := model.queryView.textarea.Value(); got != "(&\n(cn=a)  " || !model.queryView.IsInputMode() {
+		t.Errorf("Expected tab to indent without results, got %q", got)
+	}
+
+	// Inside the filter tab indents
+	model.queryView.results = []*ldap.Entry{{DN: "cn=a,dc=example,dc=com"}}
+	model.queryView.textarea.SetValue("(&\n(cn=a)")
+	model.queryView.textarea.CursorUp()
+	model.queryView.textarea.CursorStart()
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := model.queryView.textarea.Value(); got != "  (&\n(cn=a)" || !model.queryView.IsInputMode() {
+		t.Errorf("Expected tab to indent the first line, got %q", got)
+	}
+
+	// At the end of the filter tab moves to the results
+	model.queryView.textarea.SetValue("(&\n(cn=a)")
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if model.currentView != ViewModeQuery || model.queryView.IsInputMode() {
+		t.Error("Expected tab at the end of the filter to move to the results")
+	}
+}
+
+func TestQueryView_TabSwitchesByDefault(t *testing.T) {
+	model := tabTestModel("")
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if model.currentView == ViewModeQuery {
+		t.Error("Expected tab from the filter to switch views by default")
+	}
+	if got := model.queryView.textarea.Value(); got != "(&\n(cn=a)" {
+		t.Errorf("Expected the filter to be unchanged, got %q", got)
+	}
+}
+
+func TestQueryView_AbbreviateDNs(t *testing.T) {
+	qv := NewQueryView(nil)
+	qv.SetSize(120, 40)
+	qv.SetResults([]*ldap.Entry{
+		{DN: "cn=alice,ou=people,dc=example,dc=com", Attributes: map[string][]string{"cn": {"alice"}}},
+		{DN: "cn=bob,ou=people,dc=example,dc=com", Attributes: map[string][]string{"cn": {"bob"}}},
+	})
+	qv.inputMode = false
+
+	qv.Update(keyRune('a'))
+	if got := qv.table.Rows()[1][0]; got != "bob / people / example / com" {
+		t.Errorf("Expected the DN to be abbreviated, got %q", got)
+	}
+	view := qv.renderTable()
+	if !strings.Contains(view, "cn=alice,ou=people,dc=example,dc=com") {
+		t.Errorf("Expected the selected row to keep its full DN, got:\n%s", view)
+	}
+	if strings.Contains(view, "cn=bob") {
+		t.Errorf("Expected the other rows to be abbreviated, got:\n%s", view)
+	}
+
+	// Actions still act on the entry's full DN
+	qv.table.SetCursor(1)
+	_, cmd := qv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected enter to open the selected record")
+	}
+	msg, ok := cmd().(ShowRecordMsg)
+	if !ok || msg.Entry.DN != "cn=bob,ou=people,dc=example,dc=com" {
+		t.Errorf("Expected the record to open with its full DN, got %#v", msg)
+	}
+
+	qv.Update(keyRune('a'))
+	if got := qv.table.Rows()[1][0]; got != "cn=bob,ou=people,dc=example,dc=com" {
+		t.Errorf("Expected a to restore full DNs, got %q", got)
+	}
+}
+
+// resultEntries returns n distinct entries shaped like a user search result
+func resultEntries(n int) []*ldap.Entry {
+	entries := make([]*ldap.Entry, n)
+	for i := range entries {
+		entries[i] = &ldap.Entry{
+			DN: fmt.Sprintf("uid=user%d,ou=people,dc=example,dc=com", i),
+			Attributes: map[string][]string{
+				"objectClass": {"top", "person", "inetOrgPerson"},
+				"uid":         {fmt.Sprintf("user%d", i)},
+				"cn":          {fmt.Sprintf("User %d", i)},
+				"mail":        {fmt.Sprintf("user%d@example.com", i)},
+			},
+		}
+	}
+	return entries
+}
+
+func TestQueryView_RebuildReusesRows(t *testing.T) {
+	entries := resultEntries(200)
+	qv := NewQueryView(&ldap.Client{})
+	qv.SetResults(entries[:100])
+	summary := qv.table.Rows()[5][1]
+
+	// Rows already formatted are reused, so the summary stays the same despite
+	// map iteration order, and only the new entries are formatted
+	qv.SetResults(entries)
+	rows := qv.table.Rows()
+	if len(rows) != 200 || rows[5][1] != summary || len(qv.rowCache) != 200 {
+		t.Fatalf("Expected 200 rows with the first ones reused, got %d rows and %d cached", len(rows), len(qv.rowCache))
+	}
+	if rows[150][0] != entries[150].DN || qv.rowKinds[150] != classifyEntry(entries[150]) {
+		t.Errorf("Expected the appended entries formatted, got %v", rows[150])
+	}
+
+	// Abbreviating keeps the cache and rows for entries no longer shown are dropped
+	qv.SetAbbreviateDNs(true)
+	if qv.table.Rows()[0][0] != "user0 / people / example / com" {
+		t.Errorf("Expected abbreviated DNs, got %q", qv.table.Rows()[0][0])
+	}
+	qv.SetResults(entries[100:])
+	if len(qv.rowCache) != 100 || qv.rowCache[entries[0]] != nil {
+		t.Errorf("Expected only the shown entries cached, got %d", len(qv.rowCache))
+	}
+	if !strings.HasPrefix(strings.Join(qv.ResultLines, "\n"), "DN: uid=user100,") {
+		t.Errorf("Expected the legacy lines rebuilt, got %v", qv.ResultLines[:1])
+	}
+
+	// Appending a page to a large result set costs far less than formatting
+	// every row again
+	large := resultEntries(2000)
+	cold := testing.AllocsPerRun(5, func() {
+		qv.rowCache = nil
+		qv.SetResults(large[:1900])
+	})
+	warm := testing.AllocsPerRun(5, func() {
+		qv.SetResults(large[:1900])
+		qv.SetResults(large)
+	})
+	if warm >= cold/2 {
+		t.Errorf("Expected appending a page to allocate much less than a full rebuild, got %.0f vs %.0f", warm, cold)
+	}
+}
+
+// BenchmarkQueryView_AppendPage measures rebuilding the results table after
+// a page of 100 is added to 5000 results, with rows reused ("cached") and as
+// a rebuild that formats every row ("full")
+func BenchmarkQueryView_AppendPage(b *testing.B) {
+	entries := resultEntries(5100)
+	for _, bench := range []struct {
+		name  string
+		reuse bool
+	}{{"full", false}, {"cached", true}} {
+		b.Run(bench.name, func(b *testing.B) {
+			qv := NewQueryView(&ldap.Client{})
+			qv.SetResults(entries[:5000])
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				if !bench.reuse {
+					qv.rowCache = nil
+				}
+				qv.SetResults(entries)
+				qv.results = entries[:5000]
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// noteSearchError works out whether a failed search looks unindexed, setting
+// the hint shown under the error, and remembers the filter's substring
+// attributes so later searches on them are warned about before they run
+func (qv *QueryView) noteSearchError(err error) {
+	filter := strings.TrimSpace(qv.textarea.Value())
+	qv.hint = ldap.UnindexedSearchHint(filter, err)
+	if qv.hint == "" {
+		return
+	}
+	if qv.unindexed == nil {
+		qv.unindexed = make(map[string]bool)
+	}
+	for _, attribute := range ldap.SubstringAttributes(filter) {
+		qv.unindexed[strings.ToLower(attribute)] = true
+	}
+}
+
+// unindexedWarning warns before running filter when it matches by substring
+// on attributes an earlier search this session suggested aren't indexed
+func (qv *QueryView) unindexedWarning(filter string) tea.Cmd {
+	var attributes []string
+	for _, attribute := range ldap.SubstringAttributes(filter) {
+		if qv.unindexed[strings.ToLower(attribute)] {
+			attributes = append(attributes, attribute)
+		}
+	}
+	if len(attributes) == 0 {
+		return nil
+	}
+	message := fmt.Sprintf("A substring search on %s hit a server limit earlier and may be unindexed; this search may be slow or refused", strings.Join(attributes, ", "))
+	return func() tea.Msg {
+		return StatusMsg{Message: message, Level: ToastWarning}
+	}
+}
@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+func TestQueryView_UnindexedSearchHint(t *testing.T) {
+	qv := NewQueryView(nil)
+	qv.SetSize(120, 40)
+	qv.SetFilter("(mail=*@example.com)")
+
+	qv.Update(ErrorMsg{Err: goldap.NewError(goldap.LDAPResultAdminLimitExceeded, errors.New("administrative limit exceeded"))})
+	view := ansi.Strip(qv.View())
+	if !strings.Contains(view, "Hint: mail may not be indexed") {
+		t.Errorf("Expected a hint that mail may be unindexed, got:\n%s", view)
+	}
+
+	cmd := qv.unindexedWarning("(|(cn=x)(MAIL=*jdoe*))")
+	if cmd == nil {
+		t.Fatal("Expected a warning before searching mail by substring again")
+	}
+	if msg, ok := cmd().(StatusMsg); !ok || msg.Level != ToastWarning || !strings.Contains(msg.Message, "MAIL") {
+		t.Errorf("Expected a warning naming the attribute, got %#v", msg)
+	}
+	if qv.unindexedWarning("(mail=jdoe@example.com)") != nil {
+		t.Error("Expected no warning for an equality match")
+	}
+}
+
+func TestQueryView_NoHintForOtherErrors(t *testing.T) {
+	qv := NewQueryView(nil)
+	qv.SetSize(120, 40)
+	qv.SetFilter("(mail=*@example.com)")
+
+	qv.Update(ErrorMsg{Err: goldap.NewError(goldap.LDAPResultInsufficientAccessRights, errors.New("no read access"))})
+	if strings.Contains(ansi.Strip(qv.View()), "Hint:") {
+		t.Error("Expected no index hint for an access error")
+	}
+	if qv.unindexedWarning("(mail=*@example.com)") != nil {
+		t.Error("Expected nothing remembered from an unrelated error")
+	}
+}
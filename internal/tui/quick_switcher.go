@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/config"
+)
+
+// QuickSwitcher is an overlay listing saved connections by most recent use so
+// the user can jump to another directory from any view
+type QuickSwitcher struct {
+	config  *config.Config
+	indices []int // Saved connection indices in display order
+	cursor  int
+}
+
+// NewQuickSwitcher creates a switcher over the saved connections in cfg
+func NewQuickSwitcher(cfg *config.Config) *QuickSwitcher {
+	return &QuickSwitcher{
+		config:  cfg,
+		indices: cfg.RecentConnections(),
+	}
+}
+
+// Update handles a key press. It returns true when the switcher should close,
+// along with the index of the chosen connection or -1 if it was cancelled.
+func (qs *QuickSwitcher) Update(msg tea.KeyMsg) (bool, int) {
+	switch msg.String() {
+	case "up", "k":
+		if qs.cursor > 0 {
+			qs.cursor--
+		}
+	case "down", "j":
+		if qs.cursor < len(qs.indices)-1 {
+			qs.cursor++
+		}
+	case "enter":
+		if len(qs.indices) == 0 {
+			return true, -1
+		}
+		return true, qs.indices[qs.cursor]
+	case "esc", "ctrl+k":
+		return true, -1
+	}
+	return false, -1
+}
+
+// View renders the switcher as a bordered box
+func (qs *QuickSwitcher) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	detailStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+
+	sections := []string{titleStyle.Render("Switch connection"), ""}
+
+	active := qs.config.LDAP.SelectedConnection
+	for i, index := range qs.indices {
+		conn := qs.config.LDAP.SavedConnections[index]
+
+		lastUsed := "never used"
+		if !conn.LastUsed.IsZero() {
+			lastUsed = "last used " + conn.LastUsed.Local().Format("2006-01-02 15:04")
+		}
+
+		line := fmt.Sprintf("%s  %s", conn.Name, detailStyle.Render(fmt.Sprintf("%s:%d • %s", conn.Host, conn.Port, lastUsed)))
+		if index == active {
+			line += " " + detailStyle.Render("(current)")
+		}
+
+		if i == qs.cursor {
+			sections = append(sections, editorFocusStyle.Render("▶ ")+line)
+		} else {
+			sections = append(sections, "  "+line)
+		}
+	}
+
+	sections = append(sections, "", helpStyle.Render("[↑↓] select • [Enter] connect • [Esc] cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("6")).
+		Padding(0, 1).
+		Render(strings.Join(sections, "\n"))
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func newSwitcherModel(t *testing.T) (*Model, string) {
+	cfg := config.Default()
+	cfg.LDAP.SavedConnections = []config.SavedConnection{
+		{Name: "Production", Host: "", Port: 389, BaseDN: "dc=prod,dc=com"},
+		{Name: "Staging", Host: "", Port: 389, BaseDN: "dc=stage,dc=com", LastUsed: time.Now()},
+	}
+	cfg.LDAP.SelectedConnection = 1
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	model := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, path)
+	model.SetSize(100, 30)
+	return model, path
+}
+
+func TestQuickSwitcher_OrdersByRecentUse(t *testing.T) {
+	model, _ := newSwitcherModel(t)
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if model.switcher == nil {
+		t.Fatal("Expected ctrl+k to open the switcher")
+	}
+
+	if model.switcher.indices[0] != 1 {
+		t.Errorf("Expected most recently used connection first, got %v", model.switcher.indices)
+	}
+
+	zone.NewGlobal()
+	view := model.View()
+	if !strings.Contains(view, "Switch connection") || !strings.Contains(view, "never used") {
+		t.Error("Expected the switcher overlay to be rendered")
+	}
+	if strings.Index(view, "Staging") > strings.Index(view, "Production") {
+		t.Error("Expected Staging to be listed before Production")
+	}
+}
+
+func TestQuickSwitcher_CapturesKeys(t *testing.T) {
+	model, _ := newSwitcherModel(t)
+	model.currentView = ViewModeTree
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+
+	// Global keys go to the switcher while it is open
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if model.quitting || model.currentView != ViewModeTree {
+		t.Error("Expected global keys to be ignored while the switcher is open")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.switcher != nil {
+		t.Error("Expected Esc to close the switcher")
+	}
+}
+
+func TestQuickSwitcher_SelectConnects(t *testing.T) {
+	model, _ := newSwitcherModel(t)
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if model.switcher != nil {
+		t.Error("Expected the switcher to close after choosing")
+	}
+	if model.startView.config.LDAP.SelectedConnection != 0 {
+		t.Errorf("Expected Production to become active, got %d", model.startView.config.LDAP.SelectedConnection)
+	}
+	if cmd == nil {
+		t.Fatal("Expected a connect command")
+	}
+	// The host is empty, so validation fails without touching the network
+	if msg, ok := cmd().(StatusMsg); !ok || !strings.Contains(msg.Message, "host is required") {
+		t.Errorf("Expected connection validation status, got %v", msg)
+	}
+}
+
+func TestQuickSwitcher_NoSavedConnections(t *testing.T) {
+	model := NewModel(nil, config.Default())
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if model.switcher != nil {
+		t.Error("Expected no switcher without saved connections")
+	}
+	if !strings.Contains(model.toasts.latest(), "No saved connections") {
+		t.Errorf("Unexpected status: %q", model.toasts.latest())
+	}
+}
+
+func TestModel_ConnectMarksConnectionUsed(t *testing.T) {
+	model, path := newSwitcherModel(t)
+	model.startView.config.LDAP.SelectedConnection = 0
+
+	before := time.Now()
+	model.Update(ConnectMsg{Client: &ldap.Client{}, Config: model.startView.config})
+
+	if model.startView.config.LDAP.SavedConnections[0].LastUsed.Before(before) {
+		t.Error("Expected the connected profile to be marked as used")
+	}
+	loaded, _, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Expected config to be saved: %v", err)
+	}
+	if loaded.LDAP.SavedConnections[0].LastUsed.IsZero() {
+		t.Error("Expected LastUsed to be persisted")
+	}
+}
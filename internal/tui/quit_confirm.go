@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// quit quits unless a view has unsaved changes, in which case it asks first
+func (m *Model) quit() tea.Cmd {
+	if m.hasUnsavedChanges() {
+		m.confirmQuit = true
+		return nil
+	}
+	return m.exit()
+}
+
+// exit closes every open connection and quits
+func (m *Model) exit() tea.Cmd {
+	m.quitting = true
+	m.Close()
+	return tea.Quit
+}
+
+// hasUnsavedChanges reports whether any view, on any open connection, has an
+// edit or write in progress that quitting would lose
+func (m *Model) hasUnsavedChanges() bool {
+	if m.startView != nil && m.startView.HasUnsavedChanges() {
+		return true
+	}
+	if viewsHaveUnsavedChanges(m.tree, m.recordView, m.queryView) {
+		return true
+	}
+	for i, tab := range m.tabs {
+		// The active tab's views are the Model's own, checked above
+		if i != m.activeTab && viewsHaveUnsavedChanges(tab.tree, tab.recordView, tab.queryView) {
+			return true
+		}
+	}
+	return false
+}
+
+// viewsHaveUnsavedChanges reports whether one of a connection's views has
+// unsaved changes. Views that haven't been created yet have none.
+func viewsHaveUnsavedChanges(tree *TreeView, record *RecordView, query *QueryView) bool {
+	return (tree != nil && tree.HasUnsavedChanges()) ||
+		(record != nil && record.HasUnsavedChanges()) ||
+		(query != nil && query.HasUnsavedChanges())
+}
+
+// handleQuitConfirmKey quits on confirmation and otherwise goes back to the
+// work in progress. Ctrl+C a second time quits too.
+func (m *Model) handleQuitConfirmKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "y", "Y", "ctrl+c":
+		m.confirmQuit = false
+		return m.exit()
+	case "n", "N", "esc":
+		m.confirmQuit = false
+	}
+	return nil
+}
+
+// renderQuitConfirm renders the confirm-quit dialog
+func (m *Model) renderQuitConfirm() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("9")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+
+	sections := []string{
+		titleStyle.Render("Quit with unsaved changes?"),
+		"",
+		"An edit or write is still in progress and will be lost.",
+		"",
+		"[Y] quit anyway",
+		helpStyle.Render("[N/Esc] keep working"),
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("9")).
+		Padding(0, 1).
+		Render(strings.Join(sections, "\n"))
+}
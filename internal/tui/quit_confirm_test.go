@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func TestModel_QuitWithoutUnsavedChanges(t *testing.T) {
+	model, _ := newBaseDNModel(t, "")
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if model.confirmQuit || !model.quitting || cmd == nil {
+		t.Fatal("Expected q to quit straight away with nothing unsaved")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected a quit command")
+	}
+}
+
+func TestModel_QuitGuardsAttributeEdit(t *testing.T) {
+	zone.NewGlobal()
+	model, _ := newBaseDNModel(t, "")
+	model.recordView.SetEntry(&ldap.Entry{
+		DN:         "cn=test,dc=example,dc=com",
+		Attributes: map[string][]string{"cn": {"test"}},
+	})
+	model.currentView = ViewModeRecord
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if !model.recordView.HasUnsavedChanges() {
+		t.Fatal("Expected an open editor to count as unsaved")
+	}
+
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlC}); cmd != nil || model.quitting {
+		t.Fatal("Expected Ctrl+C to ask before quitting")
+	}
+	if !model.confirmQuit || !strings.Contains(model.View(), "Quit with unsaved changes?") {
+		t.Fatal("Expected the confirm-quit dialog")
+	}
+
+	// Keys go to the dialog, not the editor
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.confirmQuit || model.quitting || !model.recordView.IsEditing() {
+		t.Fatal("Expected Esc to close the dialog and keep the edit open")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if !model.quitting || cmd == nil {
+		t.Fatal("Expected Y to quit anyway")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected a quit command")
+	}
+}
+
+func TestModel_QuitGuardsBackgroundTab(t *testing.T) {
+	model, _, _ := newTwoTabModel(t)
+	model.tabs[0].recordView.saving = true
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if !model.confirmQuit || model.quitting {
+		t.Fatal("Expected a write on another connection to be guarded")
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if !model.quitting {
+		t.Error("Expected a second Ctrl+C to quit")
+	}
+}
+
+func TestViews_HasUnsavedChanges(t *testing.T) {
+	tree := NewTreeView(nil)
+	if tree.HasUnsavedChanges() {
+		t.Error("Expected a fresh tree to have nothing unsaved")
+	}
+	tree.templateForm = &TemplateForm{stage: templateStageSelect}
+	if tree.HasUnsavedChanges() {
+		t.Error("Expected picking a template not to count as unsaved")
+	}
+	tree.templateForm.stage = templateStageFill
+	if !tree.HasUnsavedChanges() {
+		t.Error("Expected entering template values to count as unsaved")
+	}
+
+	query := NewQueryView(nil)
+	query.bulk = &bulkModify{stage: bulkStagePreview}
+	if !query.HasUnsavedChanges() {
+		t.Error("Expected a pending bulk modify to count as unsaved")
+	}
+	query.bulk.stage = bulkStageReport
+	if query.HasUnsavedChanges() {
+		t.Error("Expected a finished bulk modify not to count as unsaved")
+	}
+}
+
+func TestModel_QuitClosesConnections(t *testing.T) {
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	connect := func() *ldap.Client {
+		client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+
+	cfg := config.Default()
+	model := NewModelWithUpdateCheckAndConfigPath(nil, cfg, false, "")
+	model.SetSize(120, 30)
+	first, second := connect(), connect()
+	model.Update(ConnectMsg{Client: first, Config: cfg})
+	model.Update(ConnectMsg{Client: second, Config: cfg, NewTab: true})
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if !model.quitting {
+		t.Fatal("Expected q to quit")
+	}
+	if !first.Closed() || !second.Closed() {
+		t.Errorf("Expected quitting to close every connection, got first closed %v, second closed %v", first.Closed(), second.Closed())
+	}
+
+	// Closing again on the way out is harmless
+	model.Close()
+}
@@ -0,0 +1,15 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// refuseWrite tells the user that changes are turned off when client is in
+// read-only mode. It returns nil when client may write.
+func refuseWrite(client *ldap.Client) tea.Cmd {
+	if !client.ReadOnly() {
+		return nil
+	}
+	return SendStatus("Read-only mode: changes are turned off")
+}
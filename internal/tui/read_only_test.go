@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+// readOnlyClient connects to a test server in read-only mode
+func readOnlyClient(t *testing.T) *ldap.Client {
+	t.Helper()
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), ReadOnly: true})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+// expectRefused checks that cmd reports read-only mode
+func expectRefused(t *testing.T, what string, cmd tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatalf("Expected %s to be refused", what)
+	}
+	if msg, ok := cmd().(StatusMsg); !ok || msg.Message != "Read-only mode: changes are turned off" {
+		t.Errorf("Expected %s to be refused in read-only mode, got %v", what, msg)
+	}
+}
+
+func TestReadOnly_RefusesChanges(t *testing.T) {
+	client := readOnlyClient(t)
+
+	qv := bulkTestView()
+	qv.client = client
+	_, cmd := qv.Update(keyRune('M'))
+	expectRefused(t, "bulk modify", cmd)
+	_, cmd = qv.Update(keyRune('R'))
+	expectRefused(t, "find and replace", cmd)
+	if qv.bulk != nil {
+		t.Error("Expected no bulk form in read-only mode")
+	}
+
+	rv := NewRecordView()
+	rv.SetSize(80, 20)
+	rv.SetClient(client)
+	rv.SetEntry(&ldap.Entry{DN: "uid=jdoe,dc=example,dc=com", Attributes: map[string][]string{"mail": {"jdoe@example.com"}}})
+	for _, key := range []rune{'e', '+', '-'} {
+		_, cmd := rv.Update(keyRune(key))
+		expectRefused(t, "record key "+string(key), cmd)
+	}
+	if rv.editor != nil || rv.pendingRemoval != nil {
+		t.Error("Expected no edit in read-only mode")
+	}
+
+	tv := newDeleteTreeView()
+	tv.client = client
+	tv.SetTemplates([]config.EntryTemplate{{Name: "User", RDN: "uid=${uid}"}})
+	tv.cursor = 2
+	_, cmd = tv.Update(keyRune('D'))
+	expectRefused(t, "delete", cmd)
+	_, cmd = tv.Update(keyRune('t'))
+	expectRefused(t, "a new entry", cmd)
+	if tv.deleteConfirm != nil || tv.IsFormActive() {
+		t.Error("Expected no delete or new entry in read-only mode")
+	}
+}
+
+func TestReadOnly_StatusBar(t *testing.T) {
+	zone.NewGlobal()
+	model := NewModel(nil, config.Default())
+	model.client = readOnlyClient(t)
+	model.connection = model.client.Config()
+	model.width = 160
+	if bar := model.renderStatusBar(); !strings.Contains(bar, "Read-only") {
+		t.Errorf("Expected the status bar to show read-only mode, got %q", bar)
+	}
+}
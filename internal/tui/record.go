@@ -5,11 +5,14 @@ import (
 	"image/color"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/config"
 	"github.com/ericschmar/moribito/internal/ldap"
 	zone "github.com/lrstanley/bubblezone"
 	"github.com/lucasb-eyer/go-colorful"
@@ -27,12 +30,81 @@ type RecordView struct {
 	viewport  int // Viewport offset for scrolling through attributes
 	// For clickable zones
 	renderedRows []RowData // Store row data for click handling
+
+	// Attribute editing state
+	client      *ldap.Client
+	schema      *ldap.Schema
+	editor      AttributeEditor // Active editor, nil when not editing
+	editingAttr string
+	editOp      ldap.ModifyOperation // ModifyReplace for full edits, ModifyAdd when adding a value
+	editError   error
+	saving      bool
+
+	// Attributes changed by the last modify are highlighted for a while
+	snapshot   *ldap.Entry     // Entry as it was when the modify was sent
+	changed    map[string]bool // Lower-cased names of the changed attributes
+	changedSeq int             // Identifies the highlight a ChangeHighlightExpiredMsg ends
+
+	// Per-value management of multi-valued attributes
+	expanded       map[string]bool // Attributes expanded or collapsed from the display style's default
+	pendingRemoval *RowData        // Value awaiting confirmation before it is removed
+	multiValue     string          // Display style of multi-valued attributes, see config.MultiValue*
+	separator      string          // Joins values in the inline display style
+	hideEmpty      bool            // Leave out zero-length values and attributes without values
+	columnRatio    float64         // Share of the width for the attribute column, 0 for a third
+	columns        tableColumns    // Column focused for resizing and its session widths
+	visible        []string        // Attributes shown by default; all are shown when empty
+	multiline      []string        // Attributes whose values always wrap instead of being truncated
+	showAll        bool            // Reveal the attributes outside the visible set
+	normalizeNames bool            // Show attribute names as the schema spells them
+	showOIDs       bool            // Annotate attribute names with their schema OID and syntax
+	loadingMore    string          // Attribute whose next values are being read
+	codeLanguage   string          // Language the entry is copied as code in, see export.Language*
+
+	info        *entryInfoPanel       // Creation/modification metadata panel, nil when closed
+	copyMenu    *copyMenu             // Copy-as options for the row under the cursor, nil when closed
+	certificate *certificatePanel     // Certificates of the attribute under the cursor, nil when closed
+	rights      *effectiveRightsPanel // Effective rights probe, nil when closed
+	retry       *attributeRetry       // Prompt for re-reading an empty entry, nil when closed
+	raw         *rawEntryView         // Raw mode, nil when showing the curated view
+}
+
+// EntryModifiedMsg is sent when an attribute edit has been applied and the entry re-read
+type EntryModifiedMsg struct {
+	Entry     *ldap.Entry
+	Attribute string
 }
 
-// RowData represents a single attribute row with its data
+// ChangeHighlightExpiredMsg ends the highlight of the attributes changed by a modify
+type ChangeHighlightExpiredMsg struct {
+	Seq int
+}
+
+// changeHighlightDuration is how long changed attributes stay highlighted
+const changeHighlightDuration = 3 * time.Second
+
+// changedRowStyle marks the rows of attributes changed by the last modify
+var changedRowStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("0")).
+	Background(lipgloss.Color("11"))
+
+// EntryModifyErrorMsg is sent when applying an attribute edit fails
+type EntryModifyErrorMsg struct {
+	Attribute string
+	Err       error
+}
+
+// RowData represents a single attribute row with its data.
+// Expanded attributes are followed by one value sub-row per value.
 type RowData struct {
 	AttributeName string
 	Values        []string
+	ValueIndex    int // Index into Values for value sub-rows, -1 for attribute rows
+}
+
+// IsValueRow returns true if the row shows a single value of an expanded attribute
+func (r RowData) IsValueRow() bool {
+	return r.ValueIndex >= 0
 }
 
 var (
@@ -79,9 +151,270 @@ func NewRecordView() *RecordView {
 	t.SetStyles(s)
 
 	return &RecordView{
-		table:    t,
-		viewport: 0,
+		table:      t,
+		viewport:   0,
+		expanded:   make(map[string]bool),
+		multiValue: config.MultiValueInline,
+		separator:  config.DefaultMultiValueSeparator,
+		columns:    newTableColumns(),
+	}
+}
+
+// SetMultiValueDisplay sets how attributes with several values are shown.
+// Unknown styles fall back to inline and an empty separator to the default.
+func (rv *RecordView) SetMultiValueDisplay(style, separator string) {
+	switch style {
+	case config.MultiValueLines, config.MultiValueCount:
+		rv.multiValue = style
+	default:
+		rv.multiValue = config.MultiValueInline
+	}
+	if separator == "" {
+		separator = config.DefaultMultiValueSeparator
+	}
+	rv.separator = separator
+	rv.expanded = make(map[string]bool)
+	rv.buildTable()
+}
+
+// SetColumnRatio sets the share of the width given to the attribute column,
+// zero for the default third
+func (rv *RecordView) SetColumnRatio(ratio float64) {
+	rv.columnRatio = ratio
+	if rv.width > 0 {
+		rv.SetSize(rv.width, rv.height)
+	}
+}
+
+// recordColumnTitles and recordColumnMins are the record table's column
+// headers and minimum widths
+var (
+	recordColumnTitles = []string{"Attribute", "Value(s)"}
+	recordColumnMins   = []int{15, 20}
+)
+
+// columnWidths returns the attribute and value column widths for contentWidth
+func (rv *RecordView) columnWidths(contentWidth int) (int, int) {
+	widths := rv.columns.layout(rv.ratioWidths(contentWidth), recordColumnMins)
+	return widths[0], widths[1]
+}
+
+// ratioWidths returns the column widths the saved ratio gives contentWidth
+func (rv *RecordView) ratioWidths(contentWidth int) []int {
+	nameWidth, valueWidth := splitColumnWidths(contentWidth, rv.columnRatio, recordColumnMins[0], recordColumnMins[1])
+	return []int{nameWidth, valueWidth}
+}
+
+// moveColumnDivider widens the attribute column by steps (narrows it when
+// negative) and reports the new ratio so it can be saved. Widths chosen for
+// a focused column give way to the saved ratio again.
+func (rv *RecordView) moveColumnDivider(steps int) tea.Cmd {
+	rv.columns.widths = nil
+	rv.SetColumnRatio(adjustColumnRatio(rv.columnRatio, steps))
+	return columnRatioChanged(columnTableRecord, rv.columnRatio)
+}
+
+// cycleColumnFocus focuses the next column for resizing with < and >
+func (rv *RecordView) cycleColumnFocus() string {
+	rv.columns.cycleFocus(len(recordColumnTitles))
+	if !rv.columns.focused() {
+		return "Column focus cleared; < and > move the saved divider"
+	}
+	return fmt.Sprintf("%s column focused; < and > resize it for this session", recordColumnTitles[rv.columns.focus])
+}
+
+// resizeFocusedColumn grows the focused column by steps (shrinks it when
+// negative) for this session, or moves the saved divider when no column has
+// the focus
+func (rv *RecordView) resizeFocusedColumn(steps int) tea.Cmd {
+	if !rv.columns.focused() {
+		return rv.moveColumnDivider(steps)
+	}
+	if rv.container == nil {
+		return nil
+	}
+	contentWidth, _ := rv.container.GetContentDimensions()
+	rv.columns.resize(rv.ratioWidths(contentWidth), recordColumnMins, steps)
+	rv.SetSize(rv.width, rv.height)
+	widths := rv.table.Columns()
+	return SendStatus(fmt.Sprintf("%s column: %d cells wide", recordColumnTitles[rv.columns.focus], widths[rv.columns.focus].Width))
+}
+
+// SetHideEmpty sets whether empty attribute values are hidden. While they
+// are, entries are read without their operational attributes, so the server
+// leaves those out too.
+func (rv *RecordView) SetHideEmpty(hide bool) {
+	rv.hideEmpty = hide
+	rv.client.SetOperationalAttributes(!hide)
+	rv.buildTable()
+}
+
+// toggleHideEmpty shows or hides empty attribute values for this session and
+// reads the entry again, with or without its operational attributes
+func (rv *RecordView) toggleHideEmpty() tea.Cmd {
+	rv.SetHideEmpty(!rv.hideEmpty)
+	rv.table.SetCursor(0)
+	rv.viewport = 0
+	status := "Showing empty and operational attributes"
+	if rv.hideEmpty {
+		status = "Hiding empty and operational attributes"
+	}
+	if rv.entry == nil || rv.client == nil {
+		return SendStatus(status)
+	}
+
+	client := rv.client
+	dn := rv.entry.DN
+	return tea.Batch(SendStatus(status), func() tea.Msg {
+		entry, err := client.GetEntry(dn)
+		if err != nil {
+			return readEntryError(dn, err)
+		}
+		return ShowRecordMsg{Entry: entry}
+	})
+}
+
+// SetVisibleAttributes limits the table to the named attributes until the
+// rest are revealed. An empty list shows every attribute.
+func (rv *RecordView) SetVisibleAttributes(names []string) {
+	rv.visible = names
+	rv.buildTable()
+}
+
+// SetMultilineAttributes sets the attributes whose values are always wrapped
+// across lines to fit the value column, such as description or postalAddress
+func (rv *RecordView) SetMultilineAttributes(names []string) {
+	rv.multiline = names
+	rv.adjustViewport()
+}
+
+// isMultiline reports whether the values of attribute are always wrapped
+func (rv *RecordView) isMultiline(attribute string) bool {
+	for _, name := range rv.multiline {
+		if strings.EqualFold(name, attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// rowLineCount returns how many lines row takes within width, without
+// formatting the value of rows that always take one
+func (rv *RecordView) rowLineCount(row RowData, width int) int {
+	if !rv.isMultiline(row.AttributeName) {
+		return 1
+	}
+	return len(rv.valueLines(row, width))
+}
+
+// valueLines returns the lines the value column shows for row within width:
+// a single truncated line, or the whole value wrapped for attributes that are
+// always multiline
+func (rv *RecordView) valueLines(row RowData, width int) []string {
+	text := rv.rowValueText(row)
+	width = max(width-3, 1)
+	if !rv.isMultiline(row.AttributeName) {
+		return []string{truncateToWidth(text, width)}
+	}
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, strings.Split(ansi.Wrap(line, width, ""), "\n")...)
+	}
+	return lines
+}
+
+// toggleShowAll reveals or hides the attributes outside the visible set for
+// this session and returns a description of the new state
+func (rv *RecordView) toggleShowAll() string {
+	if len(rv.visible) == 0 {
+		return "All attributes are shown; set display.visible_attributes to limit them"
+	}
+	rv.showAll = !rv.showAll
+	rv.buildTable()
+	rv.table.SetCursor(0)
+	rv.viewport = 0
+	if rv.showAll {
+		return "Showing all attributes"
+	}
+	return "Showing the configured attributes"
+}
+
+// isVisibleByDefault reports whether attribute is in the visible set
+func (rv *RecordView) isVisibleByDefault(attribute string) bool {
+	for _, name := range rv.visible {
+		if strings.EqualFold(name, attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// filtersAttributes reports whether attributes outside the visible set are hidden
+func (rv *RecordView) filtersAttributes() bool {
+	return len(rv.visible) > 0 && !rv.showAll
+}
+
+// presentAttributes returns the entry's attributes, without empty ones when they are hidden
+func (rv *RecordView) presentAttributes() map[string][]string {
+	if rv.hideEmpty {
+		return rv.entry.NonEmpty().Attributes
+	}
+	return rv.entry.Attributes
+}
+
+// displayedAttributes returns the attributes shown in the table
+func (rv *RecordView) displayedAttributes() map[string][]string {
+	attributes := rv.presentAttributes()
+	if !rv.filtersAttributes() {
+		return attributes
+	}
+
+	shown := make(map[string][]string, len(rv.visible))
+	for name, values := range attributes {
+		if rv.isVisibleByDefault(name) {
+			shown[name] = values
+		}
+	}
+	return shown
+}
+
+// hiddenAttributeCount returns how many attributes the visible set leaves out
+func (rv *RecordView) hiddenAttributeCount() int {
+	if rv.entry == nil || rv.raw != nil || !rv.filtersAttributes() {
+		return 0
 	}
+	return len(rv.presentAttributes()) - len(rv.displayedAttributes())
+}
+
+// cycleMultiValueDisplay switches to the next multi-value display style for
+// this session and returns a description of it
+func (rv *RecordView) cycleMultiValueDisplay() string {
+	next := map[string]string{
+		config.MultiValueInline: config.MultiValueLines,
+		config.MultiValueLines:  config.MultiValueCount,
+		config.MultiValueCount:  config.MultiValueInline,
+	}[rv.multiValue]
+	rv.SetMultiValueDisplay(next, rv.separator)
+	rv.table.SetCursor(0)
+	rv.viewport = 0
+
+	switch next {
+	case config.MultiValueLines:
+		return "Multi-valued attributes: one value per line"
+	case config.MultiValueCount:
+		return "Multi-valued attributes: value count only"
+	default:
+		return "Multi-valued attributes: inline"
+	}
+}
+
+// isExpanded reports whether the values of attribute are shown as sub-rows,
+// which the lines style does unless the attribute was collapsed
+func (rv *RecordView) isExpanded(attribute string) bool {
+	if expanded, ok := rv.expanded[attribute]; ok {
+		return expanded
+	}
+	return rv.multiValue == config.MultiValueLines
 }
 
 // Init initializes the record view
@@ -105,20 +438,12 @@ func (rv *RecordView) SetSize(width, height int) {
 	}
 
 	// Calculate column widths based on available content space
-	nameWidth := contentWidth / 3
-	if nameWidth < 15 {
-		nameWidth = 15
-	}
-	valueWidth := contentWidth - nameWidth - 4 // Account for borders and padding
-	if valueWidth < 20 {
-		valueWidth = 20
-		nameWidth = contentWidth - valueWidth - 4
-	}
+	nameWidth, valueWidth := rv.columnWidths(contentWidth)
 
 	// Update table dimensions
 	columns := []table.Column{
-		{Title: "Attribute", Width: nameWidth},
-		{Title: "Value(s)", Width: valueWidth},
+		{Title: rv.columns.title(0, recordColumnTitles[0]), Width: nameWidth},
+		{Title: rv.columns.title(1, recordColumnTitles[1]), Width: valueWidth},
 	}
 	rv.table.SetColumns(columns)
 	rv.table.SetHeight(tableHeight)
@@ -127,8 +452,98 @@ func (rv *RecordView) SetSize(width, height int) {
 
 // SetEntry sets the entry to display
 func (rv *RecordView) SetEntry(entry *ldap.Entry) {
+	// Keep attributes expanded when the same entry is re-read after a modification
+	if rv.entry == nil || entry == nil || rv.entry.DN != entry.DN {
+		rv.expanded = make(map[string]bool)
+		rv.info = nil
+		rv.copyMenu = nil
+		rv.certificate = nil
+		rv.rights = nil
+		rv.retry = nil
+		rv.raw = nil
+		rv.changed = nil
+		rv.loadingMore = ""
+	}
 	rv.entry = entry
+	rv.cancelEdit()
+	rv.pendingRemoval = nil
+	rv.buildTable()
+}
+
+// SetClient sets the LDAP client used to apply attribute edits
+func (rv *RecordView) SetClient(client *ldap.Client) {
+	rv.client = client
+	rv.client.SetOperationalAttributes(!rv.hideEmpty)
+}
+
+// SetSchema sets the server schema used to pick type-aware editors and to
+// spell attribute names when they are normalized
+func (rv *RecordView) SetSchema(schema *ldap.Schema) {
+	rv.schema = schema
+	if rv.normalizeNames || rv.showOIDs {
+		rv.buildTable()
+	}
+}
+
+// SetNormalizeAttributeNames sets whether attribute names are shown in the
+// case the schema defines rather than as the server returned them
+func (rv *RecordView) SetNormalizeAttributeNames(normalize bool) {
+	rv.normalizeNames = normalize
+	rv.buildTable()
+}
+
+// attributeLabel returns how the attribute name is shown in the table. Only
+// the label changes; edits and copies use the name the server returned.
+func (rv *RecordView) attributeLabel(name string) string {
+	if !rv.normalizeNames || rv.raw != nil {
+		return name
+	}
+	return rv.schema.CanonicalName(name)
+}
+
+// attributeHeading returns the attribute name as shown in its row: the label,
+// followed by the attribute type's OID and syntax while they are toggled on
+// and the schema defines the attribute
+func (rv *RecordView) attributeHeading(name string) string {
+	label := rv.attributeLabel(name)
+	if !rv.showOIDs || rv.raw != nil {
+		// Raw mode has a column of its own for the OIDs
+		return label
+	}
+	at := rv.schema.AttributeType(name)
+	if at == nil {
+		return label
+	}
+	if at.Syntax == "" {
+		return fmt.Sprintf("%s (%s)", label, at.OID)
+	}
+	return fmt.Sprintf("%s (%s, %s)", label, at.OID, ldap.SyntaxName(at.Syntax))
+}
+
+// toggleOIDs switches the OID and syntax annotations of attribute names
+func (rv *RecordView) toggleOIDs() string {
+	rv.showOIDs = !rv.showOIDs
 	rv.buildTable()
+	switch {
+	case !rv.showOIDs:
+		return "Showing attribute names only"
+	case rv.schema == nil:
+		return "Showing attribute OIDs and syntaxes once the schema has loaded"
+	default:
+		return "Showing attribute OIDs and syntaxes"
+	}
+}
+
+// IsEditing returns true while an attribute editor, a value removal prompt,
+// the attribute retry prompt or the effective rights identity input is open
+func (rv *RecordView) IsEditing() bool {
+	return rv.editor != nil || rv.pendingRemoval != nil || rv.retry != nil || rv.rights != nil
+}
+
+// HasUnsavedChanges reports whether an attribute editor is open or a change
+// is being written
+func (rv *RecordView) HasUnsavedChanges() bool {
+	return rv.editor != nil || rv.saving
 }
 
 // Update handles messages for the record view
@@ -136,10 +551,149 @@ func (rv *RecordView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case EntryModifiedMsg:
+		rv.saving = false
+		snapshot := rv.snapshot
+		rv.snapshot = nil
+		rv.SetEntry(msg.Entry)
+		return rv, rv.highlightChanges(snapshot, msg.Entry)
+
+	case ChangeHighlightExpiredMsg:
+		if msg.Seq == rv.changedSeq {
+			rv.changed = nil
+		}
+		return rv, nil
+
+	case EntryModifyErrorMsg:
+		rv.saving = false
+		rv.snapshot = nil
+		rv.editError = msg.Err
+		rv.pendingRemoval = nil
+		return rv, nil
+
+	case EntryInfoLoadedMsg:
+		if rv.info != nil && rv.info.dn == msg.DN {
+			rv.info.loading = false
+			rv.info.rows = buildInfoRows(withMetadata(rv.entry, msg.Attributes))
+		}
+		return rv, nil
+
+	case EffectiveRightsLoadedMsg:
+		rv.handleRightsLoaded(msg)
+		return rv, nil
+
+	case EntryReloadedMsg:
+		rv.handleEntryReloaded(msg)
+		return rv, nil
+
+	case RawEntryLoadedMsg:
+		rv.handleRawEntryLoaded(msg)
+		return rv, nil
+
+	case MoreValuesLoadedMsg:
+		return rv, rv.handleMoreValuesLoaded(msg)
+
+	case EntryInfoErrorMsg:
+		if rv.info != nil && rv.info.dn == msg.DN {
+			rv.info.loading = false
+			rv.info.err = msg.Err
+		}
+		return rv, nil
+
 	case tea.KeyMsg:
+		if rv.editor != nil {
+			return rv.handleEditMode(msg)
+		}
+		if rv.pendingRemoval != nil {
+			return rv.handleRemovalConfirm(msg)
+		}
+		if rv.info != nil {
+			return rv.handleInfoKey(msg)
+		}
+		if rv.copyMenu != nil {
+			return rv.handleCopyMenuKey(msg)
+		}
+		if rv.certificate != nil {
+			return rv.handleCertificateKey(msg)
+		}
+		if rv.rights != nil {
+			return rv.handleRightsKey(msg)
+		}
+		if rv.retry != nil {
+			return rv.handleRetryKey(msg)
+		}
+
+		// Raw mode shows a separate read of the entry, so changes are made from the curated view
+		if rv.raw != nil {
+			switch msg.String() {
+			case "e", "E", "+", "-":
+				return rv, SendError(fmt.Errorf("leave raw mode with R to change attributes"))
+			case "l", "L":
+				return rv, SendError(fmt.Errorf("leave raw mode with R to load more values"))
+			}
+		}
+
 		switch msg.String() {
 		case "c", "C":
 			return rv, rv.copyCurrentValue()
+		case "m", "M":
+			return rv, rv.openCopyMenu()
+		case "e", "E":
+			return rv, rv.startEdit()
+		case "i", "I":
+			return rv, rv.openInfo()
+		case "p", "P":
+			return rv, rv.openCertificate()
+		case "g", "G":
+			return rv, rv.openRights()
+		case "r", "R":
+			return rv, rv.toggleRaw()
+		case "n", "N":
+			return rv, rv.startRetry()
+		case "f":
+			return rv, rv.copyMatchingFilter()
+		case "F":
+			return rv, rv.queryMatchingFilter()
+		case "y", "Y":
+			return rv, rv.copyAsCode()
+		case "b", "B":
+			if rv.entry == nil {
+				return rv, SendError(fmt.Errorf("no record selected"))
+			}
+			return rv, ChangeBaseDN(rv.entry.DN)
+		case "t", "T":
+			if rv.entry == nil {
+				return rv, SendError(fmt.Errorf("no record selected"))
+			}
+			return rv, PinEntry(rv.entry)
+		case "o", "O":
+			if rv.entry == nil {
+				return rv, SendError(fmt.Errorf("no record selected"))
+			}
+			return rv, openAliasTarget(rv.client, rv.entry.AliasTarget())
+		case " ", "x":
+			rv.toggleExpanded()
+			return rv, nil
+		case "l", "L":
+			return rv, rv.loadMoreValues()
+		case "v", "V":
+			return rv, SendStatus(rv.cycleMultiValueDisplay())
+		case "h", "H":
+			return rv, rv.toggleHideEmpty()
+		case "a", "A":
+			return rv, SendStatus(rv.toggleShowAll())
+		case "s", "S":
+			return rv, SendStatus(rv.toggleOIDs())
+		case "shift+tab":
+			return rv, SendStatus(rv.cycleColumnFocus())
+		case "<":
+			return rv, rv.resizeFocusedColumn(-1)
+		case ">":
+			return rv, rv.resizeFocusedColumn(1)
+		case "+":
+			return rv, rv.startAddValue()
+		case "-":
+			return rv, rv.startRemoveValue()
 		case "up", "k":
 			if len(rv.renderedRows) > 0 {
 				cursor := rv.table.Cursor()
@@ -235,11 +789,395 @@ func (rv *RecordView) View() string {
 		return rv.container.RenderWithPadding(content)
 	}
 
+	if rv.editor != nil {
+		content := rv.dnHeader + "\n\n" + rv.renderEditor()
+		return rv.container.RenderWithPadding(content)
+	}
+
+	if rv.pendingRemoval != nil {
+		content := rv.dnHeader + "\n\n" + rv.renderRemovalConfirm()
+		return rv.container.RenderWithPadding(content)
+	}
+
+	if rv.info != nil {
+		content := rv.dnHeader + "\n\n" + rv.renderInfo()
+		return rv.container.RenderWithPadding(content)
+	}
+
+	if rv.copyMenu != nil {
+		content := rv.dnHeader + "\n\n" + rv.renderCopyMenu()
+		return rv.container.RenderWithPadding(content)
+	}
+
+	if rv.certificate != nil {
+		content := rv.dnHeader + "\n\n" + rv.renderCertificate()
+		return rv.container.RenderWithPadding(content)
+	}
+
+	if rv.rights != nil {
+		content := rv.dnHeader + "\n\n" + rv.renderRights()
+		return rv.container.RenderWithPadding(content)
+	}
+
 	// Create content with DN header and custom table rendering
 	content := rv.dnHeader + "\n\n" + rv.renderTable()
 	return rv.container.RenderWithPadding(content)
 }
 
+// startEdit opens an editor for the attribute under the cursor
+func (rv *RecordView) startEdit() tea.Cmd {
+	if rv.entry == nil {
+		return SendError(fmt.Errorf("no record selected"))
+	}
+	if cmd := refuseWrite(rv.client); cmd != nil {
+		return cmd
+	}
+
+	cursor := rv.table.Cursor()
+	if cursor < 0 || cursor >= len(rv.renderedRows) {
+		return SendError(fmt.Errorf("no row selected"))
+	}
+
+	row := rv.renderedRows[cursor]
+	if rv.entry.HasMoreValues(row.AttributeName) {
+		// Replacing the values would drop the ones that haven't been read
+		return SendError(fmt.Errorf("only some values of %s are loaded; load the rest with L before editing it", row.AttributeName))
+	}
+	if cmd := rv.refuseWithoutWriteRights(row.AttributeName); cmd != nil {
+		return cmd
+	}
+	rv.editor = newAttributeEditor(row.AttributeName, row.Values, rv.schema.AttributeType(row.AttributeName))
+	if strings.EqualFold(row.AttributeName, "userPassword") && rv.client.Capabilities() != nil && !rv.client.Supports(ldap.OIDPasswordModify) {
+		// Without the extended operation the server won't hash the new password
+		if te, ok := rv.editor.(*textEditor); ok {
+			te.help = iconWarning.String() + " Server lacks the password modify operation; the value is stored as entered • [Enter] save • [Esc] cancel"
+		}
+	}
+	rv.editingAttr = rv.attributeKey(row.AttributeName)
+	rv.editOp = ldap.ModifyReplace
+	rv.editError = nil
+	return nil
+}
+
+// startAddValue opens an editor for a single new value of the attribute under the cursor
+func (rv *RecordView) startAddValue() tea.Cmd {
+	row, ok := rv.currentRow()
+	if !ok {
+		return SendError(fmt.Errorf("no row selected"))
+	}
+	if cmd := refuseWrite(rv.client); cmd != nil {
+		return cmd
+	}
+
+	if attrType := rv.schema.AttributeType(row.AttributeName); attrType != nil && attrType.SingleValue {
+		return SendError(fmt.Errorf("%s is single-valued; use edit instead", row.AttributeName))
+	}
+	if cmd := rv.refuseWithoutWriteRights(row.AttributeName); cmd != nil {
+		return cmd
+	}
+
+	rv.editor = newValueEditor(row.AttributeName, nil, rv.schema.AttributeType(row.AttributeName))
+	if te, ok := rv.editor.(*textEditor); ok {
+		te.help = "[Enter] add value • [Esc] cancel"
+	}
+	rv.editingAttr = rv.attributeKey(row.AttributeName)
+	rv.editOp = ldap.ModifyAdd
+	rv.editError = nil
+	return nil
+}
+
+// startRemoveValue asks for confirmation before removing the value under the cursor.
+// Attribute rows with several values must be expanded first so a single value can be picked.
+func (rv *RecordView) startRemoveValue() tea.Cmd {
+	row, ok := rv.currentRow()
+	if !ok {
+		return SendError(fmt.Errorf("no row selected"))
+	}
+	if cmd := refuseWrite(rv.client); cmd != nil {
+		return cmd
+	}
+
+	if !row.IsValueRow() {
+		if len(row.Values) != 1 {
+			return SendError(fmt.Errorf("expand %s and select the value to remove", row.AttributeName))
+		}
+		row.ValueIndex = 0
+	}
+	if cmd := rv.refuseWithoutWriteRights(row.AttributeName); cmd != nil {
+		return cmd
+	}
+
+	rv.pendingRemoval = &row
+	rv.editError = nil
+	return nil
+}
+
+// handleRemovalConfirm handles key presses while a value removal is awaiting confirmation
+func (rv *RecordView) handleRemovalConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if rv.saving {
+		return rv, nil
+	}
+
+	switch msg.String() {
+	case "y", "Y":
+		return rv, rv.removeValue()
+	case "n", "N", "esc":
+		rv.pendingRemoval = nil
+	}
+	return rv, nil
+}
+
+// removeValue deletes the pending value from its attribute and re-reads the entry
+func (rv *RecordView) removeValue() tea.Cmd {
+	row := rv.pendingRemoval
+	value := row.Values[row.ValueIndex]
+
+	if rv.client == nil {
+		rv.pendingRemoval = nil
+		return SendError(fmt.Errorf("not connected to an LDAP server"))
+	}
+
+	rv.saving = true
+	return rv.applyChange(ldap.AttributeChange{
+		Operation: ldap.ModifyDelete,
+		Attribute: rv.attributeKey(row.AttributeName),
+		Values:    []string{value},
+	})
+}
+
+// toggleExpanded shows or hides the value sub-rows of the attribute under the cursor
+func (rv *RecordView) toggleExpanded() {
+	row, ok := rv.currentRow()
+	if !ok || len(row.Values) < 2 {
+		return
+	}
+
+	rv.expanded[row.AttributeName] = !rv.isExpanded(row.AttributeName)
+	rv.buildTable()
+
+	// Keep the cursor on the attribute row so collapsing from a sub-row doesn't jump
+	for i, r := range rv.renderedRows {
+		if r.AttributeName == row.AttributeName && !r.IsValueRow() {
+			rv.table.SetCursor(i)
+			break
+		}
+	}
+	rv.adjustViewport()
+}
+
+// currentRow returns the row under the cursor
+func (rv *RecordView) currentRow() (RowData, bool) {
+	if rv.entry == nil {
+		return RowData{}, false
+	}
+
+	cursor := rv.table.Cursor()
+	if cursor < 0 || cursor >= len(rv.renderedRows) {
+		return RowData{}, false
+	}
+	return rv.renderedRows[cursor], true
+}
+
+// cancelEdit closes the active editor without applying changes
+func (rv *RecordView) cancelEdit() {
+	rv.editor = nil
+	rv.editingAttr = ""
+	rv.editError = nil
+	rv.saving = false
+}
+
+// handleEditMode handles key presses while an attribute editor is open
+func (rv *RecordView) handleEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if rv.saving {
+		return rv, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		rv.cancelEdit()
+		return rv, nil
+	case "ctrl+s":
+		return rv, rv.saveEdit()
+	case "enter":
+		if h, ok := rv.editor.(enterHandler); !ok || !h.HandlesEnter() {
+			return rv, rv.saveEdit()
+		}
+	}
+
+	return rv, rv.editor.Update(msg)
+}
+
+// saveEdit applies the editor's values to the attribute and re-reads the entry.
+// Full edits replace every value; adding a value only sends that value.
+func (rv *RecordView) saveEdit() tea.Cmd {
+	values, err := rv.editor.Values()
+	if err != nil {
+		rv.editError = err
+		return nil
+	}
+
+	if rv.editOp == ldap.ModifyAdd && len(values) == 0 {
+		rv.editError = fmt.Errorf("enter a value to add")
+		return nil
+	}
+
+	if rv.client == nil {
+		rv.editError = fmt.Errorf("not connected to an LDAP server")
+		return nil
+	}
+
+	rv.saving = true
+	rv.editError = nil
+
+	return rv.applyChange(ldap.AttributeChange{
+		Operation: rv.editOp,
+		Attribute: rv.editingAttr,
+		Values:    values,
+	})
+}
+
+// applyChange sends a single attribute change to the server and re-reads the entry
+func (rv *RecordView) applyChange(change ldap.AttributeChange) tea.Cmd {
+	client := rv.client
+	dn := rv.entry.DN
+	attribute := change.Attribute
+	rv.snapshot = rv.entry
+
+	return func() tea.Msg {
+		if err := client.Modify(dn, []ldap.AttributeChange{change}); err != nil {
+			return EntryModifyErrorMsg{Attribute: attribute, Err: err}
+		}
+		entry, err := client.GetEntry(dn)
+		if err != nil {
+			return EntryModifyErrorMsg{Attribute: attribute, Err: err}
+		}
+		return EntryModifiedMsg{Entry: entry, Attribute: attribute}
+	}
+}
+
+// highlightChanges flags the attributes that differ between the entry as it
+// was before a modify and after it, and returns the command that clears the
+// highlight again
+func (rv *RecordView) highlightChanges(before, after *ldap.Entry) tea.Cmd {
+	if before == nil || after == nil || before.DN != after.DN {
+		return nil
+	}
+	rv.changed = changedAttributes(before, after)
+	if len(rv.changed) == 0 {
+		return nil
+	}
+	rv.changedSeq++
+	seq := rv.changedSeq
+	return tea.Tick(changeHighlightDuration, func(time.Time) tea.Msg {
+		return ChangeHighlightExpiredMsg{Seq: seq}
+	})
+}
+
+// changedAttributes returns the lower-cased names of the attributes of after
+// that were added or whose values differ from before. Value order is ignored
+// since servers needn't keep it.
+func changedAttributes(before, after *ldap.Entry) map[string]bool {
+	old := make(map[string][]string, len(before.Attributes))
+	for name, values := range before.Attributes {
+		old[strings.ToLower(name)] = values
+	}
+
+	changed := make(map[string]bool)
+	for name, values := range after.Attributes {
+		key := strings.ToLower(name)
+		previous, ok := old[key]
+		if !ok || !sameValues(previous, values) {
+			changed[key] = true
+		}
+	}
+	return changed
+}
+
+// sameValues reports whether a and b hold the same values in any order
+func sameValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		if counts[v] == 0 {
+			return false
+		}
+		counts[v]--
+	}
+	return true
+}
+
+// isChanged reports whether the attribute was changed by the last modify
+func (rv *RecordView) isChanged(name string) bool {
+	return rv.changed[strings.ToLower(name)]
+}
+
+// renderEditor renders the active attribute editor with its status and key hints
+func (rv *RecordView) renderEditor() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+
+	title := fmt.Sprintf("Editing %s", rv.editingAttr)
+	if rv.editOp == ldap.ModifyAdd {
+		title = fmt.Sprintf("Adding a value to %s", rv.editingAttr)
+	}
+
+	sections := []string{
+		titleStyle.Render(title),
+		"",
+		rv.editor.View(),
+	}
+
+	if rv.saving {
+		sections = append(sections, "", lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Italic(true).
+			Render(iconLoading.String()+" Saving..."))
+	} else if rv.editError != nil {
+		sections = append(sections, "", editorErrorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, ldap.Explain(rv.editError).Error())))
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	sections = append(sections, "", helpStyle.Render(rv.editor.Help()))
+
+	return strings.Join(sections, "\n")
+}
+
+// renderRemovalConfirm renders the confirmation prompt for removing a single value
+func (rv *RecordView) renderRemovalConfirm() string {
+	row := rv.pendingRemoval
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("9")).
+		Bold(true)
+
+	sections := []string{
+		titleStyle.Render(fmt.Sprintf("Remove a value from %s?", row.AttributeName)),
+		"",
+		editorLabelStyle.Render("Value:") + " " + row.Values[row.ValueIndex],
+	}
+
+	if rv.saving {
+		sections = append(sections, "", lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Italic(true).
+			Render(iconLoading.String()+" Removing..."))
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	sections = append(sections, "", helpStyle.Render("[Y] remove • [N/Esc] cancel"))
+
+	return strings.Join(sections, "\n")
+}
+
 // buildTable builds the table data from the entry
 func (rv *RecordView) buildTable() {
 	if rv.entry == nil {
@@ -257,59 +1195,144 @@ func (rv *RecordView) buildTable() {
 		Background(lipgloss.Color("238")).
 		Width(contentWidth)
 
-	rv.dnHeader = dnStyle.Render(fmt.Sprintf("DN: %s", rv.entry.DN))
+	if rv.raw != nil {
+		badge := rawBadgeStyle.Render("RAW")
+		rv.dnHeader = badge + dnStyle.Width(contentWidth-lipgloss.Width(badge)).Render(fmt.Sprintf(" DN: %s (all attributes, server order)", rv.entry.DN))
+	} else {
+		rv.dnHeader = dnStyle.Render(fmt.Sprintf("DN: %s", rv.entry.DN))
+	}
+	if rv.entry.IsAlias() {
+		rv.dnHeader += "\n" + renderAliasLine(rv.entry)
+	}
 
 	// Build table rows and row data
 	var rows []table.Row
 	rv.renderedRows = nil
 
-	// Sort attributes for consistent display
-	var attrNames []string
-	for name := range rv.entry.Attributes {
-		attrNames = append(attrNames, name)
+	var attributeRows []RowData
+	if rv.raw != nil {
+		if rv.raw.entry != nil {
+			attributeRows = rawRows(rv.raw.entry)
+		}
+	} else {
+		// Sort attributes for consistent display
+		attributes := rv.displayedAttributes()
+		var attrNames []string
+		for name := range attributes {
+			attrNames = append(attrNames, name)
+		}
+		sort.Slice(attrNames, func(i, j int) bool {
+			return rv.attributeLabel(attrNames[i]) < rv.attributeLabel(attrNames[j])
+		})
+		for _, name := range attrNames {
+			attributeRows = append(attributeRows, RowData{
+				AttributeName: name,
+				Values:        attributes[name],
+				ValueIndex:    -1,
+			})
+		}
 	}
-	sort.Strings(attrNames)
 
 	// Add attribute rows
-	for _, name := range attrNames {
-		values := rv.entry.Attributes[name]
+	for _, row := range attributeRows {
+		name, values := row.AttributeName, row.Values
 
 		// Store row data for click handling
-		rv.renderedRows = append(rv.renderedRows, RowData{
-			AttributeName: name,
-			Values:        values,
-		})
-
-		// Create value display
-		var valueText string
-		if len(values) == 1 {
-			valueText = values[0]
-		} else {
-			// For multiple values, join with bullet points
-			valueText = "• " + strings.Join(values, " • ")
+		rv.renderedRows = append(rv.renderedRows, row)
+		rows = append(rows, table.Row{rv.attributeHeading(name), rv.rowValueText(row)})
+
+		// Expanded attributes get one selectable sub-row per value
+		if rv.isExpanded(name) && len(values) > 1 {
+			for i := range values {
+				valueRow := RowData{
+					AttributeName: name,
+					Values:        values,
+					ValueIndex:    i,
+				}
+				rv.renderedRows = append(rv.renderedRows, valueRow)
+				rows = append(rows, table.Row{rv.attributeHeading(name), rv.rowValueText(valueRow)})
+			}
 		}
-
-		rows = append(rows, table.Row{name, valueText})
 	}
 
 	rv.table.SetRows(rows)
 }
+
+// rowValueText returns the value column text for a row
+func (rv *RecordView) rowValueText(row RowData) string {
+	if row.IsValueRow() {
+		return "  ↳ " + displayValue(row.AttributeName, row.Values[row.ValueIndex])
+	}
+	note := rv.valuesNote(row.AttributeName)
+	if len(row.Values) == 1 {
+		return displayValue(row.AttributeName, row.Values[0]) + note
+	}
+	if rv.isExpanded(row.AttributeName) {
+		return fmt.Sprintf("▼ %d values", len(row.Values)) + note
+	}
+	if rv.multiValue == config.MultiValueCount {
+		if note != "" {
+			return fmt.Sprintf("▶ %d+ values, expand to view", len(row.Values))
+		}
+		return fmt.Sprintf("▶ %d values, expand to view", len(row.Values))
+	}
+	values := make([]string, len(row.Values))
+	for i, value := range row.Values {
+		values[i] = displayValue(row.AttributeName, value)
+	}
+	if rv.separator == config.DefaultMultiValueSeparator {
+		// For multiple values, join with bullet points
+		return "• " + strings.Join(values, " • ") + note
+	}
+	return strings.Join(values, rv.separator) + note
+}
+
 func (rv *RecordView) renderTable() string {
+	if rv.raw != nil && rv.raw.entry == nil {
+		return rv.renderRawStatus()
+	}
+	hidden := rv.hiddenAttributeCount()
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+
 	if len(rv.renderedRows) == 0 {
-		return "No attributes to display"
+		if hidden > 0 {
+			return hintStyle.Render(fmt.Sprintf("None of the configured attributes are set on this entry • [A] show all %d attributes", hidden))
+		}
+		if rv.hideEmpty && len(rv.entry.Attributes) > 0 {
+			return lipgloss.NewStyle().
+				Foreground(lipgloss.Color("8")).
+				Italic(true).
+				Render("Every attribute of this entry is empty • [H] show empty attributes")
+		}
+		return rv.renderEmptyEntry()
 	}
 
 	// Get content dimensions
 	contentWidth, contentHeight := rv.container.GetContentDimensions()
-	nameWidth := contentWidth / 3
-	if nameWidth < 15 {
-		nameWidth = 15
+	nameWidth, valueWidth := rv.columnWidths(contentWidth)
+	oidWidth := rv.oidColumnWidth()
+	if oidWidth > 0 {
+		oidWidth = min(oidWidth, max(valueWidth-recordColumnMins[1]-2, 0))
+		valueWidth -= oidWidth + 2
 	}
-	valueWidth := contentWidth - nameWidth - 4
 
 	// Calculate available height for rows
 	availableHeight := contentHeight - 2 // Reserve space for DN header
-	showPagination := len(rv.renderedRows) > availableHeight
+	if rv.entry.IsAlias() {
+		availableHeight-- // Reserve 1 line for the alias target
+	}
+	if hidden > 0 {
+		availableHeight-- // Reserve 1 line for the hidden attributes hint
+	}
+	lineCounts := make([]int, len(rv.renderedRows))
+	totalLines := 0
+	for i, row := range rv.renderedRows {
+		lineCounts[i] = rv.rowLineCount(row, valueWidth)
+		totalLines += lineCounts[i]
+	}
+	showPagination := totalLines > availableHeight
 	if showPagination {
 		availableHeight = availableHeight - 1 // Reserve 1 line for pagination info
 	}
@@ -325,20 +1348,31 @@ func (rv *RecordView) renderTable() string {
 		BorderBottom(true).
 		Bold(false)
 
-	attributeHeader := lipgloss.NewStyle().Width(nameWidth).Render("Attribute")
-	valueHeader := lipgloss.NewStyle().Width(valueWidth).Render("Value(s)")
+	attributeHeader := lipgloss.NewStyle().Width(nameWidth).Render(rv.columns.title(0, recordColumnTitles[0]))
+	valueHeader := lipgloss.NewStyle().Width(valueWidth).Render(rv.columns.title(1, recordColumnTitles[1]))
+	headerCells := []string{attributeHeader, "  "}
+	if oidWidth > 0 {
+		headerCells = append(headerCells, lipgloss.NewStyle().Width(oidWidth).Render("OID"), "  ")
+	}
 	header := headerStyle.Render(
-		lipgloss.JoinHorizontal(lipgloss.Top, attributeHeader, "  ", valueHeader),
+		lipgloss.JoinHorizontal(lipgloss.Top, append(headerCells, valueHeader)...),
 	)
 
 	var rows []string
 	rows = append(rows, header)
 
-	// Calculate visible range based on viewport
+	// Calculate visible range based on viewport. Wrapped rows take several
+	// lines; a row that doesn't fit is left for the next page unless it is
+	// the first, which is cut off instead.
 	visibleStart := rv.viewport
-	visibleEnd := visibleStart + availableHeight
-	if visibleEnd > len(rv.renderedRows) {
-		visibleEnd = len(rv.renderedRows)
+	visibleEnd := visibleStart
+	usedLines := 0
+	for visibleEnd < len(rv.renderedRows) && usedLines < availableHeight {
+		if usedLines+lineCounts[visibleEnd] > availableHeight && visibleEnd > visibleStart {
+			break
+		}
+		usedLines += min(lineCounts[visibleEnd], availableHeight)
+		visibleEnd++
 	}
 
 	currentCursor := rv.table.Cursor()
@@ -347,17 +1381,10 @@ func (rv *RecordView) renderTable() string {
 	for i := visibleStart; i < visibleEnd; i++ {
 		rowData := rv.renderedRows[i]
 
-		// Create value display
-		var valueText string
-		if len(rowData.Values) == 1 {
-			valueText = rowData.Values[0]
-		} else {
-			valueText = "• " + strings.Join(rowData.Values, " • ")
-		}
-
-		if len(valueText) > valueWidth-3 {
-			valueText = valueText[:valueWidth-6] + "..."
-		}
+		// Create value display, only for the rows shown
+		valueLines := rv.valueLines(rowData, valueWidth)
+		valueLines = valueLines[:min(len(valueLines), availableHeight)]
+		valueText := strings.Join(valueLines, "\n")
 
 		var attrStyle, valueStyle lipgloss.Style
 
@@ -380,6 +1407,10 @@ func (rv *RecordView) renderTable() string {
 				Foreground(lipgloss.Color("15")).
 				Bold(true).
 				Width(valueWidth)
+		} else if rv.isChanged(rowData.AttributeName) {
+			// Just modified: highlighted until the highlight expires
+			attrStyle = changedRowStyle.Width(nameWidth)
+			valueStyle = changedRowStyle.Width(valueWidth)
 		} else {
 			// Normal row: Use subtle/no background
 			attrStyle = lipgloss.NewStyle().
@@ -388,9 +1419,23 @@ func (rv *RecordView) renderTable() string {
 				Width(valueWidth)
 		}
 
-		attributeCell := attrStyle.Render(rowData.AttributeName)
+		attributeName := rv.attributeHeading(rowData.AttributeName)
+		if rowData.IsValueRow() {
+			attributeName = ""
+		} else if rv.showOIDs {
+			attributeName = truncateToWidth(attributeName, nameWidth)
+		}
+		attributeCell := attrStyle.Height(len(valueLines)).Render(attributeName)
 		valueCell := valueStyle.Render(valueText)
-		rowContent := lipgloss.JoinHorizontal(lipgloss.Top, attributeCell, "  ", valueCell)
+		cells := []string{attributeCell, "  "}
+		if oidWidth > 0 {
+			var oid string
+			if at := rv.schema.AttributeType(rowData.AttributeName); at != nil && !rowData.IsValueRow() {
+				oid = truncateToWidth(at.OID, oidWidth)
+			}
+			cells = append(cells, attrStyle.Width(oidWidth).Height(len(valueLines)).Render(oid), "  ")
+		}
+		rowContent := lipgloss.JoinHorizontal(lipgloss.Top, append(cells, valueCell)...)
 
 		// Add clickable zone
 		zoneID := fmt.Sprintf("record-row-%d", i)
@@ -411,9 +1456,23 @@ func (rv *RecordView) renderTable() string {
 		content += "\n" + paginationStyle.Render(paginationText)
 	}
 
+	if hidden > 0 {
+		content += "\n" + hintStyle.Render(fmt.Sprintf("%d more attributes hidden • [A] show all", hidden))
+	}
+
 	return content
 }
 
+// attributeKey returns the name the entry stores the attribute called name
+// under, which may differ in case from the name shown, so changes are made
+// to the attribute as the server returned it
+func (rv *RecordView) attributeKey(name string) string {
+	if key, ok := rv.entry.AttributeKey(name); ok {
+		return key
+	}
+	return strings.TrimSpace(name)
+}
+
 // copyCurrentValue copies the current row's value to clipboard
 func (rv *RecordView) copyCurrentValue() tea.Cmd {
 	if rv.entry == nil {
@@ -433,15 +1492,23 @@ func (rv *RecordView) copyCurrentValue() tea.Cmd {
 	}
 
 	attributeName := row[0]
+	if selectedRow < len(rv.renderedRows) {
+		// The label may be normalized, so look the values up by the returned name
+		attributeName = rv.renderedRows[selectedRow].AttributeName
+	}
 
-	// Get the original values from the entry for copying
-	values, exists := rv.entry.Attributes[attributeName]
+	// Get the original values from the entry for copying, whatever the case
+	// of the name shown
+	values, exists := rv.entry.Lookup(attributeName)
 	if !exists {
 		return SendError(fmt.Errorf("attribute not found"))
 	}
 
-	// Join multiple values with comma and space
+	// Join multiple values with comma and space; value sub-rows copy just their value
 	valueText := strings.Join(values, ", ")
+	if selectedRow < len(rv.renderedRows) && rv.renderedRows[selectedRow].IsValueRow() {
+		valueText = values[rv.renderedRows[selectedRow].ValueIndex]
+	}
 
 	// Copy the value to clipboard
 	err := clipboard.WriteAll(valueText)
@@ -451,7 +1518,7 @@ func (rv *RecordView) copyCurrentValue() tea.Cmd {
 
 	// Provide feedback about what was copied
 	msg := fmt.Sprintf("Copied %s value to clipboard", attributeName)
-	return SendStatus(msg)
+	return SendSuccess(msg)
 }
 
 // Helper function to convert gamut colors to hex strings
@@ -493,14 +1560,50 @@ func (rv *RecordView) adjustViewport() {
 
 	cursor := rv.table.Cursor()
 
-	// Adjust viewport to keep cursor visible
+	// Adjust viewport to keep cursor visible, counting the lines of wrapped rows
 	if cursor < rv.viewport {
 		rv.viewport = cursor
-	} else if cursor >= rv.viewport+availableHeight {
-		rv.viewport = cursor - availableHeight + 1
+	} else if cursor < len(rv.renderedRows) {
+		contentWidth, _ := rv.container.GetContentDimensions()
+		_, valueWidth := rv.columnWidths(contentWidth)
+		lines := 0
+		for i := cursor; i >= rv.viewport; i-- {
+			lines += rv.rowLineCount(rv.renderedRows[i], valueWidth)
+			if lines > availableHeight && i < cursor {
+				rv.viewport = i + 1
+				break
+			}
+		}
 	}
 
 	if rv.viewport < 0 {
 		rv.viewport = 0
 	}
 }
+
+// Commands lists the record view's actions for the command palette, none
+// until an entry is shown
+func (rv *RecordView) Commands() []paletteCommand {
+	if rv.entry == nil {
+		return nil
+	}
+	status := func(toggle func() string) func() tea.Cmd {
+		return func() tea.Cmd { return SendStatus(toggle()) }
+	}
+	return []paletteCommand{
+		{title: "Edit the selected attribute", key: "e", run: rv.startEdit},
+		{title: "Copy the selected value", key: "c", run: rv.copyCurrentValue},
+		{title: "Copy the selected value as...", key: "m", run: rv.openCopyMenu},
+		{title: "Show entry info", key: "i", run: rv.openInfo},
+		{title: "Check effective rights", key: "g", run: rv.openRights},
+		{title: "Toggle raw mode", key: "r", run: rv.toggleRaw},
+		{title: "Copy a filter matching the entry", key: "f", run: rv.copyMatchingFilter},
+		{title: "Query for the entry", key: "F", run: rv.queryMatchingFilter},
+		{title: "Copy the entry as code", key: "y", run: rv.copyAsCode},
+		{title: "Pin the entry", key: "t", run: func() tea.Cmd { return PinEntry(rv.entry) }},
+		{title: "Cycle the multi-value style", key: "v", run: status(rv.cycleMultiValueDisplay)},
+		{title: "Hide empty and operational attributes", key: "h", run: rv.toggleHideEmpty},
+		{title: "Show all attributes", key: "a", run: status(rv.toggleShowAll)},
+		{title: "Show attribute OIDs", key: "s", run: status(rv.toggleOIDs)},
+	}
+}
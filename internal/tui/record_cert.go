@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// certificatePanel shows the certificates of the attribute under the cursor
+// one at a time, with what each says about itself
+type certificatePanel struct {
+	attribute string
+	values    []string
+	index     int // Value shown
+}
+
+// certificateLine summarizes a certificate value in one line for the record
+// table, where the DER bytes would garble the screen. It reports false when
+// the value doesn't parse, so it is shown as it is.
+func certificateLine(value string) (string, bool) {
+	summary, err := ldap.SummarizeCertificate(value)
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s (issued by %s, %s)", summary.Subject, summary.Issuer, summary.Validity(time.Now())), true
+}
+
+// displayValue returns value as the record table shows it: certificates are
+// summarized and every other value is shown as it is
+func displayValue(attribute, value string) string {
+	if ldap.IsCertificateAttribute(attribute) {
+		if line, ok := certificateLine(value); ok {
+			return line
+		}
+	}
+	return value
+}
+
+// IsCertificateOpen returns true while the certificate panel is shown
+func (rv *RecordView) IsCertificateOpen() bool {
+	return rv.certificate != nil
+}
+
+// openCertificate shows the certificate panel for the attribute or value
+// under the cursor
+func (rv *RecordView) openCertificate() tea.Cmd {
+	row, ok := rv.currentRow()
+	if !ok {
+		return SendError(fmt.Errorf("no row selected"))
+	}
+	if !ldap.IsCertificateAttribute(row.AttributeName) || len(row.Values) == 0 {
+		return SendError(fmt.Errorf("%s doesn't hold certificates", row.AttributeName))
+	}
+	rv.certificate = &certificatePanel{
+		attribute: row.AttributeName,
+		values:    row.Values,
+		index:     max(row.ValueIndex, 0),
+	}
+	return nil
+}
+
+// handleCertificateKey handles key presses while the certificate panel is shown
+func (rv *RecordView) handleCertificateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	panel := rv.certificate
+	switch msg.String() {
+	case "esc":
+		rv.certificate = nil
+	case "left", "up", "k":
+		panel.index = (panel.index + len(panel.values) - 1) % len(panel.values)
+	case "right", "down", "j":
+		panel.index = (panel.index + 1) % len(panel.values)
+	case "d", "D":
+		return rv, rv.saveCertificate(".der")
+	case "p", "P":
+		return rv, rv.saveCertificate(".pem")
+	}
+	return rv, nil
+}
+
+// certificateFileName names the file a certificate is saved to after the
+// entry's RDN value and the attribute, with the value's number when the
+// attribute holds more than one
+func certificateFileName(dn, attribute string, index, count int, ext string) string {
+	rdn, _, _ := strings.Cut(ldap.AbbreviateDN(dn), " / ")
+	name, _, _ := strings.Cut(attribute, ";")
+	parts := []string{}
+	for _, part := range []string{fileSlug(rdn), fileSlug(name)} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "certificate")
+	}
+	if count > 1 {
+		parts = append(parts, fmt.Sprint(index+1))
+	}
+	return strings.Join(parts, "-") + ext
+}
+
+// saveCertificate writes the certificate shown to a file in the working
+// directory, as DER or wrapped in PEM depending on ext. An existing file is
+// left alone.
+func (rv *RecordView) saveCertificate(ext string) tea.Cmd {
+	panel := rv.certificate
+	value := panel.values[panel.index]
+	data := []byte(value)
+	if ext == ".pem" {
+		data = ldap.CertificatePEM(value)
+	}
+	path := certificateFileName(rv.entry.DN, panel.attribute, panel.index, len(panel.values), ext)
+	return func() tea.Msg {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, os.ErrExist) {
+			return ErrorMsg{Err: fmt.Errorf("%s already exists, move it away to save the certificate again", path)}
+		}
+		if err != nil {
+			return ErrorMsg{Err: fmt.Errorf("failed to save the certificate: %w", err)}
+		}
+		_, err = file.Write(data)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return ErrorMsg{Err: fmt.Errorf("failed to save the certificate: %w", err)}
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		return StatusMsg{Message: "Saved the certificate to " + path, Level: ToastSuccess}
+	}
+}
+
+// renderCertificate draws the certificate panel
+func (rv *RecordView) renderCertificate() string {
+	panel := rv.certificate
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("14")).
+		Bold(true)
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+
+	title := panel.attribute
+	if len(panel.values) > 1 {
+		title += fmt.Sprintf(" (certificate %d of %d)", panel.index+1, len(panel.values))
+	}
+	sections := []string{titleStyle.Render(title), ""}
+
+	help := "[D] save as .der • [P] save as .pem • [Esc] close"
+	if len(panel.values) > 1 {
+		help = "[←→] previous/next • " + help
+	}
+
+	summary, err := ldap.SummarizeCertificate(panel.values[panel.index])
+	if err != nil {
+		sections = append(sections, errorStyle.Render(err.Error()), "", helpStyle.Render(help))
+		return strings.Join(sections, "\n")
+	}
+
+	validity := summary.Validity(time.Now())
+	validityStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	if !strings.HasPrefix(validity, "expires") {
+		validityStyle = errorStyle
+	}
+	fields := []struct {
+		label string
+		value string
+	}{
+		{"Subject", summary.Subject},
+		{"Issuer", summary.Issuer},
+		{"Serial", summary.SerialNumber},
+		{"Valid from", summary.NotBefore.Format("2006-01-02 15:04:05 UTC")},
+		{"Valid until", summary.NotAfter.Format("2006-01-02 15:04:05 UTC") + " " + validityStyle.Render("("+validity+")")},
+		{"SHA-256", summary.Fingerprint},
+	}
+	for _, field := range fields {
+		sections = append(sections, labelStyle.Render(fmt.Sprintf("%-12s", field.label))+field.value)
+	}
+	sections = append(sections, "", helpStyle.Render(help))
+	return strings.Join(sections, "\n")
+}
@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+// newTestCertificate returns a self-signed DER certificate for commonName
+func newTestCertificate(t *testing.T, commonName string) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"Example"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(der)
+}
+
+// newCertificateRecordView shows an entry holding certs and selects
+// the certificate attribute
+func newCertificateRecordView(t *testing.T, certs ...string) *RecordView {
+	t.Helper()
+	zone.NewGlobal()
+	rv := NewRecordView()
+	rv.SetSize(140, 30)
+	rv.SetEntry(&ldap.Entry{
+		DN: "uid=jdoe,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"uid":                    {"jdoe"},
+			"userCertificate;binary": certs,
+		},
+	})
+	for i, row := range rv.renderedRows {
+		if row.AttributeName == "userCertificate;binary" {
+			rv.table.SetCursor(i)
+		}
+	}
+	return rv
+}
+
+func TestRecordView_CertificateSummaryInline(t *testing.T) {
+	rv := newCertificateRecordView(t, newTestCertificate(t, "jdoe"))
+	view := ansi.Strip(rv.View())
+	if !strings.Contains(view, "CN=jdoe,O=Example (issued by CN=jdoe,O=Example, expires in") {
+		t.Errorf("Expected the certificate summarized in the table, got:\n%s", view)
+	}
+
+	// Values that don't parse are shown as they are
+	if got := displayValue("userCertificate", "garbage"); got != "garbage" {
+		t.Errorf("Expected an unparsable value unchanged, got %q", got)
+	}
+}
+
+func TestRecordView_CertificatePanel(t *testing.T) {
+	first, second := newTestCertificate(t, "jdoe"), newTestCertificate(t, "jdoe laptop")
+	rv := newCertificateRecordView(t, first, second)
+	rv.Update(keyRune('p'))
+	if !rv.IsCertificateOpen() {
+		t.Fatal("Expected P to open the certificate panel")
+	}
+
+	view := ansi.Strip(rv.View())
+	for _, want := range []string{"userCertificate;binary (certificate 1 of 2)", "Subject     CN=jdoe,O=Example", "Serial      2A", "(expires in 89 days)", "SHA-256"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the panel, got:\n%s", want, view)
+		}
+	}
+	rv.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if view := ansi.Strip(rv.View()); !strings.Contains(view, "CN=jdoe laptop") {
+		t.Errorf("Expected the next certificate, got:\n%s", view)
+	}
+
+	// Both forms are saved to the working directory, without overwriting
+	t.Chdir(t.TempDir())
+	_, cmd := rv.Update(keyRune('d'))
+	if status, ok := cmd().(StatusMsg); !ok || !strings.HasSuffix(status.Message, "jdoe-usercertificate-2.der") {
+		t.Fatalf("Expected the DER file saved, got %#v", cmd())
+	}
+	if data, _ := os.ReadFile("jdoe-usercertificate-2.der"); string(data) != second {
+		t.Error("Expected the DER file to hold the certificate as stored")
+	}
+	if _, ok := cmd().(ErrorMsg); !ok {
+		t.Error("Expected an existing file to be left alone")
+	}
+	_, cmd = rv.Update(keyRune('p'))
+	if _, ok := cmd().(StatusMsg); !ok {
+		t.Fatalf("Expected the PEM file saved, got %#v", cmd())
+	}
+	data, _ := os.ReadFile("jdoe-usercertificate-2.pem")
+	if block, _ := pem.Decode(data); block == nil || string(block.Bytes) != second {
+		t.Errorf("Expected a PEM block holding the certificate, got %q", data)
+	}
+
+	rv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if rv.IsCertificateOpen() {
+		t.Error("Expected Esc to close the panel")
+	}
+
+	// Other attributes have no certificate to show
+	rv.table.SetCursor(0)
+	if _, cmd := rv.Update(keyRune('p')); rv.IsCertificateOpen() || cmd == nil {
+		t.Error("Expected P to be refused outside certificate attributes")
+	}
+}
+
+func TestCertificateFileName(t *testing.T) {
+	tests := []struct {
+		dn, attribute string
+		index, count  int
+		want          string
+	}{
+		{"cn=Issuing CA,ou=pki,dc=example,dc=com", "cACertificate;binary", 0, 1, "issuing-ca-cacertificate.pem"},
+		{"uid=jdoe,dc=example,dc=com", "userCertificate", 2, 3, "jdoe-usercertificate-3.pem"},
+		{"", "", 0, 1, "certificate.pem"},
+	}
+	for _, tt := range tests {
+		if got := certificateFileName(tt.dn, tt.attribute, tt.index, tt.count, ".pem"); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
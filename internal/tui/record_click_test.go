@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func newRecordClickModel() *Model {
+	model := NewModel(nil, config.Default())
+	model.SetSize(80, 24)
+	model.recordView.SetEntry(&ldap.Entry{
+		DN: "cn=test,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"cn":   {"test"},
+			"mail": {"test@example.com"},
+			"sn":   {"Tester"},
+		},
+	})
+	model.currentView = ViewModeRecord
+	return model
+}
+
+func TestRecordViewClick_FirstClickSelects(t *testing.T) {
+	model := newRecordClickModel()
+
+	_, cmd := model.handleRecordViewClick("record-row-2", tea.MouseMsg{Type: tea.MouseLeft})
+	if model.recordView.table.Cursor() != 2 {
+		t.Errorf("Expected cursor on row 2, got %d", model.recordView.table.Cursor())
+	}
+	if cmd != nil {
+		t.Error("Expected first click on a row only to select it")
+	}
+}
+
+func TestRecordViewClick_SecondClickCopies(t *testing.T) {
+	model := newRecordClickModel()
+
+	model.handleRecordViewClick("record-row-1", tea.MouseMsg{Type: tea.MouseLeft})
+	_, cmd := model.handleRecordViewClick("record-row-1", tea.MouseMsg{Type: tea.MouseLeft})
+	if cmd == nil {
+		t.Fatal("Expected second click on the selected row to copy its value")
+	}
+
+	// Depending on clipboard availability this is either a confirmation or an error
+	switch msg := cmd().(type) {
+	case StatusMsg:
+		if msg.Message != "Copied mail value to clipboard" {
+			t.Errorf("Unexpected status message: %q", msg.Message)
+		}
+	case ErrorMsg:
+		t.Logf("Clipboard unavailable in test environment: %v", msg.Err)
+	default:
+		t.Errorf("Expected StatusMsg or ErrorMsg, got %T", msg)
+	}
+}
+
+func TestRecordViewClick_ModifierClickCopies(t *testing.T) {
+	model := newRecordClickModel()
+
+	_, cmd := model.handleRecordViewClick("record-row-2", tea.MouseMsg{Type: tea.MouseLeft, Ctrl: true})
+	if model.recordView.table.Cursor() != 2 {
+		t.Errorf("Expected cursor on row 2, got %d", model.recordView.table.Cursor())
+	}
+	if cmd == nil {
+		t.Error("Expected ctrl-click to select and copy in one step")
+	}
+
+	_, cmd = model.handleRecordViewClick("record-row-0", tea.MouseMsg{Type: tea.MouseLeft, Alt: true})
+	if cmd == nil {
+		t.Error("Expected alt-click to copy")
+	}
+}
+
+func TestRecordViewClick_OutOfRange(t *testing.T) {
+	model := newRecordClickModel()
+
+	_, cmd := model.handleRecordViewClick("record-row-99", tea.MouseMsg{Type: tea.MouseLeft})
+	if cmd != nil {
+		t.Error("Expected click outside the rendered rows to be ignored")
+	}
+}
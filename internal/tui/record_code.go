@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/export"
+)
+
+// SetCodeLanguage sets the language the record is copied as code in, see
+// export.Language*. Empty selects Go.
+func (rv *RecordView) SetCodeLanguage(language string) {
+	rv.codeLanguage = language
+}
+
+// copyAsCode copies the record to the clipboard as a Go map or Python dict
+// literal, for tests and scripts that mirror real directory data
+func (rv *RecordView) copyAsCode() tea.Cmd {
+	if rv.entry == nil {
+		return SendError(fmt.Errorf("no record selected"))
+	}
+	code, err := export.EntryCode(rv.entry, rv.codeLanguage)
+	if err != nil {
+		return SendError(err)
+	}
+	if err := clipboard.WriteAll(code); err != nil {
+		return SendError(fmt.Errorf("failed to copy to clipboard: %w", err))
+	}
+	language := "Go"
+	if strings.EqualFold(rv.codeLanguage, export.LanguagePython) {
+		language = "Python"
+	}
+	note := ""
+	if len(rv.entry.MoreValues) > 0 {
+		note = " (some attributes are only partly loaded, press L for the rest first)"
+	}
+	return SendSuccess(fmt.Sprintf("Copied %s as %s code%s", rv.entry.DN, language, note))
+}
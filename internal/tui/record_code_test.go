@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atotto/clipboard"
+)
+
+func TestRecordView_CopyAsCode(t *testing.T) {
+	if err := clipboard.WriteAll(""); err != nil {
+		t.Skipf("Clipboard not available in test environment: %v", err)
+	}
+
+	rv := newMultiValueRecordView()
+	rv.SetCodeLanguage("python")
+	_, cmd := rv.Update(keyRune('y'))
+	status, ok := cmd().(StatusMsg)
+	if !ok || status.Message != "Copied cn=admins,ou=groups,dc=example,dc=com as Python code" {
+		t.Fatalf("Expected a copy status, got %#v", cmd())
+	}
+
+	copied, err := clipboard.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(copied, "# cn=admins,ou=groups,dc=example,dc=com\n{\n") || !strings.Contains(copied, `"member": ["uid=alice,ou=people,dc=example,dc=com", `) {
+		t.Errorf("Expected the entry as a Python dict, got:\n%s", copied)
+	}
+}
+
+func TestRecordView_CopyAsCodeErrors(t *testing.T) {
+	rv := NewRecordView()
+	if _, cmd := rv.Update(keyRune('y')); cmd == nil {
+		t.Fatal("Expected an error without a record")
+	} else if _, ok := cmd().(ErrorMsg); !ok {
+		t.Errorf("Expected an error without a record, got %#v", cmd())
+	}
+
+	rv = newMultiValueRecordView()
+	rv.SetCodeLanguage("ruby")
+	_, cmd := rv.Update(keyRune('y'))
+	if msg, ok := cmd().(ErrorMsg); !ok || !strings.Contains(msg.Err.Error(), "unknown code language") {
+		t.Errorf("Expected an unknown language to be reported, got %#v", cmd())
+	}
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/export"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// copyOption is one form the copy menu can copy an attribute in
+type copyOption struct {
+	key   string // Key choosing the option directly
+	label string
+	text  string // What is copied
+}
+
+// copyMenu offers the forms the attribute under the cursor can be copied in
+type copyMenu struct {
+	attribute string
+	options   []copyOption
+	cursor    int
+}
+
+// copyOptions lists the forms values of attribute can be copied in. On a
+// value row index is the value shown, -1 on an attribute row, where the
+// LDIF, decoded and base64 forms cover every value, one per line. The
+// decoded form is only offered when every value can be decoded.
+func copyOptions(attribute string, values []string, index int) []copyOption {
+	if len(values) == 0 {
+		return nil
+	}
+	scope := values
+	if index >= 0 && index < len(values) {
+		scope = values[index : index+1]
+	}
+
+	options := []copyOption{{key: "r", label: "Raw value", text: scope[0]}}
+	if len(values) > 1 {
+		options = append(options, copyOption{key: "a", label: "All values, one per line", text: strings.Join(values, "\n")})
+	}
+
+	var ldif strings.Builder
+	decoded := make([]string, 0, len(scope))
+	encoded := make([]string, 0, len(scope))
+	for _, value := range scope {
+		ldif.WriteString(export.LDIFLine(attribute, value))
+		if text, ok := ldap.DecodeValue(attribute, value); ok {
+			decoded = append(decoded, text)
+		}
+		encoded = append(encoded, base64.StdEncoding.EncodeToString([]byte(value)))
+	}
+	options = append(options, copyOption{key: "l", label: "LDIF", text: strings.TrimSuffix(ldif.String(), "\n")})
+	if len(decoded) == len(scope) {
+		options = append(options, copyOption{key: "d", label: "Decoded", text: strings.Join(decoded, "\n")})
+	}
+	return append(options, copyOption{key: "b", label: "Base64", text: strings.Join(encoded, "\n")})
+}
+
+// IsCopyMenuOpen returns true while the copy options are shown
+func (rv *RecordView) IsCopyMenuOpen() bool {
+	return rv.copyMenu != nil
+}
+
+// openCopyMenu shows the copy options for the attribute or value under the cursor
+func (rv *RecordView) openCopyMenu() tea.Cmd {
+	row, ok := rv.currentRow()
+	if !ok {
+		return SendError(fmt.Errorf("no row selected"))
+	}
+	rv.copyMenu = &copyMenu{
+		attribute: row.AttributeName,
+		options:   copyOptions(row.AttributeName, row.Values, row.ValueIndex),
+	}
+	return nil
+}
+
+// handleCopyMenuKey handles key presses while the copy options are shown
+func (rv *RecordView) handleCopyMenuKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	menu := rv.copyMenu
+	switch key := msg.String(); key {
+	case "esc", "m", "M":
+		rv.copyMenu = nil
+	case "up", "k":
+		if menu.cursor > 0 {
+			menu.cursor--
+		}
+	case "down", "j":
+		if menu.cursor < len(menu.options)-1 {
+			menu.cursor++
+		}
+	case "enter":
+		return rv, rv.copyOption(menu.options[menu.cursor])
+	default:
+		for _, option := range menu.options {
+			if option.key == key {
+				return rv, rv.copyOption(option)
+			}
+		}
+	}
+	return rv, nil
+}
+
+// copyOption copies one of the menu's forms to the clipboard and closes the menu
+func (rv *RecordView) copyOption(option copyOption) tea.Cmd {
+	attribute := rv.copyMenu.attribute
+	rv.copyMenu = nil
+	if err := clipboard.WriteAll(option.text); err != nil {
+		return SendError(fmt.Errorf("failed to copy to clipboard: %w", err))
+	}
+	return SendSuccess(fmt.Sprintf("Copied %s (%s) to clipboard", attribute, option.label))
+}
+
+// renderCopyMenu draws the copy options with a preview of what each copies
+func (rv *RecordView) renderCopyMenu() string {
+	menu := rv.copyMenu
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("14")).
+		Bold(true)
+	previewStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+
+	sections := []string{titleStyle.Render(fmt.Sprintf("Copy %s as", menu.attribute)), ""}
+	previewWidth := max(rv.width-40, 10)
+	for i, option := range menu.options {
+		// Only the first line is previewed; binary values would garble the screen
+		preview, _, _ := strings.Cut(option.text, "\n")
+		if strings.Contains(option.text, "\n") {
+			preview += " …"
+		}
+		preview = ansi.Truncate(strings.Map(printableRune, preview), previewWidth, "…")
+
+		line := fmt.Sprintf("[%s] %-26s %s", strings.ToUpper(option.key), option.label, previewStyle.Render(preview))
+		if i == menu.cursor {
+			sections = append(sections, editorFocusStyle.Render("▶ ")+line)
+		} else {
+			sections = append(sections, "  "+line)
+		}
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	sections = append(sections, "", helpStyle.Render("[↑↓] select • [Enter] or a letter to copy • [Esc] cancel"))
+	return strings.Join(sections, "\n")
+}
+
+// printableRune replaces control characters, so a preview stays on one line
+func printableRune(r rune) rune {
+	if r < 0x20 || r == 0x7f || r == utf8.RuneError {
+		return '·'
+	}
+	return r
+}
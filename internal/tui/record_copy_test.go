@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+// copyOptionTexts maps each copy option's key to what it copies
+func copyOptionTexts(options []copyOption) map[string]string {
+	texts := make(map[string]string, len(options))
+	for _, option := range options {
+		texts[option.key] = option.text
+	}
+	return texts
+}
+
+func TestCopyOptions_SingleValue(t *testing.T) {
+	texts := copyOptionTexts(copyOptions("description", []string{"café"}, -1))
+	want := map[string]string{
+		"r": "café",
+		"l": "description:: Y2Fmw6k=",
+		"b": "Y2Fmw6k=",
+	}
+	if len(texts) != len(want) {
+		t.Errorf("Expected options %v, got %v", want, texts)
+	}
+	for key, text := range want {
+		if texts[key] != text {
+			t.Errorf("Option %s: expected %q, got %q", key, text, texts[key])
+		}
+	}
+}
+
+func TestCopyOptions_AllValues(t *testing.T) {
+	values := []string{"uid=alice,dc=example,dc=com", "uid=bob,dc=example,dc=com"}
+	texts := copyOptionTexts(copyOptions("member", values, -1))
+	want := map[string]string{
+		"r": "uid=alice,dc=example,dc=com",
+		"a": "uid=alice,dc=example,dc=com\nuid=bob,dc=example,dc=com",
+		"l": "member: uid=alice,dc=example,dc=com\nmember: uid=bob,dc=example,dc=com",
+		"b": "dWlkPWFsaWNlLGRjPWV4YW1wbGUsZGM9Y29t\ndWlkPWJvYixkYz1leGFtcGxlLGRjPWNvbQ==",
+	}
+	for key, text := range want {
+		if texts[key] != text {
+			t.Errorf("Option %s: expected %q, got %q", key, text, texts[key])
+		}
+	}
+
+	// A value row copies just its value, though all values stay on offer
+	texts = copyOptionTexts(copyOptions("member", values, 1))
+	if texts["r"] != values[1] || texts["l"] != "member: "+values[1] || texts["a"] != want["a"] {
+		t.Errorf("Expected the value row's value, got %v", texts)
+	}
+}
+
+func TestCopyOptions_Decoded(t *testing.T) {
+	guid := string([]byte{0x78, 0x56, 0x34, 0x12, 0xbc, 0x9a, 0xf0, 0xde, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef})
+	tests := []struct {
+		attribute, value, decoded string
+	}{
+		{"objectGUID", guid, "12345678-9abc-def0-0123-456789abcdef"},
+		{"objectSid", "\x01\x02\x00\x00\x00\x00\x00\x05\x20\x00\x00\x00\x20\x02\x00\x00", "S-1-5-32-544"},
+		{"pwdLastSet", "133485408000000000", "2024-01-01 00:00:00 UTC"},
+		{"whenCreated", "20240131235959.0Z", "2024-01-31 23:59:59 UTC"},
+	}
+	for _, tt := range tests {
+		texts := copyOptionTexts(copyOptions(tt.attribute, []string{tt.value}, -1))
+		if texts["d"] != tt.decoded {
+			t.Errorf("%s: expected decoded %q, got %q", tt.attribute, tt.decoded, texts["d"])
+		}
+	}
+
+	if _, ok := copyOptionTexts(copyOptions("cn", []string{"alice"}, -1))["d"]; ok {
+		t.Error("Expected no decoded form for a plain string")
+	}
+}
+
+func TestRecordView_CopyMenu(t *testing.T) {
+	rv := newMultiValueRecordView()
+	rv.table.SetCursor(1)
+	rv.Update(keyRune('m'))
+	if !rv.IsCopyMenuOpen() || rv.copyMenu.attribute != "member" {
+		t.Fatalf("Expected the copy menu for member, got %+v", rv.copyMenu)
+	}
+	view := rv.View()
+	for _, label := range []string{"Copy member as", "Raw value", "All values, one per line", "LDIF", "Base64"} {
+		if !strings.Contains(view, label) {
+			t.Errorf("Expected %q in the menu, got:\n%s", label, view)
+		}
+	}
+
+	rv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if rv.IsCopyMenuOpen() {
+		t.Fatal("Expected esc to close the menu")
+	}
+
+	if err := clipboard.WriteAll(""); err != nil {
+		t.Skipf("Clipboard not available in test environment: %v", err)
+	}
+	rv.Update(keyRune('m'))
+	_, cmd := rv.Update(keyRune('l'))
+	if status, ok := cmd().(StatusMsg); !ok || status.Message != "Copied member (LDIF) to clipboard" {
+		t.Fatalf("Expected a copy status, got %#v", cmd())
+	}
+	if rv.IsCopyMenuOpen() {
+		t.Error("Expected copying to close the menu")
+	}
+	copied, _ := clipboard.ReadAll()
+	if !strings.HasPrefix(copied, "member: uid=alice,ou=people,dc=example,dc=com\nmember: ") {
+		t.Errorf("Expected the values as LDIF, got %q", copied)
+	}
+}
+
+func TestModel_CopyMenuHelpAndKeys(t *testing.T) {
+	zone.NewGlobal()
+	model, _ := newBaseDNModel(t, "")
+	model.recordView.SetEntry(&ldap.Entry{
+		DN:         "cn=test,dc=example,dc=com",
+		Attributes: map[string][]string{"cn": {"test"}},
+	})
+	model.currentView = ViewModeRecord
+	model.Update(keyRune('m'))
+	if !model.recordView.IsCopyMenuOpen() {
+		t.Fatal("Expected M to open the copy menu")
+	}
+
+	if help := model.renderHelpBar(); !strings.Contains(help, "Copy as") {
+		t.Errorf("Expected the copy menu help, got %q", help)
+	}
+	if !model.isInputActive() {
+		t.Error("Expected the menu to hold on to the global keys")
+	}
+	if model.recordView.IsEditing() {
+		t.Error("Expected the copy menu not to count as editing")
+	}
+}
@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// EntryInfoLoadedMsg is sent when the metadata attributes of an entry have been read
+type EntryInfoLoadedMsg struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// EntryInfoErrorMsg is sent when reading the metadata attributes fails
+type EntryInfoErrorMsg struct {
+	DN  string
+	Err error
+}
+
+// metadataFormat controls how a metadata value is displayed
+type metadataFormat int
+
+const (
+	metadataPlain metadataFormat = iota
+	metadataTime
+	metadataGUID
+)
+
+// metadataFields lists the info panel rows. Each row shows the first of its
+// attributes the entry has, so OpenLDAP and Active Directory names both work.
+var metadataFields = []struct {
+	Label      string
+	Attributes []string
+	Format     metadataFormat
+}{
+	{"Created", []string{"createTimestamp", "whenCreated"}, metadataTime},
+	{"Modified", []string{"modifyTimestamp", "whenChanged"}, metadataTime},
+	{"Created by", []string{"creatorsName"}, metadataPlain},
+	{"Modified by", []string{"modifiersName"}, metadataPlain},
+	{"UUID", []string{"entryUUID", "objectGUID"}, metadataGUID},
+}
+
+// infoRow is a single formatted line of the info panel
+type infoRow struct {
+	Label     string
+	Attribute string
+	Value     string
+}
+
+// entryInfoPanel holds the state of the record view's metadata panel
+type entryInfoPanel struct {
+	dn      string
+	rows    []infoRow
+	cursor  int
+	loading bool
+	err     error
+}
+
+// buildInfoRows picks and formats the metadata values present in entry
+func buildInfoRows(entry *ldap.Entry) []infoRow {
+	var rows []infoRow
+	for _, field := range metadataFields {
+		for _, name := range field.Attributes {
+			attr, _ := entry.AttributeKey(name)
+			values, _ := entry.Lookup(name)
+			if len(values) == 0 {
+				continue
+			}
+			rows = append(rows, infoRow{
+				Label:     field.Label,
+				Attribute: attr,
+				Value:     formatMetadataValue(values[0], field.Format),
+			})
+			break
+		}
+	}
+	return rows
+}
+
+// formatMetadataValue renders a raw attribute value for the info panel
+func formatMetadataValue(value string, format metadataFormat) string {
+	switch format {
+	case metadataTime:
+		if t, err := ldap.ParseGeneralizedTime(value); err == nil {
+			return t.UTC().Format("2006-01-02 15:04:05 UTC")
+		}
+	case metadataGUID:
+		return ldap.FormatGUID(value)
+	}
+	return value
+}
+
+// withMetadata returns entry with the metadata read from the server added,
+// the latter taking precedence
+func withMetadata(entry *ldap.Entry, metadata map[string][]string) *ldap.Entry {
+	combined := &ldap.Entry{Attributes: make(map[string][]string, len(metadata))}
+	if entry != nil {
+		combined.DN = entry.DN
+		for name, values := range entry.Attributes {
+			combined.Attributes[name] = values
+		}
+	}
+	for name, values := range metadata {
+		combined.Attributes[name] = values
+	}
+	return combined
+}
+
+// IsInfoOpen returns true while the metadata panel is shown
+func (rv *RecordView) IsInfoOpen() bool {
+	return rv.info != nil
+}
+
+// openInfo shows the metadata panel for the current entry. The metadata is
+// read from the server when the entry was loaded without some of it (e.g. from
+// a query that asked for createTimestamp only).
+func (rv *RecordView) openInfo() tea.Cmd {
+	if rv.entry == nil {
+		return SendError(fmt.Errorf("no record selected"))
+	}
+
+	rv.info = &entryInfoPanel{
+		dn:   rv.entry.DN,
+		rows: buildInfoRows(rv.entry),
+	}
+	if len(rv.info.rows) == len(metadataFields) || rv.client == nil {
+		return nil
+	}
+
+	rv.info.loading = true
+	client := rv.client
+	dn := rv.entry.DN
+	return func() tea.Msg {
+		attributes, err := client.GetMetadata(dn)
+		if err != nil {
+			return EntryInfoErrorMsg{DN: dn, Err: err}
+		}
+		return EntryInfoLoadedMsg{DN: dn, Attributes: attributes}
+	}
+}
+
+// handleInfoKey handles key presses while the metadata panel is open
+func (rv *RecordView) handleInfoKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "i", "I":
+		rv.info = nil
+	case "up", "k":
+		if rv.info.cursor > 0 {
+			rv.info.cursor--
+		}
+	case "down", "j":
+		if rv.info.cursor < len(rv.info.rows)-1 {
+			rv.info.cursor++
+		}
+	case "c", "C":
+		return rv, rv.copyInfoValue()
+	}
+	return rv, nil
+}
+
+// copyInfoValue copies the selected metadata value, as displayed, to the clipboard
+func (rv *RecordView) copyInfoValue() tea.Cmd {
+	if rv.info.cursor >= len(rv.info.rows) {
+		return SendError(fmt.Errorf("no row selected"))
+	}
+	row := rv.info.rows[rv.info.cursor]
+
+	if err := clipboard.WriteAll(row.Value); err != nil {
+		return SendError(fmt.Errorf("failed to copy to clipboard: %w", err))
+	}
+	return SendSuccess(fmt.Sprintf("Copied %s value to clipboard", row.Attribute))
+}
+
+// renderInfo renders the metadata panel
+func (rv *RecordView) renderInfo() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	attrStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+
+	sections := []string{titleStyle.Render("Entry info"), ""}
+
+	switch {
+	case rv.info.loading:
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Italic(true).
+			Render(iconLoading.String()+" Loading metadata..."))
+	case rv.info.err != nil:
+		sections = append(sections, editorErrorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, ldap.Explain(rv.info.err).Error())))
+	case len(rv.info.rows) == 0:
+		sections = append(sections, "No creation or modification metadata available")
+	}
+
+	if !rv.info.loading {
+		for i, row := range rv.info.rows {
+			line := editorLabelStyle.Render(fmt.Sprintf("%-12s", row.Label)) + " " + row.Value + " " + attrStyle.Render("("+row.Attribute+")")
+			if i == rv.info.cursor {
+				sections = append(sections, editorFocusStyle.Render("▶ ")+line)
+			} else {
+				sections = append(sections, "  "+line)
+			}
+		}
+	}
+
+	sections = append(sections, "", helpStyle.Render("[↑↓] select • [C] copy • [I/Esc] close"))
+	return strings.Join(sections, "\n")
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func TestBuildInfoRows(t *testing.T) {
+	guid := string([]byte{0x78, 0x56, 0x34, 0x12, 0xbc, 0x9a, 0xf0, 0xde, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef})
+
+	rows := buildInfoRows(&ldap.Entry{Attributes: map[string][]string{
+		"whenCreated":     {"20240131235959.0Z"},
+		"modifyTimestamp": {"20240201083000Z"},
+		"modifiersName":   {"cn=admin,dc=example,dc=com"},
+		"objectGUID":      {guid},
+		"cn":              {"ignored"},
+	}})
+
+	want := []infoRow{
+		{"Created", "whenCreated", "2024-01-31 23:59:59 UTC"},
+		{"Modified", "modifyTimestamp", "2024-02-01 08:30:00 UTC"},
+		{"Modified by", "modifiersName", "cn=admin,dc=example,dc=com"},
+		{"UUID", "objectGUID", "12345678-9abc-def0-0123-456789abcdef"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("Expected %d rows, got %v", len(want), rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("Row %d: expected %v, got %v", i, want[i], rows[i])
+		}
+	}
+}
+
+func TestBuildInfoRows_CaseInsensitiveAndUnparsable(t *testing.T) {
+	rows := buildInfoRows(&ldap.Entry{Attributes: map[string][]string{
+		"createtimestamp": {"not a time"},
+		"entryuuid":       {"b3c8c5e2-1f0a-103e-8f5d-9b1d2c3a4e5f"},
+	}})
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %v", rows)
+	}
+	if rows[0].Value != "not a time" {
+		t.Errorf("Expected unparsable time shown as-is, got %q", rows[0].Value)
+	}
+	if rows[1].Value != "b3c8c5e2-1f0a-103e-8f5d-9b1d2c3a4e5f" {
+		t.Errorf("Expected entryUUID unchanged, got %q", rows[1].Value)
+	}
+}
+
+func newInfoRecordView(attributes map[string][]string) *RecordView {
+	rv := NewRecordView()
+	rv.SetSize(100, 30)
+	rv.SetEntry(&ldap.Entry{DN: "uid=jdoe,ou=people,dc=example,dc=com", Attributes: attributes})
+	return rv
+}
+
+func TestRecordView_InfoPanel(t *testing.T) {
+	rv := newInfoRecordView(map[string][]string{
+		"cn":              {"John Doe"},
+		"createTimestamp": {"20240131235959Z"},
+		"creatorsName":    {"cn=admin,dc=example,dc=com"},
+	})
+
+	if cmd := rv.openInfo(); cmd != nil {
+		t.Error("Expected no fetch without a client")
+	}
+	if !rv.IsInfoOpen() {
+		t.Fatal("Expected the info panel to open")
+	}
+
+	view := rv.View()
+	if !strings.Contains(view, "Entry info") || !strings.Contains(view, "2024-01-31 23:59:59 UTC") {
+		t.Error("Expected formatted metadata in the panel")
+	}
+
+	// Navigation stays within the panel
+	rv.Update(tea.KeyMsg{Type: tea.KeyDown})
+	rv.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if rv.info.cursor != 1 {
+		t.Errorf("Expected cursor to stop at the last row, got %d", rv.info.cursor)
+	}
+	if rv.table.Cursor() != 0 {
+		t.Error("Expected attribute table cursor to be unchanged")
+	}
+
+	rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	if rv.IsInfoOpen() {
+		t.Error("Expected 'i' to close the panel")
+	}
+}
+
+func TestRecordView_InfoPanelLoadsMissingMetadata(t *testing.T) {
+	rv := newInfoRecordView(map[string][]string{"cn": {"John Doe"}})
+	rv.SetClient(&ldap.Client{})
+
+	if cmd := rv.openInfo(); cmd == nil {
+		t.Fatal("Expected a fetch when metadata is missing")
+	}
+	if !rv.info.loading || !strings.Contains(rv.View(), "Loading metadata") {
+		t.Error("Expected a loading indicator")
+	}
+
+	// Results for another entry are ignored
+	rv.Update(EntryInfoLoadedMsg{DN: "cn=other", Attributes: map[string][]string{"entryUUID": {"x"}}})
+	if !rv.info.loading {
+		t.Error("Expected stale metadata to be ignored")
+	}
+
+	rv.Update(EntryInfoLoadedMsg{DN: rv.entry.DN, Attributes: map[string][]string{"modifiersName": {"cn=admin"}}})
+	if rv.info.loading || len(rv.info.rows) != 1 || rv.info.rows[0].Value != "cn=admin" {
+		t.Errorf("Expected fetched metadata to be shown, got %+v", rv.info)
+	}
+
+	rv.Update(EntryInfoErrorMsg{DN: rv.entry.DN, Err: errTest})
+	if !strings.Contains(rv.View(), errTest.Error()) {
+		t.Error("Expected fetch error in the panel")
+	}
+}
+
+func TestRecordView_InfoPanelLoadsPartlyMissingMetadata(t *testing.T) {
+	rv := newInfoRecordView(map[string][]string{
+		"cn":              {"John Doe"},
+		"createTimestamp": {"20240131235959Z"},
+	})
+	rv.SetClient(&ldap.Client{})
+
+	if cmd := rv.openInfo(); cmd == nil {
+		t.Fatal("Expected a fetch when some metadata is missing")
+	}
+
+	rv.Update(EntryInfoLoadedMsg{DN: rv.entry.DN, Attributes: map[string][]string{"modifiersName": {"cn=admin"}}})
+	if len(rv.info.rows) != 2 || rv.info.rows[0].Label != "Created" || rv.info.rows[1].Value != "cn=admin" {
+		t.Errorf("Expected the loaded and fetched metadata together, got %+v", rv.info.rows)
+	}
+}
+
+func TestRecordView_InfoPanelClosesOnNewEntry(t *testing.T) {
+	rv := newInfoRecordView(map[string][]string{"entryUUID": {"x"}})
+	rv.openInfo()
+	rv.SetEntry(&ldap.Entry{DN: "cn=other,dc=example,dc=com"})
+	if rv.IsInfoOpen() {
+		t.Error("Expected the panel to close when another entry is shown")
+	}
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// MoreValuesLoadedMsg is sent when the next values of an attribute the
+// server returned only in part have been read
+type MoreValuesLoadedMsg struct {
+	DN        string
+	Attribute string
+	Entry     *ldap.Entry // The entry with the new values appended
+	Err       error
+}
+
+// loadMoreValues reads the next chunk of values of the attribute under the
+// cursor, for attributes such as member on large Active Directory groups
+func (rv *RecordView) loadMoreValues() tea.Cmd {
+	row, ok := rv.currentRow()
+	if !ok {
+		return SendError(fmt.Errorf("no row selected"))
+	}
+	name := row.AttributeName
+	if !rv.entry.HasMoreValues(name) {
+		return SendStatus(fmt.Sprintf("All values of %s are loaded", name))
+	}
+	if rv.loadingMore != "" {
+		return nil
+	}
+	if rv.client == nil {
+		return SendError(fmt.Errorf("not connected to an LDAP server"))
+	}
+
+	rv.loadingMore = name
+	client := rv.client
+	entry := rv.entry
+	return func() tea.Msg {
+		updated, err := client.LoadMoreValues(entry, name)
+		return MoreValuesLoadedMsg{DN: entry.DN, Attribute: name, Entry: updated, Err: err}
+	}
+}
+
+// handleMoreValuesLoaded shows the values read by loadMoreValues
+func (rv *RecordView) handleMoreValuesLoaded(msg MoreValuesLoadedMsg) tea.Cmd {
+	if rv.entry == nil || rv.entry.DN != msg.DN || rv.loadingMore != msg.Attribute {
+		return nil
+	}
+	rv.loadingMore = ""
+	if msg.Err != nil {
+		return SendError(fmt.Errorf("failed to load more values of %s: %w", msg.Attribute, msg.Err))
+	}
+
+	loaded, _ := msg.Entry.Lookup(msg.Attribute)
+	previous, _ := rv.entry.Lookup(msg.Attribute)
+	added := len(loaded) - len(previous)
+	rv.entry = msg.Entry
+	rv.buildTable()
+
+	total := len(loaded)
+	if msg.Entry.HasMoreValues(msg.Attribute) {
+		return SendStatus(fmt.Sprintf("Loaded %d more values of %s (%d so far, [L] for more)", added, msg.Attribute, total))
+	}
+	return SendStatus(fmt.Sprintf("Loaded %d more values of %s; all %d values are loaded", added, msg.Attribute, total))
+}
+
+// valuesNote is appended to the value column of attributes that have more
+// values on the server than were returned
+func (rv *RecordView) valuesNote(name string) string {
+	if rv.raw != nil || !rv.entry.HasMoreValues(name) {
+		return ""
+	}
+	if rv.loadingMore == name {
+		return " … loading more"
+	}
+	return " … more on the server, [L] load more"
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+func TestRecordView_LoadMoreValues(t *testing.T) {
+	members := make([]string, 5)
+	for i := range members {
+		members[i] = fmt.Sprintf("uid=user%d,ou=people,dc=example,dc=com", i)
+	}
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "cn=staff,dc=example,dc=com", Attributes: map[string][]string{"cn": {"staff"}, "member": members}},
+	)
+	server.MaxValueRange = 2
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	entry, err := client.GetEntry("cn=staff,dc=example,dc=com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rv := NewRecordView()
+	rv.SetSize(120, 30)
+	rv.SetClient(client)
+	rv.SetEntry(entry)
+	rv.table.SetCursor(1)
+	if got := rv.rowValueText(rv.renderedRows[1]); !strings.Contains(got, "more on the server, [L] load more") {
+		t.Errorf("Expected a partial attribute to say more values can be loaded, got %q", got)
+	}
+
+	// Replacing the values of a partial attribute would drop the unread ones
+	if rv.startEdit() == nil || rv.editor != nil {
+		t.Error("Expected editing to be refused until every value is loaded")
+	}
+
+	for want := 4; want <= 5; want++ {
+		_, cmd := rv.Update(keyRune('L'))
+		if cmd == nil || rv.loadingMore != "member" {
+			t.Fatal("Expected L to start loading the next values")
+		}
+		if _, again := rv.Update(keyRune('L')); again != nil {
+			t.Error("Expected a second L to wait for the load in progress")
+		}
+		rv.Update(cmd())
+		if got := len(rv.entry.Attributes["member"]); got != want {
+			t.Fatalf("Expected %d members after loading more, got %d", want, got)
+		}
+	}
+	if rv.entry.HasMoreValues("member") || strings.Contains(rv.rowValueText(rv.renderedRows[1]), "[L]") {
+		t.Error("Expected the attribute to be complete")
+	}
+	if msg := rv.loadMoreValues()(); msg != (StatusMsg{Message: "All values of member are loaded"}) {
+		t.Errorf("Unexpected message once complete: %#v", msg)
+	}
+}
+
+func TestRecordView_StaleMoreValues(t *testing.T) {
+	rv := newMultiValueRecordView()
+	rv.loadingMore = "member"
+
+	// The result for an entry that is no longer shown is dropped
+	rv.SetEntry(&ldap.Entry{DN: "cn=other,dc=example,dc=com", Attributes: map[string][]string{"cn": {"other"}}})
+	rv.Update(MoreValuesLoadedMsg{DN: "cn=admins,ou=groups,dc=example,dc=com", Attribute: "member", Entry: &ldap.Entry{DN: "cn=admins,ou=groups,dc=example,dc=com"}})
+	if rv.entry.DN != "cn=other,dc=example,dc=com" || rv.loadingMore != "" {
+		t.Error("Expected a stale result to be ignored")
+	}
+}
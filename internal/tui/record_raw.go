@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// RawEntryLoadedMsg is sent when an entry has been re-read for raw mode
+type RawEntryLoadedMsg struct {
+	DN    string
+	Entry *ldap.Entry
+	Err   error
+}
+
+// rawEntryView holds the state of raw mode, which shows an entry exactly as
+// the server returns it when asked for everything
+type rawEntryView struct {
+	dn      string
+	entry   *ldap.Entry // nil while loading
+	loading bool
+	err     error
+}
+
+// rawBadgeStyle labels the DN header while raw mode is active
+var rawBadgeStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("0")).
+	Background(lipgloss.Color("11")).
+	Bold(true).
+	Padding(0, 1)
+
+// toggleRaw enters raw mode by re-reading the entry with all user,
+// operational and DN attributes, or leaves it
+func (rv *RecordView) toggleRaw() tea.Cmd {
+	if rv.raw != nil {
+		rv.raw = nil
+		rv.resetCursor()
+		return SendStatus("Raw mode off")
+	}
+	if rv.entry == nil {
+		return SendError(fmt.Errorf("no record selected"))
+	}
+	if rv.client == nil {
+		return SendError(fmt.Errorf("not connected to an LDAP server"))
+	}
+
+	rv.raw = &rawEntryView{dn: rv.entry.DN, loading: true}
+	rv.resetCursor()
+	client := rv.client
+	dn := rv.entry.DN
+	return func() tea.Msg {
+		entry, err := client.GetRawEntry(dn)
+		return RawEntryLoadedMsg{DN: dn, Entry: entry, Err: err}
+	}
+}
+
+// handleRawEntryLoaded shows the re-read entry if raw mode is still open for it
+func (rv *RecordView) handleRawEntryLoaded(msg RawEntryLoadedMsg) {
+	if rv.raw == nil || !strings.EqualFold(rv.raw.dn, msg.DN) {
+		return
+	}
+	rv.raw.loading = false
+	rv.raw.err = msg.Err
+	rv.raw.entry = msg.Entry
+	rv.buildTable()
+}
+
+// resetCursor moves the selection back to the first row after the rows change
+func (rv *RecordView) resetCursor() {
+	rv.buildTable()
+	rv.table.SetCursor(0)
+	rv.viewport = 0
+}
+
+// rawRows returns the rows of raw mode: the DN first, then every attribute
+// in the order the server returned them, with empty values kept
+func rawRows(entry *ldap.Entry) []RowData {
+	rows := []RowData{{AttributeName: "dn", Values: []string{entry.DN}, ValueIndex: -1}}
+
+	seen := make(map[string]bool, len(entry.Attributes))
+	for _, name := range entry.Order {
+		if values, ok := entry.Attributes[name]; ok && !seen[name] {
+			seen[name] = true
+			rows = append(rows, RowData{AttributeName: name, Values: values, ValueIndex: -1})
+		}
+	}
+
+	// Entries built without a server order show the rest by name
+	var rest []string
+	for name := range entry.Attributes {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		rows = append(rows, RowData{AttributeName: name, Values: entry.Attributes[name], ValueIndex: -1})
+	}
+	return rows
+}
+
+// oidColumnWidth returns the width of raw mode's OID column, which shows the
+// numeric OID of each attribute type the schema maps. It is zero outside raw
+// mode and while the schema maps none of the attributes.
+func (rv *RecordView) oidColumnWidth() int {
+	if rv.raw == nil {
+		return 0
+	}
+	width := 0
+	for _, row := range rv.renderedRows {
+		if at := rv.schema.AttributeType(row.AttributeName); at != nil {
+			width = max(width, lipgloss.Width(at.OID))
+		}
+	}
+	if width == 0 {
+		return 0
+	}
+	return max(width, len("OID"))
+}
+
+// renderRawStatus renders raw mode while the entry is being read or when reading failed
+func (rv *RecordView) renderRawStatus() string {
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+
+	var status string
+	if rv.raw.loading {
+		status = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Italic(true).
+			Render(iconLoading.String() + " Reading all attributes...")
+	} else {
+		status = editorErrorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, ldap.Explain(rv.raw.err).Error()))
+	}
+	return status + "\n\n" + helpStyle.Render("[R] leave raw mode")
+}
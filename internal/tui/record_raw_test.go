@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func newRawRecordView(t *testing.T) *RecordView {
+	rv := NewRecordView()
+	rv.SetSize(100, 30)
+	rv.SetClient(&ldap.Client{})
+	rv.SetHideEmpty(true)
+	rv.SetEntry(&ldap.Entry{
+		DN:         "uid=jdoe,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{"uid": {"jdoe"}, "cn": {"John"}},
+	})
+
+	_, cmd := rv.Update(keyRune('R'))
+	if cmd == nil || rv.raw == nil || !rv.raw.loading {
+		t.Fatal("Expected R to start reading the raw entry")
+	}
+	return rv
+}
+
+func TestRecordView_RawMode(t *testing.T) {
+	zone.NewGlobal()
+	rv := newRawRecordView(t)
+	if view := rv.View(); !strings.Contains(view, "RAW") || !strings.Contains(view, "Reading all attributes") {
+		t.Errorf("Expected the loading state to be labelled raw, got %q", view)
+	}
+
+	rv.Update(RawEntryLoadedMsg{
+		DN: "uid=jdoe,ou=people,dc=example,dc=com",
+		Entry: &ldap.Entry{
+			DN: "uid=jdoe,ou=people,dc=example,dc=com",
+			Attributes: map[string][]string{
+				"uid":         {"jdoe"},
+				"cn":          {"John"},
+				"description": {""},
+				"entryUUID":   {"5f1c"},
+			},
+			Order: []string{"uid", "entryUUID", "cn", "description"},
+		},
+	})
+
+	var names []string
+	for _, row := range rv.renderedRows {
+		names = append(names, row.AttributeName)
+	}
+	if got := strings.Join(names, ","); got != "dn,uid,entryUUID,cn,description" {
+		t.Errorf("Expected the DN then attributes in server order with empty ones kept, got %s", got)
+	}
+	if view := rv.View(); !strings.Contains(view, "RAW") || !strings.Contains(view, "entryUUID") {
+		t.Errorf("Expected raw rows to be shown, got %q", view)
+	}
+
+	if _, cmd := rv.Update(keyRune('e')); cmd == nil || rv.editor != nil {
+		t.Error("Expected editing to be refused in raw mode")
+	}
+
+	rv.Update(keyRune('R'))
+	if rv.raw != nil || len(rv.renderedRows) != 2 {
+		t.Errorf("Expected R to return to the curated view, got %d rows", len(rv.renderedRows))
+	}
+}
+
+func TestRecordView_RawModeIgnoresStaleResults(t *testing.T) {
+	rv := newRawRecordView(t)
+
+	rv.SetEntry(&ldap.Entry{DN: "uid=other,dc=example,dc=com", Attributes: map[string][]string{"uid": {"other"}}})
+	if rv.raw != nil {
+		t.Fatal("Expected selecting another entry to leave raw mode")
+	}
+
+	rv.Update(RawEntryLoadedMsg{DN: "uid=jdoe,ou=people,dc=example,dc=com", Err: errors.New("late")})
+	if rv.raw != nil || len(rv.renderedRows) != 1 {
+		t.Error("Expected a late raw result for the previous entry to be ignored")
+	}
+}
+
+func TestRecordView_RawModeError(t *testing.T) {
+	zone.NewGlobal()
+	rv := newRawRecordView(t)
+
+	rv.Update(RawEntryLoadedMsg{DN: "uid=jdoe,ou=people,dc=example,dc=com", Err: errors.New("search failed")})
+	if view := rv.View(); !strings.Contains(view, "search failed") || !strings.Contains(view, "leave raw mode") {
+		t.Errorf("Expected the error with a way back, got %q", view)
+	}
+}
+
+func TestRecordView_RawModeShowsOIDs(t *testing.T) {
+	zone.NewGlobal()
+	rv := newRawRecordView(t)
+	rv.SetSchema(&ldap.Schema{AttributeTypes: map[string]*ldap.AttributeType{
+		"cn": {OID: "2.5.4.3", Names: []string{"cn", "commonName"}},
+	}})
+	rv.Update(RawEntryLoadedMsg{
+		DN: "uid=jdoe,ou=people,dc=example,dc=com",
+		Entry: &ldap.Entry{
+			DN:         "uid=jdoe,ou=people,dc=example,dc=com",
+			Attributes: map[string][]string{"uid": {"jdoe"}, "cn": {"John"}},
+			Order:      []string{"uid", "cn"},
+		},
+	})
+
+	view := rv.View()
+	if !strings.Contains(view, "OID") || !strings.Contains(view, "2.5.4.3") {
+		t.Errorf("Expected an OID column with cn's OID in raw mode, got %q", view)
+	}
+
+	rv.Update(keyRune('R'))
+	if view := rv.View(); strings.Contains(view, "2.5.4.3") {
+		t.Errorf("Expected no OID column outside raw mode, got %q", view)
+	}
+}
@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// EntryReloadedMsg is sent when an entry has been re-read with explicit attribute names
+type EntryReloadedMsg struct {
+	Entry *ldap.Entry
+	Err   error
+}
+
+// attributeRetry holds the state of the prompt for re-reading an empty
+// entry with explicitly named attributes
+type attributeRetry struct {
+	input   textinput.Model
+	picker  *AttributePicker // Open while picking a name to add, nil otherwise
+	loading bool
+	err     error
+}
+
+// emptyEntryMessage explains why an entry has no attributes to display
+func emptyEntryMessage(entry *ldap.Entry) string {
+	switch entry.EmptyReason {
+	case ldap.EmptyReasonAccessDenied:
+		return "Access denied to this entry's attributes. The bound user may not read it; try binding as a user with read access."
+	case ldap.EmptyReasonReferral:
+		if len(entry.Referrals) == 0 {
+			return "This entry is a referral to another server."
+		}
+		return "This entry is a referral to " + strings.Join(entry.Referrals, ", ")
+	default:
+		return "Entry has no readable attributes. The server may hide attributes that aren't requested by name."
+	}
+}
+
+// startRetry opens the prompt for re-reading the entry with explicit attribute names
+func (rv *RecordView) startRetry() tea.Cmd {
+	if rv.entry == nil {
+		return SendError(fmt.Errorf("no record selected"))
+	}
+	if len(rv.renderedRows) > 0 {
+		return nil
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "cn, mail, objectClass"
+	ti.CharLimit = 0
+	ti.Width = 40
+	ti.Focus()
+	rv.retry = &attributeRetry{input: ti}
+	return textinput.Blink
+}
+
+// handleRetryKey handles key presses while the retry prompt is open
+func (rv *RecordView) handleRetryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if rv.retry.loading {
+		return rv, nil
+	}
+	if picker := rv.retry.picker; picker != nil {
+		done, name, cmd := picker.Update(msg)
+		if done {
+			rv.retry.picker = nil
+			if name != "" {
+				rv.retry.input.SetValue(replaceLastAttribute(rv.retry.input.Value(), name))
+				rv.retry.input.CursorEnd()
+			}
+		}
+		return rv, cmd
+	}
+
+	switch msg.String() {
+	case "esc":
+		rv.retry = nil
+		return rv, nil
+	case "ctrl+t":
+		rv.retry.picker = NewAttributePicker(rv.schema.AttributeNames(), rv.schema)
+		return rv, textinput.Blink
+	case "enter":
+		return rv, rv.submitRetry()
+	}
+
+	var cmd tea.Cmd
+	rv.retry.input, cmd = rv.retry.input.Update(msg)
+	return rv, cmd
+}
+
+// submitRetry re-reads the entry with the attribute names typed into the prompt
+func (rv *RecordView) submitRetry() tea.Cmd {
+	attributes := parseAttributeList(rv.retry.input.Value())
+	if len(attributes) == 0 {
+		rv.retry.err = fmt.Errorf("enter at least one attribute name")
+		return nil
+	}
+	if rv.client == nil {
+		rv.retry.err = fmt.Errorf("not connected to an LDAP server")
+		return nil
+	}
+
+	rv.retry.loading = true
+	rv.retry.err = nil
+	client := rv.client
+	dn := rv.entry.DN
+	return func() tea.Msg {
+		entry, err := client.GetEntryAttributes(dn, attributes)
+		return EntryReloadedMsg{Entry: entry, Err: err}
+	}
+}
+
+// handleEntryReloaded applies the result of a retry
+func (rv *RecordView) handleEntryReloaded(msg EntryReloadedMsg) {
+	if rv.retry == nil {
+		return
+	}
+	if msg.Err != nil {
+		rv.retry.loading = false
+		rv.retry.err = msg.Err
+		return
+	}
+	if rv.entry == nil || !strings.EqualFold(msg.Entry.DN, rv.entry.DN) {
+		return
+	}
+
+	rv.retry = nil
+	rv.SetEntry(msg.Entry)
+}
+
+// parseAttributeList splits a comma or space separated list of attribute names
+func parseAttributeList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// renderEmptyEntry renders the explanation for an entry without attributes,
+// with the retry prompt when it is open
+func (rv *RecordView) renderEmptyEntry() string {
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+
+	sections := []string{emptyEntryMessage(rv.entry)}
+
+	if rv.retry == nil {
+		if rv.entry.EmptyReason != ldap.EmptyReasonReferral {
+			sections = append(sections, "", helpStyle.Render("[N] retry with explicit attribute names"))
+		}
+		return strings.Join(sections, "\n")
+	}
+
+	sections = append(sections, "", editorLabelStyle.Render("Attributes to read:"), rv.retry.input.View())
+	if rv.retry.picker != nil {
+		return strings.Join(append(sections, "", rv.retry.picker.View()), "\n")
+	}
+	if rv.retry.loading {
+		sections = append(sections, "", lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Italic(true).
+			Render(iconLoading.String()+" Reading entry..."))
+	} else if rv.retry.err != nil {
+		sections = append(sections, "", editorErrorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, ldap.Explain(rv.retry.err).Error())))
+	}
+	sections = append(sections, "", helpStyle.Render("[Enter] read • [Ctrl+T] pick attribute • [Esc] cancel"))
+	return strings.Join(sections, "\n")
+}
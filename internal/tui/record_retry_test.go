@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func newEmptyRecordView(reason ldap.EmptyReason, referrals ...string) *RecordView {
+	rv := NewRecordView()
+	rv.SetSize(100, 30)
+	rv.SetEntry(&ldap.Entry{
+		DN:          "uid=jdoe,ou=people,dc=example,dc=com",
+		Attributes:  map[string][]string{},
+		EmptyReason: reason,
+		Referrals:   referrals,
+	})
+	return rv
+}
+
+func TestEmptyEntryMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		reason    ldap.EmptyReason
+		referrals []string
+		want      string
+		retry     bool
+	}{
+		{"no readable attributes", ldap.EmptyReasonNoReadableAttributes, nil, "no readable attributes", true},
+		{"unknown reason", ldap.EmptyReasonNone, nil, "no readable attributes", true},
+		{"access denied", ldap.EmptyReasonAccessDenied, nil, "Access denied", true},
+		{"referral", ldap.EmptyReasonReferral, []string{"ldap://other.example.com/dc=example,dc=com"}, "referral to ldap://other.example.com/dc=example,dc=com", false},
+		{"referral without urls", ldap.EmptyReasonReferral, nil, "referral to another server", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := newEmptyRecordView(tt.reason, tt.referrals...).View()
+			if !strings.Contains(view, tt.want) {
+				t.Errorf("Expected view to contain %q, got %q", tt.want, view)
+			}
+			if strings.Contains(view, "No attributes to display") {
+				t.Error("Expected the generic message to be replaced")
+			}
+			if got := strings.Contains(view, "retry with explicit attribute names"); got != tt.retry {
+				t.Errorf("Expected retry hint shown = %v", tt.retry)
+			}
+		})
+	}
+}
+
+func TestRecordView_RetryWithExplicitAttributes(t *testing.T) {
+	rv := newEmptyRecordView(ldap.EmptyReasonNoReadableAttributes)
+
+	rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if rv.retry == nil || !rv.IsEditing() {
+		t.Fatal("Expected 'r' to open the retry prompt")
+	}
+
+	// Submitting without names is rejected
+	rv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if rv.retry.err == nil || !strings.Contains(rv.View(), "at least one attribute") {
+		t.Error("Expected an error for an empty attribute list")
+	}
+
+	rv.retry.input.SetValue("cn, mail")
+	rv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if rv.retry.err == nil || !strings.Contains(rv.retry.err.Error(), "not connected") {
+		t.Errorf("Expected not connected error, got %v", rv.retry.err)
+	}
+
+	rv.SetClient(&ldap.Client{})
+	if cmd := rv.submitRetry(); cmd == nil || !rv.retry.loading {
+		t.Fatal("Expected a re-read to start")
+	}
+	if !strings.Contains(rv.View(), "Reading entry") {
+		t.Error("Expected a loading indicator")
+	}
+
+	rv.Update(EntryReloadedMsg{Err: errors.New("busy")})
+	if rv.retry.loading || !strings.Contains(rv.View(), "busy") {
+		t.Error("Expected the error to be shown in the prompt")
+	}
+
+	rv.Update(EntryReloadedMsg{Entry: &ldap.Entry{
+		DN:         "uid=jdoe,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{"cn": {"John Doe"}, "mail": {"jdoe@example.com"}},
+	}})
+	if rv.retry != nil {
+		t.Error("Expected the prompt to close after a successful re-read")
+	}
+	if len(rv.renderedRows) != 2 {
+		t.Errorf("Expected 2 attribute rows, got %d", len(rv.renderedRows))
+	}
+}
+
+func TestRecordView_RetryCancel(t *testing.T) {
+	rv := newEmptyRecordView(ldap.EmptyReasonAccessDenied)
+
+	rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	rv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if rv.retry != nil {
+		t.Error("Expected Esc to close the retry prompt")
+	}
+}
+
+func TestRecordView_RetryIgnoredWithAttributes(t *testing.T) {
+	rv := NewRecordView()
+	rv.SetSize(100, 30)
+	rv.SetEntry(&ldap.Entry{DN: "cn=test", Attributes: map[string][]string{"cn": {"test"}}})
+
+	rv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if rv.retry != nil {
+		t.Error("Expected no retry prompt for an entry with attributes")
+	}
+}
+
+func TestParseAttributeList(t *testing.T) {
+	got := parseAttributeList(" cn, mail  sn,,")
+	want := []string{"cn", "mail", "sn"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
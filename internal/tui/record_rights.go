@@ -0,0 +1,263 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// EffectiveRightsLoadedMsg is sent when the effective rights on an entry have been read
+type EffectiveRightsLoadedMsg struct {
+	DN     string
+	Rights *ldap.EffectiveRights
+	Err    error
+}
+
+// rightsRow is one attribute of the effective rights panel
+type rightsRow struct {
+	Attribute string
+	Rights    string
+}
+
+// effectiveRightsPanel holds the state of the record view's access rights
+// probe. The identity input is checked as the bound user while empty.
+type effectiveRightsPanel struct {
+	dn      string
+	input   textinput.Model
+	rights  *ldap.EffectiveRights
+	rows    []rightsRow
+	cursor  int
+	loading bool
+	err     error
+}
+
+// buildRightsRows lists the entry's attributes and those rights were
+// reported for, with the rights of each. Attributes the server reported
+// nothing for have no rights.
+func buildRightsRows(entry *ldap.Entry, rights *ldap.EffectiveRights) []rightsRow {
+	seen := make(map[string]bool)
+	var rows []rightsRow
+	add := func(name string) {
+		key := strings.ToLower(name)
+		if seen[key] || strings.EqualFold(name, ldap.EntryLevelRightsAttribute) || strings.EqualFold(name, ldap.AttributeLevelRightsAttribute) {
+			return
+		}
+		seen[key] = true
+		rows = append(rows, rightsRow{Attribute: name, Rights: rights.Rights(name)})
+	}
+
+	if entry != nil {
+		names := make([]string, 0, len(entry.Attributes))
+		for name := range entry.Attributes {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			return strings.ToLower(names[i]) < strings.ToLower(names[j])
+		})
+		for _, name := range names {
+			add(name)
+		}
+	}
+	for _, name := range rights.Names() {
+		add(name)
+	}
+	return rows
+}
+
+// IsRightsOpen returns true while the effective rights panel is shown
+func (rv *RecordView) IsRightsOpen() bool {
+	return rv.rights != nil
+}
+
+// openRights shows the effective rights panel and checks the bound user's
+// rights on the current entry. The panel is only offered when the root DSE
+// advertises the get effective rights control.
+func (rv *RecordView) openRights() tea.Cmd {
+	if rv.entry == nil {
+		return SendError(fmt.Errorf("no record selected"))
+	}
+	if rv.client == nil {
+		return SendError(fmt.Errorf("not connected to an LDAP server"))
+	}
+	if !rv.client.SupportsEffectiveRights() {
+		if rv.client.Capabilities() == nil {
+			return SendError(fmt.Errorf("the root DSE couldn't be read to check for get effective rights support"))
+		}
+		return SendError(ldap.ErrEffectiveRightsUnsupported)
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "bound user, or a DN or dn:/u: authzId"
+	ti.CharLimit = 0
+	ti.Width = 50
+	ti.Focus()
+	rv.rights = &effectiveRightsPanel{dn: rv.entry.DN, input: ti}
+	return tea.Batch(textinput.Blink, rv.checkRights())
+}
+
+// checkRights reads the effective rights of the identity typed into the
+// panel, or of the bound user while it is empty
+func (rv *RecordView) checkRights() tea.Cmd {
+	rv.rights.loading = true
+	rv.rights.err = nil
+	client := rv.client
+	dn := rv.rights.dn
+	authzID := strings.TrimSpace(rv.rights.input.Value())
+	var attributes []string
+	for name := range rv.entry.Attributes {
+		attributes = append(attributes, name)
+	}
+	sort.Strings(attributes)
+	return func() tea.Msg {
+		rights, err := client.GetEffectiveRights(dn, authzID, attributes)
+		return EffectiveRightsLoadedMsg{DN: dn, Rights: rights, Err: err}
+	}
+}
+
+// loadBoundRights reads the bound user's effective rights on the entry in
+// the background. The client keeps them, so edits they don't allow are
+// refused before the editor opens.
+func (rv *RecordView) loadBoundRights() tea.Cmd {
+	if rv.entry == nil || !rv.client.SupportsEffectiveRights() || rv.client.CachedRights(rv.entry.DN) != nil {
+		return nil
+	}
+	client := rv.client
+	dn := rv.entry.DN
+	var attributes []string
+	for name := range rv.entry.Attributes {
+		attributes = append(attributes, name)
+	}
+	sort.Strings(attributes)
+	return func() tea.Msg {
+		rights, err := client.GetEffectiveRights(dn, "", attributes)
+		return EffectiveRightsLoadedMsg{DN: dn, Rights: rights, Err: err}
+	}
+}
+
+// refuseWithoutWriteRights refuses changing attribute when the bound user's
+// effective rights on the entry are known and don't include writing it
+func (rv *RecordView) refuseWithoutWriteRights(attribute string) tea.Cmd {
+	rights := rv.client.CachedRights(rv.entry.DN)
+	if rights.MayWrite(attribute) {
+		return nil
+	}
+	return SendError(fmt.Errorf("the bound user may not write %s on this entry (rights: %s)", attribute, ldap.DescribeAttributeRights(rights.Rights(attribute))))
+}
+
+// handleRightsLoaded shows the result of a rights check in the panel
+func (rv *RecordView) handleRightsLoaded(msg EffectiveRightsLoadedMsg) {
+	if rv.rights == nil || rv.rights.dn != msg.DN {
+		return
+	}
+	rv.rights.loading = false
+	if msg.Err != nil {
+		rv.rights.err = msg.Err
+		return
+	}
+	rv.rights.rights = msg.Rights
+	rv.rights.rows = buildRightsRows(rv.entry, msg.Rights)
+	rv.rights.cursor = min(rv.rights.cursor, max(len(rv.rights.rows)-1, 0))
+}
+
+// handleRightsKey handles key presses while the effective rights panel is open
+func (rv *RecordView) handleRightsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		rv.rights = nil
+		return rv, nil
+	case "enter":
+		if rv.rights.loading {
+			return rv, nil
+		}
+		return rv, rv.checkRights()
+	case "up":
+		if rv.rights.cursor > 0 {
+			rv.rights.cursor--
+		}
+		return rv, nil
+	case "down":
+		if rv.rights.cursor < len(rv.rights.rows)-1 {
+			rv.rights.cursor++
+		}
+		return rv, nil
+	}
+
+	var cmd tea.Cmd
+	rv.rights.input, cmd = rv.rights.input.Update(msg)
+	return rv, cmd
+}
+
+// rightsVisibleRows returns how many attribute rows of the panel fit the view
+func (rv *RecordView) rightsVisibleRows() int {
+	if rv.container == nil {
+		return 10
+	}
+	_, contentHeight := rv.container.GetContentDimensions()
+	// DN header, title, identity, entry rights, spacing and help
+	return max(contentHeight-10, 3)
+}
+
+// renderRights renders the effective rights panel
+func (rv *RecordView) renderRights() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	describeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+
+	panel := rv.rights
+	sections := []string{
+		titleStyle.Render("Effective rights"),
+		"",
+		editorLabelStyle.Render("As:") + " " + panel.input.View(),
+		"",
+	}
+
+	switch {
+	case panel.loading:
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Italic(true).
+			Render(iconLoading.String()+" Checking rights..."))
+	case panel.err != nil:
+		sections = append(sections, editorErrorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, ldap.Explain(panel.err).Error())))
+	case panel.rights != nil:
+		sections = append(sections, editorLabelStyle.Render(fmt.Sprintf("%-20s", "Entry"))+" "+
+			fmt.Sprintf("%-8s", panel.rights.Entry)+" "+describeStyle.Render(ldap.DescribeEntryRights(panel.rights.Entry))+" "+
+			describeStyle.Render("("+panel.rights.AuthzID+")"))
+		if len(panel.rows) == 0 {
+			sections = append(sections, "No attribute rights reported")
+		}
+
+		visible := rv.rightsVisibleRows()
+		start := 0
+		if panel.cursor >= visible {
+			start = panel.cursor - visible + 1
+		}
+		end := min(start+visible, len(panel.rows))
+		for i := start; i < end; i++ {
+			row := panel.rows[i]
+			line := editorLabelStyle.Render(fmt.Sprintf("%-20s", row.Attribute)) + " " +
+				fmt.Sprintf("%-8s", row.Rights) + " " + describeStyle.Render(ldap.DescribeAttributeRights(row.Rights))
+			if i == panel.cursor {
+				sections = append(sections, editorFocusStyle.Render("▶ ")+line)
+			} else {
+				sections = append(sections, "  "+line)
+			}
+		}
+		if len(panel.rows) > visible {
+			sections = append(sections, describeStyle.Render(fmt.Sprintf("  %d-%d of %d attributes", start+1, end, len(panel.rows))))
+		}
+	}
+
+	sections = append(sections, "", helpStyle.Render("[Enter] check identity • [↑↓] scroll • [Esc] close"))
+	return strings.Join(sections, "\n")
+}
@@ -0,0 +1,169 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+func TestBuildRightsRows(t *testing.T) {
+	entry := &ldap.Entry{DN: "uid=alice,dc=example,dc=com", Attributes: map[string][]string{
+		"uid":                  {"alice"},
+		"cn":                   {"Alice"},
+		"attributeLevelRights": {"cn:rscwo"},
+	}}
+	rights := &ldap.EffectiveRights{Attributes: map[string]string{"CN": "rscwo", "userPassword": "wo"}}
+
+	rows := buildRightsRows(entry, rights)
+	expected := []rightsRow{{"cn", "rscwo"}, {"uid", ""}, {"userPassword", "wo"}}
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, rows)
+	}
+	for i, row := range rows {
+		if row != expected[i] {
+			t.Errorf("Row %d: expected %v, got %v", i, expected[i], row)
+		}
+	}
+}
+
+func TestRecordView_OpenRightsUnsupported(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "uid=alice,dc=example,dc=com", Attributes: map[string][]string{"uid": {"alice"}}},
+	)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	rv := NewRecordView()
+	rv.SetSize(120, 40)
+	rv.SetClient(client)
+	rv.SetEntry(&ldap.Entry{DN: "uid=alice,dc=example,dc=com", Attributes: map[string][]string{"uid": {"alice"}}})
+
+	_, cmd := rv.Update(keyRune('g'))
+	if rv.IsRightsOpen() {
+		t.Fatal("Expected the panel to stay closed before support is known")
+	}
+	if msg, ok := cmd().(ErrorMsg); !ok || !strings.Contains(msg.Err.Error(), "root DSE") {
+		t.Errorf("Expected an error about the root DSE, got %#v", msg)
+	}
+}
+
+func TestRecordView_EffectiveRights(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "", Attributes: map[string][]string{"supportedControl": {ldap.OIDGetEffectiveRights}}},
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "uid=alice,dc=example,dc=com", Attributes: map[string][]string{
+			"uid":                  {"alice"},
+			"entryLevelRights":     {"vadn"},
+			"attributeLevelRights": {"uid:rsc, userPassword:wo"},
+		}},
+	)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	if _, err := client.LoadCapabilities(); err != nil {
+		t.Fatal(err)
+	}
+
+	rv := NewRecordView()
+	rv.SetSize(120, 40)
+	rv.SetClient(client)
+	rv.SetEntry(&ldap.Entry{DN: "uid=alice,dc=example,dc=com", Attributes: map[string][]string{"uid": {"alice"}}})
+
+	_, cmd := rv.Update(keyRune('g'))
+	if !rv.IsRightsOpen() || !rv.IsEditing() {
+		t.Fatal("Expected the rights panel to open as an input mode")
+	}
+	var loaded EffectiveRightsLoadedMsg
+	for _, msg := range runCmd(cmd) {
+		if m, ok := msg.(EffectiveRightsLoadedMsg); ok {
+			loaded = m
+		}
+	}
+	if loaded.Err != nil || loaded.Rights == nil {
+		t.Fatalf("Expected rights to load, got %+v", loaded)
+	}
+	rv.Update(loaded)
+
+	view := rv.View()
+	for _, want := range []string{"Effective rights", "view, add, delete, rename", "read, search, compare", "userPassword", "write, delete", "dn:"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected the panel to show %q, got:\n%s", want, view)
+		}
+	}
+
+	rv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if rv.IsRightsOpen() {
+		t.Error("Expected Esc to close the panel")
+	}
+}
+
+func TestEffectiveRightsGateEditsAndDeletes(t *testing.T) {
+	const dn = "uid=alice,dc=example,dc=com"
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "", Attributes: map[string][]string{"supportedControl": {ldap.OIDGetEffectiveRights}}},
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: dn, Attributes: map[string][]string{
+			"uid":                  {"alice"},
+			"mail":                 {"alice@example.com"},
+			"entryLevelRights":     {"v"},
+			"attributeLevelRights": {"uid:rsc, mail:rscwo"},
+		}},
+	)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	if _, err := client.LoadCapabilities(); err != nil {
+		t.Fatal(err)
+	}
+
+	rv := NewRecordView()
+	rv.SetSize(120, 40)
+	rv.SetClient(client)
+	rv.SetEntry(&ldap.Entry{DN: dn, Attributes: map[string][]string{"uid": {"alice"}, "mail": {"alice@example.com"}}})
+	runCmd(rv.loadBoundRights())
+	if client.CachedRights(dn) == nil {
+		t.Fatal("Expected the bound user's rights to be kept on the client")
+	}
+	if rv.loadBoundRights() != nil {
+		t.Error("Expected rights already read not to be read again")
+	}
+
+	// Rows are sorted: mail, then uid
+	rv.table.SetCursor(1)
+	if msg, ok := rv.startEdit()().(ErrorMsg); !ok || !strings.Contains(msg.Err.Error(), "may not write uid") {
+		t.Errorf("Expected editing uid to be refused, got %#v", msg)
+	}
+	if rv.editor != nil {
+		t.Error("Expected no editor for an attribute the user can't write")
+	}
+	rv.table.SetCursor(0)
+	if cmd := rv.startEdit(); cmd != nil || rv.editor == nil {
+		t.Error("Expected mail to be editable")
+	}
+
+	tv := NewTreeView(client)
+	tv.SetSize(80, 20)
+	tv.roots = []*ldap.TreeNode{{DN: "dc=example,dc=com", Name: "dc=example,dc=com", IsLoaded: true,
+		Children: []*ldap.TreeNode{{DN: dn, Name: "uid=alice", IsLoaded: true}},
+	}}
+	tv.rebuildFlattenedTree()
+	tv.cursor = 1
+	_, cmd := tv.Update(keyRune('D'))
+	if msg, ok := cmd().(ErrorMsg); !ok || !strings.Contains(msg.Err.Error(), "may not delete") {
+		t.Errorf("Expected the delete to be refused from the cached rights, got %#v", msg)
+	}
+	if tv.deleteConfirm != nil {
+		t.Error("Expected no delete prompt")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/ericschmar/moribito/internal/ldap"
 	zone "github.com/lrstanley/bubblezone"
 )
@@ -168,6 +169,91 @@ func TestRecordView_LineWrapping(t *testing.T) {
 	}
 }
 
+func TestRecordView_MultilineAttributes(t *testing.T) {
+	zone.NewGlobal()
+	longValue := "This is a very long value that should wrap across multiple lines when the column width is limited"
+	entry := &ldap.Entry{
+		DN: "cn=wrap test,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"description":   {longValue},
+			"info":          {longValue},
+			"postalAddress": {"1 Main St\nSpringfield"},
+		},
+	}
+
+	rv := NewRecordView()
+	rv.SetSize(60, 20)
+	rv.SetMultilineAttributes([]string{"Description", "postaladdress"})
+	rv.SetEntry(entry)
+
+	view := ansi.Strip(rv.renderTable())
+	if !strings.Contains(view, "This is a very") || !strings.Contains(view, "limited") {
+		t.Errorf("Expected the configured attribute to wrap in full, got:\n%s", view)
+	}
+	if !strings.Contains(view, "1 Main St") || !strings.Contains(view, "Springfield") {
+		t.Errorf("Expected line breaks in a configured value to be kept, got:\n%s", view)
+	}
+	_, valueWidth := rv.columnWidths(60)
+	for _, line := range strings.Split(view, "\n") {
+		if width := ansi.StringWidth(line); width > 60 {
+			t.Errorf("Expected wrapped lines to fit the width, got %d: %q", width, line)
+		}
+	}
+	if lines := rv.valueLines(rv.renderedRows[0], valueWidth); len(lines) < 2 {
+		t.Errorf("Expected the configured value on several lines, got %q", lines)
+	}
+
+	// Attributes that aren't configured are still cut off
+	info := rv.valueLines(rv.renderedRows[1], valueWidth)
+	if len(info) != 1 || !strings.HasSuffix(info[0], "...") {
+		t.Errorf("Expected the long value of a plain attribute truncated, got %q", info)
+	}
+}
+
+func TestRecordView_MultilineScrolling(t *testing.T) {
+	zone.NewGlobal()
+	entry := &ldap.Entry{
+		DN:         "cn=wrap test,dc=example,dc=com",
+		Attributes: map[string][]string{"attr00": {strings.Repeat("word ", 60)}},
+	}
+	for i := 1; i <= 5; i++ {
+		entry.Attributes[fmt.Sprintf("attr%02d", i)] = []string{fmt.Sprintf("value%02d", i)}
+	}
+
+	rv := NewRecordView()
+	rv.SetSize(60, 10)
+	rv.SetMultilineAttributes([]string{"attr00"})
+	rv.SetEntry(entry)
+
+	// The wrapped first value fills the page, so the next row needs a scroll
+	rv.table.SetCursor(1)
+	rv.adjustViewport()
+	if rv.viewport != 1 {
+		t.Errorf("Expected the viewport to move past the wrapped row, got %d", rv.viewport)
+	}
+	view := ansi.Strip(rv.renderTable())
+	if !strings.Contains(view, "value01") || !strings.Contains(view, "Showing 2-6 of 6 attributes") {
+		t.Errorf("Expected the rows after the wrapped one, got:\n%s", view)
+	}
+
+	// A row taller than the page is shown cut off rather than not at all
+	rv.table.SetCursor(0)
+	rv.adjustViewport()
+	view = ansi.Strip(rv.renderTable())
+	if !strings.Contains(view, "attr00") || !strings.Contains(view, "Showing 1-1 of 6 attributes") {
+		t.Errorf("Expected the wrapped row alone on the page, got:\n%s", view)
+	}
+	lines := 0
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "word") {
+			lines++
+		}
+	}
+	if lines != 7 {
+		t.Errorf("Expected the wrapped row cut off at the page height, got %d lines:\n%s", lines, view)
+	}
+}
+
 func TestRecordView_CopyFunctionality(t *testing.T) {
 	// Create a test entry
 	entry := &ldap.Entry{
@@ -213,6 +299,34 @@ func TestRecordView_CopyFunctionality(t *testing.T) {
 	}
 }
 
+func TestRecordView_CopyAttributeNameCase(t *testing.T) {
+	rv := NewRecordView()
+	rv.SetSize(80, 20)
+	rv.SetEntry(&ldap.Entry{
+		DN:         "uid=jdoe,dc=example,dc=com",
+		Attributes: map[string][]string{"mail": {"jdoe@example.com"}},
+	})
+
+	// The name shown differs in case and spacing from the one stored, and
+	// changes go to the attribute under the name the server returned
+	rv.renderedRows[0].AttributeName = " Mail"
+	rv.startAddValue()
+	if rv.editingAttr != "mail" {
+		t.Errorf("Expected the stored name to be modified, got %q", rv.editingAttr)
+	}
+	rv.cancelEdit()
+
+	if err := clipboard.WriteAll(""); err != nil {
+		t.Skipf("Clipboard not available in test environment: %v", err)
+	}
+	if _, ok := rv.copyCurrentValue()().(StatusMsg); !ok {
+		t.Fatal("Expected the value to be found under the stored name")
+	}
+	if copied, _ := clipboard.ReadAll(); copied != "jdoe@example.com" {
+		t.Errorf("Expected the value copied, got %q", copied)
+	}
+}
+
 func TestRecordView_CopyKeyBinding(t *testing.T) {
 	// Create a test entry
 	entry := &ldap.Entry{
@@ -519,3 +633,101 @@ func TestRecordView_ViewportScrollingDemo(t *testing.T) {
 		t.Error("Viewport should not be negative")
 	}
 }
+
+func TestRecordView_NormalizeAttributeNames(t *testing.T) {
+	zone.NewGlobal()
+	rv := NewRecordView()
+	rv.SetSize(80, 30)
+	rv.SetSchema(&ldap.Schema{AttributeTypes: map[string]*ldap.AttributeType{
+		"objectclass":     {OID: "2.5.4.0", Names: []string{"objectClass"}},
+		"telephonenumber": {OID: "2.5.4.20", Names: []string{"telephoneNumber"}},
+		"cn":              {OID: "2.5.4.3", Names: []string{"cn", "commonName"}},
+		"commonname":      {OID: "2.5.4.3", Names: []string{"cn", "commonName"}},
+	}})
+	rv.SetEntry(&ldap.Entry{
+		DN: "uid=jdoe,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"OBJECTCLASS":     {"person"},
+			"telephonenumber": {"555-0100"},
+			"CN":              {"John Doe"},
+			"customAttr":      {"kept"},
+		},
+	})
+
+	labels := func() []string {
+		var names []string
+		for _, row := range rv.table.Rows() {
+			names = append(names, row[0])
+		}
+		return names
+	}
+	if got := fmt.Sprint(labels()); got != "[CN OBJECTCLASS customAttr telephonenumber]" {
+		t.Errorf("Expected names as returned by default, got %s", got)
+	}
+
+	rv.SetNormalizeAttributeNames(true)
+	if got := fmt.Sprint(labels()); got != "[cn customAttr objectClass telephoneNumber]" {
+		t.Errorf("Expected schema spelling sorted by label, got %s", got)
+	}
+	if rv.renderedRows[0].AttributeName != "CN" {
+		t.Errorf("Expected the row to keep the returned name for edits, got %q", rv.renderedRows[0].AttributeName)
+	}
+	if !strings.Contains(rv.renderTable(), "telephoneNumber") {
+		t.Error("Expected the rendered table to show the schema spelling")
+	}
+	rv.table.SetCursor(0)
+	if msg, ok := rv.copyCurrentValue()().(ErrorMsg); ok && strings.Contains(msg.Err.Error(), "attribute not found") {
+		t.Error("Expected copying to find the attribute by its returned name")
+	}
+
+	// Without a schema the names are shown as returned
+	rv.SetSchema(nil)
+	if got := fmt.Sprint(labels()); got != "[CN OBJECTCLASS customAttr telephonenumber]" {
+		t.Errorf("Expected names as returned without a schema, got %s", got)
+	}
+}
+
+func TestRecordView_ShowAttributeOIDs(t *testing.T) {
+	zone.NewGlobal()
+	rv := NewRecordView()
+	rv.SetSize(120, 30)
+	rv.SetEntry(&ldap.Entry{
+		DN: "uid=jdoe,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"mail":       {"jdoe@example.com"},
+			"customAttr": {"kept"},
+		},
+	})
+
+	labels := func() []string {
+		var names []string
+		for _, row := range rv.table.Rows() {
+			names = append(names, row[0])
+		}
+		return names
+	}
+
+	// Without a schema the toggle leaves the names alone
+	rv.Update(keyRune('s'))
+	if !rv.showOIDs {
+		t.Fatal("Expected s to turn on the annotations")
+	}
+	if got := fmt.Sprint(labels()); got != "[customAttr mail]" {
+		t.Errorf("Expected plain names without a schema, got %s", got)
+	}
+
+	rv.SetSchema(&ldap.Schema{AttributeTypes: map[string]*ldap.AttributeType{
+		"mail": {OID: "0.9.2342.19200300.100.1.3", Names: []string{"mail"}, Syntax: ldap.SyntaxIA5String},
+	}})
+	if got := fmt.Sprint(labels()); got != "[customAttr mail (0.9.2342.19200300.100.1.3, IA5String)]" {
+		t.Errorf("Expected the schema OID and syntax, got %s", got)
+	}
+	if !strings.Contains(rv.renderTable(), "0.9.2342.19200300.100.1.3") {
+		t.Error("Expected the rendered table to show the OID")
+	}
+
+	rv.Update(keyRune('s'))
+	if got := fmt.Sprint(labels()); got != "[customAttr mail]" {
+		t.Errorf("Expected plain names once toggled off, got %s", got)
+	}
+}
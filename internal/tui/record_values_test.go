@@ -0,0 +1,486 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func newMultiValueRecordView() *RecordView {
+	rv := NewRecordView()
+	rv.SetSize(80, 30)
+	rv.SetEntry(&ldap.Entry{
+		DN: "cn=admins,ou=groups,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"cn": {"admins"},
+			"member": {
+				"uid=alice,ou=people,dc=example,dc=com",
+				"uid=bob,ou=people,dc=example,dc=com",
+				"uid=carol,ou=people,dc=example,dc=com",
+			},
+		},
+	})
+	return rv
+}
+
+func keyRune(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestRecordView_ExpandMultiValuedAttribute(t *testing.T) {
+	rv := newMultiValueRecordView()
+
+	// Rows are sorted: cn, member
+	rv.table.SetCursor(1)
+	rv.Update(keyRune(' '))
+
+	if len(rv.renderedRows) != 5 {
+		t.Fatalf("Expected 2 attribute rows plus 3 value rows, got %d", len(rv.renderedRows))
+	}
+	for i, row := range rv.renderedRows[2:] {
+		if !row.IsValueRow() || row.ValueIndex != i || row.AttributeName != "member" {
+			t.Errorf("Row %d: expected member value sub-row %d, got %+v", i+2, i, row)
+		}
+	}
+	if rv.table.Cursor() != 1 {
+		t.Errorf("Expected cursor to stay on the attribute row, got %d", rv.table.Cursor())
+	}
+	if !strings.Contains(rv.View(), "uid=bob,ou=people,dc=example,dc=com") {
+		t.Error("Expected each value to be rendered on its own row")
+	}
+
+	// Collapsing from a sub-row returns the cursor to the attribute row
+	rv.table.SetCursor(3)
+	rv.Update(keyRune(' '))
+	if len(rv.renderedRows) != 2 {
+		t.Errorf("Expected collapse to remove value rows, got %d rows", len(rv.renderedRows))
+	}
+	if rv.table.Cursor() != 1 {
+		t.Errorf("Expected cursor on member after collapse, got %d", rv.table.Cursor())
+	}
+}
+
+func TestRecordView_ExpandIgnoresSingleValuedAttribute(t *testing.T) {
+	rv := newMultiValueRecordView()
+
+	rv.table.SetCursor(0)
+	rv.Update(keyRune(' '))
+	if len(rv.renderedRows) != 2 {
+		t.Errorf("Expected single-valued attribute not to expand, got %d rows", len(rv.renderedRows))
+	}
+}
+
+func TestRecordView_ExpansionSurvivesReload(t *testing.T) {
+	rv := newMultiValueRecordView()
+	rv.table.SetCursor(1)
+	rv.Update(keyRune(' '))
+
+	// Re-reading the same entry after a modification keeps it expanded
+	rv.Update(EntryModifiedMsg{Entry: &ldap.Entry{
+		DN: "cn=admins,ou=groups,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"member": {"uid=alice,ou=people,dc=example,dc=com", "uid=bob,ou=people,dc=example,dc=com"},
+		},
+	}, Attribute: "member"})
+	if len(rv.renderedRows) != 3 {
+		t.Errorf("Expected member to stay expanded after reload, got %d rows", len(rv.renderedRows))
+	}
+
+	// A different entry starts collapsed
+	rv.SetEntry(&ldap.Entry{
+		DN:         "cn=users,ou=groups,dc=example,dc=com",
+		Attributes: map[string][]string{"member": {"a", "b"}},
+	})
+	if len(rv.renderedRows) != 1 {
+		t.Errorf("Expected new entry to start collapsed, got %d rows", len(rv.renderedRows))
+	}
+}
+
+func TestRecordView_RemoveValueRequiresSelection(t *testing.T) {
+	rv := newMultiValueRecordView()
+
+	// Removing from a collapsed multi-valued attribute is refused
+	rv.table.SetCursor(1)
+	_, cmd := rv.Update(keyRune('-'))
+	if rv.IsEditing() {
+		t.Error("Expected no removal prompt for a collapsed multi-valued attribute")
+	}
+	if cmd == nil {
+		t.Fatal("Expected an error command")
+	}
+	if _, ok := cmd().(ErrorMsg); !ok {
+		t.Error("Expected ErrorMsg when no single value is selected")
+	}
+}
+
+func TestRecordView_RemoveValueConfirm(t *testing.T) {
+	rv := newMultiValueRecordView()
+	rv.table.SetCursor(1)
+	rv.Update(keyRune(' '))
+
+	rv.table.SetCursor(3) // uid=bob
+	rv.Update(keyRune('-'))
+	if !rv.IsEditing() || rv.pendingRemoval == nil {
+		t.Fatal("Expected removal confirmation prompt")
+	}
+	view := rv.View()
+	if !strings.Contains(view, "Remove a value from member?") || !strings.Contains(view, "uid=bob") {
+		t.Error("Expected prompt to name the attribute and value")
+	}
+
+	// Declining leaves the entry untouched
+	rv.Update(keyRune('n'))
+	if rv.IsEditing() {
+		t.Error("Expected 'n' to cancel the removal")
+	}
+
+	// Confirming without a client reports an error instead of modifying
+	rv.Update(keyRune('-'))
+	_, cmd := rv.Update(keyRune('y'))
+	if rv.IsEditing() {
+		t.Error("Expected prompt to close when there is no client")
+	}
+	if cmd == nil {
+		t.Fatal("Expected an error command")
+	}
+	if _, ok := cmd().(ErrorMsg); !ok {
+		t.Error("Expected ErrorMsg when removing without a client")
+	}
+}
+
+func TestRecordView_RemoveSingleValueFromAttributeRow(t *testing.T) {
+	rv := newMultiValueRecordView()
+
+	rv.table.SetCursor(0) // cn has one value
+	rv.Update(keyRune('-'))
+	if rv.pendingRemoval == nil || rv.pendingRemoval.ValueIndex != 0 {
+		t.Error("Expected the only value of a single-valued row to be selected for removal")
+	}
+}
+
+func TestRecordView_AddValue(t *testing.T) {
+	rv := newMultiValueRecordView()
+	rv.table.SetCursor(1)
+
+	rv.Update(keyRune('+'))
+	if !rv.IsEditing() || rv.editOp != ldap.ModifyAdd {
+		t.Fatal("Expected '+' to open an add-value editor")
+	}
+	if !strings.Contains(rv.View(), "Adding a value to member") {
+		t.Error("Expected add-value title to be rendered")
+	}
+
+	// An empty value is rejected rather than removing the attribute
+	rv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if rv.editError == nil || !strings.Contains(rv.editError.Error(), "enter a value") {
+		t.Errorf("Expected empty value error, got %v", rv.editError)
+	}
+
+	for _, r := range "uid=dave" {
+		rv.Update(keyRune(r))
+	}
+	rv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if rv.editError == nil || !strings.Contains(rv.editError.Error(), "not connected") {
+		t.Errorf("Expected not connected error, got %v", rv.editError)
+	}
+}
+
+func TestRecordView_AddValueRejectsSingleValuedSchema(t *testing.T) {
+	rv := newMultiValueRecordView()
+	rv.SetSchema(&ldap.Schema{AttributeTypes: map[string]*ldap.AttributeType{
+		"cn": {OID: "2.5.4.3", Names: []string{"cn"}, SingleValue: true},
+	}})
+
+	rv.table.SetCursor(0)
+	rv.Update(keyRune('+'))
+	if rv.IsEditing() {
+		t.Error("Expected '+' to be refused for a single-valued attribute")
+	}
+}
+
+func TestRecordView_ValueRowText(t *testing.T) {
+	rv := newMultiValueRecordView()
+	rv.table.SetCursor(1)
+	rv.Update(keyRune(' '))
+
+	if got := rv.rowValueText(rv.renderedRows[1]); got != "▼ 3 values" {
+		t.Errorf("Expected expanded summary, got %q", got)
+	}
+	if got := rv.rowValueText(rv.renderedRows[2]); !strings.HasSuffix(got, "uid=alice,ou=people,dc=example,dc=com") {
+		t.Errorf("Expected value sub-row text, got %q", got)
+	}
+}
+
+func TestRecordView_MultiValueDisplayStyles(t *testing.T) {
+	tests := []struct {
+		name      string
+		style     string
+		separator string
+		wantRows  int
+		wantText  string
+	}{
+		{"inline default", "", "", 2, "• uid=alice,ou=people,dc=example,dc=com • uid=bob,ou=people,dc=example,dc=com • uid=carol,ou=people,dc=example,dc=com"},
+		{"inline separator", config.MultiValueInline, "; ", 2, "uid=alice,ou=people,dc=example,dc=com; uid=bob,ou=people,dc=example,dc=com; uid=carol,ou=people,dc=example,dc=com"},
+		{"one per line", config.MultiValueLines, "", 5, "▼ 3 values"},
+		{"count only", config.MultiValueCount, "", 2, "▶ 3 values, expand to view"},
+		{"unknown falls back to inline", "table", "", 2, "• uid=alice,ou=people,dc=example,dc=com • uid=bob,ou=people,dc=example,dc=com • uid=carol,ou=people,dc=example,dc=com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rv := newMultiValueRecordView()
+			rv.SetMultiValueDisplay(tt.style, tt.separator)
+
+			if len(rv.renderedRows) != tt.wantRows {
+				t.Fatalf("Expected %d rows, got %d", tt.wantRows, len(rv.renderedRows))
+			}
+			if got := rv.rowValueText(rv.renderedRows[1]); got != tt.wantText {
+				t.Errorf("Expected %q, got %q", tt.wantText, got)
+			}
+			if got := rv.rowValueText(rv.renderedRows[0]); got != "admins" {
+				t.Errorf("Expected single values to be shown as-is, got %q", got)
+			}
+		})
+	}
+}
+
+func TestRecordView_MultiValueDisplayExpandAndCollapse(t *testing.T) {
+	rv := newMultiValueRecordView()
+	rv.table.SetCursor(1)
+
+	// Count-only attributes expand to show their values
+	rv.SetMultiValueDisplay(config.MultiValueCount, "")
+	rv.table.SetCursor(1)
+	rv.Update(keyRune(' '))
+	if len(rv.renderedRows) != 5 {
+		t.Errorf("Expected expanding a counted attribute to show its values, got %d rows", len(rv.renderedRows))
+	}
+
+	// Attributes shown one per line can be collapsed
+	rv.SetMultiValueDisplay(config.MultiValueLines, "")
+	rv.table.SetCursor(1)
+	rv.Update(keyRune(' '))
+	if len(rv.renderedRows) != 2 {
+		t.Errorf("Expected collapsing to hide the values, got %d rows", len(rv.renderedRows))
+	}
+}
+
+func TestRecordView_CycleMultiValueDisplay(t *testing.T) {
+	rv := newMultiValueRecordView()
+
+	for _, want := range []string{config.MultiValueLines, config.MultiValueCount, config.MultiValueInline} {
+		_, cmd := rv.Update(keyRune('v'))
+		if rv.multiValue != want {
+			t.Errorf("Expected V to switch to %s, got %s", want, rv.multiValue)
+		}
+		if _, ok := cmd().(StatusMsg); !ok {
+			t.Error("Expected the new style to be reported")
+		}
+	}
+}
+
+func TestRecordView_HideEmptyAttributes(t *testing.T) {
+	rv := NewRecordView()
+	rv.SetSize(80, 30)
+	rv.SetEntry(&ldap.Entry{
+		DN: "uid=jdoe,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"cn":          {"John Doe"},
+			"description": {""},
+			"mail":        {"", "jdoe@example.com"},
+		},
+	})
+	if len(rv.renderedRows) != 3 {
+		t.Fatalf("Expected every attribute by default, got %d rows", len(rv.renderedRows))
+	}
+
+	_, cmd := rv.Update(keyRune('h'))
+	if msg, ok := cmd().(StatusMsg); !ok || msg.Message != "Hiding empty and operational attributes" {
+		t.Errorf("Unexpected status %v", msg)
+	}
+	if len(rv.renderedRows) != 2 || rv.renderedRows[0].AttributeName != "cn" || rv.renderedRows[1].AttributeName != "mail" {
+		t.Errorf("Expected description to be hidden, got %+v", rv.renderedRows)
+	}
+	if got := rv.renderedRows[1].Values; len(got) != 1 {
+		t.Errorf("Expected the empty mail value to be hidden, got %v", got)
+	}
+
+	// Entries that are entirely empty say so instead of looking unreadable
+	rv.SetEntry(&ldap.Entry{DN: "cn=blank,dc=example,dc=com", Attributes: map[string][]string{"description": {""}}})
+	if view := rv.renderTable(); !strings.Contains(view, "Every attribute of this entry is empty") {
+		t.Errorf("Expected a hidden-empty message, got:\n%s", view)
+	}
+
+	rv.Update(keyRune('h'))
+	if len(rv.renderedRows) != 1 {
+		t.Errorf("Expected empty attributes to be shown again, got %d rows", len(rv.renderedRows))
+	}
+}
+
+func TestRecordView_HideEmptyLeavesOperationalAttributesToTheServer(t *testing.T) {
+	const dn = "uid=jdoe,dc=example,dc=com"
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: dn, Attributes: map[string][]string{"uid": {"jdoe"}}},
+	)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+
+	rv := NewRecordView()
+	rv.SetSize(80, 30)
+	rv.SetClient(client)
+	rv.SetEntry(&ldap.Entry{DN: dn, Attributes: map[string][]string{"uid": {"jdoe"}}})
+
+	var shown *ldap.Entry
+	for _, msg := range runCmd(rv.toggleHideEmpty()) {
+		if msg, ok := msg.(ShowRecordMsg); ok {
+			shown = msg.Entry
+		}
+	}
+	if shown == nil || shown.DN != dn {
+		t.Fatalf("Expected the entry to be read again, got %+v", shown)
+	}
+	rv.toggleHideEmpty()
+	if _, err := client.GetEntry(dn); err != nil {
+		t.Fatal(err)
+	}
+
+	requested := server.Attributes()
+	if len(requested) < 2 || strings.Join(requested[0], " ") != "*" || strings.Join(requested[len(requested)-1], " ") != "* +" {
+		t.Errorf("Expected operational attributes asked for only while empty ones are shown, got %v", requested)
+	}
+}
+
+func newVisibleSetRecordView() *RecordView {
+	rv := NewRecordView()
+	rv.SetSize(80, 30)
+	rv.SetVisibleAttributes([]string{"cn", "Mail", "telephoneNumber"})
+	rv.SetEntry(&ldap.Entry{
+		DN: "uid=jdoe,ou=people,dc=example,dc=com",
+		Attributes: map[string][]string{
+			"cn":          {"John Doe"},
+			"mail":        {"jdoe@example.com"},
+			"objectClass": {"inetOrgPerson"},
+			"uid":         {"jdoe"},
+		},
+	})
+	return rv
+}
+
+func TestRecordView_VisibleAttributes(t *testing.T) {
+	zone.NewGlobal()
+	rv := newVisibleSetRecordView()
+
+	var names []string
+	for _, row := range rv.renderedRows {
+		names = append(names, row.AttributeName)
+	}
+	if got := strings.Join(names, ","); got != "cn,mail" {
+		t.Errorf("Expected only the configured attributes, matched case-insensitively, got %s", got)
+	}
+	if view := rv.View(); !strings.Contains(view, "2 more attributes hidden") {
+		t.Errorf("Expected a hint about the hidden attributes, got %q", view)
+	}
+
+	rv.Update(keyRune('a'))
+	if len(rv.renderedRows) != 4 {
+		t.Errorf("Expected A to reveal every attribute, got %d rows", len(rv.renderedRows))
+	}
+	if view := rv.View(); strings.Contains(view, "hidden") {
+		t.Error("Expected no hidden attributes hint once all are shown")
+	}
+
+	rv.Update(keyRune('a'))
+	if len(rv.renderedRows) != 2 {
+		t.Errorf("Expected A again to hide the rest, got %d rows", len(rv.renderedRows))
+	}
+}
+
+func TestRecordView_VisibleAttributesUnset(t *testing.T) {
+	rv := newMultiValueRecordView()
+	if len(rv.renderedRows) != 2 || rv.hiddenAttributeCount() != 0 {
+		t.Errorf("Expected every attribute without a visible set, got %d rows", len(rv.renderedRows))
+	}
+	if status := rv.toggleShowAll(); !strings.Contains(status, "visible_attributes") {
+		t.Errorf("Expected the toggle to explain the setting, got %q", status)
+	}
+}
+
+func TestRecordView_VisibleAttributesNoneSet(t *testing.T) {
+	rv := NewRecordView()
+	rv.SetSize(80, 30)
+	rv.SetVisibleAttributes([]string{"mail"})
+	rv.SetEntry(&ldap.Entry{DN: "cn=admins,dc=example,dc=com", Attributes: map[string][]string{"cn": {"admins"}}})
+
+	if view := rv.View(); !strings.Contains(view, "None of the configured attributes are set") {
+		t.Errorf("Expected an explanation with a way to show the rest, got %q", view)
+	}
+}
+
+func TestRecordView_HighlightsChangedAttributes(t *testing.T) {
+	rv := newMultiValueRecordView()
+	rv.SetClient(&ldap.Client{})
+	rv.applyChange(ldap.AttributeChange{Operation: ldap.ModifyAdd, Attribute: "description", Values: []string{"Admins"}})
+	if rv.snapshot == nil {
+		t.Fatal("Expected the entry to be kept from before the modify")
+	}
+
+	modified := &ldap.Entry{
+		DN: rv.entry.DN,
+		Attributes: map[string][]string{
+			"CN": {"admins"},
+			// Same values in another order
+			"member": {
+				"uid=carol,ou=people,dc=example,dc=com",
+				"uid=alice,ou=people,dc=example,dc=com",
+				"uid=bob,ou=people,dc=example,dc=com",
+			},
+			"description": {"Admins"},
+		},
+	}
+	_, cmd := rv.Update(EntryModifiedMsg{Entry: modified, Attribute: "description"})
+	if cmd == nil {
+		t.Fatal("Expected a timer to end the highlight")
+	}
+	if !rv.isChanged("description") || rv.isChanged("cn") || rv.isChanged("member") {
+		t.Errorf("Expected only description to be flagged, got %v", rv.changed)
+	}
+
+	// A newer highlight isn't ended by an older timer
+	rv.Update(ChangeHighlightExpiredMsg{Seq: rv.changedSeq - 1})
+	if !rv.isChanged("description") {
+		t.Error("Expected a stale timer to leave the highlight")
+	}
+	rv.Update(ChangeHighlightExpiredMsg{Seq: rv.changedSeq})
+	if len(rv.changed) != 0 {
+		t.Error("Expected the highlight to fade")
+	}
+}
+
+func TestChangedAttributes(t *testing.T) {
+	before := &ldap.Entry{Attributes: map[string][]string{
+		"cn":    {"Alice"},
+		"mail":  {"alice@example.com"},
+		"title": {"Engineer"},
+		"ou":    {"a", "b"},
+	}}
+	after := &ldap.Entry{Attributes: map[string][]string{
+		"cn":              {"Alice"},
+		"mail":            {"alice@example.org"},
+		"telephoneNumber": {"555-0100"},
+		"ou":              {"a", "a"},
+	}}
+
+	got := changedAttributes(before, after)
+	if len(got) != 3 || !got["mail"] || !got["telephonenumber"] || !got["ou"] {
+		t.Errorf("Expected mail, telephoneNumber and ou to be changed, got %v", got)
+	}
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// RetryStatusMsg is sent when an operation lost its connection and is being retried
+type RetryStatusMsg struct {
+	Connection string // Name of the connection's tab
+	Event      ldap.RetryEvent
+}
+
+// retryBuffer bounds the retry reports waiting to be shown. Reports beyond
+// it are dropped rather than holding up the operation being retried.
+const retryBuffer = 16
+
+// watchRetries reports client's retries in the status bar. The first call
+// returns the command that waits for reports; later connections share it.
+func (m *Model) watchRetries(client *ldap.Client, connection string) tea.Cmd {
+	var cmd tea.Cmd
+	if m.retries == nil {
+		m.retries = make(chan RetryStatusMsg, retryBuffer)
+		cmd = waitForRetry(m.retries)
+	}
+
+	retries := m.retries
+	client.SetRetryHandler(func(event ldap.RetryEvent) {
+		select {
+		case retries <- RetryStatusMsg{Connection: connection, Event: event}:
+		default:
+		}
+	})
+	return cmd
+}
+
+// waitForRetry waits for the next retry report
+func waitForRetry(retries <-chan RetryStatusMsg) tea.Cmd {
+	return func() tea.Msg {
+		return <-retries
+	}
+}
+
+// retryStatus describes a retry for the status bar, naming the connection
+// when several are open
+func (m *Model) retryStatus(msg RetryStatusMsg) string {
+	status := fmt.Sprintf("%s %s", iconLoading, msg.Event)
+	if len(m.tabs) > 1 {
+		status = fmt.Sprintf("[%s] %s", msg.Connection, status)
+	}
+	return status
+}
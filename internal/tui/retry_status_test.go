@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func TestModel_ShowsRetries(t *testing.T) {
+	model, _, _ := newTwoTabModel(t)
+	if model.retries == nil {
+		t.Fatal("Expected connecting to start watching for retries")
+	}
+
+	model.retries <- RetryStatusMsg{Connection: "Production", Event: ldap.RetryEvent{Attempt: 2, MaxAttempts: 3, Delay: time.Second}}
+	_, cmd := model.Update(waitForRetry(model.retries)())
+	if !strings.HasPrefix(model.toasts.latest(), "[Production] ") || !strings.HasSuffix(model.toasts.latest(), "Connection lost, retrying (attempt 2/3, waiting 1s)") {
+		t.Errorf("Expected the retry and its connection in the status bar, got %q", model.toasts.latest())
+	}
+
+	// The next report is waited for once one has been shown
+	if cmd == nil {
+		t.Fatal("Expected to keep waiting for retries")
+	}
+	model.retries <- RetryStatusMsg{Connection: "Staging", Event: ldap.RetryEvent{Attempt: 3, MaxAttempts: 3, Delay: 2 * time.Second}}
+	if retry, ok := cmd().(RetryStatusMsg); !ok || retry.Event.Attempt != 3 {
+		t.Errorf("Expected the next retry report for whichever tab is open, got %#v", retry)
+	}
+}
+
+func TestModel_RetriesOutliveTheirTab(t *testing.T) {
+	model, _, _ := newTwoTabModel(t)
+
+	// The waiter was tagged with the tab that was active when it started
+	staging := model.tabs[model.activeTab]
+	model.closeTab()
+
+	model.retries <- RetryStatusMsg{Connection: "Production", Event: ldap.RetryEvent{Attempt: 1, MaxAttempts: 3, Delay: time.Second}}
+	_, cmd := model.Update(tabMsg{tab: staging, msg: waitForRetry(model.retries)()})
+	if !strings.HasSuffix(model.toasts.latest(), "Connection lost, retrying (attempt 1/3, waiting 1s)") {
+		t.Errorf("Expected the retry shown after its tab closed, got %q", model.toasts.latest())
+	}
+	if cmd == nil {
+		t.Error("Expected to keep waiting for retries after the tab closed")
+	}
+}
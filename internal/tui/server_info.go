@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// ServerTimeMsg is sent when the server's clock has been read. Local is the
+// local time halfway through the read, which the skew is measured against.
+type ServerTimeMsg struct {
+	Client *ldap.Client
+	Server time.Time
+	Local  time.Time
+	Err    error
+}
+
+// readServerTimeCmd reads the server's clock in the background
+func readServerTimeCmd(client *ldap.Client) tea.Cmd {
+	return func() tea.Msg {
+		before := time.Now()
+		server, err := client.GetServerTime()
+		after := time.Now()
+		return ServerTimeMsg{Client: client, Server: server, Local: before.Add(after.Sub(before) / 2), Err: err}
+	}
+}
+
+// ServerInfo is an overlay describing the connected server: what its root
+// DSE advertises and how far its clock is from the local one
+type ServerInfo struct {
+	client     *ldap.Client
+	connection ldap.Config
+
+	loading bool
+	server  time.Time
+	local   time.Time
+	err     error
+	latency latencyReading
+}
+
+// NewServerInfo creates the overlay for client and starts reading its clock
+// and measuring its round-trip time
+func NewServerInfo(client *ldap.Client, connection ldap.Config) (*ServerInfo, tea.Cmd) {
+	info := &ServerInfo{client: client, connection: connection, loading: true}
+	return info, tea.Batch(readServerTimeCmd(client), pingCmd(client, false))
+}
+
+// Update handles a key press. It returns true when the overlay should close.
+func (si *ServerInfo) Update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+g":
+		return true, nil
+	case "r", "R":
+		if si.loading {
+			return false, nil
+		}
+		si.loading = true
+		return false, tea.Batch(readServerTimeCmd(si.client), pingCmd(si.client, false))
+	}
+	return false, nil
+}
+
+// setTime shows the result of a clock read
+func (si *ServerInfo) setTime(msg ServerTimeMsg) {
+	si.loading = false
+	si.server, si.local, si.err = msg.Server, msg.Local, msg.Err
+}
+
+// skew returns how far the server clock is ahead of the local one
+func (si *ServerInfo) skew() time.Duration {
+	return ldap.ClockSkew(si.server, si.local)
+}
+
+// View renders the overlay as a bordered box
+func (si *ServerInfo) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	detailStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8"))
+	row := func(label, value string) string {
+		return editorLabelStyle.Render(fmt.Sprintf("%-16s", label)) + " " + value
+	}
+
+	sections := []string{titleStyle.Render("Server info"), ""}
+	sections = append(sections, row("Server", net.JoinHostPort(si.connection.Host, strconv.Itoa(si.connection.Port))))
+	sections = append(sections, row("Round trip", si.latency.describe()))
+
+	caps := si.client.Capabilities()
+	switch {
+	case caps == nil:
+		sections = append(sections, detailStyle.Render("The root DSE couldn't be read"))
+	default:
+		if vendor := strings.TrimSpace(caps.VendorName + " " + caps.VendorVersion); vendor != "" {
+			sections = append(sections, row("Vendor", vendor))
+		}
+		if len(caps.NamingContexts) > 0 {
+			sections = append(sections, row("Naming contexts", strings.Join(caps.NamingContexts, ", ")))
+		}
+		sections = append(sections, row("Advertises", fmt.Sprintf("%d controls, %d extended operations", len(caps.Controls), len(caps.Extensions))))
+	}
+
+	sections = append(sections, "")
+	switch {
+	case si.loading:
+		sections = append(sections, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Italic(true).
+			Render(iconLoading.String()+" Reading server time..."))
+	case si.err != nil:
+		sections = append(sections, row("Server time", detailStyle.Render(ldap.Explain(si.err).Error())))
+	default:
+		skew := si.skew()
+		skewText := ldap.FormatClockSkew(skew)
+		if ldap.ExcessiveClockSkew(skew) {
+			skewText = editorErrorStyle.Render(fmt.Sprintf("%s %s (more than %s; Kerberos and replication may fail)", iconWarning, skewText, ldap.MaxClockSkew))
+		} else {
+			skewText = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render(skewText)
+		}
+		sections = append(sections,
+			row("Server time", si.server.UTC().Format("2006-01-02 15:04:05 UTC")),
+			row("Local time", si.local.UTC().Format("2006-01-02 15:04:05 UTC")),
+			row("Clock skew", skewText),
+		)
+	}
+
+	sections = append(sections, "", helpStyle.Render("[R] read the clock and round trip again • [M] monitor • [Esc] close"))
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("6")).
+		Padding(0, 1).
+		Render(strings.Join(sections, "\n"))
+}
+
+// openServerInfo shows the server info overlay for the active connection
+func (m *Model) openServerInfo() tea.Cmd {
+	if m.client == nil {
+		m.notify(ToastInfo, "Connect to a server to see its info")
+		return nil
+	}
+	var cmd tea.Cmd
+	m.serverInfo, cmd = NewServerInfo(m.client, m.connection)
+	m.serverInfo.latency = m.latency
+	return cmd
+}
+
+// handleServerInfoKey forwards a key to the server info overlay; M opens the
+// monitor dashboard in its place
+func (m *Model) handleServerInfoKey(msg tea.KeyMsg) tea.Cmd {
+	if msg.String() == "m" || msg.String() == "M" {
+		return m.openMonitor()
+	}
+	done, cmd := m.serverInfo.Update(msg)
+	if done {
+		m.serverInfo = nil
+	}
+	return cmd
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+)
+
+func TestModel_ServerInfo(t *testing.T) {
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "", Attributes: map[string][]string{
+			"currentTime": {ldap.FormatGeneralizedTime(time.Now())},
+			"vendorName":  {"Example Directory"},
+		}},
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+	)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer client.Close()
+	if _, err := client.LoadCapabilities(); err != nil {
+		t.Fatal(err)
+	}
+
+	model := NewModel(nil, config.Default())
+	model.client = client
+	model.connection = client.Config()
+	model.width, model.height = 120, 40
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if model.serverInfo == nil || !model.isInputActive() {
+		t.Fatal("Expected Ctrl+G to open the server info overlay")
+	}
+	var timeRead, pinged bool
+	for _, msg := range runCmd(cmd) {
+		switch msg := msg.(type) {
+		case ServerTimeMsg:
+			timeRead = msg.Err == nil
+		case PingMsg:
+			pinged = msg.Err == nil && !msg.Scheduled
+		}
+		model.Update(msg)
+	}
+	if !timeRead || !pinged {
+		t.Fatalf("Expected the server time to be read and the server pinged, got %v and %v", timeRead, pinged)
+	}
+
+	view := model.serverInfo.View()
+	for _, want := range []string{"Example Directory", "Server time", "Clock skew", "Round trip"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected the overlay to show %q, got:\n%s", want, view)
+		}
+	}
+	if skew := model.serverInfo.skew(); ldap.ExcessiveClockSkew(skew) {
+		t.Errorf("Expected the clocks to agree, got a skew of %v", skew)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.serverInfo != nil {
+		t.Error("Expected Esc to close the overlay")
+	}
+}
+
+func TestServerInfo_HighlightsSkew(t *testing.T) {
+	local := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	info := &ServerInfo{client: &ldap.Client{}, connection: ldap.Config{Host: "2001:db8::1", Port: 636}}
+	info.setTime(ServerTimeMsg{Server: local.Add(-7 * time.Minute), Local: local})
+
+	view := info.View()
+	if !strings.Contains(view, "[2001:db8::1]:636") {
+		t.Errorf("Expected the IPv6 address in brackets, got:\n%s", view)
+	}
+	if !strings.Contains(view, "7m0s behind") || !strings.Contains(view, "Kerberos and replication may fail") {
+		t.Errorf("Expected the skew to be flagged, got:\n%s", view)
+	}
+
+	info.setTime(ServerTimeMsg{Err: ldap.ErrServerTimeUnavailable})
+	if !strings.Contains(info.View(), "does not publish its current time") {
+		t.Error("Expected a missing server time to be explained")
+	}
+}
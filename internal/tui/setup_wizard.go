@@ -0,0 +1,661 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// wizardStep identifies a page of the setup wizard
+type wizardStep int
+
+const (
+	wizardStepWelcome wizardStep = iota
+	wizardStepDiscover
+	wizardStepHost
+	wizardStepSecurity
+	wizardStepCertificate // Skipped without encryption
+	wizardStepBind
+	wizardStepBaseDN
+	wizardStepReview
+)
+
+// wizardResult tells the model whether the wizard is still running
+type wizardResult int
+
+const (
+	wizardRunning  wizardResult = iota
+	wizardSkipped               // The user chose to fill in the config form instead
+	wizardFinished              // Settings were applied to the config
+)
+
+// wizardSecurityOptions lists the transport choices with their default ports
+var wizardSecurityOptions = []struct {
+	Label  string
+	Port   int
+	UseSSL bool
+	UseTLS bool
+}{
+	{"StartTLS (recommended)", 389, false, true},
+	{"LDAPS (SSL)", 636, true, false},
+	{"No encryption (passwords are sent in clear text)", 389, false, false},
+}
+
+// wizardDiscoveryMsg is sent when the DNS SRV lookup for a domain completes
+type wizardDiscoveryMsg struct {
+	Domain  string
+	Servers []ldap.DiscoveredServer
+	Err     error
+}
+
+// wizardBaseDNMsg is sent when the naming contexts have been read from the root DSE
+type wizardBaseDNMsg struct {
+	Contexts []string // Suggested base DN first
+	Err      error
+}
+
+// SetupWizardView walks a first-time user through the connection settings one
+// step at a time, then writes them to the config
+type SetupWizardView struct {
+	config    *config.Config
+	step      wizardStep
+	width     int
+	height    int
+	container *ViewContainer
+
+	domainInput   textinput.Model
+	hostInput     textinput.Model
+	portInput     textinput.Model
+	bindUserInput textinput.Model
+	bindPassInput textinput.Model
+	baseDNInput   textinput.Model
+	caCertInput   textinput.Model
+
+	security  int  // Index into wizardSecurityOptions
+	verifyTLS bool // Verify the server certificate of an encrypted connection
+	bindFocus int  // 0 for the bind user, 1 for the password
+
+	discoveredDomain string
+	servers          []ldap.DiscoveredServer
+	serverCursor     int
+	contexts         []string
+	contextCursor    int
+
+	busy bool
+	err  error
+}
+
+// newWizardInput creates a text input for a wizard step
+func newWizardInput(placeholder string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = 0
+	ti.Width = 50
+	return ti
+}
+
+// NewSetupWizardView creates a setup wizard that writes its settings to cfg
+func NewSetupWizardView(cfg *config.Config) *SetupWizardView {
+	w := &SetupWizardView{
+		config:        cfg,
+		domainInput:   newWizardInput("example.com (optional)"),
+		hostInput:     newWizardInput("ldap.example.com"),
+		portInput:     newWizardInput("389"),
+		bindUserInput: newWizardInput("cn=admin,dc=example,dc=com or admin@example.com"),
+		bindPassInput: newWizardInput("password"),
+		baseDNInput:   newWizardInput("dc=example,dc=com"),
+		caCertInput:   newWizardInput("/etc/ssl/certs/corp-ca.pem (optional)"),
+		verifyTLS:     true,
+	}
+	w.portInput.CharLimit = 5
+	w.portInput.SetValue(strconv.Itoa(wizardSecurityOptions[0].Port))
+	w.bindPassInput.EchoMode = textinput.EchoPassword
+	return w
+}
+
+// SetSize sets the wizard dimensions
+func (w *SetupWizardView) SetSize(width, height int) {
+	w.width = width
+	w.height = height
+	w.container = NewViewContainer(width, height)
+}
+
+// Update handles messages. It reports whether the wizard was skipped or
+// finished, in which case the model closes it.
+func (w *SetupWizardView) Update(msg tea.Msg) (wizardResult, tea.Cmd) {
+	switch msg := msg.(type) {
+	case wizardDiscoveryMsg:
+		w.handleDiscovery(msg)
+		return wizardRunning, nil
+
+	case wizardBaseDNMsg:
+		w.handleBaseDN(msg)
+		return wizardRunning, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+x" {
+			return wizardSkipped, nil
+		}
+		if w.busy {
+			return wizardRunning, nil
+		}
+		if msg.String() == "esc" {
+			if w.step == wizardStepWelcome {
+				return wizardSkipped, nil
+			}
+			w.setStep(w.previousStep())
+			return wizardRunning, nil
+		}
+		return w.handleKey(msg)
+	}
+	return wizardRunning, nil
+}
+
+// handleKey handles a key press on the current step
+func (w *SetupWizardView) handleKey(msg tea.KeyMsg) (wizardResult, tea.Cmd) {
+	switch w.step {
+	case wizardStepWelcome:
+		if msg.String() == "enter" {
+			w.setStep(wizardStepDiscover)
+		}
+		return wizardRunning, nil
+
+	case wizardStepDiscover:
+		switch msg.String() {
+		case "enter":
+			return wizardRunning, w.submitDiscover()
+		case "up":
+			if w.serverCursor > 0 {
+				w.serverCursor--
+			}
+			return wizardRunning, nil
+		case "down":
+			if w.serverCursor < len(w.servers)-1 {
+				w.serverCursor++
+			}
+			return wizardRunning, nil
+		}
+		return wizardRunning, w.updateInput(&w.domainInput, msg)
+
+	case wizardStepHost:
+		if msg.String() == "enter" {
+			if strings.TrimSpace(w.hostInput.Value()) == "" {
+				w.err = fmt.Errorf("enter the server's host name")
+				return wizardRunning, nil
+			}
+			w.setStep(wizardStepSecurity)
+			return wizardRunning, nil
+		}
+		return wizardRunning, w.updateInput(&w.hostInput, msg)
+
+	case wizardStepSecurity:
+		switch msg.String() {
+		case "up":
+			w.selectSecurity(w.security - 1)
+			return wizardRunning, nil
+		case "down":
+			w.selectSecurity(w.security + 1)
+			return wizardRunning, nil
+		case "enter":
+			port, err := strconv.Atoi(strings.TrimSpace(w.portInput.Value()))
+			if err != nil || port < 1 || port > 65535 {
+				w.err = fmt.Errorf("port must be a number from 1 to 65535")
+				return wizardRunning, nil
+			}
+			if w.encrypted() {
+				w.setStep(wizardStepCertificate)
+			} else {
+				w.setStep(wizardStepBind)
+			}
+			return wizardRunning, nil
+		}
+		return wizardRunning, w.updateInput(&w.portInput, msg)
+
+	case wizardStepCertificate:
+		switch msg.String() {
+		case "up":
+			w.verifyTLS = true
+			w.err = nil
+			return wizardRunning, nil
+		case "down":
+			w.verifyTLS = false
+			w.err = nil
+			return wizardRunning, nil
+		case "enter":
+			if path := strings.TrimSpace(w.caCertInput.Value()); path != "" && w.verifyTLS {
+				if _, err := os.Stat(path); err != nil {
+					w.err = fmt.Errorf("CA certificate %s can't be read: %w", path, err)
+					return wizardRunning, nil
+				}
+			}
+			w.setStep(wizardStepBind)
+			return wizardRunning, nil
+		}
+		return wizardRunning, w.updateInput(&w.caCertInput, msg)
+
+	case wizardStepBind:
+		switch msg.String() {
+		case "tab", "shift+tab", "up", "down":
+			w.bindFocus = 1 - w.bindFocus
+			w.focusCurrent()
+			return wizardRunning, nil
+		case "enter":
+			if w.bindFocus == 0 {
+				w.bindFocus = 1
+				w.focusCurrent()
+				return wizardRunning, nil
+			}
+			w.setStep(wizardStepBaseDN)
+			return wizardRunning, w.detectBaseDN()
+		}
+		if w.bindFocus == 0 {
+			return wizardRunning, w.updateInput(&w.bindUserInput, msg)
+		}
+		return wizardRunning, w.updateInput(&w.bindPassInput, msg)
+
+	case wizardStepBaseDN:
+		switch msg.String() {
+		case "up":
+			w.selectContext(w.contextCursor - 1)
+			return wizardRunning, nil
+		case "down":
+			w.selectContext(w.contextCursor + 1)
+			return wizardRunning, nil
+		case "enter":
+			if strings.TrimSpace(w.baseDNInput.Value()) == "" {
+				w.err = fmt.Errorf("enter the base DN to browse from")
+				return wizardRunning, nil
+			}
+			w.setStep(wizardStepReview)
+			return wizardRunning, nil
+		}
+		return wizardRunning, w.updateInput(&w.baseDNInput, msg)
+
+	case wizardStepReview:
+		if msg.String() == "enter" {
+			if problems := w.connection().Validate(); len(problems) > 0 {
+				w.err = errors.New(strings.Join(problems, "; "))
+				return wizardRunning, nil
+			}
+			w.apply()
+			return wizardFinished, nil
+		}
+	}
+	return wizardRunning, nil
+}
+
+// updateInput forwards a key to a text input and clears any step error
+func (w *SetupWizardView) updateInput(input *textinput.Model, msg tea.KeyMsg) tea.Cmd {
+	w.err = nil
+	var cmd tea.Cmd
+	*input, cmd = input.Update(msg)
+	return cmd
+}
+
+// setStep moves to a step and focuses its input
+func (w *SetupWizardView) setStep(step wizardStep) {
+	w.step = step
+	w.err = nil
+	w.bindFocus = 0
+	w.focusCurrent()
+}
+
+// focusCurrent focuses the input of the current step and blurs the rest
+func (w *SetupWizardView) focusCurrent() {
+	inputs := map[*textinput.Model]bool{
+		&w.domainInput:   w.step == wizardStepDiscover,
+		&w.hostInput:     w.step == wizardStepHost,
+		&w.portInput:     w.step == wizardStepSecurity,
+		&w.bindUserInput: w.step == wizardStepBind && w.bindFocus == 0,
+		&w.bindPassInput: w.step == wizardStepBind && w.bindFocus == 1,
+		&w.baseDNInput:   w.step == wizardStepBaseDN,
+		&w.caCertInput:   w.step == wizardStepCertificate,
+	}
+	for input, focused := range inputs {
+		if focused {
+			input.Focus()
+		} else {
+			input.Blur()
+		}
+	}
+}
+
+// previousStep is the step Esc returns to, passing over the certificate
+// step when the connection isn't encrypted
+func (w *SetupWizardView) previousStep() wizardStep {
+	if w.step == wizardStepBind && !w.encrypted() {
+		return wizardStepSecurity
+	}
+	return w.step - 1
+}
+
+// encrypted reports whether the chosen transport uses TLS
+func (w *SetupWizardView) encrypted() bool {
+	option := wizardSecurityOptions[w.security]
+	return option.UseSSL || option.UseTLS
+}
+
+// selectSecurity picks a transport option, moving the port along with it
+// unless the user has typed a custom one
+func (w *SetupWizardView) selectSecurity(index int) {
+	if index < 0 || index >= len(wizardSecurityOptions) {
+		return
+	}
+	if w.portInput.Value() == strconv.Itoa(wizardSecurityOptions[w.security].Port) {
+		w.portInput.SetValue(strconv.Itoa(wizardSecurityOptions[index].Port))
+	}
+	w.security = index
+	w.err = nil
+}
+
+// submitDiscover looks up servers for the entered domain, uses the selected
+// discovered server, or skips discovery when no domain is given
+func (w *SetupWizardView) submitDiscover() tea.Cmd {
+	domain := strings.TrimSpace(w.domainInput.Value())
+	if domain == "" {
+		w.setStep(wizardStepHost)
+		return nil
+	}
+
+	if len(w.servers) > 0 && strings.EqualFold(domain, w.discoveredDomain) {
+		server := w.servers[w.serverCursor]
+		w.hostInput.SetValue(server.Host)
+		w.security = 0
+		if server.UseSSL {
+			w.security = 1
+		}
+		w.portInput.SetValue(strconv.Itoa(server.Port))
+		w.setStep(wizardStepHost)
+		return nil
+	}
+
+	w.busy = true
+	w.err = nil
+	return func() tea.Msg {
+		servers, err := ldap.DiscoverServers(domain)
+		return wizardDiscoveryMsg{Domain: domain, Servers: servers, Err: err}
+	}
+}
+
+// handleDiscovery shows the servers found for a domain
+func (w *SetupWizardView) handleDiscovery(msg wizardDiscoveryMsg) {
+	w.busy = false
+	w.discoveredDomain = msg.Domain
+	w.servers = msg.Servers
+	w.serverCursor = 0
+	w.err = msg.Err
+}
+
+// detectBaseDN connects with the settings so far and reads the naming
+// contexts from the root DSE
+func (w *SetupWizardView) detectBaseDN() tea.Cmd {
+	clientConfig := ClientConfig(w.config, w.connection())
+
+	w.busy = true
+	return func() tea.Msg {
+		client, err := connectWithTimeout(clientConfig, connectTimeout)
+		if err != nil {
+			return wizardBaseDNMsg{Err: err}
+		}
+		defer client.Close()
+
+		caps, err := client.LoadCapabilities()
+		if err != nil {
+			return wizardBaseDNMsg{Err: err}
+		}
+		return wizardBaseDNMsg{Contexts: orderNamingContexts(caps)}
+	}
+}
+
+// orderNamingContexts lists the naming contexts with the suggested base DN first
+func orderNamingContexts(caps *ldap.Capabilities) []string {
+	suggested := caps.SuggestedBaseDN()
+	if suggested == "" {
+		return nil
+	}
+	contexts := []string{suggested}
+	for _, context := range caps.NamingContexts {
+		if !strings.EqualFold(context, suggested) {
+			contexts = append(contexts, context)
+		}
+	}
+	return contexts
+}
+
+// handleBaseDN fills in the detected base DN
+func (w *SetupWizardView) handleBaseDN(msg wizardBaseDNMsg) {
+	w.busy = false
+	w.contexts = msg.Contexts
+	w.contextCursor = 0
+	w.err = msg.Err
+	if msg.Err == nil && len(msg.Contexts) == 0 {
+		w.err = fmt.Errorf("the server doesn't list any naming contexts")
+	}
+	if len(msg.Contexts) > 0 {
+		w.baseDNInput.SetValue(msg.Contexts[0])
+	}
+}
+
+// selectContext fills the base DN input with a detected naming context
+func (w *SetupWizardView) selectContext(index int) {
+	if index < 0 || index >= len(w.contexts) {
+		return
+	}
+	w.contextCursor = index
+	w.baseDNInput.SetValue(w.contexts[index])
+}
+
+// connection returns the settings entered so far
+func (w *SetupWizardView) connection() config.LDAPConnection {
+	option := wizardSecurityOptions[w.security]
+	port, _ := strconv.Atoi(strings.TrimSpace(w.portInput.Value()))
+	conn := config.LDAPConnection{
+		Host:     strings.TrimSpace(w.hostInput.Value()),
+		Port:     port,
+		BaseDN:   strings.TrimSpace(w.baseDNInput.Value()),
+		UseSSL:   option.UseSSL,
+		UseTLS:   option.UseTLS,
+		BindUser: strings.TrimSpace(w.bindUserInput.Value()),
+		BindPass: w.bindPassInput.Value(),
+	}
+	if w.encrypted() && w.verifyTLS {
+		conn.VerifyTLS = true
+		conn.CACertPath = strings.TrimSpace(w.caCertInput.Value())
+	}
+	return conn
+}
+
+// apply writes the entered settings to the default connection of the config
+func (w *SetupWizardView) apply() {
+	conn := w.connection()
+	w.config.LDAP.Host = conn.Host
+	w.config.LDAP.Port = conn.Port
+	w.config.LDAP.BaseDN = conn.BaseDN
+	w.config.LDAP.UseSSL = conn.UseSSL
+	w.config.LDAP.UseTLS = conn.UseTLS
+	w.config.LDAP.BindUser = conn.BindUser
+	w.config.LDAP.BindPass = conn.BindPass
+	w.config.LDAP.VerifyTLS = conn.VerifyTLS
+	w.config.LDAP.CACertPath = conn.CACertPath
+}
+
+// View renders the current step
+func (w *SetupWizardView) View() string {
+	if w.container == nil {
+		w.container = NewViewContainer(w.width, w.height)
+	}
+
+	explainStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("7"))
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+
+	title, explanation, body, help := w.renderStep()
+
+	sections := []string{
+		headerStyle.Render(fmt.Sprintf("Setup (%d/%d) • %s", int(w.step)+1, int(wizardStepReview)+1, title)),
+		explainStyle.Render(explanation),
+		"",
+	}
+	if body != "" {
+		sections = append(sections, body)
+	}
+
+	if w.busy {
+		sections = append(sections, "", lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")).
+			Italic(true).
+			Render(iconLoading.String()+" Working..."))
+	} else if w.err != nil {
+		sections = append(sections, "", editorErrorStyle.Render(fmt.Sprintf("%s %s", iconError, w.err.Error())))
+	}
+
+	help += " • [Ctrl+X] skip to manual config"
+	sections = append(sections, "", helpStyle.Render(help))
+
+	return w.container.RenderWithPadding(containerStyle.Render(strings.Join(sections, "\n")))
+}
+
+// renderStep returns the title, explanation, body and key help of the current step
+func (w *SetupWizardView) renderStep() (string, string, string, string) {
+	switch w.step {
+	case wizardStepWelcome:
+		return "Welcome to moribito",
+			"No configuration file was found. This wizard asks for your LDAP server's\n" +
+				"details one at a time, checks them and saves them to a config file.",
+			"",
+			"[Enter] start • [Esc] skip to manual config"
+
+	case wizardStepDiscover:
+		var lines []string
+		lines = append(lines, w.domainInput.View())
+		if len(w.servers) > 0 {
+			lines = append(lines, "", fmt.Sprintf("Servers found for %s:", w.discoveredDomain))
+			for i, server := range w.servers {
+				scheme := "ldap"
+				if server.UseSSL {
+					scheme = "ldaps"
+				}
+				line := fmt.Sprintf("%s://%s:%d", scheme, server.Host, server.Port)
+				if i == w.serverCursor {
+					lines = append(lines, editorFocusStyle.Render("▶ ")+line)
+				} else {
+					lines = append(lines, "  "+line)
+				}
+			}
+		}
+		return "Find servers",
+			"Directories that publish DNS SRV records (Active Directory does) can be\n" +
+				"found from their domain name. Leave this empty to enter the host yourself.",
+			strings.Join(lines, "\n"),
+			"[Enter] look up / use selected server • [↑↓] select • [Esc] back"
+
+	case wizardStepHost:
+		return "Server",
+			"The host name or IP address of the LDAP server.",
+			w.hostInput.View(),
+			"[Enter] next • [Esc] back"
+
+	case wizardStepSecurity:
+		var lines []string
+		for i, option := range wizardSecurityOptions {
+			if i == w.security {
+				lines = append(lines, editorFocusStyle.Render("▶ "+option.Label))
+			} else {
+				lines = append(lines, "  "+option.Label)
+			}
+		}
+		lines = append(lines, "", editorLabelStyle.Render("Port:")+" "+w.portInput.View())
+		return "Security",
+			"How the connection is protected. StartTLS upgrades a plain connection on\n" +
+				"port 389; LDAPS uses TLS from the start on port 636.",
+			strings.Join(lines, "\n"),
+			"[↑↓] choose • [Enter] next • [Esc] back"
+
+	case wizardStepCertificate:
+		choices := []string{
+			"Verify the server certificate (recommended)",
+			"Don't verify (self-signed test servers; open to interception)",
+		}
+		var lines []string
+		for i, choice := range choices {
+			if (i == 0) == w.verifyTLS {
+				lines = append(lines, editorFocusStyle.Render("▶ "+choice))
+			} else {
+				lines = append(lines, "  "+choice)
+			}
+		}
+		lines = append(lines, "", editorLabelStyle.Render("CA certificate:")+" "+w.caCertInput.View())
+		return "Certificate",
+			"Whether to check that the server's certificate is valid for its host name.\n" +
+				"Servers signed by a private CA need its PEM file or a directory of them;\n" +
+				"leave it empty to trust the system's CAs.",
+			strings.Join(lines, "\n"),
+			"[↑↓] choose • [Enter] next • [Esc] back"
+
+	case wizardStepBind:
+		body := editorLabelStyle.Render("Bind user:") + " " + w.bindUserInput.View() + "\n" +
+			editorLabelStyle.Render("Password: ") + " " + w.bindPassInput.View()
+		return "Credentials",
+			"The account to sign in as, written as a DN or a UPN. Leave both fields\n" +
+				"empty to connect anonymously.",
+			body,
+			"[Tab] switch field • [Enter] next • [Esc] back"
+
+	case wizardStepBaseDN:
+		lines := []string{w.baseDNInput.View()}
+		if len(w.contexts) > 0 {
+			lines = append(lines, "", "Naming contexts on the server:")
+			for i, context := range w.contexts {
+				if i == w.contextCursor {
+					lines = append(lines, editorFocusStyle.Render("▶ ")+context)
+				} else {
+					lines = append(lines, "  "+context)
+				}
+			}
+		}
+		return "Base DN",
+			"Where browsing and searches start. It is detected from the server's\n" +
+				"naming contexts when possible; you can also type it yourself.",
+			strings.Join(lines, "\n"),
+			"[↑↓] choose detected • [Enter] next • [Esc] back"
+
+	default:
+		conn := w.connection()
+		bind := conn.BindUser
+		if bind == "" {
+			bind = "(anonymous)"
+		}
+		rows := [][2]string{
+			{"Host", conn.Host},
+			{"Port", strconv.Itoa(conn.Port)},
+			{"Security", wizardSecurityOptions[w.security].Label},
+		}
+		switch {
+		case conn.CACertPath != "":
+			rows = append(rows, [2]string{"Certificate", "verified against " + conn.CACertPath})
+		case conn.VerifyTLS:
+			rows = append(rows, [2]string{"Certificate", "verified against the system CAs"})
+		case w.encrypted():
+			rows = append(rows, [2]string{"Certificate", "not verified"})
+		}
+		rows = append(rows, [2]string{"Bind user", bind}, [2]string{"Base DN", conn.BaseDN})
+		lines := make([]string, 0, len(rows))
+		for _, row := range rows {
+			lines = append(lines, editorLabelStyle.Render(fmt.Sprintf("%-11s", row[0]))+" "+row[1])
+		}
+		return "Review",
+			"These settings will be saved to your config file and used to connect.",
+			strings.Join(lines, "\n"),
+			"[Enter] save and connect • [Esc] back"
+	}
+}
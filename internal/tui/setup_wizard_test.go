@@ -0,0 +1,328 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func wizardKey(w *SetupWizardView, key string) (wizardResult, tea.Cmd) {
+	switch key {
+	case "enter":
+		return w.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	case "esc":
+		return w.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	case "up":
+		return w.Update(tea.KeyMsg{Type: tea.KeyUp})
+	case "down":
+		return w.Update(tea.KeyMsg{Type: tea.KeyDown})
+	case "tab":
+		return w.Update(tea.KeyMsg{Type: tea.KeyTab})
+	case "ctrl+x":
+		return w.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	}
+	return w.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+}
+
+func newTestWizard() *SetupWizardView {
+	w := NewSetupWizardView(config.Default())
+	w.SetSize(100, 40)
+	return w
+}
+
+func TestSetupWizard_WalkThrough(t *testing.T) {
+	w := newTestWizard()
+
+	wizardKey(w, "enter")
+	if w.step != wizardStepDiscover {
+		t.Fatalf("Expected the discovery step, got %d", w.step)
+	}
+
+	// Skipping discovery goes straight to the host
+	wizardKey(w, "enter")
+	if w.step != wizardStepHost {
+		t.Fatalf("Expected the host step, got %d", w.step)
+	}
+	wizardKey(w, "enter")
+	if w.err == nil {
+		t.Error("Expected an empty host to be rejected")
+	}
+	wizardKey(w, "ldap.example.com")
+	wizardKey(w, "enter")
+
+	// Choosing LDAPS moves the default port along
+	wizardKey(w, "down")
+	if w.portInput.Value() != "636" {
+		t.Errorf("Expected LDAPS to default to port 636, got %s", w.portInput.Value())
+	}
+	wizardKey(w, "enter")
+	if w.step != wizardStepCertificate {
+		t.Fatalf("Expected the certificate step for LDAPS, got %d", w.step)
+	}
+
+	// Verification is on by default, against a CA file that must exist
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	w.caCertInput.SetValue(caPath)
+	wizardKey(w, "enter")
+	if w.step != wizardStepCertificate || w.err == nil || !strings.Contains(w.err.Error(), "can't be read") {
+		t.Fatalf("Expected a missing CA file to be rejected, got %v", w.err)
+	}
+	if err := os.WriteFile(caPath, []byte("-----BEGIN CERTIFICATE-----\n"), 0600); err != nil {
+		t.Fatalf("Failed to write the CA file: %v", err)
+	}
+	wizardKey(w, "enter")
+	if w.step != wizardStepBind {
+		t.Fatalf("Expected the credentials step, got %d", w.step)
+	}
+
+	wizardKey(w, "cn=admin,dc=example,dc=com")
+	wizardKey(w, "tab")
+	wizardKey(w, "secret")
+	if strings.Contains(w.View(), "secret") {
+		t.Error("Expected the password to be masked")
+	}
+	_, cmd := wizardKey(w, "enter")
+	if w.step != wizardStepBaseDN || cmd == nil || !w.busy {
+		t.Fatal("Expected base DN detection to start")
+	}
+
+	// Keys are ignored while detection runs
+	wizardKey(w, "esc")
+	if w.step != wizardStepBaseDN {
+		t.Error("Expected the step to stay while busy")
+	}
+
+	w.Update(wizardBaseDNMsg{Contexts: []string{"dc=example,dc=com", "o=other"}})
+	if w.baseDNInput.Value() != "dc=example,dc=com" {
+		t.Errorf("Expected the suggested base DN, got %q", w.baseDNInput.Value())
+	}
+	wizardKey(w, "down")
+	if w.baseDNInput.Value() != "o=other" {
+		t.Errorf("Expected choosing another naming context, got %q", w.baseDNInput.Value())
+	}
+	wizardKey(w, "up")
+	wizardKey(w, "enter")
+
+	view := w.View()
+	for _, want := range []string{"Review", "ldap.example.com", "636", "LDAPS", "verified against " + caPath, "dc=example,dc=com"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected review to show %q", want)
+		}
+	}
+
+	result, _ := wizardKey(w, "enter")
+	if result != wizardFinished {
+		t.Fatalf("Expected the wizard to finish, got %d", result)
+	}
+
+	ldapCfg := w.config.LDAP
+	if ldapCfg.Host != "ldap.example.com" || ldapCfg.Port != 636 || !ldapCfg.UseSSL || ldapCfg.UseTLS ||
+		ldapCfg.BindUser != "cn=admin,dc=example,dc=com" || ldapCfg.BindPass != "secret" || ldapCfg.BaseDN != "dc=example,dc=com" ||
+		!ldapCfg.VerifyTLS || ldapCfg.CACertPath != caPath {
+		t.Errorf("Expected settings to be applied, got %+v", ldapCfg)
+	}
+}
+
+func TestSetupWizard_Certificate(t *testing.T) {
+	// Without encryption there is no certificate to verify
+	w := newTestWizard()
+	w.hostInput.SetValue("ldap.example.com")
+	w.setStep(wizardStepSecurity)
+	w.selectSecurity(2)
+	wizardKey(w, "enter")
+	if w.step != wizardStepBind {
+		t.Fatalf("Expected plaintext to skip the certificate step, got %d", w.step)
+	}
+	wizardKey(w, "esc")
+	if w.step != wizardStepSecurity {
+		t.Errorf("Expected Esc to return to the security step, got %d", w.step)
+	}
+	if conn := w.connection(); conn.VerifyTLS {
+		t.Error("Expected a plaintext connection not to verify certificates")
+	}
+
+	// Turning verification off drops the CA path, and the probe made to
+	// detect the base DN uses the same settings as connecting
+	w.selectSecurity(0)
+	wizardKey(w, "enter")
+	w.caCertInput.SetValue("/nonexistent/ca.pem")
+	wizardKey(w, "down")
+	wizardKey(w, "enter")
+	if w.step != wizardStepBind {
+		t.Fatalf("Expected the CA path to be ignored without verification, got step %d (%v)", w.step, w.err)
+	}
+	w.config.Throttle.OperationsPerSecond = 5
+	probe := ClientConfig(w.config, w.connection())
+	if probe.VerifyTLS || probe.CACertPath != "" || !probe.UseTLS || probe.OperationsPerSecond != 5 {
+		t.Errorf("Expected the probe settings to match the connection, got %+v", probe)
+	}
+	w.setStep(wizardStepReview)
+	if view := w.View(); !strings.Contains(view, "not verified") {
+		t.Errorf("Expected the review to show the certificate isn't verified, got:\n%s", view)
+	}
+}
+
+func TestSetupWizard_Discovery(t *testing.T) {
+	w := newTestWizard()
+	wizardKey(w, "enter")
+	wizardKey(w, "example.com")
+
+	_, cmd := wizardKey(w, "enter")
+	if cmd == nil || !w.busy {
+		t.Fatal("Expected a lookup to start")
+	}
+
+	w.Update(wizardDiscoveryMsg{Domain: "example.com", Servers: []ldap.DiscoveredServer{
+		{Host: "dc1.example.com", Port: 636, UseSSL: true},
+		{Host: "dc2.example.com", Port: 389},
+	}})
+	if !strings.Contains(w.View(), "ldaps://dc1.example.com:636") {
+		t.Error("Expected discovered servers to be listed")
+	}
+
+	wizardKey(w, "down")
+	wizardKey(w, "enter")
+	if w.step != wizardStepHost || w.hostInput.Value() != "dc2.example.com" {
+		t.Fatalf("Expected the selected server to fill the host, got %q", w.hostInput.Value())
+	}
+	if w.security != 0 || w.portInput.Value() != "389" {
+		t.Errorf("Expected StartTLS on port 389 for an _ldap server, got %d/%s", w.security, w.portInput.Value())
+	}
+}
+
+func TestSetupWizard_DiscoveryFailure(t *testing.T) {
+	w := newTestWizard()
+	wizardKey(w, "enter")
+	wizardKey(w, "example.com")
+	wizardKey(w, "enter")
+
+	w.Update(wizardDiscoveryMsg{Domain: "example.com", Err: errors.New("no LDAP servers found for example.com")})
+	if w.busy || !strings.Contains(w.View(), "no LDAP servers found") {
+		t.Error("Expected the lookup error to be shown")
+	}
+
+	// Clearing the domain continues without discovery
+	w.domainInput.SetValue("")
+	wizardKey(w, "enter")
+	if w.step != wizardStepHost {
+		t.Errorf("Expected the host step, got %d", w.step)
+	}
+}
+
+func TestSetupWizard_BaseDNDetectionFailure(t *testing.T) {
+	w := newTestWizard()
+	w.setStep(wizardStepBaseDN)
+	w.busy = true
+
+	w.Update(wizardBaseDNMsg{Err: errors.New("connection refused")})
+	if !strings.Contains(w.View(), "connection refused") {
+		t.Error("Expected the detection error to be shown")
+	}
+
+	wizardKey(w, "enter")
+	if w.step != wizardStepBaseDN || w.err == nil {
+		t.Error("Expected an empty base DN to be rejected")
+	}
+
+	wizardKey(w, "dc=corp,dc=example")
+	wizardKey(w, "enter")
+	if w.step != wizardStepReview {
+		t.Errorf("Expected a typed base DN to be accepted, got step %d", w.step)
+	}
+}
+
+func TestSetupWizard_ReviewValidates(t *testing.T) {
+	w := newTestWizard()
+	w.hostInput.SetValue("ldap.example.com")
+	w.baseDNInput.SetValue("dc=example,dc=com")
+	w.bindUserInput.SetValue("admin")
+	w.setStep(wizardStepReview)
+
+	result, _ := wizardKey(w, "enter")
+	if result != wizardRunning || w.err == nil || !strings.Contains(w.err.Error(), "should be a DN") {
+		t.Errorf("Expected validation to block saving, got %v", w.err)
+	}
+}
+
+func TestSetupWizard_BackAndSkip(t *testing.T) {
+	w := newTestWizard()
+	wizardKey(w, "enter")
+	wizardKey(w, "enter")
+
+	wizardKey(w, "esc")
+	if w.step != wizardStepDiscover {
+		t.Errorf("Expected Esc to go back a step, got %d", w.step)
+	}
+
+	if result, _ := wizardKey(w, "ctrl+x"); result != wizardSkipped {
+		t.Error("Expected Ctrl+X to skip the wizard")
+	}
+
+	w = newTestWizard()
+	if result, _ := wizardKey(w, "esc"); result != wizardSkipped {
+		t.Error("Expected Esc on the welcome page to skip the wizard")
+	}
+}
+
+func TestOrderNamingContexts(t *testing.T) {
+	caps := &ldap.Capabilities{
+		NamingContexts:       []string{"CN=Configuration,DC=corp,DC=example", "DC=corp,DC=example"},
+		DefaultNamingContext: "DC=corp,DC=example",
+	}
+	got := orderNamingContexts(caps)
+	if len(got) != 2 || got[0] != "DC=corp,DC=example" {
+		t.Errorf("Expected the default naming context first, got %v", got)
+	}
+	if orderNamingContexts(&ldap.Capabilities{}) != nil {
+		t.Error("Expected no contexts when none are advertised")
+	}
+}
+
+func TestModel_SetupWizard(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	model := NewModelWithUpdateCheckAndConfigPath(nil, config.Default(), false, configPath)
+	model.Init()
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model.ShowSetupWizard()
+
+	if !strings.Contains(model.View(), "Welcome to moribito") {
+		t.Error("Expected the wizard to replace the start view")
+	}
+
+	// Global shortcuts go to the wizard
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if model.quitting {
+		t.Error("Expected q not to quit while the wizard is open")
+	}
+
+	model.wizard.hostInput.SetValue("ldap.example.com")
+	model.wizard.baseDNInput.SetValue("dc=example,dc=com")
+	model.wizard.setStep(wizardStepReview)
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.wizard != nil || cmd == nil {
+		t.Fatal("Expected finishing the wizard to close it and start connecting")
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil || !strings.Contains(string(data), "ldap.example.com") {
+		t.Errorf("Expected settings to be saved, got %q, %v", data, err)
+	}
+}
+
+func TestModel_SetupWizardSkip(t *testing.T) {
+	model := NewModelWithUpdateCheckAndConfigPath(nil, config.Default(), false, filepath.Join(t.TempDir(), "config.yaml"))
+	model.Init()
+	model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model.ShowSetupWizard()
+
+	model.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	if model.wizard != nil || model.currentView != ViewModeStart {
+		t.Error("Expected skipping to show the start view's config form")
+	}
+}
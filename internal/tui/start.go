@@ -2,13 +2,15 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ericschmar/moribito/internal/config"
 	"github.com/ericschmar/moribito/internal/ldap"
@@ -17,24 +19,36 @@ import (
 
 // StartView provides the start page with configuration editing
 type StartView struct {
-	config     *config.Config
-	configPath string // Path to config file for saving changes
-	width      int
-	height     int
-	cursor     int
-	editing    bool
+	config       *config.Config
+	configPath   string // Path to config file for saving changes
+	width        int
+	height       int
+	cursor       int
+	editing      bool
 	editingField int
-	textInput  textinput.Model // Text input for editing fields
-	container  *ViewContainer
+	textInput    textinput.Model // Text input for editing fields
+	container    *ViewContainer
 
 	// Connection management state
-	connectionCursor        int    // Which saved connection is highlighted
-	showNewConnectionDialog bool   // Whether to show new connection name dialog
+	connectionCursor        int             // Which saved connection is highlighted
+	showNewConnectionDialog bool            // Whether to show new connection name dialog
 	newConnInput            textinput.Model // Text input for new connection name
 
 	// Error tracking
 	saveError     error     // Last save error
 	saveErrorTime time.Time // When the error occurred
+	valueError    error     // Why the value being edited can't be saved
+
+	// Config validation warnings found on startup
+	configWarnings     []string
+	configWarningsTime time.Time
+
+	// Problems with the connection settings found when Connect was pressed
+	connectProblems []string
+
+	// Warning before a bind that would send the password in clear text
+	plaintextPrompt   *plaintextBindPrompt
+	plaintextAccepted map[string]bool // Keys of the servers and accounts accepted this session
 }
 
 // Field indices for editing
@@ -52,6 +66,7 @@ const (
 	FieldBaseDN
 	FieldUseSSL
 	FieldUseTLS
+	FieldBindMethod
 	FieldBindUser
 	FieldBindPass
 	FieldPageSize
@@ -64,6 +79,7 @@ type fieldConfig struct {
 	name        string
 	placeholder string
 	isBool      bool
+	options     []string // Values cycled through by a choice field
 	isPassword  bool
 	isHeader    bool // For section headers
 	isAction    bool // For clickable actions
@@ -82,6 +98,7 @@ var fields = []fieldConfig{
 	{name: "Base DN", placeholder: "dc=example,dc=com"},
 	{name: "Use SSL", isBool: true},
 	{name: "Use TLS", isBool: true},
+	{name: "Bind Method", options: []string{ldap.BindMethodSimple, ldap.BindMethodGSSAPI}},
 	{name: "Bind User", placeholder: "cn=admin,dc=example,dc=com"},
 	{name: "Bind Password", isPassword: true},
 	{name: "Page Size", placeholder: "100"},
@@ -227,6 +244,12 @@ func NewStartViewWithConfigPath(cfg *config.Config, configPath string) *StartVie
 		newConnInput: newConnInput,
 	}
 
+	// Repair any problems in the loaded config and remember what was fixed
+	if warnings := cfg.ValidateAndRepair(); len(warnings) > 0 {
+		sv.configWarnings = warnings
+		sv.configWarningsTime = time.Now()
+	}
+
 	return sv
 }
 
@@ -246,6 +269,9 @@ func (sv *StartView) SetSize(width, height int) {
 func (sv *StartView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if sv.plaintextPrompt != nil {
+			return sv.handlePlaintextBindKey(msg)
+		}
 		if sv.showNewConnectionDialog {
 			return sv.handleNewConnectionDialog(msg)
 		}
@@ -312,6 +338,11 @@ func (sv *StartView) getFieldValue(field int) string {
 		return strconv.FormatBool(sv.config.LDAP.UseSSL)
 	case FieldUseTLS:
 		return strconv.FormatBool(sv.config.LDAP.UseTLS)
+	case FieldBindMethod:
+		if sv.config.LDAP.BindMethod == "" {
+			return ldap.BindMethodSimple
+		}
+		return sv.config.LDAP.BindMethod
 	case FieldBindUser:
 		return sv.config.LDAP.BindUser
 	case FieldBindPass:
@@ -374,6 +405,9 @@ func (sv *StartView) View() string {
 	if sv.showNewConnectionDialog {
 		return sv.renderNewConnectionDialog()
 	}
+	if sv.plaintextPrompt != nil {
+		return sv.renderPlaintextBindDialog()
+	}
 
 	return sv.container.RenderWithPadding(sv.renderConfigPane(contentWidth))
 }
@@ -508,7 +542,7 @@ func (sv *StartView) renderHeaderField(field int) string {
 		configPathText := placeholderStyle.Render(fmt.Sprintf("  Config: %s", sv.configPath))
 		return headerText + "\n" + configPathText
 	} else if field == FieldConnectionHeader && sv.configPath == "" {
-		warningText := errorStyle.Render("  ⚠ Config file not set - changes will not persist")
+		warningText := errorStyle.Render("  " + iconWarning.String() + " Config file not set - changes will not persist")
 		return headerText + "\n" + warningText
 	}
 
@@ -552,6 +586,10 @@ func (sv *StartView) renderConnectionList() string {
 		}
 
 		connLine := fmt.Sprintf("%s%s (%s)", indicator, conn.Name, conn.Host)
+		if conn.Source != "" {
+			// Kept in its own file, which the start view doesn't change
+			connLine += " • " + filepath.Join(config.ConnectionsDirName, filepath.Base(conn.Source)) + " (read-only)"
+		}
 		if i == sv.connectionCursor && sv.cursor == FieldConnectionList {
 			connLine = selectedConnectionStyle.Render(connLine)
 		}
@@ -563,6 +601,9 @@ func (sv *StartView) renderConnectionList() string {
 
 // renderEditingField renders the field currently being edited
 func (sv *StartView) renderEditingField() string {
+	if len(fields[sv.editingField].options) > 0 {
+		return fmt.Sprintf("%s (press Space or ←→ to change)", sv.getFieldValue(sv.editingField))
+	}
 	// For boolean fields, show toggle instructions instead of text input
 	if fields[sv.editingField].isBool {
 		currentValue := sv.getFieldValue(sv.editingField)
@@ -578,14 +619,32 @@ func (sv *StartView) renderInstructions() string {
 
 	// Show error message if there is one and it's recent (within last 5 seconds)
 	if sv.saveError != nil && time.Since(sv.saveErrorTime) < 5*time.Second {
-		errorMsg := fmt.Sprintf("⚠ %s", sv.saveError.Error())
+		errorMsg := fmt.Sprintf("%s %s", iconWarning, sv.saveError.Error())
 		parts = append(parts, errorStyle.Render(errorMsg))
 	}
 
+	// Show config warnings for a while after startup
+	if len(sv.configWarnings) > 0 && time.Since(sv.configWarningsTime) < 10*time.Second {
+		for _, warning := range sv.configWarnings {
+			parts = append(parts, errorStyle.Render(fmt.Sprintf("%s %s", iconWarning, warning)))
+		}
+	}
+
+	if sv.editing && sv.valueError != nil {
+		parts = append(parts, errorStyle.Render(fmt.Sprintf("%s %s", iconFailed, sv.valueError.Error())))
+	}
+
+	// Show connection problems until the next connect attempt
+	for _, problem := range sv.connectProblems {
+		parts = append(parts, errorStyle.Render(fmt.Sprintf("%s %s", iconFailed, problem)))
+	}
+
 	// Show regular instructions
 	var instructions string
 	if sv.editing {
-		if fields[sv.editingField].isBool {
+		if len(fields[sv.editingField].options) > 0 {
+			instructions = "Press [Space] or [←→] to change • [Enter] or [Esc] to finish"
+		} else if fields[sv.editingField].isBool {
 			instructions = "Press [Space] to toggle • [Y/N] or [T/F] to set • [Enter] or [Esc] to finish"
 		} else {
 			instructions = "Press [Enter] to save • [Esc] to cancel • Arrow keys to navigate • Cmd+V to paste"
@@ -623,11 +682,42 @@ func (sv *StartView) renderNewConnectionDialog() string {
 
 // IsEditing returns true if the start view is currently in editing mode
 func (sv *StartView) IsEditing() bool {
-	return sv.editing || sv.showNewConnectionDialog
+	return sv.editing || sv.showNewConnectionDialog || sv.plaintextPrompt != nil
+}
+
+// HasUnsavedChanges reports whether a setting or new connection is being
+// entered and hasn't been saved yet
+func (sv *StartView) HasUnsavedChanges() bool {
+	return sv.IsEditing()
 }
 
 // handleEditMode handles input when editing a configuration value
 func (sv *StartView) handleEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Choice fields cycle through their options
+	if options := fields[sv.editingField].options; len(options) > 0 {
+		step := 0
+		switch msg.String() {
+		case "enter", "esc":
+			sv.editing = false
+			return sv, nil
+		case " ", "right", "l":
+			step = 1
+		case "left", "h":
+			step = len(options) - 1
+		}
+		if step != 0 {
+			current := 0
+			for i, option := range options {
+				if option == sv.getFieldValue(sv.editingField) {
+					current = i
+				}
+			}
+			sv.setFieldOption(sv.editingField, options[(current+step)%len(options)])
+			sv.saveConfigToDisk()
+		}
+		return sv, nil
+	}
+
 	// Handle boolean fields differently - use toggles instead of text input
 	if fields[sv.editingField].isBool {
 		switch msg.String() {
@@ -651,9 +741,9 @@ func (sv *StartView) handleEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Update the config value directly
 			switch sv.editingField {
 			case FieldUseSSL:
-				sv.config.LDAP.UseSSL = newValue
+				sv.setUseSSL(newValue)
 			case FieldUseTLS:
-				sv.config.LDAP.UseTLS = newValue
+				sv.setUseTLS(newValue)
 			}
 
 			// Save the configuration to disk
@@ -665,11 +755,16 @@ func (sv *StartView) handleEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Handle regular fields with textinput
 	switch msg.String() {
 	case "enter":
+		if err := sv.validateValue(); err != nil {
+			sv.valueError = err
+			return sv, nil
+		}
 		sv.saveValue()
 		sv.editing = false
 		return sv, nil
 
 	case "esc":
+		sv.valueError = nil
 		sv.editing = false
 		return sv, nil
 
@@ -681,6 +776,51 @@ func (sv *StartView) handleEditMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// setFieldOption stores the chosen value of a choice field
+func (sv *StartView) setFieldOption(field int, value string) {
+	switch field {
+	case FieldBindMethod:
+		// Simple binds are the default and are left out of the config file
+		if value == ldap.BindMethodSimple {
+			value = ""
+		}
+		sv.config.LDAP.BindMethod = value
+	}
+}
+
+// setUseSSL sets whether to connect with LDAPS, turning StartTLS off when
+// it is enabled since the two can't be combined
+func (sv *StartView) setUseSSL(enabled bool) {
+	sv.config.LDAP.UseSSL = enabled
+	if enabled {
+		sv.config.LDAP.UseTLS = false
+	}
+}
+
+// setUseTLS sets whether to upgrade the connection with StartTLS, turning
+// LDAPS off when it is enabled
+func (sv *StartView) setUseTLS(enabled bool) {
+	sv.config.LDAP.UseTLS = enabled
+	if enabled {
+		sv.config.LDAP.UseSSL = false
+	}
+}
+
+// validateValue checks the edited value before it is saved, normalizing it
+// in place where it has a canonical form
+func (sv *StartView) validateValue() error {
+	switch sv.editingField {
+	case FieldBaseDN:
+		normalized, err := ldap.NormalizeDN(sv.textInput.Value())
+		if err != nil {
+			return err
+		}
+		sv.textInput.SetValue(normalized)
+	}
+	sv.valueError = nil
+	return nil
+}
+
 // saveValue saves the edited value to the config
 func (sv *StartView) saveValue() {
 	inputValue := sv.textInput.Value()
@@ -696,11 +836,11 @@ func (sv *StartView) saveValue() {
 		sv.config.LDAP.BaseDN = inputValue
 	case FieldUseSSL:
 		if useSSL, err := strconv.ParseBool(inputValue); err == nil {
-			sv.config.LDAP.UseSSL = useSSL
+			sv.setUseSSL(useSSL)
 		}
 	case FieldUseTLS:
 		if useTLS, err := strconv.ParseBool(inputValue); err == nil {
-			sv.config.LDAP.UseTLS = useTLS
+			sv.setUseTLS(useTLS)
 		}
 	case FieldBindUser:
 		sv.config.LDAP.BindUser = inputValue
@@ -709,6 +849,10 @@ func (sv *StartView) saveValue() {
 	case FieldPageSize:
 		if pageSize, err := strconv.Atoi(inputValue); err == nil && pageSize > 0 {
 			sv.config.Pagination.PageSize = uint32(pageSize)
+			if warning := sv.config.Pagination.ClampPageSize(); warning != "" {
+				sv.configWarnings = []string{warning}
+				sv.configWarningsTime = time.Now()
+			}
 		}
 	}
 
@@ -734,6 +878,30 @@ func (sv *StartView) saveConfigToDisk() {
 	}
 }
 
+// markActiveConnectionUsed stamps the selected saved connection with the
+// current time and persists it, so recent connections can be listed first
+func (sv *StartView) markActiveConnectionUsed() {
+	index := sv.config.LDAP.SelectedConnection
+	if index < 0 || index >= len(sv.config.LDAP.SavedConnections) {
+		return
+	}
+	sv.config.MarkConnectionUsed(index, time.Now())
+	sv.saveConfigToDisk()
+}
+
+// refuseIncludedChange tells the user that the saved connection at index
+// can't be saved or deleted when it was read from the connections directory,
+// whose files moribito leaves as they are written. It returns nil for the
+// config file's own connections.
+func (sv *StartView) refuseIncludedChange(index int) tea.Cmd {
+	conn := sv.config.LDAP.SavedConnections[index]
+	if conn.Source == "" {
+		return nil
+	}
+	file := filepath.Join(config.ConnectionsDirName, filepath.Base(conn.Source))
+	return SendStatus(fmt.Sprintf("%s is read-only here; edit %s to change it", conn.Name, file))
+}
+
 // handleFieldAction handles enter key press on different field types
 func (sv *StartView) handleFieldAction() (tea.Model, tea.Cmd) {
 	fieldCfg := fields[sv.cursor]
@@ -750,17 +918,25 @@ func (sv *StartView) handleFieldAction() (tea.Model, tea.Cmd) {
 	case FieldSaveConnection:
 		// Save current settings to the currently selected connection
 		if len(sv.config.LDAP.SavedConnections) > 0 && sv.config.LDAP.SelectedConnection >= 0 && sv.config.LDAP.SelectedConnection < len(sv.config.LDAP.SavedConnections) {
-			// Update the currently selected saved connection with current settings
-			updated := config.SavedConnection{
-				Name:     sv.config.LDAP.SavedConnections[sv.config.LDAP.SelectedConnection].Name,
-				Host:     sv.config.LDAP.Host,
-				Port:     sv.config.LDAP.Port,
-				BaseDN:   sv.config.LDAP.BaseDN,
-				UseSSL:   sv.config.LDAP.UseSSL,
-				UseTLS:   sv.config.LDAP.UseTLS,
-				BindUser: sv.config.LDAP.BindUser,
-				BindPass: sv.config.LDAP.BindPass,
+			if cmd := sv.refuseIncludedChange(sv.config.LDAP.SelectedConnection); cmd != nil {
+				return sv, cmd
 			}
+			// Update the currently selected saved connection with current
+			// settings, keeping what the form doesn't edit such as LastUsed
+			updated := sv.config.LDAP.SavedConnections[sv.config.LDAP.SelectedConnection]
+			updated.Host = sv.config.LDAP.Host
+			updated.Port = sv.config.LDAP.Port
+			updated.BaseDN = sv.config.LDAP.BaseDN
+			updated.UseSSL = sv.config.LDAP.UseSSL
+			updated.UseTLS = sv.config.LDAP.UseTLS
+			updated.BindUser = sv.config.LDAP.BindUser
+			updated.BindPass = sv.config.LDAP.BindPass
+			updated.VerifyTLS = sv.config.LDAP.VerifyTLS
+			updated.CACertPath = sv.config.LDAP.CACertPath
+			updated.BindMethod = sv.config.LDAP.BindMethod
+			updated.KerberosRealm = sv.config.LDAP.KerberosRealm
+			updated.KerberosKeytab = sv.config.LDAP.KerberosKeytab
+			updated.KerberosConfig = sv.config.LDAP.KerberosConfig
 			sv.config.UpdateSavedConnection(sv.config.LDAP.SelectedConnection, updated)
 			sv.saveConfigToDisk()
 		} else {
@@ -774,6 +950,9 @@ func (sv *StartView) handleFieldAction() (tea.Model, tea.Cmd) {
 	case FieldDeleteConnection:
 		// Delete the currently selected saved connection
 		if len(sv.config.LDAP.SavedConnections) > 0 && sv.connectionCursor < len(sv.config.LDAP.SavedConnections) {
+			if cmd := sv.refuseIncludedChange(sv.connectionCursor); cmd != nil {
+				return sv, cmd
+			}
 			sv.config.RemoveSavedConnection(sv.connectionCursor)
 			if sv.connectionCursor >= len(sv.config.LDAP.SavedConnections) && len(sv.config.LDAP.SavedConnections) > 0 {
 				sv.connectionCursor = len(sv.config.LDAP.SavedConnections) - 1
@@ -793,6 +972,7 @@ func (sv *StartView) handleFieldAction() (tea.Model, tea.Cmd) {
 		if !fieldCfg.isHeader && !fieldCfg.isSeparator && !fieldCfg.isAction {
 			sv.editing = true
 			sv.editingField = sv.cursor
+			sv.valueError = nil
 
 			// Initialize textinput with current value
 			sv.textInput.SetValue(sv.getFieldValue(sv.cursor))
@@ -831,6 +1011,14 @@ func (sv *StartView) handleNewConnectionDialog(msg tea.KeyMsg) (tea.Model, tea.C
 				UseTLS:   sv.config.LDAP.UseTLS,
 				BindUser: sv.config.LDAP.BindUser,
 				BindPass: sv.config.LDAP.BindPass,
+
+				VerifyTLS:  sv.config.LDAP.VerifyTLS,
+				CACertPath: sv.config.LDAP.CACertPath,
+
+				BindMethod:     sv.config.LDAP.BindMethod,
+				KerberosRealm:  sv.config.LDAP.KerberosRealm,
+				KerberosKeytab: sv.config.LDAP.KerberosKeytab,
+				KerberosConfig: sv.config.LDAP.KerberosConfig,
 			}
 			sv.config.AddSavedConnection(newConn)
 
@@ -856,69 +1044,123 @@ func (sv *StartView) handleNewConnectionDialog(msg tea.KeyMsg) (tea.Model, tea.C
 	}
 }
 
+// showAuthenticationRequired explains that the server refused an anonymous
+// connection and moves the cursor to the bind user to set one
+func (sv *StartView) showAuthenticationRequired(explanation ldap.Explanation) {
+	sv.connectProblems = []string{explanation.Summary + ". " + explanation.Fix}
+	sv.cursor = FieldBindUser
+}
+
 // handleConnect attempts to create an LDAP connection with current settings
 func (sv *StartView) handleConnect() (tea.Model, tea.Cmd) {
 	activeConn := sv.config.GetActiveConnection()
 
-	// Validate required fields
-	if activeConn.Host == "" {
-		return sv, func() tea.Msg {
-			return StatusMsg{Message: "Error: LDAP host is required"}
+	// Validate the settings, reporting every problem before connecting
+	sv.connectProblems = activeConn.Validate()
+	if len(sv.connectProblems) > 0 {
+		message := "Error: " + sv.connectProblems[0]
+		if len(sv.connectProblems) > 1 {
+			message = fmt.Sprintf("Error: %d connection settings need fixing", len(sv.connectProblems))
 		}
-	}
-	if activeConn.BaseDN == "" {
 		return sv, func() tea.Msg {
-			return StatusMsg{Message: "Error: Base DN is required"}
+			return StatusMsg{Message: message, Level: ToastError}
 		}
 	}
 
-	// Return command that will attempt connection in background
-	return sv, func() tea.Msg {
-		// Create LDAP configuration
-		ldapConfig := ldap.Config{
-			Host:           activeConn.Host,
-			Port:           activeConn.Port,
-			BaseDN:         activeConn.BaseDN,
-			UseSSL:         activeConn.UseSSL,
-			UseTLS:         activeConn.UseTLS,
-			BindUser:       activeConn.BindUser,
-			BindPass:       activeConn.BindPass,
-			RetryEnabled:   sv.config.Retry.Enabled,
-			MaxRetries:     sv.config.Retry.MaxAttempts,
-			InitialDelayMs: sv.config.Retry.InitialDelayMs,
-			MaxDelayMs:     sv.config.Retry.MaxDelayMs,
+	// Passwords kept out of the config file are asked for on each run
+	if sv.config.LDAP.NeverSavePasswords && !activeConn.Complete() {
+		sv.cursor = FieldBindPass
+		sv.handleFieldAction()
+		return sv, SendStatus("Enter the bind password for this session, then connect; it isn't saved")
+	}
+
+	// Attempt the connection in the background
+	ldapConfig := ClientConfig(sv.config, activeConn)
+	connect := func() tea.Msg {
+		client, err := connectWithTimeout(ldapConfig, connectTimeout)
+		if errors.Is(err, errConnectTimeout) {
+			return StatusMsg{Message: "Connection timeout after 5 seconds", Level: ToastError}
+		}
+		if explanation, ok := ldap.ExplainAnonymousBind(err, ldapConfig); ok {
+			return AuthenticationRequiredMsg{Explanation: explanation}
+		}
+		if err != nil {
+			return StatusMsg{Message: fmt.Sprintf("Connection failed: %v", ldap.Explain(err)), Level: ToastError}
 		}
+		return ConnectMsg{
+			Client: client,
+			Config: sv.config,
+		}
+	}
+
+	// Sending the password unencrypted has to be accepted first
+	if sv.needsPlaintextConfirmation(activeConn) {
+		sv.plaintextPrompt = &plaintextBindPrompt{connection: activeConn, connect: connect}
+		return sv, nil
+	}
+	return sv, connect
+}
+
+// ClientConfig returns the client settings for a connection, along with the
+// retry, throttle and audit settings cfg shares between all connections
+func ClientConfig(cfg *config.Config, conn config.LDAPConnection) ldap.Config {
+	return ldap.Config{
+		Host:                conn.Host,
+		Port:                conn.Port,
+		BaseDN:              conn.BaseDN,
+		UseSSL:              conn.UseSSL,
+		UseTLS:              conn.UseTLS,
+		VerifyTLS:           conn.VerifyTLS,
+		CACertPath:          conn.CACertPath,
+		BindUser:            conn.BindUser,
+		BindPass:            conn.BindPass,
+		BindMethod:          conn.BindMethod,
+		KerberosRealm:       conn.KerberosRealm,
+		KerberosKeytab:      conn.KerberosKeytab,
+		KerberosConfig:      conn.KerberosConfig,
+		RetryEnabled:        cfg.Retry.Enabled,
+		MaxRetries:          cfg.Retry.MaxAttempts,
+		InitialDelayMs:      cfg.Retry.InitialDelayMs,
+		MaxDelayMs:          cfg.Retry.MaxDelayMs,
+		OperationsPerSecond: cfg.Throttle.Rate(),
+		DerefAliases:        cfg.LDAP.DerefAliases,
+		ReadOnly:            cfg.ReadOnly,
+		AuditLogPath:        cfg.AuditLogPath(),
+		ConnectionName:      conn.Name,
+	}
+}
 
-		// Create channel to receive result or timeout
-		resultChan := make(chan struct {
+// connectTimeout bounds how long a connection attempt may take
+const connectTimeout = 5 * time.Second
+
+// errConnectTimeout is returned by connectWithTimeout when the server doesn't answer in time
+var errConnectTimeout = errors.New("connection timed out")
+
+// connectWithTimeout creates an LDAP client, giving up after timeout
+func connectWithTimeout(ldapConfig ldap.Config, timeout time.Duration) (*ldap.Client, error) {
+	// Create channel to receive result or timeout
+	resultChan := make(chan struct {
+		client *ldap.Client
+		err    error
+	}, 1)
+
+	// Start connection attempt in goroutine
+	go func() {
+		client, err := ldap.NewClient(ldapConfig)
+		resultChan <- struct {
 			client *ldap.Client
 			err    error
-		}, 1)
-
-		// Start connection attempt in goroutine
-		go func() {
-			client, err := ldap.NewClient(ldapConfig)
-			resultChan <- struct {
-				client *ldap.Client
-				err    error
-			}{client, err}
-		}()
-
-		// Wait for result or timeout (5 seconds)
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		select {
-		case result := <-resultChan:
-			if result.err != nil {
-				return StatusMsg{Message: fmt.Sprintf("Connection failed: %v", result.err)}
-			}
-			return ConnectMsg{
-				Client: result.client,
-				Config: sv.config,
-			}
-		case <-ctx.Done():
-			return StatusMsg{Message: "Connection timeout after 5 seconds"}
-		}
+		}{client, err}
+	}()
+
+	// Wait for result or timeout
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	select {
+	case result := <-resultChan:
+		return result.client, result.err
+	case <-ctx.Done():
+		return nil, errConnectTimeout
 	}
 }
@@ -3,6 +3,7 @@ package tui
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ericschmar/moribito/internal/config"
@@ -49,6 +50,38 @@ func TestStartView_SaveConfigToDisk(t *testing.T) {
 	}
 }
 
+func TestStartView_SaveKeepsHandWrittenConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "# Managed by hand, see the wiki\nldap:\n  host: initial.example.com # old server\n  port: 389\n  base_dn: \"dc=example,dc=com\"\ninventory:\n  team: directory-services\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _, err := config.Load(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sv := NewStartViewWithConfigPath(cfg, configPath)
+	sv.editing = true
+	sv.editingField = FieldHost
+	sv.textInput.SetValue("modified.example.com")
+	sv.saveValue()
+	if sv.saveError != nil {
+		t.Fatalf("Unexpected save error: %v", sv.saveError)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := string(data)
+	for _, want := range []string{"# Managed by hand, see the wiki", "host: modified.example.com # old server", "inventory:\n  team: directory-services"} {
+		if !strings.Contains(saved, want) {
+			t.Errorf("Expected the saved config to contain %q, got:\n%s", want, saved)
+		}
+	}
+}
+
 func TestStartView_BackwardCompatibilityWithoutConfigPath(t *testing.T) {
 	// Create StartView without config path (old style)
 	cfg := config.Default()
@@ -123,5 +156,3 @@ func TestStartView_ConnectionManagementSaving(t *testing.T) {
 		t.Errorf("Expected connection name 'Test Connection', got '%s'", reloadedCfg.LDAP.SavedConnections[0].Name)
 	}
 }
-
-// Test helper - remove the keyMsg type since it's not needed
\ No newline at end of file
@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ericschmar/moribito/internal/config"
+	zone "github.com/lrstanley/bubblezone"
 )
 
 // TestStartView_ConnectButton tests that the Connect button works correctly
@@ -99,3 +100,44 @@ func TestStartView_ConnectButtonAvailable(t *testing.T) {
 		t.Error("FieldConnect index is out of bounds")
 	}
 }
+
+// TestStartView_ConnectReportsAllProblems tests that every invalid setting is listed before connecting
+func TestStartView_ConnectReportsAllProblems(t *testing.T) {
+	zone.NewGlobal()
+	cfg := config.Default()
+	cfg.LDAP.Port = 0
+	cfg.LDAP.BindUser = "admin"
+
+	sv := NewStartViewWithConfigPath(cfg, filepath.Join(t.TempDir(), "config.yaml"))
+	// Loading repairs SSL with StartTLS, so combine them afterwards
+	cfg.LDAP.UseSSL = true
+	cfg.LDAP.UseTLS = true
+	sv.width = 100
+	sv.height = 40
+	sv.cursor = FieldConnect
+
+	_, cmd := sv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected a status command")
+	}
+	statusMsg, ok := cmd().(StatusMsg)
+	if !ok || statusMsg.Message != "Error: 3 connection settings need fixing" {
+		t.Errorf("Expected a summary of the problems, got %v", cmd())
+	}
+
+	view := sv.View()
+	for _, want := range []string{"out of range", "can't both be enabled", "should be a DN"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected start view to list %q", want)
+		}
+	}
+
+	// Fixing the settings clears the problems on the next attempt
+	cfg.LDAP.Port = 636
+	cfg.LDAP.UseTLS = false
+	cfg.LDAP.BindUser = "cn=admin,dc=example,dc=com"
+	sv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(sv.connectProblems) != 0 {
+		t.Errorf("Expected problems to be cleared, got %v", sv.connectProblems)
+	}
+}
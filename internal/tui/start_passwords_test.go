@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+)
+
+// newPasswordStartView edits a connection with a password while passwords
+// are never saved, writing to a config file in a temporary directory
+func newPasswordStartView(t *testing.T) (*StartView, string) {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	cfg := config.Default()
+	cfg.LDAP.Host = "ldap.example.com"
+	cfg.LDAP.BaseDN = "dc=example,dc=com"
+	cfg.LDAP.BindUser = "cn=admin,dc=example,dc=com"
+	cfg.LDAP.BindPass = "secret"
+	cfg.LDAP.UseTLS = true
+	cfg.LDAP.NeverSavePasswords = true
+	return NewStartViewWithConfigPath(cfg, configPath), configPath
+}
+
+func loadSavedConfig(t *testing.T, configPath string) *config.Config {
+	t.Helper()
+	saved, _, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	return saved
+}
+
+func TestStartView_NewConnectionWithoutSavedPassword(t *testing.T) {
+	sv, configPath := newPasswordStartView(t)
+	sv.showNewConnectionDialog = true
+	sv.newConnInput.SetValue("work")
+	sv.handleNewConnectionDialog(tea.KeyMsg{Type: tea.KeyEnter})
+
+	saved := loadSavedConfig(t, configPath)
+	if len(saved.LDAP.SavedConnections) != 1 || saved.LDAP.SavedConnections[0].BindUser != "cn=admin,dc=example,dc=com" {
+		t.Fatalf("Expected the connection to be saved, got %+v", saved.LDAP.SavedConnections)
+	}
+	if saved.LDAP.SavedConnections[0].BindPass != "" || saved.LDAP.BindPass != "" {
+		t.Error("Expected the saved connection to have no password")
+	}
+	if sv.config.LDAP.SavedConnections[0].BindPass != "secret" || sv.config.GetActiveConnection().BindPass != "secret" {
+		t.Error("Expected the password to be kept for the session")
+	}
+}
+
+func TestStartView_SaveConnectionWithoutSavedPassword(t *testing.T) {
+	sv, configPath := newPasswordStartView(t)
+	sv.config.AddSavedConnection(config.SavedConnection{Name: "work", Host: "old.example.com"})
+	sv.config.LDAP.SelectedConnection = 0
+	sv.cursor = FieldSaveConnection
+	sv.handleFieldAction()
+
+	saved := loadSavedConfig(t, configPath)
+	if got := saved.LDAP.SavedConnections[0]; got.Host != "ldap.example.com" || got.BindPass != "" {
+		t.Errorf("Expected the updated connection without its password, got %+v", got)
+	}
+	if sv.config.LDAP.SavedConnections[0].BindPass != "secret" {
+		t.Error("Expected the password to be kept for the session")
+	}
+}
+
+func TestStartView_SaveConnectionKeepsLastUsed(t *testing.T) {
+	sv, configPath := newPasswordStartView(t)
+	used := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	sv.config.AddSavedConnection(config.SavedConnection{Name: "work", Host: "old.example.com", LastUsed: used})
+	sv.config.LDAP.SelectedConnection = 0
+	sv.config.LDAP.Host = "new.example.com"
+	sv.cursor = FieldSaveConnection
+	sv.handleFieldAction()
+
+	// Saving the form's settings doesn't reset the quick switcher's order
+	saved := loadSavedConfig(t, configPath).LDAP.SavedConnections[0]
+	if saved.Host != "new.example.com" || !saved.LastUsed.Equal(used) {
+		t.Errorf("Expected the new host with the last use kept, got %+v", saved)
+	}
+}
+
+func TestStartView_AsksForUnsavedPassword(t *testing.T) {
+	sv, _ := newPasswordStartView(t)
+	sv.config.LDAP.BindPass = ""
+	sv.cursor = FieldConnect
+
+	_, cmd := sv.handleFieldAction()
+	if !sv.editing || sv.editingField != FieldBindPass {
+		t.Fatal("Expected the password field to open for entry")
+	}
+	if msg, ok := cmd().(StatusMsg); !ok || !strings.Contains(msg.Message, "bind password") {
+		t.Errorf("Expected a prompt for the password, got %#v", cmd())
+	}
+}
+
+func TestStartView_IncludedConnectionsAreReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("ldap:\n    host: ldap.example.com\n    base_dn: dc=example,dc=com\n"), 0600); err != nil {
+		t.Fatalf("Failed to write the config: %v", err)
+	}
+	connectionsDir := config.ConnectionsDir(configPath)
+	if err := os.Mkdir(connectionsDir, 0700); err != nil {
+		t.Fatalf("Failed to create %s: %v", connectionsDir, err)
+	}
+	staging := filepath.Join(connectionsDir, "staging.yaml")
+	if err := os.WriteFile(staging, []byte("host: ldap.staging.example.com\nbase_dn: dc=staging,dc=example,dc=com\n"), 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", staging, err)
+	}
+
+	cfg := loadSavedConfig(t, configPath)
+	sv := NewStartViewWithConfigPath(cfg, configPath)
+	sv.config.SetActiveConnection(0)
+	sv.config.LDAP.Host = "edited.example.com"
+
+	// Neither saving nor deleting the connection is possible, since the
+	// change would go to the config file and be lost or duplicated there
+	for _, field := range []int{FieldSaveConnection, FieldDeleteConnection} {
+		sv.cursor = field
+		_, cmd := sv.handleFieldAction()
+		if cmd == nil {
+			t.Fatalf("Expected field %d to be refused for an included connection", field)
+		}
+		if msg, ok := cmd().(StatusMsg); !ok || !strings.Contains(msg.Message, "edit connections.d/staging.yaml to change it") {
+			t.Errorf("Expected the file to edit to be named, got %v", msg)
+		}
+	}
+	if len(sv.config.LDAP.SavedConnections) != 1 || sv.config.LDAP.SavedConnections[0].Host != "ldap.staging.example.com" {
+		t.Errorf("Expected the included connection unchanged, got %+v", sv.config.LDAP.SavedConnections)
+	}
+
+	saved := loadSavedConfig(t, configPath)
+	if warnings := saved.ValidateAndRepair(); len(warnings) != 0 {
+		t.Errorf("Expected the config to load without warnings, got %v", warnings)
+	}
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/config"
+)
+
+// plaintextBindPrompt holds a connection attempt that would send the bind
+// password unencrypted until the user accepts or cancels it
+type plaintextBindPrompt struct {
+	connection config.LDAPConnection
+	connect    tea.Cmd // Runs the connection once accepted
+}
+
+// plaintextBindKey identifies a server and account the user has accepted
+// binding to in clear text, so the warning isn't repeated on reconnects
+func plaintextBindKey(conn config.LDAPConnection) string {
+	return fmt.Sprintf("%s:%d/%s", strings.ToLower(conn.Host), conn.Port, conn.BindUser)
+}
+
+// needsPlaintextConfirmation reports whether connecting to conn must be
+// confirmed first because the password would be sent in clear text
+func (sv *StartView) needsPlaintextConfirmation(conn config.LDAPConnection) bool {
+	if !conn.PlaintextBind() || sv.config.LDAP.AllowPlaintextBind {
+		return false
+	}
+	return !sv.plaintextAccepted[plaintextBindKey(conn)]
+}
+
+// confirmingPlaintextBind reports whether the clear-text bind warning is shown
+func (sv *StartView) confirmingPlaintextBind() bool {
+	return sv.plaintextPrompt != nil
+}
+
+// handlePlaintextBindKey accepts or cancels the pending connection
+func (sv *StartView) handlePlaintextBindKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		prompt := sv.plaintextPrompt
+		sv.plaintextPrompt = nil
+		if sv.plaintextAccepted == nil {
+			sv.plaintextAccepted = make(map[string]bool)
+		}
+		sv.plaintextAccepted[plaintextBindKey(prompt.connection)] = true
+		return sv, prompt.connect
+	case "n", "N", "esc":
+		sv.plaintextPrompt = nil
+		return sv, SendStatus("Connection cancelled; enable Use SSL or Use TLS to encrypt the bind")
+	}
+	return sv, nil
+}
+
+// renderPlaintextBindDialog renders the warning shown before binding with
+// credentials over an unencrypted connection
+func (sv *StartView) renderPlaintextBindDialog() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("9")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+
+	conn := sv.plaintextPrompt.connection
+	content := strings.Join([]string{
+		titleStyle.Render(iconWarning.String() + " Unencrypted connection"),
+		"",
+		"You are binding with credentials over an unencrypted connection.",
+		fmt.Sprintf("The password of %s is sent to %s:%d in clear text,", conn.BindUser, conn.Host, conn.Port),
+		"where anyone on the network path can read it.",
+		"",
+		"Enable Use SSL or Use TLS to encrypt the connection, or set",
+		"ldap.allow_plaintext_bind in the config to skip this warning.",
+		"",
+		helpStyle.Render("[Y] connect anyway • [N/Esc] cancel"),
+	}, "\n")
+
+	style := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("15")).
+		Background(lipgloss.Color("0")).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("9")).
+		Padding(1, 2)
+
+	return sv.container.RenderCentered(style.Render(content))
+}
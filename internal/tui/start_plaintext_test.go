@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+)
+
+// newPlaintextStartView returns a start view set up to bind with a password
+// over a plain LDAP connection
+func newPlaintextStartView(t *testing.T) *StartView {
+	t.Helper()
+	cfg := config.Default()
+	cfg.LDAP.Host = "ldap.example.com"
+	cfg.LDAP.Port = 389
+	cfg.LDAP.BaseDN = "dc=example,dc=com"
+	cfg.LDAP.BindUser = "cn=admin,dc=example,dc=com"
+	cfg.LDAP.BindPass = "secret"
+
+	sv := NewStartViewWithConfigPath(cfg, filepath.Join(t.TempDir(), "config.yaml"))
+	sv.SetSize(100, 40)
+	sv.cursor = FieldConnect
+	return sv
+}
+
+func TestStartView_PlaintextBindWarning(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *config.Config)
+		warn   bool
+	}{
+		{"password over plain LDAP", func(cfg *config.Config) {}, true},
+		{"StartTLS", func(cfg *config.Config) { cfg.LDAP.UseTLS = true }, false},
+		{"SSL", func(cfg *config.Config) { cfg.LDAP.UseSSL, cfg.LDAP.Port = true, 636 }, false},
+		{"anonymous", func(cfg *config.Config) { cfg.LDAP.BindUser, cfg.LDAP.BindPass = "", "" }, false},
+		{"opted out", func(cfg *config.Config) { cfg.LDAP.AllowPlaintextBind = true }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv := newPlaintextStartView(t)
+			tt.modify(sv.config)
+
+			_, cmd := sv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			if sv.confirmingPlaintextBind() != tt.warn {
+				t.Fatalf("Expected warning shown = %v", tt.warn)
+			}
+			if tt.warn && cmd != nil {
+				t.Error("Expected no connection attempt until the warning is accepted")
+			}
+			if !tt.warn && cmd == nil {
+				t.Error("Expected the connection to be attempted")
+			}
+		})
+	}
+}
+
+func TestStartView_PlaintextBindAccept(t *testing.T) {
+	sv := newPlaintextStartView(t)
+	sv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := sv.View(); !strings.Contains(view, "You are binding with credentials over an unencrypted connection") {
+		t.Errorf("Expected the warning dialog, got:\n%s", view)
+	}
+
+	// Enter alone doesn't accept, so the warning isn't skipped by habit
+	if _, cmd := sv.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !sv.confirmingPlaintextBind() {
+		t.Fatal("Expected Enter to leave the warning up")
+	}
+
+	_, cmd := sv.Update(keyRune('y'))
+	if cmd == nil || sv.confirmingPlaintextBind() {
+		t.Fatal("Expected Y to close the warning and connect")
+	}
+
+	// Once accepted, connecting to the same server and account doesn't ask again
+	if _, cmd := sv.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || sv.confirmingPlaintextBind() {
+		t.Error("Expected the accepted connection not to warn again")
+	}
+	sv.config.LDAP.BindUser = "cn=other,dc=example,dc=com"
+	if sv.Update(tea.KeyMsg{Type: tea.KeyEnter}); !sv.confirmingPlaintextBind() {
+		t.Error("Expected another account to be warned about")
+	}
+}
+
+func TestStartView_PlaintextBindCancel(t *testing.T) {
+	sv := newPlaintextStartView(t)
+	sv.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	_, cmd := sv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil || sv.confirmingPlaintextBind() {
+		t.Fatal("Expected Esc to close the warning")
+	}
+	if msg, ok := cmd().(StatusMsg); !ok || !strings.Contains(msg.Message, "Connection cancelled") {
+		t.Errorf("Expected a cancelled status, got %#v", cmd())
+	}
+}
+
+func TestModel_PlaintextBindWarningOutsideStartView(t *testing.T) {
+	sv := newPlaintextStartView(t)
+	m := NewModelWithUpdateCheckAndConfigPath(nil, sv.config, false, sv.configPath)
+	m.currentView = ViewModeTree
+
+	// Reconnecting and the setup wizard connect from other views
+	m.startConnect()
+	if !m.startView.confirmingPlaintextBind() || m.currentView != ViewModeStart {
+		t.Fatal("Expected the start view to show the warning")
+	}
+
+	// Global keys don't leave the warning
+	m.Update(keyRune('2'))
+	if m.currentView != ViewModeStart || !m.startView.confirmingPlaintextBind() {
+		t.Error("Expected the warning to keep the keys")
+	}
+}
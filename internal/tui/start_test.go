@@ -34,6 +34,28 @@ func TestStartView_TextInputIntegration(t *testing.T) {
 	}
 }
 
+func TestStartView_PageSizeLimit(t *testing.T) {
+	cfg := config.Default()
+	sv := NewStartView(cfg)
+	sv.editing = true
+	sv.editingField = FieldPageSize
+
+	sv.textInput.SetValue("200")
+	sv.saveValue()
+	if cfg.Pagination.PageSize != 200 || len(sv.configWarnings) != 0 {
+		t.Errorf("Expected page size 200 to be kept, got %d and %v", cfg.Pagination.PageSize, sv.configWarnings)
+	}
+
+	sv.textInput.SetValue("100000")
+	sv.saveValue()
+	if cfg.Pagination.PageSize != config.DefaultMaxPageSize {
+		t.Errorf("Expected the page size to be capped at %d, got %d", config.DefaultMaxPageSize, cfg.Pagination.PageSize)
+	}
+	if len(sv.configWarnings) != 1 || !strings.Contains(sv.configWarnings[0], "above the maximum") {
+		t.Errorf("Expected a warning about the capped page size, got %v", sv.configWarnings)
+	}
+}
+
 func TestStartView_NewConnectionDialog(t *testing.T) {
 	cfg := &config.Config{
 		LDAP: config.LDAPConfig{
@@ -88,6 +110,66 @@ func TestStartView_BooleanToggle(t *testing.T) {
 	}
 }
 
+func TestStartView_SSLAndTLSAreExclusive(t *testing.T) {
+	cfg := &config.Config{
+		LDAP: config.LDAPConfig{
+			Host:   "localhost",
+			Port:   389,
+			UseTLS: true,
+		},
+	}
+
+	sv := NewStartView(cfg)
+	sv.editing = true
+	sv.editingField = FieldUseSSL
+	_, _ = sv.handleEditMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if !cfg.LDAP.UseSSL || cfg.LDAP.UseTLS {
+		t.Errorf("Expected enabling SSL to turn StartTLS off, got ssl=%v tls=%v", cfg.LDAP.UseSSL, cfg.LDAP.UseTLS)
+	}
+
+	sv.editingField = FieldUseTLS
+	_, _ = sv.handleEditMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	if cfg.LDAP.UseSSL || !cfg.LDAP.UseTLS {
+		t.Errorf("Expected enabling StartTLS to turn SSL off, got ssl=%v tls=%v", cfg.LDAP.UseSSL, cfg.LDAP.UseTLS)
+	}
+
+	// Turning one off leaves the other alone
+	_, _ = sv.handleEditMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if cfg.LDAP.UseSSL || cfg.LDAP.UseTLS {
+		t.Errorf("Expected both to be off, got ssl=%v tls=%v", cfg.LDAP.UseSSL, cfg.LDAP.UseTLS)
+	}
+}
+
+func TestStartView_BindMethodSelector(t *testing.T) {
+	cfg := &config.Config{LDAP: config.LDAPConfig{Host: "dc1.example.com", Port: 389}}
+
+	sv := NewStartView(cfg)
+	if got := sv.getFieldValue(FieldBindMethod); got != "simple" {
+		t.Errorf("Expected simple bind by default, got %q", got)
+	}
+
+	sv.cursor = FieldBindMethod
+	sv.handleFieldAction()
+	if !sv.editing {
+		t.Fatal("Expected Enter to start choosing a bind method")
+	}
+
+	_, _ = sv.handleEditMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
+	if cfg.LDAP.BindMethod != "gssapi" {
+		t.Errorf("Expected Space to select gssapi, got %q", cfg.LDAP.BindMethod)
+	}
+
+	_, _ = sv.handleEditMode(tea.KeyMsg{Type: tea.KeyLeft})
+	if cfg.LDAP.BindMethod != "" {
+		t.Errorf("Expected simple to be stored as the default, got %q", cfg.LDAP.BindMethod)
+	}
+
+	_, _ = sv.handleEditMode(tea.KeyMsg{Type: tea.KeyEnter})
+	if sv.editing {
+		t.Error("Expected Enter to finish choosing")
+	}
+}
+
 func TestStartView_LayoutAndAlignment(t *testing.T) {
 	// Initialize bubblezone for tests
 	zone.NewGlobal()
@@ -148,3 +230,32 @@ func TestStartView_LayoutAndAlignment(t *testing.T) {
 		t.Error("Expected navigation instructions to be present in output")
 	}
 }
+
+func TestStartView_BaseDNValidation(t *testing.T) {
+	cfg := &config.Config{LDAP: config.LDAPConfig{Host: "localhost", Port: 389, BaseDN: "dc=example,dc=com"}}
+
+	sv := NewStartView(cfg)
+	sv.editing = true
+	sv.editingField = FieldBaseDN
+	sv.textInput.SetValue("dc=example,=com")
+
+	_, _ = sv.handleEditMode(tea.KeyMsg{Type: tea.KeyEnter})
+	if !sv.editing {
+		t.Fatal("Expected a malformed base DN to keep the field in editing mode")
+	}
+	if sv.valueError == nil {
+		t.Fatal("Expected the validation error to be shown")
+	}
+	if cfg.LDAP.BaseDN != "dc=example,dc=com" {
+		t.Errorf("Expected the base DN to be unchanged, got %q", cfg.LDAP.BaseDN)
+	}
+
+	sv.textInput.SetValue("OU=People , DC=Example,DC=com")
+	_, _ = sv.handleEditMode(tea.KeyMsg{Type: tea.KeyEnter})
+	if sv.editing || sv.valueError != nil {
+		t.Fatal("Expected a valid base DN to be saved")
+	}
+	if cfg.LDAP.BaseDN != "ou=People,dc=Example,dc=com" {
+		t.Errorf("Expected the normalized base DN to be saved, got %q", cfg.LDAP.BaseDN)
+	}
+}
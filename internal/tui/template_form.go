@@ -0,0 +1,417 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// EntryAddedMsg is sent when an entry has been created under ParentDN
+type EntryAddedMsg struct {
+	DN       string
+	ParentDN string
+}
+
+// EntryAddErrorMsg is sent when creating an entry fails
+type EntryAddErrorMsg struct {
+	DN  string
+	Err error
+}
+
+// templateFormStage is the step of the add-from-template flow
+type templateFormStage int
+
+const (
+	templateStageSelect templateFormStage = iota // Choosing a template
+	templateStageFill                            // Entering placeholder values
+)
+
+// namingAttributes are offered when picking the attribute an entry's RDN is
+// named by, in the usual order of preference
+var namingAttributes = []string{"cn", "uid", "ou", "o", "dc", "l", "c", "st"}
+
+// TemplateForm creates an entry from a configured template. It first lists the
+// templates, then prompts only for the placeholders the chosen template uses.
+// The parent DN is fixed; the DN is assembled from it and the RDN attribute,
+// which starts as the template's and can be changed.
+type TemplateForm struct {
+	client    *ldap.Client
+	schema    *ldap.Schema // Offers the naming attributes it defines, may be nil
+	templates []config.EntryTemplate
+	parentDN  string
+
+	stage        templateFormStage
+	cursor       int // Selected template
+	template     config.EntryTemplate
+	placeholders []string
+	extras       []string          // Attributes added beyond the template's, one value each
+	inputs       []textinput.Model // The placeholder inputs, then one per extra attribute
+	focus        int               // Focused input
+
+	// DN builder: the RDN is rdnAttribute=rdnValue, with rdnValue's
+	// placeholders filled in. Multi-valued template RDNs are used as they are.
+	rdnAttribute string
+	rdnValue     string
+	rdnFixed     bool
+	picker       *AttributePicker // Open while picking an attribute, nil otherwise
+	pickingExtra bool             // The picker adds an extra attribute rather than naming the RDN
+
+	err    error
+	saving bool
+}
+
+// NewTemplateForm creates a form for adding an entry under parentDN. The
+// schema, if known, limits the RDN attributes offered to those it defines.
+func NewTemplateForm(client *ldap.Client, schema *ldap.Schema, templates []config.EntryTemplate, parentDN string) *TemplateForm {
+	f := &TemplateForm{
+		client:    client,
+		schema:    schema,
+		templates: templates,
+		parentDN:  parentDN,
+	}
+	// Skip the picker when there is nothing to choose between
+	if len(templates) == 1 {
+		f.selectTemplate(0)
+	}
+	return f
+}
+
+// selectTemplate moves to the fill stage for the template at index
+func (f *TemplateForm) selectTemplate(index int) {
+	f.template = f.templates[index]
+	f.placeholders = f.template.Placeholders()
+	attribute, value, ok := strings.Cut(f.template.RDN, "=")
+	f.rdnAttribute = strings.TrimSpace(attribute)
+	f.rdnValue = strings.TrimSpace(value)
+	f.rdnFixed = !ok || strings.Contains(value, "+")
+	f.picker = nil
+	f.extras = nil
+	f.inputs = make([]textinput.Model, len(f.placeholders))
+	for i, name := range f.placeholders {
+		f.inputs[i] = newTemplateInput(name)
+	}
+	f.focus = 0
+	if len(f.inputs) > 0 {
+		f.inputs[0].Focus()
+	}
+	f.stage = templateStageFill
+	f.err = nil
+}
+
+// newTemplateInput creates an input for the placeholder or attribute name
+func newTemplateInput(name string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = name
+	ti.CharLimit = 0
+	ti.Width = 40
+	return ti
+}
+
+// values returns the placeholder values entered so far
+func (f *TemplateForm) values() map[string]string {
+	values := make(map[string]string, len(f.placeholders))
+	for i, name := range f.placeholders {
+		values[name] = strings.TrimSpace(f.inputs[i].Value())
+	}
+	return values
+}
+
+// setFocus moves input focus to the placeholder at index
+func (f *TemplateForm) setFocus(index int) {
+	if len(f.inputs) == 0 {
+		return
+	}
+	f.inputs[f.focus].Blur()
+	f.focus = (index + len(f.inputs)) % len(f.inputs)
+	f.inputs[f.focus].Focus()
+}
+
+// Update handles a key press. It returns true when the form should be closed.
+func (f *TemplateForm) Update(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if f.saving {
+		return false, nil
+	}
+
+	if f.picker != nil {
+		done, name, cmd := f.picker.Update(msg)
+		if done {
+			f.picker = nil
+			switch {
+			case name == "":
+			case f.pickingExtra:
+				f.addExtra(name)
+			default:
+				f.rdnAttribute = name
+				f.err = nil
+			}
+		}
+		return false, cmd
+	}
+
+	if msg.String() == "esc" {
+		if f.stage == templateStageFill && len(f.templates) > 1 {
+			// Go back to the template list
+			f.stage = templateStageSelect
+			f.err = nil
+			return false, nil
+		}
+		return true, nil
+	}
+
+	switch f.stage {
+	case templateStageSelect:
+		switch msg.String() {
+		case "up", "k":
+			if f.cursor > 0 {
+				f.cursor--
+			}
+		case "down", "j":
+			if f.cursor < len(f.templates)-1 {
+				f.cursor++
+			}
+		case "enter":
+			f.selectTemplate(f.cursor)
+		}
+		return false, nil
+
+	case templateStageFill:
+		switch msg.String() {
+		case "down":
+			f.setFocus(f.focus + 1)
+			return false, nil
+		case "up":
+			f.setFocus(f.focus - 1)
+			return false, nil
+		case "ctrl+s":
+			return false, f.submit()
+		case "ctrl+t":
+			return false, f.openRDNPicker()
+		case "ctrl+n":
+			return false, f.openExtraPicker()
+		case "enter":
+			// Enter advances through the fields and creates the entry on the last one
+			if f.focus < len(f.inputs)-1 {
+				f.setFocus(f.focus + 1)
+				return false, nil
+			}
+			return false, f.submit()
+		}
+
+		if len(f.inputs) > 0 {
+			var cmd tea.Cmd
+			f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+			return false, cmd
+		}
+	}
+
+	return false, nil
+}
+
+// rdnAttributeCandidates lists the attributes the RDN can be named by: the
+// current one, then the naming attributes the schema defines, or all of them
+// when the schema is unknown
+func (f *TemplateForm) rdnAttributeCandidates() []string {
+	candidates := []string{f.rdnAttribute}
+	for _, name := range namingAttributes {
+		if strings.EqualFold(name, f.rdnAttribute) {
+			continue
+		}
+		if f.schema != nil && f.schema.AttributeType(name) == nil {
+			continue
+		}
+		candidates = append(candidates, f.schema.CanonicalName(name))
+	}
+	return candidates
+}
+
+// openRDNPicker lets the RDN attribute be picked from the naming attributes
+func (f *TemplateForm) openRDNPicker() tea.Cmd {
+	if f.rdnFixed {
+		f.err = fmt.Errorf("template %q names entries by %q, which can't be changed here", f.template.Name, f.template.RDN)
+		return nil
+	}
+	f.picker = NewAttributePicker(f.rdnAttributeCandidates(), f.schema)
+	f.pickingExtra = false
+	return textinput.Blink
+}
+
+// openExtraPicker lets an attribute the template doesn't set be picked from
+// the schema, or from common attribute names when the schema is unknown
+func (f *TemplateForm) openExtraPicker() tea.Cmd {
+	f.picker = NewAttributePicker(f.schema.AttributeNames(), f.schema)
+	f.pickingExtra = true
+	return textinput.Blink
+}
+
+// addExtra adds an input for a value of the attribute name and focuses it
+func (f *TemplateForm) addExtra(name string) {
+	f.extras = append(f.extras, name)
+	f.inputs = append(f.inputs, newTemplateInput(name))
+	f.err = nil
+	f.setFocus(len(f.inputs) - 1)
+}
+
+// addExtraValues adds the values entered for extra attributes to
+// attributes, next to any values the template gives the same attribute.
+// Extra attributes left empty are skipped.
+func (f *TemplateForm) addExtraValues(attributes map[string][]string) {
+	for i, name := range f.extras {
+		value := strings.TrimSpace(f.inputs[len(f.placeholders)+i].Value())
+		if value == "" {
+			continue
+		}
+		for existing := range attributes {
+			if strings.EqualFold(existing, name) {
+				name = existing
+				break
+			}
+		}
+		attributes[name] = append(attributes[name], value)
+	}
+}
+
+// buildDN assembles the DN of the new entry from the parent, the RDN
+// attribute and the placeholder values, validated as a DN
+func (f *TemplateForm) buildDN(values map[string]string) (string, error) {
+	if f.rdnFixed {
+		dn, _, err := f.template.Render(f.parentDN, values, ldap.EscapeDNValue)
+		return dn, err
+	}
+	return ldap.BuildDN(f.parentDN, f.rdnAttribute, config.SubstitutePlaceholders(f.rdnValue, values))
+}
+
+// submit renders the template and adds the resulting entry
+func (f *TemplateForm) submit() tea.Cmd {
+	values := f.values()
+	template := f.template
+	if !f.rdnFixed {
+		template.RDN = f.rdnAttribute + "=" + f.rdnValue
+	}
+	_, attributes, err := template.Render(f.parentDN, values, ldap.EscapeDNValue)
+	if err != nil {
+		f.err = err
+		return nil
+	}
+	f.addExtraValues(attributes)
+	dn, err := f.buildDN(values)
+	if err != nil {
+		f.err = err
+		return nil
+	}
+
+	if f.client == nil {
+		f.err = fmt.Errorf("not connected to an LDAP server")
+		return nil
+	}
+
+	client := f.client
+	parentDN := f.parentDN
+	f.saving = true
+	f.err = nil
+
+	return func() tea.Msg {
+		if err := client.Add(dn, attributes); err != nil {
+			return EntryAddErrorMsg{DN: dn, Err: err}
+		}
+		return EntryAddedMsg{DN: dn, ParentDN: parentDN}
+	}
+}
+
+// View renders the form
+func (f *TemplateForm) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")).
+		Italic(true)
+	previewStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("14"))
+
+	var sections []string
+
+	switch f.stage {
+	case templateStageSelect:
+		sections = append(sections,
+			titleStyle.Render("New entry from template"),
+			"Parent: "+f.parentDN,
+			"",
+		)
+		for i, tmpl := range f.templates {
+			line := fmt.Sprintf("%s (%s)", tmpl.Name, strings.Join(tmpl.ObjectClasses, ", "))
+			if i == f.cursor {
+				sections = append(sections, editorFocusStyle.Render("▶ "+line))
+			} else {
+				sections = append(sections, "  "+line)
+			}
+		}
+		sections = append(sections, "", helpStyle.Render("[↑↓] select • [Enter] choose • [Esc] cancel"))
+
+	case templateStageFill:
+		if f.picker != nil {
+			title := "RDN attribute"
+			if f.pickingExtra {
+				title = "Add an attribute"
+			}
+			sections = append(sections,
+				titleStyle.Render(title),
+				"Parent: "+f.parentDN,
+				"",
+				f.picker.View(),
+			)
+			break
+		}
+
+		sections = append(sections,
+			titleStyle.Render(fmt.Sprintf("New %s", f.template.Name)),
+			previewStyle.Render("DN: "+f.previewDN()),
+			"Parent: "+f.parentDN,
+		)
+		if !f.rdnFixed {
+			sections = append(sections, editorLabelStyle.Render("RDN attribute:")+" "+f.rdnAttribute)
+		}
+		sections = append(sections, "")
+		if len(f.placeholders) == 0 && len(f.extras) == 0 {
+			sections = append(sections, "This template has no placeholders.")
+		}
+		for i, name := range append(append([]string(nil), f.placeholders...), f.extras...) {
+			sections = append(sections, editorLabelStyle.Render(name+":")+" "+f.inputs[i].View())
+		}
+
+		if f.saving {
+			sections = append(sections, "", lipgloss.NewStyle().
+				Foreground(lipgloss.Color("11")).
+				Italic(true).
+				Render(iconLoading.String()+" Creating entry..."))
+		} else if f.err != nil {
+			sections = append(sections, "", editorErrorStyle.Render(fmt.Sprintf("%s Error: %s", iconError, ldap.Explain(f.err).Error())))
+		}
+
+		sections = append(sections, "", helpStyle.Render("[↑↓] select field • [Enter] next/create • [Ctrl+S] create • [Ctrl+T] RDN attribute • [Ctrl+N] add attribute • [Esc] back"))
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// previewDN shows the DN that will be created with the values entered so
+// far. Until it is complete and valid it is shown as typed, with the
+// placeholders still to fill in.
+func (f *TemplateForm) previewDN() string {
+	values := f.values()
+	if dn, err := f.buildDN(values); err == nil {
+		return dn
+	}
+	rdn := config.SubstitutePlaceholders(f.template.RDN, values)
+	if !f.rdnFixed {
+		rdn = f.rdnAttribute + "=" + config.SubstitutePlaceholders(f.rdnValue, values)
+	}
+	if f.parentDN == "" {
+		return rdn
+	}
+	return rdn + "," + f.parentDN
+}
@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ericschmar/moribito/internal/config"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+var errTest = errors.New("insufficient access")
+
+func testTemplates() []config.EntryTemplate {
+	return []config.EntryTemplate{
+		{
+			Name:          "User",
+			RDN:           "uid=${uid}",
+			ObjectClasses: []string{"inetOrgPerson"},
+			Attributes: map[string][]string{
+				"cn": {"${first} ${last}"},
+				"sn": {"${last}"},
+			},
+		},
+		{
+			Name:          "Group",
+			RDN:           "cn=${name}",
+			ObjectClasses: []string{"groupOfNames"},
+		},
+	}
+}
+
+func typeInto(f *TemplateForm, text string) {
+	for _, r := range text {
+		f.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestTemplateForm_SelectAndFill(t *testing.T) {
+	f := NewTemplateForm(nil, nil, testTemplates(), "ou=people,dc=example,dc=com")
+	if f.stage != templateStageSelect {
+		t.Fatal("Expected template picker when several templates exist")
+	}
+
+	f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if f.stage != templateStageFill || f.template.Name != "User" {
+		t.Fatalf("Expected to fill the User template, got stage %d template %q", f.stage, f.template.Name)
+	}
+
+	// Only the placeholders are prompted for
+	if strings.Join(f.placeholders, ",") != "uid,first,last" {
+		t.Errorf("Unexpected placeholders: %v", f.placeholders)
+	}
+
+	typeInto(f, "jdoe")
+	f.Update(tea.KeyMsg{Type: tea.KeyEnter}) // next field
+	typeInto(f, "Jane")
+	f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	typeInto(f, "Doe")
+
+	if got := f.previewDN(); got != "uid=jdoe,ou=people,dc=example,dc=com" {
+		t.Errorf("Unexpected DN preview: %s", got)
+	}
+
+	// Creating without a client reports an error and keeps the form open
+	done, cmd := f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if done || cmd != nil {
+		t.Error("Expected form to stay open without a client")
+	}
+	if f.err == nil || !strings.Contains(f.err.Error(), "not connected") {
+		t.Errorf("Expected not connected error, got %v", f.err)
+	}
+}
+
+func TestTemplateForm_MissingPlaceholder(t *testing.T) {
+	f := NewTemplateForm(nil, nil, testTemplates()[:1], "ou=people,dc=example,dc=com")
+	if f.stage != templateStageFill {
+		t.Fatal("Expected a single template to skip the picker")
+	}
+
+	typeInto(f, "jdoe")
+	f.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if f.err == nil || !strings.Contains(f.err.Error(), "${first}") {
+		t.Errorf("Expected missing placeholder error, got %v", f.err)
+	}
+	if !strings.Contains(f.View(), "missing value") {
+		t.Error("Expected error to be rendered")
+	}
+}
+
+func TestTemplateForm_EscGoesBackThenCloses(t *testing.T) {
+	f := NewTemplateForm(nil, nil, testTemplates(), "dc=example,dc=com")
+	f.Update(tea.KeyMsg{Type: tea.KeyDown})
+	f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if f.template.Name != "Group" {
+		t.Fatalf("Expected Group template, got %q", f.template.Name)
+	}
+
+	if done, _ := f.Update(tea.KeyMsg{Type: tea.KeyEsc}); done || f.stage != templateStageSelect {
+		t.Error("Expected Esc to return to the template list")
+	}
+	if done, _ := f.Update(tea.KeyMsg{Type: tea.KeyEsc}); !done {
+		t.Error("Expected Esc on the template list to close the form")
+	}
+}
+
+func newTemplateTreeView() *TreeView {
+	var client *ldap.Client
+	tv := NewTreeView(client)
+	tv.SetSize(80, 20)
+	tv.roots = []*ldap.TreeNode{{DN: "dc=example,dc=com", Name: "dc=example,dc=com", IsLoaded: true,
+		Children: []*ldap.TreeNode{{DN: "ou=people,dc=example,dc=com", Name: "ou=people"}},
+	}}
+	tv.rebuildFlattenedTree()
+	return tv
+}
+
+func TestTreeView_OpenTemplateForm(t *testing.T) {
+	tv := newTemplateTreeView()
+
+	// Without templates the user is told how to configure them
+	_, cmd := tv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if tv.IsFormActive() {
+		t.Error("Expected no form without templates")
+	}
+	if cmd == nil {
+		t.Fatal("Expected an error command")
+	}
+	if _, ok := cmd().(ErrorMsg); !ok {
+		t.Error("Expected ErrorMsg without templates")
+	}
+
+	tv.SetTemplates(testTemplates())
+	tv.cursor = 1
+	tv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if !tv.IsFormActive() {
+		t.Fatal("Expected 't' to open the template form")
+	}
+	if tv.templateForm.parentDN != "ou=people,dc=example,dc=com" {
+		t.Errorf("Expected selected node as parent, got %s", tv.templateForm.parentDN)
+	}
+	if !strings.Contains(tv.View(), "New entry from template") {
+		t.Error("Expected the form to be rendered in place of the tree")
+	}
+
+	// Keys go to the form while it is open
+	tv.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if tv.cursor != 1 || tv.templateForm.cursor != 1 {
+		t.Error("Expected navigation keys to move the template selection, not the tree")
+	}
+
+	tv.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if tv.IsFormActive() {
+		t.Error("Expected Esc to close the form")
+	}
+}
+
+func TestTreeView_EntryAddMessages(t *testing.T) {
+	tv := newTemplateTreeView()
+	tv.SetTemplates(testTemplates())
+	tv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	tv.templateForm.saving = true
+
+	tv.Update(EntryAddErrorMsg{DN: "uid=x,dc=example,dc=com", Err: errTest})
+	if !tv.IsFormActive() || tv.templateForm.saving || tv.templateForm.err != errTest {
+		t.Error("Expected add error to be shown in the still-open form")
+	}
+
+	// The parent of an unexpanded node isn't reloaded
+	_, cmd := tv.Update(EntryAddedMsg{DN: "uid=x,ou=people,dc=example,dc=com", ParentDN: "ou=people,dc=example,dc=com"})
+	if tv.IsFormActive() {
+		t.Error("Expected form to close after the entry was created")
+	}
+	if cmd == nil {
+		t.Error("Expected a status command after creating an entry")
+	}
+	if tv.FlattenedTree[1].Node.IsLoaded {
+		t.Error("Expected unexpanded parent to stay unloaded")
+	}
+}
+
+func TestTemplateForm_ChangeRDNAttribute(t *testing.T) {
+	schema := &ldap.Schema{AttributeTypes: map[string]*ldap.AttributeType{
+		"cn":  {OID: "2.5.4.3", Names: []string{"cn"}},
+		"uid": {OID: "0.9.2342.19200300.100.1.1", Names: []string{"uid"}},
+		"ou":  {OID: "2.5.4.11", Names: []string{"ou"}},
+	}}
+	f := NewTemplateForm(nil, schema, testTemplates()[:1], "OU=People, DC=example, DC=com")
+
+	if got := strings.Join(f.rdnAttributeCandidates(), ","); got != "uid,cn,ou" {
+		t.Errorf("Expected the template's attribute, then the schema's naming attributes, got %s", got)
+	}
+
+	typeInto(f, "Doe, Jane")
+	f.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if f.picker == nil {
+		t.Fatal("Expected Ctrl+T to open the RDN attribute picker")
+	}
+	typeInto(f, "cn")
+	f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if f.picker != nil || f.rdnAttribute != "cn" {
+		t.Fatalf("Expected cn to be picked, got %q", f.rdnAttribute)
+	}
+
+	// The parent stays as given; the value is escaped and the DN normalized
+	if got := f.previewDN(); got != `cn=Doe\, Jane,ou=People,dc=example,dc=com` {
+		t.Errorf("Unexpected DN preview: %s", got)
+	}
+	if !strings.Contains(f.View(), "RDN attribute: cn") {
+		t.Error("Expected the RDN attribute to be shown")
+	}
+}
+
+func TestTemplateForm_AddAttribute(t *testing.T) {
+	schema := &ldap.Schema{AttributeTypes: map[string]*ldap.AttributeType{
+		"mail":            {OID: "0.9.2342.19200300.100.1.3", Names: []string{"mail"}},
+		"telephoneNumber": {OID: "2.5.4.20", Names: []string{"telephoneNumber"}},
+		"sn":              {OID: "2.5.4.4", Names: []string{"sn"}},
+	}}
+	f := NewTemplateForm(nil, schema, testTemplates()[:1], "ou=people,dc=example,dc=com")
+	typeInto(f, "jdoe")
+	f.Update(tea.KeyMsg{Type: tea.KeyDown})
+	typeInto(f, "Jane")
+	f.Update(tea.KeyMsg{Type: tea.KeyDown})
+	typeInto(f, "Doe")
+
+	for _, attr := range []struct{ prefix, value string }{{"ma", "jdoe@example.com"}, {"SN", "Smith"}} {
+		f.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+		if f.picker == nil || !f.pickingExtra {
+			t.Fatal("Expected Ctrl+N to open the attribute picker")
+		}
+		typeInto(f, attr.prefix)
+		f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		typeInto(f, attr.value)
+	}
+	if got := strings.Join(f.extras, ","); got != "mail,sn" {
+		t.Fatalf("Expected the picked attributes to be added, got %s", got)
+	}
+	if view := f.View(); !strings.Contains(view, "mail:") {
+		t.Errorf("Expected a field for the added attribute, got %q", view)
+	}
+
+	_, attributes, err := f.template.Render(f.parentDN, f.values(), ldap.EscapeDNValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.addExtraValues(attributes)
+	if got := strings.Join(attributes["mail"], ","); got != "jdoe@example.com" {
+		t.Errorf("Expected the added mail value, got %q", got)
+	}
+	if got := strings.Join(attributes["sn"], ","); got != "Doe,Smith" {
+		t.Errorf("Expected the added value next to the template's, got %q", got)
+	}
+}
+
+func TestTemplateForm_MultiValuedRDNIsFixed(t *testing.T) {
+	f := NewTemplateForm(nil, nil, []config.EntryTemplate{{
+		Name:          "Host",
+		RDN:           "cn=${name}+ipHostNumber=${ip}",
+		ObjectClasses: []string{"ipHost"},
+	}}, "dc=example,dc=com")
+
+	f.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if f.picker != nil || f.err == nil {
+		t.Error("Expected a multi-valued RDN to keep its attributes")
+	}
+	typeInto(f, "web, east")
+	f.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	typeInto(f, "10.0.0.1")
+	if got := f.previewDN(); got != `cn=web\, east+ipHostNumber=10.0.0.1,dc=example,dc=com` {
+		t.Errorf("Expected the comma escaped in the DN preview, got %s", got)
+	}
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ToastLevel is how important a notification is, which sets its colour and
+// how long it stays up
+type ToastLevel int
+
+const (
+	ToastInfo ToastLevel = iota
+	ToastSuccess
+	ToastWarning
+	ToastError
+)
+
+// toastTimeouts is how long each level of toast is shown. Errors stay longest
+// so there is time to read them.
+var toastTimeouts = map[ToastLevel]time.Duration{
+	ToastInfo:    4 * time.Second,
+	ToastSuccess: 3 * time.Second,
+	ToastWarning: 6 * time.Second,
+	ToastError:   10 * time.Second,
+}
+
+// maxToasts bounds the toasts stacked above the status bar; the oldest go first
+const maxToasts = 3
+
+// toastTickInterval is how often toasts are checked for expiry
+const toastTickInterval = 500 * time.Millisecond
+
+// toast is one transient notification
+type toast struct {
+	id      int
+	level   ToastLevel
+	message string
+	expires time.Time
+}
+
+// toastQueue holds the notifications currently shown, oldest first
+type toastQueue struct {
+	items []toast
+	next  int // ID of the next toast pushed
+}
+
+// push adds a toast expiring after its level's timeout. A message already
+// shown moves to the end with a fresh timeout instead of appearing twice.
+func (q *toastQueue) push(level ToastLevel, message string, now time.Time) {
+	if message == "" {
+		return
+	}
+	for i, item := range q.items {
+		if item.message == message {
+			q.items = append(q.items[:i], q.items[i+1:]...)
+			break
+		}
+	}
+	q.items = append(q.items, toast{id: q.next, level: level, message: message, expires: now.Add(toastTimeouts[level])})
+	q.next++
+	if len(q.items) > maxToasts {
+		q.items = q.items[len(q.items)-maxToasts:]
+	}
+}
+
+// expire removes the toasts whose timeout has passed at now
+func (q *toastQueue) expire(now time.Time) {
+	items := q.items[:0]
+	for _, item := range q.items {
+		if now.Before(item.expires) {
+			items = append(items, item)
+		}
+	}
+	q.items = items
+}
+
+// prefixSince prepends prefix to the toasts pushed from the ID next onwards,
+// naming the background connection they came from, see Model.updateTab
+func (q *toastQueue) prefixSince(next int, prefix string) {
+	for i, item := range q.items {
+		if item.id >= next {
+			q.items[i].message = prefix + item.message
+		}
+	}
+}
+
+// latest returns the newest toast's message, empty when none is shown
+func (q *toastQueue) latest() string {
+	if len(q.items) == 0 {
+		return ""
+	}
+	return q.items[len(q.items)-1].message
+}
+
+// ToastTickMsg checks the shown toasts for expiry
+type ToastTickMsg struct {
+	Time time.Time
+}
+
+// toastTick waits for the next expiry check. The model starts it once and
+// re-arms it on every tick, so toasts expire without each change of the
+// queue having to schedule its own command.
+func toastTick() tea.Cmd {
+	return tea.Tick(toastTickInterval, func(t time.Time) tea.Msg {
+		return ToastTickMsg{Time: t}
+	})
+}
+
+// toastStyles colours toasts by level
+var toastStyles = map[ToastLevel]lipgloss.Style{
+	ToastInfo:    lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("238")).Padding(0, 1),
+	ToastSuccess: lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("10")).Padding(0, 1),
+	ToastWarning: lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("11")).Padding(0, 1),
+	ToastError:   lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Background(lipgloss.Color("9")).Bold(true).Padding(0, 1),
+}
+
+// toastIcons mark toasts by level, so they can be told apart without colour
+var toastIcons = map[ToastLevel]icon{
+	ToastSuccess: iconSuccess,
+	ToastWarning: iconWarning,
+	ToastError:   iconError,
+}
+
+// render draws each toast as a line at most width wide, oldest first
+func (q *toastQueue) render(width int) []string {
+	lines := make([]string, 0, len(q.items))
+	for _, item := range q.items {
+		message := item.message
+		if icon, ok := toastIcons[item.level]; ok && !strings.HasPrefix(message, icon.String()) {
+			message = icon.String() + " " + message
+		}
+		style := toastStyles[item.level]
+		lines = append(lines, ansi.Truncate(style.Render(message), width, "…"))
+	}
+	return lines
+}
+
+// overlayToasts draws the shown toasts right-aligned over the last lines of
+// content, which the status bar follows, so the layout doesn't move
+func (m *Model) overlayToasts(contentLines []string) []string {
+	toasts := m.toasts.render(m.width)
+	if len(toasts) == 0 {
+		return contentLines
+	}
+	start := max(len(contentLines)-len(toasts), 0)
+	toasts = toasts[len(toasts)-(len(contentLines)-start):]
+	for i, toast := range toasts {
+		line := contentLines[start+i]
+		left := max(m.width-lipgloss.Width(toast)-1, 0)
+		line = ansi.Truncate(line, left, "")
+		if gap := left - lipgloss.Width(line); gap > 0 {
+			line += strings.Repeat(" ", gap)
+		}
+		contentLines[start+i] = line + toast
+	}
+	return contentLines
+}
+
+// notify shows a toast above the status bar
+func (m *Model) notify(level ToastLevel, message string) {
+	m.toasts.push(level, message, time.Now())
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/config"
+)
+
+// toastMessages returns the messages of the shown toasts, oldest first
+func toastMessages(q *toastQueue) []string {
+	var messages []string
+	for _, item := range q.items {
+		messages = append(messages, item.message)
+	}
+	return messages
+}
+
+func TestToastQueue_ExpiresByLevel(t *testing.T) {
+	var q toastQueue
+	now := time.Now()
+	q.push(ToastSuccess, "Copied DN to clipboard", now)
+	q.push(ToastError, "Error: no such object", now.Add(time.Second))
+
+	q.expire(now.Add(2 * time.Second))
+	if got := toastMessages(&q); len(got) != 2 {
+		t.Fatalf("Expected both toasts to still be shown, got %v", got)
+	}
+
+	// The success toast times out first and the error stays up
+	q.expire(now.Add(toastTimeouts[ToastSuccess]))
+	if got := toastMessages(&q); len(got) != 1 || got[0] != "Error: no such object" {
+		t.Errorf("Expected only the error left, got %v", got)
+	}
+	q.expire(now.Add(time.Second + toastTimeouts[ToastError]))
+	if got := q.latest(); got != "" {
+		t.Errorf("Expected every toast to have expired, got %q", got)
+	}
+}
+
+func TestToastQueue_Ordering(t *testing.T) {
+	var q toastQueue
+	now := time.Now()
+	for _, message := range []string{"one", "two", "three"} {
+		q.push(ToastInfo, message, now)
+	}
+
+	// Repeating a message moves it to the end instead of stacking it twice
+	q.push(ToastInfo, "one", now)
+	if got := strings.Join(toastMessages(&q), ","); got != "two,three,one" {
+		t.Errorf("Expected the repeated toast to move last, got %s", got)
+	}
+
+	// Past the limit the oldest toast goes
+	q.push(ToastWarning, "four", now)
+	if got := strings.Join(toastMessages(&q), ","); got != "three,one,four" {
+		t.Errorf("Expected the oldest toast to be dropped, got %s", got)
+	}
+	if q.latest() != "four" {
+		t.Errorf("Expected the newest toast last, got %q", q.latest())
+	}
+
+	next := q.next
+	q.push(ToastInfo, "five", now)
+	q.prefixSince(next, "[Staging] ")
+	if got := strings.Join(toastMessages(&q), ","); got != "one,four,[Staging] five" {
+		t.Errorf("Expected only the new toast to be prefixed, got %s", got)
+	}
+}
+
+func TestModel_ToastsStack(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.Init()
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	m.Update(StatusMsg{Message: "Copied DN to clipboard", Level: ToastSuccess})
+	m.Update(ErrorMsg{Err: errors.New("no such object")})
+	if got := toastMessages(&m.toasts); len(got) != 2 || got[0] != "Copied DN to clipboard" || got[1] != "Error: no such object" {
+		t.Fatalf("Expected the error to stack after the copy confirmation, got %v", got)
+	}
+
+	view := m.View()
+	if lipgloss.Height(view) != 30 {
+		t.Errorf("Expected toasts not to change the height of the view, got %d lines", lipgloss.Height(view))
+	}
+	lines := strings.Split(ansi.Strip(view), "\n")
+	copied, failed, status := -1, -1, -1
+	for i, line := range lines {
+		if strings.Contains(line, "Disconnected") {
+			status = i
+		}
+		if strings.Contains(line, "Copied DN to clipboard") {
+			copied = i
+		}
+		if strings.Contains(line, "Error: no such object") {
+			failed = i
+		}
+	}
+	if copied < 0 || failed != copied+1 {
+		t.Errorf("Expected both toasts, newest lowest, got lines %d and %d:\n%s", copied, failed, ansi.Strip(view))
+	}
+	if failed != status-1 {
+		t.Errorf("Expected the newest toast just above the status bar (line %d), got line %d", status-1, failed)
+	}
+
+	// Ticks past the timeouts clear them
+	m.Update(ToastTickMsg{Time: time.Now().Add(toastTimeouts[ToastError] + time.Second)})
+	if len(m.toasts.items) != 0 {
+		t.Errorf("Expected the toasts to expire, got %v", toastMessages(&m.toasts))
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ericschmar/moribito/internal/config"
 	"github.com/ericschmar/moribito/internal/ldap"
 	zone "github.com/lrstanley/bubblezone"
 )
@@ -14,17 +15,38 @@ import (
 // TreeView represents the LDAP tree view
 type TreeView struct {
 	client        *ldap.Client
-	root          *ldap.TreeNode
+	roots         []*ldap.TreeNode // The base DN, or every naming context when allContexts is set
+	allContexts   bool
 	FlattenedTree []*TreeItem
 	cursor        int
 	viewport      int
 	width         int
 	height        int
-	loading       bool
+	loading       bool  // Building the root node; the whole view shows a loading message
+	rootErr       error // The base DN doesn't exist; shown in place of the tree
 	container     *ViewContainer
 	// Timer fields for loading display
 	loadingStartTime time.Time
 	loadingElapsed   time.Duration
+	timerRunning     bool
+	// Nodes whose children are being loaded, with when each load started.
+	// Several can be in flight at once; each shows its own spinner.
+	loadingNodes map[*ldap.TreeNode]time.Time
+	// Expand all: the levels still to load below each node being read for
+	// it, and the nodes it left unexpanded at the depth limit
+	maxAutoDepth int
+	collapseRoot bool // Leave a single root collapsed when the tree loads
+	expanding    map[*ldap.TreeNode]int
+	depthCapped  map[*ldap.TreeNode]bool
+	// Entry creation from templates
+	templates    []config.EntryTemplate
+	schema       *ldap.Schema  // Offers naming attributes when building a new entry's DN
+	templateForm *TemplateForm // Active add-from-template form, nil when closed
+	// Entry deletion
+	deleteConfirm *DeleteConfirm // Active delete prompt, nil when closed
+	deleteProbe   *ldap.TreeNode // Node whose children or rights are read before asking to delete it
+	// Name of the connection, shown in front of each root
+	connectionName string
 }
 
 // TreeItem represents a flattened tree item for display
@@ -37,9 +59,13 @@ type TreeItem struct {
 // NewTreeView creates a new tree view
 func NewTreeView(client *ldap.Client) *TreeView {
 	return &TreeView{
-		client:   client,
-		cursor:   0,
-		viewport: 0,
+		client:       client,
+		cursor:       0,
+		viewport:     0,
+		loadingNodes: make(map[*ldap.TreeNode]time.Time),
+		maxAutoDepth: defaultMaxAutoDepth,
+		expanding:    make(map[*ldap.TreeNode]int),
+		depthCapped:  make(map[*ldap.TreeNode]bool),
 	}
 }
 
@@ -54,10 +80,118 @@ func (tv *TreeView) SetSize(width, height int) {
 	tv.container = NewViewContainer(width, height)
 }
 
+// SetConnectionName sets the connection name shown in front of each root,
+// such as [prod] dc=example,dc=com
+func (tv *TreeView) SetConnectionName(name string) {
+	tv.connectionName = name
+}
+
+// SetTemplates sets the entry templates offered by the add-from-template flow
+func (tv *TreeView) SetTemplates(templates []config.EntryTemplate) {
+	tv.templates = templates
+}
+
+// SetSchema sets the server schema, used to offer naming attributes when
+// building the DN of a new entry
+func (tv *TreeView) SetSchema(schema *ldap.Schema) {
+	tv.schema = schema
+}
+
+// SetAllNamingContexts sets whether the tree shows every naming context the
+// server advertises as a root instead of just the base DN. It takes effect
+// the next time the tree is loaded.
+func (tv *TreeView) SetAllNamingContexts(all bool) {
+	tv.allContexts = all
+}
+
+// defaultMaxAutoDepth is how many levels expand all loads when
+// display.tree_max_auto_depth isn't set
+const defaultMaxAutoDepth = 3
+
+// SetMaxAutoDepth sets how many levels expand all loads below the selected
+// node. Zero uses the default and a negative depth has no limit.
+func (tv *TreeView) SetMaxAutoDepth(depth int) {
+	if depth == 0 {
+		depth = defaultMaxAutoDepth
+	}
+	tv.maxAutoDepth = depth
+}
+
+// SetCollapseRoot sets whether a single root stays collapsed when the tree
+// loads instead of listing its children straight away, which saves reading
+// a very large base until it is opened
+func (tv *TreeView) SetCollapseRoot(collapse bool) {
+	tv.collapseRoot = collapse
+}
+
+// toggleNamingContexts switches between the base DN and every naming context
+// for this session and reloads the tree
+func (tv *TreeView) toggleNamingContexts() tea.Cmd {
+	tv.allContexts = !tv.allContexts
+	return tv.Reload()
+}
+
+// moveToRoot moves the cursor to the next (step 1) or previous (step -1)
+// root, jumping between naming contexts. Moving back from inside a root
+// goes to the top of that root first.
+func (tv *TreeView) moveToRoot(step int) {
+	var rows []int
+	for i, item := range tv.FlattenedTree {
+		if item.Level == 0 {
+			rows = append(rows, i)
+		}
+	}
+
+	target := -1
+	for _, row := range rows {
+		if step > 0 && row > tv.cursor {
+			target = row
+			break
+		}
+		if step < 0 && row < tv.cursor {
+			target = row
+		}
+	}
+	if target < 0 {
+		return
+	}
+	tv.cursor = target
+	tv.adjustViewport()
+}
+
+// IsFormActive returns true while the add-from-template form or delete prompt is open
+func (tv *TreeView) IsFormActive() bool {
+	return tv.templateForm != nil || tv.deleteConfirm != nil
+}
+
+// HasUnsavedChanges reports whether a new entry's values are being entered
+// or written, or a delete is running
+func (tv *TreeView) HasUnsavedChanges() bool {
+	if tv.templateForm != nil && (tv.templateForm.stage == templateStageFill || tv.templateForm.saving) {
+		return true
+	}
+	return tv.deleteConfirm != nil && tv.deleteConfirm.deleting
+}
+
 // Update handles messages for the tree view
 func (tv *TreeView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if tv.templateForm != nil {
+			done, cmd := tv.templateForm.Update(msg)
+			if done {
+				tv.templateForm = nil
+			}
+			return tv, cmd
+		}
+		if tv.deleteConfirm != nil {
+			done, cmd := tv.deleteConfirm.Update(msg)
+			if done {
+				tv.deleteConfirm = nil
+			}
+			return tv, cmd
+		}
+
 		switch msg.String() {
 		case "up", "k":
 			if tv.cursor > 0 {
@@ -109,32 +243,140 @@ func (tv *TreeView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return tv, tv.expandNode()
 		case "left", "h":
 			return tv, tv.collapseNode()
+		case "g":
+			return tv, tv.collapseAll()
+		case "E":
+			return tv, tv.expandAll()
 		case "enter":
 			return tv, tv.viewRecord()
+		case "b", "B":
+			return tv, tv.changeBaseDN()
+		case "o", "O":
+			return tv, tv.openAliasTarget()
+		case "t":
+			return tv, tv.openTemplateForm()
+		case "D":
+			return tv, tv.openDeleteConfirm()
+		case "n":
+			return tv, tv.toggleNamingContexts()
+		case "]":
+			tv.moveToRoot(1)
+		case "[":
+			tv.moveToRoot(-1)
 		}
 
+	case EntryAddedMsg:
+		tv.templateForm = nil
+		return tv, tea.Batch(tv.reloadChildren(msg.ParentDN), SendSuccess(fmt.Sprintf("Created %s", msg.DN)))
+
+	case EntryAddErrorMsg:
+		if tv.templateForm != nil {
+			tv.templateForm.saving = false
+			tv.templateForm.err = msg.Err
+		}
+		return tv, nil
+
+	case EntryDeletedMsg:
+		tv.deleteConfirm = nil
+		tv.removeNode(msg.DN)
+		if msg.Subtree {
+			return tv, SendSuccess(fmt.Sprintf("Deleted subtree %s", msg.DN))
+		}
+		return tv, SendSuccess(fmt.Sprintf("Deleted %s", msg.DN))
+
+	case StaleEntryRemovedMsg:
+		if !tv.removeNode(msg.DN) {
+			return tv, SendStatus(fmt.Sprintf("%s isn't listed below the tree's roots", msg.DN))
+		}
+		return tv, SendSuccess(fmt.Sprintf("Removed %s from the tree", msg.DN))
+
+	case EntryDeleteErrorMsg:
+		if tv.deleteConfirm != nil {
+			tv.deleteConfirm.deleting = false
+			tv.deleteConfirm.err = msg.Err
+		}
+		return tv, nil
+
 	case RootNodeLoadedMsg:
-		tv.root = msg.Node
+		tv.roots = msg.Roots
+		tv.rootErr = nil
 		tv.loading = false
 		tv.rebuildFlattenedTree()
-		// Automatically expand the root node to show immediate children
-		if tv.root != nil && !tv.root.IsLoaded {
-			return tv, tv.loadChildren(tv.root)
+		if msg.Err != nil {
+			return tv, SendError(msg.Err)
+		}
+		// Automatically expand a single root to show immediate children,
+		// unless configured not to; naming contexts start collapsed so they
+		// can be opened one by one
+		if len(tv.roots) == 1 && !tv.roots[0].IsLoaded && !tv.collapseRoot {
+			return tv, tv.loadChildren(tv.roots[0])
+		}
+		if len(tv.roots) > 1 {
+			return tv, SendStatus(fmt.Sprintf("Tree loaded with %d naming contexts", len(tv.roots)))
 		}
 		return tv, SendStatus("Tree loaded")
 
+	case DeleteRightsLoadedMsg:
+		if msg.Node != tv.deleteProbe {
+			return tv, nil
+		}
+		tv.deleteProbe = nil
+		// Rights that couldn't be read don't stand in the way; the server still checks
+		return tv, tv.openDeletePrompt(msg.Node)
+
 	case NodeChildrenLoadedMsg:
+		delete(tv.loadingNodes, msg.Node)
+		probed := msg.Node == tv.deleteProbe
+		if probed {
+			tv.deleteProbe = nil
+		}
+		if msg.Err != nil {
+			delete(tv.expanding, msg.Node)
+			if tv.isBaseRoot(msg.Node) && ldap.IsNoSuchObject(msg.Err) {
+				tv.rootErr = msg.Err
+				return tv, nil
+			}
+			return tv, SendError(msg.Err)
+		}
+		if !msg.Node.IsLoaded {
+			msg.Node.Children = msg.Children
+			msg.Node.IsLoaded = true
+			// Children read to choose how to delete stay out of sight
+			msg.Node.Collapsed = probed && len(msg.Children) > 0
+		}
+		if probed {
+			tv.rebuildFlattenedTree()
+			return tv, tv.confirmDelete(msg.Node)
+		}
+		delete(tv.depthCapped, msg.Node)
+		if depth, ok := tv.expanding[msg.Node]; ok {
+			delete(tv.expanding, msg.Node)
+			var cmds []tea.Cmd
+			for _, child := range msg.Node.Children {
+				cmds = append(cmds, tv.expandTo(child, depth-1))
+			}
+			tv.rebuildFlattenedTree()
+			if len(tv.expanding) == 0 {
+				cmds = append(cmds, tv.expandedStatus())
+			}
+			return tv, tea.Batch(cmds...)
+		}
 		tv.rebuildFlattenedTree()
-		tv.loading = false
+		if tv.isEmptyBase() {
+			return tv, SendStatus("The base DN exists but has no entries below it")
+		}
 		return tv, SendStatus(fmt.Sprintf("Loaded children for %s", msg.Node.Name))
 
 	case LoadingTimerTickMsg:
 		if tv.loading {
-			// Update elapsed time for display and continue the timer
+			// Update elapsed time for display
 			tv.loadingElapsed = msg.Time.Sub(tv.loadingStartTime)
+		}
+		if tv.loading || len(tv.loadingNodes) > 0 {
 			return tv, tv.timerTickCmd()
 		}
 		// If not loading anymore, don't continue the timer
+		tv.timerRunning = false
 		return tv, nil
 
 	case tea.Msg:
@@ -163,7 +405,22 @@ func (tv *TreeView) View() string {
 		return tv.container.RenderCentered(loadingMsg)
 	}
 
+	if tv.templateForm != nil {
+		return tv.container.RenderWithPadding(tv.templateForm.View())
+	}
+
+	if tv.deleteConfirm != nil {
+		return tv.container.RenderWithPadding(tv.deleteConfirm.View())
+	}
+
+	if tv.rootErr != nil {
+		return tv.container.RenderWithPadding(tv.renderMissingBase())
+	}
+
 	if len(tv.FlattenedTree) == 0 {
+		if tv.allContexts {
+			return tv.container.RenderCentered("No naming contexts found • [N] show the base DN")
+		}
 		return tv.container.RenderCentered("No entries found")
 	}
 
@@ -179,7 +436,15 @@ func (tv *TreeView) View() string {
 
 	var lines []string
 	visibleStart := tv.viewport
-	visibleEnd := visibleStart + availableHeight
+
+	// Once its row scrolls away, the root above the rows shown stays pinned
+	// on the first line
+	if root := tv.pinnedRoot(visibleStart); root >= 0 {
+		line := tv.renderTreeItem(tv.FlattenedTree[root], root == tv.cursor, contentWidth)
+		lines = append(lines, zone.Mark(fmt.Sprintf("tree-item-%d", root), line))
+		availableHeight--
+	}
+	visibleEnd := visibleStart + max(availableHeight, 1)
 	if visibleEnd > len(tv.FlattenedTree) {
 		visibleEnd = len(tv.FlattenedTree)
 	}
@@ -195,6 +460,14 @@ func (tv *TreeView) View() string {
 		lines = append(lines, line)
 	}
 
+	// A base without children looks like any unexpanded entry, so say so
+	if tv.isEmptyBase() {
+		lines = append(lines, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")).
+			Italic(true).
+			Render("  The base DN exists but has no entries below it • [N] show naming contexts"))
+	}
+
 	content := strings.Join(lines, "\n")
 
 	// Add pagination info if applicable
@@ -209,59 +482,148 @@ func (tv *TreeView) View() string {
 	return tv.container.RenderWithPadding(content)
 }
 
+// pinnedRoot returns the row of the root that the row at start is below,
+// or -1 when start is itself a root or out of range
+func (tv *TreeView) pinnedRoot(start int) int {
+	if start <= 0 || start >= len(tv.FlattenedTree) || tv.FlattenedTree[start].Level == 0 {
+		return -1
+	}
+	for i := start - 1; i >= 0; i-- {
+		if tv.FlattenedTree[i].Level == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// isBaseRoot reports whether node is the base DN the tree is rooted at, as
+// opposed to one of several naming contexts or an entry below it
+func (tv *TreeView) isBaseRoot(node *ldap.TreeNode) bool {
+	return !tv.allContexts && len(tv.roots) == 1 && tv.roots[0] == node
+}
+
+// isEmptyBase reports whether the base DN was read and has no children
+func (tv *TreeView) isEmptyBase() bool {
+	return len(tv.roots) == 1 && tv.isBaseRoot(tv.roots[0]) && tv.roots[0].IsLoaded && len(tv.roots[0].Children) == 0
+}
+
+// renderMissingBase explains that the base DN doesn't exist and how to
+// find one that does
+func (tv *TreeView) renderMissingBase() string {
+	dn := ""
+	if len(tv.roots) == 1 {
+		dn = tv.roots[0].DN
+	}
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("%s Base DN not found: %s", iconError, dn)),
+		"",
+		"The server has no entry at the base DN, or the bound user isn't allowed to see it.",
+		"Press [N] to list the server's naming contexts, then [B] on one to use it as the base DN.",
+		"",
+		dimStyle.Render(tv.rootErr.Error()),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// treeRootStyle sets the roots of the tree apart from the entries below them
+var treeRootStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("14")).
+	Background(lipgloss.Color("238")).
+	Bold(true)
+
 // renderTreeItem renders a single tree item
 func (tv *TreeView) renderTreeItem(item *TreeItem, isCursor bool, contentWidth int) string {
 	indent := strings.Repeat("  ", item.Level)
 
 	var prefix string
-	if item.Node.Children != nil {
-		if len(item.Node.Children) > 0 {
-			prefix = "[-] "
-		} else if item.Node.IsLoaded {
-			prefix = "[·] "
-		} else {
-			prefix = "[+] "
-		}
+	if started, ok := tv.loadingNodes[item.Node]; ok {
+		prefix = "[" + progressSpinner[int(time.Since(started)/(100*time.Millisecond))%len(progressSpinner)] + "] "
+	} else if item.Node.IsLoaded && len(item.Node.Children) == 0 {
+		prefix = "[·] "
+	} else if item.Node.IsLoaded && !item.Node.Collapsed {
+		prefix = "[-] "
 	} else {
 		prefix = "[+] "
 	}
 
-	name := item.Node.Name
+	name := item.Node.Label
+	if name == "" {
+		name = item.Node.Name
+	}
 	if name == "" {
 		name = item.Node.DN
 	}
 
+	if item.Level == 0 && tv.connectionName != "" {
+		name = "[" + tv.connectionName + "] " + name
+	}
+
 	content := indent + prefix + name
+	if tv.depthCapped[item.Node] {
+		content += " " + iconDepthLimit.String()
+	}
+	if item.Node.Alias != "" {
+		content += " " + iconAlias.String() + " " + item.Node.Alias
+	}
 
 	style := lipgloss.NewStyle()
+	if item.Level == 0 {
+		style = treeRootStyle
+	}
 	if isCursor {
 		style = style.Background(lipgloss.Color(GetGradientColor(0.5))).Foreground(lipgloss.Color("15"))
 	}
 
 	// Truncate if too long
-	if contentWidth > 5 && len(content) > contentWidth-2 {
-		content = content[:contentWidth-5] + "..."
+	if contentWidth > 5 {
+		content = truncateToWidth(content, contentWidth-2)
 	}
 
 	return style.Width(contentWidth).Render(content)
 }
 
-// loadRootNode loads the root node of the tree
+// Reload discards the loaded tree and rebuilds it from the client's base DN,
+// or from every naming context
+func (tv *TreeView) Reload() tea.Cmd {
+	tv.roots = nil
+	tv.rootErr = nil
+	tv.FlattenedTree = nil
+	tv.cursor = 0
+	tv.viewport = 0
+	tv.templateForm = nil
+	tv.deleteConfirm = nil
+	tv.loadingNodes = make(map[*ldap.TreeNode]time.Time)
+	tv.expanding = make(map[*ldap.TreeNode]int)
+	tv.depthCapped = make(map[*ldap.TreeNode]bool)
+	return tv.loadRootNode()
+}
+
+// loadRootNode loads the root nodes of the tree
 func (tv *TreeView) loadRootNode() tea.Cmd {
 	tv.loading = true
 	tv.loadingStartTime = time.Now()
 	tv.loadingElapsed = 0
+	client, allContexts := tv.client, tv.allContexts
 
 	// Return both the loading operation and the timer tick
 	return tea.Batch(
 		func() tea.Msg {
-			root, err := tv.client.BuildTree()
+			if allContexts {
+				roots, err := client.BuildNamingContextTrees()
+				if err != nil {
+					return RootNodeLoadedMsg{Err: err}
+				}
+				return RootNodeLoadedMsg{Roots: roots}
+			}
+			root, err := client.BuildTree()
 			if err != nil {
 				return ErrorMsg{Err: err}
 			}
-			return RootNodeLoadedMsg{Node: root}
+			return RootNodeLoadedMsg{Roots: []*ldap.TreeNode{root}}
 		},
-		tv.timerTickCmd(),
+		tv.startTimer(),
 	)
 }
 
@@ -278,25 +640,23 @@ func (tv *TreeView) expandNode() tea.Cmd {
 		return SendStatus("No children to expand")
 	}
 
+	if node.IsLoaded && node.Collapsed {
+		// Children are kept when collapsing, so show them again without a reload
+		node.Collapsed = false
+		delete(tv.depthCapped, node)
+		tv.rebuildFlattenedTree()
+		return SendStatus("Node expanded")
+	}
+
 	if node.IsLoaded {
 		return SendStatus("Node already expanded")
 	}
 
-	tv.loading = true
-	tv.loadingStartTime = time.Now()
-	tv.loadingElapsed = 0
+	if _, ok := tv.loadingNodes[node]; ok {
+		return SendStatus("Already loading children")
+	}
 
-	// Return both the loading operation and the timer tick
-	return tea.Batch(
-		func() tea.Msg {
-			err := tv.client.LoadChildren(node)
-			if err != nil {
-				return ErrorMsg{Err: err}
-			}
-			return NodeChildrenLoadedMsg{Node: node}
-		},
-		tv.timerTickCmd(),
-	)
+	return tv.loadChildren(node)
 }
 
 // collapseNode collapses the current node
@@ -308,17 +668,104 @@ func (tv *TreeView) collapseNode() tea.Cmd {
 	item := tv.FlattenedTree[tv.cursor]
 	node := item.Node
 
-	if !node.IsLoaded || len(node.Children) == 0 {
+	if !node.IsLoaded || node.Collapsed || len(node.Children) == 0 {
 		return SendStatus("No children to collapse")
 	}
 
-	// Mark as not loaded to collapse
-	node.IsLoaded = false
+	node.Collapsed = true
 	tv.rebuildFlattenedTree()
 
 	return SendStatus("Node collapsed")
 }
 
+// collapseAll collapses every node back to the roots, keeping the loaded
+// children so nodes expand again without a reload
+func (tv *TreeView) collapseAll() tea.Cmd {
+	if len(tv.roots) == 0 {
+		return nil
+	}
+
+	var collapse func(node *ldap.TreeNode)
+	collapse = func(node *ldap.TreeNode) {
+		if node.IsLoaded && len(node.Children) > 0 {
+			node.Collapsed = true
+		}
+		for _, child := range node.Children {
+			collapse(child)
+		}
+	}
+	for _, root := range tv.roots {
+		collapse(root)
+	}
+
+	tv.rebuildFlattenedTree()
+	tv.cursor = 0
+	tv.viewport = 0
+	return SendStatus("Collapsed tree to root")
+}
+
+// expandAll loads and expands the subtree of the current node, down to the
+// configured depth. Nodes at the limit that may have children are left
+// collapsed and marked, so a deep directory isn't read by accident.
+func (tv *TreeView) expandAll() tea.Cmd {
+	if tv.cursor >= len(tv.FlattenedTree) {
+		return nil
+	}
+	if len(tv.expanding) > 0 {
+		return SendStatus("Already expanding")
+	}
+
+	node := tv.FlattenedTree[tv.cursor].Node
+	cmd := tv.expandTo(node, tv.maxAutoDepth)
+	tv.rebuildFlattenedTree()
+	if len(tv.expanding) == 0 {
+		// Everything was loaded already
+		return tea.Batch(cmd, tv.expandedStatus())
+	}
+	return tea.Batch(cmd, SendStatus(fmt.Sprintf("Expanding %s...", node.Name)))
+}
+
+// expandTo expands node and the depth levels below it, loading the nodes
+// that aren't loaded yet. At depth zero a node that isn't loaded is marked
+// as cut off instead; a negative depth never reaches zero.
+func (tv *TreeView) expandTo(node *ldap.TreeNode, depth int) tea.Cmd {
+	if depth == 0 {
+		if !node.IsLoaded || (node.Collapsed && len(node.Children) > 0) {
+			tv.depthCapped[node] = true
+		}
+		return nil
+	}
+	delete(tv.depthCapped, node)
+	if !node.IsLoaded {
+		tv.expanding[node] = depth
+		if _, ok := tv.loadingNodes[node]; ok {
+			// Loading already; its children are expanded when they arrive
+			return nil
+		}
+		return tv.loadChildren(node)
+	}
+
+	node.Collapsed = false
+	var cmds []tea.Cmd
+	for _, child := range node.Children {
+		cmds = append(cmds, tv.expandTo(child, depth-1))
+	}
+	return tea.Batch(cmds...)
+}
+
+// expandedStatus reports a finished expand all, and how many nodes it left
+// at the depth limit
+func (tv *TreeView) expandedStatus() tea.Cmd {
+	if len(tv.depthCapped) == 0 {
+		return SendStatus("Expanded all")
+	}
+	entries := "entries are"
+	if len(tv.depthCapped) == 1 {
+		entries = "entry is"
+	}
+	return SendStatus(fmt.Sprintf("Expanded %d levels; %d %s at the depth limit, marked %s, press → to open them", tv.maxAutoDepth, len(tv.depthCapped), entries, iconDepthLimit))
+}
+
 // viewRecord shows the record for the current node
 func (tv *TreeView) viewRecord() tea.Cmd {
 	if tv.cursor >= len(tv.FlattenedTree) {
@@ -331,18 +778,188 @@ func (tv *TreeView) viewRecord() tea.Cmd {
 	return func() tea.Msg {
 		entry, err := tv.client.GetEntry(node.DN)
 		if err != nil {
-			return ErrorMsg{Err: err}
+			return readEntryError(node.DN, err)
 		}
 		return ShowRecordMsg{Entry: entry}
 	}
 }
 
-// rebuildFlattenedTree rebuilds the flattened tree for display
+// openAliasTarget shows the record the alias under the cursor points to
+func (tv *TreeView) openAliasTarget() tea.Cmd {
+	if tv.cursor >= len(tv.FlattenedTree) {
+		return nil
+	}
+	return openAliasTarget(tv.client, tv.FlattenedTree[tv.cursor].Node.Alias)
+}
+
+// openTemplateForm starts creating an entry from a template under the current node
+func (tv *TreeView) openTemplateForm() tea.Cmd {
+	if tv.cursor >= len(tv.FlattenedTree) {
+		return nil
+	}
+
+	if cmd := refuseWrite(tv.client); cmd != nil {
+		return cmd
+	}
+	if len(tv.templates) == 0 {
+		return SendError(fmt.Errorf("no entry templates configured; add entry_templates to the config file"))
+	}
+
+	parent := tv.FlattenedTree[tv.cursor].Node
+	tv.templateForm = NewTemplateForm(tv.client, tv.schema, tv.templates, parent.DN)
+	return nil
+}
+
+// changeBaseDN offers the selected node as the connection's base DN
+func (tv *TreeView) changeBaseDN() tea.Cmd {
+	if tv.cursor >= len(tv.FlattenedTree) {
+		return nil
+	}
+	return ChangeBaseDN(tv.FlattenedTree[tv.cursor].Node.DN)
+}
+
+// openDeleteConfirm asks for confirmation before deleting the current node.
+// Nodes with children are removed with the tree delete control when the
+// server advertises it; otherwise the user is told to delete the children
+// first. The children of an unexpanded node are read first to tell which.
+func (tv *TreeView) openDeleteConfirm() tea.Cmd {
+	if tv.cursor >= len(tv.FlattenedTree) {
+		return nil
+	}
+
+	if cmd := refuseWrite(tv.client); cmd != nil {
+		return cmd
+	}
+	node := tv.FlattenedTree[tv.cursor].Node
+	if tv.isRoot(node) {
+		if len(tv.roots) > 1 {
+			return SendError(fmt.Errorf("refusing to delete the naming context %s", node.DN))
+		}
+		return SendError(fmt.Errorf("refusing to delete the base DN %s", node.DN))
+	}
+	return tv.confirmDelete(node)
+}
+
+// confirmDelete opens the delete prompt for node, once its children and,
+// where the server reports them, the bound user's rights on it are known
+func (tv *TreeView) confirmDelete(node *ldap.TreeNode) tea.Cmd {
+	if !node.IsLoaded {
+		tv.deleteProbe = node
+		return tv.loadChildren(node)
+	}
+	if tv.client.SupportsEffectiveRights() && tv.client.CachedRights(node.DN) == nil {
+		tv.deleteProbe = node
+		client := tv.client
+		return func() tea.Msg {
+			_, err := client.GetEffectiveRights(node.DN, "", nil)
+			return DeleteRightsLoadedMsg{Node: node, Err: err}
+		}
+	}
+	return tv.openDeletePrompt(node)
+}
+
+// openDeletePrompt opens the delete prompt for node, unless the bound user's
+// rights forbid deleting it or its children can't be deleted with it
+func (tv *TreeView) openDeletePrompt(node *ldap.TreeNode) tea.Cmd {
+	if rights := tv.client.CachedRights(node.DN); !rights.MayDelete() {
+		return SendError(fmt.Errorf("the bound user may not delete %s (rights: %s)", node.DN, ldap.DescribeEntryRights(rights.Entry)))
+	}
+	subtree := len(node.Children) > 0 && tv.client.SupportsTreeDelete()
+	if len(node.Children) > 0 && !subtree {
+		return SendError(fmt.Errorf("%s has %d children; %w", node.DN, len(node.Children), ldap.ErrTreeDeleteUnsupported))
+	}
+
+	tv.deleteConfirm = NewDeleteConfirm(tv.client, node.DN, subtree)
+	return nil
+}
+
+// removeNode drops the node with the given DN from its parent after a
+// delete, reporting whether it was found. Roots are never removed.
+func (tv *TreeView) removeNode(dn string) bool {
+	var remove func(node *ldap.TreeNode) bool
+	remove = func(node *ldap.TreeNode) bool {
+		for i, child := range node.Children {
+			if child.DN == dn {
+				node.Children = append(node.Children[:i], node.Children[i+1:]...)
+				return true
+			}
+			if remove(child) {
+				return true
+			}
+		}
+		return false
+	}
+	removed := false
+	for _, root := range tv.roots {
+		if remove(root) {
+			removed = true
+			break
+		}
+	}
+	if !removed {
+		return false
+	}
+
+	tv.rebuildFlattenedTree()
+	if tv.cursor >= len(tv.FlattenedTree) {
+		tv.cursor = len(tv.FlattenedTree) - 1
+	}
+	if tv.cursor < 0 {
+		tv.cursor = 0
+	}
+	tv.adjustViewport()
+	return true
+}
+
+// reloadChildren re-reads the children of the node with the given DN so new
+// entries show up. Nodes that haven't been expanded are left alone.
+func (tv *TreeView) reloadChildren(dn string) tea.Cmd {
+	for _, item := range tv.FlattenedTree {
+		if item.Node.DN == dn {
+			if !item.Node.IsLoaded {
+				return nil
+			}
+			item.Node.IsLoaded = false
+			return tv.loadChildren(item.Node)
+		}
+	}
+	return nil
+}
+
+// rebuildFlattenedTree rebuilds the flattened tree for display, each root at
+// the top level
 func (tv *TreeView) rebuildFlattenedTree() {
 	tv.FlattenedTree = nil
-	if tv.root != nil {
-		tv.flattenTreeNode(tv.root, 0, true)
+	for i, root := range tv.roots {
+		tv.flattenTreeNode(root, 0, i == len(tv.roots)-1)
+	}
+}
+
+// isRoot reports whether node is one of the tree's roots
+func (tv *TreeView) isRoot(node *ldap.TreeNode) bool {
+	for _, root := range tv.roots {
+		if root == node {
+			return true
+		}
 	}
+	return false
+}
+
+// LoadedDNs returns the DNs of every node loaded so far, including children
+// of collapsed nodes
+func (tv *TreeView) LoadedDNs() []string {
+	var dns []string
+	var walk func(node *ldap.TreeNode)
+	walk = func(node *ldap.TreeNode) {
+		dns = append(dns, node.DN)
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	for _, root := range tv.roots {
+		walk(root)
+	}
+	return dns
 }
 
 // adjustViewport adjusts the viewport to keep the cursor visible
@@ -365,6 +982,11 @@ func (tv *TreeView) adjustViewport() {
 		tv.viewport = tv.cursor - availableHeight + 1
 	}
 
+	// A pinned root takes the first line, leaving one row less
+	if tv.pinnedRoot(tv.viewport) >= 0 && tv.cursor >= tv.viewport+availableHeight-1 && tv.cursor > tv.viewport {
+		tv.viewport++
+	}
+
 	if tv.viewport < 0 {
 		tv.viewport = 0
 	}
@@ -379,7 +1001,7 @@ func (tv *TreeView) flattenTreeNode(node *ldap.TreeNode, level int, isLast bool)
 	}
 	tv.FlattenedTree = append(tv.FlattenedTree, item)
 
-	if node.IsLoaded && node.Children != nil {
+	if node.IsLoaded && !node.Collapsed && node.Children != nil {
 		for i, child := range node.Children {
 			isLastChild := i == len(node.Children)-1
 			tv.flattenTreeNode(child, level+1, isLastChild)
@@ -388,12 +1010,27 @@ func (tv *TreeView) flattenTreeNode(node *ldap.TreeNode, level int, isLast bool)
 }
 
 // Custom messages for tree view
+
+// RootNodeLoadedMsg carries the roots the tree starts from: the base DN, or
+// one per naming context
 type RootNodeLoadedMsg struct {
+	Roots []*ldap.TreeNode
+	Err   error
+}
+
+// DeleteRightsLoadedMsg is sent when the bound user's rights on Node have
+// been read before asking to delete it
+type DeleteRightsLoadedMsg struct {
 	Node *ldap.TreeNode
+	Err  error
 }
 
+// NodeChildrenLoadedMsg carries the children read for Node. The children are
+// attached when the message is handled, so loads in flight never touch the tree.
 type NodeChildrenLoadedMsg struct {
-	Node *ldap.TreeNode
+	Node     *ldap.TreeNode
+	Children []*ldap.TreeNode
+	Err      error
 }
 
 type LoadingTimerTickMsg struct {
@@ -407,25 +1044,50 @@ func (tv *TreeView) timerTickCmd() tea.Cmd {
 	})
 }
 
-// loadChildren loads children for a specific node
+// startTimer starts the loading timer unless it is already running
+func (tv *TreeView) startTimer() tea.Cmd {
+	if tv.timerRunning {
+		return nil
+	}
+	tv.timerRunning = true
+	return tv.timerTickCmd()
+}
+
+// loadChildren loads children for a specific node. Loads made by expand
+// all are paced by the client's throttle, like other recursive operations.
 func (tv *TreeView) loadChildren(node *ldap.TreeNode) tea.Cmd {
 	if node == nil || node.IsLoaded {
 		return nil
 	}
 
-	tv.loading = true
-	tv.loadingStartTime = time.Now()
-	tv.loadingElapsed = 0
+	tv.loadingNodes[node] = time.Now()
+	client := tv.client
+	dn := node.DN
+	_, throttle := tv.expanding[node]
 
 	// Return both the loading operation and the timer tick
 	return tea.Batch(
 		func() tea.Msg {
-			err := tv.client.LoadChildren(node)
-			if err != nil {
-				return ErrorMsg{Err: err}
+			if throttle {
+				client.Throttle()
 			}
-			return NodeChildrenLoadedMsg{Node: node}
+			children, err := client.GetChildren(dn)
+			return NodeChildrenLoadedMsg{Node: node, Children: children, Err: err}
 		},
-		tv.timerTickCmd(),
+		tv.startTimer(),
 	)
 }
+
+// Commands lists the tree's actions for the command palette
+func (tv *TreeView) Commands() []paletteCommand {
+	return []paletteCommand{
+		{title: "View the selected entry", key: "Enter", run: tv.viewRecord},
+		{title: "Collapse all", key: "g", run: tv.collapseAll},
+		{title: "Expand all below the selected entry", key: "E", run: tv.expandAll},
+		{title: "Set the selected entry as base DN", key: "b", run: tv.changeBaseDN},
+		{title: "Create an entry from a template", key: "t", run: tv.openTemplateForm},
+		{title: "Delete the selected entry", key: "D", run: tv.openDeleteConfirm},
+		{title: "Show all naming contexts", key: "n", run: tv.toggleNamingContexts},
+		{title: "Reload the tree", run: tv.Reload},
+	}
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+// loadTreeAt builds the tree rooted at baseDN from a server holding only
+// dc=example,dc=com, returning the view and the status of the root's load
+func loadTreeAt(t *testing.T, baseDN string) (*TreeView, string) {
+	t.Helper()
+	zone.NewGlobal()
+	server := ldaptest.NewServer(t, ldaptest.Entry{DN: "dc=example,dc=com"})
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: baseDN})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	tv := NewTreeView(client)
+	tv.SetSize(100, 20)
+	root, _ := client.BuildTree()
+	tv.Update(RootNodeLoadedMsg{Roots: []*ldap.TreeNode{root}})
+	children, err := client.GetChildren(root.DN)
+	_, cmd := tv.Update(NodeChildrenLoadedMsg{Node: root, Children: children, Err: err})
+
+	status := ""
+	if cmd != nil {
+		if msg, ok := cmd().(StatusMsg); ok {
+			status = msg.Message
+		}
+	}
+	return tv, status
+}
+
+func TestTreeView_MissingBaseDN(t *testing.T) {
+	tv, status := loadTreeAt(t, "dc=missing,dc=com")
+	if tv.rootErr == nil {
+		t.Fatal("Expected the missing base DN to be recorded")
+	}
+	if status != "" {
+		t.Errorf("Expected no status besides the explanation, got %q", status)
+	}
+	view := ansi.Strip(tv.View())
+	for _, want := range []string{"Base DN not found: dc=missing,dc=com", "[N] to list the server's naming contexts"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the view, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "has no entries below it") {
+		t.Error("Expected a missing base not to be described as empty")
+	}
+
+	// Switching to the naming contexts clears it
+	tv.Update(keyRune('n'))
+	if tv.rootErr != nil {
+		t.Error("Expected reloading to clear the missing base DN")
+	}
+}
+
+func TestTreeView_EmptyBaseDN(t *testing.T) {
+	tv, status := loadTreeAt(t, "dc=example,dc=com")
+	if tv.rootErr != nil {
+		t.Fatalf("Expected an existing base DN not to be reported missing, got %v", tv.rootErr)
+	}
+	if status != "The base DN exists but has no entries below it" {
+		t.Errorf("Unexpected status %q", status)
+	}
+	view := ansi.Strip(tv.View())
+	if !strings.Contains(view, "[·] dc=example") || !strings.Contains(view, "The base DN exists but has no entries below it") {
+		t.Errorf("Expected the base and a note that it is empty, got:\n%s", view)
+	}
+	if strings.Contains(view, "Base DN not found") {
+		t.Error("Expected an empty base not to be described as missing")
+	}
+}
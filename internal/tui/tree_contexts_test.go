@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+// newMultiRootTreeView shows three naming contexts, the first one expanded
+func newMultiRootTreeView() *TreeView {
+	tv := NewTreeView(nil)
+	tv.SetSize(80, 20)
+	tv.Update(RootNodeLoadedMsg{Roots: []*ldap.TreeNode{
+		{DN: "dc=a,dc=com", Name: "dc=a,dc=com", IsLoaded: true, Children: []*ldap.TreeNode{
+			{DN: "ou=people,dc=a,dc=com", Name: "ou=people"},
+			{DN: "ou=groups,dc=a,dc=com", Name: "ou=groups"},
+		}},
+		{DN: "dc=b,dc=com", Name: "dc=b,dc=com"},
+		{DN: "cn=config", Name: "cn=config"},
+	}})
+	return tv
+}
+
+func TestTreeView_FlattensEveryRoot(t *testing.T) {
+	tv := newMultiRootTreeView()
+
+	var got []string
+	for _, item := range tv.FlattenedTree {
+		got = append(got, strings.Repeat(" ", item.Level)+item.Node.DN)
+	}
+	want := []string{"dc=a,dc=com", " ou=people,dc=a,dc=com", " ou=groups,dc=a,dc=com", "dc=b,dc=com", "cn=config"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Unexpected flattened tree:\n got %q\nwant %q", got, want)
+	}
+	if tv.FlattenedTree[0].IsLast || tv.FlattenedTree[3].IsLast || !tv.FlattenedTree[4].IsLast {
+		t.Error("Expected only the last root to be marked last")
+	}
+	if dns := tv.LoadedDNs(); len(dns) != 5 {
+		t.Errorf("Expected the DNs of every root, got %v", dns)
+	}
+
+	tv.Update(keyRune('g'))
+	if len(tv.FlattenedTree) != 3 {
+		t.Errorf("Expected collapsing to leave the three roots, got %d items", len(tv.FlattenedTree))
+	}
+}
+
+func TestTreeView_RootsLoadCollapsed(t *testing.T) {
+	tv := NewTreeView(nil)
+	_, cmd := tv.Update(RootNodeLoadedMsg{Roots: []*ldap.TreeNode{{DN: "dc=a,dc=com"}, {DN: "dc=b,dc=com"}}})
+	if len(tv.loadingNodes) != 0 {
+		t.Error("Expected naming contexts not to be expanded automatically")
+	}
+	if msg, ok := cmd().(StatusMsg); !ok || msg.Message != "Tree loaded with 2 naming contexts" {
+		t.Errorf("Unexpected status %#v", msg)
+	}
+}
+
+func TestTreeView_NavigateBetweenContexts(t *testing.T) {
+	tv := newMultiRootTreeView()
+	tv.cursor = 1
+
+	tv.Update(keyRune(']'))
+	if tv.cursor != 3 {
+		t.Fatalf("Expected ] to jump to dc=b, got row %d", tv.cursor)
+	}
+	tv.Update(keyRune(']'))
+	tv.Update(keyRune(']'))
+	if tv.cursor != 4 {
+		t.Errorf("Expected the cursor to stay on the last context, got row %d", tv.cursor)
+	}
+	tv.Update(keyRune('['))
+	tv.Update(keyRune('['))
+	if tv.cursor != 0 {
+		t.Errorf("Expected [ to go back to dc=a, got row %d", tv.cursor)
+	}
+
+	// Naming contexts can't be deleted from the tree
+	cmd := tv.openDeleteConfirm()
+	if tv.deleteConfirm != nil || cmd == nil {
+		t.Fatal("Expected deleting a naming context to be refused")
+	}
+	if msg, ok := cmd().(ErrorMsg); !ok || !strings.Contains(msg.Err.Error(), "naming context dc=a,dc=com") {
+		t.Errorf("Unexpected refusal %#v", msg)
+	}
+}
+
+func TestTreeView_ToggleNamingContexts(t *testing.T) {
+	tv := newMultiRootTreeView()
+	tv.Update(keyRune('n'))
+	if !tv.allContexts || !tv.loading || tv.roots != nil {
+		t.Error("Expected n to reload the tree from every naming context")
+	}
+}
+
+func TestTreeView_NamingContextsUnavailable(t *testing.T) {
+	tv := NewTreeView(nil)
+	tv.SetSize(80, 20)
+	tv.SetAllNamingContexts(true)
+	tv.loading = true
+
+	_, cmd := tv.Update(RootNodeLoadedMsg{Err: errors.New("root DSE not readable")})
+	if tv.loading {
+		t.Error("Expected loading to stop")
+	}
+	if _, ok := cmd().(ErrorMsg); !ok {
+		t.Error("Expected the failure to be reported")
+	}
+	if !strings.Contains(tv.View(), "[N] show the base DN") {
+		t.Error("Expected a hint to return to the base DN")
+	}
+}
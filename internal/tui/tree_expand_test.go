@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+	"github.com/ericschmar/moribito/internal/ldap/ldaptest"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+// newDeepTree builds a tree over a chain of entries five levels deep below
+// dc=example,dc=com, with the root's children loaded
+func newDeepTree(t *testing.T) *TreeView {
+	t.Helper()
+	return newThrottledDeepTree(t, 0)
+}
+
+// newThrottledDeepTree builds the tree of newDeepTree with a client limited
+// to operationsPerSecond bulk operations, 0 for no limit
+func newThrottledDeepTree(t *testing.T, operationsPerSecond float64) *TreeView {
+	t.Helper()
+	zone.NewGlobal()
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "ou=a,dc=example,dc=com"},
+		ldaptest.Entry{DN: "ou=b,ou=a,dc=example,dc=com"},
+		ldaptest.Entry{DN: "ou=c,ou=b,ou=a,dc=example,dc=com"},
+		ldaptest.Entry{DN: "ou=d,ou=c,ou=b,ou=a,dc=example,dc=com"},
+		ldaptest.Entry{DN: "ou=e,ou=d,ou=c,ou=b,ou=a,dc=example,dc=com"},
+	)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com", OperationsPerSecond: operationsPerSecond})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	tv := NewTreeView(client)
+	tv.SetSize(100, 20)
+	root, _ := client.BuildTree()
+	_, cmd := tv.Update(RootNodeLoadedMsg{Roots: []*ldap.TreeNode{root}})
+	drainTree(tv, cmd)
+	return tv
+}
+
+// drainTree runs cmd and feeds the loads it starts back into the tree until
+// none are left, returning the last status sent
+func drainTree(tv *TreeView, cmd tea.Cmd) string {
+	status := ""
+	pending := []tea.Cmd{cmd}
+	for len(pending) > 0 {
+		cmd, pending = pending[0], pending[1:]
+		for _, msg := range runCmd(cmd) {
+			switch msg := msg.(type) {
+			case NodeChildrenLoadedMsg:
+				_, next := tv.Update(msg)
+				pending = append(pending, next)
+			case StatusMsg:
+				status = msg.Message
+			}
+		}
+	}
+	return status
+}
+
+// treeDepth returns the depth of the deepest loaded node below node
+func treeDepth(node *ldap.TreeNode) int {
+	depth := 0
+	for _, child := range node.Children {
+		depth = max(depth, treeDepth(child)+1)
+	}
+	return depth
+}
+
+func TestTreeView_ExpandAllRespectsDepth(t *testing.T) {
+	tv := newDeepTree(t)
+	tv.SetMaxAutoDepth(2)
+
+	status := drainTree(tv, tv.expandAll())
+	root := tv.FlattenedTree[0].Node
+	if depth := treeDepth(root); depth != 2 {
+		t.Fatalf("Expected expand all to load 2 levels, loaded %d", depth)
+	}
+	if len(tv.expanding) != 0 {
+		t.Errorf("Expected no loads left in flight, got %d", len(tv.expanding))
+	}
+
+	// ou=b has children but sits at the limit, so it is left unloaded and marked
+	b := root.Children[0].Children[0]
+	if b.IsLoaded || !tv.depthCapped[b] {
+		t.Fatalf("Expected %s to be cut off at the depth limit", b.DN)
+	}
+	if len(tv.depthCapped) != 1 {
+		t.Errorf("Expected only %s to be marked, got %d nodes", b.DN, len(tv.depthCapped))
+	}
+	if !strings.Contains(status, "1 entry is at the depth limit") {
+		t.Errorf("Expected the status to count the cut-off entries, got %q", status)
+	}
+
+	view := ansi.Strip(tv.View())
+	if !strings.Contains(view, "ou=b "+iconDepthLimit.String()) {
+		t.Errorf("Expected the cut-off entry to be marked, got:\n%s", view)
+	}
+	if strings.Contains(view, "ou=a "+iconDepthLimit.String()) {
+		t.Errorf("Expected expanded entries not to be marked, got:\n%s", view)
+	}
+
+	// Opening the marked entry loads it and clears the mark
+	tv.cursor = 2
+	drainTree(tv, tv.expandNode())
+	if !b.IsLoaded || tv.depthCapped[b] {
+		t.Error("Expected expanding the cut-off entry to load it and clear the mark")
+	}
+}
+
+func TestTreeView_ExpandAllWithoutLimit(t *testing.T) {
+	tv := newDeepTree(t)
+	tv.SetMaxAutoDepth(-1)
+
+	status := drainTree(tv, tv.expandAll())
+	if depth := treeDepth(tv.FlattenedTree[0].Node); depth != 5 {
+		t.Errorf("Expected the whole chain to load, loaded %d levels", depth)
+	}
+	if len(tv.depthCapped) != 0 {
+		t.Errorf("Expected no entries marked without a limit, got %d", len(tv.depthCapped))
+	}
+	if status != "Expanded all" {
+		t.Errorf("Unexpected status %q", status)
+	}
+	if len(tv.FlattenedTree) != 6 {
+		t.Errorf("Expected every entry shown, got %d", len(tv.FlattenedTree))
+	}
+}
+
+func TestTreeView_ExpandAllIsThrottled(t *testing.T) {
+	// A burst of 4 loads runs at once and the fifth waits its turn
+	tv := newThrottledDeepTree(t, 4)
+	tv.SetMaxAutoDepth(-1)
+
+	start := time.Now()
+	drainTree(tv, tv.expandAll())
+	if depth := treeDepth(tv.FlattenedTree[0].Node); depth != 5 {
+		t.Fatalf("Expected the whole chain to load, loaded %d levels", depth)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Expected the loads to be paced at 4 a second, took %v", elapsed)
+	}
+}
+
+func TestTreeView_ExpandAllDefaultDepth(t *testing.T) {
+	tv := newDeepTree(t)
+	tv.SetMaxAutoDepth(0)
+	if tv.maxAutoDepth != defaultMaxAutoDepth {
+		t.Fatalf("Expected zero to select the default depth, got %d", tv.maxAutoDepth)
+	}
+
+	drainTree(tv, tv.expandAll())
+	if depth := treeDepth(tv.FlattenedTree[0].Node); depth != defaultMaxAutoDepth {
+		t.Errorf("Expected %d levels loaded, got %d", defaultMaxAutoDepth, depth)
+	}
+
+	// Collapsed, already loaded subtrees are opened again without a reload
+	tv.collapseAll()
+	status := drainTree(tv, tv.expandAll())
+	if len(tv.FlattenedTree) != defaultMaxAutoDepth+1 {
+		t.Errorf("Expected the loaded levels shown again, got %d rows", len(tv.FlattenedTree))
+	}
+	if !strings.Contains(status, "depth limit") {
+		t.Errorf("Expected the status to mention the cut-off entry, got %q", status)
+	}
+}
+
+func TestTreeView_RootAutoExpand(t *testing.T) {
+	zone.NewGlobal()
+	server := ldaptest.NewServer(t,
+		ldaptest.Entry{DN: "dc=example,dc=com"},
+		ldaptest.Entry{DN: "ou=people,dc=example,dc=com"},
+		ldaptest.Entry{DN: "ou=groups,dc=example,dc=com"},
+	)
+	client, err := ldap.NewClient(ldap.Config{Host: "127.0.0.1", Port: server.Port(), BaseDN: "dc=example,dc=com"})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	for _, collapse := range []bool{false, true} {
+		tv := NewTreeView(client)
+		tv.SetSize(100, 20)
+		tv.SetCollapseRoot(collapse)
+		root, _ := client.BuildTree()
+		_, cmd := tv.Update(RootNodeLoadedMsg{Roots: []*ldap.TreeNode{root}})
+		status := drainTree(tv, cmd)
+
+		want := 3 // The root and its two children
+		if collapse {
+			want = 1
+		}
+		if len(tv.FlattenedTree) != want {
+			t.Errorf("collapse %v: expected %d rows after load, got %d", collapse, want, len(tv.FlattenedTree))
+		}
+		if collapse && (root.IsLoaded || status != "Tree loaded") {
+			t.Errorf("Expected the collapsed root left unread, got loaded %v and status %q", root.IsLoaded, status)
+		}
+	}
+}
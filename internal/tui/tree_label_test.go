@@ -0,0 +1,25 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+)
+
+func TestTreeView_RendersLabelAttribute(t *testing.T) {
+	tv := NewTreeView(nil)
+	tv.SetSize(80, 10)
+
+	labelled := &TreeItem{Node: &ldap.TreeNode{DN: "uid=jdoe,ou=people,dc=example,dc=com", Name: "uid=jdoe", Label: "Jane Doe"}, Level: 1}
+	if got := ansi.Strip(tv.renderTreeItem(labelled, false, 80)); !strings.Contains(got, "[+] Jane Doe") || strings.Contains(got, "uid=jdoe") {
+		t.Errorf("Expected the label in place of the RDN, got %q", got)
+	}
+
+	// Entries without the attribute keep their RDN
+	plain := &TreeItem{Node: &ldap.TreeNode{DN: "uid=old,ou=people,dc=example,dc=com", Name: "uid=old"}, Level: 1}
+	if got := ansi.Strip(tv.renderTreeItem(plain, false, 80)); !strings.Contains(got, "[+] uid=old") {
+		t.Errorf("Expected the RDN as a fallback, got %q", got)
+	}
+}
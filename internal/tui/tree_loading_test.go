@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func newLoadingTreeView() (*TreeView, *ldap.TreeNode, *ldap.TreeNode) {
+	people := &ldap.TreeNode{DN: "ou=people,dc=example,dc=com", Name: "people"}
+	groups := &ldap.TreeNode{DN: "ou=groups,dc=example,dc=com", Name: "groups"}
+
+	tv := NewTreeView(&ldap.Client{})
+	tv.SetSize(80, 24)
+	tv.roots = []*ldap.TreeNode{{DN: "dc=example,dc=com", Name: "example", Children: []*ldap.TreeNode{people, groups}, IsLoaded: true}}
+	tv.rebuildFlattenedTree()
+	return tv, people, groups
+}
+
+func TestTreeView_ConcurrentChildLoads(t *testing.T) {
+	zone.NewGlobal()
+	tv, people, groups := newLoadingTreeView()
+
+	tv.cursor = 1
+	if cmd := tv.expandNode(); cmd == nil {
+		t.Fatal("Expected expanding people to start a load")
+	}
+	tv.cursor = 2
+	if cmd := tv.expandNode(); cmd == nil {
+		t.Fatal("Expected expanding groups while people loads to start a second load")
+	}
+	if len(tv.loadingNodes) != 2 {
+		t.Fatalf("Expected both loads in flight, got %d", len(tv.loadingNodes))
+	}
+	if tv.loading {
+		t.Error("Expected child loads to leave the tree visible")
+	}
+
+	view := tv.View()
+	if strings.Contains(view, "Loading LDAP tree") || strings.Count(view, "[⠋]") != 2 {
+		t.Errorf("Expected a spinner next to each loading node, got %q", view)
+	}
+
+	// The second load finishes first
+	admins := &ldap.TreeNode{DN: "cn=admins,ou=groups,dc=example,dc=com", Name: "admins"}
+	tv.Update(NodeChildrenLoadedMsg{Node: groups, Children: []*ldap.TreeNode{admins}})
+	if !groups.IsLoaded || len(groups.Children) != 1 {
+		t.Errorf("Expected the groups children to be attached, got %+v", groups)
+	}
+	if _, ok := tv.loadingNodes[people]; !ok || len(tv.loadingNodes) != 1 {
+		t.Error("Expected people to still be loading")
+	}
+	if people.IsLoaded {
+		t.Error("Expected people to be untouched by the groups result")
+	}
+
+	// Expanding a node that is already loading doesn't start another load
+	tv.cursor = 1
+	tv.expandNode()
+	if len(tv.loadingNodes) != 1 {
+		t.Errorf("Expected no duplicate load, got %d in flight", len(tv.loadingNodes))
+	}
+
+	jdoe := &ldap.TreeNode{DN: "uid=jdoe,ou=people,dc=example,dc=com", Name: "jdoe"}
+	tv.Update(NodeChildrenLoadedMsg{Node: people, Children: []*ldap.TreeNode{jdoe}})
+	if !people.IsLoaded || len(tv.loadingNodes) != 0 {
+		t.Error("Expected people to be loaded and nothing left in flight")
+	}
+	if len(tv.FlattenedTree) != 5 {
+		t.Errorf("Expected both subtrees in the flattened tree, got %d items", len(tv.FlattenedTree))
+	}
+}
+
+func TestTreeView_ChildLoadError(t *testing.T) {
+	tv, people, _ := newLoadingTreeView()
+
+	tv.cursor = 1
+	tv.expandNode()
+	_, cmd := tv.Update(NodeChildrenLoadedMsg{Node: people, Err: errors.New("busy")})
+	if _, ok := tv.loadingNodes[people]; ok {
+		t.Error("Expected a failed load to clear the node's spinner")
+	}
+	if people.IsLoaded {
+		t.Error("Expected a failed load to leave the node unexpanded")
+	}
+	if cmd == nil {
+		t.Fatal("Expected the error to be reported")
+	}
+	if msg, ok := cmd().(ErrorMsg); !ok || msg.Err.Error() != "busy" {
+		t.Errorf("Expected an ErrorMsg, got %#v", msg)
+	}
+}
+
+func TestTreeView_CollapseAllToRoot(t *testing.T) {
+	tv, people, groups := newLoadingTreeView()
+	staff := &ldap.TreeNode{DN: "ou=staff,ou=people,dc=example,dc=com", Name: "staff"}
+	jdoe := &ldap.TreeNode{DN: "uid=jdoe,ou=staff,ou=people,dc=example,dc=com", Name: "jdoe", IsLoaded: true}
+	staff.Children, staff.IsLoaded = []*ldap.TreeNode{jdoe}, true
+	people.Children, people.IsLoaded = []*ldap.TreeNode{staff}, true
+	groups.Children, groups.IsLoaded = []*ldap.TreeNode{{DN: "cn=admins,ou=groups,dc=example,dc=com", Name: "admins"}}, true
+	tv.rebuildFlattenedTree()
+	if len(tv.FlattenedTree) != 6 {
+		t.Fatalf("Expected three expanded levels, got %d items", len(tv.FlattenedTree))
+	}
+	tv.cursor = 3
+	tv.viewport = 2
+
+	tv.Update(keyRune('g'))
+	if len(tv.FlattenedTree) != 1 || tv.FlattenedTree[0].Node != tv.roots[0] {
+		t.Fatalf("Expected only the root to be left, got %d items", len(tv.FlattenedTree))
+	}
+	if tv.cursor != 0 || tv.viewport != 0 {
+		t.Errorf("Expected the cursor and viewport to reset, got %d and %d", tv.cursor, tv.viewport)
+	}
+
+	// Loaded children are kept, so expanding shows them again without a reload
+	if cmd := tv.expandNode(); cmd == nil || len(tv.loadingNodes) != 0 {
+		t.Error("Expected the root to expand from its kept children")
+	}
+	if len(tv.FlattenedTree) != 3 || len(people.Children) != 1 {
+		t.Errorf("Expected the root's children back with deeper levels still collapsed, got %d items", len(tv.FlattenedTree))
+	}
+}
@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/ericschmar/moribito/internal/ldap"
 	zone "github.com/lrstanley/bubblezone"
 )
@@ -50,12 +53,12 @@ func TestTreeView_ViewportContentUpdate(t *testing.T) {
 	tv.SetSize(80, 24)
 
 	// Create some mock tree data
-	tv.root = &ldap.TreeNode{
+	tv.roots = []*ldap.TreeNode{{
 		DN:       "dc=example,dc=com",
 		Name:     "example.com",
 		Children: nil,
 		IsLoaded: true,
-	}
+	}}
 	tv.rebuildFlattenedTree()
 
 	// Test that view content is updated
@@ -104,3 +107,54 @@ func TestTreeView_CursorVisibility(t *testing.T) {
 		t.Errorf("Viewport should not be negative, got %d", tv.viewport)
 	}
 }
+
+func TestTreeView_PinnedRoot(t *testing.T) {
+	zone.NewGlobal()
+	tv := NewTreeView(nil)
+	tv.SetSize(80, 10)
+	tv.SetConnectionName("prod")
+
+	root := &ldap.TreeNode{DN: "dc=example,dc=com", Name: "dc=example,dc=com", IsLoaded: true}
+	for i := 0; i < 30; i++ {
+		root.Children = append(root.Children, &ldap.TreeNode{DN: fmt.Sprintf("uid=user%02d,dc=example,dc=com", i), Name: fmt.Sprintf("uid=user%02d", i)})
+	}
+	tv.roots = []*ldap.TreeNode{root}
+	tv.rebuildFlattenedTree()
+
+	lines := strings.Split(ansi.Strip(tv.View()), "\n")
+	if !strings.Contains(lines[0], "[-] [prod] dc=example,dc=com") {
+		t.Fatalf("Expected the root with the connection name first, got %q", lines[0])
+	}
+	if strings.Count(strings.Join(lines, "\n"), "[prod]") != 1 {
+		t.Error("Expected the root shown once while its row is on screen")
+	}
+
+	// Scrolled to the bottom, the root stays on the first line above the cursor
+	tv.cursor = len(tv.FlattenedTree) - 1
+	tv.adjustViewport()
+	if tv.viewport == 0 {
+		t.Fatal("Expected the viewport to scroll")
+	}
+	lines = strings.Split(ansi.Strip(tv.View()), "\n")
+	if !strings.Contains(lines[0], "[prod] dc=example,dc=com") {
+		t.Errorf("Expected the root pinned when scrolled, got %q", lines[0])
+	}
+	if view := strings.Join(lines, "\n"); !strings.Contains(view, "uid=user29") || strings.Contains(view, fmt.Sprintf("uid=user%02d", tv.viewport-2)) {
+		t.Errorf("Expected the rows from %d to the cursor below the root, got:\n%s", tv.viewport, view)
+	}
+	if len(lines) > 10 {
+		t.Errorf("Expected the pinned root to fit in the height, got %d lines", len(lines))
+	}
+
+	// Moving up one row at a time keeps the cursor below the pinned root
+	for tv.cursor > 1 {
+		tv.cursor--
+		tv.adjustViewport()
+		if tv.pinnedRoot(tv.viewport) >= 0 && tv.cursor == tv.viewport-1 {
+			t.Fatalf("Expected the cursor on screen at row %d", tv.cursor)
+		}
+		if row := fmt.Sprintf("uid=user%02d", tv.cursor-1); !strings.Contains(ansi.Strip(tv.View()), row) {
+			t.Fatalf("Expected %s on screen", row)
+		}
+	}
+}
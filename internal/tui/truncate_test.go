@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ericschmar/moribito/internal/ldap"
+	zone "github.com/lrstanley/bubblezone"
+)
+
+func TestTruncateToWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+	}{
+		{"ascii", "cn=John Smith,ou=people,dc=example,dc=com", 20},
+		{"accented", "cn=José Müller-Ñúñez,ou=personnes,dc=société,dc=fr", 20},
+		{"cjk", "cn=山田太郎,ou=営業部,dc=例え,dc=jp", 15},
+		{"emoji", "description: 🚀🚀🚀🚀🚀🚀🚀🚀🚀🚀", 12},
+		{"odd width cjk", "東京都千代田区丸の内", 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateToWidth(tt.input, tt.width)
+
+			if !utf8.ValidString(got) {
+				t.Fatalf("Truncation produced invalid UTF-8: %q", got)
+			}
+			if w := lipgloss.Width(got); w > tt.width {
+				t.Errorf("Expected width <= %d, got %d (%q)", tt.width, w, got)
+			}
+			if !strings.HasSuffix(got, "...") {
+				t.Errorf("Expected ellipsis on truncated value, got %q", got)
+			}
+			if !strings.HasPrefix(tt.input, strings.TrimSuffix(got, "...")) {
+				t.Errorf("Expected truncated value to be a prefix of the input, got %q", got)
+			}
+		})
+	}
+}
+
+func TestTruncateToWidth_FitsUnchanged(t *testing.T) {
+	for _, s := range []string{"", "short", "Ñúñez", "山田"} {
+		if got := truncateToWidth(s, 10); got != s {
+			t.Errorf("Expected %q to be unchanged, got %q", s, got)
+		}
+	}
+}
+
+func TestTruncateToWidth_KeepsStyling(t *testing.T) {
+	styled := lipgloss.NewStyle().Bold(true).Render("山田太郎山田太郎山田太郎")
+	got := truncateToWidth(styled, 10)
+
+	if w := lipgloss.Width(got); w > 10 {
+		t.Errorf("Expected width <= 10, got %d", w)
+	}
+	if !utf8.ValidString(ansi.Strip(got)) {
+		t.Errorf("Expected valid UTF-8 after stripping styles, got %q", got)
+	}
+}
+
+func TestTreeView_RenderTreeItemMultibyte(t *testing.T) {
+	var client *ldap.Client
+	tv := NewTreeView(client)
+
+	item := &TreeItem{
+		Node: &ldap.TreeNode{
+			DN:   "ou=開発部門とエンジニアリング,dc=例え,dc=jp",
+			Name: "ou=開発部門とエンジニアリング部門の皆さん",
+		},
+		Level: 1,
+	}
+
+	contentWidth := 20
+	rendered := tv.renderTreeItem(item, false, contentWidth)
+	if !utf8.ValidString(rendered) {
+		t.Fatalf("Rendered tree item contains broken runes: %q", rendered)
+	}
+	if w := lipgloss.Width(rendered); w != contentWidth {
+		t.Errorf("Expected rendered width %d, got %d", contentWidth, w)
+	}
+	if !strings.Contains(rendered, "...") {
+		t.Error("Expected long multibyte name to be truncated with ellipsis")
+	}
+}
+
+func TestRecordView_RenderTableMultibyte(t *testing.T) {
+	zone.NewGlobal()
+	rv := NewRecordView()
+	rv.SetSize(60, 20)
+	rv.SetEntry(&ldap.Entry{
+		DN: "cn=José Müller,ou=personnes,dc=société,dc=fr",
+		Attributes: map[string][]string{
+			"description": {strings.Repeat("Ñúñez 山田太郎 ", 10)},
+		},
+	})
+
+	view := rv.renderTable()
+	if !utf8.ValidString(view) {
+		t.Fatalf("Rendered table contains broken runes")
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 60 {
+			t.Errorf("Line exceeds view width (%d): %q", w, line)
+		}
+	}
+	if !strings.Contains(view, "...") {
+		t.Error("Expected long multibyte value to be truncated with ellipsis")
+	}
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// typeConfirmReadyStyle and typeConfirmWaitingStyle mark whether the typed
+// text matches and the action can run
+var (
+	typeConfirmReadyStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	typeConfirmWaitingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Italic(true)
+)
+
+// TypeToConfirmView guards a destructive action by having an expected string,
+// such as an RDN or an entry count, typed first. The action only runs while
+// the input matches it exactly.
+type TypeToConfirmView struct {
+	expected string
+	action   func() tea.Cmd
+	input    textinput.Model
+}
+
+// NewTypeToConfirmView creates a focused prompt for expected that runs action
+// when Enter is pressed on a match
+func NewTypeToConfirmView(expected string, action func() tea.Cmd) *TypeToConfirmView {
+	ti := textinput.New()
+	ti.Placeholder = "type " + expected + " to confirm"
+	ti.CharLimit = 0
+	ti.Width = 40
+	ti.Focus()
+	return &TypeToConfirmView{expected: expected, action: action, input: ti}
+}
+
+// Matches reports whether the typed text is exactly the expected string
+func (v *TypeToConfirmView) Matches() bool {
+	return v.input.Value() == v.expected
+}
+
+// Update handles a key press. Enter runs the action when the text matches and
+// otherwise returns an error saying what to type; other keys edit the text.
+func (v *TypeToConfirmView) Update(msg tea.KeyMsg) (tea.Cmd, error) {
+	if msg.String() == "enter" {
+		if !v.Matches() {
+			return nil, fmt.Errorf("type %s exactly to confirm", v.expected)
+		}
+		return v.action(), nil
+	}
+
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	return cmd, nil
+}
+
+// SetValue replaces the typed text
+func (v *TypeToConfirmView) SetValue(value string) {
+	v.input.SetValue(value)
+}
+
+// Focus puts the cursor in the input
+func (v *TypeToConfirmView) Focus() tea.Cmd {
+	return v.input.Focus()
+}
+
+// Blur takes the cursor out of the input
+func (v *TypeToConfirmView) Blur() {
+	v.input.Blur()
+}
+
+// View renders the prompt and whether Enter will run the action
+func (v *TypeToConfirmView) View() string {
+	state := typeConfirmWaitingStyle.Render("[Enter] is disabled until the text matches")
+	if v.Matches() {
+		state = typeConfirmReadyStyle.Render(iconSuccess.String() + " Matches; [Enter] to confirm")
+	}
+	return fmt.Sprintf("Type %s to confirm: %s\n%s", editorFocusStyle.Render(v.expected), v.input.View(), state)
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTypeToConfirmView_MatchEnablesAction(t *testing.T) {
+	ran := false
+	v := NewTypeToConfirmView("ou=people", func() tea.Cmd {
+		ran = true
+		return nil
+	})
+
+	for _, r := range "ou=people" {
+		v.Update(keyRune(r))
+	}
+	if !v.Matches() {
+		t.Fatal("Expected the typed text to match")
+	}
+	if !strings.Contains(v.View(), "to confirm") {
+		t.Errorf("Expected the view to say confirming is enabled, got %q", v.View())
+	}
+
+	if _, err := v.Update(tea.KeyMsg{Type: tea.KeyEnter}); err != nil {
+		t.Fatalf("Expected no error on a match, got %v", err)
+	}
+	if !ran {
+		t.Error("Expected Enter to run the action on a match")
+	}
+}
+
+func TestTypeToConfirmView_MismatchKeepsActionDisabled(t *testing.T) {
+	ran := false
+	v := NewTypeToConfirmView("ou=people", func() tea.Cmd {
+		ran = true
+		return nil
+	})
+
+	for _, value := range []string{"", "ou=peopl", "OU=people", "ou=people "} {
+		v.SetValue(value)
+		if v.Matches() {
+			t.Errorf("Expected %q not to match", value)
+		}
+		if !strings.Contains(v.View(), "disabled") {
+			t.Errorf("Expected the view to say Enter is disabled for %q", value)
+		}
+		_, err := v.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if err == nil || !strings.Contains(err.Error(), "type ou=people exactly") {
+			t.Errorf("Expected an error naming the text to type for %q, got %v", value, err)
+		}
+	}
+	if ran {
+		t.Error("Expected the action not to run without a match")
+	}
+}
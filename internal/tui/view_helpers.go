@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // ViewContainer provides consistent sizing and basic rendering for all views
@@ -69,12 +70,20 @@ func truncateWithEllipsis(line string, targetWidth int) string {
 		return line + "..."
 	}
 
-	// Truncate by removing characters from the end until we fit
-	runes := []rune(line)
-	for len(runes) > 0 && lipgloss.Width(string(runes)) > targetWidth {
-		runes = runes[:len(runes)-1]
+	return truncateToWidth(line, targetWidth+3)
+}
+
+// truncateToWidth shortens s to at most width terminal cells, ending it with "..."
+// when anything was cut. Widths are measured per grapheme, so multibyte and
+// double-width characters are never split and ANSI styling is preserved.
+func truncateToWidth(s string, width int) string {
+	if width < 0 {
+		width = 0
 	}
-	return string(runes) + "..."
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	return ansi.Truncate(s, width, "...")
 }
 
 // shouldApplyContainerStyling determines if the container should apply additional styling
@@ -121,12 +130,7 @@ func (vc *ViewContainer) RenderPlain(content string) string {
 
 	var processedLines []string
 	for _, line := range lines {
-		if len(line) <= contentWidth {
-			processedLines = append(processedLines, line)
-		} else {
-			// Only truncate for plain content
-			processedLines = append(processedLines, line[:contentWidth-3]+"...")
-		}
+		processedLines = append(processedLines, truncateToWidth(line, contentWidth))
 	}
 
 	// Enforce height limit for plain content